@@ -16,6 +16,20 @@ module.exports = function(sequelize, DataTypes) {
       type: DataTypes.STRING,
       field: 'password',
       allowNull: false
+    },
+    previous_username: {
+      type: DataTypes.STRING,
+      field: 'previous_username'
+    },
+    previous_password: {
+      type: DataTypes.STRING,
+      field: 'previous_password'
+    },
+    previous_expire_at: {
+      type: DataTypes.BIGINT,
+      field: 'previous_expire_at',
+      allowNull: false,
+      defaultValue: 0
     }
   },{
     freezeTableName: true,
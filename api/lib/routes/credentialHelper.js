@@ -21,6 +21,23 @@ module.exports = function(models, credentialCache, cacheTTL) {
     return isValidCred;
   }
 
+  // the previous credentials of a rotation stay valid until previous_expire_at, which is in nanoseconds
+  function isPreviousCredentialValid(creds) {
+    if (!creds.previous_username || !creds.previous_password) {
+      return false;
+    }
+    var expireAt = Number(creds.previous_expire_at);
+    return !expireAt || expireAt / 1000000 > Date.now();
+  }
+
+  function validateAllCredentialDetails(username, password, creds) {
+    if (validateCredentialDetails(username, creds.username, password, creds.password)) {
+      return true;
+    }
+    return isPreviousCredentialValid(creds) &&
+      validateCredentialDetails(username, creds.previous_username, password, creds.previous_password);
+  }
+
   credhelper.createOrUpdateCredentials = function(req, callback) {
     var username = uuidv4();
     var password = uuidv4();
@@ -28,7 +45,11 @@ module.exports = function(models, credentialCache, cacheTTL) {
     models.credentials.upsert({
       id: appId,
       username: generateHash(username),
-      password: generateHash(password)
+      password: generateHash(password),
+      // a new binding revokes the credentials which are kept by a rotation
+      previous_username: null,
+      previous_password: null,
+      previous_expire_at: 0
     }).then(function(createdData) {
       if (createdData) {
         logger.info('New credentials has been generated successfully', {
@@ -127,7 +148,7 @@ module.exports = function(models, credentialCache, cacheTTL) {
     // Try to find credentials in cache
     try{
       creds = credentialCache.get(appId, true);
-      isValidCred = validateCredentialDetails(username, creds.username, password, creds.password);
+      isValidCred = validateAllCredentialDetails(username, password, creds);
       // If cache contains old or invalid credentials
       if (!isValidCred){
         logger.info('Credentials not valid', {
@@ -166,14 +187,17 @@ module.exports = function(models, credentialCache, cacheTTL) {
         callback(error);
       }
       else {
-        isValidCred = validateCredentialDetails(username, creds.username, password, creds.password);
+        isValidCred = validateAllCredentialDetails(username, password, creds);
         logger.info('Credentials hasbeen found successfully in database', {
           'app_id': appId,
           'isValid': isValidCred
         });
         cachedCred = {
             'username': creds.username,
-            'password': creds.password
+            'password': creds.password,
+            'previous_username': creds.previous_username,
+            'previous_password': creds.previous_password,
+            'previous_expire_at': creds.previous_expire_at
         };
         var isCached = credentialCache.set(appId, cachedCred, cacheTTL);
         logger.info('Credential cached',{ 'app_id':appId, 'isCached':isCached });
@@ -62,7 +62,7 @@ module.exports = function(settings, models){
     models.policy_json.findById(req.params.app_id).then (function(policyExists) {
       if(policyExists) {
         logger.info('Policy details retrieved ', { 'app id': req.params.app_id });
-        res.status(HttpStatus.OK).json(policyExists.policy_json);
+        res.status(HttpStatus.OK).json(policyHelper.redactPolicy(policyExists.policy_json));
       } 
       else{
         logger.info('No policy found',{ 'app id': req.params.app_id });
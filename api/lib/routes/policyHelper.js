@@ -3,37 +3,220 @@ module.exports = function(models){
   var logger = require('../log/logger');
   var HttpStatus = require('http-status-codes');
   var helper = {};
+  function isObject(value) {
+    return value !== null && typeof value === 'object' && !Array.isArray(value);
+  }
+
+  function diffValues(path, oldValue, newValue, changes) {
+    if (isObject(oldValue) && isObject(newValue)) {
+      var keys = Object.keys(oldValue);
+      Object.keys(newValue).forEach(function(key) {
+        if (!oldValue.hasOwnProperty(key)) {
+          keys.push(key);
+        }
+      });
+      keys.sort().forEach(function(key) {
+        diffValues(path ? path + '.' + key : key, oldValue[key], newValue[key], changes);
+      });
+      return changes;
+    }
+    if (Array.isArray(oldValue) && Array.isArray(newValue)) {
+      for (var i = 0; i < oldValue.length || i < newValue.length; i++) {
+        diffValues(path + '[' + i + ']', oldValue[i], newValue[i], changes);
+      }
+      return changes;
+    }
+    // a null value is the same as a missing one
+    oldValue = oldValue === null ? undefined : oldValue;
+    newValue = newValue === null ? undefined : newValue;
+    if (JSON.stringify(oldValue) !== JSON.stringify(newValue)) {
+      changes.push({ path: path, old: oldValue, new: newValue });
+    }
+    return changes;
+  }
+
+  // the same changes as models.DiffPolicies of the Go API server computes for the policy versions
+  helper.diffPolicies = function(oldPolicy, newPolicy) {
+    return diffValues('', oldPolicy || {}, newPolicy, []);
+  };
+
+  // the same secrets as models.RedactPolicy of the Go API server redacts
+  var REDACTED_VALUE = '[REDACTED]';
+  var SECRET_PATHS = [/^webhooks\[\d+\]\.secret$/, /^metric_sources\[\d+\]\.headers\..+$/];
+  // the urls whose user info is a secret
+  var SECRET_URL_PATHS = [/^metric_sources\[\d+\]\.url$/];
+  var URL_USER_INFO = /^([a-zA-Z][a-zA-Z0-9+.-]*:\/\/)([^\/?#]*)@/;
+
+  function matchesAny(paths, path) {
+    return paths.some(function(secretPath) { return secretPath.test(path); });
+  }
+
+  function redactUrl(url) {
+    return url.replace(URL_USER_INFO, '$1' + REDACTED_VALUE + '@');
+  }
+
+  function redactValue(path, value) {
+    if (value === null || value === undefined) {
+      return value;
+    }
+    if (matchesAny(SECRET_PATHS, path)) {
+      return REDACTED_VALUE;
+    }
+    if (typeof value === 'string' && matchesAny(SECRET_URL_PATHS, path)) {
+      return redactUrl(value);
+    }
+    if (Array.isArray(value)) {
+      return value.map(function(item, i) {
+        return redactValue(path + '[' + i + ']', item);
+      });
+    }
+    if (isObject(value)) {
+      var redacted = {};
+      Object.keys(value).forEach(function(key) {
+        redacted[key] = redactValue(path ? path + '.' + key : key, value[key]);
+      });
+      return redacted;
+    }
+    return value;
+  }
+
+  helper.REDACTED_VALUE = REDACTED_VALUE;
+
+  // the policy without its secrets, for the responses and the policy versions
+  helper.redactPolicy = function(policy) {
+    return redactValue('', policy);
+  };
+
+  helper.redactPolicyChanges = function(changes) {
+    return changes.map(function(change) {
+      return { path: change.path, old: redactValue(change.path, change.old), new: redactValue(change.path, change.new) };
+    });
+  };
+
+  function badRequest(message) {
+    var error = new Error(message);
+    error.statusCode = HttpStatus.BAD_REQUEST;
+    return error;
+  }
+
+  function restoreMetricSourceSecrets(source, currentSources) {
+    var current = currentSources.find(function(currentSource) {
+      return currentSource.metric_type === source.metric_type;
+    });
+    var headers = isObject(source.headers) ? source.headers : {};
+    Object.keys(headers).forEach(function(name) {
+      if (headers[name] !== REDACTED_VALUE) {
+        return;
+      }
+      if (!current || !isObject(current.headers) || !current.headers.hasOwnProperty(name)) {
+        throw badRequest('the header ' + name + ' of metric source ' + source.metric_type +
+          ' is redacted, but the current policy has no metric source with this header');
+      }
+      headers[name] = current.headers[name];
+    });
+
+    var url = typeof source.url === 'string' ? source.url : '';
+    var match = url.match(URL_USER_INFO);
+    if (match && match[2] === REDACTED_VALUE) {
+      var currentMatch = current && typeof current.url === 'string' ? current.url.match(URL_USER_INFO) : null;
+      if (!currentMatch) {
+        throw badRequest('the user info of the url of metric source ' + source.metric_type +
+          ' is redacted, but the current policy has no metric source with user info');
+      }
+      source.url = match[1] + currentMatch[2] + '@' + url.substring(match[0].length);
+    }
+  }
+
+  // a redacted webhook secret keeps the secret of the webhook with the same url in the current policy, and a metric
+  // source keeps the headers and the user info of the metric source with the same metric type, so that a policy from
+  // GET can be saved again
+  helper.restorePolicySecrets = function(policy, currentPolicy) {
+    var currentWebhooks = (currentPolicy && currentPolicy.webhooks) || [];
+    ((policy && policy.webhooks) || []).forEach(function(webhook) {
+      if (!isObject(webhook) || webhook.secret !== REDACTED_VALUE) {
+        return;
+      }
+      var current = currentWebhooks.find(function(currentWebhook) {
+        return currentWebhook.url === webhook.url;
+      });
+      if (!current) {
+        throw badRequest('the secret of webhook ' + webhook.url +
+          ' is redacted, but the current policy has no webhook with this url');
+      }
+      webhook.secret = current.secret;
+    });
+    var currentSources = (currentPolicy && currentPolicy.metric_sources) || [];
+    ((policy && policy.metric_sources) || []).forEach(function(source) {
+      if (isObject(source)) {
+        restoreMetricSourceSecrets(source, currentSources);
+      }
+    });
+    return policy;
+  };
+
   helper.createOrUpdatePolicy = function(req, callback) {
   /*  Create policy will only be called in the async waterfall when we do not 
   get any error during the schedule creation/update. */
-    models.policy_json.findOrCreate({ where:{ app_id: req.params.app_id },
-      defaults: { app_id: req.params.app_id,
-      policy_json: req.body, guid: req.query.policy_guid } })
-      .spread(function(result, created) {
-        if(created) {
-          logger.info('No policy exists, creating policy..',{ 'app id': req.params.app_id });  
-          callback(null, { 'statusCode':HttpStatus.CREATED,'response':result.policy_json });
+    var appId = req.params.app_id;
+    var created = false;
+    models.sequelize.transaction(function(t) {
+      // the same lock as the Go API server takes, so that the versions of the policy of an app are numbered one by one
+      return models.sequelize.query('SELECT pg_advisory_xact_lock(hashtext(:appId))',
+        { replacements: { appId: appId }, transaction: t })
+      .then(function() {
+        return models.policy_json.findOne({ where: { app_id: appId }, transaction: t });
+      }).then(function(existing) {
+        var save;
+        var oldPolicy = {};
+        if (existing) {
+          oldPolicy = typeof existing.policy_json === 'string' ? JSON.parse(existing.policy_json) : existing.policy_json;
         }
-        else {
-          logger.info('Updating the existing policy',{ 'app id': req.params.app_id });
-          models.policy_json.update({
-            app_id: req.params.app_id,
+        helper.restorePolicySecrets(req.body, oldPolicy);
+        if (existing) {
+          save = models.policy_json.update({
+            app_id: appId,
             policy_json: req.body,
             guid: req.query.policy_guid
-          },{ where: { app_id: req.params.app_id } ,plain: true, returning:true }).then(function(result) {
-            callback(null, { 'statusCode':HttpStatus.OK,'response':result[1].policy_json });
-          }).catch(function(error) {
-            logger.error ('Failed to update policy',
-               { 'app id': req.params.app_id,'error':error });
-            error.statusCode = HttpStatus.INTERNAL_SERVER_ERROR;
-            callback(error);
+          },{ where: { app_id: appId }, plain: true, returning: true, transaction: t }).then(function(result) {
+            return result[1];
           });
         }
-      }).catch(function(error) {
-        logger.error ('Failed to create policy', { 'app id': req.params.app_id,'error':error });
-        error.statusCode = HttpStatus.INTERNAL_SERVER_ERROR;
-        callback(error);
+        else {
+          created = true;
+          save = models.policy_json.create({ app_id: appId, policy_json: req.body, guid: req.query.policy_guid },
+            { transaction: t });
+        }
+        return save.then(function(result) {
+          // the policies saved here have no author, the API has no user of the request
+          return models.sequelize.query('INSERT INTO policy_version (app_id, version, policy_json, guid, author, created_at, diff) ' +
+            'SELECT :appId, COALESCE(MAX(version), 0) + 1, :policyJson, :guid, \'\', ' +
+            '(EXTRACT(EPOCH FROM clock_timestamp()) * 1000000000)::bigint, :diff FROM policy_version WHERE app_id = :appId', {
+              replacements: {
+                appId: appId,
+                policyJson: JSON.stringify(helper.redactPolicy(req.body)),
+                guid: req.query.policy_guid,
+                diff: JSON.stringify(helper.redactPolicyChanges(helper.diffPolicies(oldPolicy, req.body)))
+              },
+              transaction: t
+            }).then(function() {
+              return result;
+            });
+        });
       });
+    }).then(function(result) {
+      if(created) {
+        logger.info('No policy exists, creating policy..',{ 'app id': appId });
+        callback(null, { 'statusCode':HttpStatus.CREATED,'response':helper.redactPolicy(result.policy_json) });
+      }
+      else {
+        logger.info('Updating the existing policy',{ 'app id': appId });
+        callback(null, { 'statusCode':HttpStatus.OK,'response':helper.redactPolicy(result.policy_json) });
+      }
+    }).catch(function(error) {
+      logger.error (created ? 'Failed to create policy' : 'Failed to update policy', { 'app id': appId,'error':error });
+      error.statusCode = error.statusCode || HttpStatus.INTERNAL_SERVER_ERROR;
+      callback(error);
+    });
   }
 
   helper.deletePolicy = function(req, callback) {
@@ -65,11 +65,11 @@ describe("Routing Aggregated App Metrics", function() {
   });
 
   var histories = [
-    { "app_id": theAppId, "timestamp": 100, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 110, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 150, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 170, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 200, "name": "memoryused", "unit": "megabytes", "value": "200" }
+    { "app_id": theAppId, "timestamp": 100, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 110, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 150, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 170, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 200, "name": "memoryused", "unit": "megabytes", "value": 200 }
   ]
   describe("get metrics", function() {
     context("parameters", function() {
@@ -18,6 +18,7 @@ var HttpStatus = require('http-status-codes');
 var models = require('../../../lib/models')(settings.db);
 var credHelper = require('../../../lib/routes/credentialHelper')(models,credentialCache,settings.cacheTTL);
 var credentials = models.credentials;
+var bcrypt = require('bcrypt-nodejs');
 
 describe('Credential Management helper ', function() {
 
@@ -197,5 +198,78 @@ describe('Credential Management helper ', function() {
         done();
       });
     });
+
+    context('when the credentials are rotated', function() {
+      var mockRequest;
+
+      function rotate(previousExpireAt, done) {
+        credentials.find({ where: { id: '12345' } }).then(function(creds) {
+          return credentials.update({
+            previous_username: creds.username,
+            previous_password: creds.password,
+            previous_expire_at: previousExpireAt,
+            username: bcrypt.hashSync('new-username', bcrypt.genSaltSync(8)),
+            password: bcrypt.hashSync('new-password', bcrypt.genSaltSync(8))
+          }, { where: { id: '12345' } });
+        }).then(function() {
+          credentialCache.flushAll();
+          done();
+        });
+      }
+
+      beforeEach(function() {
+        mockRequest = {
+          body: {},
+          params: {
+            'app_id': '12345'
+          },
+          query: {
+            'username': username,
+            'password': password
+          }
+        };
+      });
+
+      context('when the previous credentials have not expired', function() {
+        beforeEach(function(done) {
+          rotate((Date.now() + 60000) * 1000000, done);
+        });
+
+        it('should validate the previous credentials from the database and from the cache', function(done) {
+          credHelper.validateCredentials(mockRequest, function(error, result) {
+            expect(error).to.be.null;
+            expect(result.isValid).to.equal(true);
+            credHelper.validateCredentials(mockRequest, function(error, result) {
+              expect(error).to.be.null;
+              expect(result.isValid).to.equal(true);
+              done();
+            });
+          });
+        });
+
+        it('should validate the new credentials', function(done) {
+          mockRequest.query = { 'username': 'new-username', 'password': 'new-password' };
+          credHelper.validateCredentials(mockRequest, function(error, result) {
+            expect(error).to.be.null;
+            expect(result.isValid).to.equal(true);
+            done();
+          });
+        });
+      });
+
+      context('when the previous credentials have expired', function() {
+        beforeEach(function(done) {
+          rotate((Date.now() - 1000) * 1000000, done);
+        });
+
+        it('should invalidate the previous credentials', function(done) {
+          credHelper.validateCredentials(mockRequest, function(error, result) {
+            expect(error).to.be.null;
+            expect(result.isValid).to.equal(false);
+            done();
+          });
+        });
+      });
+    });
   });
 });
@@ -64,11 +64,11 @@ describe("Routing Metrics", function() {
 
   });
   var histories = [
-    { "app_id": theAppId, "timestamp": 100, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 110, "instance_index": 1, "collected_at": 1, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 150, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 170, "instance_index": 1, "collected_at": 1, "name": "memoryused", "unit": "megabytes", "value": "200" },
-    { "app_id": theAppId, "timestamp": 200, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": "200" }
+    { "app_id": theAppId, "timestamp": 100, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 110, "instance_index": 1, "collected_at": 1, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 150, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 170, "instance_index": 1, "collected_at": 1, "name": "memoryused", "unit": "megabytes", "value": 200 },
+    { "app_id": theAppId, "timestamp": 200, "instance_index": 0, "collected_at": 0, "name": "memoryused", "unit": "megabytes", "value": 200 }
   ]
   describe("get metrics", function() {
     context("parameters", function() {
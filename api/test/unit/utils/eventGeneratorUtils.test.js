@@ -25,11 +25,11 @@ describe('eventGenerator Utility functions', function() {
     });
     context('all parameters are valid', function() {
       var aggregatedMetrics = [
-        { "app_id": theAppId, "timestamp": 100,"name": "memoryused", "unit": "megabytes", "value": "200"},
-        { "app_id": theAppId, "timestamp": 110,"name": "memoryused", "unit": "megabytes", "value": "200"},
-        { "app_id": theAppId, "timestamp": 150,"name": "memoryused", "unit": "megabytes", "value": "200"},
-        { "app_id": theAppId, "timestamp": 170,"name": "memoryused", "unit": "megabytes", "value": "200"},
-        { "app_id": theAppId, "timestamp": 200,"name": "memoryused", "unit": "megabytes", "value": "200"}
+        { "app_id": theAppId, "timestamp": 100,"name": "memoryused", "unit": "megabytes", "value": 200},
+        { "app_id": theAppId, "timestamp": 110,"name": "memoryused", "unit": "megabytes", "value": 200},
+        { "app_id": theAppId, "timestamp": 150,"name": "memoryused", "unit": "megabytes", "value": 200},
+        { "app_id": theAppId, "timestamp": 170,"name": "memoryused", "unit": "megabytes", "value": 200},
+        { "app_id": theAppId, "timestamp": 200,"name": "memoryused", "unit": "megabytes", "value": 200}
       ];
       it('should get the aggregatedMetrics', function(done) {
         nock(eventGeneratorUri)
@@ -25,11 +25,11 @@ describe('metricsCollector Utility functions', function() {
     });
     context('all parameters are valid', function() {
       var metrics = [
-        { "app_id": theAppId, "timestamp": 100, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": "200" },
-        { "app_id": theAppId, "timestamp": 110, "instance_index": 1, "collected_at": 1, "name": metricType, "unit": "megabytes", "value": "200" },
-        { "app_id": theAppId, "timestamp": 150, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": "200" },
-        { "app_id": theAppId, "timestamp": 170, "instance_index": 1, "collected_at": 1, "name": metricType, "unit": "megabytes", "value": "200" },
-        { "app_id": theAppId, "timestamp": 200, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": "200" }
+        { "app_id": theAppId, "timestamp": 100, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": 200 },
+        { "app_id": theAppId, "timestamp": 110, "instance_index": 1, "collected_at": 1, "name": metricType, "unit": "megabytes", "value": 200 },
+        { "app_id": theAppId, "timestamp": 150, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": 200 },
+        { "app_id": theAppId, "timestamp": 170, "instance_index": 1, "collected_at": 1, "name": metricType, "unit": "megabytes", "value": 200 },
+        { "app_id": theAppId, "timestamp": 200, "instance_index": 0, "collected_at": 0, "name": metricType, "unit": "megabytes", "value": 200 }
       ];
       context("instanceIndex is not provided", function() {
         it('should get the metrics of all instances', function(done) {
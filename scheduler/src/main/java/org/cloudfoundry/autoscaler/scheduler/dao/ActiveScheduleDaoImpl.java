@@ -21,8 +21,8 @@ public class ActiveScheduleDaoImpl extends JdbcDaoSupport implements ActiveSched
 	private static final String SELECT_SQL = "SELECT * FROM " + TABLE_NAME + " WHERE id=?";
 
 	private static final String INSERT_SQL = "INSERT INTO " + TABLE_NAME
-			+ "(id, app_id, start_job_identifier, instance_min_count, instance_max_count, initial_min_instance_count) "
-			+ "VALUES (?, ?, ?, ?, ?, ?)";
+			+ "(id, app_id, start_job_identifier, instance_min_count, instance_max_count, initial_min_instance_count, override) "
+			+ "VALUES (?, ?, ?, ?, ?, ?, ?)";
 
 	private static final String DELETE_SQL = "DELETE FROM " + TABLE_NAME + " WHERE id=? and start_job_identifier=?";
 
@@ -50,7 +50,8 @@ public class ActiveScheduleDaoImpl extends JdbcDaoSupport implements ActiveSched
 	public void create(ActiveScheduleEntity activeScheduleEntity) {
 		Object[] objects = new Object[] { activeScheduleEntity.getId(), activeScheduleEntity.getAppId(),
 				activeScheduleEntity.getStartJobIdentifier(), activeScheduleEntity.getInstanceMinCount(),
-				activeScheduleEntity.getInstanceMaxCount(), activeScheduleEntity.getInitialMinInstanceCount() };
+				activeScheduleEntity.getInstanceMaxCount(), activeScheduleEntity.getInitialMinInstanceCount(),
+				activeScheduleEntity.getOverride() };
 		try {
 			getJdbcTemplate().update(INSERT_SQL, objects);
 		} catch (DataAccessException e) {
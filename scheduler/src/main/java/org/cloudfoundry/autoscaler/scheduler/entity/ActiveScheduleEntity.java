@@ -6,7 +6,9 @@ import java.sql.SQLException;
 import org.springframework.jdbc.core.RowMapper;
 
 import com.fasterxml.jackson.annotation.JsonIgnore;
+import com.fasterxml.jackson.annotation.JsonInclude;
 import com.fasterxml.jackson.annotation.JsonProperty;
+import com.fasterxml.jackson.annotation.JsonRawValue;
 
 import io.swagger.annotations.ApiModel;
 import io.swagger.annotations.ApiModelProperty;
@@ -36,6 +38,12 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 	@JsonProperty(value = "initial_min_instance_count")
 	private Integer initialMinInstanceCount;
 
+	@ApiModelProperty(position = 4)
+	@JsonProperty(value = "override")
+	@JsonRawValue
+	@JsonInclude(JsonInclude.Include.NON_NULL)
+	private String override;
+
 	public Long getId() {
 		return id;
 	}
@@ -84,6 +92,14 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 		this.initialMinInstanceCount = initialMinInstanceCount;
 	}
 
+	public String getOverride() {
+		return override;
+	}
+
+	public void setOverride(String override) {
+		this.override = override;
+	}
+
 	public ActiveScheduleEntity mapRow(ResultSet rs, int rowNum) throws SQLException {
 		ActiveScheduleEntity activeScheduleEntity = new ActiveScheduleEntity();
 		activeScheduleEntity.setId(rs.getLong("id"));
@@ -94,6 +110,7 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 
 		int initialMinInstanceCount = rs.getInt("initial_min_instance_count");
 		activeScheduleEntity.setInitialMinInstanceCount(rs.wasNull() ? null : initialMinInstanceCount);
+		activeScheduleEntity.setOverride(rs.getString("override"));
 
 		return activeScheduleEntity;
 	}
@@ -112,6 +129,7 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 			return false;
 		if (!instanceMinCount.equals(that.instanceMinCount)) return false;
 		if (!instanceMaxCount.equals(that.instanceMaxCount)) return false;
+		if (override != null ? !override.equals(that.override) : that.override != null) return false;
 		return initialMinInstanceCount != null ? initialMinInstanceCount.equals(that.initialMinInstanceCount) : that.initialMinInstanceCount == null;
 	}
 
@@ -123,6 +141,7 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 		result = 31 * result + instanceMinCount.hashCode();
 		result = 31 * result + instanceMaxCount.hashCode();
 		result = 31 * result + (initialMinInstanceCount != null ? initialMinInstanceCount.hashCode() : 0);
+		result = 31 * result + (override != null ? override.hashCode() : 0);
 		return result;
 	}
 
@@ -130,6 +149,6 @@ public class ActiveScheduleEntity implements RowMapper<ActiveScheduleEntity> {
 	public String toString() {
 		return "ActiveScheduleEntity{" + "id=" + id + ", appId='" + appId + '\'' + ", startJobIdentifier="
 				+ startJobIdentifier + ", instanceMinCount=" + instanceMinCount + ", instanceMaxCount="
-				+ instanceMaxCount + ", initialMinInstanceCount=" + initialMinInstanceCount + '}';
+				+ instanceMaxCount + ", initialMinInstanceCount=" + initialMinInstanceCount + ", override=" + override + '}';
 	}
 }
@@ -8,7 +8,11 @@ import javax.persistence.MappedSuperclass;
 import javax.persistence.SequenceGenerator;
 import javax.validation.constraints.NotNull;
 
+import org.cloudfoundry.autoscaler.scheduler.util.RawJsonDeserializer;
+
 import com.fasterxml.jackson.annotation.JsonProperty;
+import com.fasterxml.jackson.annotation.JsonRawValue;
+import com.fasterxml.jackson.databind.annotation.JsonDeserialize;
 
 import io.swagger.annotations.ApiModel;
 import io.swagger.annotations.ApiModelProperty;
@@ -70,6 +74,13 @@ public class ScheduleEntity {
 	@JsonProperty(value = "guid")
 	private String guid;
 
+	@ApiModelProperty(position = 14)
+	@Column(name = "override")
+	@JsonProperty(value = "override")
+	@JsonRawValue
+	@JsonDeserialize(using = RawJsonDeserializer.class)
+	private String override;
+
 	public Long getId() {
 		return id;
 	}
@@ -142,6 +153,14 @@ public class ScheduleEntity {
 		this.guid = guid;
 	}
 
+	public String getOverride() {
+		return override;
+	}
+
+	public void setOverride(String override) {
+		this.override = override;
+	}
+
 	@Override
 	public boolean equals(Object o) {
 		if (this == o)
@@ -172,6 +191,8 @@ public class ScheduleEntity {
 			return false;
 		if (!guid.equals(that.guid))
 			return false;
+		if (override != null ? !override.equals(that.override) : that.override != null)
+			return false;
 		return true;
 
 	}
@@ -187,6 +208,7 @@ public class ScheduleEntity {
 		result = 31 * result + instanceMaxCount.hashCode();
 		result = 31 * result + (initialMinInstanceCount != null ? initialMinInstanceCount.hashCode() : 0);
 		result = 31 * result + (guid != null ? guid.hashCode() : 0);
+		result = 31 * result + (override != null ? override.hashCode() : 0);
 		return result;
 	}
 
@@ -195,7 +217,8 @@ public class ScheduleEntity {
 		return "ScheduleEntity [id=" + id + ", appId=" + appId + ", timeZone=" + timeZone + ", defaultInstanceMinCount="
 				+ defaultInstanceMinCount + ", defaultInstanceMaxCount=" + defaultInstanceMaxCount
 				+ ", instanceMinCount=" + instanceMinCount + ", instanceMaxCount=" + instanceMaxCount
-				+ ", initialMinInstanceCount=" + initialMinInstanceCount + ", guid=" + guid + "]";
+				+ ", initialMinInstanceCount=" + initialMinInstanceCount + ", guid=" + guid + ", override=" + override
+				+ "]";
 	}
 
 }
@@ -131,6 +131,7 @@ class ScheduleJobManager {
 		jobDataMap.put(ScheduleJobHelper.INSTANCE_MIN_COUNT, scheduleEntity.getInstanceMinCount());
 		jobDataMap.put(ScheduleJobHelper.INSTANCE_MAX_COUNT, scheduleEntity.getInstanceMaxCount());
 		jobDataMap.put(ScheduleJobHelper.INITIAL_MIN_INSTANCE_COUNT, scheduleEntity.getInitialMinInstanceCount());
+		jobDataMap.put(ScheduleJobHelper.OVERRIDE, scheduleEntity.getOverride());
 		jobDataMap.put(ScheduleJobHelper.DEFAULT_INSTANCE_MIN_COUNT, scheduleEntity.getDefaultInstanceMinCount());
 		jobDataMap.put(ScheduleJobHelper.DEFAULT_INSTANCE_MAX_COUNT, scheduleEntity.getDefaultInstanceMaxCount());
 
@@ -0,0 +1,18 @@
+package org.cloudfoundry.autoscaler.scheduler.util;
+
+import java.io.IOException;
+
+import com.fasterxml.jackson.core.JsonParser;
+import com.fasterxml.jackson.databind.DeserializationContext;
+import com.fasterxml.jackson.databind.JsonDeserializer;
+
+/**
+ * Keeps a JSON value as its raw JSON text, so that it is passed through without being interpreted.
+ */
+public class RawJsonDeserializer extends JsonDeserializer<String> {
+
+	@Override
+	public String deserialize(JsonParser parser, DeserializationContext ctxt) throws IOException {
+		return parser.readValueAsTree().toString();
+	}
+}
@@ -37,6 +37,7 @@ public class ScheduleJobHelper {
 	public static final String INITIAL_MIN_INSTANCE_COUNT = "initialMinInstanceCount";
 	public static final String INSTANCE_MIN_COUNT = "instanceMinCount";
 	public static final String INSTANCE_MAX_COUNT = "instanceMaxCount";
+	public static final String OVERRIDE = "override";
 	public static final String DEFAULT_INSTANCE_MIN_COUNT = "defaultInstanceMinCount";
 	public static final String DEFAULT_INSTANCE_MAX_COUNT = "defaultInstanceMaxCount";
 	public static final String START_JOB_IDENTIFIER = "startJobIdentifier";
@@ -144,6 +145,9 @@ public class ScheduleJobHelper {
 		// Initial min instance count can be null
 		activeScheduleEntity.setInitialMinInstanceCount((Integer) jobDataMap.get(INITIAL_MIN_INSTANCE_COUNT));
 
+		// Override can be null
+		activeScheduleEntity.setOverride(jobDataMap.getString(OVERRIDE));
+
 		return activeScheduleEntity;
 	}
 
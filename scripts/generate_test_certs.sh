@@ -74,3 +74,14 @@ certstrap --depot-path ${depot_path} sign metricserver_client --CA autoscaler-ca
 # metricsforwarder certificate for loggregator_agent
 certstrap --depot-path ${depot_path} request-cert --passphrase '' --common-name metron --ip 127.0.0.1
 certstrap --depot-path ${depot_path} sign metron --CA loggregator-ca --years "20"
+
+# CA to distribute to dummy cf instance identity certs
+certstrap --depot-path ${depot_path} init --passphrase '' --common-name cfInstanceIdentityCA --years "20"
+mv -f ${depot_path}/cfInstanceIdentityCA.crt ${depot_path}/cf-instance-identity-ca.crt
+mv -f ${depot_path}/cfInstanceIdentityCA.key ${depot_path}/cf-instance-identity-ca.key
+
+# cf instance identity certificate of app an-app-id for metricsforwarder, certstrap only supports one organizational unit
+openssl req -new -nodes -newkey rsa:2048 -keyout ${depot_path}/cf_instance.key -out ${depot_path}/cf_instance.csr -subj "/OU=organization:an-org-id/OU=space:a-space-id/OU=app:an-app-id/CN=an-instance-id"
+echo "extendedKeyUsage=clientAuth" > ${depot_path}/cf_instance.ext
+openssl x509 -req -sha256 -in ${depot_path}/cf_instance.csr -CA ${depot_path}/cf-instance-identity-ca.crt -CAkey ${depot_path}/cf-instance-identity-ca.key -set_serial 1 -days 7300 -extfile ${depot_path}/cf_instance.ext -out ${depot_path}/cf_instance.crt
+rm -f ${depot_path}/cf_instance.ext
@@ -6,10 +6,12 @@ import (
 	"autoscaler/api/schedulerutil"
 	"autoscaler/db"
 	"autoscaler/models"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"code.cloudfoundry.org/cfhttp/handlers"
 	"code.cloudfoundry.org/lager"
@@ -168,8 +170,8 @@ func (h *BrokerHandler) BindServiceInstance(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		h.logger.Info("saving policy json", lager.Data{"policy": body.Policy})
-		err = h.policydb.SaveAppPolicy(body.AppID, body.Policy, policyGuid.String())
+		h.logger.Info("saving policy json", lager.Data{"policy": models.RedactPolicyForLog(body.Policy)})
+		err = h.policydb.SaveAppPolicy(body.AppID, body.Policy, policyGuid.String(), originatingUserId(r))
 		if err != nil {
 			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
 				Code:    "Interal-Server-Error",
@@ -177,7 +179,7 @@ func (h *BrokerHandler) BindServiceInstance(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		h.logger.Info("creating/updating schedules", lager.Data{"policy": body.Policy})
+		h.logger.Info("creating/updating schedules", lager.Data{"policy": models.RedactPolicyForLog(body.Policy)})
 		err = h.schedulerUtil.CreateOrUpdateSchedule(body.AppID, body.Policy, policyGuid.String())
 		if err != nil {
 			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
@@ -264,3 +266,23 @@ func (h *BrokerHandler) UnbindServiceInstance(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("{}"))
 }
+
+// originatingUserId returns the user_id of the X-Broker-API-Originating-Identity header sent by Cloud Foundry, or ""
+// when it is not present.
+func originatingUserId(r *http.Request) string {
+	identity := strings.SplitN(r.Header.Get("X-Broker-API-Originating-Identity"), " ", 2)
+	if len(identity) != 2 || identity[0] != "cloudfoundry" {
+		return ""
+	}
+	value, err := base64.StdEncoding.DecodeString(identity[1])
+	if err != nil {
+		return ""
+	}
+	user := struct {
+		UserId string `json:"user_id"`
+	}{}
+	if json.Unmarshal(value, &user) != nil {
+		return ""
+	}
+	return user.UserId
+}
@@ -6,6 +6,7 @@ import (
 	"autoscaler/fakes"
 	"autoscaler/models"
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -386,10 +387,17 @@ var _ = Describe("BrokerHandler", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				req, err = http.NewRequest(http.MethodPut, "", bytes.NewReader(body))
+				req.Header.Set("X-Broker-API-Originating-Identity", "cloudfoundry "+base64.StdEncoding.EncodeToString([]byte(`{"user_id":"a-user-id"}`)))
 			})
 			It("succeeds with 201", func() {
 				Expect(resp.Code).To(Equal(http.StatusCreated))
 			})
+			It("saves the policy by the originating user", func() {
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(1))
+				appId, _, _, author := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(appId).To(Equal(testAppId))
+				Expect(author).To(Equal("a-user-id"))
+			})
 		})
 	})
 
@@ -141,6 +141,11 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	}
 
 	cfg.UseBuildInMode = false
+	cfg.Stream = config.StreamConfig{
+		PollInterval:         5 * time.Second,
+		HeartbeatInterval:    30 * time.Second,
+		SubscriberBufferSize: 100,
+	}
 
 	cfg.CF.API = ccServer.URL()
 	cfg.CF.GrantType = cf.GrantTypeClientCredentials
@@ -61,8 +61,8 @@ func main() {
 	}
 	defer policyDb.Close()
 
+	var bindingDB db.BindingDB
 	if !conf.UseBuildInMode {
-		var bindingDB db.BindingDB
 		bindingDB, err = sqldb.NewBindingSQLDB(conf.DB.BindingDB, logger.Session("bindingdb-db"))
 		if err != nil {
 			logger.Error("failed to connect bindingdb database", err, lager.Data{"dbConfig": conf.DB.BindingDB})
@@ -86,7 +86,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	publicApiHttpServer, err := publicapiserver.NewPublicApiServer(logger.Session("public_api_http_server"), conf, policyDb, cfClient)
+	publicApiHttpServer, err := publicapiserver.NewPublicApiServer(logger.Session("public_api_http_server"), conf, policyDb, bindingDB, cfClient, paClock)
 	if err != nil {
 		logger.Error("failed to create public api http server", err)
 		os.Exit(1)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v2"
@@ -16,7 +17,12 @@ import (
 )
 
 const (
-	DefaultLoggingLevel = "info"
+	DefaultLoggingLevel                 = "info"
+	DefaultStreamPollInterval           = 5 * time.Second
+	DefaultStreamHeartbeatInterval      = 30 * time.Second
+	DefaultStreamSubscriberBufferSize   = 100
+	DefaultCredentialRotationOverlap    = 24 * time.Hour
+	DefaultCredentialRotationMaxOverlap = 30 * 24 * time.Hour
 )
 
 type ServerConfig struct {
@@ -59,32 +65,60 @@ type EventGeneratorConfig struct {
 	TLSClientCerts    models.TLSCerts `yaml:"tls"`
 }
 
+type StreamConfig struct {
+	PollInterval         time.Duration `yaml:"poll_interval"`
+	HeartbeatInterval    time.Duration `yaml:"heartbeat_interval"`
+	SubscriberBufferSize int           `yaml:"subscriber_buffer_size"`
+}
+
+var defaultStreamConfig = StreamConfig{
+	PollInterval:         DefaultStreamPollInterval,
+	HeartbeatInterval:    DefaultStreamHeartbeatInterval,
+	SubscriberBufferSize: DefaultStreamSubscriberBufferSize,
+}
+
+// CredentialRotationConfig is the period the previous custom metrics credentials of an app stay valid after a
+// rotation, when the rotation request does not set it, and the maximum period a rotation request can set.
+type CredentialRotationConfig struct {
+	DefaultOverlap time.Duration `yaml:"default_overlap"`
+	MaxOverlap     time.Duration `yaml:"max_overlap"`
+}
+
+var defaultCredentialRotationConfig = CredentialRotationConfig{
+	DefaultOverlap: DefaultCredentialRotationOverlap,
+	MaxOverlap:     DefaultCredentialRotationMaxOverlap,
+}
+
 type Config struct {
-	Logging              helpers.LoggingConfig  `yaml:"logging"`
-	BrokerServer         ServerConfig           `yaml:"broker_server"`
-	PublicApiServer      ServerConfig           `yaml:"public_api_server"`
-	DB                   DBConfig               `yaml:"db"`
-	BrokerUsername       string                 `yaml:"broker_username"`
-	BrokerPassword       string                 `yaml:"broker_password"`
-	CatalogPath          string                 `yaml:"catalog_path"`
-	CatalogSchemaPath    string                 `yaml:"catalog_schema_path"`
-	DashboardRedirectURI string                 `yaml:"dashboard_redirect_uri"`
-	PolicySchemaPath     string                 `yaml:"policy_schema_path"`
-	Scheduler            SchedulerConfig        `yaml:"scheduler"`
-	ScalingEngine        ScalingEngineConfig    `yaml:"scaling_engine"`
-	MetricsCollector     MetricsCollectorConfig `yaml:"metrics_collector"`
-	EventGenerator       EventGeneratorConfig   `yaml:"event_generator"`
-	CF                   cf.CFConfig            `yaml:"cf"`
-	UseBuildInMode       bool                   `yaml:"use_buildin_mode"`
-	InfoFilePath         string                 `yaml:"info_file_path"`
+	Logging              helpers.LoggingConfig    `yaml:"logging"`
+	BrokerServer         ServerConfig             `yaml:"broker_server"`
+	PublicApiServer      ServerConfig             `yaml:"public_api_server"`
+	DB                   DBConfig                 `yaml:"db"`
+	BrokerUsername       string                   `yaml:"broker_username"`
+	BrokerPassword       string                   `yaml:"broker_password"`
+	CatalogPath          string                   `yaml:"catalog_path"`
+	CatalogSchemaPath    string                   `yaml:"catalog_schema_path"`
+	DashboardRedirectURI string                   `yaml:"dashboard_redirect_uri"`
+	PolicySchemaPath     string                   `yaml:"policy_schema_path"`
+	Scheduler            SchedulerConfig          `yaml:"scheduler"`
+	ScalingEngine        ScalingEngineConfig      `yaml:"scaling_engine"`
+	MetricsCollector     MetricsCollectorConfig   `yaml:"metrics_collector"`
+	EventGenerator       EventGeneratorConfig     `yaml:"event_generator"`
+	CF                   cf.CFConfig              `yaml:"cf"`
+	UseBuildInMode       bool                     `yaml:"use_buildin_mode"`
+	InfoFilePath         string                   `yaml:"info_file_path"`
+	Stream               StreamConfig             `yaml:"stream"`
+	CredentialRotation   CredentialRotationConfig `yaml:"credential_rotation"`
 }
 
 func LoadConfig(reader io.Reader) (*Config, error) {
 	conf := &Config{
-		Logging:         defaultLoggingConfig,
-		BrokerServer:    defaultBrokerServerConfig,
-		PublicApiServer: defaultPublicApiServerConfig,
-		UseBuildInMode:  false,
+		Logging:            defaultLoggingConfig,
+		BrokerServer:       defaultBrokerServerConfig,
+		PublicApiServer:    defaultPublicApiServerConfig,
+		UseBuildInMode:     false,
+		Stream:             defaultStreamConfig,
+		CredentialRotation: defaultCredentialRotationConfig,
 		CF: cf.CFConfig{
 			SkipSSLValidation: false,
 			GrantType:         cf.GrantTypeClientCredentials,
@@ -141,6 +175,21 @@ func (c *Config) Validate() error {
 	if c.InfoFilePath == "" {
 		return fmt.Errorf("Configuration error: InfoFilePath is empty")
 	}
+	if c.Stream.PollInterval <= 0 {
+		return fmt.Errorf("Configuration error: stream.poll_interval is less-equal than 0")
+	}
+	if c.Stream.HeartbeatInterval <= 0 {
+		return fmt.Errorf("Configuration error: stream.heartbeat_interval is less-equal than 0")
+	}
+	if c.Stream.SubscriberBufferSize <= 0 {
+		return fmt.Errorf("Configuration error: stream.subscriber_buffer_size is less-equal than 0")
+	}
+	if c.CredentialRotation.DefaultOverlap < 0 {
+		return fmt.Errorf("Configuration error: credential_rotation.default_overlap is less than 0")
+	}
+	if c.CredentialRotation.DefaultOverlap > c.CredentialRotation.MaxOverlap {
+		return fmt.Errorf("Configuration error: credential_rotation.default_overlap is greater than credential_rotation.max_overlap")
+	}
 	if !c.UseBuildInMode {
 		if c.DB.BindingDB.URL == "" {
 			return fmt.Errorf("Configuration error: BindingDB URL is empty")
@@ -141,6 +141,13 @@ event_generator:
     ca_file: /var/vcap/jobs/autoscaler/config/certs/autoscaler-ca.crt
 use_buildin_mode: false
 info_file_path: /var/vcap/jobs/autoscaer/config/info-file.json
+stream:
+  poll_interval: 2s
+  heartbeat_interval: 10s
+  subscriber_buffer_size: 50
+credential_rotation:
+  default_overlap: 48h
+  max_overlap: 168h
 cf:
   api: https://api.example.com
   client_id: client-id
@@ -183,6 +190,15 @@ cf:
 						},
 					},
 				))
+				Expect(conf.Stream).To(Equal(StreamConfig{
+					PollInterval:         2 * time.Second,
+					HeartbeatInterval:    10 * time.Second,
+					SubscriberBufferSize: 50,
+				}))
+				Expect(conf.CredentialRotation).To(Equal(CredentialRotationConfig{
+					DefaultOverlap: 48 * time.Hour,
+					MaxOverlap:     168 * time.Hour,
+				}))
 			})
 		})
 		Context("with partial config", func() {
@@ -236,6 +252,15 @@ cf:
 					}))
 				Expect(conf.UseBuildInMode).To(BeFalse())
 				Expect(conf.CF.GrantType).To(Equal(cf.GrantTypeClientCredentials))
+				Expect(conf.Stream).To(Equal(StreamConfig{
+					PollInterval:         DefaultStreamPollInterval,
+					HeartbeatInterval:    DefaultStreamHeartbeatInterval,
+					SubscriberBufferSize: DefaultStreamSubscriberBufferSize,
+				}))
+				Expect(conf.CredentialRotation).To(Equal(CredentialRotationConfig{
+					DefaultOverlap: DefaultCredentialRotationOverlap,
+					MaxOverlap:     DefaultCredentialRotationMaxOverlap,
+				}))
 			})
 		})
 
@@ -762,6 +787,15 @@ cf:
 
 			conf.InfoFilePath = "../exampleconfig/info-file.json"
 			conf.UseBuildInMode = false
+			conf.Stream = StreamConfig{
+				PollInterval:         5 * time.Second,
+				HeartbeatInterval:    30 * time.Second,
+				SubscriberBufferSize: 100,
+			}
+			conf.CredentialRotation = CredentialRotationConfig{
+				DefaultOverlap: 24 * time.Hour,
+				MaxOverlap:     30 * 24 * time.Hour,
+			}
 
 		})
 		JustBeforeEach(func() {
@@ -909,6 +943,51 @@ cf:
 			})
 		})
 
+		Context("when stream.poll_interval is not positive", func() {
+			BeforeEach(func() {
+				conf.Stream.PollInterval = 0
+			})
+			It("should err", func() {
+				Expect(err).To(MatchError(MatchRegexp("Configuration error: stream.poll_interval is less-equal than 0")))
+			})
+		})
+
+		Context("when stream.heartbeat_interval is not positive", func() {
+			BeforeEach(func() {
+				conf.Stream.HeartbeatInterval = 0
+			})
+			It("should err", func() {
+				Expect(err).To(MatchError(MatchRegexp("Configuration error: stream.heartbeat_interval is less-equal than 0")))
+			})
+		})
+
+		Context("when stream.subscriber_buffer_size is not positive", func() {
+			BeforeEach(func() {
+				conf.Stream.SubscriberBufferSize = 0
+			})
+			It("should err", func() {
+				Expect(err).To(MatchError(MatchRegexp("Configuration error: stream.subscriber_buffer_size is less-equal than 0")))
+			})
+		})
+
+		Context("when credential_rotation.default_overlap is negative", func() {
+			BeforeEach(func() {
+				conf.CredentialRotation.DefaultOverlap = -time.Hour
+			})
+			It("should err", func() {
+				Expect(err).To(MatchError(MatchRegexp("Configuration error: credential_rotation.default_overlap is less than 0")))
+			})
+		})
+
+		Context("when credential_rotation.default_overlap is greater than credential_rotation.max_overlap", func() {
+			BeforeEach(func() {
+				conf.CredentialRotation.MaxOverlap = time.Hour
+			})
+			It("should err", func() {
+				Expect(err).To(MatchError(MatchRegexp("Configuration error: credential_rotation.default_overlap is greater than credential_rotation.max_overlap")))
+			})
+		})
+
 		Context("when cf.grant_type is not client_credentials", func() {
 			BeforeEach(func() {
 				conf.CF.GrantType = cf.GrantTypePassword
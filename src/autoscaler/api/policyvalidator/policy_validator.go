@@ -1,9 +1,11 @@
 package policyvalidator
 
 import (
+	"autoscaler/helpers"
 	"autoscaler/models"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/xeipuuv/gojsonschema"
@@ -13,6 +15,8 @@ const (
 	DateTimeLayout = "2006-01-02T15:04"
 	DateLayout     = "2006-01-02"
 	TimeLayout     = "15:04"
+
+	minSeasonalHistoryWindowSecs = 2 * 24 * 60 * 60
 )
 
 type PolicyValidator struct {
@@ -115,7 +119,10 @@ func (pv *PolicyValidator) validateAttributes(policy *models.ScalingPolicy, resu
 	}
 
 	scalingRulesContext := gojsonschema.NewJsonContext("scaling_rules", rootContext)
-	pv.validateScalingRuleThreshold(policy, scalingRulesContext, result)
+	pv.validateScalingRules(policy.ScalingRules, "scaling_rules", scalingRulesContext, result)
+
+	metricSourcesContext := gojsonschema.NewJsonContext("metric_sources", rootContext)
+	pv.validateMetricSources(policy, metricSourcesContext, result)
 
 	if policy.Schedules == nil {
 		return
@@ -124,47 +131,211 @@ func (pv *PolicyValidator) validateAttributes(policy *models.ScalingPolicy, resu
 
 	pv.validateRecurringSchedules(policy, schedulesContext, result)
 	pv.validateSpecificDateSchedules(policy, schedulesContext, result)
+	pv.validateScheduleOverrides(policy, schedulesContext, result)
+}
+
+// validateScalingRules checks the rules of the policy or of a "rules" override of a schedule, rulesPath is the path of
+// the rules in the error descriptions.
+func (pv *PolicyValidator) validateScalingRules(rules []*models.ScalingRule, rulesPath string, scalingRulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	pv.validateScalingRuleThreshold(rules, rulesPath, scalingRulesContext, result)
+	pv.validatePredictiveScalingRules(rules, rulesPath, scalingRulesContext, result)
+	pv.validateCompoundScalingRules(rules, rulesPath, scalingRulesContext, result)
 }
 
-func (pv *PolicyValidator) validateScalingRuleThreshold(policy *models.ScalingPolicy, scalingRulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
-	for srIndex, scalingRule := range policy.ScalingRules {
+// validateScheduleOverrides checks the rules of the "rules" overrides like the rules of the policy.
+func (pv *PolicyValidator) validateScheduleOverrides(policy *models.ScalingPolicy, schedulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	recurringScheduleContext := gojsonschema.NewJsonContext("recurring_schedule", schedulesContext)
+	for scheduleIndex, schedule := range policy.Schedules.RecurringSchedules {
+		if schedule.Override == nil || schedule.Override.Mode != models.ScheduleOverrideRules {
+			continue
+		}
+		scheduleContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", scheduleIndex), recurringScheduleContext)
+		scalingRulesContext := gojsonschema.NewJsonContext("scaling_rules", gojsonschema.NewJsonContext("override", scheduleContext))
+		rulesPath := fmt.Sprintf("recurring_schedule[%d].override.scaling_rules", scheduleIndex)
+		pv.validateScalingRules(schedule.Override.ScalingRules, rulesPath, scalingRulesContext, result)
+	}
+
+	specficDateScheduleContext := gojsonschema.NewJsonContext("specific_date", schedulesContext)
+	for scheduleIndex, schedule := range policy.Schedules.SpecificDateSchedules {
+		if schedule.Override == nil || schedule.Override.Mode != models.ScheduleOverrideRules {
+			continue
+		}
+		scheduleContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", scheduleIndex), specficDateScheduleContext)
+		scalingRulesContext := gojsonschema.NewJsonContext("scaling_rules", gojsonschema.NewJsonContext("override", scheduleContext))
+		rulesPath := fmt.Sprintf("specific_date[%d].override.scaling_rules", scheduleIndex)
+		pv.validateScalingRules(schedule.Override.ScalingRules, rulesPath, scalingRulesContext, result)
+	}
+}
+
+func (pv *PolicyValidator) validateScalingRuleThreshold(rules []*models.ScalingRule, rulesPath string, scalingRulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	for srIndex, scalingRule := range rules {
 		currentContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", srIndex), scalingRulesContext)
 		errDetails := gojsonschema.ErrorDetails{
+			"scalingRules":     rulesPath,
 			"scalingRuleIndex": srIndex,
+			"field":            "threshold",
 		}
 
-		switch scalingRule.MetricType {
-		case "memoryused":
-			if scalingRule.Threshold <= 0 {
-				formatString := "scaling_rules[{{.scalingRuleIndex}}].threshold for metric_type memoryused should be greater than 0"
+		if scalingRule.Anomaly != nil {
+			if scalingRule.IsCompound() || scalingRule.IsTargetTracking() || scalingRule.Predictive != nil || scalingRule.RateOfChange != nil {
+				formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}] with anomaly should not have condition, target_value, predictive or rate_of_change"
 				err := newPolicyValidationError(currentContext, formatString, errDetails)
 				result.AddError(err, errDetails)
 			}
-		case "memoryutil":
-			if scalingRule.Threshold <= 0 || scalingRule.Threshold > 100 {
-				formatString := "scaling_rules[{{.scalingRuleIndex}}].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100"
+			if scalingRule.Anomaly.IsNotify() && scalingRule.Adjustment != "" {
+				formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}] with anomaly action notify should not have adjustment"
 				err := newPolicyValidationError(currentContext, formatString, errDetails)
 				result.AddError(err, errDetails)
 			}
-		case "responsetime":
-			if scalingRule.Threshold <= 0 {
-				formatString := "scaling_rules[{{.scalingRuleIndex}}].threshold for metric_type responsetime should be greater than 0"
+			// the threshold is the number of standard deviations away from the baseline whatever the metric type
+			continue
+		}
+
+		if scalingRule.RateOfChange != nil {
+			if scalingRule.IsCompound() || scalingRule.IsTargetTracking() || scalingRule.Predictive != nil {
+				formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}] with rate_of_change should not have condition, target_value or predictive"
 				err := newPolicyValidationError(currentContext, formatString, errDetails)
 				result.AddError(err, errDetails)
 			}
-		case "throughput":
-			if scalingRule.Threshold <= 0 {
-				formatString := "scaling_rules[{{.scalingRuleIndex}}].threshold for metric_type throughput should be greater than 0"
+			// the change of the metric can be negative or greater than 100 whatever the metric type
+			continue
+		}
+
+		if scalingRule.IsCompound() {
+			if scalingRule.MetricType != "" || scalingRule.Threshold != 0 || scalingRule.Operator != "" || scalingRule.IsTargetTracking() ||
+				scalingRule.Predictive != nil || scalingRule.Aggregation != "" {
+				formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}] with condition should not have metric_type, threshold, operator, target_value, predictive or aggregation"
+				err := newPolicyValidationError(currentContext, formatString, errDetails)
+				result.AddError(err, errDetails)
+			}
+			errDetails["field"] = "condition.threshold"
+			for _, comparison := range scalingRule.Condition.Comparisons() {
+				pv.validateThresholdValue(comparison.MetricType, comparison.Threshold, currentContext, errDetails, result)
+			}
+			continue
+		}
+
+		value := scalingRule.Threshold
+		if scalingRule.IsTargetTracking() {
+			value = scalingRule.TargetValue
+			errDetails["field"] = "target_value"
+
+			if scalingRule.Threshold != 0 || scalingRule.Operator != "" || scalingRule.Adjustment != "" || scalingRule.Predictive != nil {
+				formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}] with target_value should not have threshold, operator, adjustment or predictive"
 				err := newPolicyValidationError(currentContext, formatString, errDetails)
 				result.AddError(err, errDetails)
 			}
-		case "cpu":
-			if scalingRule.Threshold <= 0 || scalingRule.Threshold > 100 {
-				formatString := "scaling_rules[{{.scalingRuleIndex}}].threshold for metric_type cpu should be greater than 0 and less than equal to 100"
+		}
+
+		pv.validateThresholdValue(scalingRule.MetricType, value, currentContext, errDetails, result)
+	}
+}
+
+func (pv *PolicyValidator) validateThresholdValue(metricType string, value float64, currentContext *gojsonschema.JsonContext, errDetails gojsonschema.ErrorDetails, result *gojsonschema.Result) {
+	switch metricType {
+	case "memoryused":
+		if value <= 0 {
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].{{.field}} for metric_type memoryused should be greater than 0"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	case "memoryutil":
+		if value <= 0 || value > 100 {
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].{{.field}} for metric_type memoryutil should be greater than 0 and less than equal to 100"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	case "responsetime":
+		if value <= 0 {
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].{{.field}} for metric_type responsetime should be greater than 0"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	case "throughput":
+		if value <= 0 {
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].{{.field}} for metric_type throughput should be greater than 0"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	case "cpu":
+		if value <= 0 || value > 100 {
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].{{.field}} for metric_type cpu should be greater than 0 and less than equal to 100"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	default:
+	}
+}
+
+func (pv *PolicyValidator) validateCompoundScalingRules(rules []*models.ScalingRule, rulesPath string, scalingRulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	for srIndex, scalingRule := range rules {
+		if !scalingRule.IsCompound() {
+			continue
+		}
+		if len(satisfiableRanges(scalingRule.Condition)) == 0 {
+			currentContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", srIndex), scalingRulesContext)
+			errDetails := gojsonschema.ErrorDetails{
+				"scalingRules":     rulesPath,
+				"scalingRuleIndex": srIndex,
+				"condition":        scalingRule.Condition.String(),
+			}
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].condition {{.condition}} can never be satisfied"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	}
+}
+
+func (pv *PolicyValidator) validatePredictiveScalingRules(rules []*models.ScalingRule, rulesPath string, scalingRulesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	for srIndex, scalingRule := range rules {
+		predictive := scalingRule.Predictive
+		if predictive == nil {
+			continue
+		}
+		if predictive.Method == models.ForecastMethodHoltWinters && predictive.DailySeasonality &&
+			predictive.HistoryWindowSeconds != 0 && predictive.HistoryWindowSeconds < minSeasonalHistoryWindowSecs {
+			currentContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", srIndex), scalingRulesContext)
+			errDetails := gojsonschema.ErrorDetails{
+				"scalingRules":      rulesPath,
+				"scalingRuleIndex":  srIndex,
+				"historyWindowSecs": predictive.HistoryWindowSeconds,
+				"minHistoryWindow":  minSeasonalHistoryWindowSecs,
+			}
+			formatString := "{{.scalingRules}}[{{.scalingRuleIndex}}].predictive.history_window_secs {{.historyWindowSecs}} should be at least {{.minHistoryWindow}} for holt_winters with daily_seasonality"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		}
+	}
+}
+
+func (pv *PolicyValidator) validateMetricSources(policy *models.ScalingPolicy, metricSourcesContext *gojsonschema.JsonContext, result *gojsonschema.Result) {
+	metricTypes := map[string]int{}
+	for msIndex, source := range policy.MetricSources {
+		currentContext := gojsonschema.NewJsonContext(fmt.Sprintf("%d", msIndex), metricSourcesContext)
+		if previous, exists := metricTypes[source.MetricType]; exists {
+			errDetails := gojsonschema.ErrorDetails{
+				"metricSourceIndex": msIndex,
+				"previousIndex":     previous,
+				"metricType":        source.MetricType,
+			}
+			formatString := "metric_sources[{{.metricSourceIndex}}] has the same metric_type {{.metricType}} as metric_sources[{{.previousIndex}}]"
+			err := newPolicyValidationError(currentContext, formatString, errDetails)
+			result.AddError(err, errDetails)
+		} else {
+			metricTypes[source.MetricType] = msIndex
+		}
+
+		if source.Type == models.MetricSourceTypeHTTPJSON && source.JSONPath != "" {
+			if _, err := helpers.ParseJSONPath(source.JSONPath); err != nil {
+				errDetails := gojsonschema.ErrorDetails{
+					"metricSourceIndex": msIndex,
+					"jsonPath":          source.JSONPath,
+					"error":             err.Error(),
+				}
+				formatString := "metric_sources[{{.metricSourceIndex}}].json_path {{.jsonPath}} is invalid: {{.error}}"
 				err := newPolicyValidationError(currentContext, formatString, errDetails)
 				result.AddError(err, errDetails)
 			}
-		default:
 		}
 	}
 }
@@ -406,6 +577,10 @@ func (pv *PolicyValidator) validateOverlappingInSpecificDateSchedules(policy *mo
 func getErrorsObject(resErr []gojsonschema.ResultError) *[]PolicyValidationErrors {
 	policyValidationErrorsResult := []PolicyValidationErrors{}
 	for _, err := range resErr {
+		// the errors failing the "then" or "else" of a condition are reported on their own
+		if err.Type() == "condition_then" || err.Type() == "condition_else" {
+			continue
+		}
 		policyValidationErrorsResult = append(policyValidationErrorsResult, PolicyValidationErrors{
 			Context:     err.Context().String(),
 			Description: err.Description(),
@@ -414,6 +589,91 @@ func getErrorsObject(resErr []gojsonschema.ResultError) *[]PolicyValidationError
 	return &policyValidationErrorsResult
 }
 
+// valueRange is the range of a metric value that breaches a comparison.
+type valueRange struct {
+	low           float64
+	lowInclusive  bool
+	high          float64
+	highInclusive bool
+}
+
+func newValueRange(operator string, threshold float64) valueRange {
+	r := valueRange{low: math.Inf(-1), high: math.Inf(1)}
+	switch operator {
+	case ">":
+		r.low = threshold
+	case ">=":
+		r.low, r.lowInclusive = threshold, true
+	case "<":
+		r.high = threshold
+	case "<=":
+		r.high, r.highInclusive = threshold, true
+	}
+	return r
+}
+
+func (r valueRange) intersect(other valueRange) valueRange {
+	if other.low > r.low || (other.low == r.low && !other.lowInclusive) {
+		r.low, r.lowInclusive = other.low, other.lowInclusive
+	}
+	if other.high < r.high || (other.high == r.high && !other.highInclusive) {
+		r.high, r.highInclusive = other.high, other.highInclusive
+	}
+	return r
+}
+
+func (r valueRange) isEmpty() bool {
+	return r.low > r.high || (r.low == r.high && !(r.lowInclusive && r.highInclusive))
+}
+
+// satisfiableRanges expands the condition into the alternative combinations of value ranges, keyed by the metric type
+// and aggregation, that breach it. Combinations requiring an empty range are dropped, so the condition can never be
+// satisfied when none is returned.
+func satisfiableRanges(condition *models.ScalingCondition) []map[string]valueRange {
+	if len(condition.And) > 0 {
+		combinations := []map[string]valueRange{{}}
+		for _, child := range condition.And {
+			childCombinations := satisfiableRanges(child)
+			merged := []map[string]valueRange{}
+			for _, combination := range combinations {
+				for _, childCombination := range childCombinations {
+					if m, ok := mergeRanges(combination, childCombination); ok {
+						merged = append(merged, m)
+					}
+				}
+			}
+			combinations = merged
+		}
+		return combinations
+	}
+	if len(condition.Or) > 0 {
+		combinations := []map[string]valueRange{}
+		for _, child := range condition.Or {
+			combinations = append(combinations, satisfiableRanges(child)...)
+		}
+		return combinations
+	}
+	key := condition.MetricType + "#" + condition.AggregationFunction()
+	return []map[string]valueRange{{key: newValueRange(condition.Operator, condition.Threshold)}}
+}
+
+func mergeRanges(a map[string]valueRange, b map[string]valueRange) (map[string]valueRange, bool) {
+	merged := map[string]valueRange{}
+	for key, r := range a {
+		merged[key] = r
+	}
+	for key, r := range b {
+		if existing, found := merged[key]; found {
+			r = existing.intersect(r)
+			if r.isEmpty() {
+				return nil, false
+			}
+		}
+		merged[key] = r
+	}
+	return merged, true
+}
+
 func hasIntersection(a []int, b []int) bool {
 	m := make(map[int]bool)
 	for _, item := range a {
@@ -156,20 +156,1310 @@ var _ = Describe("PolicyValidator", func() {
 			})
 		})
 
+		Context("when mode is shadow", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"mode":"shadow",
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should succeed", func() {
+				Expect(valid).To(BeTrue())
+			})
+		})
+
+		Context("when mode is invalid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"mode":"dry-run",
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).mode",
+						Description: "mode must be one of the following: \"active\", \"shadow\"",
+					},
+				}))
+			})
+		})
+
+		Context("when scale-in stabilization is set", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scale_in_stabilization_secs":300,
+					"scale_in_max_step":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should succeed", func() {
+				Expect(valid).To(BeTrue())
+			})
+		})
+
+		Context("when scale_in_stabilization_secs is greater than 3600", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scale_in_stabilization_secs":3601,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).scale_in_stabilization_secs",
+						Description: "Must be less than or equal to 3600",
+					},
+				}))
+			})
+		})
+
+		Context("when scale_in_max_step is less than 1", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scale_in_max_step":0,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).scale_in_max_step",
+						Description: "Must be greater than or equal to 1",
+					},
+				}))
+			})
+		})
+
+		Context("when cool_down_per_rule is not a boolean", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"cool_down_per_rule":"true",
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).cool_down_per_rule",
+						Description: "Invalid type. Expected: boolean, given: string",
+					},
+				}))
+			})
+		})
+
+		Context("when webhooks are valid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}],
+					"webhooks":[
+					{
+						"url":"https://alerts.example.com/autoscaler",
+						"secret":"a-secret",
+						"events":["failed","max_instances_reached"]
+					},
+					{
+						"url":"http://audit.example.com/autoscaler",
+						"secret":"another-secret"
+					}]
+				}`
+			})
+			It("should succeed", func() {
+				Expect(valid).To(BeTrue())
+			})
+		})
+
+		Context("when the secret of a webhook is missing", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}],
+					"webhooks":[
+					{
+						"url":"https://alerts.example.com/autoscaler"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).webhooks.0",
+						Description: "secret is required",
+					},
+				}))
+			})
+		})
+
+		Context("when the url of a webhook is invalid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}],
+					"webhooks":[
+					{
+						"url":"ftp://alerts.example.com/autoscaler",
+						"secret":"a-secret"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).webhooks.0.url",
+						Description: "Does not match pattern '^https?://[^\\s]+$'",
+					},
+				}))
+			})
+		})
+
+		Context("when the events of a webhook are invalid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"adjustment":"+1"
+					}],
+					"webhooks":[
+					{
+						"url":"https://alerts.example.com/autoscaler",
+						"secret":"a-secret",
+						"events":["scaled"]
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).webhooks.0.events.0",
+						Description: "webhooks.0.events.0 must be one of the following: \"succeeded\", \"failed\", \"ignored\", \"simulated\", \"max_instances_reached\"",
+					},
+				}))
+			})
+		})
+
+		Context("when metric sources are valid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"http_json",
+						"url":"https://rabbitmq.example.com/api/queues/%2F/orders",
+						"json_path":"$.messages_ready",
+						"headers":{"Authorization":"Basic Z3Vlc3Q6Z3Vlc3Q="}
+					},
+					{
+						"metric_type":"consumer_lag",
+						"type":"prometheus",
+						"url":"http://prometheus.example.com",
+						"query":"sum(kafka_consumergroup_lag{group=\"orders\"})",
+						"unit":"messages"
+					}]
+				}`
+			})
+			It("should succeed", func() {
+				Expect(valid).To(BeTrue())
+			})
+		})
+
+		Context("when the json_path of a http_json metric source is missing", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"http_json",
+						"url":"https://rabbitmq.example.com/api/queues/%2F/orders"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).metric_sources.0",
+						Description: "json_path is required",
+					},
+				}))
+			})
+		})
+
+		Context("when the query of a prometheus metric source is missing", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"prometheus",
+						"url":"http://prometheus.example.com"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).metric_sources.0",
+						Description: "query is required",
+					},
+				}))
+			})
+		})
+
+		Context("when the type of a metric source is invalid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"graphite",
+						"url":"http://graphite.example.com",
+						"query":"sum(queue_depth)"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).metric_sources.0.type",
+						Description: "metric_sources.0.type must be one of the following: \"http_json\", \"prometheus\"",
+					},
+				}))
+			})
+		})
+
+		Context("when the json_path of a metric source is invalid", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"http_json",
+						"url":"https://rabbitmq.example.com/api/queues/%2F/orders",
+						"json_path":"messages_ready"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).metric_sources.0",
+						Description: "metric_sources[0].json_path messages_ready is invalid: json path \"messages_ready\" does not start with $",
+					},
+				}))
+			})
+		})
+
+		Context("when two metric sources have the same metric type", func() {
+			BeforeEach(func() {
+				policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"queue_depth",
+						"threshold":1000,
+						"operator":">",
+						"adjustment":"+1"
+					}],
+					"metric_sources":[
+					{
+						"metric_type":"queue_depth",
+						"type":"http_json",
+						"url":"https://rabbitmq.example.com/api/queues/%2F/orders",
+						"json_path":"$.messages_ready"
+					},
+					{
+						"metric_type":"queue_depth",
+						"type":"prometheus",
+						"url":"http://prometheus.example.com",
+						"query":"sum(rabbitmq_queue_messages_ready)"
+					}]
+				}`
+			})
+			It("should fail", func() {
+				Expect(valid).To(BeFalse())
+				Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+					{
+						Context:     "(root).metric_sources.1",
+						Description: "metric_sources[1] has the same metric_type queue_depth as metric_sources[0]",
+					},
+				}))
+			})
+		})
+
 		Context("Scaling Rules", func() {
 
-			Context("when metric_type is missing", func() {
+			Context("when metric_type is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"breach_duration_secs":600,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "metric_type is required",
+						},
+					}))
+				})
+			})
+
+			Context("when metric_type is non-alphanum", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type": "%me$ric",
+						"breach_duration_secs":600,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.metric_type",
+							Description: "Does not match pattern '^[a-zA-Z0-9_]+$'",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "threshold is required",
+						},
+					}))
+				})
+			})
+			Context("when threshold is not integer", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold": 90.55,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when threshold is not a number", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold": "90",
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.threshold",
+							Description: "Invalid type. Expected: number, given: string",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for memoryused is less than 0", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryused",
+						"breach_duration_secs":600,
+						"threshold": -90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type memoryused should be greater than 0",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for memoryused is greater than 100", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryused",
+						"breach_duration_secs":600,
+						"threshold": 500,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when threshold for memoryutil is less than 0", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold": -90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for memoryutil is greater than 100", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold": 500,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for responsetime is less than 0", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"responsetime",
+						"breach_duration_secs":600,
+						"threshold": -90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type responsetime should be greater than 0",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for responsetime is greater than 100", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"responsetime",
+						"breach_duration_secs":600,
+						"threshold": 500,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when threshold for throughput is less than 0", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"breach_duration_secs":600,
+						"threshold": -90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type throughput should be greater than 0",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for throughput is greater than 100", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"breach_duration_secs":600,
+						"threshold": 500,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when threshold for cpu is less than 0", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"cpu",
+						"breach_duration_secs":600,
+						"threshold": -90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type cpu should be greater than 0 and less than equal to 100",
+						},
+					}))
+				})
+			})
+
+			Context("when threshold for cpu is greater than 100", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"cpu",
+						"breach_duration_secs":600,
+						"threshold": 500,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].threshold for metric_type cpu should be greater than 0 and less than equal to 100",
+						},
+					}))
+				})
+			})
+
+			Context("when operator is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold":90,
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "operator is required",
+						},
+					}))
+				})
+			})
+
+			Context("when operator is invalid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"operator": "abcd",
+						"threshold":90,
+						"cool_down_secs":300,
+						"adjustment":"+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.operator",
+							Description: "scaling_rules.0.operator must be one of the following: \"\\u003c\", \"\\u003e\", \"\\u003c=\", \"\\u003e=\"",
+						},
+					}))
+				})
+			})
+
+			Context("when adjustment is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "adjustment is required",
+						},
+					}))
+				})
+			})
+
+			Context("when adjustment is invalid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs":600,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment": "5"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.adjustment",
+							Description: "Does not match pattern '^[-+][1-9]+[0-9]*$'",
+						},
+					}))
+				})
+			})
+			Context("when breach_duration_secs is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when breach_duration_secs is not an integer", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": "a",
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.breach_duration_secs",
+							Description: "Invalid type. Expected: integer, given: string",
+						},
+					}))
+				})
+			})
+
+			Context("when breach_duration_secs is less than 60", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": 5,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.breach_duration_secs",
+							Description: "Must be greater than or equal to 60",
+						},
+					}))
+				})
+			})
+
+			Context("when breach_duration_secs is greater than 3600", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": 55000,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":300,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.breach_duration_secs",
+							Description: "Must be less than or equal to 3600",
+						},
+					}))
+				})
+			})
+
+			Context("when cool_down_secs is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": 300,
+						"threshold":90,
+						"operator":">=",
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when cool_down_secs is less than 60", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": 300,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":5,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.cool_down_secs",
+							Description: "Must be greater than or equal to 60",
+						},
+					}))
+				})
+			})
+
+			Context("when cool_down_secs is greater than 3600", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"breach_duration_secs": 300,
+						"threshold":90,
+						"operator":">=",
+						"cool_down_secs":55000,
+						"adjustment": "+1"
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.cool_down_secs",
+							Description: "Must be less than or equal to 3600",
+						},
+					}))
+				})
+			})
+
+			Context("when predictive is valid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"linear_regression","forecast_horizon_secs":300,"daily_seasonality":true}
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when predictive method is missing", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"forecast_horizon_secs":300}
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.predictive",
+							Description: "method is required",
+						},
+					}))
+				})
+			})
+
+			Context("when rate_of_change is valid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":-10,
+						"operator":"<",
+						"adjustment":"-1",
+						"rate_of_change":{"window_secs":60,"change":"percent"}
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when rate_of_change change is invalid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"threshold":100,
+						"operator":">",
+						"adjustment":"+1",
+						"rate_of_change":{"window_secs":120,"change":"ratio"}
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.rate_of_change.change",
+							Description: "scaling_rules.0.rate_of_change.change must be one of the following: \"percent\", \"absolute\"",
+						},
+					}))
+				})
+			})
+
+			Context("when rate_of_change window_secs is less than 60", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"threshold":100,
+						"operator":">",
+						"adjustment":"+1",
+						"rate_of_change":{"window_secs":30}
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.rate_of_change.window_secs",
+							Description: "Must be greater than or equal to 60",
+						},
+					}))
+				})
+			})
+
+			Context("when rate_of_change is used together with predictive", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"throughput",
+						"threshold":100,
+						"operator":">",
+						"adjustment":"+1",
+						"rate_of_change":{"window_secs":120},
+						"predictive":{"method":"linear_regression","forecast_horizon_secs":300}
+					}]
+				}`
+				})
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0] with rate_of_change should not have condition, target_value or predictive",
+						},
+					}))
+				})
+			})
+
+			Context("when anomaly is valid", func() {
+				BeforeEach(func() {
+					policyString = `{
+					"instance_max_count":4,
+					"instance_min_count":1,
+					"scaling_rules":[
+					{
+						"metric_type":"memoryutil",
+						"threshold":3,
+						"operator":">",
+						"adjustment":"+1",
+						"anomaly":{"action":"scale"}
+					},
+					{
+						"metric_type":"throughput",
+						"threshold":-3,
+						"operator":"<",
+						"anomaly":{"action":"notify"}
+					}]
+				}`
+				})
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
+				})
+			})
+
+			Context("when anomaly which scales has no adjustment", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"breach_duration_secs":600,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":3,
+						"operator":">",
+						"anomaly":{}
 					}]
 				}`
 				})
@@ -178,25 +1468,24 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
 							Context:     "(root).scaling_rules.0",
-							Description: "metric_type is required",
+							Description: "adjustment is required",
 						},
 					}))
 				})
 			})
 
-			Context("when metric_type is non-alphanum", func() {
+			Context("when anomaly which notifies has adjustment", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type": "%me$ric",
-						"breach_duration_secs":600,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":3,
+						"operator":">",
+						"adjustment":"+1",
+						"anomaly":{"action":"notify"}
 					}]
 				}`
 				})
@@ -204,25 +1493,25 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.metric_type",
-							Description: "Does not match pattern '^[a-zA-Z0-9_]+$'",
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0] with anomaly action notify should not have adjustment",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold is missing", func() {
+			Context("when anomaly action is invalid", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":3,
+						"operator":">",
+						"adjustment":"+1",
+						"anomaly":{"action":"alert"}
 					}]
 				}`
 				})
@@ -230,25 +1519,26 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0",
-							Description: "threshold is required",
+							Context:     "(root).scaling_rules.0.anomaly.action",
+							Description: "scaling_rules.0.anomaly.action must be one of the following: \"scale\", \"notify\"",
 						},
 					}))
 				})
 			})
-			Context("when threshold is not integer", func() {
+
+			Context("when anomaly is used together with rate_of_change", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"threshold": 90.55,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":3,
+						"operator":">",
+						"adjustment":"+1",
+						"anomaly":{"action":"scale"},
+						"rate_of_change":{"window_secs":120}
 					}]
 				}`
 				})
@@ -256,74 +1546,71 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.threshold",
-							Description: "Invalid type. Expected: integer, given: number",
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0] with anomaly should not have condition, target_value, predictive or rate_of_change",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for memoryused is less than 0", func() {
+			Context("when aggregation is valid", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryused",
-						"breach_duration_secs":600,
-						"threshold": -90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"responsetime",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"aggregation":"p99"
 					}]
 				}`
 				})
-				It("should fail", func() {
-					Expect(valid).To(BeFalse())
-					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
-						{
-							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type memoryused should be greater than 0",
-						},
-					}))
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
 				})
 			})
 
-			Context("when threshold for memoryused is greater than 100", func() {
+			Context("when aggregation is invalid", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryused",
-						"breach_duration_secs":600,
-						"threshold": 500,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"responsetime",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"aggregation":"median"
 					}]
 				}`
 				})
-				It("should succeed", func() {
-					Expect(valid).To(BeTrue())
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.aggregation",
+							Description: "scaling_rules.0.aggregation must be one of the following: \"avg\", \"max\", \"min\", \"sum\", \"p50\", \"p90\", \"p99\"",
+						},
+					}))
 				})
 			})
 
-			Context("when threshold for memoryutil is less than 0", func() {
+			Context("when predictive method is invalid", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"threshold": -90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"abcd","forecast_horizon_secs":300}
 					}]
 				}`
 				})
@@ -331,26 +1618,25 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100",
+							Context:     "(root).scaling_rules.0.predictive.method",
+							Description: "scaling_rules.0.predictive.method must be one of the following: \"linear_regression\", \"holt_winters\"",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for memoryutil is greater than 100", func() {
+			Context("when predictive forecast_horizon_secs is missing", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"threshold": 500,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"linear_regression"}
 					}]
 				}`
 				})
@@ -358,26 +1644,25 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100",
+							Context:     "(root).scaling_rules.0.predictive",
+							Description: "forecast_horizon_secs is required",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for responsetime is less than 0", func() {
+			Context("when predictive forecast_horizon_secs is greater than 3600", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"responsetime",
-						"breach_duration_secs":600,
-						"threshold": -90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"linear_regression","forecast_horizon_secs":7200}
 					}]
 				}`
 				})
@@ -385,35 +1670,40 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type responsetime should be greater than 0",
+							Context:     "(root).scaling_rules.0.predictive.forecast_horizon_secs",
+							Description: "Must be less than or equal to 3600",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for responsetime is greater than 100", func() {
+			Context("when predictive history_window_secs is less than 600", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"responsetime",
-						"breach_duration_secs":600,
-						"threshold": 500,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"linear_regression","forecast_horizon_secs":300,"history_window_secs":60}
 					}]
 				}`
 				})
-				It("should succeed", func() {
-					Expect(valid).To(BeTrue())
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.predictive.history_window_secs",
+							Description: "Must be greater than or equal to 600",
+						},
+					}))
 				})
 			})
 
-			Context("when threshold for throughput is less than 0", func() {
+			Context("when predictive history_window_secs is shorter than two days for seasonal holt_winters", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
@@ -421,11 +1711,10 @@ var _ = Describe("PolicyValidator", func() {
 					"scaling_rules":[
 					{
 						"metric_type":"throughput",
-						"breach_duration_secs":600,
-						"threshold": -90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"threshold":500,
+						"operator":">",
+						"adjustment":"+1",
+						"predictive":{"method":"holt_winters","forecast_horizon_secs":300,"history_window_secs":86400,"daily_seasonality":true}
 					}]
 				}`
 				})
@@ -434,25 +1723,22 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
 							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type throughput should be greater than 0",
+							Description: "scaling_rules[0].predictive.history_window_secs 86400 should be at least 172800 for holt_winters with daily_seasonality",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for throughput is greater than 100", func() {
+			Context("when target_value is used instead of threshold, operator and adjustment", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"throughput",
-						"breach_duration_secs":600,
-						"threshold": 500,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"cpu",
+						"target_value":60,
+						"scale_in_damping":0.5
 					}]
 				}`
 				})
@@ -461,7 +1747,7 @@ var _ = Describe("PolicyValidator", func() {
 				})
 			})
 
-			Context("when threshold for cpu is less than 0", func() {
+			Context("when target_value is used together with threshold", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
@@ -469,10 +1755,9 @@ var _ = Describe("PolicyValidator", func() {
 					"scaling_rules":[
 					{
 						"metric_type":"cpu",
-						"breach_duration_secs":600,
-						"threshold": -90,
-						"operator":">=",
-						"cool_down_secs":300,
+						"target_value":60,
+						"threshold":80,
+						"operator":">",
 						"adjustment":"+1"
 					}]
 				}`
@@ -482,13 +1767,13 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
 							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type cpu should be greater than 0 and less than equal to 100",
+							Description: "scaling_rules[0] with target_value should not have threshold, operator, adjustment or predictive",
 						},
 					}))
 				})
 			})
 
-			Context("when threshold for cpu is greater than 100", func() {
+			Context("when target_value for cpu is greater than 100", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
@@ -496,11 +1781,7 @@ var _ = Describe("PolicyValidator", func() {
 					"scaling_rules":[
 					{
 						"metric_type":"cpu",
-						"breach_duration_secs":600,
-						"threshold": 500,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"target_value":160
 					}]
 				}`
 				})
@@ -509,24 +1790,21 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
 							Context:     "(root).scaling_rules.0",
-							Description: "scaling_rules[0].threshold for metric_type cpu should be greater than 0 and less than equal to 100",
+							Description: "scaling_rules[0].target_value for metric_type cpu should be greater than 0 and less than equal to 100",
 						},
 					}))
 				})
 			})
 
-			Context("when operator is missing", func() {
+			Context("when target_value is not greater than 0", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"threshold":90,
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"throughput",
+						"target_value":0
 					}]
 				}`
 				})
@@ -534,26 +1812,23 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0",
-							Description: "operator is required",
+							Context:     "(root).scaling_rules.0.target_value",
+							Description: "Must be greater than 0",
 						},
 					}))
 				})
 			})
 
-			Context("when operator is invalid", func() {
+			Context("when scale_in_damping is greater than 1", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"operator": "abcd",
-						"threshold":90,
-						"cool_down_secs":300,
-						"adjustment":"+1"
+						"metric_type":"cpu",
+						"target_value":60,
+						"scale_in_damping":1.5
 					}]
 				}`
 				})
@@ -561,52 +1836,52 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.operator",
-							Description: "scaling_rules.0.operator must be one of the following: \"\\u003c\", \"\\u003e\", \"\\u003c=\", \"\\u003e=\"",
+							Context:     "(root).scaling_rules.0.scale_in_damping",
+							Description: "Must be less than or equal to 1",
 						},
 					}))
 				})
 			})
 
-			Context("when adjustment is missing", func() {
+			Context("when condition is valid", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70},
+							{"or":[
+								{"metric_type":"cpu","operator":"<","threshold":50},
+								{"metric_type":"throughput","operator":">","threshold":500,"aggregation":"sum"}
+							]}
+						]},
 						"breach_duration_secs":600,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300
+						"adjustment":"+1"
 					}]
 				}`
 				})
-				It("should fail", func() {
-					Expect(valid).To(BeFalse())
-					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
-						{
-							Context:     "(root).scaling_rules.0",
-							Description: "adjustment is required",
-						},
-					}))
+				It("should succeed", func() {
+					Expect(valid).To(BeTrue())
 				})
 			})
 
-			Context("when adjustment is invalid", func() {
+			Context("when condition can never be satisfied", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs":600,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment": "5"
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70},
+							{"or":[
+								{"metric_type":"cpu","operator":"<","threshold":50},
+								{"metric_type":"cpu","operator":"<=","threshold":70}
+							]}
+						]},
+						"adjustment":"+1"
 					}]
 				}`
 				})
@@ -614,24 +1889,25 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.adjustment",
-							Description: "Does not match pattern '^[-+][1-9]+[0-9]*$'",
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].condition (cpu > 70 and (cpu < 50 or cpu <= 70)) can never be satisfied",
 						},
 					}))
 				})
 			})
-			Context("when breach_duration_secs is missing", func() {
+
+			Context("when condition compares different aggregations of the same metric", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment": "+1"
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":90,"aggregation":"max"},
+							{"metric_type":"cpu","operator":"<","threshold":50}
+						]},
+						"adjustment":"+1"
 					}]
 				}`
 				})
@@ -640,46 +1916,21 @@ var _ = Describe("PolicyValidator", func() {
 				})
 			})
 
-			Context("when breach_duration_secs is not an integer", func() {
-				BeforeEach(func() {
-					policyString = `{
-					"instance_max_count":4,
-					"instance_min_count":1,
-					"scaling_rules":[
-					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": "a",
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment": "+1"
-					}]
-				}`
-				})
-				It("should fail", func() {
-					Expect(valid).To(BeFalse())
-					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
-						{
-							Context:     "(root).scaling_rules.0.breach_duration_secs",
-							Description: "Invalid type. Expected: integer, given: string",
-						},
-					}))
-				})
-			})
-
-			Context("when breach_duration_secs is less than 60", func() {
+			Context("when condition is used together with metric_type and threshold", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": 5,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment": "+1"
+						"metric_type":"cpu",
+						"threshold":80,
+						"operator":">",
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70},
+							{"metric_type":"throughput","operator":">","threshold":500}
+						]},
+						"adjustment":"+1"
 					}]
 				}`
 				})
@@ -687,26 +1938,25 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.breach_duration_secs",
-							Description: "Must be greater than or equal to 60",
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0] with condition should not have metric_type, threshold, operator, target_value, predictive or aggregation",
 						},
 					}))
 				})
 			})
 
-			Context("when breach_duration_secs is greater than 3600", func() {
+			Context("when condition has a threshold out of range", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": 55000,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":300,
-						"adjustment": "+1"
+						"condition":{"or":[
+							{"metric_type":"cpu","operator":">","threshold":170},
+							{"metric_type":"throughput","operator":">","threshold":500}
+						]},
+						"adjustment":"+1"
 					}]
 				}`
 				})
@@ -714,73 +1964,72 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.breach_duration_secs",
-							Description: "Must be less than or equal to 3600",
+							Context:     "(root).scaling_rules.0",
+							Description: "scaling_rules[0].condition.threshold for metric_type cpu should be greater than 0 and less than equal to 100",
 						},
 					}))
 				})
 			})
 
-			Context("when cool_down_secs is missing", func() {
+			Context("when condition has a single child", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": 300,
-						"threshold":90,
-						"operator":">=",
-						"adjustment": "+1"
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70}
+						]},
+						"adjustment":"+1"
 					}]
 				}`
 				})
-				It("should succeed", func() {
-					Expect(valid).To(BeTrue())
+				It("should fail", func() {
+					Expect(valid).To(BeFalse())
+					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+						{
+							Context:     "(root).scaling_rules.0.condition.and",
+							Description: "Array must have at least 2 items",
+						},
+					}))
 				})
 			})
 
-			Context("when cool_down_secs is less than 60", func() {
+			Context("when condition mixes a group and a comparison", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": 300,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":5,
-						"adjustment": "+1"
+						"condition":{
+							"metric_type":"cpu","operator":">","threshold":70,
+							"or":[
+								{"metric_type":"cpu","operator":">","threshold":70},
+								{"metric_type":"throughput","operator":">","threshold":500}
+							]
+						},
+						"adjustment":"+1"
 					}]
 				}`
 				})
 				It("should fail", func() {
 					Expect(valid).To(BeFalse())
-					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
-						{
-							Context:     "(root).scaling_rules.0.cool_down_secs",
-							Description: "Must be greater than or equal to 60",
-						},
-					}))
 				})
 			})
 
-			Context("when cool_down_secs is greater than 3600", func() {
+			Context("when condition is missing adjustment", func() {
 				BeforeEach(func() {
 					policyString = `{
 					"instance_max_count":4,
 					"instance_min_count":1,
 					"scaling_rules":[
 					{
-						"metric_type":"memoryutil",
-						"breach_duration_secs": 300,
-						"threshold":90,
-						"operator":">=",
-						"cool_down_secs":55000,
-						"adjustment": "+1"
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70},
+							{"metric_type":"throughput","operator":">","threshold":500}
+						]}
 					}]
 				}`
 				})
@@ -788,8 +2037,8 @@ var _ = Describe("PolicyValidator", func() {
 					Expect(valid).To(BeFalse())
 					Expect(errResult).To(Equal(&[]PolicyValidationErrors{
 						{
-							Context:     "(root).scaling_rules.0.cool_down_secs",
-							Description: "Must be less than or equal to 3600",
+							Context:     "(root).scaling_rules.0",
+							Description: "adjustment is required",
 						},
 					}))
 				})
@@ -2258,6 +3507,268 @@ var _ = Describe("PolicyValidator", func() {
 						}))
 					})
 				})
+				Context("when override pins the instance count", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"pin","instance_count":3}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should succeed", func() {
+						Expect(valid).To(BeTrue())
+					})
+				})
+				Context("when override pins without instance_count", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"pin"}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override",
+								Description: "instance_count is required",
+							},
+						}))
+					})
+				})
+				Context("when override swaps in scaling rules", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[{"metric_type":"cpu","threshold":90,"operator":">","adjustment":"+2"}]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should succeed", func() {
+						Expect(valid).To(BeTrue())
+					})
+				})
+				Context("when override scaling rule is missing adjustment", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[{"metric_type":"cpu","threshold":90,"operator":">"}]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override.scaling_rules.0",
+								Description: "adjustment is required",
+							},
+						}))
+					})
+				})
+				Context("when override scaling rule has an invalid threshold", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"recurring_schedule":[
+							   {
+								  "start_time":"10:00",
+								  "end_time":"18:00",
+								  "days_of_week":[1,2,3],
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[{"metric_type":"memoryutil","threshold":150,"operator":">","adjustment":"+1"}]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.recurring_schedule.0.override.scaling_rules.0",
+								Description: "recurring_schedule[0].override.scaling_rules[0].threshold for metric_type memoryutil should be greater than 0 and less than equal to 100",
+							},
+						}))
+					})
+				})
+				Context("when override scaling rule has target_value together with threshold", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[
+									{"metric_type":"cpu","threshold":90,"operator":">","adjustment":"+2"},
+									{"metric_type":"cpu","target_value":60,"threshold":80}
+								  ]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override.scaling_rules.1",
+								Description: "specific_date[0].override.scaling_rules[1] with target_value should not have threshold, operator, adjustment or predictive",
+							},
+						}))
+					})
+				})
+				Context("when override scaling rule has a condition which can never be satisfied", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[{
+									"condition":{"and":[
+										{"metric_type":"cpu","operator":">","threshold":70},
+										{"metric_type":"cpu","operator":"<","threshold":50}
+									]},
+									"adjustment":"+1"
+								  }]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override.scaling_rules.0",
+								Description: "specific_date[0].override.scaling_rules[0].condition (cpu > 70 and cpu < 50) can never be satisfied",
+							},
+						}))
+					})
+				})
+				Context("when override scaling rule has a predictive history window which is too short", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"rules","scaling_rules":[{
+									"metric_type":"cpu","threshold":80,"operator":">","adjustment":"+1",
+									"predictive":{"method":"holt_winters","forecast_horizon_secs":300,"history_window_secs":86400,"daily_seasonality":true}
+								  }]}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override.scaling_rules.0",
+								Description: "specific_date[0].override.scaling_rules[0].predictive.history_window_secs 86400 should be at least 172800 for holt_winters with daily_seasonality",
+							},
+						}))
+					})
+				})
+				Context("when override mode is invalid", func() {
+					BeforeEach(func() {
+						policyString = `{
+						"instance_max_count":4,
+						"instance_min_count":1,
+						"schedules":{
+							"timezone":"Asia/Kolkata",
+							"specific_date":[
+							   {
+								  "start_date_time":"2099-01-04T20:00",
+								  "end_date_time":"2099-02-19T23:15",
+								  "instance_min_count":2,
+								  "instance_max_count":5,
+								  "override":{"mode":"freeze"}
+							   }
+							]
+						 }
+					}`
+					})
+					It("should fail", func() {
+						Expect(valid).To(BeFalse())
+						Expect(errResult).To(Equal(&[]PolicyValidationErrors{
+							{
+								Context:     "(root).schedules.specific_date.0.override.mode",
+								Description: "schedules.specific_date.0.override.mode must be one of the following: \"suspend\", \"pin\", \"rules\"",
+							},
+						}))
+					})
+				})
 			})
 
 		})
@@ -0,0 +1,284 @@
+package publicapiserver
+
+import (
+	"autoscaler/api/config"
+	"autoscaler/db"
+	"autoscaler/helpers"
+	"autoscaler/models"
+	"autoscaler/routes"
+
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/cfhttp/handlers"
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	StreamEventScalingHistory   = "scaling_history"
+	StreamEventAggregatedMetric = "aggregated_metric"
+)
+
+type StreamEvent struct {
+	Event string
+	Data  []byte
+}
+
+// the subscribers of an app are grouped by the aggregation of the metrics they receive
+type streamKey struct {
+	appId       string
+	aggregation string
+}
+
+type appStream struct {
+	subscribers map[chan *StreamEvent]struct{}
+	stop        chan struct{}
+}
+
+// EventStream pushes the new scaling histories and aggregated metrics of an app to its subscribers. The scaling
+// engine and the event generator are polled once per app and aggregation however many subscribers there are.
+type EventStream struct {
+	logger               lager.Logger
+	conf                 *config.Config
+	policydb             db.PolicyDB
+	scalingEngineClient  *http.Client
+	eventGeneratorClient *http.Client
+	clock                clock.Clock
+	lock                 sync.Mutex
+	appStreams           map[streamKey]*appStream
+}
+
+func NewEventStream(logger lager.Logger, conf *config.Config, policydb db.PolicyDB, clock clock.Clock) *EventStream {
+	seClient, err := helpers.CreateHTTPClient(&conf.ScalingEngine.TLSClientCerts)
+	if err != nil {
+		logger.Error("failed to create http client for ScalingEngine", err, lager.Data{"scalingengine": conf.ScalingEngine.TLSClientCerts})
+		os.Exit(1)
+	}
+	egClient, err := helpers.CreateHTTPClient(&conf.EventGenerator.TLSClientCerts)
+	if err != nil {
+		logger.Error("failed to create http client for EventGenerator", err, lager.Data{"eventgenerator": conf.EventGenerator.TLSClientCerts})
+		os.Exit(1)
+	}
+	return &EventStream{
+		logger:               logger.Session("event-stream"),
+		conf:                 conf,
+		policydb:             policydb,
+		scalingEngineClient:  seClient,
+		eventGeneratorClient: egClient,
+		clock:                clock,
+		appStreams:           map[streamKey]*appStream{},
+	}
+}
+
+// Subscribe returns the events of an app, the aggregated metrics are those aggregated with the given function.
+func (s *EventStream) Subscribe(appId string, aggregation string) chan *StreamEvent {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := streamKey{appId: appId, aggregation: aggregation}
+	stream, exists := s.appStreams[key]
+	if !exists {
+		stream = &appStream{
+			subscribers: map[chan *StreamEvent]struct{}{},
+			stop:        make(chan struct{}),
+		}
+		s.appStreams[key] = stream
+		go s.poll(key, stream.stop)
+	}
+
+	events := make(chan *StreamEvent, s.conf.Stream.SubscriberBufferSize)
+	stream.subscribers[events] = struct{}{}
+	return events
+}
+
+func (s *EventStream) Unsubscribe(appId string, aggregation string, events chan *StreamEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := streamKey{appId: appId, aggregation: aggregation}
+	stream, exists := s.appStreams[key]
+	if !exists {
+		return
+	}
+	delete(stream.subscribers, events)
+	if len(stream.subscribers) == 0 {
+		close(stream.stop)
+		delete(s.appStreams, key)
+	}
+}
+
+func (s *EventStream) poll(key streamKey, stop chan struct{}) {
+	appId := key.appId
+	logger := s.logger.WithData(lager.Data{"appId": appId, "aggregation": key.aggregation})
+	logger.Info("start-polling")
+
+	ticker := s.clock.NewTicker(s.conf.Stream.PollInterval)
+	defer ticker.Stop()
+
+	// only the activities after the first subscription are streamed
+	since := s.clock.Now().UnixNano()
+	historyStart := since
+	metricStarts := map[string]int64{}
+
+	for {
+		select {
+		case <-stop:
+			logger.Info("stop-polling")
+			return
+		case <-ticker.C():
+		}
+
+		histories := []*models.AppScalingHistory{}
+		path, _ := routes.ScalingEngineRoutes().Get(routes.GetScalingHistoriesRouteName).URLPath("appid", appId)
+		err := s.get(s.scalingEngineClient, s.conf.ScalingEngine.ScalingEngineUrl+path.RequestURI(), historyStart, "", &histories)
+		if err != nil {
+			logger.Error("failed-to-retrieve-scaling-histories", err)
+		}
+		for _, history := range histories {
+			if history.Timestamp < historyStart {
+				continue
+			}
+			historyStart = history.Timestamp + 1
+			s.broadcast(key, StreamEventScalingHistory, history)
+		}
+
+		policy, err := s.policydb.GetAppPolicy(appId)
+		if err != nil {
+			logger.Error("failed-to-get-app-policy", err)
+			continue
+		}
+		if policy == nil {
+			continue
+		}
+		for _, rule := range policy.ScalingRules {
+			for _, metricType := range rule.MetricTypes() {
+				if _, exists := metricStarts[metricType]; !exists {
+					metricStarts[metricType] = since
+				}
+				metrics := []*models.AppMetric{}
+				path, _ := routes.EventGeneratorRoutes().Get(routes.GetAggregatedMetricHistoriesRouteName).URLPath("appid", appId, "metrictype", metricType)
+				err = s.get(s.eventGeneratorClient, s.conf.EventGenerator.EventGeneratorUrl+path.RequestURI(), metricStarts[metricType], key.aggregation, &metrics)
+				if err != nil {
+					logger.Error("failed-to-retrieve-aggregated-metrics", err, lager.Data{"metricType": metricType})
+					continue
+				}
+				for _, metric := range metrics {
+					if metric.Timestamp < metricStarts[metricType] {
+						continue
+					}
+					metricStarts[metricType] = metric.Timestamp + 1
+					s.broadcast(key, StreamEventAggregatedMetric, metric)
+				}
+			}
+		}
+	}
+}
+
+func (s *EventStream) get(client *http.Client, baseUrl string, start int64, aggregation string, result interface{}) error {
+	parameters := url.Values{}
+	parameters.Add("start", strconv.FormatInt(start, 10))
+	parameters.Add("order", "asc")
+	if aggregation != "" {
+		parameters.Add("aggregation", aggregation)
+	}
+
+	resp, err := client.Get(baseUrl + "?" + parameters.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, result)
+}
+
+func (s *EventStream) broadcast(key streamKey, event string, value interface{}) {
+	appId := key.appId
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.logger.Error("failed-to-marshal-event", err, lager.Data{"appId": appId, "event": event})
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stream, exists := s.appStreams[key]
+	if !exists {
+		return
+	}
+	for events := range stream.subscribers {
+		select {
+		case events <- &StreamEvent{Event: event, Data: data}:
+		default:
+			s.logger.Info("drop-event-for-slow-subscriber", lager.Data{"appId": appId, "event": event})
+		}
+	}
+}
+
+func (s *EventStream) StreamAppEvents(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.logger.Error("Streaming is not supported", nil, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Streaming is not supported"})
+		return
+	}
+
+	aggregation := r.URL.Query().Get("aggregation")
+	if aggregation == "" {
+		aggregation = models.AggregationAvg
+	}
+	if !models.IsValidAggregation(aggregation) {
+		s.logger.Error("Bad Request", nil, lager.Data{"appId": appId, "aggregation": aggregation})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "aggregation must be one of " + strings.Join(models.Aggregations, ", "),
+		})
+		return
+	}
+
+	s.logger.Info("Stream App Events", lager.Data{"appId": appId, "aggregation": aggregation})
+
+	events := s.Subscribe(appId, aggregation)
+	defer s.Unsubscribe(appId, aggregation, events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := s.clock.NewTicker(s.conf.Stream.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			s.logger.Info("Stream App Events closed", lager.Data{"appId": appId})
+			return
+		case event := <-events:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Event, event.Data)
+		case <-heartbeat.C():
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,186 @@
+package publicapiserver_test
+
+import (
+	. "autoscaler/api/publicapiserver"
+	"autoscaler/fakes"
+	"autoscaler/models"
+
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStream", func() {
+	var (
+		policydb    *fakes.FakePolicyDB
+		fclock      *fakeclock.FakeClock
+		eventStream *EventStream
+		history     models.AppScalingHistory
+		metric      models.AppMetric
+	)
+
+	BeforeEach(func() {
+		policydb = &fakes.FakePolicyDB{}
+		policydb.GetAppPolicyReturns(&models.ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 5,
+			ScalingRules: []*models.ScalingRule{
+				{MetricType: TEST_METRIC_TYPE, Operator: ">", Threshold: 80, Adjustment: "+1"},
+			},
+		}, nil)
+		fclock = fakeclock.NewFakeClock(time.Now())
+		eventStream = NewEventStream(lager.NewLogger("test"), conf, policydb, fclock)
+
+		history = models.AppScalingHistory{
+			AppId:        TEST_APP_ID,
+			Timestamp:    fclock.Now().Add(time.Second).UnixNano(),
+			Status:       models.ScalingStatusSucceeded,
+			OldInstances: 2,
+			NewInstances: 3,
+			Reason:       "a reason",
+		}
+		metric = models.AppMetric{
+			AppId:      TEST_APP_ID,
+			MetricType: TEST_METRIC_TYPE,
+			Unit:       TEST_METRIC_UNIT,
+			Value:      90,
+			Timestamp:  fclock.Now().Add(time.Second).UnixNano(),
+		}
+		scalingEngineStatus = http.StatusOK
+		scalingEngineResponse = []models.AppScalingHistory{
+			{AppId: TEST_APP_ID, Timestamp: 300, Reason: "an old reason"},
+			history,
+		}
+		eventGeneratorStatus = http.StatusOK
+		eventGeneratorResponse = []models.AppMetric{metric}
+	})
+
+	expectedEvent := func(event string, value interface{}) *StreamEvent {
+		data, err := json.Marshal(value)
+		Expect(err).NotTo(HaveOccurred())
+		return &StreamEvent{Event: event, Data: data}
+	}
+
+	Describe("Subscribe", func() {
+		var events chan *StreamEvent
+
+		BeforeEach(func() {
+			events = eventStream.Subscribe(TEST_APP_ID, models.AggregationAvg)
+			Eventually(fclock.WatcherCount).Should(Equal(1))
+		})
+
+		AfterEach(func() {
+			eventStream.Unsubscribe(TEST_APP_ID, models.AggregationAvg, events)
+		})
+
+		It("streams the new scaling histories and aggregated metrics once", func() {
+			fclock.Increment(conf.Stream.PollInterval)
+			Eventually(events).Should(Receive(Equal(expectedEvent(StreamEventScalingHistory, &history))))
+			Eventually(events).Should(Receive(Equal(expectedEvent(StreamEventAggregatedMetric, &metric))))
+			Expect(eventGeneratorQueries.Last().Get("aggregation")).To(Equal(models.AggregationAvg))
+
+			fclock.Increment(conf.Stream.PollInterval)
+			Consistently(events).ShouldNot(Receive())
+		})
+
+		Context("when there is a subscriber of another aggregation of the app", func() {
+			var maxEvents chan *StreamEvent
+
+			BeforeEach(func() {
+				maxEvents = eventStream.Subscribe(TEST_APP_ID, models.AggregationMax)
+				Eventually(fclock.WatcherCount).Should(Equal(2))
+			})
+
+			AfterEach(func() {
+				eventStream.Unsubscribe(TEST_APP_ID, models.AggregationMax, maxEvents)
+			})
+
+			It("polls the metrics of the aggregation for the subscriber", func() {
+				eventStream.Unsubscribe(TEST_APP_ID, models.AggregationAvg, events)
+				Eventually(fclock.WatcherCount).Should(Equal(1))
+
+				fclock.Increment(conf.Stream.PollInterval)
+				Eventually(maxEvents).Should(Receive(Equal(expectedEvent(StreamEventScalingHistory, &history))))
+				Eventually(maxEvents).Should(Receive(Equal(expectedEvent(StreamEventAggregatedMetric, &metric))))
+				Expect(eventGeneratorQueries.Last().Get("aggregation")).To(Equal(models.AggregationMax))
+			})
+		})
+
+		Context("when there is another subscriber of the app", func() {
+			var anotherEvents chan *StreamEvent
+
+			BeforeEach(func() {
+				anotherEvents = eventStream.Subscribe(TEST_APP_ID, models.AggregationAvg)
+			})
+
+			It("polls once for both subscribers", func() {
+				Consistently(fclock.WatcherCount).Should(Equal(1))
+				fclock.Increment(conf.Stream.PollInterval)
+				Eventually(events).Should(Receive(Equal(expectedEvent(StreamEventScalingHistory, &history))))
+				Eventually(anotherEvents).Should(Receive(Equal(expectedEvent(StreamEventScalingHistory, &history))))
+				Expect(policydb.GetAppPolicyCallCount()).To(Equal(1))
+
+				eventStream.Unsubscribe(TEST_APP_ID, models.AggregationAvg, anotherEvents)
+				Consistently(fclock.WatcherCount).Should(Equal(1))
+			})
+		})
+
+		Context("when the last subscriber unsubscribes", func() {
+			It("stops polling", func() {
+				eventStream.Unsubscribe(TEST_APP_ID, models.AggregationAvg, events)
+				Eventually(fclock.WatcherCount).Should(Equal(0))
+			})
+		})
+	})
+
+	Describe("StreamAppEvents", func() {
+		It("writes the events until the request is done", func() {
+			resp := httptest.NewRecorder()
+			ctx, cancel := context.WithCancel(context.Background())
+			req, err := http.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/events", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				eventStream.StreamAppEvents(resp, req.WithContext(ctx), map[string]string{"appId": TEST_APP_ID})
+				close(done)
+			}()
+
+			Eventually(fclock.WatcherCount).Should(Equal(2))
+			fclock.Increment(conf.Stream.PollInterval)
+			Consistently(done, 500*time.Millisecond).ShouldNot(BeClosed())
+			cancel()
+			Eventually(done).Should(BeClosed())
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+			historyData, _ := json.Marshal(&history)
+			metricData, _ := json.Marshal(&metric)
+			Expect(resp.Body.String()).To(Equal(
+				"event: scaling_history\ndata: " + string(historyData) + "\n\n" +
+					"event: aggregated_metric\ndata: " + string(metricData) + "\n\n"))
+			Eventually(fclock.WatcherCount).Should(Equal(0))
+		})
+
+		Context("when the aggregation is invalid", func() {
+			It("should fail with 400", func() {
+				resp := httptest.NewRecorder()
+				req, err := http.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/events?aggregation=median", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				eventStream.StreamAppEvents(resp, req, map[string]string{"appId": TEST_APP_ID})
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(ContainSubstring(`"code":"Bad Request"`))
+				Expect(fclock.WatcherCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -3,8 +3,11 @@ package publicapiserver
 import (
 	"autoscaler/cf"
 	"autoscaler/models"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"code.cloudfoundry.org/cfhttp/handlers"
 	"code.cloudfoundry.org/lager"
@@ -79,3 +82,27 @@ func (oam *OAuthMiddleware) Middleware(next http.Handler) http.Handler {
 		return
 	})
 }
+
+// userIdFromToken returns the user_id claim of the user token, or "" when the token is not a JWT. The token is not
+// verified here, so it is only to be used after the middleware accepted it.
+func userIdFromToken(userToken string) string {
+	tokenSplitted := strings.Split(userToken, " ")
+	if len(tokenSplitted) != 2 {
+		return ""
+	}
+	parts := strings.Split(tokenSplitted[1], ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ""
+	}
+	claims := struct {
+		UserId string `json:"user_id"`
+	}{}
+	if json.Unmarshal(payload, &claims) != nil {
+		return ""
+	}
+	return claims.UserId
+}
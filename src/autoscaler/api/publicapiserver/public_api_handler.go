@@ -2,28 +2,42 @@ package publicapiserver
 
 import (
 	"autoscaler/api/config"
+	"autoscaler/api/policyvalidator"
+	"autoscaler/api/schedulerutil"
 	"autoscaler/db"
 	"autoscaler/helpers"
 	"autoscaler/models"
 	"autoscaler/routes"
+	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/cfhttp/handlers"
 	"code.cloudfoundry.org/lager"
+	uuid "github.com/nu7hatch/gouuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type PublicApiHandler struct {
 	logger                 lager.Logger
 	conf                   *config.Config
 	policydb               db.PolicyDB
+	bindingdb              db.BindingDB
 	scalingEngineClient    *http.Client
 	metricsCollectorClient *http.Client
 	eventGeneratorClient   *http.Client
+	policyValidator        *policyvalidator.PolicyValidator
+	schedulerUtil          *schedulerutil.SchedulerUtil
 }
 
-func NewPublicApiHandler(logger lager.Logger, conf *config.Config, policydb db.PolicyDB) *PublicApiHandler {
+// NewPublicApiHandler creates the handler of the public API, bindingdb is nil in build-in mode.
+func NewPublicApiHandler(logger lager.Logger, conf *config.Config, policydb db.PolicyDB, bindingdb db.BindingDB) *PublicApiHandler {
 	seClient, err := helpers.CreateHTTPClient(&conf.ScalingEngine.TLSClientCerts)
 	if err != nil {
 		logger.Error("failed to create http client for ScalingEngine", err, lager.Data{"scalingengine": conf.ScalingEngine.TLSClientCerts})
@@ -43,12 +57,360 @@ func NewPublicApiHandler(logger lager.Logger, conf *config.Config, policydb db.P
 		logger:                 logger,
 		conf:                   conf,
 		policydb:               policydb,
+		bindingdb:              bindingdb,
 		scalingEngineClient:    seClient,
 		metricsCollectorClient: mcClient,
 		eventGeneratorClient:   egClient,
+		policyValidator:        policyvalidator.NewPolicyValidator(conf.PolicySchemaPath),
+		schedulerUtil:          schedulerutil.NewSchedulerUtil(conf, logger),
 	}
 }
 
+func (h *PublicApiHandler) GetScalingPolicy(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	if appId == "" {
+		h.logger.Error("AppId is missing", nil, nil)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "AppId is required",
+		})
+		return
+	}
+
+	h.logger.Info("Get Scaling Policy", lager.Data{"appId": appId})
+
+	scalingPolicy, err := h.policydb.GetAppPolicy(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling policy from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling policy"})
+		return
+	}
+
+	if scalingPolicy == nil {
+		h.logger.Info("policy doesn't exist", lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Policy Not Found"})
+		return
+	}
+
+	policyBytes, err := json.Marshal(scalingPolicy)
+	if err != nil {
+		h.logger.Error("Failed to marshal scaling policy", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling policy"})
+		return
+	}
+	h.writePolicy(w, appId, policyBytes)
+}
+
+func (h *PublicApiHandler) AttachScalingPolicy(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	if appId == "" {
+		h.logger.Error("AppId is missing", nil, nil)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "AppId is required",
+		})
+		return
+	}
+
+	h.logger.Info("Attach Scaling Policy", lager.Data{"appId": appId})
+
+	if !h.checkAppBound(w, appId) {
+		return
+	}
+
+	policyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error("Failed to read request body", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Failed to read request body"})
+		return
+	}
+	policyStr := string(policyBytes)
+
+	errResults, valid := h.policyValidator.ValidatePolicy(policyStr)
+	if !valid {
+		h.logger.Error("Failed to validate policy", nil, lager.Data{"errResults": errResults})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, errResults)
+		return
+	}
+
+	policyStr, ok := h.restorePolicySecrets(w, appId, policyStr)
+	if !ok {
+		return
+	}
+
+	if !h.savePolicy(w, r, appId, policyStr) {
+		return
+	}
+	h.writePolicy(w, appId, []byte(policyStr))
+}
+
+// checkAppBound returns whether the app is bound to the service, and writes the error response when it is not. The
+// apps are not bound in build-in mode.
+func (h *PublicApiHandler) checkAppBound(w http.ResponseWriter, appId string) bool {
+	if h.conf.UseBuildInMode {
+		return true
+	}
+	bound, err := h.bindingdb.CheckServiceBinding(appId)
+	if err != nil {
+		h.logger.Error("Failed to check service binding", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error checking service binding"})
+		return false
+	}
+	if !bound {
+		h.logger.Info("app is not bound", lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusForbidden, models.ErrorResponse{
+			Code:    "Forbidden",
+			Message: "The application is not bound to Auto-Scaling service"})
+		return false
+	}
+	return true
+}
+
+// restorePolicySecrets replaces the redacted secrets of the policy by the secrets of the current policy of the app, so
+// that a policy from GET or from a version can be saved again. It writes the error response when it fails.
+func (h *PublicApiHandler) restorePolicySecrets(w http.ResponseWriter, appId string, policyStr string) (string, bool) {
+	currentPolicy, err := h.policydb.GetAppPolicy(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling policy from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling policy"})
+		return "", false
+	}
+	restored, err := models.RestorePolicySecrets(policyStr, currentPolicy)
+	if err != nil {
+		h.logger.Error("Failed to restore policy secrets", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: err.Error()})
+		return "", false
+	}
+	return restored, true
+}
+
+// writePolicy writes the policy without its secrets.
+func (h *PublicApiHandler) writePolicy(w http.ResponseWriter, appId string, policyBytes []byte) {
+	redacted, err := models.RedactPolicy(policyBytes)
+	if err != nil {
+		h.logger.Error("Failed to redact policy", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error redacting policy"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(redacted)
+}
+
+// savePolicy saves the policy as a new version by the user of the request, and creates or updates the schedules.
+// The save is reverted when the schedules fail to be created or updated. It writes the error response when it fails.
+func (h *PublicApiHandler) savePolicy(w http.ResponseWriter, r *http.Request, appId string, policyStr string) bool {
+	previous, err := h.policydb.GetAppPolicyJson(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling policy from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling policy"})
+		return false
+	}
+
+	policyGuid, err := uuid.NewV4()
+	if err != nil {
+		h.logger.Error("Failed to generate policy guid", err, nil)
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error generating policy guid"})
+		return false
+	}
+
+	author := userIdFromToken(r.Header.Get("Authorization"))
+	err = h.policydb.SaveAppPolicy(appId, policyStr, policyGuid.String(), author)
+	if err != nil {
+		h.logger.Error("Failed to save policy", err, lager.Data{"appId": appId, "policy": models.RedactPolicyForLog(policyStr)})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error saving policy"})
+		return false
+	}
+
+	h.logger.Info("creating/updating schedules", lager.Data{"appId": appId, "policy": models.RedactPolicyForLog(policyStr)})
+	err = h.schedulerUtil.CreateOrUpdateSchedule(appId, policyStr, policyGuid.String())
+	if err != nil {
+		h.logger.Error("Failed to create/update schedule", err, lager.Data{"appId": appId})
+		revertErr := h.policydb.RevertAppPolicy(appId, policyGuid.String(), previous)
+		if revertErr != nil {
+			h.logger.Error("Failed to revert policy", revertErr, lager.Data{"appId": appId})
+		}
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error creating/updating schedules"})
+		return false
+	}
+	return true
+}
+
+func (h *PublicApiHandler) GetPolicyVersions(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Get Policy Versions", lager.Data{"appId": appId})
+
+	versions, err := h.policydb.RetrievePolicyVersions(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve policy versions from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving policy versions"})
+		return
+	}
+	// the versions saved before the secrets were redacted may still have them
+	for _, version := range versions {
+		version.Diff = models.RedactPolicyChanges(version.Diff)
+	}
+	handlers.WriteJSONResponse(w, http.StatusOK, versions)
+}
+
+func (h *PublicApiHandler) GetPolicyVersion(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Get Policy Version", lager.Data{"appId": appId, "version": vars["version"]})
+
+	policyVersion, ok := h.getPolicyVersion(w, appId, vars["version"])
+	if !ok {
+		return
+	}
+	policy, err := models.RedactPolicy(policyVersion.Policy)
+	if err != nil {
+		h.logger.Error("Failed to redact policy", err, lager.Data{"appId": appId, "version": policyVersion.Version})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error redacting policy"})
+		return
+	}
+	policyVersion.Policy = policy
+	policyVersion.Diff = models.RedactPolicyChanges(policyVersion.Diff)
+	handlers.WriteJSONResponse(w, http.StatusOK, policyVersion)
+}
+
+// RollbackPolicy saves the policy of a version as a new version, so that the rollback is in the versions as well.
+func (h *PublicApiHandler) RollbackPolicy(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Rollback Policy", lager.Data{"appId": appId, "version": vars["version"]})
+
+	if !h.checkAppBound(w, appId) {
+		return
+	}
+
+	policyVersion, ok := h.getPolicyVersion(w, appId, vars["version"])
+	if !ok {
+		return
+	}
+	policyStr := string(policyVersion.Policy)
+
+	// the schema may have changed since the version was saved
+	errResults, valid := h.policyValidator.ValidatePolicy(policyStr)
+	if !valid {
+		h.logger.Error("Failed to validate policy", nil, lager.Data{"errResults": errResults})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, errResults)
+		return
+	}
+
+	// the secrets are not kept in the versions, so they are taken from the current policy
+	policyStr, ok = h.restorePolicySecrets(w, appId, policyStr)
+	if !ok {
+		return
+	}
+
+	if !h.savePolicy(w, r, appId, policyStr) {
+		return
+	}
+	h.writePolicy(w, appId, []byte(policyStr))
+}
+
+func (h *PublicApiHandler) getPolicyVersion(w http.ResponseWriter, appId string, versionStr string) (*models.PolicyVersion, bool) {
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version <= 0 {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "Version must be a positive integer"})
+		return nil, false
+	}
+
+	policyVersion, err := h.policydb.GetPolicyVersion(appId, version)
+	if err != nil {
+		h.logger.Error("Failed to retrieve policy version from database", err, lager.Data{"appId": appId, "version": version})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving policy version"})
+		return nil, false
+	}
+	if policyVersion == nil {
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Policy Version Not Found"})
+		return nil, false
+	}
+	return policyVersion, true
+}
+
+func (h *PublicApiHandler) DetachScalingPolicy(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	if appId == "" {
+		h.logger.Error("AppId is missing", nil, nil)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "AppId is required",
+		})
+		return
+	}
+
+	scalingPolicy, err := h.policydb.GetAppPolicy(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling policy from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling policy"})
+		return
+	}
+	if scalingPolicy == nil {
+		h.logger.Info("policy doesn't exist", lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Policy Not Found"})
+		return
+	}
+
+	h.logger.Info("Deleting policy json", lager.Data{"appId": appId})
+	err = h.policydb.DeletePolicy(appId)
+	if err != nil {
+		h.logger.Error("Failed to delete policy from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error deleting policy"})
+		return
+	}
+
+	h.logger.Info("Deleting schedules", lager.Data{"appId": appId})
+	err = h.schedulerUtil.DeleteSchedule(appId)
+	if err != nil {
+		h.logger.Error("Failed to delete schedule", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error deleting schedules"})
+		return
+	}
+
+	handlers.WriteJSONResponse(w, http.StatusOK, struct{}{})
+}
+
 func (h *PublicApiHandler) GetScalingHistories(w http.ResponseWriter, r *http.Request, vars map[string]string) {
 	appId := vars["appId"]
 
@@ -105,6 +467,233 @@ func (h *PublicApiHandler) GetScalingHistories(w http.ResponseWriter, r *http.Re
 	handlers.WriteJSONResponse(w, resp.StatusCode, paginatedResponse)
 }
 
+func (h *PublicApiHandler) GetScalingHistoryExplanation(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	historyId := vars["historyId"]
+
+	h.logger.Info("Get ScalingHistoryExplanation", lager.Data{"appId": appId, "historyId": historyId})
+
+	path, _ := routes.ScalingEngineRoutes().Get(routes.GetScalingHistoryExplanationRouteName).URLPath("appid", appId, "historyid", historyId)
+
+	url := h.conf.ScalingEngine.ScalingEngineUrl + path.RequestURI()
+
+	resp, err := h.scalingEngineClient.Get(url)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling explanation from scaling engine", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling explanation from scaling engine"})
+		return
+	}
+	defer resp.Body.Close()
+
+	responseData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("Error occured during parsing scaling explanation result", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error parsing scaling explanation from scaling engine"})
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		h.logger.Error("Error occured during getting scaling explanation", nil, lager.Data{"statusCode": resp.StatusCode, "body": string(responseData)})
+	}
+
+	// the scaling engine responds with the explanation or an error response in json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	w.Write(responseData)
+}
+
+type pauseAutoscalingRequest struct {
+	Reason       string `json:"reason"`
+	DurationSecs int64  `json:"duration_secs"`
+}
+
+// PauseAutoscaling pauses the autoscaling of the app through the scaling engine, which records the pause in the
+// scaling history. The body is optional, the pause does not expire without a duration.
+func (h *PublicApiHandler) PauseAutoscaling(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Pause Autoscaling", lager.Data{"appId": appId})
+
+	pauseRequest := &pauseAutoscalingRequest{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(bytes.TrimSpace(body)) > 0 {
+		err = json.Unmarshal(body, pauseRequest)
+	}
+	if err != nil {
+		h.logger.Error("Failed to decode pause request", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "Incorrect pause request in request body"})
+		return
+	}
+	if pauseRequest.DurationSecs < 0 {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "duration_secs must not be negative"})
+		return
+	}
+
+	pause := &models.AppPause{
+		PausedBy: userIdFromToken(r.Header.Get("Authorization")),
+		Reason:   pauseRequest.Reason,
+	}
+	if pauseRequest.DurationSecs > 0 {
+		pause.ExpireAt = time.Now().Add(time.Duration(pauseRequest.DurationSecs) * time.Second).UnixNano()
+	}
+	pauseBody, err := json.Marshal(pause)
+	if err != nil {
+		h.logger.Error("Failed to marshal pause", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error pausing autoscaling"})
+		return
+	}
+
+	path, _ := routes.ScalingEngineRoutes().Get(routes.PauseAppRouteName).URLPath("appid", appId)
+	h.proxyPauseRequest(w, http.MethodPut, h.conf.ScalingEngine.ScalingEngineUrl+path.RequestURI(), pauseBody)
+}
+
+func (h *PublicApiHandler) ResumeAutoscaling(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Resume Autoscaling", lager.Data{"appId": appId})
+
+	parameters := url.Values{}
+	parameters.Set("resumed_by", userIdFromToken(r.Header.Get("Authorization")))
+	path, _ := routes.ScalingEngineRoutes().Get(routes.ResumeAppRouteName).URLPath("appid", appId)
+	h.proxyPauseRequest(w, http.MethodDelete, h.conf.ScalingEngine.ScalingEngineUrl+path.RequestURI()+"?"+parameters.Encode(), nil)
+}
+
+func (h *PublicApiHandler) proxyPauseRequest(w http.ResponseWriter, method string, url string, body []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		h.logger.Error("Failed to create pause request to scaling engine", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error changing autoscaling pause in scaling engine"})
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.scalingEngineClient.Do(req)
+	if err != nil {
+		h.logger.Error("Failed to change autoscaling pause in scaling engine", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error changing autoscaling pause in scaling engine"})
+		return
+	}
+	defer resp.Body.Close()
+
+	responseData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("Error occured during parsing pause result", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error changing autoscaling pause in scaling engine"})
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("{}"))
+		return
+	case http.StatusOK:
+	default:
+		h.logger.Error("Error occured during changing autoscaling pause", nil, lager.Data{"statusCode": resp.StatusCode, "body": string(responseData)})
+	}
+
+	// the scaling engine responds with the pause or an error response in json
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	w.Write(responseData)
+}
+
+type rotateCredentialsRequest struct {
+	OverlapSecs *int64 `json:"overlap_secs"`
+}
+
+// RotateCustomMetricsCredentials replaces the custom metrics credentials of the app with new ones, the previous
+// credentials stay valid for the overlap period so that the app can switch to the new credentials without a rebind.
+// The body is optional, the overlap period of the configuration is used without it.
+func (h *PublicApiHandler) RotateCustomMetricsCredentials(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Rotate Custom Metrics Credentials", lager.Data{"appId": appId})
+
+	rotateRequest := &rotateCredentialsRequest{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(bytes.TrimSpace(body)) > 0 {
+		err = json.Unmarshal(body, rotateRequest)
+	}
+	if err != nil {
+		h.logger.Error("Failed to decode rotate credentials request", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "Incorrect rotate credentials request in request body"})
+		return
+	}
+	overlap := h.conf.CredentialRotation.DefaultOverlap
+	if rotateRequest.OverlapSecs != nil {
+		overlap = time.Duration(*rotateRequest.OverlapSecs) * time.Second
+	}
+	if overlap < 0 || overlap > h.conf.CredentialRotation.MaxOverlap {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "overlap_secs must be between 0 and " + strconv.FormatInt(int64(h.conf.CredentialRotation.MaxOverlap/time.Second), 10)})
+		return
+	}
+
+	username, err := uuid.NewV4()
+	if err != nil {
+		h.writeRotateError(w, appId, err)
+		return
+	}
+	password, err := uuid.NewV4()
+	if err != nil {
+		h.writeRotateError(w, appId, err)
+		return
+	}
+	usernameHash, err := bcrypt.GenerateFromPassword([]byte(username.String()), bcrypt.DefaultCost)
+	if err != nil {
+		h.writeRotateError(w, appId, err)
+		return
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password.String()), bcrypt.DefaultCost)
+	if err != nil {
+		h.writeRotateError(w, appId, err)
+		return
+	}
+
+	previousExpireAt := time.Now().Add(overlap).UnixNano()
+	err = h.policydb.RotateCustomMetricsCreds(appId, string(usernameHash), string(passwordHash), previousExpireAt)
+	if err == db.ErrDoesNotExist {
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Custom Metrics Credentials Not Found"})
+		return
+	}
+	if err != nil {
+		h.writeRotateError(w, appId, err)
+		return
+	}
+
+	handlers.WriteJSONResponse(w, http.StatusOK, models.CustomMetricCredentialsRotation{
+		Username:         username.String(),
+		Password:         password.String(),
+		PreviousExpireAt: previousExpireAt,
+	})
+}
+
+func (h *PublicApiHandler) writeRotateError(w http.ResponseWriter, appId string, err error) {
+	h.logger.Error("Failed to rotate custom metrics credentials", err, lager.Data{"appId": appId})
+	handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+		Code:    "Interal-Server-Error",
+		Message: "Error rotating custom metrics credentials"})
+}
+
 func (h *PublicApiHandler) GetAggregatedMetricsHistories(w http.ResponseWriter, r *http.Request, vars map[string]string) {
 	appId := vars["appId"]
 	metricType := vars["metricType"]
@@ -128,6 +717,18 @@ func (h *PublicApiHandler) GetAggregatedMetricsHistories(w http.ResponseWriter,
 		})
 		return
 	}
+	aggregation := r.URL.Query().Get("aggregation")
+	if aggregation != "" {
+		if !models.IsValidAggregation(aggregation) {
+			h.logger.Error("Bad Request", nil, lager.Data{"appId": appId, "aggregation": aggregation})
+			handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "Bad Request",
+				Message: "aggregation must be one of " + strings.Join(models.Aggregations, ", "),
+			})
+			return
+		}
+		parameters.Add("aggregation", aggregation)
+	}
 
 	path, _ := routes.EventGeneratorRoutes().Get(routes.GetAggregatedMetricHistoriesRouteName).URLPath("appid", appId, "metrictype", metricType)
 
@@ -170,6 +771,68 @@ func (h *PublicApiHandler) GetAggregatedMetricsHistories(w http.ResponseWriter,
 	handlers.WriteJSONResponse(w, resp.StatusCode, paginatedResponse)
 }
 
+// GetAnomalies returns the anomalies recorded for the anomaly rules of an app in notify mode.
+func (h *PublicApiHandler) GetAnomalies(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+
+	h.logger.Info("Get Anomalies", lager.Data{"appId": appId})
+
+	parameters, err := parseParameter(r, vars)
+	if err != nil {
+		h.logger.Error("Bad Request", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	path, _ := routes.EventGeneratorRoutes().Get(routes.GetAnomaliesRouteName).URLPath("appid", appId)
+
+	query := url.Values{}
+	query.Add("start", parameters.Get("start-time"))
+	query.Add("end", parameters.Get("end-time"))
+	query.Add("order", parameters.Get("order-direction"))
+	url := h.conf.EventGenerator.EventGeneratorUrl + path.RequestURI() + "?" + query.Encode()
+
+	resp, err := h.eventGeneratorClient.Get(url)
+	if err != nil {
+		h.logger.Error("Failed to retrieve anomalies from eventgenerator", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving anomalies from eventgenerator"})
+		return
+	}
+	defer resp.Body.Close()
+
+	responseData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("Error occured during parsing anomalies result", err, lager.Data{"url": url})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error parsing anomalies from eventgenerator"})
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		h.logger.Error("Error occured during getting anomalies", nil, lager.Data{"statusCode": resp.StatusCode, "body": string(responseData)})
+		handlers.WriteJSONResponse(w, resp.StatusCode, models.ErrorResponse{
+			Code:    http.StatusText(resp.StatusCode),
+			Message: string(responseData)})
+		return
+	}
+
+	paginatedResponse, err := paginateResource(responseData, parameters, r)
+	if err != nil {
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: err.Error()})
+		return
+	}
+
+	handlers.WriteJSONResponse(w, resp.StatusCode, paginatedResponse)
+}
+
 func (h *PublicApiHandler) GetInstanceMetricsHistories(w http.ResponseWriter, r *http.Request, vars map[string]string) {
 	appId := vars["appId"]
 
@@ -2,20 +2,52 @@ package publicapiserver_test
 
 import (
 	. "autoscaler/api/publicapiserver"
+	"autoscaler/db"
 	"autoscaler/fakes"
 	"autoscaler/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var _ = Describe("PublicApiHandler", func() {
+	const (
+		INVALID_POLICY_STR = `{
+			"instance_max_count":4,
+			"scaling_rules":[
+			{
+				"metric_type":"memoryused",
+				"threshold":30,
+				"operator":"<",
+				"adjustment":"-1"
+			}]
+		}`
+		WEBHOOK_POLICY_STR = `{"instance_min_count":1,"instance_max_count":5,"scaling_rules":[{"metric_type":"memoryused","threshold":30,"operator":"<","adjustment":"-1"}],"webhooks":[{"url":"https://hooks.example.com/scaling","secret":"[REDACTED]"}]}`
+		VALID_POLICY_STR   = `{
+			"instance_min_count":1,
+			"instance_max_count":5,
+			"scaling_rules":[
+			{
+				"metric_type":"memoryused",
+				"threshold":30,
+				"operator":"<",
+				"adjustment":"-1"
+			}]
+		}`
+	)
 	var (
 		policydb      *fakes.FakePolicyDB
+		bindingdb     *fakes.FakeBindingDB
 		handler       *PublicApiHandler
 		resp          *httptest.ResponseRecorder
 		req           *http.Request
@@ -23,10 +55,12 @@ var _ = Describe("PublicApiHandler", func() {
 	)
 	BeforeEach(func() {
 		policydb = &fakes.FakePolicyDB{}
+		bindingdb = &fakes.FakeBindingDB{}
+		bindingdb.CheckServiceBindingReturns(true, nil)
 		resp = httptest.NewRecorder()
 
 		pathVariables = map[string]string{}
-		handler = NewPublicApiHandler(lager.NewLogger("test"), conf, policydb)
+		handler = NewPublicApiHandler(lager.NewLogger("test"), conf, policydb, bindingdb)
 	})
 
 	Describe("GetInfo", func() {
@@ -53,6 +87,826 @@ var _ = Describe("PublicApiHandler", func() {
 		})
 	})
 
+	Describe("GetScalingPolicy", func() {
+		JustBeforeEach(func() {
+			handler.GetScalingPolicy(resp, req, pathVariables)
+		})
+		Context("When appId is not present", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps//policy", nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"AppId is required"}`))
+			})
+		})
+
+		Context("When database gives error", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(nil, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error retrieving scaling policy"}`))
+			})
+		})
+
+		Context("When policy doesn't exist", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(nil, nil)
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Not Found","message":"Policy Not Found"}`))
+			})
+		})
+
+		Context("When policy exists", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(&models.ScalingPolicy{
+					InstanceMin: 1,
+					InstanceMax: 5,
+					ScalingRules: []*models.ScalingRule{{
+						MetricType: "memoryused",
+						Threshold:  30,
+						Operator:   "<",
+						Adjustment: "-1",
+					}},
+				}, nil)
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal(`{"instance_min_count":1,"instance_max_count":5,"scaling_rules":[{"metric_type":"memoryused","threshold":30,"operator":"\u003c","adjustment":"-1"}]}`))
+				Expect(policydb.GetAppPolicyArgsForCall(0)).To(Equal(TEST_APP_ID))
+			})
+		})
+
+		Context("When policy has webhooks", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(&models.ScalingPolicy{
+					InstanceMin: 1,
+					InstanceMax: 5,
+					ScalingRules: []*models.ScalingRule{{
+						MetricType: "memoryused",
+						Threshold:  30,
+						Operator:   "<",
+						Adjustment: "-1",
+					}},
+					Webhooks: []*models.Webhook{{URL: "https://hooks.example.com/scaling", Secret: "a-secret"}},
+				}, nil)
+			})
+			It("should redact the secrets", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(WEBHOOK_POLICY_STR))
+			})
+		})
+	})
+
+	Describe("AttachScalingPolicy", func() {
+		JustBeforeEach(func() {
+			handler.AttachScalingPolicy(resp, req, pathVariables)
+		})
+		Context("When appId is not present", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps//policy", strings.NewReader(VALID_POLICY_STR))
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"AppId is required"}`))
+			})
+		})
+
+		Context("When policy is invalid", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(INVALID_POLICY_STR))
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`[{"context":"(root)","description":"instance_min_count is required"}]`))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When the app is not bound to the service", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				bindingdb.CheckServiceBindingReturns(false, nil)
+			})
+			It("should fail with 403", func() {
+				Expect(resp.Code).To(Equal(http.StatusForbidden))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Forbidden","message":"The application is not bound to Auto-Scaling service"}`))
+				Expect(bindingdb.CheckServiceBindingArgsForCall(0)).To(Equal(TEST_APP_ID))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When checking the service binding errors", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				bindingdb.CheckServiceBindingReturns(false, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error checking service binding"}`))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When retrieving the current policy errors", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				policydb.GetAppPolicyJsonReturns(nil, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error retrieving scaling policy"}`))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When save policy errors", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				policydb.SaveAppPolicyReturns(errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error saving policy"}`))
+			})
+		})
+
+		Context("When scheduler returns non 200 and non 204 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				schedulerStatus = http.StatusInternalServerError
+				policydb.GetAppPolicyJsonReturns(&models.PolicyJson{AppId: TEST_APP_ID, PolicyStr: VALID_POLICY_STR, PolicyGuid: "previous-guid"}, nil)
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error creating/updating schedules"}`))
+			})
+			It("restores the previous policy", func() {
+				_, _, savedGuid, _ := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(policydb.RevertAppPolicyCallCount()).To(Equal(1))
+				appId, policyGuid, previous := policydb.RevertAppPolicyArgsForCall(0)
+				Expect(appId).To(Equal(TEST_APP_ID))
+				Expect(policyGuid).To(Equal(savedGuid))
+				Expect(previous).To(Equal(&models.PolicyJson{AppId: TEST_APP_ID, PolicyStr: VALID_POLICY_STR, PolicyGuid: "previous-guid"}))
+			})
+
+			Context("When the app did not have a policy", func() {
+				BeforeEach(func() {
+					policydb.GetAppPolicyJsonReturns(nil, nil)
+				})
+				It("removes the saved policy", func() {
+					Expect(policydb.RevertAppPolicyCallCount()).To(Equal(1))
+					_, _, previous := policydb.RevertAppPolicyArgsForCall(0)
+					Expect(previous).To(BeNil())
+				})
+			})
+		})
+
+		Context("When scheduler returns 200 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				req.Header.Set("Authorization", "bearer "+testJWT(`{"user_id":"`+TEST_USER_ID+`"}`))
+				schedulerStatus = http.StatusOK
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal(VALID_POLICY_STR))
+
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(1))
+				appId, policy, policyGuid, author := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(appId).To(Equal(TEST_APP_ID))
+				Expect(policy).To(Equal(VALID_POLICY_STR))
+				Expect(policyGuid).NotTo(BeEmpty())
+				Expect(author).To(Equal(TEST_USER_ID))
+			})
+		})
+
+		Context("When scheduler returns 204 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(VALID_POLICY_STR))
+				schedulerStatus = http.StatusNoContent
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal(VALID_POLICY_STR))
+			})
+		})
+
+		Context("When the policy has a redacted webhook secret", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(WEBHOOK_POLICY_STR))
+				schedulerStatus = http.StatusOK
+				policydb.GetAppPolicyReturns(&models.ScalingPolicy{
+					InstanceMin: 1,
+					InstanceMax: 4,
+					Webhooks:    []*models.Webhook{{URL: "https://hooks.example.com/scaling", Secret: "a-secret"}},
+				}, nil)
+			})
+			It("keeps the secret of the current policy", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(WEBHOOK_POLICY_STR))
+
+				_, policy, _, _ := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(policy).To(MatchJSON(strings.Replace(WEBHOOK_POLICY_STR, models.RedactedValue, "a-secret", 1)))
+			})
+
+			Context("When the current policy does not have the webhook", func() {
+				BeforeEach(func() {
+					policydb.GetAppPolicyReturns(nil, nil)
+				})
+				It("should fail with 400", func() {
+					Expect(resp.Code).To(Equal(http.StatusBadRequest))
+					Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"the secret of webhook https://hooks.example.com/scaling is redacted, but the current policy has no webhook with this url"}`))
+					Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("When the policy has a new webhook secret", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/policy", strings.NewReader(strings.Replace(WEBHOOK_POLICY_STR, models.RedactedValue, "a-new-secret", 1)))
+				schedulerStatus = http.StatusOK
+			})
+			It("saves the secret without returning it", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(WEBHOOK_POLICY_STR))
+
+				_, policy, _, _ := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(policy).To(ContainSubstring("a-new-secret"))
+			})
+		})
+	})
+
+	Describe("GetPolicyVersions", func() {
+		JustBeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy/versions", nil)
+			handler.GetPolicyVersions(resp, req, pathVariables)
+		})
+
+		Context("When database gives error", func() {
+			BeforeEach(func() {
+				policydb.RetrievePolicyVersionsReturns(nil, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error retrieving policy versions"}`))
+			})
+		})
+
+		Context("When there are versions", func() {
+			BeforeEach(func() {
+				policydb.RetrievePolicyVersionsReturns([]*models.PolicyVersion{{
+					AppId:      TEST_APP_ID,
+					Version:    2,
+					PolicyGuid: "a-policy-guid",
+					Author:     TEST_USER_ID,
+					CreatedAt:  300,
+					Diff:       []*models.PolicyChange{{Path: "instance_max_count", Old: 4, New: 5}},
+				}}, nil)
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`[{"app_id":"test-app-id","version":2,"policy_guid":"a-policy-guid","author":"test-user-id","created_at":300,"diff":[{"path":"instance_max_count","old":4,"new":5}]}]`))
+				Expect(policydb.RetrievePolicyVersionsArgsForCall(0)).To(Equal(TEST_APP_ID))
+			})
+		})
+	})
+
+	Describe("GetPolicyVersion", func() {
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			pathVariables["version"] = "1"
+		})
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/policy/versions/"+pathVariables["version"], nil)
+			handler.GetPolicyVersion(resp, req, pathVariables)
+		})
+
+		Context("When version is not a number", func() {
+			BeforeEach(func() {
+				pathVariables["version"] = "latest"
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"Version must be a positive integer"}`))
+			})
+		})
+
+		Context("When version doesn't exist", func() {
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Not Found","message":"Policy Version Not Found"}`))
+			})
+		})
+
+		Context("When version exists", func() {
+			BeforeEach(func() {
+				policydb.GetPolicyVersionReturns(&models.PolicyVersion{
+					AppId:      TEST_APP_ID,
+					Version:    1,
+					PolicyGuid: "a-policy-guid",
+					Diff:       []*models.PolicyChange{},
+					Policy:     []byte(VALID_POLICY_STR),
+				}, nil)
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{"app_id":"test-app-id","version":1,"policy_guid":"a-policy-guid","author":"","created_at":0,"diff":[],"policy":` + VALID_POLICY_STR + `}`))
+				appId, version := policydb.GetPolicyVersionArgsForCall(0)
+				Expect(appId).To(Equal(TEST_APP_ID))
+				Expect(version).To(Equal(1))
+			})
+		})
+
+		Context("When version was saved with a webhook secret", func() {
+			BeforeEach(func() {
+				policydb.GetPolicyVersionReturns(&models.PolicyVersion{
+					AppId:   TEST_APP_ID,
+					Version: 1,
+					Diff:    []*models.PolicyChange{{Path: "webhooks[0].secret", Old: "old-secret", New: "a-secret"}},
+					Policy:  []byte(strings.Replace(WEBHOOK_POLICY_STR, models.RedactedValue, "a-secret", 1)),
+				}, nil)
+			})
+			It("should redact the secrets", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{"app_id":"test-app-id","version":1,"policy_guid":"","author":"","created_at":0,"diff":[{"path":"webhooks[0].secret","old":"[REDACTED]","new":"[REDACTED]"}],"policy":` + WEBHOOK_POLICY_STR + `}`))
+			})
+		})
+	})
+
+	Describe("RollbackPolicy", func() {
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			pathVariables["version"] = "1"
+			policydb.GetPolicyVersionReturns(&models.PolicyVersion{
+				AppId:   TEST_APP_ID,
+				Version: 1,
+				Policy:  []byte(VALID_POLICY_STR),
+			}, nil)
+			schedulerStatus = http.StatusOK
+		})
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPost, "/v1/apps/"+TEST_APP_ID+"/policy/versions/1/rollback", nil)
+			req.Header.Set("Authorization", "bearer "+testJWT(`{"user_id":"`+TEST_USER_ID+`"}`))
+			handler.RollbackPolicy(resp, req, pathVariables)
+		})
+
+		Context("When the app is not bound to the service", func() {
+			BeforeEach(func() {
+				bindingdb.CheckServiceBindingReturns(false, nil)
+			})
+			It("should fail with 403", func() {
+				Expect(resp.Code).To(Equal(http.StatusForbidden))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Forbidden","message":"The application is not bound to Auto-Scaling service"}`))
+				Expect(policydb.GetPolicyVersionCallCount()).To(Equal(0))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When version doesn't exist", func() {
+			BeforeEach(func() {
+				policydb.GetPolicyVersionReturns(nil, nil)
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When the policy of the version is no longer valid", func() {
+			BeforeEach(func() {
+				policydb.GetPolicyVersionReturns(&models.PolicyVersion{
+					AppId:   TEST_APP_ID,
+					Version: 1,
+					Policy:  []byte(INVALID_POLICY_STR),
+				}, nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`[{"context":"(root)","description":"instance_min_count is required"}]`))
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When save policy errors", func() {
+			BeforeEach(func() {
+				policydb.SaveAppPolicyReturns(errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error saving policy"}`))
+			})
+		})
+
+		Context("When scheduler returns non 200 and non 204 status code", func() {
+			BeforeEach(func() {
+				schedulerStatus = http.StatusInternalServerError
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error creating/updating schedules"}`))
+			})
+			It("restores the previous policy", func() {
+				Expect(policydb.RevertAppPolicyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("When scheduler returns 200 status code", func() {
+			It("saves the policy of the version as a new version", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal(VALID_POLICY_STR))
+
+				Expect(policydb.SaveAppPolicyCallCount()).To(Equal(1))
+				appId, policy, policyGuid, author := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(appId).To(Equal(TEST_APP_ID))
+				Expect(policy).To(Equal(VALID_POLICY_STR))
+				Expect(policyGuid).NotTo(BeEmpty())
+				Expect(author).To(Equal(TEST_USER_ID))
+			})
+		})
+
+		Context("When the version has a redacted webhook secret", func() {
+			BeforeEach(func() {
+				policydb.GetPolicyVersionReturns(&models.PolicyVersion{
+					AppId:   TEST_APP_ID,
+					Version: 1,
+					Policy:  []byte(WEBHOOK_POLICY_STR),
+				}, nil)
+				policydb.GetAppPolicyReturns(&models.ScalingPolicy{
+					InstanceMin: 1,
+					InstanceMax: 4,
+					Webhooks:    []*models.Webhook{{URL: "https://hooks.example.com/scaling", Secret: "a-secret"}},
+				}, nil)
+			})
+			It("keeps the secret of the current policy", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(WEBHOOK_POLICY_STR))
+
+				_, policy, _, _ := policydb.SaveAppPolicyArgsForCall(0)
+				Expect(policy).To(MatchJSON(strings.Replace(WEBHOOK_POLICY_STR, models.RedactedValue, "a-secret", 1)))
+			})
+		})
+	})
+
+	Describe("DetachScalingPolicy", func() {
+		BeforeEach(func() {
+			policydb.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 5}, nil)
+		})
+		JustBeforeEach(func() {
+			handler.DetachScalingPolicy(resp, req, pathVariables)
+		})
+		Context("When appId is not present", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps//policy", nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"AppId is required"}`))
+			})
+		})
+
+		Context("When the policy doesn't exist", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(nil, nil)
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Not Found","message":"Policy Not Found"}`))
+				Expect(policydb.DeletePolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When retrieving the policy errors", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.GetAppPolicyReturns(nil, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error retrieving scaling policy"}`))
+				Expect(policydb.DeletePolicyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When delete policy errors", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				policydb.DeletePolicyReturns(errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error deleting policy"}`))
+			})
+		})
+
+		Context("When scheduler returns non 200, non 204 and non 404 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				schedulerStatus = http.StatusInternalServerError
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error deleting schedules"}`))
+			})
+		})
+
+		Context("When scheduler returns 404 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				schedulerStatus = http.StatusNotFound
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal("{}"))
+			})
+		})
+
+		Context("When scheduler returns 200 status code", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/policy", nil)
+				schedulerStatus = http.StatusOK
+			})
+			It("should succeed with 200", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal("{}"))
+				Expect(policydb.DeletePolicyArgsForCall(0)).To(Equal(TEST_APP_ID))
+			})
+		})
+	})
+
+	Describe("GetScalingHistoryExplanation", func() {
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			pathVariables["historyId"] = "123"
+			req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/scaling_histories/123/explanation", nil)
+		})
+		JustBeforeEach(func() {
+			handler.GetScalingHistoryExplanation(resp, req, pathVariables)
+		})
+
+		Context("When the scaling engine returns the explanation", func() {
+			BeforeEach(func() {
+				scalingEngineExplanationStatus = http.StatusOK
+				scalingEngineExplanationResponse = &models.ScalingExplanation{
+					Trigger:           &models.Trigger{AppId: TEST_APP_ID, MetricType: "memoryused", Operator: ">", Threshold: 80, Adjustment: "+1"},
+					Comparisons:       []*models.MetricComparison{},
+					CurrentInstances:  2,
+					ComputedInstances: 3,
+					InstanceMin:       1,
+					InstanceMax:       5,
+					NewInstances:      3,
+				}
+			})
+			It("should succeed with the explanation", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{"trigger":{"app_id":"` + TEST_APP_ID + `","metric_type":"memoryused","metric_unit":"","breach_duration_secs":0,"cool_down_secs":0,"threshold":80,"operator":">","adjustment":"+1"},"comparisons":[],"current_instances":2,"computed_instances":3,"instance_min":1,"instance_max":5,"new_instances":3}`))
+			})
+		})
+
+		Context("When the scaling engine does not find the explanation", func() {
+			BeforeEach(func() {
+				scalingEngineExplanationStatus = http.StatusNotFound
+				scalingEngineExplanationResponse = models.ErrorResponse{Code: "Not-Found", Message: "Scaling explanation not found"}
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(MatchJSON(`{"code":"Not-Found","message":"Scaling explanation not found"}`))
+			})
+		})
+	})
+
+	Describe("PauseAutoscaling", func() {
+		var body string
+
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			body = `{"reason":"database migration","duration_secs":3600}`
+			scalingEnginePauseStatus = http.StatusOK
+			scalingEnginePauseResponse = &models.AppPause{AppId: TEST_APP_ID, PausedBy: TEST_USER_ID, Reason: "database migration", PausedAt: 100, ExpireAt: 200}
+		})
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPost, "/v1/apps/"+TEST_APP_ID+"/autoscaling:pause", strings.NewReader(body))
+			req.Header.Set("Authorization", "bearer "+testJWT(`{"user_id":"`+TEST_USER_ID+`"}`))
+			handler.PauseAutoscaling(resp, req, pathVariables)
+		})
+
+		Context("When the scaling engine pauses the app", func() {
+			It("should succeed with the pause", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{"app_id":"` + TEST_APP_ID + `","paused_by":"` + TEST_USER_ID + `","reason":"database migration","paused_at":100,"expire_at":200}`))
+
+				Expect(scalingEnginePauseRequest.Method).To(Equal(http.MethodPut))
+				Expect(scalingEnginePauseRequest.URL.Path).To(Equal("/v1/apps/" + TEST_APP_ID + "/pause"))
+				pause := &models.AppPause{}
+				Expect(json.Unmarshal(scalingEnginePauseBody, pause)).To(Succeed())
+				Expect(pause.PausedBy).To(Equal(TEST_USER_ID))
+				Expect(pause.Reason).To(Equal("database migration"))
+				Expect(pause.ExpireAt).To(BeNumerically("~", time.Now().Add(time.Hour).UnixNano(), int64(time.Minute)))
+			})
+		})
+
+		Context("When the request has no body", func() {
+			BeforeEach(func() {
+				body = ""
+			})
+			It("should pause the app without expiry", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				pause := &models.AppPause{}
+				Expect(json.Unmarshal(scalingEnginePauseBody, pause)).To(Succeed())
+				Expect(pause).To(Equal(&models.AppPause{PausedBy: TEST_USER_ID}))
+			})
+		})
+
+		Context("When the body is invalid", func() {
+			BeforeEach(func() {
+				body = `{"duration_secs":"an hour"}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"Incorrect pause request in request body"}`))
+			})
+		})
+
+		Context("When the duration is negative", func() {
+			BeforeEach(func() {
+				body = `{"duration_secs":-1}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"duration_secs must not be negative"}`))
+			})
+		})
+
+		Context("When the scaling engine fails to pause the app", func() {
+			BeforeEach(func() {
+				scalingEnginePauseStatus = http.StatusInternalServerError
+				scalingEnginePauseResponse = models.ErrorResponse{Code: "Interal-Server-Error", Message: "Error pausing app"}
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(MatchJSON(`{"code":"Interal-Server-Error","message":"Error pausing app"}`))
+			})
+		})
+	})
+
+	Describe("ResumeAutoscaling", func() {
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			req = httptest.NewRequest(http.MethodPost, "/v1/apps/"+TEST_APP_ID+"/autoscaling:resume", nil)
+			req.Header.Set("Authorization", "bearer "+testJWT(`{"user_id":"`+TEST_USER_ID+`"}`))
+			scalingEnginePauseStatus = http.StatusNoContent
+			scalingEngineResumeBody = ""
+		})
+		JustBeforeEach(func() {
+			handler.ResumeAutoscaling(resp, req, pathVariables)
+		})
+
+		Context("When the scaling engine resumes the app", func() {
+			It("should succeed", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal("{}"))
+
+				Expect(scalingEnginePauseRequest.Method).To(Equal(http.MethodDelete))
+				Expect(scalingEnginePauseRequest.URL.Path).To(Equal("/v1/apps/" + TEST_APP_ID + "/pause"))
+				Expect(scalingEnginePauseRequest.URL.Query().Get("resumed_by")).To(Equal(TEST_USER_ID))
+			})
+		})
+
+		Context("When the app is not paused", func() {
+			BeforeEach(func() {
+				scalingEnginePauseStatus = http.StatusNotFound
+				scalingEngineResumeBody = `{"code":"Not-Found","message":"App is not paused"}`
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(MatchJSON(`{"code":"Not-Found","message":"App is not paused"}`))
+			})
+		})
+	})
+
+	Describe("RotateCustomMetricsCredentials", func() {
+		var body string
+
+		BeforeEach(func() {
+			pathVariables["appId"] = TEST_APP_ID
+			body = ""
+		})
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPost, "/v1/apps/"+TEST_APP_ID+"/custom_metrics_credentials:rotate", strings.NewReader(body))
+			handler.RotateCustomMetricsCredentials(resp, req, pathVariables)
+		})
+
+		Context("When the request has no body", func() {
+			It("should rotate the credentials with the default overlap", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				rotation := &models.CustomMetricCredentialsRotation{}
+				Expect(json.Unmarshal(resp.Body.Bytes(), rotation)).To(Succeed())
+				Expect(rotation.Username).NotTo(BeEmpty())
+				Expect(rotation.Password).NotTo(BeEmpty())
+				Expect(rotation.PreviousExpireAt).To(BeNumerically("~", time.Now().Add(24*time.Hour).UnixNano(), int64(time.Minute)))
+
+				Expect(policydb.RotateCustomMetricsCredsCallCount()).To(Equal(1))
+				appId, usernameHash, passwordHash, previousExpireAt := policydb.RotateCustomMetricsCredsArgsForCall(0)
+				Expect(appId).To(Equal(TEST_APP_ID))
+				Expect(bcrypt.CompareHashAndPassword([]byte(usernameHash), []byte(rotation.Username))).To(Succeed())
+				Expect(bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(rotation.Password))).To(Succeed())
+				Expect(previousExpireAt).To(Equal(rotation.PreviousExpireAt))
+			})
+		})
+
+		Context("When the overlap is given", func() {
+			BeforeEach(func() {
+				body = `{"overlap_secs":0}`
+			})
+			It("should expire the previous credentials after the overlap", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				_, _, _, previousExpireAt := policydb.RotateCustomMetricsCredsArgsForCall(0)
+				Expect(previousExpireAt).To(BeNumerically("~", time.Now().UnixNano(), int64(time.Minute)))
+			})
+		})
+
+		Context("When the body is invalid", func() {
+			BeforeEach(func() {
+				body = `{"overlap_secs":"a day"}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"Incorrect rotate credentials request in request body"}`))
+				Expect(policydb.RotateCustomMetricsCredsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("When the overlap is negative", func() {
+			BeforeEach(func() {
+				body = `{"overlap_secs":-1}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"overlap_secs must be between 0 and 2592000"}`))
+			})
+		})
+
+		Context("When the overlap exceeds the maximum", func() {
+			BeforeEach(func() {
+				body = `{"overlap_secs":2592001}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(policydb.RotateCustomMetricsCredsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("When the app has no credentials", func() {
+			BeforeEach(func() {
+				policydb.RotateCustomMetricsCredsReturns(db.ErrDoesNotExist)
+			})
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Not Found","message":"Custom Metrics Credentials Not Found"}`))
+			})
+		})
+
+		Context("When the database fails", func() {
+			BeforeEach(func() {
+				policydb.RotateCustomMetricsCredsReturns(errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error rotating custom metrics credentials"}`))
+			})
+		})
+	})
+
 	Describe("GetScalingHistories", func() {
 		JustBeforeEach(func() {
 			scalingEngineResponse = []models.AppScalingHistory{
@@ -432,7 +1286,7 @@ var _ = Describe("PublicApiHandler", func() {
 					CollectedAt:   0,
 					Name:          TEST_METRIC_TYPE,
 					Unit:          TEST_METRIC_UNIT,
-					Value:         "200",
+					Value:         200,
 				},
 				{
 					AppId:         TEST_APP_ID,
@@ -441,7 +1295,7 @@ var _ = Describe("PublicApiHandler", func() {
 					CollectedAt:   1,
 					Name:          TEST_METRIC_TYPE,
 					Unit:          TEST_METRIC_UNIT,
-					Value:         "250",
+					Value:         250,
 				},
 				{
 					AppId:         TEST_APP_ID,
@@ -450,7 +1304,7 @@ var _ = Describe("PublicApiHandler", func() {
 					CollectedAt:   0,
 					Name:          TEST_METRIC_TYPE,
 					Unit:          TEST_METRIC_UNIT,
-					Value:         "250",
+					Value:         250,
 				},
 				{
 					AppId:         TEST_APP_ID,
@@ -459,7 +1313,7 @@ var _ = Describe("PublicApiHandler", func() {
 					CollectedAt:   1,
 					Name:          TEST_METRIC_TYPE,
 					Unit:          TEST_METRIC_UNIT,
-					Value:         "200",
+					Value:         200,
 				},
 				{
 					AppId:         TEST_APP_ID,
@@ -468,7 +1322,7 @@ var _ = Describe("PublicApiHandler", func() {
 					CollectedAt:   0,
 					Name:          TEST_METRIC_TYPE,
 					Unit:          TEST_METRIC_UNIT,
-					Value:         "200",
+					Value:         200,
 				},
 			}
 			handler.GetInstanceMetricsHistories(resp, req, pathVariables)
@@ -750,7 +1604,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get full page", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":1,"prev_url":"","next_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":100,"unit":"test_unit","value":"200"},{"app_id":"test-app-id","collected_at":1,"instance_index":1,"name":"test_metric","timestamp":110,"unit":"test_unit","value":"250"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":1,"prev_url":"","next_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":100,"unit":"test_unit","value":200},{"app_id":"test-app-id","collected_at":1,"instance_index":1,"name":"test_metric","timestamp":110,"unit":"test_unit","value":250}]}`))
 			})
 		})
 		Context("when getting 2nd page", func() {
@@ -770,7 +1624,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get full page", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":2,"prev_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=1\u0026results-per-page=2\u0026start-time=100","next_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=3\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":150,"unit":"test_unit","value":"250"},{"app_id":"test-app-id","collected_at":1,"instance_index":1,"name":"test_metric","timestamp":170,"unit":"test_unit","value":"200"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":2,"prev_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=1\u0026results-per-page=2\u0026start-time=100","next_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=3\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":150,"unit":"test_unit","value":250},{"app_id":"test-app-id","collected_at":1,"instance_index":1,"name":"test_metric","timestamp":170,"unit":"test_unit","value":200}]}`))
 			})
 		})
 
@@ -791,7 +1645,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get only one record", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":3,"prev_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","next_url":"","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":120,"unit":"test_unit","value":"200"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":3,"prev_url":"/v1/apps/test-app-id/metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","next_url":"","resources":[{"app_id":"test-app-id","collected_at":0,"instance_index":0,"name":"test_metric","timestamp":120,"unit":"test_unit","value":200}]}`))
 			})
 		})
 
@@ -822,39 +1676,44 @@ var _ = Describe("PublicApiHandler", func() {
 		JustBeforeEach(func() {
 			eventGeneratorResponse = []models.AppMetric{
 				{
-					AppId:      TEST_APP_ID,
-					Timestamp:  100,
-					MetricType: TEST_METRIC_TYPE,
-					Unit:       TEST_METRIC_UNIT,
-					Value:      "200",
+					AppId:       TEST_APP_ID,
+					Timestamp:   100,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Unit:        TEST_METRIC_UNIT,
+					Value:       200,
 				},
 				{
-					AppId:      TEST_APP_ID,
-					Timestamp:  110,
-					MetricType: TEST_METRIC_TYPE,
-					Unit:       TEST_METRIC_UNIT,
-					Value:      "250",
+					AppId:       TEST_APP_ID,
+					Timestamp:   110,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Unit:        TEST_METRIC_UNIT,
+					Value:       250,
 				},
 				{
-					AppId:      TEST_APP_ID,
-					Timestamp:  150,
-					MetricType: TEST_METRIC_TYPE,
-					Unit:       TEST_METRIC_UNIT,
-					Value:      "250",
+					AppId:       TEST_APP_ID,
+					Timestamp:   150,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Unit:        TEST_METRIC_UNIT,
+					Value:       250,
 				},
 				{
-					AppId:      TEST_APP_ID,
-					Timestamp:  170,
-					MetricType: TEST_METRIC_TYPE,
-					Unit:       TEST_METRIC_UNIT,
-					Value:      "200",
+					AppId:       TEST_APP_ID,
+					Timestamp:   170,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Unit:        TEST_METRIC_UNIT,
+					Value:       200,
 				},
 				{
-					AppId:      TEST_APP_ID,
-					Timestamp:  200,
-					MetricType: TEST_METRIC_TYPE,
-					Unit:       TEST_METRIC_UNIT,
-					Value:      "200",
+					AppId:       TEST_APP_ID,
+					Timestamp:   200,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Unit:        TEST_METRIC_UNIT,
+					Value:       200,
 				},
 			}
 			handler.GetAggregatedMetricsHistories(resp, req, pathVariables)
@@ -1136,7 +1995,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get full page", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":1,"prev_url":"","next_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","name":"test_metric","timestamp":100,"unit":"test_unit","value":"200"},{"app_id":"test-app-id","name":"test_metric","timestamp":110,"unit":"test_unit","value":"250"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":1,"prev_url":"","next_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","resources":[{"aggregation":"avg","app_id":"test-app-id","name":"test_metric","timestamp":100,"unit":"test_unit","value":200},{"aggregation":"avg","app_id":"test-app-id","name":"test_metric","timestamp":110,"unit":"test_unit","value":250}]}`))
 			})
 		})
 		Context("when getting 2nd page", func() {
@@ -1156,7 +2015,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get full page", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":2,"prev_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=1\u0026results-per-page=2\u0026start-time=100","next_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=3\u0026results-per-page=2\u0026start-time=100","resources":[{"app_id":"test-app-id","name":"test_metric","timestamp":150,"unit":"test_unit","value":"250"},{"app_id":"test-app-id","name":"test_metric","timestamp":170,"unit":"test_unit","value":"200"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":2,"prev_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=1\u0026results-per-page=2\u0026start-time=100","next_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=3\u0026results-per-page=2\u0026start-time=100","resources":[{"aggregation":"avg","app_id":"test-app-id","name":"test_metric","timestamp":150,"unit":"test_unit","value":250},{"aggregation":"avg","app_id":"test-app-id","name":"test_metric","timestamp":170,"unit":"test_unit","value":200}]}`))
 			})
 		})
 
@@ -1177,7 +2036,7 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 			It("should get only one record", func() {
 				Expect(resp.Code).To(Equal(http.StatusOK))
-				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":3,"prev_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","next_url":"","resources":[{"app_id":"test-app-id","name":"test_metric","timestamp":200,"unit":"test_unit","value":"200"}]}`))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":5,"total_pages":3,"page":3,"prev_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=2\u0026start-time=100","next_url":"","resources":[{"aggregation":"avg","app_id":"test-app-id","name":"test_metric","timestamp":200,"unit":"test_unit","value":200}]}`))
 			})
 		})
 
@@ -1202,5 +2061,137 @@ var _ = Describe("PublicApiHandler", func() {
 			})
 		})
 
+		Context("when aggregation is given", func() {
+			BeforeEach(func() {
+				eventGeneratorStatus = http.StatusOK
+				pathVariables["appId"] = TEST_APP_ID
+				pathVariables["metricType"] = TEST_METRIC_TYPE
+
+				params := url.Values{}
+				params.Add("start-time", "100")
+				params.Add("end-time", "300")
+				params.Add("page", "1")
+				params.Add("order-direction", "desc")
+				params.Add("results-per-page", "2")
+				params.Add("aggregation", "avg")
+
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/aggregated_metric_histories/"+TEST_METRIC_TYPE+"?"+params.Encode(), nil)
+			})
+			It("should query the eventgenerator with the aggregation", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				requests := eventGeneratorServer.ReceivedRequests()
+				Expect(requests[len(requests)-1].URL.Query().Get("aggregation")).To(Equal("avg"))
+				Expect(resp.Body.String()).To(ContainSubstring(`"next_url":"/v1/apps/test-app-id/aggregated_metric_histories/test_metric?aggregation=avg\u0026end-time=300`))
+			})
+		})
+
+		Context("when aggregation is invalid", func() {
+			BeforeEach(func() {
+				pathVariables["appId"] = TEST_APP_ID
+				pathVariables["metricType"] = TEST_METRIC_TYPE
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/aggregated_metric_histories/"+TEST_METRIC_TYPE+"?aggregation=median", nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"aggregation must be one of avg, max, min, sum, p50, p90, p99"}`))
+			})
+		})
+
+	})
+
+	Describe("GetAnomalies", func() {
+		BeforeEach(func() {
+			anomaliesResponse = []models.AnomalyScore{
+				{
+					AppId:       TEST_APP_ID,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Value:       500,
+					Unit:        TEST_METRIC_UNIT,
+					Baseline:    200,
+					StdDev:      50,
+					Score:       6,
+					Timestamp:   200,
+				},
+				{
+					AppId:       TEST_APP_ID,
+					MetricType:  TEST_METRIC_TYPE,
+					Aggregation: models.AggregationAvg,
+					Value:       450,
+					Unit:        TEST_METRIC_UNIT,
+					Baseline:    200,
+					StdDev:      50,
+					Score:       5,
+					Timestamp:   100,
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			handler.GetAnomalies(resp, req, pathVariables)
+		})
+
+		Context("When appId is not present", func() {
+			BeforeEach(func() {
+				anomaliesStatus = http.StatusOK
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/anomalies", nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"appId is required"}`))
+			})
+		})
+
+		Context("When start-time is not integer", func() {
+			BeforeEach(func() {
+				anomaliesStatus = http.StatusOK
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/anomalies?start-time=not-integer", nil)
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"start-time must be an integer"}`))
+			})
+		})
+
+		Context("when eventgenerator returns 500", func() {
+			BeforeEach(func() {
+				anomaliesStatus = http.StatusInternalServerError
+				pathVariables["appId"] = TEST_APP_ID
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/anomalies", nil)
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when getting 1st page", func() {
+			BeforeEach(func() {
+				anomaliesStatus = http.StatusOK
+				pathVariables["appId"] = TEST_APP_ID
+
+				params := url.Values{}
+				params.Add("start-time", "100")
+				params.Add("end-time", "300")
+				params.Add("page", "1")
+				params.Add("order-direction", "desc")
+				params.Add("results-per-page", "1")
+
+				req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/anomalies?"+params.Encode(), nil)
+			})
+			It("should query the eventgenerator and return the recorded anomalies", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				query := eventGeneratorQueries.Last()
+				Expect(query.Get("start")).To(Equal("100"))
+				Expect(query.Get("end")).To(Equal("300"))
+				Expect(query.Get("order")).To(Equal("DESC"))
+				Expect(resp.Body.String()).To(Equal(`{"total_results":2,"total_pages":2,"page":1,"prev_url":"","next_url":"/v1/apps/test-app-id/anomalies?end-time=300\u0026order-direction=DESC\u0026page=2\u0026results-per-page=1\u0026start-time=100","resources":[{"aggregation":"avg","app_id":"test-app-id","baseline":200,"name":"test_metric","score":6,"std_dev":50,"timestamp":200,"unit":"test_unit","value":500}]}`))
+			})
+		})
 	})
 })
+
+func testJWT(claims string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+}
@@ -9,6 +9,7 @@ import (
 	"autoscaler/db"
 	"autoscaler/routes"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/gorilla/mux"
 	"github.com/tedsuo/ifrit"
@@ -22,9 +23,11 @@ func (vh VarsFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vh(w, r, vars)
 }
 
-func NewPublicApiServer(logger lager.Logger, conf *config.Config, policydb db.PolicyDB, cfclient cf.CFClient) (ifrit.Runner, error) {
+func NewPublicApiServer(logger lager.Logger, conf *config.Config, policydb db.PolicyDB, bindingdb db.BindingDB, cfclient cf.CFClient, clock clock.Clock) (ifrit.Runner, error) {
 
-	pah := NewPublicApiHandler(logger, conf, policydb)
+	pah := NewPublicApiHandler(logger, conf, policydb, bindingdb)
+	es := NewEventStream(logger, conf, policydb, clock)
+	sgh := NewScalingGroupHandler(logger, policydb, cfclient)
 	oam := NewOauthMiddleware(logger, cfclient)
 
 	r := routes.PublicApiRoutes()
@@ -34,8 +37,23 @@ func NewPublicApiServer(logger lager.Logger, conf *config.Config, policydb db.Po
 	rp := routes.PublicApiProtectedRoutes()
 	rp.Use(oam.Middleware)
 	rp.Get(routes.PublicApiScalingHistoryRouteName).Handler(VarsFunc(pah.GetScalingHistories))
+	rp.Get(routes.PublicApiScalingHistoryExplanationRouteName).Handler(VarsFunc(pah.GetScalingHistoryExplanation))
 	rp.Get(routes.PublicApiMetricsHistoryRouteName).Handler(VarsFunc(pah.GetInstanceMetricsHistories))
 	rp.Get(routes.PublicApiAggregatedMetricsHistoryRouteName).Handler(VarsFunc(pah.GetAggregatedMetricsHistories))
+	rp.Get(routes.PublicApiAnomaliesRouteName).Handler(VarsFunc(pah.GetAnomalies))
+	rp.Get(routes.PublicApiGetPolicyRouteName).Handler(VarsFunc(pah.GetScalingPolicy))
+	rp.Get(routes.PublicApiAttachPolicyRouteName).Handler(VarsFunc(pah.AttachScalingPolicy))
+	rp.Get(routes.PublicApiDetachPolicyRouteName).Handler(VarsFunc(pah.DetachScalingPolicy))
+	rp.Get(routes.PublicApiGetPolicyVersionsRouteName).Handler(VarsFunc(pah.GetPolicyVersions))
+	rp.Get(routes.PublicApiGetPolicyVersionRouteName).Handler(VarsFunc(pah.GetPolicyVersion))
+	rp.Get(routes.PublicApiRollbackPolicyRouteName).Handler(VarsFunc(pah.RollbackPolicy))
+	rp.Get(routes.PublicApiEventStreamRouteName).Handler(VarsFunc(es.StreamAppEvents))
+	rp.Get(routes.PublicApiGetScalingGroupRouteName).Handler(VarsFunc(sgh.GetScalingGroup))
+	rp.Get(routes.PublicApiSaveScalingGroupRouteName).Handler(VarsFunc(sgh.SaveScalingGroup))
+	rp.Get(routes.PublicApiDeleteScalingGroupRouteName).Handler(VarsFunc(sgh.DeleteScalingGroup))
+	rp.Get(routes.PublicApiPauseAutoscalingRouteName).Handler(VarsFunc(pah.PauseAutoscaling))
+	rp.Get(routes.PublicApiResumeAutoscalingRouteName).Handler(VarsFunc(pah.ResumeAutoscaling))
+	rp.Get(routes.PublicApiRotateCustomMetricsCredentialsRouteName).Handler(VarsFunc(pah.RotateCustomMetricsCredentials))
 
 	addr := fmt.Sprintf("0.0.0.0:%d", conf.PublicApiServer.Port)
 
@@ -5,6 +5,7 @@ import (
 
 	// . "autoscaler/api/publicapiserver"
 	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -40,7 +41,7 @@ var _ = Describe("PublicApiServer", func() {
 				CollectedAt:   0,
 				Name:          TEST_METRIC_TYPE,
 				Unit:          TEST_METRIC_UNIT,
-				Value:         "200",
+				Value:         200,
 			},
 		}
 
@@ -50,7 +51,7 @@ var _ = Describe("PublicApiServer", func() {
 				Timestamp:  100,
 				MetricType: TEST_METRIC_TYPE,
 				Unit:       TEST_METRIC_UNIT,
-				Value:      "200",
+				Value:      200,
 			},
 		}
 	})
@@ -58,6 +59,62 @@ var _ = Describe("PublicApiServer", func() {
 	Describe("Protected Routes", func() {
 
 		Describe("Without AuthorizatioToken", func() {
+			Context("when calling get policy endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodGet, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					rsp, err = httpClient.Do(req)
+				})
+				It("should fail", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("when calling events endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/events"
+
+					req, err := http.NewRequest(http.MethodGet, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					rsp, err = httpClient.Do(req)
+				})
+				It("should fail", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("when calling put policy endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodPut, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					rsp, err = httpClient.Do(req)
+				})
+				It("should fail", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("when calling delete policy endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodDelete, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					rsp, err = httpClient.Do(req)
+				})
+				It("should fail", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
 			Context("when calling scaling_histories endpoint", func() {
 				BeforeEach(func() {
 					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/scaling_histories"
@@ -167,6 +224,62 @@ var _ = Describe("PublicApiServer", func() {
 				fakeCFClient.IsUserSpaceDeveloperReturns(true, nil)
 			})
 
+			Context("when calling get policy endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodGet, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Add("Authorization", TEST_USER_TOKEN)
+
+					rsp, err = httpClient.Do(req)
+				})
+				It("should get 404 when policy doesn't exist", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when calling attach policy endpoint", func() {
+				BeforeEach(func() {
+					schedulerStatus = http.StatusOK
+
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodPut, serverUrl.String(), strings.NewReader(`{"instance_min_count":1,"instance_max_count":5,"scaling_rules":[{"metric_type":"memoryused","threshold":30,"operator":"<","adjustment":"-1"}]}`))
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Add("Authorization", TEST_USER_TOKEN)
+
+					rsp, err = httpClient.Do(req)
+				})
+				It("should succeed", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when calling detach policy endpoint", func() {
+				BeforeEach(func() {
+					schedulerStatus = http.StatusOK
+					fakePolicyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 5}, nil)
+
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/policy"
+
+					req, err := http.NewRequest(http.MethodDelete, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Add("Authorization", TEST_USER_TOKEN)
+
+					rsp, err = httpClient.Do(req)
+				})
+				AfterEach(func() {
+					fakePolicyDB.GetAppPolicyReturns(nil, nil)
+				})
+				It("should succeed", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
 			Context("when calling scaling_histories endpoint", func() {
 				BeforeEach(func() {
 					scalingEngineStatus = http.StatusOK
@@ -220,6 +333,26 @@ var _ = Describe("PublicApiServer", func() {
 					Expect(rsp.StatusCode).To(Equal(http.StatusOK))
 				})
 			})
+
+			Context("when calling events endpoint", func() {
+				BeforeEach(func() {
+					serverUrl.Path = "/v1/apps/" + TEST_APP_ID + "/events"
+
+					req, err := http.NewRequest(http.MethodGet, serverUrl.String(), nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Add("Authorization", TEST_USER_TOKEN)
+
+					rsp, err = httpClient.Do(req)
+				})
+				AfterEach(func() {
+					rsp.Body.Close()
+				})
+				It("should open the event stream", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+					Expect(rsp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+				})
+			})
 		})
 	})
 	Describe("UnProtected Routes", func() {
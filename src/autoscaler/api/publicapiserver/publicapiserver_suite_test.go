@@ -13,9 +13,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/onsi/gomega/ghttp"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -47,16 +50,31 @@ var (
 	scalingEngineServer    *ghttp.Server
 	metricsCollectorServer *ghttp.Server
 	eventGeneratorServer   *ghttp.Server
+	schedulerServer        *ghttp.Server
 
 	scalingEngineStatus    int
 	metricsCollectorStatus int
 	eventGeneratorStatus   int
+	schedulerStatus        int
+
+	scalingEngineExplanationStatus   int
+	scalingEngineExplanationResponse interface{}
+
+	scalingEnginePauseStatus   int
+	scalingEnginePauseResponse interface{}
+	scalingEngineResumeBody    string
+	scalingEnginePauseRequest  *http.Request
+	scalingEnginePauseBody     []byte
 
 	scalingEngineResponse    []models.AppScalingHistory
 	metricsCollectorResponse []models.AppInstanceMetric
 	eventGeneratorResponse   []models.AppMetric
+	anomaliesStatus          int
+	anomaliesResponse        []models.AnomalyScore
+	eventGeneratorQueries    *recordedQueries
 
 	fakeCFClient *fakes.FakeCFClient
+	fakePolicyDB *fakes.FakePolicyDB
 )
 
 func TestPublicapiserver(t *testing.T) {
@@ -68,6 +86,7 @@ var _ = BeforeSuite(func() {
 	scalingEngineServer = ghttp.NewServer()
 	metricsCollectorServer = ghttp.NewServer()
 	eventGeneratorServer = ghttp.NewServer()
+	schedulerServer = ghttp.NewServer()
 
 	testCertDir := "../../../../test-certs"
 	apiPort := 11000 + GinkgoParallelNode()
@@ -78,7 +97,11 @@ var _ = BeforeSuite(func() {
 		PublicApiServer: config.ServerConfig{
 			Port: apiPort,
 		},
-		InfoFilePath: "../exampleconfig/info-file.json",
+		InfoFilePath:     "../exampleconfig/info-file.json",
+		PolicySchemaPath: "../policyvalidator/policy_json.schema.json",
+		Scheduler: config.SchedulerConfig{
+			SchedulerURL: schedulerServer.URL(),
+		},
 		MetricsCollector: config.MetricsCollectorConfig{
 			MetricsCollectorUrl: metricsCollectorServer.URL(),
 			TLSClientCerts: models.TLSCerts{
@@ -110,12 +133,23 @@ var _ = BeforeSuite(func() {
 			Secret:            CLIENT_SECRET,
 			SkipSSLValidation: true,
 		},
+		Stream: config.StreamConfig{
+			PollInterval:         time.Second,
+			HeartbeatInterval:    10 * time.Second,
+			SubscriberBufferSize: 10,
+		},
+		CredentialRotation: config.CredentialRotationConfig{
+			DefaultOverlap: 24 * time.Hour,
+			MaxOverlap:     30 * 24 * time.Hour,
+		},
 	}
 
-	fakePolicyDB := &fakes.FakePolicyDB{}
+	fakePolicyDB = &fakes.FakePolicyDB{}
+	fakeBindingDB := &fakes.FakeBindingDB{}
+	fakeBindingDB.CheckServiceBindingReturns(true, nil)
 	fakeCFClient = &fakes.FakeCFClient{}
 
-	httpServer, err := publicapiserver.NewPublicApiServer(lager.NewLogger("test"), conf, fakePolicyDB, fakeCFClient)
+	httpServer, err := publicapiserver.NewPublicApiServer(lager.NewLogger("test"), conf, fakePolicyDB, fakeBindingDB, fakeCFClient, clock.NewClock())
 	Expect(err).NotTo(HaveOccurred())
 
 	serverUrl, err = url.Parse("http://127.0.0.1:" + strconv.Itoa(apiPort))
@@ -130,6 +164,19 @@ var _ = BeforeSuite(func() {
 
 	logger = helpers.InitLoggerFromConfig(&conf.Logging, "test")
 
+	scalingExplanationPathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/scaling_histories/[A-Za-z0-9\\-]+/explanation")
+	Expect(err).NotTo(HaveOccurred())
+	scalingEngineServer.RouteToHandler(http.MethodGet, scalingExplanationPathMatcher, ghttp.RespondWithJSONEncodedPtr(&scalingEngineExplanationStatus, &scalingEngineExplanationResponse))
+
+	pausePathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/pause")
+	Expect(err).NotTo(HaveOccurred())
+	recordPauseRequest := func(w http.ResponseWriter, r *http.Request) {
+		scalingEnginePauseRequest = r
+		scalingEnginePauseBody, _ = ioutil.ReadAll(r.Body)
+	}
+	scalingEngineServer.RouteToHandler(http.MethodPut, pausePathMatcher, ghttp.CombineHandlers(recordPauseRequest, ghttp.RespondWithJSONEncodedPtr(&scalingEnginePauseStatus, &scalingEnginePauseResponse)))
+	scalingEngineServer.RouteToHandler(http.MethodDelete, pausePathMatcher, ghttp.CombineHandlers(recordPauseRequest, ghttp.RespondWithPtr(&scalingEnginePauseStatus, &scalingEngineResumeBody)))
+
 	scalingHistoryPathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/scaling_histories")
 	Expect(err).NotTo(HaveOccurred())
 	scalingEngineServer.RouteToHandler(http.MethodGet, scalingHistoryPathMatcher, ghttp.RespondWithJSONEncodedPtr(&scalingEngineStatus, &scalingEngineResponse))
@@ -140,7 +187,20 @@ var _ = BeforeSuite(func() {
 
 	eventGeneratorPathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/aggregated_metric_histories/[a-zA-Z0-9_]+")
 	Expect(err).NotTo(HaveOccurred())
-	eventGeneratorServer.RouteToHandler(http.MethodGet, eventGeneratorPathMatcher, ghttp.RespondWithJSONEncodedPtr(&eventGeneratorStatus, &eventGeneratorResponse))
+	eventGeneratorQueries = &recordedQueries{}
+	recordEventGeneratorQuery := func(w http.ResponseWriter, r *http.Request) {
+		eventGeneratorQueries.add(r.URL.Query())
+	}
+	eventGeneratorServer.RouteToHandler(http.MethodGet, eventGeneratorPathMatcher, ghttp.CombineHandlers(recordEventGeneratorQuery, ghttp.RespondWithJSONEncodedPtr(&eventGeneratorStatus, &eventGeneratorResponse)))
+
+	anomaliesPathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/anomalies")
+	Expect(err).NotTo(HaveOccurred())
+	eventGeneratorServer.RouteToHandler(http.MethodGet, anomaliesPathMatcher, ghttp.CombineHandlers(recordEventGeneratorQuery, ghttp.RespondWithJSONEncodedPtr(&anomaliesStatus, &anomaliesResponse)))
+
+	schedulerPathMatcher, err := regexp.Compile("/v1/apps/[A-Za-z0-9\\-]+/schedules")
+	Expect(err).NotTo(HaveOccurred())
+	schedulerServer.RouteToHandler(http.MethodPut, schedulerPathMatcher, ghttp.RespondWithPtr(&schedulerStatus, nil))
+	schedulerServer.RouteToHandler(http.MethodDelete, schedulerPathMatcher, ghttp.RespondWithPtr(&schedulerStatus, nil))
 
 })
 
@@ -150,6 +210,7 @@ var _ = AfterSuite(func() {
 	scalingEngineServer.Close()
 	metricsCollectorServer.Close()
 	eventGeneratorServer.Close()
+	schedulerServer.Close()
 })
 
 func GetTestHandler() http.HandlerFunc {
@@ -157,3 +218,24 @@ func GetTestHandler() http.HandlerFunc {
 		w.Write([]byte("Success"))
 	}
 }
+
+// recordedQueries keeps the query strings of the requests which are sent by the goroutines of the server
+type recordedQueries struct {
+	lock    sync.Mutex
+	queries []url.Values
+}
+
+func (q *recordedQueries) add(query url.Values) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.queries = append(q.queries, query)
+}
+
+func (q *recordedQueries) Last() url.Values {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.queries) == 0 {
+		return nil
+	}
+	return q.queries[len(q.queries)-1]
+}
@@ -0,0 +1,241 @@
+package publicapiserver
+
+import (
+	"autoscaler/cf"
+	"autoscaler/db"
+	"autoscaler/models"
+
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/cfhttp/handlers"
+	"code.cloudfoundry.org/lager"
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+// ScalingGroupHandler manages the scaling group of an app. The group changes the scaling of every member, so the
+// user must be allowed to manage all of them, not only the app in the path.
+type ScalingGroupHandler struct {
+	logger   lager.Logger
+	policydb db.PolicyDB
+	cfClient cf.CFClient
+}
+
+func NewScalingGroupHandler(logger lager.Logger, policydb db.PolicyDB, cfClient cf.CFClient) *ScalingGroupHandler {
+	return &ScalingGroupHandler{
+		logger:   logger.Session("scaling-group-handler"),
+		policydb: policydb,
+		cfClient: cfClient,
+	}
+}
+
+func (h *ScalingGroupHandler) GetScalingGroup(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Get Scaling Group", lager.Data{"appId": appId})
+
+	group, err := h.policydb.GetScalingGroup(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling group from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling group"})
+		return
+	}
+	if group == nil {
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Scaling Group Not Found"})
+		return
+	}
+	handlers.WriteJSONResponse(w, http.StatusOK, group)
+}
+
+func (h *ScalingGroupHandler) SaveScalingGroup(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Save Scaling Group", lager.Data{"appId": appId})
+
+	group := &models.ScalingGroup{}
+	err := json.NewDecoder(r.Body).Decode(group)
+	if err != nil {
+		h.logger.Error("Failed to decode scaling group", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: "Invalid scaling group"})
+		return
+	}
+	if message := validateScalingGroup(appId, group); message != "" {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad Request",
+			Message: message})
+		return
+	}
+
+	// the group id is taken from the current group of the app, or generated for a new group, so that the user
+	// cannot replace the members of a group which they do not manage by naming it
+	current, err := h.policydb.GetScalingGroup(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling group from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling group"})
+		return
+	}
+	if group.GroupId != "" && (current == nil || current.GroupId != group.GroupId) {
+		handlers.WriteJSONResponse(w, http.StatusConflict, models.ErrorResponse{
+			Code:    "Conflict",
+			Message: fmt.Sprintf("Scaling group %s is not the scaling group of app %s", group.GroupId, appId)})
+		return
+	}
+	if current != nil {
+		group.GroupId = current.GroupId
+	} else {
+		groupId, err := uuid.NewV4()
+		if err != nil {
+			h.logger.Error("Failed to generate scaling group id", err, lager.Data{"appId": appId})
+			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "Interal-Server-Error",
+				Message: "Error generating scaling group id"})
+			return
+		}
+		group.GroupId = groupId.String()
+	}
+
+	// the members which leave the group are affected as well
+	appIds := []string{}
+	if current != nil {
+		for _, existingMember := range current.Members {
+			if group.Member(existingMember.AppId) == nil {
+				appIds = append(appIds, existingMember.AppId)
+			}
+		}
+	}
+	for _, member := range group.Members {
+		if member.AppId != appId {
+			existing, err := h.policydb.GetScalingGroup(member.AppId)
+			if err != nil {
+				h.logger.Error("Failed to retrieve scaling group from database", err, lager.Data{"appId": member.AppId})
+				handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+					Code:    "Interal-Server-Error",
+					Message: "Error retrieving scaling group"})
+				return
+			}
+			if existing != nil && existing.GroupId != group.GroupId {
+				handlers.WriteJSONResponse(w, http.StatusConflict, models.ErrorResponse{
+					Code:    "Conflict",
+					Message: fmt.Sprintf("App %s is in scaling group %s", member.AppId, existing.GroupId)})
+				return
+			}
+		}
+		appIds = append(appIds, member.AppId)
+	}
+	if !h.authorizeApps(w, r, appIds) {
+		return
+	}
+
+	err = h.policydb.SaveScalingGroup(group)
+	if err != nil {
+		h.logger.Error("Failed to save scaling group", err, lager.Data{"appId": appId, "group": group})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error saving scaling group"})
+		return
+	}
+	handlers.WriteJSONResponse(w, http.StatusOK, group)
+}
+
+func (h *ScalingGroupHandler) DeleteScalingGroup(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appId"]
+	h.logger.Info("Delete Scaling Group", lager.Data{"appId": appId})
+
+	group, err := h.policydb.GetScalingGroup(appId)
+	if err != nil {
+		h.logger.Error("Failed to retrieve scaling group from database", err, lager.Data{"appId": appId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error retrieving scaling group"})
+		return
+	}
+	if group == nil {
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not Found",
+			Message: "Scaling Group Not Found"})
+		return
+	}
+
+	appIds := []string{}
+	for _, member := range group.Members {
+		appIds = append(appIds, member.AppId)
+	}
+	if !h.authorizeApps(w, r, appIds) {
+		return
+	}
+
+	err = h.policydb.DeleteScalingGroup(group.GroupId)
+	if err != nil {
+		h.logger.Error("Failed to delete scaling group", err, lager.Data{"appId": appId, "groupId": group.GroupId})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error deleting scaling group"})
+		return
+	}
+	handlers.WriteJSONResponse(w, http.StatusOK, models.ErrorResponse{})
+}
+
+// authorizeApps checks that the user is an admin or a space developer of every app, and writes the error response
+// when not.
+func (h *ScalingGroupHandler) authorizeApps(w http.ResponseWriter, r *http.Request, appIds []string) bool {
+	userToken := r.Header.Get("Authorization")
+	isUserAdmin, err := h.cfClient.IsUserAdmin(userToken)
+	if err != nil {
+		h.logger.Error("failed to check if user is admin", err, nil)
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Failed to check if user is admin"})
+		return false
+	}
+	if isUserAdmin {
+		return true
+	}
+
+	for _, appId := range appIds {
+		isUserSpaceDeveloper, err := h.cfClient.IsUserSpaceDeveloper(userToken, appId)
+		if err != nil {
+			h.logger.Error("failed to check spacedeveloper permissions", err, lager.Data{"appId": appId})
+			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "Interal-Server-Error",
+				Message: "Failed to check space developer permission"})
+			return false
+		}
+		if !isUserSpaceDeveloper {
+			handlers.WriteJSONResponse(w, http.StatusUnauthorized, models.ErrorResponse{
+				Code:    "Unauthorized",
+				Message: fmt.Sprintf("Not authorized to manage app %s", appId)})
+			return false
+		}
+	}
+	return true
+}
+
+func validateScalingGroup(appId string, group *models.ScalingGroup) string {
+	if len(group.Members) < 2 {
+		return "A scaling group requires at least 2 members"
+	}
+	seen := map[string]bool{}
+	for _, member := range group.Members {
+		if member.AppId == "" {
+			return "app_id of the members is required"
+		}
+		if seen[member.AppId] {
+			return fmt.Sprintf("App %s is a member more than once", member.AppId)
+		}
+		seen[member.AppId] = true
+		if member.Ratio < 0 {
+			return fmt.Sprintf("The ratio of app %s must be positive", member.AppId)
+		}
+	}
+	if !seen[appId] {
+		return fmt.Sprintf("App %s is not a member of the scaling group", appId)
+	}
+	return ""
+}
@@ -0,0 +1,279 @@
+package publicapiserver_test
+
+import (
+	. "autoscaler/api/publicapiserver"
+	"autoscaler/fakes"
+	"autoscaler/models"
+
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScalingGroupHandler", func() {
+	const (
+		validGroupString = `{"group_id":"a-group","members":[{"app_id":"test-app-id"},{"app_id":"other-app-id","ratio":0.5}]}`
+		newGroupString   = `{"members":[{"app_id":"test-app-id"},{"app_id":"other-app-id","ratio":0.5}]}`
+	)
+	var (
+		policydb      *fakes.FakePolicyDB
+		cfClient      *fakes.FakeCFClient
+		handler       *ScalingGroupHandler
+		resp          *httptest.ResponseRecorder
+		req           *http.Request
+		pathVariables map[string]string
+		group         *models.ScalingGroup
+	)
+
+	BeforeEach(func() {
+		policydb = &fakes.FakePolicyDB{}
+		cfClient = &fakes.FakeCFClient{}
+		cfClient.IsUserSpaceDeveloperReturns(true, nil)
+		resp = httptest.NewRecorder()
+		pathVariables = map[string]string{"appId": TEST_APP_ID}
+		handler = NewScalingGroupHandler(lager.NewLogger("test"), policydb, cfClient)
+		group = &models.ScalingGroup{
+			GroupId: "a-group",
+			Members: []*models.ScalingGroupMember{
+				{AppId: TEST_APP_ID},
+				{AppId: "other-app-id", Ratio: 0.5},
+			},
+		}
+	})
+
+	Describe("GetScalingGroup", func() {
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodGet, "/v1/apps/"+TEST_APP_ID+"/scaling_group", nil)
+			handler.GetScalingGroup(resp, req, pathVariables)
+		})
+
+		Context("when the app is in a scaling group", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturns(group, nil)
+			})
+			It("returns the group", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(validGroupString))
+				Expect(policydb.GetScalingGroupArgsForCall(0)).To(Equal(TEST_APP_ID))
+			})
+		})
+
+		Context("when the app is not in a scaling group", func() {
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Not Found","message":"Scaling Group Not Found"}`))
+			})
+		})
+
+		Context("when database gives error", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturns(nil, errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error retrieving scaling group"}`))
+			})
+		})
+	})
+
+	Describe("SaveScalingGroup", func() {
+		var body string
+
+		BeforeEach(func() {
+			body = newGroupString
+		})
+
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPut, "/v1/apps/"+TEST_APP_ID+"/scaling_group", strings.NewReader(body))
+			req.Header.Set("Authorization", TEST_USER_TOKEN)
+			handler.SaveScalingGroup(resp, req, pathVariables)
+		})
+
+		Context("when the group is valid", func() {
+			It("saves the group with a generated group id", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(policydb.SaveScalingGroupCallCount()).To(Equal(1))
+				saved := policydb.SaveScalingGroupArgsForCall(0)
+				Expect(saved.GroupId).NotTo(BeEmpty())
+				Expect(saved.Members).To(Equal(group.Members))
+				Expect(resp.Body.String()).To(ContainSubstring(`"group_id":"` + saved.GroupId + `"`))
+			})
+
+			It("checks the user is a space developer of every member", func() {
+				Expect(cfClient.IsUserSpaceDeveloperCallCount()).To(Equal(2))
+				token, appId := cfClient.IsUserSpaceDeveloperArgsForCall(0)
+				Expect(token).To(Equal(TEST_USER_TOKEN))
+				Expect(appId).To(Equal(TEST_APP_ID))
+				_, appId = cfClient.IsUserSpaceDeveloperArgsForCall(1)
+				Expect(appId).To(Equal("other-app-id"))
+			})
+		})
+
+		Context("when the app is in a scaling group", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturns(&models.ScalingGroup{
+					GroupId: "a-group",
+					Members: []*models.ScalingGroupMember{{AppId: TEST_APP_ID}, {AppId: "other-app-id"}},
+				}, nil)
+			})
+
+			It("keeps the group id of the app", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(validGroupString))
+				Expect(policydb.SaveScalingGroupArgsForCall(0)).To(Equal(group))
+			})
+
+			Context("when the body has the group id of the app", func() {
+				BeforeEach(func() {
+					body = validGroupString
+				})
+				It("saves the group", func() {
+					Expect(resp.Code).To(Equal(http.StatusOK))
+					Expect(policydb.SaveScalingGroupArgsForCall(0)).To(Equal(group))
+				})
+			})
+		})
+
+		Context("when the body has the group id of another scaling group", func() {
+			BeforeEach(func() {
+				body = `{"group_id":"another-group","members":[{"app_id":"test-app-id"},{"app_id":"other-app-id"}]}`
+			})
+			It("should fail with 409", func() {
+				Expect(resp.Code).To(Equal(http.StatusConflict))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Conflict","message":"Scaling group another-group is not the scaling group of app test-app-id"}`))
+				Expect(policydb.SaveScalingGroupCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the body is not valid json", func() {
+			BeforeEach(func() {
+				body = `{"group_id":`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"Invalid scaling group"}`))
+				Expect(policydb.SaveScalingGroupCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the group has a single member", func() {
+			BeforeEach(func() {
+				body = `{"group_id":"a-group","members":[{"app_id":"test-app-id"}]}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"A scaling group requires at least 2 members"}`))
+			})
+		})
+
+		Context("when the app is not a member of the group", func() {
+			BeforeEach(func() {
+				body = `{"group_id":"a-group","members":[{"app_id":"an-app-id"},{"app_id":"other-app-id"}]}`
+			})
+			It("should fail with 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Bad Request","message":"App test-app-id is not a member of the scaling group"}`))
+			})
+		})
+
+		Context("when a member is in another scaling group", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturnsOnCall(1, &models.ScalingGroup{GroupId: "another-group"}, nil)
+			})
+			It("should fail with 409", func() {
+				Expect(resp.Code).To(Equal(http.StatusConflict))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Conflict","message":"App other-app-id is in scaling group another-group"}`))
+				Expect(policydb.SaveScalingGroupCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a member leaves the group", func() {
+			BeforeEach(func() {
+				existing := &models.ScalingGroup{
+					GroupId: "a-group",
+					Members: []*models.ScalingGroupMember{{AppId: TEST_APP_ID}, {AppId: "leaving-app-id"}},
+				}
+				policydb.GetScalingGroupReturnsOnCall(0, existing, nil)
+			})
+			It("checks the user is a space developer of the leaving member", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(cfClient.IsUserSpaceDeveloperCallCount()).To(Equal(3))
+				_, appId := cfClient.IsUserSpaceDeveloperArgsForCall(0)
+				Expect(appId).To(Equal("leaving-app-id"))
+			})
+		})
+
+		Context("when the user is not a space developer of a member", func() {
+			BeforeEach(func() {
+				cfClient.IsUserSpaceDeveloperReturnsOnCall(1, false, nil)
+			})
+			It("should fail with 401", func() {
+				Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Unauthorized","message":"Not authorized to manage app other-app-id"}`))
+				Expect(policydb.SaveScalingGroupCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the user is an admin", func() {
+			BeforeEach(func() {
+				cfClient.IsUserAdminReturns(true, nil)
+			})
+			It("saves the group without checking the members", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(cfClient.IsUserSpaceDeveloperCallCount()).To(BeZero())
+				Expect(policydb.SaveScalingGroupCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when database fails to save the group", func() {
+			BeforeEach(func() {
+				policydb.SaveScalingGroupReturns(errors.New("database error"))
+			})
+			It("should fail with 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(`{"code":"Interal-Server-Error","message":"Error saving scaling group"}`))
+			})
+		})
+	})
+
+	Describe("DeleteScalingGroup", func() {
+		JustBeforeEach(func() {
+			req = httptest.NewRequest(http.MethodDelete, "/v1/apps/"+TEST_APP_ID+"/scaling_group", nil)
+			req.Header.Set("Authorization", TEST_USER_TOKEN)
+			handler.DeleteScalingGroup(resp, req, pathVariables)
+		})
+
+		Context("when the app is in a scaling group", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturns(group, nil)
+			})
+			It("deletes the group", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(policydb.DeleteScalingGroupArgsForCall(0)).To(Equal("a-group"))
+			})
+		})
+
+		Context("when the app is not in a scaling group", func() {
+			It("should fail with 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+				Expect(policydb.DeleteScalingGroupCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the user is not a space developer of a member", func() {
+			BeforeEach(func() {
+				policydb.GetScalingGroupReturns(group, nil)
+				cfClient.IsUserSpaceDeveloperReturnsOnCall(1, false, nil)
+			})
+			It("should fail with 401", func() {
+				Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+				Expect(policydb.DeleteScalingGroupCallCount()).To(BeZero())
+			})
+		})
+	})
+})
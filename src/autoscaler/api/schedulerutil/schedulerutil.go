@@ -3,6 +3,7 @@ package schedulerutil
 import (
 	"autoscaler/api/config"
 	"autoscaler/helpers"
+	"autoscaler/models"
 	"autoscaler/routes"
 	"fmt"
 	"io/ioutil"
@@ -44,19 +45,19 @@ func (su *SchedulerUtil) CreateOrUpdateSchedule(appId string, policyJSONStr stri
 
 	req, err := http.NewRequest("PUT", url, strings.NewReader(policyJSONStr))
 	if err != nil {
-		su.logger.Error("failed to create request to scheduler", err, lager.Data{"appId": appId, "policy": policyJSONStr})
+		su.logger.Error("failed to create request to scheduler", err, lager.Data{"appId": appId, "policy": models.RedactPolicyForLog(policyJSONStr)})
 		return err
 	}
 
 	resp, err := su.httpClient.Do(req)
 	if err != nil {
-		su.logger.Error("failed to do request to scheduler", err, lager.Data{"appId": appId, "policy": policyJSONStr})
+		su.logger.Error("failed to do request to scheduler", err, lager.Data{"appId": appId, "policy": models.RedactPolicyForLog(policyJSONStr)})
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
-		su.logger.Info("successfully created/updated schedules", lager.Data{"appId": appId, "policy": policyJSONStr})
+		su.logger.Info("successfully created/updated schedules", lager.Data{"appId": appId, "policy": models.RedactPolicyForLog(policyJSONStr)})
 		return nil
 	}
 
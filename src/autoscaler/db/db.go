@@ -46,11 +46,24 @@ type PolicyDB interface {
 	DatabaseStatus
 	GetAppIds() (map[string]bool, error)
 	GetAppPolicy(appId string) (*models.ScalingPolicy, error)
-	SaveAppPolicy(appId string, policy string, policyGuid string) error
+	GetAppPolicyJson(appId string) (*models.PolicyJson, error)
+	SaveAppPolicy(appId string, policy string, policyGuid string, author string) error
+	RevertAppPolicy(appId string, policyGuid string, previous *models.PolicyJson) error
+	RetrievePolicyVersions(appId string) ([]*models.PolicyVersion, error)
+	GetPolicyVersion(appId string, version int) (*models.PolicyVersion, error)
 	RetrievePolicies() ([]*models.PolicyJson, error)
 	Close() error
 	DeletePolicy(appId string) error
-	GetCustomMetricsCreds(appId string) (string, string, error)
+	GetCustomMetricsCreds(appId string) ([]*models.CustomMetricCredentials, error)
+	RotateCustomMetricsCreds(appId string, usernameHash string, passwordHash string, previousExpireAt int64) error
+	GetScalingGroup(appId string) (*models.ScalingGroup, error)
+	SaveScalingGroup(group *models.ScalingGroup) error
+	DeleteScalingGroup(groupId string) error
+	GetAppPause(appId string) (*models.AppPause, error)
+	RetrieveAppPauses() (map[string]*models.AppPause, error)
+	SaveAppPause(pause *models.AppPause) error
+	DeleteAppPause(appId string) error
+	DeleteExpiredAppPause(appId string, now int64) error
 }
 
 type BindingDB interface {
@@ -59,6 +72,7 @@ type BindingDB interface {
 	DeleteServiceInstance(serviceInstanceId string) error
 	CreateServiceBinding(bindingId string, serviceInstanceId string, appId string) error
 	DeleteServiceBinding(bindingId string) error
+	CheckServiceBinding(appId string) (bool, error)
 	Close() error
 }
 
@@ -66,8 +80,10 @@ type AppMetricDB interface {
 	DatabaseStatus
 	SaveAppMetric(appMetric *models.AppMetric) error
 	SaveAppMetricsInBulk(metrics []*models.AppMetric) error
-	RetrieveAppMetrics(appId string, metricType string, start int64, end int64, orderType OrderType) ([]*models.AppMetric, error)
+	RetrieveAppMetrics(appId string, metricType string, aggregation string, start int64, end int64, orderType OrderType) ([]*models.AppMetric, error)
 	PruneAppMetrics(before int64) error
+	SaveAnomalyScore(score *models.AnomalyScore) error
+	RetrieveAnomalyScores(appId string, start int64, end int64, orderType OrderType) ([]*models.AnomalyScore, error)
 	Close() error
 }
 
@@ -75,13 +91,21 @@ type ScalingEngineDB interface {
 	DatabaseStatus
 	SaveScalingHistory(history *models.AppScalingHistory) error
 	RetrieveScalingHistories(appId string, start int64, end int64, orderType OrderType, includeAll bool) ([]*models.AppScalingHistory, error)
+	SaveScalingExplanation(history *models.AppScalingHistory, explanation *models.ScalingExplanation) error
+	RetrieveScalingExplanation(appId string, historyId int64) (*models.ScalingExplanation, error)
+	SaveScalingRecommendation(appId string, timestamp int64, instances int) error
+	GetMaxScalingRecommendation(appId string, start int64) (int, error)
 	PruneScalingHistories(before int64) error
-	UpdateScalingCooldownExpireTime(appId string, expireAt int64) error
-	CanScaleApp(appId string) (bool, int64, error)
+	UpdateScalingCooldownExpireTime(appId string, direction string, ruleKey string, expireAt int64) error
+	CanScaleApp(appId string, direction string, ruleKey string) (bool, int64, error)
 	GetActiveSchedule(appId string) (*models.ActiveSchedule, error)
 	GetActiveSchedules() (map[string]string, error)
 	SetActiveSchedule(appId string, schedule *models.ActiveSchedule) error
 	RemoveActiveSchedule(appId string) error
+	SaveScalingHistoryWithWebhookDeliveries(history *models.AppScalingHistory, deliveries []*models.WebhookDelivery) error
+	ClaimWebhookDeliveries(now int64, leaseUntil int64, limit int) ([]*models.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *models.WebhookDelivery) error
+	DeleteWebhookDelivery(id int64) error
 	Close() error
 }
 
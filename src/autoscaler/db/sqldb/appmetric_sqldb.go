@@ -52,8 +52,8 @@ func (adb *AppMetricSQLDB) Close() error {
 	return nil
 }
 func (adb *AppMetricSQLDB) SaveAppMetric(appMetric *models.AppMetric) error {
-	query := "INSERT INTO app_metric(app_id, metric_type, unit, timestamp, value) values($1, $2, $3, $4, $5)"
-	_, err := adb.sqldb.Exec(query, appMetric.AppId, appMetric.MetricType, appMetric.Unit, appMetric.Timestamp, appMetric.Value)
+	query := "INSERT INTO app_metric(app_id, metric_type, aggregation, unit, timestamp, value) values($1, $2, $3, $4, $5, $6)"
+	_, err := adb.sqldb.Exec(query, appMetric.AppId, appMetric.MetricType, getAggregation(appMetric), appMetric.Unit, appMetric.Timestamp, appMetric.Value)
 
 	if err != nil {
 		adb.logger.Error("insert-metric-into-app-metric-table", err, lager.Data{"query": query, "appMetric": appMetric})
@@ -68,13 +68,13 @@ func (adb *AppMetricSQLDB) SaveAppMetricsInBulk(appMetrics []*models.AppMetric)
 		return err
 	}
 
-	stmt, err := txn.Prepare(CopyIn("app_metric", "app_id", "metric_type", "unit", "timestamp", "value"))
+	stmt, err := txn.Prepare(CopyIn("app_metric", "app_id", "metric_type", "aggregation", "unit", "timestamp", "value"))
 	if err != nil {
 		adb.logger.Error("failed-to-prepare-statement", err)
 		return err
 	}
 	for _, appMetric := range appMetrics {
-		_, err := stmt.Exec(appMetric.AppId, appMetric.MetricType, appMetric.Unit, appMetric.Timestamp, appMetric.Value)
+		_, err := stmt.Exec(appMetric.AppId, appMetric.MetricType, getAggregation(appMetric), appMetric.Unit, appMetric.Timestamp, appMetric.Value)
 		if err != nil {
 			adb.logger.Error("failed-to-execute", err)
 		}
@@ -100,7 +100,7 @@ func (adb *AppMetricSQLDB) SaveAppMetricsInBulk(appMetrics []*models.AppMetric)
 
 	return nil
 }
-func (adb *AppMetricSQLDB) RetrieveAppMetrics(appIdP string, metricTypeP string, startP int64, endP int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+func (adb *AppMetricSQLDB) RetrieveAppMetrics(appIdP string, metricTypeP string, aggregationP string, startP int64, endP int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 	var orderStr string
 	if orderType == db.ASC {
 		orderStr = db.ASCSTR
@@ -112,9 +112,15 @@ func (adb *AppMetricSQLDB) RetrieveAppMetrics(appIdP string, metricTypeP string,
 		endP = time.Now().UnixNano()
 	}
 
-	query := "SELECT app_id,metric_type,value,unit,timestamp FROM app_metric WHERE app_id=$1 AND metric_type=$2 AND timestamp>=$3 AND timestamp<=$4 ORDER BY timestamp " + orderStr
+	query := "SELECT app_id,metric_type,aggregation,value,unit,timestamp FROM app_metric WHERE app_id=$1 AND metric_type=$2 AND timestamp>=$3 AND timestamp<=$4 AND value IS NOT NULL"
+	args := []interface{}{appIdP, metricTypeP, startP, endP}
+	if aggregationP != "" {
+		query += " AND aggregation=$5"
+		args = append(args, aggregationP)
+	}
+	query += " ORDER BY timestamp " + orderStr
 	appMetricList := []*models.AppMetric{}
-	rows, err := adb.sqldb.Query(query, appIdP, metricTypeP, startP, endP)
+	rows, err := adb.sqldb.Query(query, args...)
 	if err != nil {
 		adb.logger.Error("retrieve-app-metric-list-from-app_metric-table", err, lager.Data{"query": query})
 		return nil, err
@@ -122,21 +128,23 @@ func (adb *AppMetricSQLDB) RetrieveAppMetrics(appIdP string, metricTypeP string,
 	defer rows.Close()
 	var appId string
 	var metricType string
+	var aggregation string
 	var unit string
-	var value string
+	var value float64
 	var timestamp int64
 
 	for rows.Next() {
-		if err = rows.Scan(&appId, &metricType, &value, &unit, &timestamp); err != nil {
+		if err = rows.Scan(&appId, &metricType, &aggregation, &value, &unit, &timestamp); err != nil {
 			adb.logger.Error("scan-appmetric-from-search-result", err)
 			return nil, err
 		}
 		appMetric := &models.AppMetric{
-			AppId:      appId,
-			MetricType: metricType,
-			Value:      value,
-			Unit:       unit,
-			Timestamp:  timestamp,
+			AppId:       appId,
+			MetricType:  metricType,
+			Aggregation: aggregation,
+			Value:       value,
+			Unit:        unit,
+			Timestamp:   timestamp,
 		}
 		appMetricList = append(appMetricList, appMetric)
 	}
@@ -148,10 +156,67 @@ func (adb *AppMetricSQLDB) PruneAppMetrics(before int64) error {
 	_, err := adb.sqldb.Exec(query, before)
 	if err != nil {
 		adb.logger.Error("prune-metrics-from-app_metric-table", err, lager.Data{"query": query, "before": before})
+		return err
+	}
+
+	query = "DELETE FROM app_metric_anomaly WHERE timestamp <= $1"
+	_, err = adb.sqldb.Exec(query, before)
+	if err != nil {
+		adb.logger.Error("prune-anomalies-from-app_metric_anomaly-table", err, lager.Data{"query": query, "before": before})
 	}
 
 	return err
 }
+
+// SaveAnomalyScore records an anomaly of an app metric, an anomaly which is already recorded is ignored.
+func (adb *AppMetricSQLDB) SaveAnomalyScore(score *models.AnomalyScore) error {
+	query := "INSERT INTO app_metric_anomaly(app_id, metric_type, aggregation, value, unit, baseline, std_dev, score, timestamp) " +
+		"values($1, $2, $3, $4, $5, $6, $7, $8, $9) " +
+		"ON CONFLICT(app_id, metric_type, aggregation, timestamp) DO NOTHING"
+	_, err := adb.sqldb.Exec(query, score.AppId, score.MetricType, score.Aggregation, score.Value, score.Unit, score.Baseline, score.StdDev, score.Score, score.Timestamp)
+	if err != nil {
+		adb.logger.Error("insert-anomaly-into-app_metric_anomaly-table", err, lager.Data{"query": query, "score": score})
+	}
+	return err
+}
+
+func (adb *AppMetricSQLDB) RetrieveAnomalyScores(appId string, start int64, end int64, orderType db.OrderType) ([]*models.AnomalyScore, error) {
+	orderStr := db.DESCSTR
+	if orderType == db.ASC {
+		orderStr = db.ASCSTR
+	}
+	if end < 0 {
+		end = time.Now().UnixNano()
+	}
+
+	query := "SELECT app_id, metric_type, aggregation, value, unit, baseline, std_dev, score, timestamp FROM app_metric_anomaly " +
+		"WHERE app_id=$1 AND timestamp>=$2 AND timestamp<=$3 ORDER BY timestamp " + orderStr
+	rows, err := adb.sqldb.Query(query, appId, start, end)
+	if err != nil {
+		adb.logger.Error("retrieve-anomalies-from-app_metric_anomaly-table", err, lager.Data{"query": query})
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []*models.AnomalyScore{}
+	for rows.Next() {
+		score := &models.AnomalyScore{}
+		if err = rows.Scan(&score.AppId, &score.MetricType, &score.Aggregation, &score.Value, &score.Unit, &score.Baseline, &score.StdDev, &score.Score, &score.Timestamp); err != nil {
+			adb.logger.Error("scan-anomaly-from-search-result", err)
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+	return scores, rows.Err()
+}
+
 func (adb *AppMetricSQLDB) GetDBStatus() sql.DBStats {
 	return adb.sqldb.Stats()
 }
+
+func getAggregation(appMetric *models.AppMetric) string {
+	if appMetric.Aggregation == "" {
+		return models.AggregationAvg
+	}
+	return appMetric.Aggregation
+}
@@ -24,6 +24,7 @@ var _ = Describe("AppMetricSQLDB", func() {
 		start, end        int64
 		before            int64
 		appId, metricName string
+		aggregation       string
 		testMetricName    string = "Test-Metric-Name"
 		testMetricUnit    string = "Test-Metric-Unit"
 		testAppId         string = "Test-App-ID"
@@ -89,7 +90,7 @@ var _ = Describe("AppMetricSQLDB", func() {
 					MetricType: testMetricName,
 					Unit:       testMetricUnit,
 					Timestamp:  11111111,
-					Value:      "300",
+					Value:      300,
 				}
 				err = adb.SaveAppMetric(appMetric)
 				Expect(err).NotTo(HaveOccurred())
@@ -120,14 +121,14 @@ var _ = Describe("AppMetricSQLDB", func() {
 						MetricType: testMetricName,
 						Unit:       testMetricUnit,
 						Timestamp:  11111111,
-						Value:      "300",
+						Value:      300,
 					},
 					&models.AppMetric{
 						AppId:      testAppId,
 						MetricType: testMetricName,
 						Unit:       testMetricUnit,
 						Timestamp:  22222222,
-						Value:      "400",
+						Value:      400,
 					},
 				}
 
@@ -152,23 +153,24 @@ var _ = Describe("AppMetricSQLDB", func() {
 				MetricType: testMetricName,
 				Unit:       testMetricUnit,
 				Timestamp:  11111111,
-				Value:      "100",
+				Value:      100,
 			}
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
 			appMetric.Timestamp = 33333333
-			appMetric.Value = "200"
+			appMetric.Value = 200
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
 			appMetric.Timestamp = 55555555
-			appMetric.Value = "300"
+			appMetric.Value = 300
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
 			appId = testAppId
 			metricName = testMetricName
+			aggregation = ""
 			start = 0
 			end = -1
 
@@ -180,7 +182,7 @@ var _ = Describe("AppMetricSQLDB", func() {
 		})
 
 		JustBeforeEach(func() {
-			appMetrics, err = adb.RetrieveAppMetrics(appId, metricName, start, end, orderType)
+			appMetrics, err = adb.RetrieveAppMetrics(appId, metricName, aggregation, start, end, orderType)
 		})
 
 		Context("The app has no metrics", func() {
@@ -247,25 +249,28 @@ var _ = Describe("AppMetricSQLDB", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(appMetrics).To(Equal([]*models.AppMetric{
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  11111111,
-						Value:      "100",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   11111111,
+						Value:       100,
 					},
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  33333333,
-						Value:      "200",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   33333333,
+						Value:       200,
 					},
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  55555555,
-						Value:      "300",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   55555555,
+						Value:       300,
 					}}))
 			})
 		})
@@ -279,18 +284,47 @@ var _ = Describe("AppMetricSQLDB", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(appMetrics).To(Equal([]*models.AppMetric{
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  33333333,
-						Value:      "200",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   33333333,
+						Value:       200,
 					},
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  55555555,
-						Value:      "300",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   55555555,
+						Value:       300,
+					}}))
+			})
+		})
+
+		Context("when retriving the appMetrics of an aggregation", func() {
+			BeforeEach(func() {
+				err = adb.SaveAppMetric(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricName,
+					Aggregation: models.AggregationMax,
+					Unit:        testMetricUnit,
+					Timestamp:   33333333,
+					Value:       250,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				aggregation = models.AggregationMax
+			})
+			It("returns only the appMetrics of the aggregation", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(appMetrics).To(Equal([]*models.AppMetric{
+					&models.AppMetric{
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationMax,
+						Unit:        testMetricUnit,
+						Timestamp:   33333333,
+						Value:       250,
 					}}))
 			})
 		})
@@ -303,31 +337,90 @@ var _ = Describe("AppMetricSQLDB", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(appMetrics).To(Equal([]*models.AppMetric{
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  55555555,
-						Value:      "300",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   55555555,
+						Value:       300,
 					},
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  33333333,
-						Value:      "200",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   33333333,
+						Value:       200,
 					},
 					&models.AppMetric{
-						AppId:      testAppId,
-						MetricType: testMetricName,
-						Unit:       testMetricUnit,
-						Timestamp:  11111111,
-						Value:      "100",
+						AppId:       testAppId,
+						MetricType:  testMetricName,
+						Aggregation: models.AggregationAvg,
+						Unit:        testMetricUnit,
+						Timestamp:   11111111,
+						Value:       100,
 					},
 				}))
 			})
 		})
 	})
 
+	Context("SaveAnomalyScore and RetrieveAnomalyScores", func() {
+		var scores []*models.AnomalyScore
+
+		BeforeEach(func() {
+			adb, err = NewAppMetricSQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanAppMetricAnomalyTable()
+
+			score := &models.AnomalyScore{
+				AppId:       testAppId,
+				MetricType:  testMetricName,
+				Aggregation: models.AggregationAvg,
+				Value:       200,
+				Unit:        testMetricUnit,
+				Baseline:    100,
+				StdDev:      20,
+				Score:       5,
+				Timestamp:   11111111,
+			}
+			Expect(adb.SaveAnomalyScore(score)).To(Succeed())
+			Expect(adb.SaveAnomalyScore(score)).To(Succeed())
+			anotherScore := *score
+			anotherScore.Timestamp = 22222222
+			anotherScore.Score = -4
+			Expect(adb.SaveAnomalyScore(&anotherScore)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			err = adb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			scores, err = adb.RetrieveAnomalyScores(testAppId, 0, -1, db.DESC)
+		})
+
+		It("retrieves the anomalies once each, newest first", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scores).To(HaveLen(2))
+			Expect(scores[0].Timestamp).To(Equal(int64(22222222)))
+			Expect(scores[0].Score).To(Equal(-4.0))
+			Expect(scores[1]).To(Equal(&models.AnomalyScore{
+				AppId:       testAppId,
+				MetricType:  testMetricName,
+				Aggregation: models.AggregationAvg,
+				Value:       200,
+				Unit:        testMetricUnit,
+				Baseline:    100,
+				StdDev:      20,
+				Score:       5,
+				Timestamp:   11111111,
+			}))
+		})
+	})
+
 	Context("PruneAppMetrics", func() {
 		BeforeEach(func() {
 			adb, err = NewAppMetricSQLDB(dbConfig, logger)
@@ -340,19 +433,19 @@ var _ = Describe("AppMetricSQLDB", func() {
 				MetricType: testMetricName,
 				Unit:       testMetricUnit,
 				Timestamp:  11111111,
-				Value:      "100",
+				Value:      100,
 			}
 
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
 			appMetric.Timestamp = 55555555
-			appMetric.Value = "200"
+			appMetric.Value = 200
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
 			appMetric.Timestamp = 33333333
-			appMetric.Value = "300"
+			appMetric.Value = 300
 			err = adb.SaveAppMetric(appMetric)
 			Expect(err).NotTo(HaveOccurred())
 
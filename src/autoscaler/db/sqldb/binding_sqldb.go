@@ -144,6 +144,18 @@ func (bdb *BindingSQLDB) DeleteServiceBinding(bindingId string) error {
 	return db.ErrDoesNotExist
 }
 
+// CheckServiceBinding returns whether the app is bound to a service instance.
+func (bdb *BindingSQLDB) CheckServiceBinding(appId string) (bool, error) {
+	query := "SELECT FROM binding WHERE app_id = $1"
+	rows, err := bdb.sqldb.Query(query, appId)
+	if err != nil {
+		bdb.logger.Error("check-service-binding", err, lager.Data{"query": query, "appId": appId})
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
 func (bdb *BindingSQLDB) GetDBStatus() sql.DBStats {
 	return bdb.sqldb.Stats()
 }
@@ -230,4 +230,43 @@ var _ = Describe("BindingSqldb", func() {
 		})
 	})
 
+	Describe("CheckServiceBinding", func() {
+		var bound bool
+
+		BeforeEach(func() {
+			bdb, err = NewBindingSQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanServiceBindingTable()
+			cleanServiceInstanceTable()
+		})
+		AfterEach(func() {
+			cleanServiceBindingTable()
+			cleanServiceInstanceTable()
+			err = bdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		JustBeforeEach(func() {
+			bound, err = bdb.CheckServiceBinding(testAppId)
+		})
+		Context("When the app is not bound", func() {
+			It("should return false", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bound).To(BeFalse())
+			})
+		})
+		Context("When the app is bound", func() {
+			BeforeEach(func() {
+				err = bdb.CreateServiceInstance(testInstanceId, testOrgGuid, testSpaceGuid)
+				Expect(err).NotTo(HaveOccurred())
+				err = bdb.CreateServiceBinding(testBindingId, testInstanceId, testAppId)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("should return true", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bound).To(BeTrue())
+			})
+		})
+	})
+
 })
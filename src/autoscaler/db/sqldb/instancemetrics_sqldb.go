@@ -149,7 +149,8 @@ func (idb *InstanceMetricsSQLDB) RetrieveInstanceMetrics(appid string, instanceI
 	mtrcs := []*models.AppInstanceMetric{}
 	var index uint32
 	var collectedAt, timestamp int64
-	var unit, value string
+	var unit string
+	var value float64
 
 	for rows.Next() {
 		if err := rows.Scan(&index, &collectedAt, &unit, &value, &timestamp); err != nil {
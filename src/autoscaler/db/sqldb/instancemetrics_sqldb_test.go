@@ -97,7 +97,7 @@ var _ = Describe("InstancemetricsSqldb", func() {
 					CollectedAt:   111111,
 					Name:          testMetricName,
 					Unit:          testMetricUnit,
-					Value:         "123",
+					Value:         123,
 					Timestamp:     110000,
 				}
 				err = idb.SaveMetric(metric)
@@ -118,21 +118,21 @@ var _ = Describe("InstancemetricsSqldb", func() {
 				}
 				metric.InstanceIndex = 0
 				metric.CollectedAt = 111111
-				metric.Value = "123"
+				metric.Value = 123
 				metric.Timestamp = 111100
 				err = idb.SaveMetric(metric)
 				Expect(err).NotTo(HaveOccurred())
 
 				metric.InstanceIndex = 1
 				metric.CollectedAt = 111111
-				metric.Value = "214365"
+				metric.Value = 214365
 				metric.Timestamp = 110000
 				err = idb.SaveMetric(metric)
 				Expect(err).NotTo(HaveOccurred())
 
 				metric.InstanceIndex = 0
 				metric.CollectedAt = 222222
-				metric.Value = "654321"
+				metric.Value = 654321
 				metric.Timestamp = 220000
 				err = idb.SaveMetric(metric)
 				Expect(err).NotTo(HaveOccurred())
@@ -166,7 +166,7 @@ var _ = Describe("InstancemetricsSqldb", func() {
 					CollectedAt:   111111,
 					Name:          testMetricName,
 					Unit:          testMetricUnit,
-					Value:         "123",
+					Value:         123,
 					Timestamp:     110000,
 				}
 				metric2 := models.AppInstanceMetric{
@@ -175,7 +175,7 @@ var _ = Describe("InstancemetricsSqldb", func() {
 					CollectedAt:   222222,
 					Name:          testMetricName,
 					Unit:          testMetricUnit,
-					Value:         "234",
+					Value:         234,
 					Timestamp:     220000,
 				}
 				err = idb.SaveMetricsInBulk([]*models.AppInstanceMetric{&metric1, &metric2})
@@ -203,35 +203,35 @@ var _ = Describe("InstancemetricsSqldb", func() {
 
 			metric.InstanceIndex = 0
 			metric.CollectedAt = 111111
-			metric.Value = "654321"
+			metric.Value = 654321
 			metric.Timestamp = 111100
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 1
 			metric.CollectedAt = 111111
-			metric.Value = "214365"
+			metric.Value = 214365
 			metric.Timestamp = 110000
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 1
 			metric.CollectedAt = 222222
-			metric.Value = "321765"
+			metric.Value = 321765
 			metric.Timestamp = 222200
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 0
 			metric.CollectedAt = 333333
-			metric.Value = "879654"
+			metric.Value = 879654
 			metric.Timestamp = 110000
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 0
 			metric.CollectedAt = 222222
-			metric.Value = "654321"
+			metric.Value = 654321
 			metric.Timestamp = 111100
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
@@ -579,28 +579,28 @@ var _ = Describe("InstancemetricsSqldb", func() {
 
 			metric.InstanceIndex = 0
 			metric.CollectedAt = 111111
-			metric.Value = "654321"
+			metric.Value = 654321
 			metric.Timestamp = 111100
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 1
 			metric.CollectedAt = 111111
-			metric.Value = "214365"
+			metric.Value = 214365
 			metric.Timestamp = 110000
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 1
 			metric.CollectedAt = 222222
-			metric.Value = "321765"
+			metric.Value = 321765
 			metric.Timestamp = 222200
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
 
 			metric.InstanceIndex = 0
 			metric.CollectedAt = 222222
-			metric.Value = "654321"
+			metric.Value = 654321
 			metric.Timestamp = 111100
 			err = idb.SaveMetric(metric)
 			Expect(err).NotTo(HaveOccurred())
@@ -5,6 +5,7 @@ import (
 	"autoscaler/models"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	_ "github.com/lib/pq"
@@ -117,23 +118,209 @@ func (pdb *PolicySQLDB) GetAppPolicy(appId string) (*models.ScalingPolicy, error
 	scalingPolicy := &models.ScalingPolicy{}
 	err = json.Unmarshal(policyJson, scalingPolicy)
 	if err != nil {
-		pdb.logger.Error("get-app-policy-unmarshal", err, lager.Data{"policyJson": string(policyJson)})
+		pdb.logger.Error("get-app-policy-unmarshal", err, lager.Data{"policyJson": models.RedactPolicyForLog(string(policyJson))})
 		return nil, err
 	}
 	return scalingPolicy, nil
 }
 
-func (pdb *PolicySQLDB) SaveAppPolicy(appId string, policyJSON string, policyGuid string) error {
-	query := "INSERT INTO policy_json (app_id, policy_json, guid) VALUES ($1,$2, $3) " +
+// GetAppPolicyJson returns the policy of the app as it is saved together with its guid, or nil when the app has no
+// policy.
+func (pdb *PolicySQLDB) GetAppPolicyJson(appId string) (*models.PolicyJson, error) {
+	policyJson := &models.PolicyJson{AppId: appId}
+	query := "SELECT policy_json, guid FROM policy_json WHERE app_id = $1"
+	err := pdb.sqldb.QueryRow(query, appId).Scan(&policyJson.PolicyStr, &policyJson.PolicyGuid)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		pdb.logger.Error("get-app-policy-json", err, lager.Data{"query": query, "appid": appId})
+		return nil, err
+	}
+	return policyJson, nil
+}
+
+// SaveAppPolicy saves the policy of the app, and keeps it as a new version with the changes from the policy it
+// replaces.
+func (pdb *PolicySQLDB) SaveAppPolicy(appId string, policyJSON string, policyGuid string, author string) error {
+	txn, err := pdb.sqldb.Begin()
+	if err != nil {
+		pdb.logger.Error("save-app-policy-start-transaction", err, lager.Data{"app_id": appId})
+		return err
+	}
+	defer txn.Rollback()
+
+	// the policy of the app may not exist yet, so the saves are serialized with a lock of the app instead of its row,
+	// the api server in node takes the same lock
+	query := "SELECT pg_advisory_xact_lock(hashtext($1))"
+	_, err = txn.Exec(query, appId)
+	if err != nil {
+		pdb.logger.Error("save-app-policy-lock", err, lager.Data{"query": query, "app_id": appId})
+		return err
+	}
+
+	var oldPolicyJSON string
+	query = "SELECT policy_json FROM policy_json WHERE app_id = $1"
+	err = txn.QueryRow(query, appId).Scan(&oldPolicyJSON)
+	if err != nil && err != sql.ErrNoRows {
+		pdb.logger.Error("save-app-policy-get-old-policy", err, lager.Data{"query": query, "app_id": appId})
+		return err
+	}
+
+	diff, err := models.DiffPolicies(oldPolicyJSON, policyJSON)
+	if err != nil {
+		pdb.logger.Error("save-app-policy-diff", err, lager.Data{"app_id": appId, "policyJSON": models.RedactPolicyForLog(policyJSON)})
+		return err
+	}
+	// the versions are kept forever, so they keep the policy and its changes without the secrets
+	diffJSON, err := json.Marshal(models.RedactPolicyChanges(diff))
+	if err != nil {
+		pdb.logger.Error("save-app-policy-marshal-diff", err, lager.Data{"app_id": appId})
+		return err
+	}
+
+	query = "INSERT INTO policy_json (app_id, policy_json, guid) VALUES ($1,$2, $3) " +
 		"ON CONFLICT(app_id) DO UPDATE SET policy_json=EXCLUDED.policy_json, guid=EXCLUDED.guid"
+	_, err = txn.Exec(query, appId, policyJSON, policyGuid)
+	if err != nil {
+		pdb.logger.Error("save-app-policy", err, lager.Data{"query": query, "app_id": appId, "policyJSON": models.RedactPolicyForLog(policyJSON), "policyGuid": policyGuid})
+		return err
+	}
+
+	versionJSON, err := models.RedactPolicy([]byte(policyJSON))
+	if err != nil {
+		pdb.logger.Error("save-app-policy-redact-version", err, lager.Data{"app_id": appId})
+		return err
+	}
+
+	query = "INSERT INTO policy_version (app_id, version, policy_json, guid, author, created_at, diff) " +
+		"SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6 FROM policy_version WHERE app_id = $1"
+	_, err = txn.Exec(query, appId, string(versionJSON), policyGuid, author, time.Now().UnixNano(), string(diffJSON))
+	if err != nil {
+		pdb.logger.Error("save-app-policy-version", err, lager.Data{"query": query, "app_id": appId, "policyGuid": policyGuid})
+		return err
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		pdb.logger.Error("save-app-policy-commit-transaction", err, lager.Data{"app_id": appId})
+	}
+	return err
+}
+
+// RevertAppPolicy undoes the save of the policy with policyGuid, it deletes its version and restores the previous
+// policy, or deletes the policy when there was none. Nothing is reverted when the policy has been replaced since.
+func (pdb *PolicySQLDB) RevertAppPolicy(appId string, policyGuid string, previous *models.PolicyJson) error {
+	txn, err := pdb.sqldb.Begin()
+	if err != nil {
+		pdb.logger.Error("revert-app-policy-start-transaction", err, lager.Data{"app_id": appId})
+		return err
+	}
+	defer txn.Rollback()
+
+	query := "SELECT pg_advisory_xact_lock(hashtext($1))"
+	_, err = txn.Exec(query, appId)
+	if err != nil {
+		pdb.logger.Error("revert-app-policy-lock", err, lager.Data{"query": query, "app_id": appId})
+		return err
+	}
+
+	var currentGuid string
+	query = "SELECT guid FROM policy_json WHERE app_id = $1"
+	err = txn.QueryRow(query, appId).Scan(&currentGuid)
+	if err == sql.ErrNoRows || (err == nil && currentGuid != policyGuid) {
+		pdb.logger.Info("revert-app-policy-replaced", lager.Data{"app_id": appId, "policyGuid": policyGuid})
+		return nil
+	}
+	if err != nil {
+		pdb.logger.Error("revert-app-policy-get-guid", err, lager.Data{"query": query, "app_id": appId})
+		return err
+	}
 
-	_, err := pdb.sqldb.Exec(query, appId, policyJSON, policyGuid)
+	query = "DELETE FROM policy_version WHERE app_id = $1 AND guid = $2"
+	_, err = txn.Exec(query, appId, policyGuid)
 	if err != nil {
-		pdb.logger.Error("save-app-policy", err, lager.Data{"query": query, "app_id": appId, "policyJSON": policyJSON, "policyGuid": policyGuid})
+		pdb.logger.Error("revert-app-policy-delete-version", err, lager.Data{"query": query, "app_id": appId, "policyGuid": policyGuid})
+		return err
+	}
+
+	if previous == nil {
+		query = "DELETE FROM policy_json WHERE app_id = $1"
+		_, err = txn.Exec(query, appId)
+	} else {
+		query = "UPDATE policy_json SET policy_json = $1, guid = $2 WHERE app_id = $3"
+		_, err = txn.Exec(query, previous.PolicyStr, previous.PolicyGuid, appId)
+	}
+	if err != nil {
+		pdb.logger.Error("revert-app-policy", err, lager.Data{"query": query, "app_id": appId})
+		return err
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		pdb.logger.Error("revert-app-policy-commit-transaction", err, lager.Data{"app_id": appId})
 	}
 	return err
 }
 
+// RetrievePolicyVersions returns the versions of the app policy, newest first and without the policies.
+func (pdb *PolicySQLDB) RetrievePolicyVersions(appId string) ([]*models.PolicyVersion, error) {
+	query := "SELECT version, guid, author, created_at, diff FROM policy_version WHERE app_id = $1 ORDER BY version DESC"
+	rows, err := pdb.sqldb.Query(query, appId)
+	if err != nil {
+		pdb.logger.Error("retrieve-policy-versions", err, lager.Data{"query": query, "appId": appId})
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*models.PolicyVersion{}
+	for rows.Next() {
+		version := &models.PolicyVersion{AppId: appId}
+		var diff sql.NullString
+		if err = rows.Scan(&version.Version, &version.PolicyGuid, &version.Author, &version.CreatedAt, &diff); err != nil {
+			pdb.logger.Error("retrieve-policy-versions-scan", err, lager.Data{"query": query, "appId": appId})
+			return nil, err
+		}
+		if err = unmarshalPolicyDiff(diff, version); err != nil {
+			pdb.logger.Error("retrieve-policy-versions-unmarshal-diff", err, lager.Data{"appId": appId, "version": version.Version})
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// GetPolicyVersion returns the version of the app policy, or nil when it does not exist.
+func (pdb *PolicySQLDB) GetPolicyVersion(appId string, version int) (*models.PolicyVersion, error) {
+	query := "SELECT policy_json, guid, author, created_at, diff FROM policy_version WHERE app_id = $1 AND version = $2"
+	policyVersion := &models.PolicyVersion{AppId: appId, Version: version}
+	var policyJSON []byte
+	var diff sql.NullString
+	err := pdb.sqldb.QueryRow(query, appId, version).Scan(&policyJSON, &policyVersion.PolicyGuid, &policyVersion.Author, &policyVersion.CreatedAt, &diff)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		pdb.logger.Error("get-policy-version", err, lager.Data{"query": query, "appId": appId, "version": version})
+		return nil, err
+	}
+	policyVersion.Policy = policyJSON
+
+	if err = unmarshalPolicyDiff(diff, policyVersion); err != nil {
+		pdb.logger.Error("get-policy-version-unmarshal-diff", err, lager.Data{"appId": appId, "version": version})
+		return nil, err
+	}
+	return policyVersion, nil
+}
+
+func unmarshalPolicyDiff(diff sql.NullString, version *models.PolicyVersion) error {
+	version.Diff = []*models.PolicyChange{}
+	if !diff.Valid || diff.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(diff.String), &version.Diff)
+}
+
 func (pdb *PolicySQLDB) DeletePolicy(appId string) error {
 	query := "DELETE FROM policy_json WHERE app_id = $1"
 	_, err := pdb.sqldb.Exec(query, appId)
@@ -147,15 +334,194 @@ func (pdb *PolicySQLDB) GetDBStatus() sql.DBStats {
 	return pdb.sqldb.Stats()
 }
 
-func (pdb *PolicySQLDB) GetCustomMetricsCreds(appId string) (string, string, error) {
-	var password string
-	var username string
-	query := "SELECT username,password from credentials WHERE id = $1"
-	err := pdb.sqldb.QueryRow(query, appId).Scan(&username, &password)
-
+// GetCustomMetricsCreds returns the valid credentials of the app, the previous credentials of a rotation are returned
+// until they expire.
+func (pdb *PolicySQLDB) GetCustomMetricsCreds(appId string) ([]*models.CustomMetricCredentials, error) {
+	var previousUsername sql.NullString
+	var previousPassword sql.NullString
+	credentials := &models.CustomMetricCredentials{}
+	previousCredentials := &models.CustomMetricCredentials{}
+	query := "SELECT username, password, previous_username, previous_password, previous_expire_at FROM credentials WHERE id = $1"
+	err := pdb.sqldb.QueryRow(query, appId).Scan(&credentials.Username, &credentials.Password, &previousUsername, &previousPassword, &previousCredentials.ExpireAt)
 	if err != nil {
 		pdb.logger.Error("get-custom-metrics-creds-from-credentials-table", err, lager.Data{"query": query})
-		return "", "", err
+		return nil, err
+	}
+
+	result := []*models.CustomMetricCredentials{credentials}
+	if previousUsername.Valid && previousPassword.Valid && !previousCredentials.IsExpired(time.Now().UnixNano()) {
+		previousCredentials.Username = previousUsername.String
+		previousCredentials.Password = previousPassword.String
+		result = append(result, previousCredentials)
+	}
+	return result, nil
+}
+
+// RotateCustomMetricsCreds replaces the credentials of the app and keeps the replaced credentials until
+// previousExpireAt, the credentials replaced by an earlier rotation are revoked.
+func (pdb *PolicySQLDB) RotateCustomMetricsCreds(appId string, usernameHash string, passwordHash string, previousExpireAt int64) error {
+	query := "UPDATE credentials SET previous_username = username, previous_password = password, previous_expire_at = $1," +
+		" username = $2, password = $3, updated_at = $4 WHERE id = $5"
+	result, err := pdb.sqldb.Exec(query, previousExpireAt, usernameHash, passwordHash, time.Now(), appId)
+	if err != nil {
+		pdb.logger.Error("rotate-custom-metrics-creds", err, lager.Data{"query": query, "appId": appId})
+		return err
 	}
-	return username, password, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		pdb.logger.Error("rotate-custom-metrics-creds-rows-affected", err, lager.Data{"query": query, "appId": appId})
+		return err
+	}
+	if rowsAffected == 0 {
+		return db.ErrDoesNotExist
+	}
+	return nil
+}
+
+// GetScalingGroup returns the scaling group the app is a member of, or nil when it is not in a group.
+func (pdb *PolicySQLDB) GetScalingGroup(appId string) (*models.ScalingGroup, error) {
+	query := "SELECT app_id, group_id, ratio, instance_offset FROM scaling_group" +
+		" WHERE group_id = (SELECT group_id FROM scaling_group WHERE app_id = $1) ORDER BY app_id"
+	rows, err := pdb.sqldb.Query(query, appId)
+	if err != nil {
+		pdb.logger.Error("get-scaling-group", err, lager.Data{"query": query, "appId": appId})
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group *models.ScalingGroup
+	for rows.Next() {
+		var groupId string
+		member := &models.ScalingGroupMember{}
+		if err = rows.Scan(&member.AppId, &groupId, &member.Ratio, &member.Offset); err != nil {
+			pdb.logger.Error("get-scaling-group-scan", err, lager.Data{"query": query, "appId": appId})
+			return nil, err
+		}
+		if group == nil {
+			group = &models.ScalingGroup{GroupId: groupId, Members: []*models.ScalingGroupMember{}}
+		}
+		group.Members = append(group.Members, member)
+	}
+	return group, rows.Err()
+}
+
+// SaveScalingGroup replaces the members of the group.
+func (pdb *PolicySQLDB) SaveScalingGroup(group *models.ScalingGroup) error {
+	txn, err := pdb.sqldb.Begin()
+	if err != nil {
+		pdb.logger.Error("save-scaling-group-start-transaction", err, lager.Data{"group": group})
+		return err
+	}
+	defer txn.Rollback()
+
+	query := "DELETE FROM scaling_group WHERE group_id = $1"
+	_, err = txn.Exec(query, group.GroupId)
+	if err != nil {
+		pdb.logger.Error("save-scaling-group-delete", err, lager.Data{"query": query, "group": group})
+		return err
+	}
+
+	query = "INSERT INTO scaling_group (app_id, group_id, ratio, instance_offset) VALUES ($1, $2, $3, $4)"
+	for _, member := range group.Members {
+		_, err = txn.Exec(query, member.AppId, group.GroupId, member.RatioOrDefault(), member.Offset)
+		if err != nil {
+			pdb.logger.Error("save-scaling-group-insert", err, lager.Data{"query": query, "group": group, "member": member})
+			return err
+		}
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		pdb.logger.Error("save-scaling-group-commit-transaction", err, lager.Data{"group": group})
+	}
+	return err
+}
+
+func (pdb *PolicySQLDB) DeleteScalingGroup(groupId string) error {
+	query := "DELETE FROM scaling_group WHERE group_id = $1"
+	_, err := pdb.sqldb.Exec(query, groupId)
+	if err != nil {
+		pdb.logger.Error("delete-scaling-group", err, lager.Data{"query": query, "groupId": groupId})
+	}
+	return err
+}
+
+func (pdb *PolicySQLDB) GetAppPause(appId string) (*models.AppPause, error) {
+	query := "SELECT app_id, paused_by, reason, paused_at, expire_at FROM app_pause WHERE app_id = $1"
+	pause := &models.AppPause{}
+	var reason sql.NullString
+	err := pdb.sqldb.QueryRow(query, appId).Scan(&pause.AppId, &pause.PausedBy, &reason, &pause.PausedAt, &pause.ExpireAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		pdb.logger.Error("get-app-pause", err, lager.Data{"query": query, "appId": appId})
+		return nil, err
+	}
+	pause.Reason = reason.String
+	return pause, nil
+}
+
+func (pdb *PolicySQLDB) RetrieveAppPauses() (map[string]*models.AppPause, error) {
+	query := "SELECT app_id, paused_by, reason, paused_at, expire_at FROM app_pause"
+	rows, err := pdb.sqldb.Query(query)
+	if err != nil {
+		pdb.logger.Error("retrieve-app-pauses", err, lager.Data{"query": query})
+		return nil, err
+	}
+	defer rows.Close()
+
+	pauses := map[string]*models.AppPause{}
+	for rows.Next() {
+		pause := &models.AppPause{}
+		var reason sql.NullString
+		if err = rows.Scan(&pause.AppId, &pause.PausedBy, &reason, &pause.PausedAt, &pause.ExpireAt); err != nil {
+			pdb.logger.Error("retrieve-app-pauses-scan", err, lager.Data{"query": query})
+			return nil, err
+		}
+		pause.Reason = reason.String
+		pauses[pause.AppId] = pause
+	}
+	return pauses, rows.Err()
+}
+
+// SaveAppPause pauses the app, or replaces the pause of an app which is paused already.
+func (pdb *PolicySQLDB) SaveAppPause(pause *models.AppPause) error {
+	query := "INSERT INTO app_pause (app_id, paused_by, reason, paused_at, expire_at) VALUES ($1, $2, $3, $4, $5) " +
+		"ON CONFLICT(app_id) DO UPDATE SET paused_by=EXCLUDED.paused_by, reason=EXCLUDED.reason, " +
+		"paused_at=EXCLUDED.paused_at, expire_at=EXCLUDED.expire_at"
+	_, err := pdb.sqldb.Exec(query, pause.AppId, pause.PausedBy, pause.Reason, pause.PausedAt, pause.ExpireAt)
+	if err != nil {
+		pdb.logger.Error("save-app-pause", err, lager.Data{"query": query, "pause": pause})
+	}
+	return err
+}
+
+func (pdb *PolicySQLDB) DeleteAppPause(appId string) error {
+	query := "DELETE FROM app_pause WHERE app_id = $1"
+	_, err := pdb.sqldb.Exec(query, appId)
+	if err != nil {
+		pdb.logger.Error("delete-app-pause", err, lager.Data{"query": query, "appId": appId})
+	}
+	return err
+}
+
+// DeleteExpiredAppPause deletes the pause of the app when it expires at or before now, and returns ErrDoesNotExist
+// when the app has no such pause, e.g. because another scaling engine has deleted it already.
+func (pdb *PolicySQLDB) DeleteExpiredAppPause(appId string, now int64) error {
+	query := "DELETE FROM app_pause WHERE app_id = $1 AND expire_at > 0 AND expire_at <= $2"
+	result, err := pdb.sqldb.Exec(query, appId, now)
+	if err != nil {
+		pdb.logger.Error("delete-expired-app-pause", err, lager.Data{"query": query, "appId": appId})
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		pdb.logger.Error("delete-expired-app-pause-rows-affected", err, lager.Data{"query": query, "appId": appId})
+		return err
+	}
+	if rowsAffected == 0 {
+		return db.ErrDoesNotExist
+	}
+	return nil
 }
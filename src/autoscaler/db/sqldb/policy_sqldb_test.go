@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 )
@@ -28,8 +29,6 @@ var _ = Describe("PolicySQLDB", func() {
 		appId          string
 		policies       []*models.PolicyJson
 		testMetricName string = "TestMetricName"
-		username       string
-		password       string
 	)
 
 	BeforeEach(func() {
@@ -236,6 +235,7 @@ var _ = Describe("PolicySQLDB", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			cleanPolicyTable()
+			cleanPolicyVersionTable()
 		})
 
 		AfterEach(func() {
@@ -257,12 +257,20 @@ var _ = Describe("PolicySQLDB", func() {
 						"adjustment":"+1"
 					}]
 				}`
-				err = pdb.SaveAppPolicy("an-app-id", policyJsonStr, "1234")
+				err = pdb.SaveAppPolicy("an-app-id", policyJsonStr, "1234", "a-user-id")
 			})
 			It("saves the policy", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(getAppPolicy("an-app-id")).To(Equal(policyJsonStr))
 			})
+			It("saves the first version", func() {
+				version, err := pdb.GetPolicyVersion("an-app-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version.PolicyGuid).To(Equal("1234"))
+				Expect(version.Author).To(Equal("a-user-id"))
+				Expect(string(version.Policy)).To(Equal(policyJsonStr))
+				Expect(version.Diff).To(ContainElement(&models.PolicyChange{Path: "instance_max_count", New: float64(4)}))
+			})
 		})
 
 		Context("when a policy is already present for the app_id", func() {
@@ -290,12 +298,172 @@ var _ = Describe("PolicySQLDB", func() {
 						"adjustment":"+1"
 					}]
 				}`
-				err = pdb.SaveAppPolicy("an-app-id", policyJsonStr, "1234")
+				err = pdb.SaveAppPolicy("an-app-id", policyJsonStr, "1234", "a-user-id")
 			})
 			It("updates the policy", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(getAppPolicy("an-app-id")).To(Equal(policyJsonStr))
 			})
+			It("saves a version with the changes from the old policy", func() {
+				version, err := pdb.GetPolicyVersion("an-app-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version.Diff).To(ContainElement(&models.PolicyChange{Path: "instance_max_count", Old: float64(6), New: float64(4)}))
+			})
+		})
+
+		Context("when the policy has webhook secrets", func() {
+			JustBeforeEach(func() {
+				policyJsonStr = `{"instance_min_count":1,"instance_max_count":4,"webhooks":[{"url":"https://hooks.example.com/scaling","secret":"a-secret"}]}`
+				err = pdb.SaveAppPolicy("an-app-id", policyJsonStr, "1234", "a-user-id")
+			})
+			It("saves the policy with the secrets", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getAppPolicy("an-app-id")).To(Equal(policyJsonStr))
+			})
+			It("saves a version without the secrets", func() {
+				version, err := pdb.GetPolicyVersion("an-app-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(version.Policy)).NotTo(ContainSubstring("a-secret"))
+				Expect(version.Policy).To(MatchJSON(`{"instance_min_count":1,"instance_max_count":4,"webhooks":[{"url":"https://hooks.example.com/scaling","secret":"[REDACTED]"}]}`))
+				Expect(version.Diff).To(ContainElement(&models.PolicyChange{Path: "webhooks", New: []interface{}{
+					map[string]interface{}{"url": "https://hooks.example.com/scaling", "secret": models.RedactedValue},
+				}}))
+			})
+		})
+
+		Context("when the first policies of the app_id are saved concurrently", func() {
+			It("saves every policy as a version", func() {
+				errs := make(chan error, 5)
+				for i := 0; i < 5; i++ {
+					go func(i int) {
+						errs <- pdb.SaveAppPolicy("an-app-id", fmt.Sprintf(`{"instance_min_count":1,"instance_max_count":%d}`, i+2), "1234", "a-user-id")
+					}(i)
+				}
+				for i := 0; i < 5; i++ {
+					Expect(<-errs).NotTo(HaveOccurred())
+				}
+				versions, err := pdb.RetrievePolicyVersions("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(HaveLen(5))
+			})
+		})
+	})
+
+	Describe("PolicyVersions", func() {
+		var policies []string
+
+		BeforeEach(func() {
+			pdb, err = NewPolicySQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanPolicyTable()
+			cleanPolicyVersionTable()
+
+			policies = []string{
+				`{"instance_min_count":1,"instance_max_count":4}`,
+				`{"instance_min_count":1,"instance_max_count":5}`,
+			}
+			Expect(pdb.SaveAppPolicy("an-app-id", policies[0], "guid-1", "a-user-id")).To(Succeed())
+			Expect(pdb.DeletePolicy("an-app-id")).To(Succeed())
+			Expect(pdb.SaveAppPolicy("an-app-id", policies[1], "guid-2", "another-user-id")).To(Succeed())
+			Expect(pdb.SaveAppPolicy("another-app-id", policies[0], "guid-3", "a-user-id")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			err = pdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("RetrievePolicyVersions", func() {
+			It("returns the versions of the app newest first", func() {
+				versions, err := pdb.RetrievePolicyVersions("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(HaveLen(2))
+				Expect(versions[0].Version).To(Equal(2))
+				Expect(versions[0].PolicyGuid).To(Equal("guid-2"))
+				Expect(versions[0].Author).To(Equal("another-user-id"))
+				Expect(versions[0].Policy).To(BeEmpty())
+				Expect(versions[1].Version).To(Equal(1))
+				Expect(versions[1].CreatedAt).To(BeNumerically("<=", versions[0].CreatedAt))
+			})
+
+			It("returns no versions when the app never had a policy", func() {
+				versions, err := pdb.RetrievePolicyVersions("an-unknown-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+			})
+		})
+
+		Context("GetPolicyVersion", func() {
+			It("keeps the versions of a detached policy", func() {
+				version, err := pdb.GetPolicyVersion("an-app-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(version.Policy)).To(Equal(policies[0]))
+			})
+
+			It("returns nil when the version does not exist", func() {
+				version, err := pdb.GetPolicyVersion("an-app-id", 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(BeNil())
+			})
+		})
+	})
+
+	Describe("RevertAppPolicy", func() {
+		var previous *models.PolicyJson
+
+		BeforeEach(func() {
+			pdb, err = NewPolicySQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanPolicyTable()
+			cleanPolicyVersionTable()
+		})
+
+		AfterEach(func() {
+			err = pdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the app had a policy", func() {
+			BeforeEach(func() {
+				Expect(pdb.SaveAppPolicy("an-app-id", `{"instance_min_count":1,"instance_max_count":4}`, "guid-1", "a-user-id")).To(Succeed())
+				previous, err = pdb.GetAppPolicyJson("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(previous).To(Equal(&models.PolicyJson{AppId: "an-app-id", PolicyStr: `{"instance_min_count":1,"instance_max_count":4}`, PolicyGuid: "guid-1"}))
+				Expect(pdb.SaveAppPolicy("an-app-id", `{"instance_min_count":1,"instance_max_count":5}`, "guid-2", "a-user-id")).To(Succeed())
+			})
+
+			It("restores the previous policy and deletes the version of the reverted policy", func() {
+				Expect(pdb.RevertAppPolicy("an-app-id", "guid-2", previous)).To(Succeed())
+				Expect(pdb.GetAppPolicyJson("an-app-id")).To(Equal(previous))
+				versions, err := pdb.RetrievePolicyVersions("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(HaveLen(1))
+				Expect(versions[0].PolicyGuid).To(Equal("guid-1"))
+			})
+
+			It("does not revert a policy which has been replaced since", func() {
+				Expect(pdb.SaveAppPolicy("an-app-id", `{"instance_min_count":1,"instance_max_count":6}`, "guid-3", "a-user-id")).To(Succeed())
+				Expect(pdb.RevertAppPolicy("an-app-id", "guid-2", previous)).To(Succeed())
+				policyJson, err := pdb.GetAppPolicyJson("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policyJson.PolicyGuid).To(Equal("guid-3"))
+				Expect(pdb.RetrievePolicyVersions("an-app-id")).To(HaveLen(3))
+			})
+		})
+
+		Context("when the app had no policy", func() {
+			BeforeEach(func() {
+				Expect(pdb.GetAppPolicyJson("an-app-id")).To(BeNil())
+				Expect(pdb.SaveAppPolicy("an-app-id", `{"instance_min_count":1,"instance_max_count":5}`, "guid-1", "a-user-id")).To(Succeed())
+			})
+
+			It("deletes the policy and its version", func() {
+				Expect(pdb.RevertAppPolicy("an-app-id", "guid-1", nil)).To(Succeed())
+				Expect(pdb.GetAppPolicyJson("an-app-id")).To(BeNil())
+				Expect(pdb.RetrievePolicyVersions("an-app-id")).To(BeEmpty())
+			})
 		})
 	})
 
@@ -347,6 +515,8 @@ var _ = Describe("PolicySQLDB", func() {
 	})
 
 	Describe("GetCustomMetricsCreds", func() {
+		var credentials []*models.CustomMetricCredentials
+
 		BeforeEach(func() {
 			pdb, err = NewPolicySQLDB(dbConfig, logger)
 			Expect(err).NotTo(HaveOccurred())
@@ -360,14 +530,13 @@ var _ = Describe("PolicySQLDB", func() {
 		})
 
 		JustBeforeEach(func() {
-			username, password, err = pdb.GetCustomMetricsCreds("an-app-id")
+			credentials, err = pdb.GetCustomMetricsCreds("an-app-id")
 		})
 
 		Context("when credentials table is empty", func() {
 			It("should not return any credentials", func() {
 				Expect(err).To(HaveOccurred())
-				Expect(password).To(BeEmpty())
-				Expect(username).To(BeEmpty())
+				Expect(credentials).To(BeEmpty())
 			})
 		})
 
@@ -378,11 +547,212 @@ var _ = Describe("PolicySQLDB", func() {
 
 			It("Should get the password", func() {
 				Expect(err).NotTo(HaveOccurred())
-				Expect(password).To(Equal("password"))
-				Expect(username).To(Equal("username"))
+				Expect(credentials).To(Equal([]*models.CustomMetricCredentials{{Username: "username", Password: "password"}}))
+			})
+		})
+
+		Context("when the credentials have been rotated", func() {
+			var previousExpireAt int64
+
+			BeforeEach(func() {
+				insertCustomMetricsBindingCredentials("an-app-id", "username", "password")
+			})
+
+			JustBeforeEach(func() {
+				err = pdb.RotateCustomMetricsCreds("an-app-id", "new-username", "new-password", previousExpireAt)
+				Expect(err).NotTo(HaveOccurred())
+				credentials, err = pdb.GetCustomMetricsCreds("an-app-id")
+			})
+
+			Context("when the previous credentials have not expired", func() {
+				BeforeEach(func() {
+					previousExpireAt = time.Now().Add(time.Hour).UnixNano()
+				})
+
+				It("should get both credentials", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credentials).To(Equal([]*models.CustomMetricCredentials{
+						{Username: "new-username", Password: "new-password"},
+						{Username: "username", Password: "password", ExpireAt: previousExpireAt},
+					}))
+				})
+			})
+
+			Context("when the previous credentials have expired", func() {
+				BeforeEach(func() {
+					previousExpireAt = time.Now().Add(-time.Second).UnixNano()
+				})
+
+				It("should get the new credentials only", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credentials).To(Equal([]*models.CustomMetricCredentials{{Username: "new-username", Password: "new-password"}}))
+				})
+			})
+		})
+	})
+
+	Describe("RotateCustomMetricsCreds", func() {
+		BeforeEach(func() {
+			pdb, err = NewPolicySQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanCredentialsTable()
+		})
+
+		AfterEach(func() {
+			err = pdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the app has no credentials", func() {
+			It("should error", func() {
+				err = pdb.RotateCustomMetricsCreds("an-app-id", "new-username", "new-password", 0)
+				Expect(err).To(Equal(db.ErrDoesNotExist))
+			})
+		})
+
+		Context("when the credentials are rotated twice", func() {
+			BeforeEach(func() {
+				insertCustomMetricsBindingCredentials("an-app-id", "username", "password")
+			})
+
+			It("should revoke the first credentials", func() {
+				expireAt := time.Now().Add(time.Hour).UnixNano()
+				Expect(pdb.RotateCustomMetricsCreds("an-app-id", "second-username", "second-password", expireAt)).To(Succeed())
+				Expect(pdb.RotateCustomMetricsCreds("an-app-id", "third-username", "third-password", expireAt)).To(Succeed())
+
+				credentials, err := pdb.GetCustomMetricsCreds("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials).To(Equal([]*models.CustomMetricCredentials{
+					{Username: "third-username", Password: "third-password"},
+					{Username: "second-username", Password: "second-password", ExpireAt: expireAt},
+				}))
+			})
+		})
+	})
+
+	Describe("Scaling groups", func() {
+		var group *models.ScalingGroup
+
+		BeforeEach(func() {
+			pdb, err = NewPolicySQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanScalingGroupTable()
+			group = &models.ScalingGroup{
+				GroupId: "a-group-id",
+				Members: []*models.ScalingGroupMember{
+					{AppId: "app-id-1", Ratio: 1},
+					{AppId: "app-id-2", Ratio: 0.5, Offset: 1},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			err = pdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the group is saved", func() {
+			BeforeEach(func() {
+				err = pdb.SaveScalingGroup(group)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the group of every member", func() {
+				Expect(pdb.GetScalingGroup("app-id-1")).To(Equal(group))
+				Expect(pdb.GetScalingGroup("app-id-2")).To(Equal(group))
+			})
+
+			It("replaces the members when the group is saved again", func() {
+				group.Members = group.Members[1:]
+				err = pdb.SaveScalingGroup(group)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdb.GetScalingGroup("app-id-1")).To(BeNil())
+				Expect(pdb.GetScalingGroup("app-id-2")).To(Equal(group))
 			})
 
+			It("fails when a member is in another group", func() {
+				err = pdb.SaveScalingGroup(&models.ScalingGroup{
+					GroupId: "another-group-id",
+					Members: []*models.ScalingGroupMember{{AppId: "app-id-1", Ratio: 1}},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(pdb.GetScalingGroup("app-id-1")).To(Equal(group))
+			})
+
+			It("deletes the group", func() {
+				err = pdb.DeleteScalingGroup("a-group-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdb.GetScalingGroup("app-id-1")).To(BeNil())
+			})
+		})
+
+		Context("when the app is not in a group", func() {
+			It("returns nil", func() {
+				Expect(pdb.GetScalingGroup("app-id-1")).To(BeNil())
+			})
 		})
 	})
+	Describe("App pauses", func() {
+		var pause *models.AppPause
+
+		BeforeEach(func() {
+			pdb, err = NewPolicySQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanAppPauseTable()
+			pause = &models.AppPause{AppId: "app-id-1", PausedBy: "a-user-id", Reason: "database migration", PausedAt: 111, ExpireAt: 222}
+		})
+
+		AfterEach(func() {
+			err = pdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the app is paused", func() {
+			BeforeEach(func() {
+				err = pdb.SaveAppPause(pause)
+				Expect(err).NotTo(HaveOccurred())
+			})
 
+			It("returns the pause", func() {
+				Expect(pdb.GetAppPause("app-id-1")).To(Equal(pause))
+				Expect(pdb.RetrieveAppPauses()).To(Equal(map[string]*models.AppPause{"app-id-1": pause}))
+			})
+
+			It("replaces the pause when the app is paused again", func() {
+				pause = &models.AppPause{AppId: "app-id-1", PausedBy: "another-user-id", PausedAt: 333}
+				err = pdb.SaveAppPause(pause)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdb.GetAppPause("app-id-1")).To(Equal(pause))
+			})
+
+			It("deletes the pause", func() {
+				err = pdb.DeleteAppPause("app-id-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdb.GetAppPause("app-id-1")).To(BeNil())
+			})
+
+			It("deletes the pause when it has expired", func() {
+				err = pdb.DeleteExpiredAppPause("app-id-1", 221)
+				Expect(err).To(Equal(db.ErrDoesNotExist))
+				Expect(pdb.GetAppPause("app-id-1")).To(Equal(pause))
+
+				err = pdb.DeleteExpiredAppPause("app-id-1", 222)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdb.GetAppPause("app-id-1")).To(BeNil())
+
+				err = pdb.DeleteExpiredAppPause("app-id-1", 222)
+				Expect(err).To(Equal(db.ErrDoesNotExist))
+			})
+		})
+
+		Context("when the app is not paused", func() {
+			It("returns nil", func() {
+				Expect(pdb.GetAppPause("app-id-1")).To(BeNil())
+				Expect(pdb.RetrieveAppPauses()).To(BeEmpty())
+			})
+		})
+	})
 })
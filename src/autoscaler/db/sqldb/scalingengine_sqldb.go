@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -52,10 +53,10 @@ func (sdb *ScalingEngineSQLDB) Close() error {
 
 func (sdb *ScalingEngineSQLDB) SaveScalingHistory(history *models.AppScalingHistory) error {
 	query := "INSERT INTO scalinghistory" +
-		"(appid, timestamp, scalingtype, status, oldinstances, newinstances, reason, message, error) " +
-		" VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)"
-	_, err := sdb.sqldb.Exec(query, history.AppId, history.Timestamp, history.ScalingType, history.Status,
-		history.OldInstances, history.NewInstances, history.Reason, history.Message, history.Error)
+		"(appid, timestamp, scalingtype, status, oldinstances, newinstances, reason, message, error, groupid) " +
+		" VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	err := sdb.sqldb.QueryRow(query, history.AppId, history.Timestamp, history.ScalingType, history.Status,
+		history.OldInstances, history.NewInstances, history.Reason, history.Message, history.Error, history.GroupId).Scan(&history.Id)
 
 	if err != nil {
 		sdb.logger.Error("save-scaling-history", err, lager.Data{"query": query, "history": history})
@@ -71,7 +72,7 @@ func (sdb *ScalingEngineSQLDB) RetrieveScalingHistories(appId string, start int6
 		orderStr = db.ASCSTR
 	}
 
-	query := "SELECT timestamp, scalingtype, status, oldinstances, newinstances, reason, message, error FROM scalinghistory WHERE" +
+	query := "SELECT id, timestamp, scalingtype, status, oldinstances, newinstances, reason, message, error, groupid FROM scalinghistory WHERE" +
 		" appid = $1 " +
 		" AND timestamp >= $2" +
 		" AND timestamp <= $3" +
@@ -91,17 +92,18 @@ func (sdb *ScalingEngineSQLDB) RetrieveScalingHistories(appId string, start int6
 
 	defer rows.Close()
 
-	var timestamp int64
+	var id, timestamp int64
 	var scalingType, status, oldInstances, newInstances int
-	var reason, message, errorMsg string
+	var reason, message, errorMsg, groupId string
 
 	for rows.Next() {
-		if err = rows.Scan(&timestamp, &scalingType, &status, &oldInstances, &newInstances, &reason, &message, &errorMsg); err != nil {
+		if err = rows.Scan(&id, &timestamp, &scalingType, &status, &oldInstances, &newInstances, &reason, &message, &errorMsg, &groupId); err != nil {
 			sdb.logger.Error("retrieve-scaling-history-scan", err)
 			return nil, err
 		}
 
 		history := models.AppScalingHistory{
+			Id:           id,
 			AppId:        appId,
 			Timestamp:    timestamp,
 			ScalingType:  models.ScalingType(scalingType),
@@ -111,6 +113,7 @@ func (sdb *ScalingEngineSQLDB) RetrieveScalingHistories(appId string, start int6
 			Reason:       reason,
 			Message:      message,
 			Error:        errorMsg,
+			GroupId:      groupId,
 		}
 
 		if includeAll || history.Status != models.ScalingStatusIgnored {
@@ -125,15 +128,94 @@ func (sdb *ScalingEngineSQLDB) PruneScalingHistories(before int64) error {
 	_, err := sdb.sqldb.Exec(query, before)
 	if err != nil {
 		sdb.logger.Error("failed-prune-scaling-histories-from-scalinghistory-table", err, lager.Data{"query": query, "before": before})
+		return err
+	}
+
+	query = "DELETE FROM scalingexplanation WHERE timestamp <= $1"
+	_, err = sdb.sqldb.Exec(query, before)
+	if err != nil {
+		sdb.logger.Error("failed-prune-scaling-explanations-from-scalingexplanation-table", err, lager.Data{"query": query, "before": before})
+		return err
+	}
+
+	query = "DELETE FROM scalingrecommendation WHERE timestamp <= $1"
+	_, err = sdb.sqldb.Exec(query, before)
+	if err != nil {
+		sdb.logger.Error("failed-prune-scaling-recommendations-from-scalingrecommendation-table", err, lager.Data{"query": query, "before": before})
+	}
+	return err
+}
+
+func (sdb *ScalingEngineSQLDB) SaveScalingExplanation(history *models.AppScalingHistory, explanation *models.ScalingExplanation) error {
+	explanationJson, err := json.Marshal(explanation)
+	if err != nil {
+		sdb.logger.Error("failed-marshal-scaling-explanation", err, lager.Data{"appid": history.AppId, "historyid": history.Id})
+		return err
+	}
+
+	query := "INSERT INTO scalingexplanation(historyid, appid, timestamp, explanation) VALUES($1, $2, $3, $4)"
+	_, err = sdb.sqldb.Exec(query, history.Id, history.AppId, history.Timestamp, string(explanationJson))
+	if err != nil {
+		sdb.logger.Error("failed-save-scaling-explanation", err, lager.Data{"query": query, "appid": history.AppId, "historyid": history.Id})
 	}
 	return err
 }
 
-func (sdb *ScalingEngineSQLDB) CanScaleApp(appId string) (bool, int64, error) {
-	query := "SELECT expireat FROM scalingcooldown WHERE appid = $1"
-	rows, err := sdb.sqldb.Query(query, appId)
+func (sdb *ScalingEngineSQLDB) RetrieveScalingExplanation(appId string, historyId int64) (*models.ScalingExplanation, error) {
+	query := "SELECT explanation FROM scalingexplanation WHERE appid = $1 AND historyid = $2"
+	var explanationJson string
+	err := sdb.sqldb.QueryRow(query, appId, historyId).Scan(&explanationJson)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		sdb.logger.Error("can-scale-app-query-record", err, lager.Data{"query": query, "appid": appId})
+		sdb.logger.Error("failed-retrieve-scaling-explanation", err, lager.Data{"query": query, "appid": appId, "historyid": historyId})
+		return nil, err
+	}
+
+	explanation := &models.ScalingExplanation{}
+	err = json.Unmarshal([]byte(explanationJson), explanation)
+	if err != nil {
+		sdb.logger.Error("failed-unmarshal-scaling-explanation", err, lager.Data{"appid": appId, "historyid": historyId})
+		return nil, err
+	}
+	return explanation, nil
+}
+
+func (sdb *ScalingEngineSQLDB) SaveScalingRecommendation(appId string, timestamp int64, instances int) error {
+	query := "INSERT INTO scalingrecommendation(appid, timestamp, instances) VALUES($1, $2, $3)"
+	_, err := sdb.sqldb.Exec(query, appId, timestamp, instances)
+	if err != nil {
+		sdb.logger.Error("failed-save-scaling-recommendation", err, lager.Data{"query": query, "appid": appId, "timestamp": timestamp, "instances": instances})
+	}
+	return err
+}
+
+// GetMaxScalingRecommendation returns the highest instance count recommended for the app since start, or -1 when
+// there is no recommendation.
+func (sdb *ScalingEngineSQLDB) GetMaxScalingRecommendation(appId string, start int64) (int, error) {
+	query := "SELECT MAX(instances) FROM scalingrecommendation WHERE appid = $1 AND timestamp >= $2"
+	var instances sql.NullInt64
+	err := sdb.sqldb.QueryRow(query, appId, start).Scan(&instances)
+	if err != nil {
+		sdb.logger.Error("failed-get-max-scaling-recommendation", err, lager.Data{"query": query, "appid": appId, "start": start})
+		return -1, err
+	}
+	if !instances.Valid {
+		return -1, nil
+	}
+	return int(instances.Int64), nil
+}
+
+// CanScaleApp checks the cooldown of the app in the scaling direction, the rule key is empty unless the cooldown is
+// tracked per rule. The records without direction are written before cooldowns are tracked per direction, they apply
+// to both directions and all rules.
+func (sdb *ScalingEngineSQLDB) CanScaleApp(appId string, direction string, ruleKey string) (bool, int64, error) {
+	query := "SELECT expireat FROM scalingcooldown WHERE appid = $1 AND ((direction = $2 AND rulekey = $3) OR direction = '') " +
+		"ORDER BY expireat DESC"
+	rows, err := sdb.sqldb.Query(query, appId, direction, ruleKey)
+	if err != nil {
+		sdb.logger.Error("can-scale-app-query-record", err, lager.Data{"query": query, "appid": appId, "direction": direction, "ruleKey": ruleKey})
 		return false, 0, err
 	}
 	defer rows.Close()
@@ -141,7 +223,7 @@ func (sdb *ScalingEngineSQLDB) CanScaleApp(appId string) (bool, int64, error) {
 	var expireAt int64 = 0
 	if rows.Next() {
 		if err = rows.Scan(&expireAt); err != nil {
-			sdb.logger.Error("can-scale-app-scan", err, lager.Data{"query": query, "appid": appId})
+			sdb.logger.Error("can-scale-app-scan", err, lager.Data{"query": query, "appid": appId, "direction": direction, "ruleKey": ruleKey})
 			return false, expireAt, err
 		}
 		if expireAt < time.Now().UnixNano() {
@@ -153,29 +235,31 @@ func (sdb *ScalingEngineSQLDB) CanScaleApp(appId string) (bool, int64, error) {
 	return true, expireAt, nil
 }
 
-func (sdb *ScalingEngineSQLDB) UpdateScalingCooldownExpireTime(appId string, expireAt int64) error {
-	_, err := sdb.sqldb.Exec("DELETE FROM scalingcooldown WHERE appid = $1", appId)
+func (sdb *ScalingEngineSQLDB) UpdateScalingCooldownExpireTime(appId string, direction string, ruleKey string, expireAt int64) error {
+	_, err := sdb.sqldb.Exec("DELETE FROM scalingcooldown WHERE appid = $1 AND ((direction = $2 AND rulekey = $3) OR (direction = '' AND expireat < $4))",
+		appId, direction, ruleKey, time.Now().UnixNano())
 	if err != nil {
-		sdb.logger.Error("update-scaling-cooldown-time-delete", err, lager.Data{"appid": appId})
+		sdb.logger.Error("update-scaling-cooldown-time-delete", err, lager.Data{"appid": appId, "direction": direction, "ruleKey": ruleKey})
 		return err
 	}
 
-	_, err = sdb.sqldb.Exec("INSERT INTO scalingcooldown(appid, expireat) values($1, $2)", appId, expireAt)
+	_, err = sdb.sqldb.Exec("INSERT INTO scalingcooldown(appid, direction, rulekey, expireat) values($1, $2, $3, $4)", appId, direction, ruleKey, expireAt)
 	if err != nil {
-		sdb.logger.Error("update-scaling-cooldown-time-insert", err, lager.Data{"appid": appId, "expireAt": expireAt})
+		sdb.logger.Error("update-scaling-cooldown-time-insert", err, lager.Data{"appid": appId, "direction": direction, "ruleKey": ruleKey, "expireAt": expireAt})
 		return err
 	}
 	return nil
 }
 
 func (sdb *ScalingEngineSQLDB) GetActiveSchedule(appId string) (*models.ActiveSchedule, error) {
-	query := "SELECT scheduleid, instancemincount, instancemaxcount, initialmininstancecount" +
+	query := "SELECT scheduleid, instancemincount, instancemaxcount, initialmininstancecount, override" +
 		" FROM activeschedule WHERE appid = $1"
 
 	var scheduleId string
 	var instanceMin, instanceMax, instanceMinInitial int
+	var override sql.NullString
 
-	err := sdb.sqldb.QueryRow(query, appId).Scan(&scheduleId, &instanceMin, &instanceMax, &instanceMinInitial)
+	err := sdb.sqldb.QueryRow(query, appId).Scan(&scheduleId, &instanceMin, &instanceMax, &instanceMinInitial, &override)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -184,12 +268,20 @@ func (sdb *ScalingEngineSQLDB) GetActiveSchedule(appId string) (*models.ActiveSc
 		return nil, err
 	}
 
-	return &models.ActiveSchedule{
+	schedule := &models.ActiveSchedule{
 		ScheduleId:         scheduleId,
 		InstanceMin:        instanceMin,
 		InstanceMax:        instanceMax,
 		InstanceMinInitial: instanceMinInitial,
-	}, nil
+	}
+	if override.Valid && override.String != "" {
+		err = json.Unmarshal([]byte(override.String), &schedule.Override)
+		if err != nil {
+			sdb.logger.Error("failed-get-active-schedule-unmarshal-override", err, lager.Data{"appid": appId, "override": override.String})
+			return nil, err
+		}
+	}
+	return schedule, nil
 }
 
 func (sdb *ScalingEngineSQLDB) GetActiveSchedules() (map[string]string, error) {
@@ -229,9 +321,19 @@ func (sdb *ScalingEngineSQLDB) SetActiveSchedule(appId string, schedule *models.
 		return err
 	}
 
-	query := "INSERT INTO activeschedule(appid, scheduleid, instancemincount, instancemaxcount, initialmininstancecount) " +
-		" VALUES ($1, $2, $3, $4, $5)"
-	_, err = sdb.sqldb.Exec(query, appId, schedule.ScheduleId, schedule.InstanceMin, schedule.InstanceMax, schedule.InstanceMinInitial)
+	var override sql.NullString
+	if schedule.Override != nil {
+		overrideJson, err := json.Marshal(schedule.Override)
+		if err != nil {
+			sdb.logger.Error("failed-set-active-schedule-marshal-override", err, lager.Data{"appid": appId, "schedule": schedule})
+			return err
+		}
+		override = sql.NullString{String: string(overrideJson), Valid: true}
+	}
+
+	query := "INSERT INTO activeschedule(appid, scheduleid, instancemincount, instancemaxcount, initialmininstancecount, override) " +
+		" VALUES ($1, $2, $3, $4, $5, $6)"
+	_, err = sdb.sqldb.Exec(query, appId, schedule.ScheduleId, schedule.InstanceMin, schedule.InstanceMax, schedule.InstanceMinInitial, override)
 
 	if err != nil {
 		sdb.logger.Error("failed-set-active-scheudle-insert", err, lager.Data{"appid": appId, "schedule": schedule})
@@ -239,6 +341,88 @@ func (sdb *ScalingEngineSQLDB) SetActiveSchedule(appId string, schedule *models.
 	return err
 }
 
+// SaveScalingHistoryWithWebhookDeliveries saves the history and the webhook deliveries of its notification in the
+// outbox in one transaction, so that the notification is not lost when the scaling engine stops in between.
+func (sdb *ScalingEngineSQLDB) SaveScalingHistoryWithWebhookDeliveries(history *models.AppScalingHistory, deliveries []*models.WebhookDelivery) error {
+	txn, err := sdb.sqldb.Begin()
+	if err != nil {
+		sdb.logger.Error("save-scaling-history-start-transaction", err, lager.Data{"appid": history.AppId})
+		return err
+	}
+	defer txn.Rollback()
+
+	query := "INSERT INTO scalinghistory" +
+		"(appid, timestamp, scalingtype, status, oldinstances, newinstances, reason, message, error, groupid) " +
+		" VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	err = txn.QueryRow(query, history.AppId, history.Timestamp, history.ScalingType, history.Status,
+		history.OldInstances, history.NewInstances, history.Reason, history.Message, history.Error, history.GroupId).Scan(&history.Id)
+	if err != nil {
+		sdb.logger.Error("save-scaling-history", err, lager.Data{"query": query, "history": history})
+		return err
+	}
+
+	query = "INSERT INTO webhook_outbox(appid, url, payload, signature, attempts, nextattemptat, lasterror, createdat) " +
+		" VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	for _, delivery := range deliveries {
+		_, err = txn.Exec(query, delivery.AppId, delivery.URL, delivery.Payload, delivery.Signature,
+			delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.CreatedAt)
+		if err != nil {
+			sdb.logger.Error("failed-save-webhook-delivery", err, lager.Data{"query": query, "appid": delivery.AppId, "url": delivery.URL})
+			return err
+		}
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		sdb.logger.Error("save-scaling-history-commit", err, lager.Data{"appid": history.AppId})
+	}
+	return err
+}
+
+// ClaimWebhookDeliveries returns the deliveries due at now and postpones them to leaseUntil, so that they are not
+// claimed again by another scaling engine while being delivered.
+func (sdb *ScalingEngineSQLDB) ClaimWebhookDeliveries(now int64, leaseUntil int64, limit int) ([]*models.WebhookDelivery, error) {
+	query := "UPDATE webhook_outbox SET nextattemptat = $1 WHERE id IN (" +
+		" SELECT id FROM webhook_outbox WHERE nextattemptat <= $2 ORDER BY nextattemptat LIMIT $3 FOR UPDATE SKIP LOCKED)" +
+		" RETURNING id, appid, url, payload, signature, attempts, nextattemptat, lasterror, createdat"
+	rows, err := sdb.sqldb.Query(query, leaseUntil, now, limit)
+	if err != nil {
+		sdb.logger.Error("failed-claim-webhook-deliveries", err, lager.Data{"query": query, "now": now, "limit": limit})
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery := models.WebhookDelivery{}
+		if err = rows.Scan(&delivery.Id, &delivery.AppId, &delivery.URL, &delivery.Payload, &delivery.Signature,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError, &delivery.CreatedAt); err != nil {
+			sdb.logger.Error("failed-claim-webhook-deliveries-scan", err, lager.Data{"query": query})
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (sdb *ScalingEngineSQLDB) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	query := "UPDATE webhook_outbox SET attempts = $1, nextattemptat = $2, lasterror = $3 WHERE id = $4"
+	_, err := sdb.sqldb.Exec(query, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.Id)
+	if err != nil {
+		sdb.logger.Error("failed-update-webhook-delivery", err, lager.Data{"query": query, "id": delivery.Id})
+	}
+	return err
+}
+
+func (sdb *ScalingEngineSQLDB) DeleteWebhookDelivery(id int64) error {
+	query := "DELETE FROM webhook_outbox WHERE id = $1"
+	_, err := sdb.sqldb.Exec(query, id)
+	if err != nil {
+		sdb.logger.Error("failed-delete-webhook-delivery", err, lager.Data{"query": query, "id": id})
+	}
+	return err
+}
+
 func (sdb *ScalingEngineSQLDB) GetDBStatus() sql.DBStats {
 	return sdb.sqldb.Stats()
 }
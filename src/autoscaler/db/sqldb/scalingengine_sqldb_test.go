@@ -32,6 +32,9 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		schedules         map[string]string
 		before            int64
 		includeAll        bool
+		deliveries        []*models.WebhookDelivery
+		historyIds        map[int64]int64
+		explanation       *models.ScalingExplanation
 	)
 
 	BeforeEach(func() {
@@ -105,6 +108,33 @@ var _ = Describe("ScalingEngineSqldb", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasScalingHistory("an-app-id", 111111)).To(BeTrue())
 			})
+
+			It("sets the id of the history", func() {
+				Expect(history.Id).NotTo(BeZero())
+			})
+		})
+
+		Context("When inserting a scaling history record of a scaling group member", func() {
+			BeforeEach(func() {
+				history = &models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    111111,
+					ScalingType:  models.ScalingTypeDynamic,
+					Status:       models.ScalingStatusSucceeded,
+					OldInstances: 2,
+					NewInstances: 4,
+					Reason:       "a reason",
+					GroupId:      "a-group-id",
+				}
+				err = sdb.SaveScalingHistory(history)
+			})
+
+			It("keeps the group id of the history", func() {
+				Expect(err).NotTo(HaveOccurred())
+				histories, err := sdb.RetrieveScalingHistories("an-app-id", 0, -1, db.ASC, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(histories).To(Equal([]*models.AppScalingHistory{history}))
+			})
 		})
 
 		Context("When inserting multiple scaling history records of an app", func() {
@@ -166,6 +196,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		})
 
 		JustBeforeEach(func() {
+			historyIds = map[int64]int64{}
 			history = &models.AppScalingHistory{
 				AppId:        "an-app-id",
 				OldInstances: 2,
@@ -180,6 +211,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			history.Error = ""
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			historyIds[history.Timestamp] = history.Id
 
 			history.Timestamp = 222222
 			history.ScalingType = models.ScalingTypeDynamic
@@ -187,6 +219,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			history.Error = "an error"
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			historyIds[history.Timestamp] = history.Id
 
 			history.Timestamp = 555555
 			history.ScalingType = models.ScalingTypeSchedule
@@ -194,6 +227,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			history.Error = "an error"
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			historyIds[history.Timestamp] = history.Id
 
 			history.Timestamp = 333333
 			history.ScalingType = models.ScalingTypeSchedule
@@ -201,6 +235,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			history.Error = ""
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			historyIds[history.Timestamp] = history.Id
 
 			histories, err = sdb.RetrieveScalingHistories(appId, start, end, orderType, includeAll)
 		})
@@ -276,6 +311,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(histories).To(Equal([]*models.AppScalingHistory{
 					&models.AppScalingHistory{
+						Id:           historyIds[222222],
 						AppId:        "an-app-id",
 						Timestamp:    222222,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -287,6 +323,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Error:        "an error",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[333333],
 						AppId:        "an-app-id",
 						Timestamp:    333333,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -297,6 +334,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Message:      "a message",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[555555],
 						AppId:        "an-app-id",
 						Timestamp:    555555,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -308,6 +346,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Error:        "an error",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[666666],
 						AppId:        "an-app-id",
 						Timestamp:    666666,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -328,6 +367,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(histories).To(Equal([]*models.AppScalingHistory{
 					&models.AppScalingHistory{
+						Id:           historyIds[666666],
 						AppId:        "an-app-id",
 						Timestamp:    666666,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -338,6 +378,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Message:      "a message",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[555555],
 						AppId:        "an-app-id",
 						Timestamp:    555555,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -349,6 +390,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Error:        "an error",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[333333],
 						AppId:        "an-app-id",
 						Timestamp:    333333,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -359,6 +401,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Message:      "a message",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[222222],
 						AppId:        "an-app-id",
 						Timestamp:    222222,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -385,6 +428,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(histories).To(Equal([]*models.AppScalingHistory{
 					&models.AppScalingHistory{
+						Id:           historyIds[555555],
 						AppId:        "an-app-id",
 						Timestamp:    555555,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -395,6 +439,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Message:      "a message",
 						Error:        "an error",
 					}, &models.AppScalingHistory{
+						Id:           historyIds[333333],
 						AppId:        "an-app-id",
 						Timestamp:    333333,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -418,6 +463,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(histories).To(Equal([]*models.AppScalingHistory{
 					&models.AppScalingHistory{
+						Id:           historyIds[666666],
 						AppId:        "an-app-id",
 						Timestamp:    666666,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -428,6 +474,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Message:      "a message",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[555555],
 						AppId:        "an-app-id",
 						Timestamp:    555555,
 						ScalingType:  models.ScalingTypeSchedule,
@@ -439,6 +486,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 						Error:        "an error",
 					},
 					&models.AppScalingHistory{
+						Id:           historyIds[222222],
 						AppId:        "an-app-id",
 						Timestamp:    222222,
 						ScalingType:  models.ScalingTypeDynamic,
@@ -458,15 +506,26 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			sdb, err = NewScalingEngineSQLDB(dbConfig, logger)
 			Expect(err).NotTo(HaveOccurred())
 			cleanScalingHistoryTable()
+			cleanScalingExplanationTable()
+			cleanScalingRecommendationTable()
+
+			err = sdb.SaveScalingRecommendation("an-app-id", 222222, 3)
+			Expect(err).NotTo(HaveOccurred())
+			err = sdb.SaveScalingRecommendation("an-app-id", 555555, 2)
+			Expect(err).NotTo(HaveOccurred())
 
 			history = &models.AppScalingHistory{}
 			history.Timestamp = 666666
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			err = sdb.SaveScalingExplanation(history, &models.ScalingExplanation{})
+			Expect(err).NotTo(HaveOccurred())
 
 			history.Timestamp = 222222
 			err = sdb.SaveScalingHistory(history)
 			Expect(err).NotTo(HaveOccurred())
+			err = sdb.SaveScalingExplanation(history, &models.ScalingExplanation{})
+			Expect(err).NotTo(HaveOccurred())
 
 			history.Timestamp = 555555
 			err = sdb.SaveScalingHistory(history)
@@ -494,6 +553,8 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			It("does not remove any histories", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(getNumberOfScalingHistories()).To(Equal(4))
+				Expect(getNumberOfScalingExplanations()).To(Equal(2))
+				Expect(getNumberOfScalingRecommendations()).To(Equal(2))
 			})
 		})
 
@@ -505,6 +566,8 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			It("empties the scalinghistory table", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(getNumberOfScalingHistories()).To(Equal(0))
+				Expect(getNumberOfScalingExplanations()).To(Equal(0))
+				Expect(getNumberOfScalingRecommendations()).To(Equal(0))
 			})
 		})
 
@@ -516,6 +579,8 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			It("removes histories before the time specified", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(getNumberOfScalingHistories()).To(Equal(2))
+				Expect(getNumberOfScalingExplanations()).To(Equal(1))
+				Expect(getNumberOfScalingRecommendations()).To(Equal(1))
 			})
 		})
 
@@ -532,6 +597,130 @@ var _ = Describe("ScalingEngineSqldb", func() {
 
 	})
 
+	Describe("Scaling explanations", func() {
+		BeforeEach(func() {
+			sdb, err = NewScalingEngineSQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			cleanScalingHistoryTable()
+			cleanScalingExplanationTable()
+
+			history = &models.AppScalingHistory{
+				AppId:       "an-app-id",
+				Timestamp:   111111,
+				ScalingType: models.ScalingTypeDynamic,
+				Status:      models.ScalingStatusSucceeded,
+			}
+			err = sdb.SaveScalingHistory(history)
+			Expect(err).NotTo(HaveOccurred())
+
+			explanation = &models.ScalingExplanation{
+				Trigger: &models.Trigger{AppId: "an-app-id", MetricType: "memoryused", Operator: ">", Threshold: 80, Adjustment: "+1"},
+				Comparisons: []*models.MetricComparison{{
+					MetricType:   "memoryused",
+					Aggregation:  models.AggregationAvg,
+					Operator:     ">",
+					Threshold:    80,
+					MetricPoints: []*models.AppMetric{{AppId: "an-app-id", MetricType: "memoryused", Value: 90, Unit: "MB", Timestamp: 111110}},
+					Breached:     true,
+				}},
+				Cooldown:          &models.CooldownStatus{InCooldown: false, ExpireAt: 100000},
+				CurrentInstances:  2,
+				ComputedInstances: 3,
+				InstanceMin:       1,
+				InstanceMax:       5,
+				NewInstances:      3,
+			}
+			err = sdb.SaveScalingExplanation(history, explanation)
+		})
+
+		AfterEach(func() {
+			err = sdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("retrieves the explanation of the history", func() {
+			Expect(err).NotTo(HaveOccurred())
+			result, err := sdb.RetrieveScalingExplanation("an-app-id", history.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(explanation))
+		})
+
+		Context("when the history belongs to another app", func() {
+			It("returns nil", func() {
+				result, err := sdb.RetrieveScalingExplanation("another-app-id", history.Id)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeNil())
+			})
+		})
+
+		Context("when there is database error", func() {
+			It("should error", func() {
+				sdb.Close()
+				_, err := sdb.RetrieveScalingExplanation("an-app-id", history.Id)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Scaling recommendations", func() {
+		var (
+			instances int
+			start     int64
+		)
+
+		BeforeEach(func() {
+			sdb, err = NewScalingEngineSQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			cleanScalingRecommendationTable()
+		})
+
+		AfterEach(func() {
+			err = sdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			instances, err = sdb.GetMaxScalingRecommendation("an-app-id", start)
+		})
+
+		Context("when there are recommendations", func() {
+			BeforeEach(func() {
+				start = 200000
+				Expect(sdb.SaveScalingRecommendation("an-app-id", 100000, 9)).To(Succeed())
+				Expect(sdb.SaveScalingRecommendation("an-app-id", 200000, 4)).To(Succeed())
+				Expect(sdb.SaveScalingRecommendation("an-app-id", 300000, 6)).To(Succeed())
+				Expect(sdb.SaveScalingRecommendation("another-app-id", 300000, 8)).To(Succeed())
+			})
+
+			It("returns the highest recommendation of the app since start", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(instances).To(Equal(6))
+			})
+		})
+
+		Context("when there is no recommendation since start", func() {
+			BeforeEach(func() {
+				start = 200000
+				Expect(sdb.SaveScalingRecommendation("an-app-id", 100000, 9)).To(Succeed())
+			})
+
+			It("returns -1", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(instances).To(Equal(-1))
+			})
+		})
+
+		Context("when there is database error", func() {
+			BeforeEach(func() {
+				sdb.Close()
+			})
+
+			It("should error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
 	Describe("UpdateScalingCooldownExpireTime", func() {
 		BeforeEach(func() {
 			sdb, err = NewScalingEngineSQLDB(dbConfig, logger)
@@ -545,26 +734,55 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		})
 
 		JustBeforeEach(func() {
-			err = sdb.UpdateScalingCooldownExpireTime("an-app-id", 222222)
+			err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "", 222222)
 		})
 
 		Context("when there is no previous app cooldown record", func() {
 			It("creates the record", func() {
 				Expect(err).NotTo(HaveOccurred())
-				Expect(hasScalingCooldownRecord("an-app-id", 222222)).To(BeTrue())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionOut, "", 222222)).To(BeTrue())
 			})
 		})
 
 		Context("when there is previous app cooldown record", func() {
 			BeforeEach(func() {
-				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", 111111)
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "", 111111)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("removes the previous record and inserts a new record", func() {
 				Expect(err).NotTo(HaveOccurred())
-				Expect(hasScalingCooldownRecord("an-app-id", 111111)).To(BeFalse())
-				Expect(hasScalingCooldownRecord("an-app-id", 222222)).To(BeTrue())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionOut, "", 111111)).To(BeFalse())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionOut, "", 222222)).To(BeTrue())
+			})
+		})
+
+		Context("when there are cooldown records of the other direction and of a rule", func() {
+			BeforeEach(func() {
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionIn, "", 111111)
+				Expect(err).NotTo(HaveOccurred())
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "memoryused", 111111)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("keeps the records", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionIn, "", 111111)).To(BeTrue())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionOut, "memoryused", 111111)).To(BeTrue())
+				Expect(hasScalingCooldownRecord("an-app-id", models.ScalingDirectionOut, "", 222222)).To(BeTrue())
+			})
+		})
+
+		Context("when there are cooldown records without direction", func() {
+			BeforeEach(func() {
+				insertLegacyScalingCooldownRecord("an-app-id", 111111)
+				insertLegacyScalingCooldownRecord("an-app-id", time.Now().Add(100*time.Second).UnixNano())
+			})
+
+			It("removes the expired records only", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasScalingCooldownRecord("an-app-id", "", "", 111111)).To(BeFalse())
+				Expect(getNumberOfScalingCooldownRecords("an-app-id")).To(Equal(2))
 			})
 		})
 	})
@@ -582,7 +800,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		})
 
 		JustBeforeEach(func() {
-			canScale, cooldownExpiredAt, err = sdb.CanScaleApp("an-app-id")
+			canScale, cooldownExpiredAt, err = sdb.CanScaleApp("an-app-id", models.ScalingDirectionOut, "")
 		})
 
 		Context("when there is no cooldown record before", func() {
@@ -596,7 +814,7 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		Context("when the app is still in cooldown period", func() {
 			fakeCoolDownExpiredTime := time.Now().Add(100 * time.Second).UnixNano()
 			BeforeEach(func() {
-				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", fakeCoolDownExpiredTime)
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "", fakeCoolDownExpiredTime)
 				Expect(err).NotTo(HaveOccurred())
 			})
 			It("returns false", func() {
@@ -609,12 +827,39 @@ var _ = Describe("ScalingEngineSqldb", func() {
 		Context("when the app passes cooldown period", func() {
 			fakeCoolDownExpiredTime := time.Now().Add(0 - 100*time.Second).UnixNano()
 			BeforeEach(func() {
-				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", fakeCoolDownExpiredTime)
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "", fakeCoolDownExpiredTime)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("returns true", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(canScale).To(BeTrue())
+				Expect(cooldownExpiredAt).To(Equal(fakeCoolDownExpiredTime))
+			})
+		})
+
+		Context("when the app is in cooldown period of the other direction or of a rule only", func() {
+			fakeCoolDownExpiredTime := time.Now().Add(100 * time.Second).UnixNano()
+			BeforeEach(func() {
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionIn, "", fakeCoolDownExpiredTime)
+				Expect(err).NotTo(HaveOccurred())
+				err = sdb.UpdateScalingCooldownExpireTime("an-app-id", models.ScalingDirectionOut, "memoryused", fakeCoolDownExpiredTime)
 				Expect(err).NotTo(HaveOccurred())
 			})
 			It("returns true", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(canScale).To(BeTrue())
+				Expect(cooldownExpiredAt).To(Equal(int64(0)))
+			})
+		})
+
+		Context("when the app is in cooldown period of a record without direction", func() {
+			fakeCoolDownExpiredTime := time.Now().Add(100 * time.Second).UnixNano()
+			BeforeEach(func() {
+				insertLegacyScalingCooldownRecord("an-app-id", fakeCoolDownExpiredTime)
+			})
+			It("returns false", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(canScale).To(BeFalse())
 				Expect(cooldownExpiredAt).To(Equal(fakeCoolDownExpiredTime))
 			})
 		})
@@ -813,6 +1058,22 @@ var _ = Describe("ScalingEngineSqldb", func() {
 			})
 		})
 
+		Context("when the active schedule has an override", func() {
+			BeforeEach(func() {
+				activeSchedule.Override = &models.ScheduleOverride{
+					Mode:         models.ScheduleOverrideRules,
+					ScalingRules: []*models.ScalingRule{{MetricType: "cpu", Threshold: 90, Operator: ">", Adjustment: "+2"}},
+				}
+			})
+
+			It("should insert the active schedule with the override", func() {
+				Expect(err).NotTo(HaveOccurred())
+				schedule, err := sdb.GetActiveSchedule("an-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedule).To(Equal(activeSchedule))
+			})
+		})
+
 		Context("when there is database error", func() {
 			BeforeEach(func() {
 				sdb.Close()
@@ -824,4 +1085,93 @@ var _ = Describe("ScalingEngineSqldb", func() {
 
 	})
 
+	Describe("Webhook deliveries", func() {
+		BeforeEach(func() {
+			sdb, err = NewScalingEngineSQLDB(dbConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			err = cleanWebhookOutboxTable()
+			Expect(err).NotTo(HaveOccurred())
+			cleanScalingHistoryTable()
+
+			err = sdb.SaveScalingHistoryWithWebhookDeliveries(&models.AppScalingHistory{AppId: "an-app-id", Timestamp: 100}, []*models.WebhookDelivery{
+				{AppId: "an-app-id", URL: "https://example.com/1", Payload: "payload-1", Signature: "sha256=1", NextAttemptAt: 100, CreatedAt: 100},
+				{AppId: "an-app-id", URL: "https://example.com/2", Payload: "payload-2", Signature: "sha256=2", NextAttemptAt: 200, CreatedAt: 100},
+				{AppId: "an-app-id", URL: "https://example.com/3", Payload: "payload-3", Signature: "sha256=3", NextAttemptAt: 300, CreatedAt: 100},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err = sdb.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Describe("SaveScalingHistoryWithWebhookDeliveries", func() {
+			It("should save the history and the deliveries", func() {
+				Expect(hasScalingHistory("an-app-id", 100)).To(BeTrue())
+				Expect(getNumberOfWebhookDeliveries()).To(Equal(3))
+			})
+		})
+
+		Describe("ClaimWebhookDeliveries", func() {
+			It("should return the due deliveries and lease them", func() {
+				deliveries, err = sdb.ClaimWebhookDeliveries(200, 1000, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(2))
+				Expect(deliveries[0].URL).To(Equal("https://example.com/1"))
+				Expect(deliveries[0].Payload).To(Equal("payload-1"))
+				Expect(deliveries[0].Signature).To(Equal("sha256=1"))
+				Expect(deliveries[0].NextAttemptAt).To(Equal(int64(1000)))
+				Expect(deliveries[1].URL).To(Equal("https://example.com/2"))
+
+				deliveries, err = sdb.ClaimWebhookDeliveries(300, 1000, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(1))
+				Expect(deliveries[0].URL).To(Equal("https://example.com/3"))
+			})
+
+			It("should return at most limit deliveries", func() {
+				deliveries, err = sdb.ClaimWebhookDeliveries(300, 1000, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(1))
+			})
+		})
+
+		Describe("UpdateWebhookDelivery", func() {
+			It("should update the attempts of the delivery", func() {
+				deliveries, err = sdb.ClaimWebhookDeliveries(100, 1000, 10)
+				Expect(err).NotTo(HaveOccurred())
+				deliveries[0].Attempts = 1
+				deliveries[0].NextAttemptAt = 150
+				deliveries[0].LastError = "an error"
+				Expect(sdb.UpdateWebhookDelivery(deliveries[0])).To(Succeed())
+
+				deliveries, err = sdb.ClaimWebhookDeliveries(150, 1000, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deliveries).To(HaveLen(1))
+				Expect(deliveries[0].Attempts).To(Equal(1))
+				Expect(deliveries[0].LastError).To(Equal("an error"))
+			})
+		})
+
+		Describe("DeleteWebhookDelivery", func() {
+			It("should delete the delivery", func() {
+				deliveries, err = sdb.ClaimWebhookDeliveries(100, 1000, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sdb.DeleteWebhookDelivery(deliveries[0].Id)).To(Succeed())
+				Expect(getNumberOfWebhookDeliveries()).To(Equal(2))
+			})
+		})
+
+		Context("when there is database error", func() {
+			BeforeEach(func() {
+				sdb.Close()
+			})
+			It("should error", func() {
+				_, err = sdb.ClaimWebhookDeliveries(100, 1000, 10)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
 })
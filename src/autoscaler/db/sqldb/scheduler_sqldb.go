@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 
 	"database/sql"
+	"encoding/json"
 	"strconv"
 )
 
@@ -52,7 +53,7 @@ func (sdb *SchedulerSQLDB) Close() error {
 }
 
 func (sdb *SchedulerSQLDB) GetActiveSchedules() (map[string]*models.ActiveSchedule, error) {
-	query := "SELECT id, app_id, instance_min_count, instance_max_count, initial_min_instance_count FROM app_scaling_active_schedule"
+	query := "SELECT id, app_id, instance_min_count, instance_max_count, initial_min_instance_count, override FROM app_scaling_active_schedule"
 	rows, err := sdb.sqldb.Query(query)
 	if err != nil {
 		sdb.logger.Error("failed-get-active-schedules-query", err, lager.Data{"query": query})
@@ -65,8 +66,9 @@ func (sdb *SchedulerSQLDB) GetActiveSchedules() (map[string]*models.ActiveSchedu
 	var appId string
 	var instanceMin, instanceMax int
 	minInitial := sql.NullInt64{}
+	override := sql.NullString{}
 	for rows.Next() {
-		if err = rows.Scan(&id, &appId, &instanceMin, &instanceMax, &minInitial); err != nil {
+		if err = rows.Scan(&id, &appId, &instanceMin, &instanceMax, &minInitial, &override); err != nil {
 			sdb.logger.Error("failed-get-active-schedules-scan", err)
 			return nil, err
 		}
@@ -81,6 +83,12 @@ func (sdb *SchedulerSQLDB) GetActiveSchedules() (map[string]*models.ActiveSchedu
 			InstanceMax:        instanceMax,
 			InstanceMinInitial: instanceMinInitial,
 		}
+		if override.Valid && override.String != "" {
+			if err = json.Unmarshal([]byte(override.String), &schedule.Override); err != nil {
+				sdb.logger.Error("failed-get-active-schedules-unmarshal-override", err, lager.Data{"appid": appId, "override": override.String})
+				return nil, err
+			}
+		}
 		schedules[appId] = &schedule
 	}
 	return schedules, nil
@@ -119,6 +119,26 @@ var _ = Describe("SchedulerSqldb", func() {
 				}))
 			})
 		})
+
+		Context("when an active schedule has an override", func() {
+			BeforeEach(func() {
+				err = insertSchedulerActiveSchedule(111111, "app-id-1", 1, 2, 10, 5)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = dbHelper.Exec("UPDATE app_scaling_active_schedule SET override = $1 WHERE id = $2", `{"mode":"pin","instance_count":4}`, 111111)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the active schedule with the override", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedules).To(HaveKeyWithValue("app-id-1", &models.ActiveSchedule{
+					ScheduleId:         "111111",
+					InstanceMin:        2,
+					InstanceMax:        10,
+					InstanceMinInitial: 5,
+					Override:           &models.ScheduleOverride{Mode: models.ScheduleOverridePin, InstanceCount: 4},
+				}))
+			})
+		})
 		Context("when there is database error", func() {
 			BeforeEach(func() {
 				sdb.Close()
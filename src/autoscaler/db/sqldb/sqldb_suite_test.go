@@ -121,6 +121,27 @@ func cleanPolicyTable() {
 	}
 }
 
+func cleanPolicyVersionTable() {
+	_, e := dbHelper.Exec("DELETE from policy_version")
+	if e != nil {
+		Fail("can not clean table policy_version: " + e.Error())
+	}
+}
+
+func cleanAppPauseTable() {
+	_, e := dbHelper.Exec("DELETE from app_pause")
+	if e != nil {
+		Fail("can not clean table app_pause: " + e.Error())
+	}
+}
+
+func cleanScalingGroupTable() {
+	_, e := dbHelper.Exec("DELETE from scaling_group")
+	if e != nil {
+		Fail("can not clean table scaling_group: " + e.Error())
+	}
+}
+
 func insertPolicy(appId string, scalingPolicy *models.ScalingPolicy) {
 	policyJson, e := json.Marshal(scalingPolicy)
 	if e != nil {
@@ -159,6 +180,13 @@ func cleanAppMetricTable() {
 	}
 }
 
+func cleanAppMetricAnomalyTable() {
+	_, e := dbHelper.Exec("DELETE from app_metric_anomaly")
+	if e != nil {
+		Fail("can not clean table app_metric_anomaly : " + e.Error())
+	}
+}
+
 func hasAppMetric(appId, metricType string, timestamp int64, value string) bool {
 	query := "SELECT * FROM app_metric WHERE app_id = $1 AND metric_type = $2 AND timestamp = $3 AND value = $4"
 	rows, e := dbHelper.Query(query, appId, metricType, timestamp, value)
@@ -195,6 +223,38 @@ func hasScalingHistory(appId string, timestamp int64) bool {
 	return rows.Next()
 }
 
+func cleanScalingExplanationTable() {
+	_, e := dbHelper.Exec("DELETE from scalingexplanation")
+	if e != nil {
+		Fail("can not clean table scalingexplanation: " + e.Error())
+	}
+}
+
+func getNumberOfScalingExplanations() int {
+	var num int
+	e := dbHelper.QueryRow("SELECT COUNT(*) FROM scalingexplanation").Scan(&num)
+	if e != nil {
+		Fail("can not count the number of records in table scalingexplanation: " + e.Error())
+	}
+	return num
+}
+
+func cleanScalingRecommendationTable() {
+	_, e := dbHelper.Exec("DELETE from scalingrecommendation")
+	if e != nil {
+		Fail("can not clean table scalingrecommendation: " + e.Error())
+	}
+}
+
+func getNumberOfScalingRecommendations() int {
+	var num int
+	e := dbHelper.QueryRow("SELECT COUNT(*) FROM scalingrecommendation").Scan(&num)
+	if e != nil {
+		Fail("can not count the number of records in table scalingrecommendation: " + e.Error())
+	}
+	return num
+}
+
 func getNumberOfScalingHistories() int {
 	var num int
 	e := dbHelper.QueryRow("SELECT COUNT(*) FROM scalinghistory").Scan(&num)
@@ -211,9 +271,25 @@ func cleanScalingCooldownTable() {
 	}
 }
 
-func hasScalingCooldownRecord(appId string, expireAt int64) bool {
-	query := "SELECT * FROM scalingcooldown WHERE appid = $1 AND expireat = $2"
-	rows, e := dbHelper.Query(query, appId, expireAt)
+func getNumberOfScalingCooldownRecords(appId string) int {
+	var num int
+	e := dbHelper.QueryRow("SELECT COUNT(*) FROM scalingcooldown WHERE appid = $1", appId).Scan(&num)
+	if e != nil {
+		Fail("can not count the number of records in table scalingcooldown: " + e.Error())
+	}
+	return num
+}
+
+func insertLegacyScalingCooldownRecord(appId string, expireAt int64) {
+	_, e := dbHelper.Exec("INSERT INTO scalingcooldown(appid, expireat) VALUES($1, $2)", appId, expireAt)
+	if e != nil {
+		Fail("can not insert into table scalingcooldown: " + e.Error())
+	}
+}
+
+func hasScalingCooldownRecord(appId string, direction string, ruleKey string, expireAt int64) bool {
+	query := "SELECT * FROM scalingcooldown WHERE appid = $1 AND direction = $2 AND rulekey = $3 AND expireat = $4"
+	rows, e := dbHelper.Query(query, appId, direction, ruleKey, expireAt)
 	if e != nil {
 		Fail("can not query table scalingcooldown: " + e.Error())
 	}
@@ -237,6 +313,20 @@ func insertActiveSchedule(appId, scheduleId string, instanceMin, instanceMax, in
 	return e
 }
 
+func cleanWebhookOutboxTable() error {
+	_, e := dbHelper.Exec("DELETE from webhook_outbox")
+	return e
+}
+
+func getNumberOfWebhookDeliveries() int {
+	var num int
+	e := dbHelper.QueryRow("SELECT COUNT(*) FROM webhook_outbox").Scan(&num)
+	if e != nil {
+		Fail("failed to get number of webhook deliveries:" + e.Error())
+	}
+	return num
+}
+
 func cleanSchedulerActiveScheduleTable() error {
 	_, e := dbHelper.Exec("DELETE from app_scaling_active_schedule")
 	return e
@@ -49,18 +49,31 @@ func (a *Aggregator) getAppMonitors(policyMap map[string]*models.AppPolicy) map[
 	}
 	appMonitors := map[string]*models.AppMonitor{}
 	for appID, appPolicy := range policyMap {
-		for _, rule := range appPolicy.ScalingPolicy.ScalingRules {
-			appMonitors[fmt.Sprintf("%s-%s", appID, rule.MetricType)] = &models.AppMonitor{
-				AppId:      appID,
-				MetricType: rule.MetricType,
-				StatWindow: time.Second * time.Duration(a.defaultStatWindowSecs),
+		rules := append(appPolicy.ScalingPolicy.ScheduleOnlyRules(), appPolicy.ScalingPolicy.ScalingRules...)
+		for _, rule := range rules {
+			if rule.IsCompound() {
+				for _, comparison := range rule.Condition.Comparisons() {
+					a.addAppMonitor(appMonitors, appID, comparison.MetricType, comparison.AggregationFunction(), appPolicy.ScalingPolicy.MetricSource(comparison.MetricType))
+				}
+				continue
 			}
+			a.addAppMonitor(appMonitors, appID, rule.MetricType, rule.AggregationFunction(), appPolicy.ScalingPolicy.MetricSource(rule.MetricType))
 		}
 	}
 
 	return appMonitors
 }
 
+func (a *Aggregator) addAppMonitor(appMonitors map[string]*models.AppMonitor, appID string, metricType string, aggregation string, source *models.MetricSourceConfig) {
+	appMonitors[fmt.Sprintf("%s-%s-%s", appID, metricType, aggregation)] = &models.AppMonitor{
+		AppId:       appID,
+		MetricType:  metricType,
+		Aggregation: aggregation,
+		StatWindow:  time.Second * time.Duration(a.defaultStatWindowSecs),
+		Source:      source,
+	}
+}
+
 func (a *Aggregator) Start() {
 	go a.startAggregating()
 	go a.startSavingAppMetric()
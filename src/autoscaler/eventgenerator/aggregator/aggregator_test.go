@@ -91,7 +91,7 @@ var _ = Describe("Aggregator", func() {
 			Expect(appMetricChan).Should(BeSent(&models.AppMetric{
 				AppId:      testAppId,
 				MetricType: testMetricType,
-				Value:      "250",
+				Value:      250,
 				Unit:       testMetricUnit,
 				Timestamp:  time.Now().UnixNano(),
 			}))
@@ -104,7 +104,12 @@ var _ = Describe("Aggregator", func() {
 
 		It("should send appMonitors and save appMetrics", func() {
 			clock.Increment(1 * fakeWaitDuration)
-			Eventually(appMonitorsChan).Should(Receive())
+			Eventually(appMonitorsChan).Should(Receive(Equal(&models.AppMonitor{
+				AppId:       testAppId,
+				MetricType:  testMetricType,
+				Aggregation: models.AggregationAvg,
+				StatWindow:  time.Duration(fakeStatWindowSecs) * time.Second,
+			})))
 			Eventually(func() int {
 				cacheLock.RLock()
 				defer cacheLock.RUnlock()
@@ -112,6 +117,131 @@ var _ = Describe("Aggregator", func() {
 			}).Should(Equal(1))
 			Eventually(appMetricDatabase.SaveAppMetricsInBulkCallCount).Should(Equal(1))
 		})
+
+		Context("when the scaling rule has a condition", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId: {
+							AppId: testAppId,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax: 5,
+								InstanceMin: 1,
+								ScalingRules: []*models.ScalingRule{
+									{
+										Condition: &models.ScalingCondition{
+											And: []*models.ScalingCondition{
+												{MetricType: testMetricType, Operator: ">", Threshold: 70},
+												{MetricType: "another-metric-name", Operator: ">", Threshold: 500, Aggregation: models.AggregationMax},
+											},
+										},
+										Adjustment: "+1",
+									},
+								},
+							},
+						},
+					}
+				}
+			})
+
+			It("should send an appMonitor for each metric in the condition", func() {
+				clock.Increment(1 * fakeWaitDuration)
+				var monitors []*models.AppMonitor
+				for i := 0; i < 2; i++ {
+					var monitor *models.AppMonitor
+					Eventually(appMonitorsChan).Should(Receive(&monitor))
+					monitors = append(monitors, monitor)
+				}
+				Expect(monitors).To(ConsistOf(
+					&models.AppMonitor{
+						AppId:       testAppId,
+						MetricType:  testMetricType,
+						Aggregation: models.AggregationAvg,
+						StatWindow:  time.Duration(fakeStatWindowSecs) * time.Second,
+					},
+					&models.AppMonitor{
+						AppId:       testAppId,
+						MetricType:  "another-metric-name",
+						Aggregation: models.AggregationMax,
+						StatWindow:  time.Duration(fakeStatWindowSecs) * time.Second,
+					},
+				))
+			})
+		})
+
+		Context("when the metric type of the scaling rule has a metric source", func() {
+			var source *models.MetricSourceConfig
+			BeforeEach(func() {
+				source = &models.MetricSourceConfig{
+					MetricType: "queue_depth",
+					Type:       models.MetricSourceTypePrometheus,
+					URL:        "http://prometheus.example.com",
+					Query:      "sum(rabbitmq_queue_messages)",
+				}
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId: {
+							AppId: testAppId,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax: 5,
+								InstanceMin: 1,
+								ScalingRules: []*models.ScalingRule{
+									{MetricType: "queue_depth", Operator: ">", Threshold: 1000, Adjustment: "+1"},
+								},
+								MetricSources: []*models.MetricSourceConfig{source},
+							},
+						},
+					}
+				}
+			})
+
+			It("should send an appMonitor with the metric source", func() {
+				clock.Increment(1 * fakeWaitDuration)
+				Eventually(appMonitorsChan).Should(Receive(Equal(&models.AppMonitor{
+					AppId:       testAppId,
+					MetricType:  "queue_depth",
+					Aggregation: models.AggregationAvg,
+					StatWindow:  time.Duration(fakeStatWindowSecs) * time.Second,
+					Source:      source,
+				})))
+			})
+		})
+
+		Context("when a schedule of the policy overrides the scaling rules", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId: {
+							AppId: testAppId,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax: 5,
+								InstanceMin: 1,
+								Schedules: &models.ScalingSchedules{
+									RecurringSchedules: []*models.RecurringSchedule{{
+										Override: &models.ScheduleOverride{
+											Mode: models.ScheduleOverrideRules,
+											ScalingRules: []*models.ScalingRule{
+												{MetricType: "another-metric-name", Operator: ">", Threshold: 500, Adjustment: "+1"},
+											},
+										},
+									}},
+								},
+							},
+						},
+					}
+				}
+			})
+
+			It("should send an appMonitor for the metric of the override rule", func() {
+				clock.Increment(1 * fakeWaitDuration)
+				Eventually(appMonitorsChan).Should(Receive(Equal(&models.AppMonitor{
+					AppId:       testAppId,
+					MetricType:  "another-metric-name",
+					Aggregation: models.AggregationAvg,
+					StatWindow:  time.Duration(fakeStatWindowSecs) * time.Second,
+				})))
+			})
+		})
 	})
 
 	Context("Stop", func() {
@@ -126,7 +256,7 @@ var _ = Describe("Aggregator", func() {
 			Expect(appMetricChan).Should(BeSent(&models.AppMetric{
 				AppId:      testAppId,
 				MetricType: testMetricType,
-				Value:      "250",
+				Value:      250,
 				Unit:       testMetricUnit,
 				Timestamp:  time.Now().UnixNano(),
 			}))
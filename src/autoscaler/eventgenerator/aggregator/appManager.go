@@ -15,38 +15,55 @@ import (
 type Consumer func(map[string]*models.AppPolicy, chan *models.AppMonitor)
 type GetPoliciesFunc func() map[string]*models.AppPolicy
 type SaveAppMetricToCacheFunc func(*models.AppMetric) bool
-type QueryAppMetricsFunc func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error)
+type QueryAppMetricsFunc func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error)
+type GetLatestAppMetricsFunc func(appID string) []*models.AppMetric
+type QueryAnomalyScoresFunc func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore
 
 type AppManager struct {
-	logger                lager.Logger
-	interval              time.Duration
-	nodeNum               int
-	nodeIndex             int
-	metricCacheSizePerApp int
-	metricCache           map[string]*collection.TSDCache
-	policyDB              db.PolicyDB
-	appMetricDB           db.AppMetricDB
-	clock                 clock.Clock
-	doneChan              chan bool
-	policyMap             map[string]*models.AppPolicy
-	pLock                 sync.RWMutex
-	mLock                 sync.RWMutex
+	logger                 lager.Logger
+	interval               time.Duration
+	nodeNum                int
+	nodeIndex              int
+	metricCacheSizePerApp  int
+	anomalySmoothingFactor float64
+	anomalyMinSamples      int
+	anomalySeedWindow      time.Duration
+	metricCache            map[string]*collection.TSDCache
+	latestMetrics          map[string]map[string]*models.AppMetric
+	scoreCache             map[string]*collection.TSDCache
+	baselines              map[string]map[string]*models.MetricBaseline
+	policyDB               db.PolicyDB
+	appMetricDB            db.AppMetricDB
+	clock                  clock.Clock
+	doneChan               chan bool
+	policyMap              map[string]*models.AppPolicy
+	pLock                  sync.RWMutex
+	mLock                  sync.RWMutex
+	lLock                  sync.RWMutex
+	bLock                  sync.RWMutex
 }
 
 func NewAppManager(logger lager.Logger, clock clock.Clock, interval time.Duration, nodeNum, nodeIndex int,
-	metricCacheSizePerApp int, policyDB db.PolicyDB, appMetricDB db.AppMetricDB) *AppManager {
+	metricCacheSizePerApp int, anomalySmoothingFactor float64, anomalyMinSamples int, anomalySeedWindow time.Duration,
+	policyDB db.PolicyDB, appMetricDB db.AppMetricDB) *AppManager {
 	return &AppManager{
-		logger:                logger.Session("AppManager"),
-		clock:                 clock,
-		interval:              interval,
-		nodeNum:               nodeNum,
-		nodeIndex:             nodeIndex,
-		metricCacheSizePerApp: metricCacheSizePerApp,
-		metricCache:           make(map[string]*collection.TSDCache),
-		policyDB:              policyDB,
-		appMetricDB:           appMetricDB,
-		doneChan:              make(chan bool),
-		policyMap:             make(map[string]*models.AppPolicy),
+		logger:                 logger.Session("AppManager"),
+		clock:                  clock,
+		interval:               interval,
+		nodeNum:                nodeNum,
+		nodeIndex:              nodeIndex,
+		metricCacheSizePerApp:  metricCacheSizePerApp,
+		anomalySmoothingFactor: anomalySmoothingFactor,
+		anomalyMinSamples:      anomalyMinSamples,
+		anomalySeedWindow:      anomalySeedWindow,
+		metricCache:            make(map[string]*collection.TSDCache),
+		latestMetrics:          make(map[string]map[string]*models.AppMetric),
+		scoreCache:             make(map[string]*collection.TSDCache),
+		baselines:              make(map[string]map[string]*models.MetricBaseline),
+		policyDB:               policyDB,
+		appMetricDB:            appMetricDB,
+		doneChan:               make(chan bool),
+		policyMap:              make(map[string]*models.AppPolicy),
 	}
 }
 func (am *AppManager) GetPolicies() map[string]*models.AppPolicy {
@@ -73,7 +90,7 @@ func (am *AppManager) startPolicyRetrieve() {
 		if err != nil {
 			continue
 		}
-		policies := am.computePolicies(policyJsons)
+		policies := am.computePolicies(policyJsons, am.retrievePauses())
 
 		am.pLock.Lock()
 		am.policyMap = policies
@@ -100,11 +117,23 @@ func (am *AppManager) retrievePolicies() ([]*models.PolicyJson, error) {
 	return policyJsons, nil
 }
 
-func (am *AppManager) computePolicies(policyJsons []*models.PolicyJson) map[string]*models.AppPolicy {
+// retrievePauses returns the pauses of the apps, the scaling engine still honours a pause that fails to be retrieved
+// here.
+func (am *AppManager) retrievePauses() map[string]*models.AppPause {
+	pauses, err := am.policyDB.RetrieveAppPauses()
+	if err != nil {
+		am.logger.Error("retrieve app pauses", err)
+		return nil
+	}
+	return pauses
+}
+
+func (am *AppManager) computePolicies(policyJsons []*models.PolicyJson, pauses map[string]*models.AppPause) map[string]*models.AppPolicy {
 	policyMap := make(map[string]*models.AppPolicy)
 	for _, policyJSON := range policyJsons {
 		if (am.nodeNum == 1) || (helpers.FNVHash(policyJSON.AppId)%uint32(am.nodeNum) == uint32(am.nodeIndex)) {
 			appPolicy := policyJSON.GetAppPolicy()
+			appPolicy.Pause = pauses[policyJSON.AppId]
 			policyMap[policyJSON.AppId] = appPolicy
 		}
 	}
@@ -112,6 +141,29 @@ func (am *AppManager) computePolicies(policyJsons []*models.PolicyJson) map[stri
 }
 
 func (am *AppManager) refreshMetricCache(policies map[string]*models.AppPolicy) {
+	am.lLock.Lock()
+	for id := range am.latestMetrics {
+		if _, exist := policies[id]; !exist {
+			delete(am.latestMetrics, id)
+		}
+	}
+	am.lLock.Unlock()
+
+	am.bLock.Lock()
+	for id := range am.scoreCache {
+		if policy, exist := policies[id]; !exist || !policy.ScalingPolicy.HasAnomalyRules() {
+			delete(am.scoreCache, id)
+			delete(am.baselines, id)
+		}
+	}
+	for id, policy := range policies {
+		if _, exist := am.scoreCache[id]; !exist && policy.ScalingPolicy.HasAnomalyRules() {
+			am.scoreCache[id] = collection.NewTSDCache(am.metricCacheSizePerApp)
+			am.baselines[id] = map[string]*models.MetricBaseline{}
+		}
+	}
+	am.bLock.Unlock()
+
 	am.mLock.Lock()
 	defer am.mLock.Unlock()
 	for id := range am.metricCache {
@@ -136,12 +188,136 @@ func (am *AppManager) SaveMetricToCache(metric *models.AppMetric) bool {
 
 	if appCache != nil {
 		appCache.Put(metric)
+		am.saveLatestMetric(metric)
+		am.scoreMetric(metric)
 		return true
 	}
 	return false
 }
 
-func (am *AppManager) QueryAppMetrics(appID string, metricType string, start int64, end int64, order db.OrderType) ([]*models.AppMetric, error) {
+func (am *AppManager) saveLatestMetric(metric *models.AppMetric) {
+	am.lLock.Lock()
+	defer am.lLock.Unlock()
+	appMetrics, exist := am.latestMetrics[metric.AppId]
+	if !exist {
+		appMetrics = map[string]*models.AppMetric{}
+		am.latestMetrics[metric.AppId] = appMetrics
+	}
+	key := metric.MetricType + "#" + metric.Aggregation
+	if latest, exist := appMetrics[key]; !exist || latest.Timestamp <= metric.Timestamp {
+		appMetrics[key] = metric
+	}
+}
+
+// scoreMetric scores the metric against the baseline of its metric type and aggregation and then adds it to the
+// baseline, only for the apps with anomaly rules.
+func (am *AppManager) scoreMetric(metric *models.AppMetric) {
+	value := metric.Value
+	key := metric.MetricType + "#" + metric.Aggregation
+	am.bLock.RLock()
+	hasAnomalyRules := am.scoreCache[metric.AppId] != nil
+	_, exist := am.baselines[metric.AppId][key]
+	am.bLock.RUnlock()
+	if !hasAnomalyRules {
+		return
+	}
+	var seed *models.MetricBaseline
+	if !exist {
+		seed = am.seedBaseline(metric)
+	}
+
+	am.bLock.Lock()
+	defer am.bLock.Unlock()
+	scoreCache := am.scoreCache[metric.AppId]
+	if scoreCache == nil {
+		return
+	}
+	baseline, exist := am.baselines[metric.AppId][key]
+	if !exist {
+		if seed == nil {
+			seed = &models.MetricBaseline{}
+		}
+		baseline = seed
+		am.baselines[metric.AppId][key] = baseline
+	}
+	if score, ok := baseline.Score(value, am.anomalyMinSamples); ok {
+		scoreCache.Put(&models.AnomalyScore{
+			AppId:       metric.AppId,
+			MetricType:  metric.MetricType,
+			Aggregation: metric.Aggregation,
+			Value:       value,
+			Unit:        metric.Unit,
+			Baseline:    baseline.Mean,
+			StdDev:      baseline.StdDev(),
+			Score:       score,
+			Timestamp:   metric.Timestamp,
+		})
+	}
+	baseline.Update(value, am.anomalySmoothingFactor)
+}
+
+// seedBaseline builds the baseline of a metric type and aggregation of an app the first time it is seen from the
+// aggregated metrics of the seed window before the metric, so that the anomaly rules are evaluated again soon after the
+// event generator restarts or the app moves to this instance. The baseline starts empty when they fail to be retrieved.
+func (am *AppManager) seedBaseline(metric *models.AppMetric) *models.MetricBaseline {
+	baseline := &models.MetricBaseline{}
+	if am.anomalySeedWindow <= 0 {
+		return baseline
+	}
+	metrics, err := am.appMetricDB.RetrieveAppMetrics(metric.AppId, metric.MetricType, metric.Aggregation,
+		metric.Timestamp-am.anomalySeedWindow.Nanoseconds(), metric.Timestamp-1, db.ASC)
+	if err != nil {
+		am.logger.Error("retrieve app metrics for baseline", err, lager.Data{"appId": metric.AppId, "metricType": metric.MetricType, "aggregation": metric.Aggregation})
+		return baseline
+	}
+	for _, m := range metrics {
+		baseline.Update(m.Value, am.anomalySmoothingFactor)
+	}
+	return baseline
+}
+
+// QueryAnomalyScores returns the anomaly scores of the app metrics which are still in the cache, there are none for
+// the apps without anomaly rules.
+func (am *AppManager) QueryAnomalyScores(appID string, metricType string, aggregation string, start int64, end int64, order db.OrderType) []*models.AnomalyScore {
+	am.bLock.RLock()
+	scoreCache := am.scoreCache[appID]
+	am.bLock.RUnlock()
+
+	scores := []*models.AnomalyScore{}
+	if scoreCache == nil {
+		return scores
+	}
+	if end == -1 {
+		end = time.Now().UnixNano()
+	}
+	labels := map[string]string{models.MetricLabelName: metricType}
+	if aggregation != "" {
+		labels[models.MetricLabelAggregation] = aggregation
+	}
+	result, _ := scoreCache.Query(start, end+1, labels)
+	for _, tsd := range result {
+		scores = append(scores, tsd.(*models.AnomalyScore))
+	}
+	if order == db.DESC {
+		for i, j := 0, len(scores)-1; i < j; i, j = i+1, j-1 {
+			scores[i], scores[j] = scores[j], scores[i]
+		}
+	}
+	return scores
+}
+
+// GetLatestAppMetrics returns the latest aggregated metric of every metric type and aggregation of the app.
+func (am *AppManager) GetLatestAppMetrics(appID string) []*models.AppMetric {
+	am.lLock.RLock()
+	defer am.lLock.RUnlock()
+	metrics := []*models.AppMetric{}
+	for _, metric := range am.latestMetrics[appID] {
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+func (am *AppManager) QueryAppMetrics(appID string, metricType string, aggregation string, start int64, end int64, order db.OrderType) ([]*models.AppMetric, error) {
 	am.mLock.RLock()
 	appCache := am.metricCache[appID]
 	am.mLock.RUnlock()
@@ -152,6 +328,9 @@ func (am *AppManager) QueryAppMetrics(appID string, metricType string, start int
 
 	if appCache != nil {
 		labels := map[string]string{models.MetricLabelName: metricType}
+		if aggregation != "" {
+			labels[models.MetricLabelAggregation] = aggregation
+		}
 		result, hit := appCache.Query(start, end+1, labels)
 		if hit {
 			metrics := make([]*models.AppMetric, len(result))
@@ -167,5 +346,5 @@ func (am *AppManager) QueryAppMetrics(appID string, metricType string, start int
 			return metrics, nil
 		}
 	}
-	return am.appMetricDB.RetrieveAppMetrics(appID, metricType, start, end, order)
+	return am.appMetricDB.RetrieveAppMetrics(appID, metricType, aggregation, start, end, order)
 }
@@ -53,7 +53,7 @@ var _ = Describe("AppManager", func() {
 	})
 	Context("Start", func() {
 		JustBeforeEach(func() {
-			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, policyDB, appMetricDB)
+			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, 0.5, 3, time.Hour, policyDB, appMetricDB)
 			appManager.Start()
 
 		})
@@ -205,6 +205,40 @@ var _ = Describe("AppManager", func() {
 
 			})
 
+			Context("when the app is paused", func() {
+				var pause *models.AppPause
+
+				BeforeEach(func() {
+					pause = &models.AppPause{AppId: testAppId, PausedBy: "a-user-id"}
+					policyDB.RetrievePoliciesStub = func() ([]*models.PolicyJson, error) {
+						return []*models.PolicyJson{{AppId: testAppId, PolicyStr: policyStr}}, nil
+					}
+					policyDB.RetrieveAppPausesReturns(map[string]*models.AppPause{testAppId: pause}, nil)
+				})
+				It("adds the pause to the policy of the app", func() {
+					Eventually(func() *models.AppPause {
+						policy := appManager.GetPolicies()[testAppId]
+						if policy == nil {
+							return nil
+						}
+						return policy.Pause
+					}).Should(Equal(pause))
+				})
+			})
+
+			Context("when retrieving app pauses from database fails", func() {
+				BeforeEach(func() {
+					policyDB.RetrievePoliciesStub = func() ([]*models.PolicyJson, error) {
+						return []*models.PolicyJson{{AppId: testAppId, PolicyStr: policyStr}}, nil
+					}
+					policyDB.RetrieveAppPausesReturns(nil, errors.New("error when retrieve app pauses from database"))
+				})
+				It("still gets the policies", func() {
+					Eventually(func() int { return len(appManager.GetPolicies()) }).Should(Equal(1))
+					Expect(appManager.GetPolicies()[testAppId].Pause).To(BeNil())
+				})
+			})
+
 			Context("when retrieving policies from database fails", func() {
 				BeforeEach(func() {
 					policyDB.RetrievePoliciesStub = func() ([]*models.PolicyJson, error) {
@@ -222,7 +256,7 @@ var _ = Describe("AppManager", func() {
 
 	Context("Save and query metrics", func() {
 		JustBeforeEach(func() {
-			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, policyDB, appMetricDB)
+			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, 0.5, 3, time.Hour, policyDB, appMetricDB)
 			appManager.Start()
 
 		})
@@ -246,7 +280,7 @@ var _ = Describe("AppManager", func() {
 				appMetric1 := &models.AppMetric{
 					AppId:      testAppId,
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -254,31 +288,33 @@ var _ = Describe("AppManager", func() {
 				appMetric2 := &models.AppMetric{
 					AppId:      testAppId,
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  200,
 				}
 
 				appMetric3 := &models.AppMetric{
-					AppId:      testAppId,
-					MetricType: "test-metric-type",
-					Value:      "100",
-					Unit:       "test-unit",
-					Timestamp:  300,
+					AppId:       testAppId,
+					MetricType:  "test-metric-type",
+					Aggregation: models.AggregationAvg,
+					Value:       100,
+					Unit:        "test-unit",
+					Timestamp:   300,
 				}
 
 				appMetric4 := &models.AppMetric{
-					AppId:      testAppId,
-					MetricType: "test-metric-type",
-					Value:      "100",
-					Unit:       "test-unit",
-					Timestamp:  400,
+					AppId:       testAppId,
+					MetricType:  "test-metric-type",
+					Aggregation: models.AggregationMax,
+					Value:       100,
+					Unit:        "test-unit",
+					Timestamp:   400,
 				}
 
 				anotheAppMetric1 := &models.AppMetric{
 					AppId:      "another-app-id",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -286,7 +322,7 @@ var _ = Describe("AppManager", func() {
 				anotheAppMetric2 := &models.AppMetric{
 					AppId:      "another-app-id",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  200,
 				}
@@ -299,17 +335,115 @@ var _ = Describe("AppManager", func() {
 				Expect(appManager.SaveMetricToCache(anotheAppMetric2)).To(BeFalse())
 
 				By("cache hit")
-				data, err := appManager.QueryAppMetrics(testAppId, "test-metric-type", 300, 500, db.ASC)
+				data, err := appManager.QueryAppMetrics(testAppId, "test-metric-type", "", 300, 500, db.ASC)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(data).To(Equal([]*models.AppMetric{appMetric3, appMetric4}))
 
+				By("cache hit with aggregation")
+				data, err = appManager.QueryAppMetrics(testAppId, "test-metric-type", models.AggregationMax, 300, 500, db.ASC)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(data).To(Equal([]*models.AppMetric{appMetric4}))
+
 				By("cache miss")
 				appMetricDB.RetrieveAppMetricsReturns([]*models.AppMetric{appMetric1, appMetric2}, nil)
-				data, err = appManager.QueryAppMetrics(testAppId, "test-metric-type", 100, 200, db.ASC)
+				data, err = appManager.QueryAppMetrics(testAppId, "test-metric-type", models.AggregationAvg, 100, 200, db.ASC)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(appMetricDB.RetrieveAppMetricsCallCount()).To(Equal(1))
+				_, _, aggregation, _, _, _ := appMetricDB.RetrieveAppMetricsArgsForCall(0)
+				Expect(aggregation).To(Equal(models.AggregationAvg))
 				Expect(data).To(Equal([]*models.AppMetric{appMetric1, appMetric2}))
 
+				By("latest metrics")
+				Expect(appManager.GetLatestAppMetrics(testAppId)).To(ConsistOf(appMetric2, appMetric3, appMetric4))
+				Expect(appManager.GetLatestAppMetrics("another-app-id")).To(BeEmpty())
+			})
+		})
+
+		Context("when the policy has anomaly rules", func() {
+			BeforeEach(func() {
+				anomalyPolicyStr := `{"instance_min_count":1,"instance_max_count":5,"scaling_rules":[{"metric_type":"test-metric-type","threshold":3,"operator":">","adjustment":"+1","anomaly":{"action":"scale"}}]}`
+				policyDB.RetrievePoliciesStub = func() ([]*models.PolicyJson, error) {
+					return []*models.PolicyJson{
+						{AppId: testAppId, PolicyStr: anomalyPolicyStr},
+						{AppId: "another-app-id", PolicyStr: policyStr},
+					}, nil
+				}
+				cacheSizePerApp = 10
+			})
+			It("should score the metrics against their baseline", func() {
+				Eventually(appManager.GetPolicies).Should(HaveLen(2))
+
+				for i, value := range []float64{100, 110, 90, 100, 200} {
+					Expect(appManager.SaveMetricToCache(&models.AppMetric{
+						AppId:       testAppId,
+						MetricType:  "test-metric-type",
+						Aggregation: models.AggregationAvg,
+						Value:       value,
+						Unit:        "test-unit",
+						Timestamp:   int64(100 * (i + 1)),
+					})).To(BeTrue())
+					Expect(appManager.SaveMetricToCache(&models.AppMetric{
+						AppId:       "another-app-id",
+						MetricType:  "test-metric-type",
+						Aggregation: models.AggregationAvg,
+						Value:       value,
+						Unit:        "test-unit",
+						Timestamp:   int64(100 * (i + 1)),
+					})).To(BeTrue())
+				}
+
+				By("scoring the metrics once the baseline has enough samples")
+				scores := appManager.QueryAnomalyScores(testAppId, "test-metric-type", models.AggregationAvg, 0, 1000, db.DESC)
+				Expect(scores).To(HaveLen(2))
+				Expect(scores[0].Timestamp).To(Equal(int64(500)))
+				Expect(scores[0].Value).To(Equal(200.0))
+				Expect(scores[0].Score).To(BeNumerically(">", 3))
+				Expect(scores[1].Timestamp).To(Equal(int64(400)))
+				Expect(scores[1].Score).To(BeNumerically("<", 3))
+
+				By("not scoring the metrics of the apps without anomaly rules")
+				Expect(appManager.QueryAnomalyScores("another-app-id", "test-metric-type", models.AggregationAvg, 0, 1000, db.DESC)).To(BeEmpty())
+			})
+
+			Context("when the app metrics database has the metrics before", func() {
+				BeforeEach(func() {
+					appMetricDB.RetrieveAppMetricsReturns([]*models.AppMetric{
+						{AppId: testAppId, MetricType: "test-metric-type", Aggregation: models.AggregationAvg, Value: 100, Timestamp: 100},
+						{AppId: testAppId, MetricType: "test-metric-type", Aggregation: models.AggregationAvg, Value: 110, Timestamp: 200},
+						{AppId: testAppId, MetricType: "test-metric-type", Aggregation: models.AggregationAvg, Value: 90, Timestamp: 300},
+					}, nil)
+				})
+
+				It("should seed the baseline with them the first time the metric is seen", func() {
+					Eventually(appManager.GetPolicies).Should(HaveLen(2))
+
+					for i, value := range []float64{100, 200} {
+						Expect(appManager.SaveMetricToCache(&models.AppMetric{
+							AppId:       testAppId,
+							MetricType:  "test-metric-type",
+							Aggregation: models.AggregationAvg,
+							Value:       value,
+							Unit:        "test-unit",
+							Timestamp:   int64(100 * (i + 4)),
+						})).To(BeTrue())
+					}
+
+					Expect(appMetricDB.RetrieveAppMetricsCallCount()).To(Equal(1))
+					appId, metricType, aggregation, start, end, order := appMetricDB.RetrieveAppMetricsArgsForCall(0)
+					Expect(appId).To(Equal(testAppId))
+					Expect(metricType).To(Equal("test-metric-type"))
+					Expect(aggregation).To(Equal(models.AggregationAvg))
+					Expect(start).To(Equal(400 - time.Hour.Nanoseconds()))
+					Expect(end).To(Equal(int64(399)))
+					Expect(order).To(Equal(db.ASC))
+
+					scores := appManager.QueryAnomalyScores(testAppId, "test-metric-type", models.AggregationAvg, 0, 1000, db.DESC)
+					Expect(scores).To(HaveLen(2))
+					Expect(scores[0].Timestamp).To(Equal(int64(500)))
+					Expect(scores[0].Score).To(BeNumerically(">", 3))
+					Expect(scores[1].Timestamp).To(Equal(int64(400)))
+					Expect(scores[1].Score).To(BeNumerically("<", 3))
+				})
 			})
 		})
 
@@ -340,7 +474,7 @@ var _ = Describe("AppManager", func() {
 				appMetric1 := &models.AppMetric{
 					AppId:      "app-id-1",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -348,7 +482,7 @@ var _ = Describe("AppManager", func() {
 				appMetric2 := &models.AppMetric{
 					AppId:      "app-id-2",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -356,7 +490,7 @@ var _ = Describe("AppManager", func() {
 				appMetric3 := &models.AppMetric{
 					AppId:      "app-id-3",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -364,7 +498,7 @@ var _ = Describe("AppManager", func() {
 				appMetric4 := &models.AppMetric{
 					AppId:      "app-id-4",
 					MetricType: "test-metric-type",
-					Value:      "100",
+					Value:      100,
 					Unit:       "test-unit",
 					Timestamp:  100,
 				}
@@ -389,7 +523,7 @@ var _ = Describe("AppManager", func() {
 
 	Context("Stop", func() {
 		BeforeEach(func() {
-			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, policyDB, appMetricDB)
+			appManager = NewAppManager(logger, clock, testPolicyPollerInterval, nodeNum, nodeIndex, cacheSizePerApp, 0.5, 3, time.Hour, policyDB, appMetricDB)
 			appManager.Start()
 			Eventually(policyDB.RetrievePoliciesCallCount).Should(Equal(1))
 
@@ -2,11 +2,14 @@ package aggregator
 
 import (
 	"autoscaler/db"
+	"autoscaler/eventgenerator/metricsource"
 	"autoscaler/models"
 	"autoscaler/routes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -19,16 +22,19 @@ type MetricPoller struct {
 	doneChan           chan bool
 	appChan            chan *models.AppMonitor
 	httpClient         *http.Client
+	sourceHttpClient   *http.Client
 	appMetricChan      chan *models.AppMetric
 }
 
-func NewMetricPoller(logger lager.Logger, metricCollectorUrl string, appChan chan *models.AppMonitor, httpClient *http.Client, appMetricChan chan *models.AppMetric) *MetricPoller {
+func NewMetricPoller(logger lager.Logger, metricCollectorUrl string, appChan chan *models.AppMonitor, httpClient *http.Client,
+	sourceHttpClient *http.Client, appMetricChan chan *models.AppMetric) *MetricPoller {
 	return &MetricPoller{
 		metricCollectorUrl: metricCollectorUrl,
 		logger:             logger.Session("MetricPoller"),
 		appChan:            appChan,
 		doneChan:           make(chan bool),
 		httpClient:         httpClient,
+		sourceHttpClient:   sourceHttpClient,
 		appMetricChan:      appMetricChan,
 	}
 }
@@ -55,6 +61,11 @@ func (m *MetricPoller) startMetricRetrieve() {
 }
 
 func (m *MetricPoller) retrieveMetric(app *models.AppMonitor) {
+	if app.Source != nil {
+		m.retrieveSourceMetric(app)
+		return
+	}
+
 	appId := app.AppId
 	metricType := app.MetricType
 	endTime := time.Now()
@@ -87,47 +98,109 @@ func (m *MetricPoller) retrieveMetric(app *models.AppMonitor) {
 		return
 	}
 
-	avgMetric := m.aggregate(appId, metricType, metrics)
-	if avgMetric == nil {
+	aggregatedMetric := m.aggregate(appId, metricType, app.Aggregation, metrics)
+	if aggregatedMetric == nil {
 		return
 	}
-	m.logger.Debug("Save-aggregated-appmetric", lager.Data{"appMetric": avgMetric})
-	m.appMetricChan <- avgMetric
+	m.logger.Debug("Save-aggregated-appmetric", lager.Data{"appMetric": aggregatedMetric})
+	m.appMetricChan <- aggregatedMetric
 
 }
 
-func (m *MetricPoller) aggregate(appId string, metricType string, metrics []*models.AppInstanceMetric) *models.AppMetric {
-	var count int64
-	var sum int64
-	var unit string
-	timestamp := time.Now().UnixNano()
-	for _, metric := range metrics {
-		unit = metric.Unit
-		metricValue, err := strconv.ParseInt(metric.Value, 10, 64)
-		if err != nil {
-			m.logger.Error("failed-to-aggregate", err, lager.Data{"appid": appId, "metrictype": metricType, "value": metric.Value})
-		} else {
-			count++
-			sum += metricValue
-		}
+// retrieveSourceMetric pulls the metric from the metric source of the app, the value of the source is already the
+// value of the app so it is the same for any aggregation.
+func (m *MetricPoller) retrieveSourceMetric(app *models.AppMonitor) {
+	source, err := metricsource.NewMetricSource(app.Source, m.sourceHttpClient)
+	if err != nil {
+		m.logger.Error("Failed to create metric source", err, lager.Data{"appId": app.AppId, "metricType": app.MetricType, "type": app.Source.Type})
+		return
+	}
+	value, err := source.Fetch()
+	if err != nil {
+		m.logger.Error("Failed to retrieve metric from metric source", err, lager.Data{"appId": app.AppId, "metricType": app.MetricType, "url": models.RedactURL(app.Source.URL)})
+		return
 	}
 
-	if count == 0 {
-		return &models.AppMetric{
-			AppId:      appId,
-			MetricType: metricType,
-			Value:      "",
-			Unit:       "",
-			Timestamp:  timestamp,
-		}
+	aggregation := app.Aggregation
+	if aggregation == "" {
+		aggregation = models.AggregationAvg
+	}
+	appMetric := &models.AppMetric{
+		AppId:       app.AppId,
+		MetricType:  app.MetricType,
+		Aggregation: aggregation,
+		Value:       value,
+		Unit:        app.Source.Unit,
+		Timestamp:   time.Now().UnixNano(),
+	}
+	m.logger.Debug("Save-source-appmetric", lager.Data{"appMetric": appMetric})
+	m.appMetricChan <- appMetric
+}
+
+func (m *MetricPoller) aggregate(appId string, metricType string, aggregation string, metrics []*models.AppInstanceMetric) *models.AppMetric {
+	if aggregation == "" {
+		aggregation = models.AggregationAvg
+	}
+	if len(metrics) == 0 {
+		m.logger.Debug("no-metrics-to-aggregate", lager.Data{"appid": appId, "metrictype": metricType})
+		return nil
 	}
+	unit := metrics[0].Unit
+	values := make([]float64, len(metrics))
+	for i, metric := range metrics {
+		values[i] = metric.Value
+	}
+	timestamp := time.Now().UnixNano()
 
-	avgValue := int64(float64(sum)/float64(count) + 0.5)
+	value, err := aggregateValues(aggregation, values)
+	if err != nil {
+		m.logger.Error("failed-to-aggregate", err, lager.Data{"appid": appId, "metrictype": metricType, "aggregation": aggregation})
+		return nil
+	}
 	return &models.AppMetric{
-		AppId:      appId,
-		MetricType: metricType,
-		Value:      fmt.Sprintf("%d", avgValue),
-		Unit:       unit,
-		Timestamp:  timestamp,
+		AppId:       appId,
+		MetricType:  metricType,
+		Aggregation: aggregation,
+		Value:       value,
+		Unit:        unit,
+		Timestamp:   timestamp,
+	}
+}
+
+func aggregateValues(aggregation string, values []float64) (float64, error) {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	switch aggregation {
+	case models.AggregationAvg:
+		return sum / float64(len(values)), nil
+	case models.AggregationSum:
+		return sum, nil
+	case models.AggregationMax:
+		return sorted[len(sorted)-1], nil
+	case models.AggregationMin:
+		return sorted[0], nil
+	case models.AggregationP50:
+		return percentile(sorted, 50), nil
+	case models.AggregationP90:
+		return percentile(sorted, 90), nil
+	case models.AggregationP99:
+		return percentile(sorted, 99), nil
+	}
+	return 0, fmt.Errorf("unsupported aggregation %s", aggregation)
+}
+
+// percentile uses the nearest-rank method, so the result is always one of the values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
 	}
+	return sorted[rank-1]
 }
@@ -34,7 +34,7 @@ var _ = Describe("MetricPoller", func() {
 			CollectedAt:   111111,
 			Name:          testMetricType,
 			Unit:          testMetricUnit,
-			Value:         "100",
+			Value:         100,
 			Timestamp:     111100,
 		},
 		{
@@ -43,7 +43,7 @@ var _ = Describe("MetricPoller", func() {
 			CollectedAt:   111111,
 			Name:          testMetricType,
 			Unit:          testMetricUnit,
-			Value:         "200",
+			Value:         200,
 			Timestamp:     110000,
 		},
 
@@ -53,7 +53,7 @@ var _ = Describe("MetricPoller", func() {
 			CollectedAt:   222222,
 			Name:          testMetricType,
 			Unit:          testMetricUnit,
-			Value:         "300",
+			Value:         300,
 			Timestamp:     222200,
 		},
 		{
@@ -62,7 +62,7 @@ var _ = Describe("MetricPoller", func() {
 			CollectedAt:   222222,
 			Name:          testMetricType,
 			Unit:          testMetricUnit,
-			Value:         "400",
+			Value:         400,
 			Timestamp:     220000,
 		},
 	}
@@ -91,7 +91,7 @@ var _ = Describe("MetricPoller", func() {
 
 			metricServer = ghttp.NewUnstartedServer()
 
-			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, appMetricChan)
+			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, httpClient, appMetricChan)
 			metricPoller.Start()
 
 			Expect(appMonitorsChan).Should(BeSent(appMonitor))
@@ -128,7 +128,7 @@ var _ = Describe("MetricPoller", func() {
 		})
 
 		JustBeforeEach(func() {
-			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, appMetricChan)
+			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, httpClient, appMetricChan)
 			metricPoller.Start()
 
 			Expect(appMonitorsChan).Should(BeSent(appMonitor))
@@ -145,11 +145,191 @@ var _ = Describe("MetricPoller", func() {
 				appMetric.Timestamp = timestamp
 
 				Expect(appMetric).To(Equal(&models.AppMetric{
-					AppId:      testAppId,
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationAvg,
+					Value:       250,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when the metric type has a metric source", func() {
+			var sourceServer *ghttp.Server
+
+			BeforeEach(func() {
+				sourceServer = ghttp.NewServer()
+				sourceServer.RouteToHandler("GET", "/queues/jobs", ghttp.RespondWith(http.StatusOK, `{"messages":1234}`))
+				appMonitor.Source = &models.MetricSourceConfig{
 					MetricType: testMetricType,
-					Value:      "250",
-					Unit:       testMetricUnit,
-					Timestamp:  timestamp}))
+					Type:       models.MetricSourceTypeHTTPJSON,
+					URL:        sourceServer.URL() + "/queues/jobs",
+					JSONPath:   "$.messages",
+					Unit:       "messages",
+				}
+			})
+
+			AfterEach(func() {
+				sourceServer.Close()
+			})
+
+			It("send the metric of the source to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationAvg,
+					Value:       1234,
+					Unit:        "messages",
+					Timestamp:   timestamp}))
+				Expect(metricServer.ReceivedRequests()).To(BeEmpty())
+			})
+
+			Context("when the metric source fails", func() {
+				BeforeEach(func() {
+					sourceServer.RouteToHandler("GET", "/queues/jobs", ghttp.RespondWith(http.StatusInternalServerError, ""))
+				})
+
+				It("does not save any metrics", func() {
+					Eventually(logger.Buffer).Should(Say("Failed to retrieve metric from metric source"))
+					Consistently(appMetricChan).ShouldNot(Receive())
+				})
+			})
+		})
+
+		Context("when the aggregation is max", func() {
+			BeforeEach(func() {
+				appMonitor.Aggregation = models.AggregationMax
+			})
+
+			It("send the max of the metrics to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationMax,
+					Value:       400,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when the aggregation is min", func() {
+			BeforeEach(func() {
+				appMonitor.Aggregation = models.AggregationMin
+			})
+
+			It("send the min of the metrics to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationMin,
+					Value:       100,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when the aggregation is sum", func() {
+			BeforeEach(func() {
+				appMonitor.Aggregation = models.AggregationSum
+			})
+
+			It("send the sum of the metrics to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationSum,
+					Value:       1000,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when the aggregation is p50", func() {
+			BeforeEach(func() {
+				appMonitor.Aggregation = models.AggregationP50
+			})
+
+			It("send the p50 of the metrics to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationP50,
+					Value:       200,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when the aggregation is p90", func() {
+			BeforeEach(func() {
+				appMonitor.Aggregation = models.AggregationP90
+			})
+
+			It("send the p90 of the metrics to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationP90,
+					Value:       400,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
+			})
+		})
+
+		Context("when metrics have fractional values", func() {
+			BeforeEach(func() {
+				metricServer.RouteToHandler("GET", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK,
+					&[]*models.AppInstanceMetric{
+						{
+							AppId:         testAppId,
+							InstanceIndex: 0,
+							CollectedAt:   111111,
+							Name:          testMetricType,
+							Unit:          testMetricUnit,
+							Value:         0.25,
+							Timestamp:     111100,
+						},
+						{
+							AppId:         testAppId,
+							InstanceIndex: 1,
+							CollectedAt:   111111,
+							Name:          testMetricType,
+							Unit:          testMetricUnit,
+							Value:         1,
+							Timestamp:     110000,
+						},
+					}))
+			})
+
+			It("send the average metrics without rounding to appMetric channel", func() {
+				appMetric = <-appMetricChan
+				appMetric.Timestamp = timestamp
+
+				Expect(appMetric).To(Equal(&models.AppMetric{
+					AppId:       testAppId,
+					MetricType:  testMetricType,
+					Aggregation: models.AggregationAvg,
+					Value:       0.625,
+					Unit:        testMetricUnit,
+					Timestamp:   timestamp}))
 			})
 		})
 
@@ -174,16 +354,8 @@ var _ = Describe("MetricPoller", func() {
 					&[]*models.AppInstanceMetric{}))
 			})
 
-			It("send the average metrics with no value to appmetric channel", func() {
-				appMetric = <-appMetricChan
-				appMetric.Timestamp = timestamp
-
-				Expect(appMetric).To(Equal(&models.AppMetric{
-					AppId:      testAppId,
-					MetricType: testMetricType,
-					Value:      "",
-					Unit:       "",
-					Timestamp:  timestamp}))
+			It("should not send any metrics to appmetric channel", func() {
+				Consistently(appMetricChan).ShouldNot(Receive())
 			})
 		})
 
@@ -208,7 +380,7 @@ var _ = Describe("MetricPoller", func() {
 			metricServer.RouteToHandler("GET", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK,
 				&metrics))
 
-			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, appMetricChan)
+			metricPoller = NewMetricPoller(logger, metricServer.URL(), appMonitorsChan, httpClient, httpClient, appMetricChan)
 			metricPoller.Start()
 			metricPoller.Stop()
 			Eventually(logger.Buffer).Should(Say("stopped"))
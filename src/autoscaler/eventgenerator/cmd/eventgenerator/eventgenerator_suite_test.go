@@ -47,7 +47,7 @@ var (
 			CollectedAt:   111111,
 			Name:          metricType,
 			Unit:          metricUnit,
-			Value:         "500",
+			Value:         500,
 			Timestamp:     111100,
 		},
 		{
@@ -56,7 +56,7 @@ var (
 			CollectedAt:   111111,
 			Name:          metricType,
 			Unit:          metricUnit,
-			Value:         "600",
+			Value:         600,
 			Timestamp:     110000,
 		},
 
@@ -66,7 +66,7 @@ var (
 			CollectedAt:   222222,
 			Name:          metricType,
 			Unit:          metricUnit,
-			Value:         "700",
+			Value:         700,
 			Timestamp:     222200,
 		},
 		{
@@ -75,7 +75,7 @@ var (
 			CollectedAt:   222222,
 			Name:          metricType,
 			Unit:          metricUnit,
-			Value:         "800",
+			Value:         800,
 			Timestamp:     220000,
 		},
 	}
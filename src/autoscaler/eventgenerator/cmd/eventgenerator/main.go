@@ -11,8 +11,6 @@ import (
 	"autoscaler/helpers"
 	"autoscaler/models"
 
-	"github.com/rubyist/circuitbreaker"
-
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -60,27 +58,37 @@ func main() {
 	}
 	defer policyDB.Close()
 
-	httpStatusCollector := healthendpoint.NewHTTPStatusCollector("autoscaler", "eventgenerator")
-	promRegistry := prometheus.NewRegistry()
-	healthendpoint.RegisterCollectors(promRegistry, []prometheus.Collector{
-		healthendpoint.NewDatabaseStatusCollector("autoscaler", "eventgenerator", "appMetricDB", appMetricDB),
-		healthendpoint.NewDatabaseStatusCollector("autoscaler", "eventgenerator", "policyDB", policyDB),
-		httpStatusCollector,
-	}, true, logger.Session("eventgenerator-prometheus"))
-
 	appManager := aggregator.NewAppManager(logger, egClock, conf.Aggregator.PolicyPollerInterval,
-		len(conf.Server.NodeAddrs), conf.Server.NodeIndex, conf.Aggregator.MetricCacheSizePerApp, policyDB, appMetricDB)
+		len(conf.Server.NodeAddrs), conf.Server.NodeIndex, conf.Aggregator.MetricCacheSizePerApp,
+		conf.Aggregator.AnomalySmoothingFactor, conf.Aggregator.AnomalyMinSamples, conf.Aggregator.AnomalySeedWindow,
+		policyDB, appMetricDB)
 
 	triggersChan := make(chan []*models.Trigger, conf.Evaluator.TriggerArrayChannelSize)
 
 	evaluationManager, err := generator.NewAppEvaluationManager(logger, conf.Evaluator.EvaluationManagerInterval, egClock,
-		triggersChan, appManager.GetPolicies, conf.CircuitBreaker)
+		triggersChan, appManager.GetPolicies, appManager.GetLatestAppMetrics, conf.CircuitBreaker)
 	if err != nil {
 		logger.Error("failed to create Evaluation Manager", err)
 		os.Exit(1)
 	}
 
-	evaluators, err := createEvaluators(logger, conf, triggersChan, appMetricDB, appManager.QueryAppMetrics, evaluationManager.GetBreaker, evaluationManager.SetCoolDownExpired)
+	anomalyRecorder := generator.NewAnomalyRecorder(appMetricDB.SaveAnomalyScore)
+
+	httpStatusCollector := healthendpoint.NewHTTPStatusCollector("autoscaler", "eventgenerator")
+	collectors := []prometheus.Collector{
+		healthendpoint.NewDatabaseStatusCollector("autoscaler", "eventgenerator", "appMetricDB", appMetricDB),
+		healthendpoint.NewDatabaseStatusCollector("autoscaler", "eventgenerator", "policyDB", policyDB),
+		healthendpoint.NewAnomalyRecordCollector("autoscaler", "eventgenerator", anomalyRecorder),
+		httpStatusCollector,
+	}
+	if conf.AppStatusMetrics.Enabled {
+		collectors = append(collectors, healthendpoint.NewAppStatusCollector("autoscaler", "eventgenerator", evaluationManager,
+			conf.AppStatusMetrics.AppIds, conf.AppStatusMetrics.MaxApps))
+	}
+	promRegistry := prometheus.NewRegistry()
+	healthendpoint.RegisterCollectors(promRegistry, collectors, true, logger.Session("eventgenerator-prometheus"))
+
+	evaluators, err := createEvaluators(logger, conf, triggersChan, appManager.QueryAppMetrics, appManager.QueryAnomalyScores, anomalyRecorder, evaluationManager)
 	if err != nil {
 		logger.Error("failed to create Evaluators", err)
 		os.Exit(1)
@@ -119,7 +127,7 @@ func main() {
 		return nil
 	})
 
-	httpServer, err := server.NewServer(logger.Session("http_server"), conf, appManager.QueryAppMetrics, httpStatusCollector)
+	httpServer, err := server.NewServer(logger.Session("http_server"), conf, appManager.QueryAppMetrics, appMetricDB.RetrieveAnomalyScores, httpStatusCollector)
 	if err != nil {
 		logger.Error("failed to create http server", err)
 		os.Exit(1)
@@ -172,7 +180,8 @@ func loadConfig(path string) (*config.Config, error) {
 }
 
 func createEvaluators(logger lager.Logger, conf *config.Config, triggersChan chan []*models.Trigger,
-	database db.AppMetricDB, queryMetrics aggregator.QueryAppMetricsFunc, getBreaker func(string) *circuit.Breaker, setCoolDownExpired func(string, int64)) ([]*generator.Evaluator, error) {
+	queryMetrics aggregator.QueryAppMetricsFunc, queryAnomalyScores aggregator.QueryAnomalyScoresFunc,
+	anomalyRecorder *generator.AnomalyRecorder, evaluationManager *generator.AppEvaluationManager) ([]*generator.Evaluator, error) {
 	count := conf.Evaluator.EvaluatorCount
 
 	client, err := helpers.CreateHTTPClient(&conf.ScalingEngine.TLSClientCerts)
@@ -184,7 +193,8 @@ func createEvaluators(logger lager.Logger, conf *config.Config, triggersChan cha
 	evaluators := make([]*generator.Evaluator, count)
 	for i := 0; i < count; i++ {
 		evaluators[i] = generator.NewEvaluator(logger, client, conf.ScalingEngine.ScalingEngineURL, triggersChan,
-			conf.DefaultBreachDurationSecs, queryMetrics, getBreaker, setCoolDownExpired)
+			conf.DefaultBreachDurationSecs, queryMetrics, queryAnomalyScores, anomalyRecorder.Record, evaluationManager.GetBreaker,
+			evaluationManager.SetCoolDownExpired, evaluationManager.SetBreached, evaluationManager.RecordScalingResult)
 	}
 
 	return evaluators, nil
@@ -199,10 +209,16 @@ func createMetricPollers(logger lager.Logger, conf *config.Config, appChan chan
 	}
 	count := conf.Aggregator.MetricPollerCount
 	client.Transport.(*http.Transport).MaxIdleConnsPerHost = count
+	sourceGuard, err := helpers.NewOutboundGuard(conf.MetricSource)
+	if err != nil {
+		logger.Error("failed to create outbound guard for metric sources", err, lager.Data{"metricSource": conf.MetricSource})
+		os.Exit(1)
+	}
+	sourceClient := sourceGuard.NewHTTPClient()
 
 	pollers := make([]*aggregator.MetricPoller, count)
 	for i := 0; i < count; i++ {
-		pollers[i] = aggregator.NewMetricPoller(logger, conf.MetricCollector.MetricCollectorURL, appChan, client, appMetricChan)
+		pollers[i] = aggregator.NewMetricPoller(logger, conf.MetricCollector.MetricCollectorURL, appChan, client, sourceClient, appMetricChan)
 	}
 
 	return pollers, nil
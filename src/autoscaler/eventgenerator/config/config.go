@@ -30,6 +30,10 @@ const (
 	DefaultBreakerConsecutiveFailureCount int64         = 3
 	DefaultHttpClientTimeout              time.Duration = 5 * time.Second
 	DefaultMetricCacheSizePerApp                        = 100
+	DefaultAnomalySmoothingFactor                       = 0.1
+	DefaultAnomalyMinSamples                            = 10
+	DefaultAnomalySeedWindow              time.Duration = time.Hour
+	DefaultAppStatusMetricsMaxApps                      = 100
 )
 
 type ServerConfig struct {
@@ -51,6 +55,9 @@ type AggregatorConfig struct {
 	PolicyPollerInterval      time.Duration `yaml:"policy_poller_interval"`
 	SaveInterval              time.Duration `yaml:"save_interval"`
 	MetricCacheSizePerApp     int           `yaml:"metric_cache_size_per_app"`
+	AnomalySmoothingFactor    float64       `yaml:"anomaly_smoothing_factor"`
+	AnomalyMinSamples         int           `yaml:"anomaly_min_samples"`
+	AnomalySeedWindow         time.Duration `yaml:"anomaly_seed_window"`
 }
 
 type EvaluatorConfig struct {
@@ -74,19 +81,28 @@ type CircuitBreakerConfig struct {
 	BackOffMaxInterval      time.Duration `yaml:"back_off_max_interval"`
 	ConsecutiveFailureCount int64         `yaml:"consecutive_failure_count"`
 }
+
+// AppStatusMetricsConfig controls the per-app metrics on the health endpoint, which carry an app_id label.
+type AppStatusMetricsConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	AppIds  []string `yaml:"app_ids"`
+	MaxApps int      `yaml:"max_apps"`
+}
 type Config struct {
-	Logging                   helpers.LoggingConfig `yaml:"logging"`
-	Server                    ServerConfig          `yaml:"server"`
-	Health                    models.HealthConfig   `yaml:"health"`
-	DB                        DBConfig              `yaml:"db"`
-	Aggregator                AggregatorConfig      `yaml:"aggregator"`
-	Evaluator                 EvaluatorConfig       `yaml:"evaluator"`
-	ScalingEngine             ScalingEngineConfig   `yaml:"scalingEngine"`
-	MetricCollector           MetricCollectorConfig `yaml:"metricCollector"`
-	DefaultStatWindowSecs     int                   `yaml:"defaultStatWindowSecs"`
-	DefaultBreachDurationSecs int                   `yaml:"defaultBreachDurationSecs"`
-	CircuitBreaker            CircuitBreakerConfig  `yaml:"circuitBreaker"`
-	HttpClientTimeout         time.Duration         `yaml:"http_client_timeout"`
+	Logging                   helpers.LoggingConfig  `yaml:"logging"`
+	Server                    ServerConfig           `yaml:"server"`
+	Health                    models.HealthConfig    `yaml:"health"`
+	DB                        DBConfig               `yaml:"db"`
+	Aggregator                AggregatorConfig       `yaml:"aggregator"`
+	Evaluator                 EvaluatorConfig        `yaml:"evaluator"`
+	ScalingEngine             ScalingEngineConfig    `yaml:"scalingEngine"`
+	MetricCollector           MetricCollectorConfig  `yaml:"metricCollector"`
+	DefaultStatWindowSecs     int                    `yaml:"defaultStatWindowSecs"`
+	DefaultBreachDurationSecs int                    `yaml:"defaultBreachDurationSecs"`
+	CircuitBreaker            CircuitBreakerConfig   `yaml:"circuitBreaker"`
+	HttpClientTimeout         time.Duration          `yaml:"http_client_timeout"`
+	AppStatusMetrics          AppStatusMetricsConfig `yaml:"app_status_metrics"`
+	MetricSource              helpers.OutboundConfig `yaml:"metric_source"`
 }
 
 func LoadConfig(bytes []byte) (*Config, error) {
@@ -108,6 +124,9 @@ func LoadConfig(bytes []byte) (*Config, error) {
 			AppMonitorChannelSize:     DefaultAppMonitorChannelSize,
 			AppMetricChannelSize:      DefaultAppMetricChannelSize,
 			MetricCacheSizePerApp:     DefaultMetricCacheSizePerApp,
+			AnomalySmoothingFactor:    DefaultAnomalySmoothingFactor,
+			AnomalyMinSamples:         DefaultAnomalyMinSamples,
+			AnomalySeedWindow:         DefaultAnomalySeedWindow,
 		},
 		Evaluator: EvaluatorConfig{
 			EvaluationManagerInterval: DefaultEvaluationExecuteInterval,
@@ -115,6 +134,9 @@ func LoadConfig(bytes []byte) (*Config, error) {
 			TriggerArrayChannelSize:   DefaultTriggerArrayChannelSize,
 		},
 		HttpClientTimeout: DefaultHttpClientTimeout,
+		AppStatusMetrics: AppStatusMetricsConfig{
+			MaxApps: DefaultAppStatusMetricsMaxApps,
+		},
 	}
 	err := yaml.Unmarshal(bytes, &conf)
 	if err != nil {
@@ -169,6 +191,15 @@ func (c *Config) Validate() error {
 	if c.Aggregator.MetricCacheSizePerApp <= 0 {
 		return fmt.Errorf("Configuration error: aggregator.metric_cache_size_per_app is less-equal than 0")
 	}
+	if c.Aggregator.AnomalySmoothingFactor <= 0 || c.Aggregator.AnomalySmoothingFactor > 1 {
+		return fmt.Errorf("Configuration error: aggregator.anomaly_smoothing_factor should be greater than 0 and less-equal than 1")
+	}
+	if c.Aggregator.AnomalyMinSamples <= 0 {
+		return fmt.Errorf("Configuration error: aggregator.anomaly_min_samples is less-equal than 0")
+	}
+	if c.Aggregator.AnomalySeedWindow < 0 {
+		return fmt.Errorf("Configuration error: aggregator.anomaly_seed_window is less than 0")
+	}
 
 	if c.Evaluator.EvaluationManagerInterval <= time.Duration(0) {
 		return fmt.Errorf("Configuration error: evaluator.evaluation_manager_execute_interval is less-equal than 0")
@@ -193,6 +224,14 @@ func (c *Config) Validate() error {
 	if c.HttpClientTimeout <= time.Duration(0) {
 		return fmt.Errorf("Configuration error: http_client_timeout is less-equal than 0")
 	}
+
+	if c.AppStatusMetrics.MaxApps < 0 {
+		return fmt.Errorf("Configuration error: app_status_metrics.max_apps is less than 0")
+	}
+
+	if _, err := helpers.NewOutboundGuard(c.MetricSource); err != nil {
+		return fmt.Errorf("Configuration error: metric_source: %s", err.Error())
+	}
 	return nil
 
 }
@@ -62,6 +62,9 @@ aggregator:
   app_monitor_channel_size: 100
   app_metric_channel_size: 100
   metric_cache_size_per_app: 500
+  anomaly_smoothing_factor: 0.2
+  anomaly_min_samples: 20
+  anomaly_seed_window: 2h
 evaluator:
   evaluation_manager_execute_interval: 30s
   evaluator_count: 10
@@ -84,6 +87,13 @@ circuitBreaker:
   back_off_initial_interval: 10s
   back_off_max_interval: 60m
   consecutive_failure_count: 5
+app_status_metrics:
+  enabled: true
+  app_ids: [app-id-1, app-id-2]
+  max_apps: 10
+metric_source:
+  allowed_hosts: [prometheus.example.com, "*.metrics.example.com"]
+  allowed_networks: [10.10.0.0/16]
 `)
 			})
 
@@ -127,6 +137,9 @@ circuitBreaker:
 						AppMonitorChannelSize:     100,
 						AppMetricChannelSize:      100,
 						MetricCacheSizePerApp:     500,
+						AnomalySmoothingFactor:    0.2,
+						AnomalyMinSamples:         20,
+						AnomalySeedWindow:         2 * time.Hour,
 					},
 					Evaluator: EvaluatorConfig{
 						EvaluationManagerInterval: 30 * time.Second,
@@ -155,6 +168,15 @@ circuitBreaker:
 						BackOffMaxInterval:      1 * time.Hour,
 						ConsecutiveFailureCount: 5,
 					},
+					AppStatusMetrics: AppStatusMetricsConfig{
+						Enabled: true,
+						AppIds:  []string{"app-id-1", "app-id-2"},
+						MaxApps: 10,
+					},
+					MetricSource: helpers.OutboundConfig{
+						AllowedHosts:    []string{"prometheus.example.com", "*.metrics.example.com"},
+						AllowedNetworks: []string{"10.10.0.0/16"},
+					},
 				}))
 			})
 		})
@@ -252,6 +274,9 @@ defaultBreachDurationSecs: 600
 						AppMetricChannelSize:      DefaultAppMetricChannelSize,
 						SaveInterval:              DefaultSaveInterval,
 						MetricCacheSizePerApp:     DefaultMetricCacheSizePerApp,
+						AnomalySmoothingFactor:    DefaultAnomalySmoothingFactor,
+						AnomalyMinSamples:         DefaultAnomalyMinSamples,
+						AnomalySeedWindow:         DefaultAnomalySeedWindow,
 					},
 					Evaluator: EvaluatorConfig{
 						EvaluationManagerInterval: DefaultEvaluationExecuteInterval,
@@ -269,6 +294,9 @@ defaultBreachDurationSecs: 600
 						BackOffMaxInterval:      DefaultBackOffMaxInterval,
 						ConsecutiveFailureCount: DefaultBreakerConsecutiveFailureCount,
 					},
+					AppStatusMetrics: AppStatusMetricsConfig{
+						MaxApps: DefaultAppStatusMetricsMaxApps,
+					},
 				}))
 			})
 		})
@@ -1092,6 +1120,9 @@ health:
 					AppMonitorChannelSize:     100,
 					AppMetricChannelSize:      100,
 					MetricCacheSizePerApp:     500,
+					AnomalySmoothingFactor:    0.1,
+					AnomalyMinSamples:         10,
+					AnomalySeedWindow:         time.Hour,
 				},
 				Evaluator: EvaluatorConfig{
 					EvaluationManagerInterval: 30 * time.Second,
@@ -1217,6 +1248,33 @@ health:
 			})
 		})
 
+		Context("when AnomalySmoothingFactor is greater than 1", func() {
+			BeforeEach(func() {
+				conf.Aggregator.AnomalySmoothingFactor = 1.5
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: aggregator.anomaly_smoothing_factor should be greater than 0 and less-equal than 1"))
+			})
+		})
+
+		Context("when AnomalyMinSamples <= 0", func() {
+			BeforeEach(func() {
+				conf.Aggregator.AnomalyMinSamples = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: aggregator.anomaly_min_samples is less-equal than 0"))
+			})
+		})
+
+		Context("when AnomalySeedWindow < 0", func() {
+			BeforeEach(func() {
+				conf.Aggregator.AnomalySeedWindow = -1
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: aggregator.anomaly_seed_window is less than 0"))
+			})
+		})
+
 		Context("when EvaluationManagerInterval <= 0", func() {
 			BeforeEach(func() {
 				conf.Evaluator.EvaluationManagerInterval = 0
@@ -1311,5 +1369,23 @@ health:
 			})
 		})
 
+		Context("when AppStatusMetrics.MaxApps < 0", func() {
+			BeforeEach(func() {
+				conf.AppStatusMetrics.MaxApps = -1
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: app_status_metrics.max_apps is less than 0"))
+			})
+		})
+
+		Context("when an allowed network of the metric sources is invalid", func() {
+			BeforeEach(func() {
+				conf.MetricSource.AllowedNetworks = []string{"not-a-network"}
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError(ContainSubstring("Configuration error: metric_source: invalid allowed network")))
+			})
+		})
+
 	})
 })
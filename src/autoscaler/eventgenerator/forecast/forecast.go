@@ -0,0 +1,201 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNotEnoughData = errors.New("not enough data to forecast")
+
+type Point struct {
+	Timestamp int64
+	Value     float64
+}
+
+type HoltWintersParams struct {
+	Alpha float64
+	Beta  float64
+	Gamma float64
+}
+
+var DefaultHoltWintersParams = HoltWintersParams{
+	Alpha: 0.5,
+	Beta:  0.05,
+	Gamma: 0.3,
+}
+
+// LinearRegression fits a least-squares line through the points and returns its value at the given timestamp.
+func LinearRegression(points []Point, at int64) (float64, error) {
+	if len(points) < 2 {
+		return 0, ErrNotEnoughData
+	}
+
+	// timestamps are shifted to the first point to keep the sums small
+	origin := points[0].Timestamp
+	n := float64(len(points))
+	var sumX, sumY, sumXX, sumXY float64
+	for _, p := range points {
+		x := float64(p.Timestamp - origin)
+		sumX += x
+		sumY += p.Value
+		sumXX += x * x
+		sumXY += x * p.Value
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, ErrNotEnoughData
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return intercept + slope*float64(at-origin), nil
+}
+
+// SeasonalLinearRegression removes the seasonal component from recent by subtracting the value observed one season
+// earlier in previous, fits a line through the remainder and adds the seasonal component back at the given timestamp.
+func SeasonalLinearRegression(recent []Point, previous []Point, season int64, at int64) (float64, error) {
+	deseasonalized := make([]Point, 0, len(recent))
+	for _, p := range recent {
+		seasonal, err := Interpolate(previous, p.Timestamp-season)
+		if err != nil {
+			continue
+		}
+		deseasonalized = append(deseasonalized, Point{Timestamp: p.Timestamp, Value: p.Value - seasonal})
+	}
+
+	trend, err := LinearRegression(deseasonalized, at)
+	if err != nil {
+		return 0, err
+	}
+	seasonal, err := Interpolate(previous, at-season)
+	if err != nil {
+		return 0, err
+	}
+	return trend + seasonal, nil
+}
+
+// Interpolate returns the value at the given timestamp, linearly interpolated between the surrounding points.
+// The points must be sorted by timestamp and the timestamp must fall within them.
+func Interpolate(points []Point, at int64) (float64, error) {
+	if len(points) == 0 || at < points[0].Timestamp || at > points[len(points)-1].Timestamp {
+		return 0, ErrNotEnoughData
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].Timestamp >= at {
+			prev := points[i-1]
+			next := points[i]
+			if next.Timestamp == prev.Timestamp {
+				return next.Value, nil
+			}
+			ratio := float64(at-prev.Timestamp) / float64(next.Timestamp-prev.Timestamp)
+			return prev.Value + ratio*(next.Value-prev.Value), nil
+		}
+	}
+	return points[0].Value, nil
+}
+
+// Resample averages the points into count buckets of the given interval beginning at start.
+// Empty buckets are filled by linear interpolation from their neighbours.
+func Resample(points []Point, start int64, interval int64, count int) ([]float64, error) {
+	if interval <= 0 || count <= 0 {
+		return nil, ErrNotEnoughData
+	}
+
+	sums := make([]float64, count)
+	nums := make([]int, count)
+	for _, p := range points {
+		if p.Timestamp < start {
+			continue
+		}
+		index := int((p.Timestamp - start) / interval)
+		if index >= count {
+			continue
+		}
+		sums[index] += p.Value
+		nums[index]++
+	}
+
+	filled := []Point{}
+	for i := 0; i < count; i++ {
+		if nums[i] > 0 {
+			filled = append(filled, Point{Timestamp: int64(i), Value: sums[i] / float64(nums[i])})
+		}
+	}
+	if len(filled) == 0 {
+		return nil, ErrNotEnoughData
+	}
+
+	series := make([]float64, count)
+	for i := 0; i < count; i++ {
+		if nums[i] > 0 {
+			series[i] = sums[i] / float64(nums[i])
+			continue
+		}
+		switch {
+		case int64(i) < filled[0].Timestamp:
+			series[i] = filled[0].Value
+		case int64(i) > filled[len(filled)-1].Timestamp:
+			series[i] = filled[len(filled)-1].Value
+		default:
+			series[i], _ = Interpolate(filled, int64(i))
+		}
+	}
+	return series, nil
+}
+
+// HoltWinters applies additive triple exponential smoothing to the evenly spaced series and returns the value
+// forecast steps periods after the last observation. A seasonLength of 0 disables the seasonal component.
+func HoltWinters(series []float64, seasonLength int, steps int, params HoltWintersParams) (float64, error) {
+	if seasonLength <= 0 {
+		return holt(series, steps, params)
+	}
+	if len(series) < 2*seasonLength {
+		return 0, ErrNotEnoughData
+	}
+
+	firstMean := mean(series[:seasonLength])
+	secondMean := mean(series[seasonLength : 2*seasonLength])
+	level := firstMean
+	trend := (secondMean - firstMean) / float64(seasonLength)
+	seasonals := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		seasonals[i] = ((series[i] - firstMean) + (series[seasonLength+i] - secondMean)) / 2
+	}
+
+	for i, value := range series {
+		seasonal := seasonals[i%seasonLength]
+		lastLevel := level
+		level = params.Alpha*(value-seasonal) + (1-params.Alpha)*(level+trend)
+		trend = params.Beta*(level-lastLevel) + (1-params.Beta)*trend
+		seasonals[i%seasonLength] = params.Gamma*(value-level) + (1-params.Gamma)*seasonal
+	}
+
+	result := level + float64(steps)*trend + seasonals[(len(series)+steps-1)%seasonLength]
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrNotEnoughData
+	}
+	return result, nil
+}
+
+func holt(series []float64, steps int, params HoltWintersParams) (float64, error) {
+	if len(series) < 2 {
+		return 0, ErrNotEnoughData
+	}
+
+	level := series[0]
+	trend := series[1] - series[0]
+	for _, value := range series[1:] {
+		lastLevel := level
+		level = params.Alpha*value + (1-params.Alpha)*(level+trend)
+		trend = params.Beta*(level-lastLevel) + (1-params.Beta)*trend
+	}
+	return level + float64(steps)*trend, nil
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
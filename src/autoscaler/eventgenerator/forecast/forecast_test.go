@@ -0,0 +1,216 @@
+package forecast_test
+
+import (
+	. "autoscaler/eventgenerator/forecast"
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forecast", func() {
+	var (
+		result float64
+		err    error
+	)
+
+	Describe("LinearRegression", func() {
+		var points []Point
+
+		JustBeforeEach(func() {
+			result, err = LinearRegression(points, 600)
+		})
+
+		Context("when the points grow linearly", func() {
+			BeforeEach(func() {
+				points = []Point{{0, 100}, {100, 110}, {200, 120}, {300, 130}}
+			})
+			It("extrapolates the line", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeNumerically("~", 160, 0.0001))
+			})
+		})
+
+		Context("when the points are noisy", func() {
+			BeforeEach(func() {
+				points = []Point{{0, 98}, {100, 112}, {200, 118}, {300, 132}}
+			})
+			It("extrapolates the least-squares line", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeNumerically("~", 163.6, 0.0001))
+			})
+		})
+
+		Context("when there is only one point", func() {
+			BeforeEach(func() {
+				points = []Point{{0, 100}}
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+
+		Context("when all points have the same timestamp", func() {
+			BeforeEach(func() {
+				points = []Point{{100, 100}, {100, 200}}
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+	})
+
+	Describe("SeasonalLinearRegression", func() {
+		var recent, previous []Point
+
+		JustBeforeEach(func() {
+			result, err = SeasonalLinearRegression(recent, previous, 1000, 1400)
+		})
+
+		Context("when the previous season covers the forecast time", func() {
+			BeforeEach(func() {
+				previous = []Point{{0, 10}, {200, 20}, {400, 80}}
+				recent = []Point{{1000, 15}, {1100, 20}, {1200, 25}}
+			})
+			It("adds the seasonal value to the deseasonalized trend", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeNumerically("~", 85, 0.0001))
+			})
+		})
+
+		Context("when the previous season does not cover the forecast time", func() {
+			BeforeEach(func() {
+				previous = []Point{{0, 10}, {200, 20}}
+				recent = []Point{{1000, 15}, {1100, 20}, {1200, 25}}
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+
+		Context("when there is no previous season", func() {
+			BeforeEach(func() {
+				previous = []Point{}
+				recent = []Point{{1000, 15}, {1100, 20}, {1200, 25}}
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+	})
+
+	Describe("Interpolate", func() {
+		points := []Point{{0, 10}, {100, 20}, {300, 0}}
+
+		It("interpolates between the surrounding points", func() {
+			Expect(Interpolate(points, 50)).To(BeNumerically("~", 15, 0.0001))
+			Expect(Interpolate(points, 200)).To(BeNumerically("~", 10, 0.0001))
+		})
+
+		It("returns the exact value at a point", func() {
+			Expect(Interpolate(points, 0)).To(BeNumerically("~", 10, 0.0001))
+			Expect(Interpolate(points, 300)).To(BeNumerically("~", 0, 0.0001))
+		})
+
+		It("errors outside of the points", func() {
+			_, err = Interpolate(points, 301)
+			Expect(err).To(Equal(ErrNotEnoughData))
+			_, err = Interpolate(points, -1)
+			Expect(err).To(Equal(ErrNotEnoughData))
+		})
+	})
+
+	Describe("Resample", func() {
+		var (
+			points []Point
+			series []float64
+		)
+
+		JustBeforeEach(func() {
+			series, err = Resample(points, 1000, 10, 5)
+		})
+
+		Context("when every bucket has points", func() {
+			BeforeEach(func() {
+				points = []Point{{1000, 1}, {1005, 3}, {1010, 4}, {1020, 5}, {1030, 6}, {1045, 7}, {1050, 100}}
+			})
+			It("averages the points in each bucket", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(series).To(Equal([]float64{2, 4, 5, 6, 7}))
+			})
+		})
+
+		Context("when some buckets are empty", func() {
+			BeforeEach(func() {
+				points = []Point{{1012, 4}, {1035, 8}}
+			})
+			It("fills them from the neighbours", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(series).To(Equal([]float64{4, 4, 6, 8, 8}))
+			})
+		})
+
+		Context("when there are no points in range", func() {
+			BeforeEach(func() {
+				points = []Point{{900, 4}, {2000, 8}}
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+	})
+
+	Describe("HoltWinters", func() {
+		var (
+			series       []float64
+			seasonLength int
+			steps        int
+		)
+
+		JustBeforeEach(func() {
+			result, err = HoltWinters(series, seasonLength, steps, DefaultHoltWintersParams)
+		})
+
+		Context("without seasonality", func() {
+			BeforeEach(func() {
+				seasonLength = 0
+				steps = 5
+				series = []float64{}
+				for i := 0; i < 50; i++ {
+					series = append(series, 100+2*float64(i))
+				}
+			})
+			It("follows the trend", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeNumerically("~", 208, 0.5))
+			})
+		})
+
+		Context("with seasonality", func() {
+			BeforeEach(func() {
+				seasonLength = 24
+				steps = 7
+				series = []float64{}
+				for i := 0; i < 3*seasonLength; i++ {
+					series = append(series, 100+50*math.Sin(2*math.Pi*float64(i)/float64(seasonLength)))
+				}
+			})
+			It("follows the season", func() {
+				Expect(err).NotTo(HaveOccurred())
+				// the forecast lands on the peak of the sine wave
+				Expect(result).To(BeNumerically("~", 150, 5))
+			})
+		})
+
+		Context("when the series is shorter than two seasons", func() {
+			BeforeEach(func() {
+				seasonLength = 24
+				steps = 1
+				series = make([]float64, 30)
+			})
+			It("should error", func() {
+				Expect(err).To(Equal(ErrNotEnoughData))
+			})
+		})
+	})
+})
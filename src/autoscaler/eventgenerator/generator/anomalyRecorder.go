@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"autoscaler/healthendpoint"
+	"autoscaler/models"
+	"sync/atomic"
+)
+
+// AnomalyRecorder saves the anomalies of the anomaly rules in notify mode and counts
+// the recorded and the failed anomalies for the health endpoint.
+type AnomalyRecorder struct {
+	saveAnomalyScore func(*models.AnomalyScore) error
+	recorded         int64
+	failed           int64
+}
+
+func NewAnomalyRecorder(saveAnomalyScore func(*models.AnomalyScore) error) *AnomalyRecorder {
+	return &AnomalyRecorder{
+		saveAnomalyScore: saveAnomalyScore,
+	}
+}
+
+func (r *AnomalyRecorder) Record(score *models.AnomalyScore) error {
+	err := r.saveAnomalyScore(score)
+	if err != nil {
+		atomic.AddInt64(&r.failed, 1)
+		return err
+	}
+	atomic.AddInt64(&r.recorded, 1)
+	return nil
+}
+
+func (r *AnomalyRecorder) GetAnomalyRecordStatus() *healthendpoint.AnomalyRecordStatus {
+	return &healthendpoint.AnomalyRecordStatus{
+		Recorded: atomic.LoadInt64(&r.recorded),
+		Failed:   atomic.LoadInt64(&r.failed),
+	}
+}
@@ -0,0 +1,55 @@
+package generator_test
+
+import (
+	. "autoscaler/eventgenerator/generator"
+	"autoscaler/healthendpoint"
+	"autoscaler/models"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AnomalyRecorder", func() {
+	var (
+		recorder *AnomalyRecorder
+		saveErr  error
+		saved    []*models.AnomalyScore
+		score    = &models.AnomalyScore{AppId: "an-app-id", MetricType: "memoryused", Score: 4.5}
+		err      error
+	)
+
+	BeforeEach(func() {
+		saveErr = nil
+		saved = nil
+	})
+
+	JustBeforeEach(func() {
+		recorder = NewAnomalyRecorder(func(s *models.AnomalyScore) error {
+			if saveErr != nil {
+				return saveErr
+			}
+			saved = append(saved, s)
+			return nil
+		})
+		err = recorder.Record(score)
+	})
+
+	Context("when the anomaly is saved", func() {
+		It("counts the recorded anomaly", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(Equal([]*models.AnomalyScore{score}))
+			Expect(recorder.GetAnomalyRecordStatus()).To(Equal(&healthendpoint.AnomalyRecordStatus{Recorded: 1, Failed: 0}))
+		})
+	})
+
+	Context("when saving the anomaly fails", func() {
+		BeforeEach(func() {
+			saveErr = errors.New("an error")
+		})
+		It("returns the error and counts the failure", func() {
+			Expect(err).To(MatchError("an error"))
+			Expect(recorder.GetAnomalyRecordStatus()).To(Equal(&healthendpoint.AnomalyRecordStatus{Recorded: 0, Failed: 1}))
+		})
+	})
+})
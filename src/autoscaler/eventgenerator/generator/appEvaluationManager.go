@@ -3,6 +3,7 @@ package generator
 import (
 	"autoscaler/eventgenerator/aggregator"
 	"autoscaler/eventgenerator/config"
+	"autoscaler/healthendpoint"
 	"autoscaler/models"
 	"sync"
 	"time"
@@ -15,6 +16,13 @@ import (
 
 type ConsumeAppMonitorMap func(map[string][]*models.Trigger, chan []*models.Trigger)
 
+var scalingStatusNames = map[models.ScalingStatus]string{
+	models.ScalingStatusSucceeded: "succeeded",
+	models.ScalingStatusFailed:    "failed",
+	models.ScalingStatusIgnored:   "ignored",
+	models.ScalingStatusSimulated: "simulated",
+}
+
 type AppEvaluationManager struct {
 	evaluateInterval time.Duration
 	logger           lager.Logger
@@ -22,15 +30,20 @@ type AppEvaluationManager struct {
 	doneChan         chan bool
 	triggerChan      chan []*models.Trigger
 	getPolicies      aggregator.GetPoliciesFunc
+	getLatestMetrics aggregator.GetLatestAppMetricsFunc
 	breakerConfig    config.CircuitBreakerConfig
 	breakers         map[string]*circuit.Breaker
-	cooldownExpired  map[string]int64
+	cooldownExpired  map[string]map[string]int64
+	instances        map[string]int
+	breaches         map[string]map[string]bool
+	scalingDecisions map[string]map[string]int64
 	breakerLock      *sync.RWMutex
 	cooldownLock     *sync.Mutex
+	statusLock       *sync.RWMutex
 }
 
 func NewAppEvaluationManager(logger lager.Logger, evaluateInterval time.Duration, emClock clock.Clock,
-	triggerChan chan []*models.Trigger, getPolicies aggregator.GetPoliciesFunc,
+	triggerChan chan []*models.Trigger, getPolicies aggregator.GetPoliciesFunc, getLatestMetrics aggregator.GetLatestAppMetricsFunc,
 	breakerConfig config.CircuitBreakerConfig) (*AppEvaluationManager, error) {
 	return &AppEvaluationManager{
 		evaluateInterval: evaluateInterval,
@@ -39,10 +52,15 @@ func NewAppEvaluationManager(logger lager.Logger, evaluateInterval time.Duration
 		doneChan:         make(chan bool),
 		triggerChan:      triggerChan,
 		getPolicies:      getPolicies,
+		getLatestMetrics: getLatestMetrics,
 		breakerConfig:    breakerConfig,
-		cooldownExpired:  map[string]int64{},
+		cooldownExpired:  map[string]map[string]int64{},
+		instances:        map[string]int{},
+		breaches:         map[string]map[string]bool{},
+		scalingDecisions: map[string]map[string]int64{},
 		breakerLock:      &sync.RWMutex{},
 		cooldownLock:     &sync.Mutex{},
+		statusLock:       &sync.RWMutex{},
 	}, nil
 }
 
@@ -53,35 +71,69 @@ func (a *AppEvaluationManager) getTriggers(policyMap map[string]*models.AppPolic
 	triggersByType := make(map[string][]*models.Trigger)
 	now := a.emClock.Now().UnixNano()
 	for appId, policy := range policyMap {
-		for _, rule := range policy.ScalingPolicy.ScalingRules {
-			a.cooldownLock.Lock()
-			cooldownExpiredAt, found := a.cooldownExpired[appId]
-			a.cooldownLock.Unlock()
-			if found {
-				if cooldownExpiredAt > now {
-					continue
-				}
-			}
-			triggerKey := appId + "#" + rule.MetricType
-			triggers, exist := triggersByType[triggerKey]
-			if !exist {
-				triggers = []*models.Trigger{}
-			}
-			triggers = append(triggers, &models.Trigger{
-				AppId:                 appId,
-				MetricType:            rule.MetricType,
-				BreachDurationSeconds: rule.BreachDurationSeconds,
-				CoolDownSeconds:       rule.CoolDownSeconds,
-				Threshold:             rule.Threshold,
-				Operator:              rule.Operator,
-				Adjustment:            rule.Adjustment,
-			})
-			triggersByType[triggerKey] = triggers
+		if policy.Pause.IsActive(now) {
+			continue
 		}
+		a.addTriggers(triggersByType, appId, policy.ScalingPolicy, policy.ScalingPolicy.ScalingRules, false, now)
+		a.addTriggers(triggersByType, appId, policy.ScalingPolicy, policy.ScalingPolicy.ScheduleOnlyRules(), true, now)
 	}
 	return triggersByType
 }
 
+// addTriggers adds the triggers of the rules of an app. The rules of schedule overrides are evaluated apart from the
+// rules of the policy, so that a breach of one does not hide the other.
+func (a *AppEvaluationManager) addTriggers(triggersByType map[string][]*models.Trigger, appId string, policy *models.ScalingPolicy,
+	rules []*models.ScalingRule, scheduleOnly bool, now int64) {
+	for _, rule := range rules {
+		ruleKey := ""
+		if policy.CoolDownPerRule {
+			ruleKey = rule.Key()
+		}
+		if a.isInCoolDown(appId, rule.ScalingDirection(), ruleKey, now) {
+			continue
+		}
+		triggerKey := appId + "#" + triggerGroup(rule)
+		if scheduleOnly {
+			triggerKey = appId + "#schedule#" + triggerGroup(rule)
+		}
+		triggers, exist := triggersByType[triggerKey]
+		if !exist {
+			triggers = []*models.Trigger{}
+		}
+		triggers = append(triggers, &models.Trigger{
+			AppId:                       appId,
+			MetricType:                  rule.MetricType,
+			BreachDurationSeconds:       rule.BreachDurationSeconds,
+			CoolDownSeconds:             rule.CoolDownSeconds,
+			Threshold:                   rule.Threshold,
+			Operator:                    rule.Operator,
+			Adjustment:                  rule.Adjustment,
+			Predictive:                  rule.Predictive,
+			TargetValue:                 rule.TargetValue,
+			ScaleInDamping:              rule.ScaleInDamping,
+			Aggregation:                 rule.Aggregation,
+			Condition:                   rule.Condition,
+			RateOfChange:                rule.RateOfChange,
+			Anomaly:                     rule.Anomaly,
+			Mode:                        policy.Mode,
+			ScaleInStabilizationSeconds: policy.ScaleInStabilizationSeconds,
+			ScaleInMaxStep:              policy.ScaleInMaxStep,
+			CoolDownPerRule:             policy.CoolDownPerRule,
+			ScheduleOnly:                scheduleOnly,
+		})
+		triggersByType[triggerKey] = triggers
+	}
+}
+
+// triggerGroup returns the metric type of the rule, or the condition of a compound rule. The triggers of the rules on
+// the same metrics are evaluated together.
+func triggerGroup(rule *models.ScalingRule) string {
+	if rule.IsCompound() {
+		return rule.Condition.String()
+	}
+	return rule.MetricType
+}
+
 func (a *AppEvaluationManager) Start() {
 	go a.doEvaluate()
 	a.logger.Info("started")
@@ -125,6 +177,8 @@ func (a *AppEvaluationManager) doEvaluate() {
 			a.breakers = newBreakers
 			a.breakerLock.Unlock()
 
+			a.pruneStatuses(policies)
+
 			triggers := a.getTriggers(policies)
 			for _, triggerArray := range triggers {
 				a.triggerChan <- triggerArray
@@ -139,8 +193,134 @@ func (a *AppEvaluationManager) GetBreaker(appID string) *circuit.Breaker {
 	return a.breakers[appID]
 }
 
-func (a *AppEvaluationManager) SetCoolDownExpired(appID string, expiredAt int64) {
+// SetCoolDownExpired records the cooldown of the app in the scaling direction, the rule key is empty unless the
+// cooldown is tracked per rule. An empty direction sets the cooldown of both directions.
+func (a *AppEvaluationManager) SetCoolDownExpired(appID string, direction string, ruleKey string, expiredAt int64) {
+	a.cooldownLock.Lock()
+	defer a.cooldownLock.Unlock()
+	appCooldowns, found := a.cooldownExpired[appID]
+	if !found {
+		appCooldowns = map[string]int64{}
+		a.cooldownExpired[appID] = appCooldowns
+	}
+	if direction == "" {
+		appCooldowns[cooldownKey(models.ScalingDirectionOut, ruleKey)] = expiredAt
+		appCooldowns[cooldownKey(models.ScalingDirectionIn, ruleKey)] = expiredAt
+		return
+	}
+	appCooldowns[cooldownKey(direction, ruleKey)] = expiredAt
+}
+
+// isInCoolDown checks the cooldown of the rule, a rule that scales in both directions is in cooldown only when
+// both directions are.
+func (a *AppEvaluationManager) isInCoolDown(appID string, direction string, ruleKey string, now int64) bool {
 	a.cooldownLock.Lock()
 	defer a.cooldownLock.Unlock()
-	a.cooldownExpired[appID] = expiredAt
+	appCooldowns := a.cooldownExpired[appID]
+	if direction == "" {
+		return appCooldowns[cooldownKey(models.ScalingDirectionOut, ruleKey)] > now &&
+			appCooldowns[cooldownKey(models.ScalingDirectionIn, ruleKey)] > now
+	}
+	return appCooldowns[cooldownKey(direction, ruleKey)] > now
+}
+
+func cooldownKey(direction string, ruleKey string) string {
+	return direction + "#" + ruleKey
+}
+
+func (a *AppEvaluationManager) SetBreached(appID string, key string, breached bool) {
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+	appBreaches, found := a.breaches[appID]
+	if !found {
+		appBreaches = map[string]bool{}
+		a.breaches[appID] = appBreaches
+	}
+	appBreaches[key] = breached
+}
+
+// RecordScalingResult counts the scaling decision of the scaling engine, a nil result counts as a failed decision.
+func (a *AppEvaluationManager) RecordScalingResult(appID string, result *models.AppScalingResult) {
+	status := models.ScalingStatusFailed
+	if result != nil {
+		status = result.Status
+	}
+
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+	if result != nil {
+		a.instances[appID] = result.Instances
+	}
+	decisions, found := a.scalingDecisions[appID]
+	if !found {
+		decisions = map[string]int64{}
+		a.scalingDecisions[appID] = decisions
+	}
+	decisions[scalingStatusNames[status]]++
+}
+
+func (a *AppEvaluationManager) pruneStatuses(policies map[string]*models.AppPolicy) {
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+	for appID := range a.breaches {
+		if _, found := policies[appID]; !found {
+			delete(a.breaches, appID)
+		}
+	}
+	for appID := range a.instances {
+		if _, found := policies[appID]; !found {
+			delete(a.instances, appID)
+		}
+	}
+	for appID := range a.scalingDecisions {
+		if _, found := policies[appID]; !found {
+			delete(a.scalingDecisions, appID)
+		}
+	}
+}
+
+// GetAppStatuses returns the status of the apps handled by this event generator for the app status collector.
+func (a *AppEvaluationManager) GetAppStatuses() []*healthendpoint.AppStatus {
+	policies := a.getPolicies()
+	now := a.emClock.Now().UnixNano()
+
+	statuses := make([]*healthendpoint.AppStatus, 0, len(policies))
+	for appID, policy := range policies {
+		status := &healthendpoint.AppStatus{
+			AppId:            appID,
+			Instances:        -1,
+			InstanceMin:      policy.ScalingPolicy.InstanceMin,
+			InstanceMax:      policy.ScalingPolicy.InstanceMax,
+			Metrics:          a.getLatestMetrics(appID),
+			Breaches:         map[string]bool{},
+			ScalingDecisions: map[string]int64{},
+		}
+
+		a.statusLock.RLock()
+		if instances, found := a.instances[appID]; found {
+			status.Instances = instances
+		}
+		for key, breached := range a.breaches[appID] {
+			status.Breaches[key] = breached
+		}
+		for name, count := range a.scalingDecisions[appID] {
+			status.ScalingDecisions[name] = count
+		}
+		a.statusLock.RUnlock()
+
+		a.cooldownLock.Lock()
+		for _, expiredAt := range a.cooldownExpired[appID] {
+			if remaining := time.Duration(expiredAt - now); remaining > status.CooldownRemaining {
+				status.CooldownRemaining = remaining
+			}
+		}
+		a.cooldownLock.Unlock()
+
+		if breaker := a.GetBreaker(appID); breaker != nil {
+			status.CircuitBreakerTripped = breaker.Tripped()
+			status.CircuitBreakerConsecutiveFailures = breaker.ConsecFailures()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
 }
@@ -20,6 +20,7 @@ var _ = Describe("AppEvaluationManager", func() {
 
 	var (
 		getPolicies          aggregator.GetPoliciesFunc
+		getLatestMetrics     aggregator.GetLatestAppMetricsFunc
 		logger               lager.Logger
 		fclock               *fakeclock.FakeClock
 		manager              *AppEvaluationManager
@@ -73,12 +74,15 @@ var _ = Describe("AppEvaluationManager", func() {
 		testEvaluateInterval = 1 * time.Second
 		logger = lagertest.NewTestLogger("ApplicationManager-test")
 		triggerArrayChan = make(chan []*models.Trigger, 10)
+		getLatestMetrics = func(appID string) []*models.AppMetric {
+			return []*models.AppMetric{}
+		}
 	})
 
 	Describe("Start", func() {
 		JustBeforeEach(func() {
 			var err error
-			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, testBreakerConfig)
+			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, getLatestMetrics, testBreakerConfig)
 			Expect(err).NotTo(HaveOccurred())
 			manager.Start()
 			Eventually(fclock.WatcherCount).Should(Equal(1))
@@ -142,7 +146,7 @@ var _ = Describe("AppEvaluationManager", func() {
 				})
 
 				JustBeforeEach(func() {
-					manager.SetCoolDownExpired(testAppId2, fakeTime.Add(time.Duration(30*testEvaluateInterval)).UnixNano())
+					manager.SetCoolDownExpired(testAppId2, models.ScalingDirectionIn, "", fakeTime.Add(time.Duration(30*testEvaluateInterval)).UnixNano())
 				})
 
 				It("should add triggers to evaluate after cooldown expired", func() {
@@ -172,6 +176,250 @@ var _ = Describe("AppEvaluationManager", func() {
 			})
 		})
 
+		Context("when there is cooldownExpiredAt setting of the other direction", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: appPolicy1,
+						testAppId2: appPolicy2,
+					}
+				}
+			})
+
+			JustBeforeEach(func() {
+				manager.SetCoolDownExpired(testAppId1, models.ScalingDirectionIn, "", fakeTime.Add(time.Duration(30*testEvaluateInterval)).UnixNano())
+				manager.SetCoolDownExpired(testAppId2, models.ScalingDirectionIn, "", fakeTime.Add(time.Duration(30*testEvaluateInterval)).UnixNano())
+			})
+
+			It("should add the triggers of the rules scaling out only", func() {
+				var arr []*models.Trigger
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(&arr))
+				Expect(arr).To(HaveLen(1))
+				Expect(arr[0].AppId).To(Equal(testAppId1))
+				Consistently(triggerArrayChan).ShouldNot(Receive())
+			})
+		})
+
+		Context("when an app is paused", func() {
+			var pause *models.AppPause
+
+			BeforeEach(func() {
+				pause = &models.AppPause{AppId: testAppId2, PausedBy: "a-user-id"}
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: appPolicy1,
+						testAppId2: {AppId: testAppId2, ScalingPolicy: appPolicy2.ScalingPolicy, Pause: pause},
+					}
+				}
+			})
+
+			It("should not add the triggers of the paused app", func() {
+				var arr []*models.Trigger
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(&arr))
+				Expect(arr).To(HaveLen(1))
+				Expect(arr[0].AppId).To(Equal(testAppId1))
+				Consistently(triggerArrayChan).ShouldNot(Receive())
+			})
+
+			Context("when the pause has expired", func() {
+				BeforeEach(func() {
+					pause.ExpireAt = fakeTime.Add(5 * testEvaluateInterval).UnixNano()
+				})
+
+				It("should add the triggers of the app again", func() {
+					var arr []*models.Trigger
+					fclock.Increment(10 * testEvaluateInterval)
+					Eventually(triggerArrayChan).Should(Receive(&arr))
+					Eventually(triggerArrayChan).Should(Receive(&arr))
+				})
+			})
+		})
+
+		Context("when the cooldown is tracked per rule", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: {
+							AppId: testAppId1,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax:     5,
+								InstanceMin:     1,
+								CoolDownPerRule: true,
+								ScalingRules: []*models.ScalingRule{
+									{MetricType: "cpu", Operator: ">", Threshold: 70, Adjustment: "+1"},
+									{MetricType: "throughput", Operator: ">", Threshold: 500, Adjustment: "+1"},
+								},
+							},
+						},
+					}
+				}
+			})
+
+			JustBeforeEach(func() {
+				manager.SetCoolDownExpired(testAppId1, models.ScalingDirectionOut, "cpu:avg > 70 +1", fakeTime.Add(time.Duration(30*testEvaluateInterval)).UnixNano())
+			})
+
+			It("should add the triggers of the rules not in cooldown", func() {
+				var arr []*models.Trigger
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(&arr))
+				Expect(arr).To(HaveLen(1))
+				Expect(arr[0].MetricType).To(Equal("throughput"))
+				Expect(arr[0].CoolDownPerRule).To(BeTrue())
+				Consistently(triggerArrayChan).ShouldNot(Receive())
+			})
+		})
+
+		Context("when there is a scaling rule with a condition", func() {
+			var condition *models.ScalingCondition
+
+			BeforeEach(func() {
+				condition = &models.ScalingCondition{
+					And: []*models.ScalingCondition{
+						{MetricType: "cpu", Operator: ">", Threshold: 70},
+						{MetricType: "throughput", Operator: ">", Threshold: 500},
+					},
+				}
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: {
+							AppId: testAppId1,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax: 5,
+								InstanceMin: 1,
+								ScalingRules: []*models.ScalingRule{
+									{
+										BreachDurationSeconds: 200,
+										Condition:             condition,
+										Adjustment:            "+1",
+									},
+								},
+							},
+						},
+					}
+				}
+			})
+
+			It("should add the trigger with the condition to evaluate", func() {
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(Equal([]*models.Trigger{{
+					AppId:                 testAppId1,
+					BreachDurationSeconds: 200,
+					Adjustment:            "+1",
+					Condition:             condition,
+				}})))
+			})
+		})
+
+		Context("when the policy is in shadow mode", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: {
+							AppId: testAppId1,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax:  5,
+								InstanceMin:  1,
+								Mode:         models.PolicyModeShadow,
+								ScalingRules: appPolicy1.ScalingPolicy.ScalingRules,
+							},
+						},
+					}
+				}
+			})
+
+			It("should add triggers in shadow mode to evaluate", func() {
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(Equal([]*models.Trigger{{
+					AppId:                 testAppId1,
+					MetricType:            testMetricName,
+					BreachDurationSeconds: 200,
+					CoolDownSeconds:       200,
+					Threshold:             80,
+					Operator:              ">=",
+					Adjustment:            "1",
+					Mode:                  models.PolicyModeShadow,
+				}})))
+			})
+		})
+
+		Context("when the policy has scale-in settings", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: {
+							AppId: testAppId1,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax:                 5,
+								InstanceMin:                 1,
+								ScaleInStabilizationSeconds: 300,
+								ScaleInMaxStep:              1,
+								ScalingRules:                appPolicy1.ScalingPolicy.ScalingRules,
+							},
+						},
+					}
+				}
+			})
+
+			It("should add triggers with the scale-in settings to evaluate", func() {
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(Equal([]*models.Trigger{{
+					AppId:                       testAppId1,
+					MetricType:                  testMetricName,
+					BreachDurationSeconds:       200,
+					CoolDownSeconds:             200,
+					Threshold:                   80,
+					Operator:                    ">=",
+					Adjustment:                  "1",
+					ScaleInStabilizationSeconds: 300,
+					ScaleInMaxStep:              1,
+				}})))
+			})
+		})
+
+		Context("when a schedule of the policy overrides the scaling rules", func() {
+			BeforeEach(func() {
+				getPolicies = func() map[string]*models.AppPolicy {
+					return map[string]*models.AppPolicy{
+						testAppId1: {
+							AppId: testAppId1,
+							ScalingPolicy: &models.ScalingPolicy{
+								InstanceMax: 5,
+								InstanceMin: 1,
+								Schedules: &models.ScalingSchedules{
+									SpecificDateSchedules: []*models.SpecificDateSchedule{{
+										Override: &models.ScheduleOverride{
+											Mode: models.ScheduleOverrideRules,
+											ScalingRules: []*models.ScalingRule{{
+												MetricType: testMetricName,
+												Threshold:  90,
+												Operator:   ">",
+												Adjustment: "+2",
+											}},
+										},
+									}},
+								},
+							},
+						},
+					}
+				}
+			})
+
+			It("should add schedule-only triggers to evaluate", func() {
+				fclock.Increment(10 * testEvaluateInterval)
+				Eventually(triggerArrayChan).Should(Receive(Equal([]*models.Trigger{{
+					AppId:        testAppId1,
+					MetricType:   testMetricName,
+					Threshold:    90,
+					Operator:     ">",
+					Adjustment:   "+2",
+					ScheduleOnly: true,
+				}})))
+			})
+		})
+
 		Context("when there is no trigger", func() {
 			BeforeEach(func() {
 				getPolicies = func() map[string]*models.AppPolicy {
@@ -194,7 +442,7 @@ var _ = Describe("AppEvaluationManager", func() {
 			}
 
 			var err error
-			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, testBreakerConfig)
+			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, getLatestMetrics, testBreakerConfig)
 			Expect(err).NotTo(HaveOccurred())
 			manager.Start()
 			Eventually(fclock.WatcherCount).Should(Equal(1))
@@ -224,7 +472,7 @@ var _ = Describe("AppEvaluationManager", func() {
 				return map[string]*models.AppPolicy{testAppId1: appPolicy1}
 			}
 			var err error
-			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, testBreakerConfig)
+			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, getLatestMetrics, testBreakerConfig)
 			Expect(err).NotTo(HaveOccurred())
 			manager.Start()
 			Eventually(fclock.WatcherCount).Should(Equal(1))
@@ -261,7 +509,7 @@ var _ = Describe("AppEvaluationManager", func() {
 			}
 
 			var err error
-			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, testBreakerConfig)
+			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, getLatestMetrics, testBreakerConfig)
 			Expect(err).NotTo(HaveOccurred())
 			manager.Start()
 			Eventually(fclock.WatcherCount).Should(Equal(1))
@@ -269,18 +517,21 @@ var _ = Describe("AppEvaluationManager", func() {
 
 		It("insert the cooldownExpiredAt records in map", func() {
 
-			manager.SetCoolDownExpired(testAppId1, fakeTime.Add(time.Duration(20)*time.Second).UnixNano())
-			manager.SetCoolDownExpired(testAppId2, fakeTime.Add(time.Duration(30)*time.Second).UnixNano())
+			manager.SetCoolDownExpired(testAppId1, models.ScalingDirectionOut, "", fakeTime.Add(time.Duration(20)*time.Second).UnixNano())
+			manager.SetCoolDownExpired(testAppId2, models.ScalingDirectionIn, "", fakeTime.Add(time.Duration(30)*time.Second).UnixNano())
 
 			v := reflect.ValueOf(manager).Elem()
 			coolDownExpiredReflect := v.FieldByName("cooldownExpired")
 			Expect(coolDownExpiredReflect.Len()).Should(Equal(2))
 			for _, key := range coolDownExpiredReflect.MapKeys() {
-				value := coolDownExpiredReflect.MapIndex(key).Int()
+				appCoolDowns := coolDownExpiredReflect.MapIndex(key)
+				Expect(appCoolDowns.Len()).Should(Equal(1))
 				if key.String() == testAppId1 {
+					value := appCoolDowns.MapIndex(reflect.ValueOf("out#")).Int()
 					Expect(value).Should(Equal(fakeTime.Add(time.Duration(20) * time.Second).UnixNano()))
 				}
 				if key.String() == testAppId2 {
+					value := appCoolDowns.MapIndex(reflect.ValueOf("in#")).Int()
 					Expect(value).Should(Equal(fakeTime.Add(time.Duration(30) * time.Second).UnixNano()))
 				}
 
@@ -288,9 +539,87 @@ var _ = Describe("AppEvaluationManager", func() {
 
 		})
 
+		It("insert the cooldownExpiredAt records of both directions when the direction is empty", func() {
+			manager.SetCoolDownExpired(testAppId1, "", "", fakeTime.Add(time.Duration(20)*time.Second).UnixNano())
+
+			v := reflect.ValueOf(manager).Elem()
+			appCoolDowns := v.FieldByName("cooldownExpired").MapIndex(reflect.ValueOf(testAppId1))
+			Expect(appCoolDowns.Len()).Should(Equal(2))
+		})
+
+		AfterEach(func() {
+			manager.Stop()
+		})
+
+	})
+
+	Describe("GetAppStatuses", func() {
+		var latestMetric *models.AppMetric
+
+		BeforeEach(func() {
+			getPolicies = func() map[string]*models.AppPolicy {
+				return map[string]*models.AppPolicy{
+					testAppId1: appPolicy1,
+					testAppId2: appPolicy2,
+				}
+			}
+			latestMetric = &models.AppMetric{
+				AppId:      testAppId1,
+				MetricType: testMetricName,
+				Value:      90,
+				Timestamp:  fakeTime.UnixNano(),
+			}
+			getLatestMetrics = func(appID string) []*models.AppMetric {
+				if appID == testAppId1 {
+					return []*models.AppMetric{latestMetric}
+				}
+				return []*models.AppMetric{}
+			}
+
+			var err error
+			manager, err = NewAppEvaluationManager(logger, testEvaluateInterval, fclock, triggerArrayChan, getPolicies, getLatestMetrics, testBreakerConfig)
+			Expect(err).NotTo(HaveOccurred())
+			manager.Start()
+			Eventually(fclock.WatcherCount).Should(Equal(1))
+			fclock.Increment(1 * testEvaluateInterval)
+			Eventually(func() *circuit.Breaker { return manager.GetBreaker(testAppId1) }).ShouldNot(BeNil())
+		})
+
 		AfterEach(func() {
 			manager.Stop()
 		})
 
+		It("returns the status of every app", func() {
+			manager.SetCoolDownExpired(testAppId1, models.ScalingDirectionOut, "", fclock.Now().Add(20*time.Second).UnixNano())
+			manager.SetCoolDownExpired(testAppId1, models.ScalingDirectionIn, "", fclock.Now().Add(10*time.Second).UnixNano())
+			manager.SetBreached(testAppId1, testMetricName, true)
+			manager.RecordScalingResult(testAppId1, &models.AppScalingResult{AppId: testAppId1, Status: models.ScalingStatusSucceeded, Instances: 3})
+			manager.RecordScalingResult(testAppId1, &models.AppScalingResult{AppId: testAppId1, Status: models.ScalingStatusIgnored, Instances: 3})
+			manager.RecordScalingResult(testAppId1, nil)
+			manager.GetBreaker(testAppId1).Fail()
+
+			statuses := manager.GetAppStatuses()
+			Expect(statuses).To(HaveLen(2))
+			for _, status := range statuses {
+				switch status.AppId {
+				case testAppId1:
+					Expect(status.Instances).To(Equal(3))
+					Expect(status.InstanceMin).To(Equal(1))
+					Expect(status.InstanceMax).To(Equal(5))
+					Expect(status.Metrics).To(Equal([]*models.AppMetric{latestMetric}))
+					Expect(status.Breaches).To(Equal(map[string]bool{testMetricName: true}))
+					Expect(status.CooldownRemaining).To(Equal(20 * time.Second))
+					Expect(status.CircuitBreakerConsecutiveFailures).To(Equal(int64(1)))
+					Expect(status.ScalingDecisions).To(Equal(map[string]int64{"succeeded": 1, "ignored": 1, "failed": 1}))
+				case testAppId2:
+					Expect(status.Instances).To(Equal(-1))
+					Expect(status.Metrics).To(BeEmpty())
+					Expect(status.Breaches).To(BeEmpty())
+					Expect(status.CooldownRemaining).To(BeZero())
+					Expect(status.CircuitBreakerTripped).To(BeFalse())
+					Expect(status.ScalingDecisions).To(BeEmpty())
+				}
+			}
+		})
 	})
 })
@@ -3,14 +3,15 @@ package generator
 import (
 	"autoscaler/db"
 	"autoscaler/eventgenerator/aggregator"
+	"autoscaler/eventgenerator/forecast"
 	"autoscaler/models"
 	"autoscaler/routes"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
-	"strconv"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -19,6 +20,14 @@ import (
 
 var validOperators = []string{">", ">=", "<", "<="}
 
+const (
+	defaultForecastHistoryWindow         = 1 * time.Hour
+	defaultSeasonalForecastHistoryWindow = 3 * 24 * time.Hour
+	forecastSeason                       = 24 * time.Hour
+	forecastSampleInterval               = 1 * time.Minute
+	anomalyScoreUnit                     = "stddev"
+)
+
 type Evaluator struct {
 	logger                    lager.Logger
 	httpClient                *http.Client
@@ -27,12 +36,18 @@ type Evaluator struct {
 	doneChan                  chan bool
 	defaultBreachDurationSecs int
 	queryAppMetrics           aggregator.QueryAppMetricsFunc
+	queryAnomalyScores        aggregator.QueryAnomalyScoresFunc
+	saveAnomalyScore          func(*models.AnomalyScore) error
 	getBreaker                func(string) *circuit.Breaker
-	setCoolDownExpired        func(string, int64)
+	setCoolDownExpired        func(string, string, string, int64)
+	setBreached               func(string, string, bool)
+	recordScalingResult       func(string, *models.AppScalingResult)
 }
 
 func NewEvaluator(logger lager.Logger, httpClient *http.Client, scalingEngineUrl string, triggerChan chan []*models.Trigger,
-	defaultBreachDurationSecs int, queryAppMetrics aggregator.QueryAppMetricsFunc, getBreaker func(string) *circuit.Breaker, setCoolDownExpired func(string, int64)) *Evaluator {
+	defaultBreachDurationSecs int, queryAppMetrics aggregator.QueryAppMetricsFunc, queryAnomalyScores aggregator.QueryAnomalyScoresFunc,
+	saveAnomalyScore func(*models.AnomalyScore) error, getBreaker func(string) *circuit.Breaker, setCoolDownExpired func(string, string, string, int64),
+	setBreached func(string, string, bool), recordScalingResult func(string, *models.AppScalingResult)) *Evaluator {
 	return &Evaluator{
 		logger:                    logger.Session("Evaluator"),
 		httpClient:                httpClient,
@@ -41,8 +56,12 @@ func NewEvaluator(logger lager.Logger, httpClient *http.Client, scalingEngineUrl
 		doneChan:                  make(chan bool),
 		defaultBreachDurationSecs: defaultBreachDurationSecs,
 		queryAppMetrics:           queryAppMetrics,
+		queryAnomalyScores:        queryAnomalyScores,
+		saveAnomalyScore:          saveAnomalyScore,
 		getBreaker:                getBreaker,
 		setCoolDownExpired:        setCoolDownExpired,
+		setBreached:               setBreached,
+		recordScalingResult:       recordScalingResult,
 	}
 }
 
@@ -73,6 +92,49 @@ func (e *Evaluator) doEvaluate(triggerArray []*models.Trigger) {
 		if trigger.BreachDurationSeconds <= 0 {
 			trigger.BreachDurationSeconds = e.defaultBreachDurationSecs
 		}
+
+		if trigger.IsTargetTracking() {
+			appMetricList, err := e.retrieveAppMetrics(trigger, trigger.MetricType, trigger.AggregationFunction())
+			if err != nil {
+				continue
+			}
+			if len(appMetricList) == 0 {
+				e.logger.Debug("no-available-appmetric", lager.Data{"trigger": trigger})
+				e.setBreached(trigger.AppId, trigger.RuleKey(), false)
+				continue
+			}
+			metricValue, isOffTarget := e.evaluateTargetTracking(trigger, appMetricList)
+			e.setBreached(trigger.AppId, trigger.RuleKey(), isOffTarget)
+			if !isOffTarget {
+				continue
+			}
+			trigger.MetricUnit = appMetricList[0].Unit
+			trigger.MetricValue = metricValue
+			trigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+				MetricType:   trigger.MetricType,
+				Aggregation:  trigger.AggregationFunction(),
+				Threshold:    trigger.TargetValue,
+				MetricPoints: appMetricList,
+				Breached:     true,
+			}}}
+			e.logger.Info("send target tracking trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+			e.triggerScaling(trigger)
+			return
+		}
+
+		if trigger.IsCompound() {
+			evidence := &models.TriggerEvidence{Comparisons: []*models.MetricComparison{}}
+			breached := e.isConditionBreached(trigger, trigger.Condition, evidence)
+			e.setBreached(trigger.AppId, trigger.RuleKey(), breached)
+			if !breached {
+				continue
+			}
+			trigger.Evidence = evidence
+			e.logger.Info("send compound trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+			e.triggerScaling(trigger)
+			return
+		}
+
 		threshold := trigger.Threshold
 		operator := trigger.Operator
 		if !e.isValidOperator(operator) {
@@ -80,82 +142,308 @@ func (e *Evaluator) doEvaluate(triggerArray []*models.Trigger) {
 			continue
 		}
 
-		appMetricList, err := e.retrieveAppMetrics(trigger)
+		if trigger.RateOfChange != nil {
+			rateList, err := e.retrieveRatesOfChange(trigger)
+			if err != nil {
+				continue
+			}
+			if len(rateList) == 0 {
+				e.logger.Debug("no-available-rate-of-change", lager.Data{"trigger": trigger})
+				e.setBreached(trigger.AppId, trigger.RuleKey(), false)
+				continue
+			}
+			breached := e.isBreached(trigger, rateList, operator, threshold)
+			e.setBreached(trigger.AppId, trigger.RuleKey(), breached)
+			if !breached {
+				continue
+			}
+			trigger.MetricUnit = rateList[0].Unit
+			trigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+				MetricType:   trigger.MetricType,
+				Aggregation:  trigger.AggregationFunction(),
+				Operator:     operator,
+				Threshold:    threshold,
+				MetricPoints: rateList,
+				RateOfChange: trigger.RateOfChange,
+				Breached:     true,
+			}}}
+			e.logger.Info("send rate of change trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+			e.triggerScaling(trigger)
+			return
+		}
+
+		if trigger.Anomaly != nil {
+			scores := e.retrieveAnomalyScores(trigger)
+			if len(scores) == 0 {
+				e.logger.Debug("no-available-anomaly-score", lager.Data{"trigger": trigger})
+				e.setBreached(trigger.AppId, trigger.RuleKey(), false)
+				continue
+			}
+			scoreList := anomalyScorePoints(scores)
+			breached := e.isBreached(trigger, scoreList, operator, threshold)
+			e.setBreached(trigger.AppId, trigger.RuleKey(), breached)
+			if !breached {
+				continue
+			}
+			if trigger.Anomaly.IsNotify() {
+				e.logger.Info("record anomaly of app metric", lager.Data{"trigger": trigger, "anomaly": scores[0]})
+				if err := e.saveAnomalyScore(scores[0]); err != nil {
+					e.logger.Error("failed-to-record-anomaly", err, lager.Data{"trigger": trigger, "anomaly": scores[0]})
+				}
+				continue
+			}
+			trigger.MetricUnit = anomalyScoreUnit
+			trigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+				MetricType:   trigger.MetricType,
+				Aggregation:  trigger.AggregationFunction(),
+				Operator:     operator,
+				Threshold:    threshold,
+				MetricPoints: scoreList,
+				Anomaly:      trigger.Anomaly,
+				Breached:     true,
+			}}}
+			e.logger.Info("send anomaly trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+			e.triggerScaling(trigger)
+			return
+		}
+
+		if trigger.Predictive != nil {
+			forecastValue, unit, err := e.forecastAppMetric(trigger)
+			if err != nil {
+				e.logger.Debug("failed-to-forecast-appmetric", lager.Data{"trigger": trigger, "error": err.Error()})
+				continue
+			}
+			breached := isValueBreached(forecastValue, operator, threshold)
+			e.setBreached(trigger.AppId, trigger.RuleKey(), breached)
+			if !breached {
+				e.logger.Debug("should not send trigger alarm to scaling engine because forecast does not breach", lager.Data{"trigger": trigger, "forecast": forecastValue})
+				continue
+			}
+			trigger.MetricUnit = unit
+			trigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+				MetricType:   trigger.MetricType,
+				Aggregation:  trigger.AggregationFunction(),
+				Operator:     operator,
+				Threshold:    threshold,
+				MetricPoints: []*models.AppMetric{},
+				Forecast:     &forecastValue,
+				Breached:     true,
+			}}}
+			e.logger.Info("send predictive trigger alarm to scaling engine", lager.Data{"trigger": trigger, "forecast": forecastValue})
+			e.triggerScaling(trigger)
+			return
+		}
+
+		appMetricList, err := e.retrieveAppMetrics(trigger, trigger.MetricType, trigger.AggregationFunction())
 		if err != nil {
 			continue
 		}
 		if len(appMetricList) == 0 {
 			e.logger.Debug("no-available-appmetric", lager.Data{"trigger": trigger})
+			e.setBreached(trigger.AppId, trigger.RuleKey(), false)
 			continue
 		}
 
-		isBreached := true
-		for _, appMetric := range appMetricList {
-			if appMetric.Value == "" {
-				e.logger.Debug("should not send trigger alarm to scaling engine because there is empty value metric", lager.Data{"trigger": trigger, "appMetric": appMetric})
-				isBreached = false
-				break
+		breached := e.isBreached(trigger, appMetricList, operator, threshold)
+		e.setBreached(trigger.AppId, trigger.RuleKey(), breached)
+		if breached {
+			trigger.MetricUnit = appMetricList[0].Unit
+			trigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+				MetricType:   trigger.MetricType,
+				Aggregation:  trigger.AggregationFunction(),
+				Operator:     operator,
+				Threshold:    threshold,
+				MetricPoints: appMetricList,
+				Breached:     true,
+			}}}
+			e.logger.Info("send trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+			e.triggerScaling(trigger)
+			return
+		}
+	}
+
+}
+
+// isConditionBreached evaluates the condition tree. A comparison is breached when all the appMetrics of its metric type
+// within the breach duration of the trigger breach it. The evaluated comparisons are appended to the evidence.
+func (e *Evaluator) isConditionBreached(trigger *models.Trigger, condition *models.ScalingCondition, evidence *models.TriggerEvidence) bool {
+	if len(condition.And) > 0 {
+		for _, child := range condition.And {
+			if !e.isConditionBreached(trigger, child, evidence) {
+				return false
 			}
-			value, err := strconv.ParseInt(appMetric.Value, 10, 64)
-			if err != nil {
-				e.logger.Debug("should not send trigger alarm to scaling engine because parse metric value fails", lager.Data{"trigger": trigger, "appMetric": appMetric})
-				isBreached = false
-				break
+		}
+		return true
+	}
+	if len(condition.Or) > 0 {
+		for _, child := range condition.Or {
+			if e.isConditionBreached(trigger, child, evidence) {
+				return true
 			}
+		}
+		return false
+	}
 
-			if operator == ">" {
-				if value <= threshold {
-					e.logger.Debug("should not send trigger alarm to scaling engine", lager.Data{"trigger": trigger, "appMetric": appMetric})
-					isBreached = false
-					break
-				}
-			} else if operator == ">=" {
-				if value < threshold {
-					e.logger.Debug("should not send trigger alarm to scaling engine", lager.Data{"trigger": trigger, "appMetric": appMetric})
-					isBreached = false
-					break
-				}
-			} else if operator == "<" {
-				if value >= threshold {
-					e.logger.Debug("should not send trigger alarm to scaling engine", lager.Data{"trigger": trigger, "appMetric": appMetric})
-					isBreached = false
-					break
-				}
-			} else if operator == "<=" {
-				if value > threshold {
-					e.logger.Debug("should not send trigger alarm to scaling engine", lager.Data{"trigger": trigger, "appMetric": appMetric})
-					isBreached = false
-					break
-				}
+	if !e.isValidOperator(condition.Operator) {
+		e.logger.Error("operator-is-invalid", nil, lager.Data{"trigger": trigger, "condition": condition})
+		return false
+	}
+	appMetricList, err := e.retrieveAppMetrics(trigger, condition.MetricType, condition.AggregationFunction())
+	if err != nil {
+		return false
+	}
+	if len(appMetricList) == 0 {
+		e.logger.Debug("no-available-appmetric", lager.Data{"trigger": trigger, "condition": condition})
+		return false
+	}
+	breached := e.isBreached(trigger, appMetricList, condition.Operator, condition.Threshold)
+	evidence.Comparisons = append(evidence.Comparisons, &models.MetricComparison{
+		MetricType:   condition.MetricType,
+		Aggregation:  condition.AggregationFunction(),
+		Operator:     condition.Operator,
+		Threshold:    condition.Threshold,
+		MetricPoints: appMetricList,
+		Breached:     breached,
+	})
+	return breached
+}
+
+func (e *Evaluator) isBreached(trigger *models.Trigger, appMetricList []*models.AppMetric, operator string, threshold float64) bool {
+	for _, appMetric := range appMetricList {
+		if !isValueBreached(appMetric.Value, operator, threshold) {
+			e.logger.Debug("should not send trigger alarm to scaling engine", lager.Data{"trigger": trigger, "appMetric": appMetric})
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateTargetTracking returns the average of the metrics and whether all of them are on the same side of the target.
+func (e *Evaluator) evaluateTargetTracking(trigger *models.Trigger, appMetricList []*models.AppMetric) (float64, bool) {
+	target := trigger.TargetValue
+	above, below := 0, 0
+	sum := 0.0
+	for _, appMetric := range appMetricList {
+		value := appMetric.Value
+		if value > target {
+			above++
+		} else if value < target {
+			below++
+		}
+		sum += value
+	}
+
+	if above != len(appMetricList) && below != len(appMetricList) {
+		e.logger.Debug("should not send trigger alarm to scaling engine because metric is around target", lager.Data{"trigger": trigger})
+		return 0, false
+	}
+	return sum / float64(len(appMetricList)), true
+}
+
+func (e *Evaluator) triggerScaling(trigger *models.Trigger) {
+	if appBreaker := e.getBreaker(trigger.AppId); appBreaker != nil {
+		if trigger.Evidence != nil {
+			trigger.Evidence.CircuitBreaker = &models.CircuitBreakerStatus{
+				Tripped:             appBreaker.Tripped(),
+				ConsecutiveFailures: appBreaker.ConsecFailures(),
 			}
 		}
+		if appBreaker.Tripped() {
+			e.logger.Info("circuit-tripped", lager.Data{"appId": trigger.AppId, "consecutiveFailures": appBreaker.ConsecFailures()})
+		}
+		appBreaker.Call(func() error {
+			return e.sendTriggerAlarm(trigger)
+		}, 0)
+	} else {
+		e.sendTriggerAlarm(trigger)
+	}
+}
 
-		if isBreached {
-			trigger.MetricUnit = appMetricList[0].Unit
-			e.logger.Info("send trigger alarm to scaling engine", lager.Data{"trigger": trigger})
+func (e *Evaluator) forecastAppMetric(trigger *models.Trigger) (float64, string, error) {
+	predictive := trigger.Predictive
+	now := time.Now()
+	forecastAt := now.Add(predictive.ForecastHorizon())
 
-			if appBreaker := e.getBreaker(trigger.AppId); appBreaker != nil {
-				if appBreaker.Tripped() {
-					e.logger.Info("circuit-tripped", lager.Data{"appId": trigger.AppId, "consecutiveFailures": appBreaker.ConsecFailures()})
-				}
-				appBreaker.Call(func() error {
-					return e.sendTriggerAlarm(trigger)
-				}, 0)
-			} else {
-				e.sendTriggerAlarm(trigger)
+	historyWindow := predictive.HistoryWindow()
+	if historyWindow <= 0 {
+		historyWindow = defaultForecastHistoryWindow
+		if predictive.Method == models.ForecastMethodHoltWinters && predictive.DailySeasonality {
+			historyWindow = defaultSeasonalForecastHistoryWindow
+		}
+	}
+	historyStart := now.Add(0 - historyWindow)
+
+	history, unit, err := e.retrieveMetricPoints(trigger, historyStart, now)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(history) == 0 {
+		return 0, "", forecast.ErrNotEnoughData
+	}
+
+	var value float64
+	switch predictive.Method {
+	case models.ForecastMethodHoltWinters:
+		seasonLength := 0
+		if predictive.DailySeasonality {
+			if history[0].Timestamp > now.Add(0-2*forecastSeason).UnixNano() {
+				return 0, "", forecast.ErrNotEnoughData
 			}
-			return
+			seasonLength = int(forecastSeason / forecastSampleInterval)
+		}
+		series, err := forecast.Resample(history, historyStart.UnixNano(), int64(forecastSampleInterval), int(historyWindow/forecastSampleInterval))
+		if err != nil {
+			return 0, "", err
+		}
+		steps := int(math.Ceil(float64(predictive.ForecastHorizon()) / float64(forecastSampleInterval)))
+		value, err = forecast.HoltWinters(series, seasonLength, steps, forecast.DefaultHoltWintersParams)
+		if err != nil {
+			return 0, "", err
 		}
+	default:
+		if predictive.DailySeasonality {
+			previous, _, err := e.retrieveMetricPoints(trigger, historyStart.Add(0-forecastSeason), forecastAt.Add(0-forecastSeason))
+			if err != nil {
+				return 0, "", err
+			}
+			value, err = forecast.SeasonalLinearRegression(history, previous, int64(forecastSeason), forecastAt.UnixNano())
+			if err != nil {
+				return 0, "", err
+			}
+		} else {
+			value, err = forecast.LinearRegression(history, forecastAt.UnixNano())
+			if err != nil {
+				return 0, "", err
+			}
+		}
+	}
+
+	e.logger.Debug("forecast-appmetric", lager.Data{"trigger": trigger, "forecast": value, "forecastAt": forecastAt.UnixNano()})
+	return value, unit, nil
+}
+
+func (e *Evaluator) retrieveMetricPoints(trigger *models.Trigger, start time.Time, end time.Time) ([]forecast.Point, string, error) {
+	appMetrics, err := e.queryAppMetrics(trigger.AppId, trigger.MetricType, trigger.AggregationFunction(), start.UnixNano(), end.UnixNano(), db.ASC)
+	if err != nil {
+		e.logger.Error("retrieve-appMetrics", err, lager.Data{"trigger": trigger})
+		return nil, "", err
 	}
 
+	points := []forecast.Point{}
+	unit := ""
+	for _, appMetric := range appMetrics {
+		points = append(points, forecast.Point{Timestamp: appMetric.Timestamp, Value: appMetric.Value})
+		unit = appMetric.Unit
+	}
+	return points, unit, nil
 }
 
-func (e *Evaluator) retrieveAppMetrics(trigger *models.Trigger) ([]*models.AppMetric, error) {
+func (e *Evaluator) retrieveAppMetrics(trigger *models.Trigger, metricType string, aggregation string) ([]*models.AppMetric, error) {
 	queryEndTime := time.Now()
 	queryStartTime := queryEndTime.Add(0 - 2*trigger.BreachDuration())
 	breachStartTime := queryEndTime.Add(0 - trigger.BreachDuration())
 
-	appMetrics, err := e.queryAppMetrics(trigger.AppId, trigger.MetricType, queryStartTime.UnixNano(), queryEndTime.UnixNano(), db.ASC)
+	appMetrics, err := e.queryAppMetrics(trigger.AppId, metricType, aggregation, queryStartTime.UnixNano(), queryEndTime.UnixNano(), db.ASC)
 	if err != nil {
 		e.logger.Error("retrieve-appMetrics", err, lager.Data{"trigger": trigger})
 		return nil, err
@@ -179,6 +467,98 @@ func (e *Evaluator) retrieveAppMetrics(trigger *models.Trigger) ([]*models.AppMe
 	return result, nil
 }
 
+// retrieveRatesOfChange returns the changes of the metric over the window of the rate of change of the trigger, one
+// for each app metric within the breach duration and newest first like retrieveAppMetrics. The change at an app metric
+// is the slope from the latest app metric at least the window before it, scaled to the window, so that a gap in the
+// app metrics does not inflate it. The result is empty when the app metrics do not reach back far enough.
+func (e *Evaluator) retrieveRatesOfChange(trigger *models.Trigger) ([]*models.AppMetric, error) {
+	window := trigger.RateOfChange.Window()
+	queryEndTime := time.Now()
+	breachStartTime := queryEndTime.Add(0 - trigger.BreachDuration())
+	queryStartTime := breachStartTime.Add(0 - 2*window)
+
+	appMetrics, err := e.queryAppMetrics(trigger.AppId, trigger.MetricType, trigger.AggregationFunction(), queryStartTime.UnixNano(), queryEndTime.UnixNano(), db.ASC)
+	if err != nil {
+		e.logger.Error("retrieve-appMetrics", err, lager.Data{"trigger": trigger})
+		return nil, err
+	}
+
+	e.logger.Debug("retrieve-appMetrics", lager.Data{"appMetrics": appMetrics})
+	result := []*models.AppMetric{}
+	base := -1
+	for _, appMetric := range appMetrics {
+		if appMetric.Timestamp < breachStartTime.UnixNano() {
+			continue
+		}
+		for base+1 < len(appMetrics) && appMetrics[base+1].Timestamp <= appMetric.Timestamp-int64(window) {
+			base++
+		}
+		if base < 0 {
+			e.logger.Debug("the appmetrics are not enough for evaluation", lager.Data{"trigger": trigger, "appMetrics": appMetrics})
+			return []*models.AppMetric{}, nil
+		}
+
+		value := appMetric.Value
+		baseValue := appMetrics[base].Value
+		rate := (value - baseValue) * float64(window) / float64(appMetric.Timestamp-appMetrics[base].Timestamp)
+		unit := appMetric.Unit
+		if trigger.RateOfChange.IsPercent() {
+			if baseValue == 0 {
+				e.logger.Debug("should not send trigger alarm to scaling engine because the change from 0 has no percentage", lager.Data{"trigger": trigger, "appMetric": appMetrics[base]})
+				return []*models.AppMetric{}, nil
+			}
+			rate = rate / math.Abs(baseValue) * 100
+			unit = "%"
+		}
+		result = append([]*models.AppMetric{{
+			AppId:       appMetric.AppId,
+			MetricType:  appMetric.MetricType,
+			Aggregation: appMetric.Aggregation,
+			Value:       rate,
+			Unit:        unit,
+			Timestamp:   appMetric.Timestamp,
+		}}, result...)
+	}
+	return result, nil
+}
+
+// retrieveAnomalyScores returns the anomaly scores of the app metrics within the breach duration newest first like
+// retrieveAppMetrics. The result is empty when the scores do not reach back to the start of the breach duration, e.g.
+// while the baseline of the metric is warming up.
+func (e *Evaluator) retrieveAnomalyScores(trigger *models.Trigger) []*models.AnomalyScore {
+	queryEndTime := time.Now()
+	queryStartTime := queryEndTime.Add(0 - 2*trigger.BreachDuration())
+	breachStartTime := queryEndTime.Add(0 - trigger.BreachDuration())
+
+	scores := e.queryAnomalyScores(trigger.AppId, trigger.MetricType, trigger.AggregationFunction(), queryStartTime.UnixNano(), queryEndTime.UnixNano(), db.ASC)
+	e.logger.Debug("retrieve-anomaly-scores", lager.Data{"scores": scores})
+	result := []*models.AnomalyScore{}
+	if len(scores) == 0 || scores[0].Timestamp >= breachStartTime.UnixNano() {
+		e.logger.Debug("the anomaly scores are not enough for evaluation", lager.Data{"trigger": trigger, "scores": scores})
+		return result
+	}
+	for i := len(scores) - 1; i >= 0 && scores[i].Timestamp >= breachStartTime.UnixNano(); i-- {
+		result = append(result, scores[i])
+	}
+	return result
+}
+
+// anomalyScorePoints returns the anomaly scores as app metrics which are compared with the threshold.
+func anomalyScorePoints(scores []*models.AnomalyScore) []*models.AppMetric {
+	points := make([]*models.AppMetric, len(scores))
+	for i, score := range scores {
+		points[i] = &models.AppMetric{
+			AppId:       score.AppId,
+			MetricType:  score.MetricType,
+			Aggregation: score.Aggregation,
+			Value:       score.Score,
+			Unit:        anomalyScoreUnit,
+			Timestamp:   score.Timestamp,
+		}
+	}
+	return points
+}
+
 func (e *Evaluator) sendTriggerAlarm(trigger *models.Trigger) error {
 	jsonBytes, err := json.Marshal(trigger)
 	if err != nil {
@@ -190,6 +570,7 @@ func (e *Evaluator) sendTriggerAlarm(trigger *models.Trigger) error {
 	resp, err := e.httpClient.Post(e.scalingEngineUrl+path.Path, "application/json", bytes.NewReader(jsonBytes))
 	if err != nil {
 		e.logger.Error("failed-send-trigger-alarm-request", err, lager.Data{"trigger": trigger})
+		e.recordScalingResult(trigger.AppId, nil)
 		return err
 	}
 
@@ -208,16 +589,33 @@ func (e *Evaluator) sendTriggerAlarm(trigger *models.Trigger) error {
 			return err
 		}
 		e.logger.Debug("successfully-send-trigger-alarm with trigger", lager.Data{"trigger": trigger, "responseBody": string(respBody)})
+		e.recordScalingResult(trigger.AppId, scalingResult)
 		if scalingResult.CooldownExpiredAt != 0 {
-			e.setCoolDownExpired(trigger.AppId, scalingResult.CooldownExpiredAt)
+			e.setCoolDownExpired(trigger.AppId, scalingResult.Direction, trigger.CoolDownKey(), scalingResult.CooldownExpiredAt)
 		}
 		return nil
 	}
 	err = fmt.Errorf("Got %d when sending trigger alarm", resp.StatusCode)
+	e.recordScalingResult(trigger.AppId, nil)
 	e.logger.Error("failed-send-trigger-alarm", err, lager.Data{"trigger": trigger, "responseBody": string(respBody)})
 	return err
 
 }
+
+func isValueBreached(value float64, operator string, threshold float64) bool {
+	switch operator {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	}
+	return false
+}
+
 func (e *Evaluator) isValidOperator(operator string) bool {
 	for _, o := range validOperators {
 		if o == operator {
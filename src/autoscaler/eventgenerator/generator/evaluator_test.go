@@ -6,6 +6,7 @@ import (
 	. "autoscaler/eventgenerator/generator"
 	"autoscaler/models"
 	"autoscaler/routes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"sync"
@@ -22,25 +23,35 @@ import (
 
 var _ = Describe("Evaluator", func() {
 	var (
-		logger             *lagertest.TestLogger
-		httpClient         *http.Client
-		triggerChan        chan []*models.Trigger
-		scalingEngine      *ghttp.Server
-		evaluator          *Evaluator
-		testAppId          string = "testAppId"
-		testMetricType     string = "testMetricType"
-		testMetricUnit     string = "testMetricUnit"
-		urlPath            string
-		breachDurationSecs int = 30
-		queryAppMetrics    aggregator.QueryAppMetricsFunc
-		getBreaker         func(string) *circuit.Breaker
-		setCoolDownExpired func(string, int64)
-		cbEventChan        <-chan circuit.BreakerEvent
-		cooldownExpired    map[string]int64
-		fakeTime           time.Time   = time.Now()
-		lock               *sync.Mutex = &sync.Mutex{}
-		scalingResult      *models.AppScalingResult
-		triggerArrayGT     []*models.Trigger = []*models.Trigger{{
+		logger              *lagertest.TestLogger
+		httpClient          *http.Client
+		triggerChan         chan []*models.Trigger
+		scalingEngine       *ghttp.Server
+		evaluator           *Evaluator
+		testAppId           string = "testAppId"
+		testMetricType      string = "testMetricType"
+		testMetricUnit      string = "testMetricUnit"
+		urlPath             string
+		breachDurationSecs  int = 30
+		queryAppMetrics     aggregator.QueryAppMetricsFunc
+		queryAnomalyScores  aggregator.QueryAnomalyScoresFunc
+		saveAnomalyScore    func(*models.AnomalyScore) error
+		savedAnomalies      func() []*models.AnomalyScore
+		getBreaker          func(string) *circuit.Breaker
+		setCoolDownExpired  func(string, string, string, int64)
+		setBreached         func(string, string, bool)
+		recordScalingResult func(string, *models.AppScalingResult)
+		breaches            map[string]bool
+		scalingResults      []*models.AppScalingResult
+		cbEventChan         <-chan circuit.BreakerEvent
+		cooldownExpired     map[string]int64
+		fakeTime            time.Time   = time.Now()
+		lock                *sync.Mutex = &sync.Mutex{}
+		scalingResult       *models.AppScalingResult
+		triggers            []*models.Trigger
+		recordTrigger       http.HandlerFunc
+		receivedTriggers    func() []*models.Trigger
+		triggerArrayGT      []*models.Trigger = []*models.Trigger{{
 			AppId:           testAppId,
 			MetricType:      testMetricType,
 			CoolDownSeconds: 300,
@@ -110,22 +121,65 @@ var _ = Describe("Evaluator", func() {
 		getBreaker = func(appID string) *circuit.Breaker {
 			return nil
 		}
-		queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+		queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 			return nil, nil
 		}
+		queryAnomalyScores = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore {
+			return nil
+		}
+		anomalies := []*models.AnomalyScore{}
+		saveAnomalyScore = func(score *models.AnomalyScore) error {
+			lock.Lock()
+			defer lock.Unlock()
+			anomalies = append(anomalies, score)
+			return nil
+		}
+		savedAnomalies = func() []*models.AnomalyScore {
+			lock.Lock()
+			defer lock.Unlock()
+			return anomalies
+		}
 
 		scalingResult = &models.AppScalingResult{
 			AppId:             testAppId,
 			Adjustment:        1,
 			Status:            models.ScalingStatusSucceeded,
 			CooldownExpiredAt: fakeTime.Add(time.Duration(300) * time.Second).UnixNano(),
+			Direction:         models.ScalingDirectionOut,
 		}
 
 		cooldownExpired = map[string]int64{}
-		setCoolDownExpired = func(appId string, expiredAt int64) {
+		setCoolDownExpired = func(appId string, direction string, ruleKey string, expiredAt int64) {
+			lock.Lock()
+			defer lock.Unlock()
+			cooldownExpired[appId+"#"+direction+"#"+ruleKey] = expiredAt
+		}
+
+		breaches = map[string]bool{}
+		setBreached = func(appId string, key string, breached bool) {
+			lock.Lock()
+			defer lock.Unlock()
+			breaches[key] = breached
+		}
+		scalingResults = []*models.AppScalingResult{}
+		recordScalingResult = func(appId string, result *models.AppScalingResult) {
+			lock.Lock()
+			defer lock.Unlock()
+			scalingResults = append(scalingResults, result)
+		}
+
+		triggers = []*models.Trigger{}
+		recordTrigger = func(w http.ResponseWriter, req *http.Request) {
+			trigger := &models.Trigger{}
+			Expect(json.NewDecoder(req.Body).Decode(trigger)).To(Succeed())
+			lock.Lock()
+			defer lock.Unlock()
+			triggers = append(triggers, trigger)
+		}
+		receivedTriggers = func() []*models.Trigger {
 			lock.Lock()
 			defer lock.Unlock()
-			cooldownExpired[appId] = expiredAt
+			return triggers
 		}
 
 	})
@@ -135,7 +189,7 @@ var _ = Describe("Evaluator", func() {
 
 	Context("Start", func() {
 		JustBeforeEach(func() {
-			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, getBreaker, setCoolDownExpired)
+			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, queryAnomalyScores, saveAnomalyScore, getBreaker, setCoolDownExpired, setBreached, recordScalingResult)
 			evaluator.Start()
 		})
 
@@ -155,7 +209,7 @@ var _ = Describe("Evaluator", func() {
 					scalingEngine.RouteToHandler("POST", urlPath, ghttp.RespondWith(http.StatusOK, "successful"))
 					Expect(triggerChan).To(BeSent(triggerArrayGT))
 					appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, false)
-					queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return appMetrics, nil
 					}
 				})
@@ -164,6 +218,376 @@ var _ = Describe("Evaluator", func() {
 					Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("the appmetrics are not enough for evaluation")))
 				})
 			})
+			Context("predictive", func() {
+				var predictiveTrigger *models.Trigger
+				BeforeEach(func() {
+					predictiveTrigger = &models.Trigger{
+						AppId:           testAppId,
+						MetricType:      testMetricType,
+						CoolDownSeconds: 300,
+						Threshold:       500,
+						Operator:        ">",
+						Adjustment:      "+1",
+						Predictive: &models.PredictiveScaling{
+							Method:                 models.ForecastMethodLinearRegression,
+							ForecastHorizonSeconds: 600,
+						},
+					}
+					scalingEngine.RouteToHandler("POST", urlPath, ghttp.CombineHandlers(recordTrigger, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)))
+				})
+
+				Context("when the forecast breaches the trigger", func() {
+					BeforeEach(func() {
+						// grows 10 per minute and reaches 400 now, so 500 is passed within 600 seconds
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-30*time.Minute), time.Minute, 400-30*10, 10, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{predictiveTrigger}))
+					})
+					It("should send trigger alarm to scaling engine before the metric breaches", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send predictive trigger alarm to scaling engine")))
+					})
+					It("should send the forecast as evidence", func() {
+						Eventually(receivedTriggers).Should(HaveLen(1))
+						comparisons := receivedTriggers()[0].Evidence.Comparisons
+						Expect(comparisons).To(HaveLen(1))
+						Expect(comparisons[0].Forecast).NotTo(BeNil())
+						Expect(*comparisons[0].Forecast).To(BeNumerically(">", 500))
+						Expect(comparisons[0].MetricPoints).To(BeEmpty())
+					})
+				})
+
+				Context("when the forecast does not breach the trigger", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-30*time.Minute), time.Minute, 400, 0, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{predictiveTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("forecast does not breach")))
+					})
+				})
+
+				Context("when daily seasonality is enabled", func() {
+					BeforeEach(func() {
+						predictiveTrigger.Predictive.DailySeasonality = true
+						// flat today, but yesterday the metric rose by 200 over the next 10 minutes
+						today := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-60*time.Minute), time.Minute, 400, 0, 61)
+						yesterday := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-24*time.Hour-61*time.Minute), time.Minute, 300, 0, 61)
+						yesterday = append(yesterday, generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-24*time.Hour), time.Minute, 300, 20, 12)...)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							if end < time.Now().Add(-12*time.Hour).UnixNano() {
+								return yesterday, nil
+							}
+							return today, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{predictiveTrigger}))
+					})
+					It("should send trigger alarm to scaling engine", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+					})
+				})
+
+				Context("when the history is not enough for holt_winters with daily seasonality", func() {
+					BeforeEach(func() {
+						predictiveTrigger.Predictive.Method = models.ForecastMethodHoltWinters
+						predictiveTrigger.Predictive.DailySeasonality = true
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-30*time.Minute), time.Minute, 400, 100, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{predictiveTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("failed-to-forecast-appmetric")))
+					})
+				})
+			})
+
+			Context("rate of change", func() {
+				var rateTrigger *models.Trigger
+				BeforeEach(func() {
+					rateTrigger = &models.Trigger{
+						AppId:                 testAppId,
+						MetricType:            testMetricType,
+						BreachDurationSeconds: 120,
+						CoolDownSeconds:       300,
+						Threshold:             5,
+						Operator:              ">",
+						Adjustment:            "+1",
+						RateOfChange: &models.RateOfChange{
+							WindowSeconds: 60,
+						},
+					}
+					scalingEngine.RouteToHandler("POST", urlPath, ghttp.CombineHandlers(recordTrigger, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)))
+				})
+
+				Context("when the metric grows faster than the threshold", func() {
+					BeforeEach(func() {
+						// grows 96 per minute from 1000 to 1480, which is more than 6% per minute in the last 2 minutes
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-5*time.Minute), 10*time.Second, 1000, 16, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{rateTrigger}))
+					})
+					It("should send trigger alarm to scaling engine", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send rate of change trigger alarm to scaling engine")))
+					})
+					It("should send the rates of change as evidence", func() {
+						Eventually(receivedTriggers).Should(HaveLen(1))
+						Expect(receivedTriggers()[0].MetricUnit).To(Equal("%"))
+						comparisons := receivedTriggers()[0].Evidence.Comparisons
+						Expect(comparisons).To(HaveLen(1))
+						Expect(comparisons[0].RateOfChange).To(Equal(rateTrigger.RateOfChange))
+						Expect(comparisons[0].MetricPoints).NotTo(BeEmpty())
+						for _, point := range comparisons[0].MetricPoints {
+							Expect(point.Unit).To(Equal("%"))
+						}
+					})
+				})
+
+				Context("when the metric does not grow faster than the threshold", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-5*time.Minute), 10*time.Second, 1000, 1, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{rateTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+					})
+				})
+
+				Context("when the history is shorter than the window", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-90*time.Second), 10*time.Second, 1000, 100, 9)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{rateTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("the appmetrics are not enough for evaluation")))
+					})
+				})
+
+				Context("when the change is absolute", func() {
+					BeforeEach(func() {
+						rateTrigger.RateOfChange.Change = models.RateOfChangeAbsolute
+						rateTrigger.Threshold = 50
+						appMetrics := generateTestAppMetricsSeries(testAppId, testMetricType, testMetricUnit, time.Now().Add(-5*time.Minute), 10*time.Second, 1000, 10, 31)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{rateTrigger}))
+					})
+					It("should compare the change per window with the threshold", func() {
+						Eventually(receivedTriggers).Should(HaveLen(1))
+						Expect(receivedTriggers()[0].MetricUnit).To(Equal(testMetricUnit))
+						points := receivedTriggers()[0].Evidence.Comparisons[0].MetricPoints
+						Expect(points).NotTo(BeEmpty())
+						Expect(points[0].Value).To(Equal(60.0))
+					})
+				})
+			})
+
+			Context("anomaly", func() {
+				var anomalyTrigger *models.Trigger
+				var anomalyScores func(scores ...float64) []*models.AnomalyScore
+				BeforeEach(func() {
+					anomalyTrigger = &models.Trigger{
+						AppId:                 testAppId,
+						MetricType:            testMetricType,
+						BreachDurationSeconds: breachDurationSecs,
+						CoolDownSeconds:       300,
+						Threshold:             3,
+						Operator:              ">",
+						Adjustment:            "+1",
+						Anomaly:               &models.AnomalyDetection{Action: models.AnomalyActionScale},
+					}
+					// the scores are 10 seconds apart and the last one is now
+					anomalyScores = func(scores ...float64) []*models.AnomalyScore {
+						result := []*models.AnomalyScore{}
+						now := time.Now()
+						for i, score := range scores {
+							result = append(result, &models.AnomalyScore{
+								AppId:       testAppId,
+								MetricType:  testMetricType,
+								Aggregation: models.AggregationAvg,
+								Value:       100 + 10*score,
+								Unit:        testMetricUnit,
+								Baseline:    100,
+								StdDev:      10,
+								Score:       score,
+								Timestamp:   now.Add(time.Duration(i-len(scores)+1) * 10 * time.Second).UnixNano(),
+							})
+						}
+						return result
+					}
+					scalingEngine.RouteToHandler("POST", urlPath, ghttp.CombineHandlers(recordTrigger, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)))
+				})
+
+				Context("when the metric keeps away from its baseline", func() {
+					BeforeEach(func() {
+						scores := anomalyScores(0.5, 4, 5, 4.5, 6)
+						queryAnomalyScores = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore {
+							return scores
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{anomalyTrigger}))
+					})
+					It("should send trigger alarm to scaling engine with the scores as evidence", func() {
+						Eventually(receivedTriggers).Should(HaveLen(1))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send anomaly trigger alarm to scaling engine")))
+						comparisons := receivedTriggers()[0].Evidence.Comparisons
+						Expect(comparisons).To(HaveLen(1))
+						Expect(comparisons[0].Anomaly).To(Equal(anomalyTrigger.Anomaly))
+						Expect(comparisons[0].MetricPoints).To(HaveLen(3))
+						Expect(comparisons[0].MetricPoints[0].Value).To(Equal(6.0))
+					})
+				})
+
+				Context("when the metric is back to its baseline", func() {
+					BeforeEach(func() {
+						scores := anomalyScores(0.5, 4, 5, 1, 6)
+						queryAnomalyScores = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore {
+							return scores
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{anomalyTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+					})
+				})
+
+				Context("when the baseline is warming up", func() {
+					BeforeEach(func() {
+						scores := anomalyScores(4, 5, 6)
+						queryAnomalyScores = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore {
+							return scores
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{anomalyTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("the anomaly scores are not enough for evaluation")))
+					})
+				})
+
+				Context("when the anomaly rule notifies", func() {
+					BeforeEach(func() {
+						anomalyTrigger.Anomaly.Action = models.AnomalyActionNotify
+						anomalyTrigger.Adjustment = ""
+						scores := anomalyScores(0.5, 4, 5, 4.5, 6)
+						queryAnomalyScores = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) []*models.AnomalyScore {
+							return scores
+						}
+						Expect(triggerChan).To(BeSent([]*models.Trigger{anomalyTrigger}))
+					})
+					It("should record the latest anomaly instead of sending trigger alarm to scaling engine", func() {
+						Eventually(savedAnomalies).Should(HaveLen(1))
+						Expect(savedAnomalies()[0].Score).To(Equal(6.0))
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+					})
+
+					Context("when recording the anomaly fails", func() {
+						BeforeEach(func() {
+							saveAnomalyScore = func(score *models.AnomalyScore) error {
+								return errors.New("an error")
+							}
+						})
+						It("should log the error", func() {
+							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("failed-to-record-anomaly")))
+							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						})
+					})
+				})
+			})
+
+			Context("target tracking", func() {
+				var targetTrigger *models.Trigger
+				BeforeEach(func() {
+					targetTrigger = &models.Trigger{
+						AppId:           testAppId,
+						MetricType:      testMetricType,
+						CoolDownSeconds: 300,
+						TargetValue:     60,
+					}
+				})
+
+				Context("when the appMetrics are all above the target", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{90, 95, 85}, breachDurationSecs, true)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						scalingEngine.RouteToHandler("POST", urlPath,
+							ghttp.CombineHandlers(
+								ghttp.VerifyJSONRepresenting(models.Trigger{
+									AppId:                 testAppId,
+									MetricType:            testMetricType,
+									MetricUnit:            testMetricUnit,
+									BreachDurationSeconds: breachDurationSecs,
+									CoolDownSeconds:       300,
+									TargetValue:           60,
+									MetricValue:           90,
+									Evidence: &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+										MetricType:   testMetricType,
+										Aggregation:  models.AggregationAvg,
+										Threshold:    60,
+										MetricPoints: metricsInBreachDuration(appMetrics),
+										Breached:     true,
+									}}},
+								}),
+								ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)),
+						)
+						Expect(triggerChan).To(BeSent([]*models.Trigger{targetTrigger}))
+					})
+					It("should send trigger alarm with the average metric value to scaling engine", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send target tracking trigger alarm to scaling engine")))
+					})
+				})
+
+				Context("when the appMetrics are all below the target", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{20, 30, 25}, breachDurationSecs, true)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						scalingEngine.RouteToHandler("POST", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult))
+						Expect(triggerChan).To(BeSent([]*models.Trigger{targetTrigger}))
+					})
+					It("should send trigger alarm to scaling engine", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+					})
+				})
+
+				Context("when the appMetrics are around the target", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{50, 70, 60}, breachDurationSecs, true)
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						scalingEngine.RouteToHandler("POST", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult))
+						Expect(triggerChan).To(BeSent([]*models.Trigger{targetTrigger}))
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("metric is around target")))
+					})
+				})
+			})
+
 			Context("operators", func() {
 				BeforeEach(func() {
 					scalingEngine.RouteToHandler("POST", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult))
@@ -175,7 +599,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 							scalingEngine.RouteToHandler("POST", urlPath,
@@ -189,6 +613,14 @@ var _ = Describe("Evaluator", func() {
 										Threshold:             500,
 										Operator:              ">",
 										Adjustment:            "+1",
+										Evidence: &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+											MetricType:   testMetricType,
+											Aggregation:  models.AggregationAvg,
+											Operator:     ">",
+											Threshold:    500,
+											MetricPoints: metricsInBreachDuration(appMetrics),
+											Breached:     true,
+										}}},
 									}),
 									ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)),
 							)
@@ -202,7 +634,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics do not breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 150, 600}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -214,7 +646,7 @@ var _ = Describe("Evaluator", func() {
 					})
 					Context("when appMetrics is empty", func() {
 						BeforeEach(func() {
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return []*models.AppMetric{}, nil
 							}
 						})
@@ -223,23 +655,6 @@ var _ = Describe("Evaluator", func() {
 							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
 						})
 					})
-					Context("when the appMetrics contain empty value elements", func() {
-						BeforeEach(func() {
-							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, true)
-							appMetrics = append(appMetrics, &models.AppMetric{AppId: testAppId,
-								MetricType: testMetricType,
-								Value:      "",
-								Unit:       "",
-								Timestamp:  time.Now().UnixNano()})
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
-								return appMetrics, nil
-							}
-						})
-						It("should not send trigger alarm to scaling engine", func() {
-							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
-							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine because there is empty value metric")))
-						})
-					})
 				})
 				Context(">=", func() {
 					BeforeEach(func() {
@@ -249,7 +664,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 500, 500}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -262,7 +677,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics do not breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 150, 600}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -274,7 +689,7 @@ var _ = Describe("Evaluator", func() {
 					})
 					Context("when appMetrics is empty", func() {
 						BeforeEach(func() {
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return []*models.AppMetric{}, nil
 							}
 
@@ -284,23 +699,6 @@ var _ = Describe("Evaluator", func() {
 							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
 						})
 					})
-					Context("when the appMetrics contain  empty value elements", func() {
-						BeforeEach(func() {
-							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 500, 500}, breachDurationSecs, true)
-							appMetrics = append(appMetrics, &models.AppMetric{AppId: testAppId,
-								MetricType: testMetricType,
-								Value:      "",
-								Unit:       "",
-								Timestamp:  time.Now().UnixNano()})
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
-								return appMetrics, nil
-							}
-						})
-						It("should not send trigger alarm to scaling engine", func() {
-							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
-							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine because there is empty value metric")))
-						})
-					})
 				})
 				Context("<", func() {
 					BeforeEach(func() {
@@ -309,7 +707,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 300, 400}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -322,7 +720,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics do not breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 550, 600}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -334,7 +732,7 @@ var _ = Describe("Evaluator", func() {
 					})
 					Context("when appMetrics is empty", func() {
 						BeforeEach(func() {
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return []*models.AppMetric{}, nil
 							}
 
@@ -344,23 +742,6 @@ var _ = Describe("Evaluator", func() {
 							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
 						})
 					})
-					Context("when the appMetrics contain  empty value elements", func() {
-						BeforeEach(func() {
-							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 300, 400}, breachDurationSecs, true)
-							appMetrics = append(appMetrics, &models.AppMetric{AppId: testAppId,
-								MetricType: testMetricType,
-								Value:      "",
-								Unit:       "",
-								Timestamp:  time.Now().UnixNano()})
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
-								return appMetrics, nil
-							}
-						})
-						It("should not send trigger alarm to scaling engine", func() {
-							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
-							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine because there is empty value metric")))
-						})
-					})
 				})
 				Context("<=", func() {
 					BeforeEach(func() {
@@ -369,7 +750,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 500, 500}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 
@@ -383,7 +764,7 @@ var _ = Describe("Evaluator", func() {
 					Context("when the appMetrics do not breach the trigger", func() {
 						BeforeEach(func() {
 							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 550, 600}, breachDurationSecs, true)
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return appMetrics, nil
 							}
 						})
@@ -395,7 +776,7 @@ var _ = Describe("Evaluator", func() {
 					})
 					Context("when appMetrics is empty", func() {
 						BeforeEach(func() {
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 								return []*models.AppMetric{}, nil
 							}
 
@@ -405,21 +786,143 @@ var _ = Describe("Evaluator", func() {
 							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
 						})
 					})
-					Context("when the appMetrics contain  empty value elements", func() {
+				})
+			})
+
+			Context("fractional values", func() {
+				BeforeEach(func() {
+					Expect(triggerChan).To(BeSent([]*models.Trigger{{
+						AppId:                 testAppId,
+						MetricType:            testMetricType,
+						BreachDurationSeconds: breachDurationSecs,
+						CoolDownSeconds:       300,
+						Threshold:             0.5,
+						Operator:              ">",
+						Adjustment:            "+1",
+					}}))
+				})
+				Context("when the appMetrics breach the trigger", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{1, 1}, breachDurationSecs, true)
+						appMetrics[1].Value = 0.55
+						appMetrics[2].Value = 0.75
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+						scalingEngine.RouteToHandler("POST", urlPath, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult))
+					})
+					It("should send trigger alarm to scaling engine", func() {
+						Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send trigger alarm to scaling engine")))
+					})
+				})
+				Context("when the appMetrics do not breach the trigger", func() {
+					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{1, 1}, breachDurationSecs, true)
+						appMetrics[1].Value = 0.55
+						appMetrics[2].Value = 0.45
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+							return appMetrics, nil
+						}
+					})
+					It("should not send trigger alarm to scaling engine", func() {
+						Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+						Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine")))
+					})
+				})
+			})
+
+			Context("compound conditions", func() {
+				var cpuMetrics, throughputMetrics []*models.AppMetric
+
+				BeforeEach(func() {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+						if metricType == "cpu" {
+							return cpuMetrics, nil
+						}
+						return throughputMetrics, nil
+					}
+					scalingEngine.RouteToHandler("POST", urlPath, ghttp.CombineHandlers(recordTrigger, ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult)))
+				})
+
+				Context("and", func() {
+					BeforeEach(func() {
+						Expect(triggerChan).To(BeSent([]*models.Trigger{{
+							AppId:                 testAppId,
+							BreachDurationSeconds: breachDurationSecs,
+							Adjustment:            "+1",
+							Condition: &models.ScalingCondition{
+								And: []*models.ScalingCondition{
+									{MetricType: "cpu", Operator: ">", Threshold: 70},
+									{MetricType: "throughput", Operator: ">", Threshold: 500},
+								},
+							},
+						}}))
+					})
+
+					Context("when all the conditions are breached", func() {
 						BeforeEach(func() {
-							appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{200, 500, 500}, breachDurationSecs, true)
-							appMetrics = append(appMetrics, &models.AppMetric{AppId: testAppId,
-								MetricType: testMetricType,
-								Value:      "",
-								Unit:       "",
-								Timestamp:  time.Now().UnixNano()})
-							queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
-								return appMetrics, nil
-							}
+							cpuMetrics = generateTestAppMetrics(testAppId, "cpu", "%", []int64{80, 90}, breachDurationSecs, true)
+							throughputMetrics = generateTestAppMetrics(testAppId, "throughput", "rps", []int64{600, 700}, breachDurationSecs, true)
+						})
+						It("should send trigger alarm to scaling engine", func() {
+							Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send compound trigger alarm to scaling engine")))
+						})
+						It("should send the comparisons of all the conditions as evidence", func() {
+							Eventually(receivedTriggers).Should(HaveLen(1))
+							Expect(receivedTriggers()[0].Evidence).To(Equal(&models.TriggerEvidence{Comparisons: []*models.MetricComparison{
+								{MetricType: "cpu", Aggregation: models.AggregationAvg, Operator: ">", Threshold: 70, MetricPoints: metricsInBreachDuration(cpuMetrics), Breached: true},
+								{MetricType: "throughput", Aggregation: models.AggregationAvg, Operator: ">", Threshold: 500, MetricPoints: metricsInBreachDuration(throughputMetrics), Breached: true},
+							}}))
+						})
+					})
+
+					Context("when only one of the conditions is breached", func() {
+						BeforeEach(func() {
+							cpuMetrics = generateTestAppMetrics(testAppId, "cpu", "%", []int64{80, 90}, breachDurationSecs, true)
+							throughputMetrics = generateTestAppMetrics(testAppId, "throughput", "rps", []int64{100, 200}, breachDurationSecs, true)
+						})
+						It("should not send trigger alarm to scaling engine", func() {
+							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
+							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine")))
+						})
+					})
+				})
+
+				Context("or", func() {
+					BeforeEach(func() {
+						Expect(triggerChan).To(BeSent([]*models.Trigger{{
+							AppId:                 testAppId,
+							BreachDurationSeconds: breachDurationSecs,
+							Adjustment:            "+1",
+							Condition: &models.ScalingCondition{
+								Or: []*models.ScalingCondition{
+									{MetricType: "cpu", Operator: ">", Threshold: 70},
+									{MetricType: "throughput", Operator: ">", Threshold: 500},
+								},
+							},
+						}}))
+					})
+
+					Context("when one of the conditions is breached", func() {
+						BeforeEach(func() {
+							cpuMetrics = generateTestAppMetrics(testAppId, "cpu", "%", []int64{10, 20}, breachDurationSecs, true)
+							throughputMetrics = generateTestAppMetrics(testAppId, "throughput", "rps", []int64{600, 700}, breachDurationSecs, true)
+						})
+						It("should send trigger alarm to scaling engine", func() {
+							Eventually(scalingEngine.ReceivedRequests).Should(HaveLen(1))
+							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("send compound trigger alarm to scaling engine")))
+						})
+					})
+
+					Context("when none of the conditions is breached", func() {
+						BeforeEach(func() {
+							cpuMetrics = generateTestAppMetrics(testAppId, "cpu", "%", []int64{10, 20}, breachDurationSecs, true)
+							throughputMetrics = generateTestAppMetrics(testAppId, "throughput", "rps", []int64{100, 200}, breachDurationSecs, true)
 						})
 						It("should not send trigger alarm to scaling engine", func() {
 							Consistently(scalingEngine.ReceivedRequests).Should(HaveLen(0))
-							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("should not send trigger alarm to scaling engine because there is empty value metric")))
 						})
 					})
 				})
@@ -431,15 +934,24 @@ var _ = Describe("Evaluator", func() {
 				})
 				Context("when only the first trigger breaches", func() {
 					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 550, 600}, breachDurationSecs, true)
+						expectedTrigger := firstTrigger
+						expectedTrigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+							MetricType:   testMetricType,
+							Aggregation:  models.AggregationAvg,
+							Operator:     ">=",
+							Threshold:    500,
+							MetricPoints: metricsInBreachDuration(appMetrics),
+							Breached:     true,
+						}}}
 						scalingEngine.AppendHandlers(
 							ghttp.CombineHandlers(
 								ghttp.VerifyRequest("POST", urlPath),
-								ghttp.VerifyJSONRepresenting(firstTrigger),
+								ghttp.VerifyJSONRepresenting(expectedTrigger),
 								ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult),
 							),
 						)
-						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 550, 600}, breachDurationSecs, true)
-						queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 							return appMetrics, nil
 						}
 					})
@@ -453,15 +965,24 @@ var _ = Describe("Evaluator", func() {
 
 				Context("when only second tigger breaches", func() {
 					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{300, 400, 500}, breachDurationSecs, true)
+						expectedTrigger := secondTrigger
+						expectedTrigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+							MetricType:   testMetricType,
+							Aggregation:  models.AggregationAvg,
+							Operator:     "<=",
+							Threshold:    500,
+							MetricPoints: metricsInBreachDuration(appMetrics),
+							Breached:     true,
+						}}}
 						scalingEngine.AppendHandlers(
 							ghttp.CombineHandlers(
 								ghttp.VerifyRequest("POST", urlPath),
-								ghttp.VerifyJSONRepresenting(secondTrigger),
+								ghttp.VerifyJSONRepresenting(expectedTrigger),
 								ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult),
 							),
 						)
-						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{300, 400, 500}, breachDurationSecs, true)
-						queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 							return appMetrics, nil
 						}
 					})
@@ -475,15 +996,24 @@ var _ = Describe("Evaluator", func() {
 
 				Context("when both tiggers breach", func() {
 					BeforeEach(func() {
+						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 500, 500}, breachDurationSecs, true)
+						expectedTrigger := firstTrigger
+						expectedTrigger.Evidence = &models.TriggerEvidence{Comparisons: []*models.MetricComparison{{
+							MetricType:   testMetricType,
+							Aggregation:  models.AggregationAvg,
+							Operator:     ">=",
+							Threshold:    500,
+							MetricPoints: metricsInBreachDuration(appMetrics),
+							Breached:     true,
+						}}}
 						scalingEngine.AppendHandlers(
 							ghttp.CombineHandlers(
 								ghttp.VerifyRequest("POST", urlPath),
-								ghttp.VerifyJSONRepresenting(firstTrigger),
+								ghttp.VerifyJSONRepresenting(expectedTrigger),
 								ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult),
 							),
 						)
-						appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{500, 500, 500}, breachDurationSecs, true)
-						queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+						queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 							return appMetrics, nil
 						}
 					})
@@ -499,7 +1029,7 @@ var _ = Describe("Evaluator", func() {
 			Context("sending trigger ", func() {
 				BeforeEach(func() {
 					appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, true)
-					queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return appMetrics, nil
 					}
 					Expect(triggerChan).To(BeSent(triggerArrayGT))
@@ -522,11 +1052,47 @@ var _ = Describe("Evaluator", func() {
 							Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("successfully-send-trigger-alarm with trigger")))
 							lock.Lock()
 							Eventually(cooldownExpired).Should(HaveLen(1))
-							Eventually(cooldownExpired[testAppId]).Should(Equal(fakeTime.Add(time.Duration(300) * time.Second).UnixNano()))
+							Eventually(cooldownExpired[testAppId+"#out#"]).Should(Equal(fakeTime.Add(time.Duration(300) * time.Second).UnixNano()))
+							lock.Unlock()
+						})
+
+						It("should record the breach and the scaling result", func() {
+							Eventually(func() []*models.AppScalingResult {
+								lock.Lock()
+								defer lock.Unlock()
+								return scalingResults
+							}).Should(Equal([]*models.AppScalingResult{scalingResult}))
+							lock.Lock()
+							Expect(breaches).To(Equal(map[string]bool{triggerArrayGT[0].RuleKey(): true}))
 							lock.Unlock()
 						})
 					})
 
+					Context("when there is a circuit breaker for the app", func() {
+						BeforeEach(func() {
+							breaker := circuit.NewConsecutiveBreaker(3)
+							breaker.Fail()
+							getBreaker = func(appID string) *circuit.Breaker {
+								return breaker
+							}
+							scalingEngine.AppendHandlers(
+								ghttp.CombineHandlers(
+									ghttp.VerifyRequest("POST", urlPath),
+									recordTrigger,
+									ghttp.RespondWithJSONEncoded(http.StatusOK, &scalingResult),
+								),
+							)
+						})
+
+						It("should send the circuit breaker status as evidence", func() {
+							Eventually(receivedTriggers).Should(HaveLen(1))
+							Expect(receivedTriggers()[0].Evidence.CircuitBreaker).To(Equal(&models.CircuitBreakerStatus{
+								Tripped:             false,
+								ConsecutiveFailures: 1,
+							}))
+						})
+					})
+
 					Context("when cooldownExpiredAt is 0 in scalingResult", func() {
 						BeforeEach(func() {
 							scalingResult.CooldownExpiredAt = 0
@@ -625,7 +1191,7 @@ var _ = Describe("Evaluator", func() {
 			Context("circuit break for scaling failures", func() {
 				BeforeEach(func() {
 					appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, true)
-					queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return appMetrics, nil
 					}
 
@@ -713,7 +1279,7 @@ var _ = Describe("Evaluator", func() {
 
 			Context("when retrieving appMetrics  failed", func() {
 				BeforeEach(func() {
-					queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return nil, errors.New("an error")
 					}
 				})
@@ -749,10 +1315,10 @@ var _ = Describe("Evaluator", func() {
 	Context("Stop", func() {
 		BeforeEach(func() {
 			scalingEngine = ghttp.NewServer()
-			queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+			queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 				return nil, nil
 			}
-			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, getBreaker, setCoolDownExpired)
+			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, queryAnomalyScores, saveAnomalyScore, getBreaker, setCoolDownExpired, setBreached, recordScalingResult)
 			evaluator.Start()
 			Expect(triggerChan).To(BeSent(triggerArrayGT))
 
@@ -768,10 +1334,10 @@ var _ = Describe("Evaluator", func() {
 		BeforeEach(func() {
 			scalingEngine = ghttp.NewUnstartedServer()
 			appMetrics := generateTestAppMetrics(testAppId, testMetricType, testMetricUnit, []int64{600, 650, 620}, breachDurationSecs, true)
-			queryAppMetrics = func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+			queryAppMetrics = func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 				return appMetrics, nil
 			}
-			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, getBreaker, setCoolDownExpired)
+			evaluator = NewEvaluator(logger, httpClient, scalingEngine.URL(), triggerChan, breachDurationSecs, queryAppMetrics, queryAnomalyScores, saveAnomalyScore, getBreaker, setCoolDownExpired, setBreached, recordScalingResult)
 			evaluator.Start()
 			Expect(triggerChan).To(BeSent(triggerArrayGT))
 		})
@@ -784,5 +1350,13 @@ var _ = Describe("Evaluator", func() {
 		It("should log the error", func() {
 			Eventually(logger.LogMessages).Should(ContainElement(ContainSubstring("failed-send-trigger-alarm-request")))
 		})
+
+		It("should record a failed scaling decision", func() {
+			Eventually(func() []*models.AppScalingResult {
+				lock.Lock()
+				defer lock.Unlock()
+				return scalingResults
+			}).Should(Equal([]*models.AppScalingResult{nil}))
+		})
 	})
 })
@@ -4,7 +4,6 @@ import (
 	"autoscaler/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"strconv"
 	"time"
 
 	"testing"
@@ -21,7 +20,7 @@ func generateTestAppMetrics(appId string, metricType string, unit string, metric
 		appMetrics = append(appMetrics, &models.AppMetric{
 			AppId:      appId,
 			MetricType: metricType,
-			Value:      "10",
+			Value:      10,
 			Unit:       unit,
 			Timestamp:  time.Now().UnixNano() - int64(time.Duration(breachDurationSecs)*time.Second),
 		})
@@ -30,10 +29,34 @@ func generateTestAppMetrics(appId string, metricType string, unit string, metric
 		appMetrics = append(appMetrics, &models.AppMetric{
 			AppId:      appId,
 			MetricType: metricType,
-			Value:      strconv.FormatInt(value, 10),
+			Value:      float64(value),
 			Unit:       unit,
 			Timestamp:  time.Now().UnixNano(),
 		})
 	}
 	return appMetrics
 }
+
+func generateTestAppMetricsSeries(appId string, metricType string, unit string, start time.Time, interval time.Duration, firstValue int64, increment int64, count int) []*models.AppMetric {
+	appMetrics := []*models.AppMetric{}
+	for i := 0; i < count; i++ {
+		appMetrics = append(appMetrics, &models.AppMetric{
+			AppId:      appId,
+			MetricType: metricType,
+			Value:      float64(firstValue + int64(i)*increment),
+			Unit:       unit,
+			Timestamp:  start.Add(time.Duration(i) * interval).UnixNano(),
+		})
+	}
+	return appMetrics
+}
+
+// metricsInBreachDuration returns the appMetrics generated by generateTestAppMetrics with enough metrics in the order
+// in which the evaluator retrieves them.
+func metricsInBreachDuration(appMetrics []*models.AppMetric) []*models.AppMetric {
+	result := []*models.AppMetric{}
+	for i := len(appMetrics) - 1; i > 0; i-- {
+		result = append(result, appMetrics[i])
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package metricsource
+
+import (
+	"autoscaler/helpers"
+	"autoscaler/models"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// HTTPJSONSource gets a JSON document from a URL and selects the metric value with a JSON path.
+type HTTPJSONSource struct {
+	url        string
+	headers    map[string]string
+	jsonPath   helpers.JSONPath
+	httpClient *http.Client
+}
+
+func NewHTTPJSONSource(config *models.MetricSourceConfig, httpClient *http.Client) (*HTTPJSONSource, error) {
+	jsonPath, err := helpers.ParseJSONPath(config.JSONPath)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPJSONSource{
+		url:        config.URL,
+		headers:    config.Headers,
+		jsonPath:   jsonPath,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (s *HTTPJSONSource) Fetch() (float64, error) {
+	resp, err := get(s.httpClient, s.url, s.headers)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var document interface{}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&document)
+	if err != nil {
+		return 0, err
+	}
+	value, err := s.jsonPath.Select(document)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(value)
+}
@@ -0,0 +1,57 @@
+package metricsource
+
+import (
+	"autoscaler/models"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// maxResponseSize is the largest response body of a metric source which is read, a larger body fails to decode.
+const maxResponseSize = 1 << 20
+
+// MetricSource pulls the current value of a metric of an app from outside the app instances.
+type MetricSource interface {
+	Fetch() (float64, error)
+}
+
+func NewMetricSource(config *models.MetricSourceConfig, httpClient *http.Client) (MetricSource, error) {
+	switch config.Type {
+	case models.MetricSourceTypeHTTPJSON:
+		return NewHTTPJSONSource(config, httpClient)
+	case models.MetricSourceTypePrometheus:
+		return NewPrometheusSource(config, httpClient), nil
+	}
+	return nil, fmt.Errorf("unsupported metric source type %s", config.Type)
+}
+
+func get(httpClient *http.Client, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("metric source responded with status code %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// toFloat converts a JSON number, or a string holding a number as Prometheus sends the sample values.
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, fmt.Errorf("value %v is not a number", value)
+}
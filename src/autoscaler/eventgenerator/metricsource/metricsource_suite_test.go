@@ -0,0 +1,13 @@
+package metricsource_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestMetricSource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MetricSource Suite")
+}
@@ -0,0 +1,142 @@
+package metricsource_test
+
+import (
+	. "autoscaler/eventgenerator/metricsource"
+	"autoscaler/models"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/cfhttp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("MetricSource", func() {
+	var (
+		server *ghttp.Server
+		config *models.MetricSourceConfig
+		source MetricSource
+		value  float64
+		err    error
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		source, err = NewMetricSource(config, cfhttp.NewClient())
+		Expect(err).NotTo(HaveOccurred())
+		value, err = source.Fetch()
+	})
+
+	Context("http_json", func() {
+		BeforeEach(func() {
+			config = &models.MetricSourceConfig{
+				MetricType: "queue_depth",
+				Type:       models.MetricSourceTypeHTTPJSON,
+				URL:        server.URL() + "/api/queues/vhost/jobs",
+				JSONPath:   "$.backing_queue_status.len",
+				Headers:    map[string]string{"Authorization": "Basic abc"},
+			}
+		})
+
+		Context("when the endpoint returns the value", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/queues/vhost/jobs"),
+					ghttp.VerifyHeaderKV("Authorization", "Basic abc"),
+					ghttp.RespondWith(http.StatusOK, `{"name":"jobs","backing_queue_status":{"len":1234}}`),
+				))
+			})
+			It("selects the value with the json path", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(Equal(1234.0))
+			})
+		})
+
+		Context("when the value is not a number", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"backing_queue_status":{"len":{}}}`))
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("value map[] is not a number"))
+			})
+		})
+
+		Context("when the endpoint fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusUnauthorized, ""))
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("metric source responded with status code 401"))
+			})
+		})
+
+		Context("when the response is too large", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"padding":"`+strings.Repeat("x", 2<<20)+`","backing_queue_status":{"len":1234}}`))
+			})
+			It("should error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("prometheus", func() {
+		BeforeEach(func() {
+			config = &models.MetricSourceConfig{
+				MetricType: "consumer_lag",
+				Type:       models.MetricSourceTypePrometheus,
+				URL:        server.URL() + "/",
+				Query:      `sum(kafka_consumergroup_lag{consumergroup="workers"})`,
+			}
+		})
+
+		Context("when the query results in one sample", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/query", `query=sum%28kafka_consumergroup_lag%7Bconsumergroup%3D%22workers%22%7D%29`),
+					ghttp.RespondWith(http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1560000000.123,"5678"]}]}}`),
+				))
+			})
+			It("returns the value of the sample", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(Equal(5678.0))
+			})
+		})
+
+		Context("when the query results in a scalar", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"success","data":{"resultType":"scalar","result":[1560000000.123,"12.5"]}}`))
+			})
+			It("returns the scalar", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(Equal(12.5))
+			})
+		})
+
+		Context("when the query results in several samples", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"value":[1,"1"]},{"value":[1,"2"]}]}}`))
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("prometheus query resulted in 2 samples instead of 1"))
+			})
+		})
+
+		Context("when the query fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("prometheus query failed: parse error"))
+			})
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package metricsource
+
+import (
+	"autoscaler/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PrometheusSource runs an instant query against the HTTP API of Prometheus. The query must result in a scalar or in
+// a vector of one sample, e.g. sum(kafka_consumergroup_lag{consumergroup="workers"}).
+type PrometheusSource struct {
+	url        string
+	query      string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Value []interface{} `json:"value"`
+}
+
+func NewPrometheusSource(config *models.MetricSourceConfig, httpClient *http.Client) *PrometheusSource {
+	return &PrometheusSource{
+		url:        strings.TrimSuffix(config.URL, "/") + "/api/v1/query",
+		query:      config.Query,
+		headers:    config.Headers,
+		httpClient: httpClient,
+	}
+}
+
+func (s *PrometheusSource) Fetch() (float64, error) {
+	resp, err := get(s.httpClient, s.url+"?"+url.Values{"query": {s.query}}.Encode(), s.headers)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	result := &prometheusResponse{}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(result)
+	if err != nil {
+		return 0, err
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", result.Error)
+	}
+
+	var value []interface{}
+	switch result.Data.ResultType {
+	case "scalar":
+		err = json.Unmarshal(result.Data.Result, &value)
+	case "vector":
+		samples := []prometheusSample{}
+		err = json.Unmarshal(result.Data.Result, &samples)
+		if err == nil && len(samples) != 1 {
+			err = fmt.Errorf("prometheus query resulted in %d samples instead of 1", len(samples))
+		}
+		if err == nil {
+			value = samples[0].Value
+		}
+	default:
+		err = fmt.Errorf("unsupported prometheus result type %s", result.Data.ResultType)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 2 {
+		return 0, fmt.Errorf("prometheus sample %v is not a timestamp and a value", value)
+	}
+	return toFloat(value[1])
+}
@@ -14,15 +14,19 @@ import (
 	"code.cloudfoundry.org/lager"
 )
 
+type QueryAnomaliesFunc func(appID string, start int64, end int64, orderType db.OrderType) ([]*models.AnomalyScore, error)
+
 type EventGenHandler struct {
 	logger         lager.Logger
 	queryAppMetric aggregator.QueryAppMetricsFunc
+	queryAnomalies QueryAnomaliesFunc
 }
 
-func NewEventGenHandler(logger lager.Logger, queryAppMetric aggregator.QueryAppMetricsFunc) *EventGenHandler {
+func NewEventGenHandler(logger lager.Logger, queryAppMetric aggregator.QueryAppMetricsFunc, queryAnomalies QueryAnomaliesFunc) *EventGenHandler {
 	return &EventGenHandler{
 		logger:         logger,
 		queryAppMetric: queryAppMetric,
+		queryAnomalies: queryAnomalies,
 	}
 }
 
@@ -32,9 +36,82 @@ func (h *EventGenHandler) GetAggregatedMetricHistories(w http.ResponseWriter, r
 	startParam := r.URL.Query()["start"]
 	endParam := r.URL.Query()["end"]
 	orderParam := r.URL.Query()["order"]
+	aggregation := r.URL.Query().Get("aggregation")
+
+	h.logger.Debug("get-aggregated-metric-histories", lager.Data{"appid": appID, "metrictype": metricType, "start": startParam, "end": endParam, "order": orderParam, "aggregation": aggregation})
+
+	start, end, order, ok := h.parseQueryRange(w, "get-aggregated-metric-histories", startParam, endParam, orderParam)
+	if !ok {
+		return
+	}
+
+	if aggregation != "" && !models.IsValidAggregation(aggregation) {
+		h.logger.Error("get-aggregated-metric-histories-parse-aggregation", nil, lager.Data{"aggregation": aggregation})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: fmt.Sprintf("Incorrect aggregation parameter in query string, the value can only be one of %s", strings.Join(models.Aggregations, ", ")),
+		})
+		return
+	}
+
+	mtrcs, err := h.queryAppMetric(appID, metricType, aggregation, start, end, order)
+	if err != nil {
+		h.logger.Error("get-aggregated-metric-histories-retrieve-metrics", err, lager.Data{"appid": appID, "metrictype": metricType, "aggregation": aggregation, "start": start, "end": end, "order": order})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error getting aggregated metric histories"})
+		return
+	}
+
+	body, err := json.Marshal(mtrcs)
+	if err != nil {
+		h.logger.Error("get-aggregated-metric-histories-marshal", err, lager.Data{"appid": appID, "metrictype": metricType, "metrics": mtrcs})
+
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error marshaling aggregated metric histories"})
+		return
+	}
+	w.Write(body)
+}
+
+// GetAnomalies returns the anomalies recorded for the anomaly rules of an app in notify mode.
+func (h *EventGenHandler) GetAnomalies(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appID := vars["appid"]
+	startParam := r.URL.Query()["start"]
+	endParam := r.URL.Query()["end"]
+	orderParam := r.URL.Query()["order"]
+
+	h.logger.Debug("get-anomalies", lager.Data{"appid": appID, "start": startParam, "end": endParam, "order": orderParam})
+
+	start, end, order, ok := h.parseQueryRange(w, "get-anomalies", startParam, endParam, orderParam)
+	if !ok {
+		return
+	}
 
-	h.logger.Debug("get-aggregated-metric-histories", lager.Data{"appid": appID, "metrictype": metricType, "start": startParam, "end": endParam, "order": orderParam})
+	anomalies, err := h.queryAnomalies(appID, start, end, order)
+	if err != nil {
+		h.logger.Error("get-anomalies-retrieve-anomalies", err, lager.Data{"appid": appID, "start": start, "end": end, "order": order})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error getting anomalies"})
+		return
+	}
+
+	body, err := json.Marshal(anomalies)
+	if err != nil {
+		h.logger.Error("get-anomalies-marshal", err, lager.Data{"appid": appID, "anomalies": anomalies})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error marshaling anomalies"})
+		return
+	}
+	w.Write(body)
+}
 
+// parseQueryRange parses the start, end and order parameters of the history queries and writes
+// a bad request response when one of them is invalid.
+func (h *EventGenHandler) parseQueryRange(w http.ResponseWriter, action string, startParam, endParam, orderParam []string) (int64, int64, db.OrderType, bool) {
 	var err error
 	start := int64(0)
 	end := int64(-1)
@@ -43,35 +120,35 @@ func (h *EventGenHandler) GetAggregatedMetricHistories(w http.ResponseWriter, r
 	if len(startParam) == 1 {
 		start, err = strconv.ParseInt(startParam[0], 10, 64)
 		if err != nil {
-			h.logger.Error("get-aggregated-metric-histories-parse-start-time", err, lager.Data{"start": startParam})
+			h.logger.Error(action+"-parse-start-time", err, lager.Data{"start": startParam})
 			handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 				Code:    "Bad-Request",
 				Message: "Error parsing start time"})
-			return
+			return 0, 0, 0, false
 		}
 	} else if len(startParam) > 1 {
-		h.logger.Error("get-aggregated-metric-histories-get-start-time", err, lager.Data{"start": startParam})
+		h.logger.Error(action+"-get-start-time", err, lager.Data{"start": startParam})
 		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "Bad-Request",
 			Message: "Incorrect start parameter in query string"})
-		return
+		return 0, 0, 0, false
 	}
 
 	if len(endParam) == 1 {
 		end, err = strconv.ParseInt(endParam[0], 10, 64)
 		if err != nil {
-			h.logger.Error("get-aggregated-metric-histories-parse-end-time", err, lager.Data{"end": endParam})
+			h.logger.Error(action+"-parse-end-time", err, lager.Data{"end": endParam})
 			handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 				Code:    "Bad-Request",
 				Message: "Error parsing end time"})
-			return
+			return 0, 0, 0, false
 		}
 	} else if len(endParam) > 1 {
-		h.logger.Error("get-aggregated-metric-histories-get-end-time", err, lager.Data{"end": endParam})
+		h.logger.Error(action+"-get-end-time", err, lager.Data{"end": endParam})
 		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "Bad-Request",
 			Message: "Incorrect end parameter in query string"})
-		return
+		return 0, 0, 0, false
 	}
 
 	if len(orderParam) == 1 {
@@ -81,41 +158,20 @@ func (h *EventGenHandler) GetAggregatedMetricHistories(w http.ResponseWriter, r
 		} else if orderStr == db.ASCSTR {
 			order = db.ASC
 		} else {
-			h.logger.Error("get-aggregated-metric-histories-parse-order", err, lager.Data{"order": orderParam})
+			h.logger.Error(action+"-parse-order", err, lager.Data{"order": orderParam})
 			handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 				Code:    "Bad-Request",
 				Message: fmt.Sprintf("Incorrect order parameter in query string, the value can only be %s or %s", db.ASCSTR, db.DESCSTR),
 			})
-			return
+			return 0, 0, 0, false
 		}
 	} else if len(orderParam) > 1 {
-		h.logger.Error("get-aggregated-metric-histories-parse-order", err, lager.Data{"order": orderParam})
+		h.logger.Error(action+"-parse-order", err, lager.Data{"order": orderParam})
 		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "Bad-Request",
 			Message: "Incorrect order parameter in query string"})
-		return
+		return 0, 0, 0, false
 	}
 
-	var mtrcs []*models.AppMetric
-
-	mtrcs, err = h.queryAppMetric(appID, metricType, start, end, order)
-	if err != nil {
-		h.logger.Error("get-aggregated-metric-histories-retrieve-metrics", err, lager.Data{"appid": appID, "metrictype": metricType, "start": start, "end": end, "order": order})
-		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
-			Code:    "Interal-Server-Error",
-			Message: "Error getting aggregated metric histories"})
-		return
-	}
-
-	var body []byte
-	body, err = json.Marshal(mtrcs)
-	if err != nil {
-		h.logger.Error("get-aggregated-metric-histories-marshal", err, lager.Data{"appid": appID, "metrictype": metricType, "metrics": mtrcs})
-
-		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
-			Code:    "Interal-Server-Error",
-			Message: "Error marshaling aggregated metric histories"})
-		return
-	}
-	w.Write(body)
+	return start, end, order, true
 }
@@ -17,11 +17,13 @@ import (
 )
 
 var testUrlAggregatedMetricHistories = "http://localhost/v1/apps/an-app-id/aggregated_metric_histories/a-metric-type"
+var testUrlAnomalies = "http://localhost/v1/apps/an-app-id/anomalies"
 
 var _ = Describe("EventgenHandler", func() {
 	var (
 		handler         *EventGenHandler
 		queryAppMetrics aggregator.QueryAppMetricsFunc
+		queryAnomalies  QueryAnomaliesFunc
 
 		resp       *httptest.ResponseRecorder
 		req        *http.Request
@@ -30,6 +32,7 @@ var _ = Describe("EventgenHandler", func() {
 		metric2    models.AppMetric
 		appid      string
 		name       string
+		aggr       string
 		start, end int64
 		order      db.OrderType
 		logger     lager.Logger
@@ -39,7 +42,7 @@ var _ = Describe("EventgenHandler", func() {
 		JustBeforeEach(func() {
 			logger = lager.NewLogger("handler-test")
 			resp = httptest.NewRecorder()
-			handler = NewEventGenHandler(logger, queryAppMetrics)
+			handler = NewEventGenHandler(logger, queryAppMetrics, queryAnomalies)
 			handler.GetAggregatedMetricHistories(resp, req, map[string]string{"appid": "an-app-id", "metrictype": "a-metric-type"})
 		})
 
@@ -169,13 +172,34 @@ var _ = Describe("EventgenHandler", func() {
 				})
 			})
 
+			Context("when aggregation value is invalid", func() {
+				BeforeEach(func() {
+					req, err = http.NewRequest(http.MethodGet, testUrlAggregatedMetricHistories+"?aggregation=median", nil)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("returns 400", func() {
+					Expect(resp.Code).To(Equal(http.StatusBadRequest))
+
+					errJson := &models.ErrorResponse{}
+					err = json.Unmarshal(resp.Body.Bytes(), errJson)
+
+					Expect(err).ToNot(HaveOccurred())
+					Expect(errJson).To(Equal(&models.ErrorResponse{
+						Code:    "Bad-Request",
+						Message: "Incorrect aggregation parameter in query string, the value can only be one of avg, max, min, sum, p50, p90, p99",
+					}))
+				})
+			})
+
 		})
 
 		Context("when request query string is valid", func() {
 			BeforeEach(func() {
-				queryAppMetrics = func(appID string, metricType string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+				queryAppMetrics = func(appID string, metricType string, aggregation string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 					appid = appID
 					name = metricType
+					aggr = aggregation
 					start = startTime
 					end = endTime
 					order = orderType
@@ -198,6 +222,28 @@ var _ = Describe("EventgenHandler", func() {
 
 			})
 
+			Context("when aggregation is in query string", func() {
+				BeforeEach(func() {
+					req, err = http.NewRequest(http.MethodGet, testUrlAggregatedMetricHistories+"?aggregation=p99", nil)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("queries metrics with the given aggregation", func() {
+					Expect(aggr).To(Equal("p99"))
+				})
+			})
+
+			Context("when there is no aggregation in query string", func() {
+				BeforeEach(func() {
+					req, err = http.NewRequest(http.MethodGet, testUrlAggregatedMetricHistories+"?start=123", nil)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("queries metrics of all aggregations", func() {
+					Expect(aggr).To(BeEmpty())
+				})
+			})
+
 			Context("when there is no start time in query string", func() {
 				BeforeEach(func() {
 					req, err = http.NewRequest(http.MethodGet, testUrlAggregatedMetricHistories+"?end=123&order=desc", nil)
@@ -240,22 +286,24 @@ var _ = Describe("EventgenHandler", func() {
 					Expect(err).ToNot(HaveOccurred())
 
 					metric1 = models.AppMetric{
-						AppId:      "an-app-id",
-						MetricType: "a-metric-type",
-						Unit:       "metric-unit",
-						Value:      "12345678",
-						Timestamp:  111100,
+						AppId:       "an-app-id",
+						MetricType:  "a-metric-type",
+						Aggregation: models.AggregationMax,
+						Unit:        "metric-unit",
+						Value:       12345678,
+						Timestamp:   111100,
 					}
 
 					metric2 = models.AppMetric{
-						AppId:      "an-app-id",
-						MetricType: "a-metric-type",
-						Unit:       "metric-unit",
-						Value:      "87654321",
-						Timestamp:  111111,
+						AppId:       "an-app-id",
+						MetricType:  "a-metric-type",
+						Aggregation: models.AggregationMax,
+						Unit:        "metric-unit",
+						Value:       87654321,
+						Timestamp:   111111,
 					}
 
-					queryAppMetrics = func(appID string, metricType string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return []*models.AppMetric{&metric2, &metric1}, nil
 					}
 				})
@@ -276,7 +324,7 @@ var _ = Describe("EventgenHandler", func() {
 					req, err = http.NewRequest(http.MethodGet, testUrlAggregatedMetricHistories+"?start=123&end=567&order=desc", nil)
 					Expect(err).ToNot(HaveOccurred())
 
-					queryAppMetrics = func(appID string, metricType string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+					queryAppMetrics = func(appID string, metricType string, aggregation string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 						return nil, errors.New("an error")
 					}
 
@@ -298,4 +346,102 @@ var _ = Describe("EventgenHandler", func() {
 
 		})
 	})
+
+	Describe("GetAnomalies", func() {
+		var anomaly models.AnomalyScore
+
+		JustBeforeEach(func() {
+			logger = lager.NewLogger("handler-test")
+			resp = httptest.NewRecorder()
+			handler = NewEventGenHandler(logger, queryAppMetrics, queryAnomalies)
+			handler.GetAnomalies(resp, req, map[string]string{"appid": "an-app-id"})
+		})
+
+		Context("when order value is invalid", func() {
+			BeforeEach(func() {
+				req, err = http.NewRequest(http.MethodGet, testUrlAnomalies+"?order=not-order-type", nil)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Bad-Request",
+					Message: fmt.Sprintf("Incorrect order parameter in query string, the value can only be %s or %s", db.ASCSTR, db.DESCSTR),
+				}))
+			})
+		})
+
+		Context("when query anomalies succeeds", func() {
+			BeforeEach(func() {
+				req, err = http.NewRequest(http.MethodGet, testUrlAnomalies+"?start=123&end=567&order=desc", nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				anomaly = models.AnomalyScore{
+					AppId:       "an-app-id",
+					MetricType:  "memoryused",
+					Aggregation: models.AggregationAvg,
+					Value:       500,
+					Unit:        "MB",
+					Baseline:    200,
+					StdDev:      50,
+					Score:       6,
+					Timestamp:   111111,
+				}
+				queryAnomalies = func(appID string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AnomalyScore, error) {
+					appid = appID
+					start = startTime
+					end = endTime
+					order = orderType
+					return []*models.AnomalyScore{&anomaly}, nil
+				}
+			})
+
+			It("queries anomalies with the given start, end and order", func() {
+				Expect(appid).To(Equal("an-app-id"))
+				Expect(start).To(Equal(int64(123)))
+				Expect(end).To(Equal(int64(567)))
+				Expect(order).To(Equal(db.DESC))
+			})
+
+			It("returns 200 with anomalies in message body", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+
+				anomalies := &[]models.AnomalyScore{}
+				err = json.Unmarshal(resp.Body.Bytes(), anomalies)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*anomalies).To(Equal([]models.AnomalyScore{anomaly}))
+			})
+		})
+
+		Context("when query fails", func() {
+			BeforeEach(func() {
+				req, err = http.NewRequest(http.MethodGet, testUrlAnomalies, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				queryAnomalies = func(appID string, startTime int64, endTime int64, orderType db.OrderType) ([]*models.AnomalyScore, error) {
+					return nil, errors.New("an error")
+				}
+			})
+
+			It("returns 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Interal-Server-Error",
+					Message: "Error getting anomalies",
+				}))
+			})
+		})
+	})
 })
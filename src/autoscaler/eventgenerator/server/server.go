@@ -23,12 +23,13 @@ func (vh VarsFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vh(w, r, vars)
 }
 
-func NewServer(logger lager.Logger, conf *config.Config, queryAppMetric aggregator.QueryAppMetricsFunc, httpStatusCollector healthendpoint.HTTPStatusCollector) (ifrit.Runner, error) {
-	eh := NewEventGenHandler(logger, queryAppMetric)
+func NewServer(logger lager.Logger, conf *config.Config, queryAppMetric aggregator.QueryAppMetricsFunc, queryAnomalies QueryAnomaliesFunc, httpStatusCollector healthendpoint.HTTPStatusCollector) (ifrit.Runner, error) {
+	eh := NewEventGenHandler(logger, queryAppMetric, queryAnomalies)
 	httpStatusCollectMiddleware := healthendpoint.NewHTTPStatusCollectMiddleware(httpStatusCollector)
 	r := routes.EventGeneratorRoutes()
 	r.Use(httpStatusCollectMiddleware.Collect)
 	r.Get(routes.GetAggregatedMetricHistoriesRouteName).Handler(VarsFunc(eh.GetAggregatedMetricHistories))
+	r.Get(routes.GetAnomaliesRouteName).Handler(VarsFunc(eh.GetAnomalies))
 
 	addr := fmt.Sprintf("0.0.0.0:%d", conf.Server.Port)
 
@@ -35,12 +35,15 @@ var _ = BeforeSuite(func() {
 			Port: port,
 		},
 	}
-	queryAppMetrics := func(appID string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+	queryAppMetrics := func(appID string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+		return nil, nil
+	}
+	queryAnomalies := func(appID string, start int64, end int64, orderType db.OrderType) ([]*models.AnomalyScore, error) {
 		return nil, nil
 	}
 
 	httpStatusCollector := &fakes.FakeHTTPStatusCollector{}
-	httpServer, err := server.NewServer(lager.NewLogger("test"), conf, queryAppMetrics, httpStatusCollector)
+	httpServer, err := server.NewServer(lager.NewLogger("test"), conf, queryAppMetrics, queryAnomalies, httpStatusCollector)
 	Expect(err).NotTo(HaveOccurred())
 
 	serverUrl, err = url.Parse("http://127.0.0.1:" + strconv.Itoa(port))
@@ -8,6 +8,7 @@ import (
 )
 
 const TestPathAggregatedMetricHistories = "/v1/apps/an-app-id/aggregated_metric_histories/a-metric-type"
+const TestPathAnomalies = "/v1/apps/an-app-id/anomalies"
 
 var _ = Describe("Server", func() {
 	var (
@@ -31,6 +32,22 @@ var _ = Describe("Server", func() {
 		})
 	})
 
+	Context("when retrieving anomalies", func() {
+		BeforeEach(func() {
+			serverUrl.Path = TestPathAnomalies
+		})
+
+		JustBeforeEach(func() {
+			rsp, err = http.Get(serverUrl.String())
+		})
+
+		It("should return 200", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+			rsp.Body.Close()
+		})
+	})
+
 	Context("when requesting the wrong path", func() {
 		BeforeEach(func() {
 			serverUrl.Path = "/not-exist-path"
@@ -0,0 +1,64 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"autoscaler/healthendpoint"
+	"sync"
+)
+
+type FakeAnomalyRecordStatusProvider struct {
+	GetAnomalyRecordStatusStub        func() *healthendpoint.AnomalyRecordStatus
+	getAnomalyRecordStatusMutex       sync.RWMutex
+	getAnomalyRecordStatusArgsForCall []struct{}
+	getAnomalyRecordStatusReturns     struct {
+		result1 *healthendpoint.AnomalyRecordStatus
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAnomalyRecordStatusProvider) GetAnomalyRecordStatus() *healthendpoint.AnomalyRecordStatus {
+	fake.getAnomalyRecordStatusMutex.Lock()
+	fake.getAnomalyRecordStatusArgsForCall = append(fake.getAnomalyRecordStatusArgsForCall, struct{}{})
+	fake.recordInvocation("GetAnomalyRecordStatus", []interface{}{})
+	fake.getAnomalyRecordStatusMutex.Unlock()
+	if fake.GetAnomalyRecordStatusStub != nil {
+		return fake.GetAnomalyRecordStatusStub()
+	}
+	return fake.getAnomalyRecordStatusReturns.result1
+}
+
+func (fake *FakeAnomalyRecordStatusProvider) GetAnomalyRecordStatusCallCount() int {
+	fake.getAnomalyRecordStatusMutex.RLock()
+	defer fake.getAnomalyRecordStatusMutex.RUnlock()
+	return len(fake.getAnomalyRecordStatusArgsForCall)
+}
+
+func (fake *FakeAnomalyRecordStatusProvider) GetAnomalyRecordStatusReturns(result1 *healthendpoint.AnomalyRecordStatus) {
+	fake.GetAnomalyRecordStatusStub = nil
+	fake.getAnomalyRecordStatusReturns = struct {
+		result1 *healthendpoint.AnomalyRecordStatus
+	}{result1}
+}
+
+func (fake *FakeAnomalyRecordStatusProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAnomalyRecordStatusMutex.RLock()
+	defer fake.getAnomalyRecordStatusMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAnomalyRecordStatusProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ healthendpoint.AnomalyRecordStatusProvider = new(FakeAnomalyRecordStatusProvider)
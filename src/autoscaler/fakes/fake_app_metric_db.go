@@ -31,14 +31,15 @@ type FakeAppMetricDB struct {
 	saveAppMetricsInBulkReturns struct {
 		result1 error
 	}
-	RetrieveAppMetricsStub        func(appId string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error)
+	RetrieveAppMetricsStub        func(appId string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error)
 	retrieveAppMetricsMutex       sync.RWMutex
 	retrieveAppMetricsArgsForCall []struct {
-		appId      string
-		metricType string
-		start      int64
-		end        int64
-		orderType  db.OrderType
+		appId       string
+		metricType  string
+		aggregation string
+		start       int64
+		end         int64
+		orderType   db.OrderType
 	}
 	retrieveAppMetricsReturns struct {
 		result1 []*models.AppMetric
@@ -52,6 +53,26 @@ type FakeAppMetricDB struct {
 	pruneAppMetricsReturns struct {
 		result1 error
 	}
+	SaveAnomalyScoreStub        func(score *models.AnomalyScore) error
+	saveAnomalyScoreMutex       sync.RWMutex
+	saveAnomalyScoreArgsForCall []struct {
+		score *models.AnomalyScore
+	}
+	saveAnomalyScoreReturns struct {
+		result1 error
+	}
+	RetrieveAnomalyScoresStub        func(appId string, start int64, end int64, orderType db.OrderType) ([]*models.AnomalyScore, error)
+	retrieveAnomalyScoresMutex       sync.RWMutex
+	retrieveAnomalyScoresArgsForCall []struct {
+		appId     string
+		start     int64
+		end       int64
+		orderType db.OrderType
+	}
+	retrieveAnomalyScoresReturns struct {
+		result1 []*models.AnomalyScore
+		result2 error
+	}
 	CloseStub        func() error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct{}
@@ -155,19 +176,20 @@ func (fake *FakeAppMetricDB) SaveAppMetricsInBulkReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAppMetricDB) RetrieveAppMetrics(appId string, metricType string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
+func (fake *FakeAppMetricDB) RetrieveAppMetrics(appId string, metricType string, aggregation string, start int64, end int64, orderType db.OrderType) ([]*models.AppMetric, error) {
 	fake.retrieveAppMetricsMutex.Lock()
 	fake.retrieveAppMetricsArgsForCall = append(fake.retrieveAppMetricsArgsForCall, struct {
-		appId      string
-		metricType string
-		start      int64
-		end        int64
-		orderType  db.OrderType
-	}{appId, metricType, start, end, orderType})
-	fake.recordInvocation("RetrieveAppMetrics", []interface{}{appId, metricType, start, end, orderType})
+		appId       string
+		metricType  string
+		aggregation string
+		start       int64
+		end         int64
+		orderType   db.OrderType
+	}{appId, metricType, aggregation, start, end, orderType})
+	fake.recordInvocation("RetrieveAppMetrics", []interface{}{appId, metricType, aggregation, start, end, orderType})
 	fake.retrieveAppMetricsMutex.Unlock()
 	if fake.RetrieveAppMetricsStub != nil {
-		return fake.RetrieveAppMetricsStub(appId, metricType, start, end, orderType)
+		return fake.RetrieveAppMetricsStub(appId, metricType, aggregation, start, end, orderType)
 	}
 	return fake.retrieveAppMetricsReturns.result1, fake.retrieveAppMetricsReturns.result2
 }
@@ -178,10 +200,10 @@ func (fake *FakeAppMetricDB) RetrieveAppMetricsCallCount() int {
 	return len(fake.retrieveAppMetricsArgsForCall)
 }
 
-func (fake *FakeAppMetricDB) RetrieveAppMetricsArgsForCall(i int) (string, string, int64, int64, db.OrderType) {
+func (fake *FakeAppMetricDB) RetrieveAppMetricsArgsForCall(i int) (string, string, string, int64, int64, db.OrderType) {
 	fake.retrieveAppMetricsMutex.RLock()
 	defer fake.retrieveAppMetricsMutex.RUnlock()
-	return fake.retrieveAppMetricsArgsForCall[i].appId, fake.retrieveAppMetricsArgsForCall[i].metricType, fake.retrieveAppMetricsArgsForCall[i].start, fake.retrieveAppMetricsArgsForCall[i].end, fake.retrieveAppMetricsArgsForCall[i].orderType
+	return fake.retrieveAppMetricsArgsForCall[i].appId, fake.retrieveAppMetricsArgsForCall[i].metricType, fake.retrieveAppMetricsArgsForCall[i].aggregation, fake.retrieveAppMetricsArgsForCall[i].start, fake.retrieveAppMetricsArgsForCall[i].end, fake.retrieveAppMetricsArgsForCall[i].orderType
 }
 
 func (fake *FakeAppMetricDB) RetrieveAppMetricsReturns(result1 []*models.AppMetric, result2 error) {
@@ -224,6 +246,74 @@ func (fake *FakeAppMetricDB) PruneAppMetricsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAppMetricDB) SaveAnomalyScore(score *models.AnomalyScore) error {
+	fake.saveAnomalyScoreMutex.Lock()
+	fake.saveAnomalyScoreArgsForCall = append(fake.saveAnomalyScoreArgsForCall, struct {
+		score *models.AnomalyScore
+	}{score})
+	fake.recordInvocation("SaveAnomalyScore", []interface{}{score})
+	fake.saveAnomalyScoreMutex.Unlock()
+	if fake.SaveAnomalyScoreStub != nil {
+		return fake.SaveAnomalyScoreStub(score)
+	}
+	return fake.saveAnomalyScoreReturns.result1
+}
+
+func (fake *FakeAppMetricDB) SaveAnomalyScoreCallCount() int {
+	fake.saveAnomalyScoreMutex.RLock()
+	defer fake.saveAnomalyScoreMutex.RUnlock()
+	return len(fake.saveAnomalyScoreArgsForCall)
+}
+
+func (fake *FakeAppMetricDB) SaveAnomalyScoreArgsForCall(i int) *models.AnomalyScore {
+	fake.saveAnomalyScoreMutex.RLock()
+	defer fake.saveAnomalyScoreMutex.RUnlock()
+	return fake.saveAnomalyScoreArgsForCall[i].score
+}
+
+func (fake *FakeAppMetricDB) SaveAnomalyScoreReturns(result1 error) {
+	fake.SaveAnomalyScoreStub = nil
+	fake.saveAnomalyScoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppMetricDB) RetrieveAnomalyScores(appId string, start int64, end int64, orderType db.OrderType) ([]*models.AnomalyScore, error) {
+	fake.retrieveAnomalyScoresMutex.Lock()
+	fake.retrieveAnomalyScoresArgsForCall = append(fake.retrieveAnomalyScoresArgsForCall, struct {
+		appId     string
+		start     int64
+		end       int64
+		orderType db.OrderType
+	}{appId, start, end, orderType})
+	fake.recordInvocation("RetrieveAnomalyScores", []interface{}{appId, start, end, orderType})
+	fake.retrieveAnomalyScoresMutex.Unlock()
+	if fake.RetrieveAnomalyScoresStub != nil {
+		return fake.RetrieveAnomalyScoresStub(appId, start, end, orderType)
+	}
+	return fake.retrieveAnomalyScoresReturns.result1, fake.retrieveAnomalyScoresReturns.result2
+}
+
+func (fake *FakeAppMetricDB) RetrieveAnomalyScoresCallCount() int {
+	fake.retrieveAnomalyScoresMutex.RLock()
+	defer fake.retrieveAnomalyScoresMutex.RUnlock()
+	return len(fake.retrieveAnomalyScoresArgsForCall)
+}
+
+func (fake *FakeAppMetricDB) RetrieveAnomalyScoresArgsForCall(i int) (string, int64, int64, db.OrderType) {
+	fake.retrieveAnomalyScoresMutex.RLock()
+	defer fake.retrieveAnomalyScoresMutex.RUnlock()
+	return fake.retrieveAnomalyScoresArgsForCall[i].appId, fake.retrieveAnomalyScoresArgsForCall[i].start, fake.retrieveAnomalyScoresArgsForCall[i].end, fake.retrieveAnomalyScoresArgsForCall[i].orderType
+}
+
+func (fake *FakeAppMetricDB) RetrieveAnomalyScoresReturns(result1 []*models.AnomalyScore, result2 error) {
+	fake.RetrieveAnomalyScoresStub = nil
+	fake.retrieveAnomalyScoresReturns = struct {
+		result1 []*models.AnomalyScore
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAppMetricDB) Close() error {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
@@ -261,6 +351,10 @@ func (fake *FakeAppMetricDB) Invocations() map[string][][]interface{} {
 	defer fake.retrieveAppMetricsMutex.RUnlock()
 	fake.pruneAppMetricsMutex.RLock()
 	defer fake.pruneAppMetricsMutex.RUnlock()
+	fake.saveAnomalyScoreMutex.RLock()
+	defer fake.saveAnomalyScoreMutex.RUnlock()
+	fake.retrieveAnomalyScoresMutex.RLock()
+	defer fake.retrieveAnomalyScoresMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
 	return fake.invocations
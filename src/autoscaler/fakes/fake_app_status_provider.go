@@ -0,0 +1,64 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"autoscaler/healthendpoint"
+	"sync"
+)
+
+type FakeAppStatusProvider struct {
+	GetAppStatusesStub        func() []*healthendpoint.AppStatus
+	getAppStatusesMutex       sync.RWMutex
+	getAppStatusesArgsForCall []struct{}
+	getAppStatusesReturns     struct {
+		result1 []*healthendpoint.AppStatus
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAppStatusProvider) GetAppStatuses() []*healthendpoint.AppStatus {
+	fake.getAppStatusesMutex.Lock()
+	fake.getAppStatusesArgsForCall = append(fake.getAppStatusesArgsForCall, struct{}{})
+	fake.recordInvocation("GetAppStatuses", []interface{}{})
+	fake.getAppStatusesMutex.Unlock()
+	if fake.GetAppStatusesStub != nil {
+		return fake.GetAppStatusesStub()
+	}
+	return fake.getAppStatusesReturns.result1
+}
+
+func (fake *FakeAppStatusProvider) GetAppStatusesCallCount() int {
+	fake.getAppStatusesMutex.RLock()
+	defer fake.getAppStatusesMutex.RUnlock()
+	return len(fake.getAppStatusesArgsForCall)
+}
+
+func (fake *FakeAppStatusProvider) GetAppStatusesReturns(result1 []*healthendpoint.AppStatus) {
+	fake.GetAppStatusesStub = nil
+	fake.getAppStatusesReturns = struct {
+		result1 []*healthendpoint.AppStatus
+	}{result1}
+}
+
+func (fake *FakeAppStatusProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAppStatusesMutex.RLock()
+	defer fake.getAppStatusesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAppStatusProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ healthendpoint.AppStatusProvider = new(FakeAppStatusProvider)
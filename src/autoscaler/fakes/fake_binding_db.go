@@ -8,6 +8,19 @@ import (
 )
 
 type FakeBindingDB struct {
+	CheckServiceBindingStub        func(string) (bool, error)
+	checkServiceBindingMutex       sync.RWMutex
+	checkServiceBindingArgsForCall []struct {
+		arg1 string
+	}
+	checkServiceBindingReturns struct {
+		result1 bool
+		result2 error
+	}
+	checkServiceBindingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	CloseStub        func() error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -80,6 +93,69 @@ type FakeBindingDB struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeBindingDB) CheckServiceBinding(arg1 string) (bool, error) {
+	fake.checkServiceBindingMutex.Lock()
+	ret, specificReturn := fake.checkServiceBindingReturnsOnCall[len(fake.checkServiceBindingArgsForCall)]
+	fake.checkServiceBindingArgsForCall = append(fake.checkServiceBindingArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("CheckServiceBinding", []interface{}{arg1})
+	fake.checkServiceBindingMutex.Unlock()
+	if fake.CheckServiceBindingStub != nil {
+		return fake.CheckServiceBindingStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.checkServiceBindingReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBindingDB) CheckServiceBindingCallCount() int {
+	fake.checkServiceBindingMutex.RLock()
+	defer fake.checkServiceBindingMutex.RUnlock()
+	return len(fake.checkServiceBindingArgsForCall)
+}
+
+func (fake *FakeBindingDB) CheckServiceBindingCalls(stub func(string) (bool, error)) {
+	fake.checkServiceBindingMutex.Lock()
+	defer fake.checkServiceBindingMutex.Unlock()
+	fake.CheckServiceBindingStub = stub
+}
+
+func (fake *FakeBindingDB) CheckServiceBindingArgsForCall(i int) string {
+	fake.checkServiceBindingMutex.RLock()
+	defer fake.checkServiceBindingMutex.RUnlock()
+	argsForCall := fake.checkServiceBindingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBindingDB) CheckServiceBindingReturns(result1 bool, result2 error) {
+	fake.checkServiceBindingMutex.Lock()
+	defer fake.checkServiceBindingMutex.Unlock()
+	fake.CheckServiceBindingStub = nil
+	fake.checkServiceBindingReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBindingDB) CheckServiceBindingReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.checkServiceBindingMutex.Lock()
+	defer fake.checkServiceBindingMutex.Unlock()
+	fake.CheckServiceBindingStub = nil
+	if fake.checkServiceBindingReturnsOnCall == nil {
+		fake.checkServiceBindingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.checkServiceBindingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBindingDB) Close() error {
 	fake.closeMutex.Lock()
 	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
@@ -431,6 +507,8 @@ func (fake *FakeBindingDB) GetDBStatusReturnsOnCall(i int, result1 sql.DBStats)
 func (fake *FakeBindingDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkServiceBindingMutex.RLock()
+	defer fake.checkServiceBindingMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
 	fake.createServiceBindingMutex.RLock()
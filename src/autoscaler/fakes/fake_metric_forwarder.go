@@ -13,6 +13,17 @@ type FakeMetricForwarder struct {
 	emitMetricArgsForCall []struct {
 		arg1 *models.CustomMetric
 	}
+	EnqueueMetricsStub        func([]*models.CustomMetric) int
+	enqueueMetricsMutex       sync.RWMutex
+	enqueueMetricsArgsForCall []struct {
+		arg1 []*models.CustomMetric
+	}
+	enqueueMetricsReturns struct {
+		result1 int
+	}
+	enqueueMetricsReturnsOnCall map[int]struct {
+		result1 int
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -41,11 +52,78 @@ func (fake *FakeMetricForwarder) EmitMetricArgsForCall(i int) *models.CustomMetr
 	return fake.emitMetricArgsForCall[i].arg1
 }
 
+func (fake *FakeMetricForwarder) EnqueueMetrics(arg1 []*models.CustomMetric) int {
+	var arg1Copy []*models.CustomMetric
+	if arg1 != nil {
+		arg1Copy = make([]*models.CustomMetric, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.enqueueMetricsMutex.Lock()
+	ret, specificReturn := fake.enqueueMetricsReturnsOnCall[len(fake.enqueueMetricsArgsForCall)]
+	fake.enqueueMetricsArgsForCall = append(fake.enqueueMetricsArgsForCall, struct {
+		arg1 []*models.CustomMetric
+	}{arg1Copy})
+	fake.recordInvocation("EnqueueMetrics", []interface{}{arg1Copy})
+	fake.enqueueMetricsMutex.Unlock()
+	if fake.EnqueueMetricsStub != nil {
+		return fake.EnqueueMetricsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.enqueueMetricsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeMetricForwarder) EnqueueMetricsCallCount() int {
+	fake.enqueueMetricsMutex.RLock()
+	defer fake.enqueueMetricsMutex.RUnlock()
+	return len(fake.enqueueMetricsArgsForCall)
+}
+
+func (fake *FakeMetricForwarder) EnqueueMetricsCalls(stub func([]*models.CustomMetric) int) {
+	fake.enqueueMetricsMutex.Lock()
+	defer fake.enqueueMetricsMutex.Unlock()
+	fake.EnqueueMetricsStub = stub
+}
+
+func (fake *FakeMetricForwarder) EnqueueMetricsArgsForCall(i int) []*models.CustomMetric {
+	fake.enqueueMetricsMutex.RLock()
+	defer fake.enqueueMetricsMutex.RUnlock()
+	argsForCall := fake.enqueueMetricsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeMetricForwarder) EnqueueMetricsReturns(result1 int) {
+	fake.enqueueMetricsMutex.Lock()
+	defer fake.enqueueMetricsMutex.Unlock()
+	fake.EnqueueMetricsStub = nil
+	fake.enqueueMetricsReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeMetricForwarder) EnqueueMetricsReturnsOnCall(i int, result1 int) {
+	fake.enqueueMetricsMutex.Lock()
+	defer fake.enqueueMetricsMutex.Unlock()
+	fake.EnqueueMetricsStub = nil
+	if fake.enqueueMetricsReturnsOnCall == nil {
+		fake.enqueueMetricsReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.enqueueMetricsReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
 func (fake *FakeMetricForwarder) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.emitMetricMutex.RLock()
 	defer fake.emitMetricMutex.RUnlock()
+	fake.enqueueMetricsMutex.RLock()
+	defer fake.enqueueMetricsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
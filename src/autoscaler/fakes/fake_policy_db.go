@@ -19,6 +19,29 @@ type FakePolicyDB struct {
 	closeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteAppPauseStub        func(string) error
+	deleteAppPauseMutex       sync.RWMutex
+	deleteAppPauseArgsForCall []struct {
+		arg1 string
+	}
+	deleteAppPauseReturns struct {
+		result1 error
+	}
+	deleteAppPauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteExpiredAppPauseStub        func(string, int64) error
+	deleteExpiredAppPauseMutex       sync.RWMutex
+	deleteExpiredAppPauseArgsForCall []struct {
+		arg1 string
+		arg2 int64
+	}
+	deleteExpiredAppPauseReturns struct {
+		result1 error
+	}
+	deleteExpiredAppPauseReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeletePolicyStub        func(string) error
 	deletePolicyMutex       sync.RWMutex
 	deletePolicyArgsForCall []struct {
@@ -30,6 +53,17 @@ type FakePolicyDB struct {
 	deletePolicyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteScalingGroupStub        func(string) error
+	deleteScalingGroupMutex       sync.RWMutex
+	deleteScalingGroupArgsForCall []struct {
+		arg1 string
+	}
+	deleteScalingGroupReturns struct {
+		result1 error
+	}
+	deleteScalingGroupReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetAppIdsStub        func() (map[string]bool, error)
 	getAppIdsMutex       sync.RWMutex
 	getAppIdsArgsForCall []struct {
@@ -42,6 +76,19 @@ type FakePolicyDB struct {
 		result1 map[string]bool
 		result2 error
 	}
+	GetAppPauseStub        func(string) (*models.AppPause, error)
+	getAppPauseMutex       sync.RWMutex
+	getAppPauseArgsForCall []struct {
+		arg1 string
+	}
+	getAppPauseReturns struct {
+		result1 *models.AppPause
+		result2 error
+	}
+	getAppPauseReturnsOnCall map[int]struct {
+		result1 *models.AppPause
+		result2 error
+	}
 	GetAppPolicyStub        func(string) (*models.ScalingPolicy, error)
 	getAppPolicyMutex       sync.RWMutex
 	getAppPolicyArgsForCall []struct {
@@ -55,20 +102,31 @@ type FakePolicyDB struct {
 		result1 *models.ScalingPolicy
 		result2 error
 	}
-	GetCustomMetricsCredsStub        func(string) (string, string, error)
+	GetAppPolicyJsonStub        func(string) (*models.PolicyJson, error)
+	getAppPolicyJsonMutex       sync.RWMutex
+	getAppPolicyJsonArgsForCall []struct {
+		arg1 string
+	}
+	getAppPolicyJsonReturns struct {
+		result1 *models.PolicyJson
+		result2 error
+	}
+	getAppPolicyJsonReturnsOnCall map[int]struct {
+		result1 *models.PolicyJson
+		result2 error
+	}
+	GetCustomMetricsCredsStub        func(string) ([]*models.CustomMetricCredentials, error)
 	getCustomMetricsCredsMutex       sync.RWMutex
 	getCustomMetricsCredsArgsForCall []struct {
 		arg1 string
 	}
 	getCustomMetricsCredsReturns struct {
-		result1 string
-		result2 string
-		result3 error
+		result1 []*models.CustomMetricCredentials
+		result2 error
 	}
 	getCustomMetricsCredsReturnsOnCall map[int]struct {
-		result1 string
-		result2 string
-		result3 error
+		result1 []*models.CustomMetricCredentials
+		result2 error
 	}
 	GetDBStatusStub        func() sql.DBStats
 	getDBStatusMutex       sync.RWMutex
@@ -80,6 +138,45 @@ type FakePolicyDB struct {
 	getDBStatusReturnsOnCall map[int]struct {
 		result1 sql.DBStats
 	}
+	GetPolicyVersionStub        func(string, int) (*models.PolicyVersion, error)
+	getPolicyVersionMutex       sync.RWMutex
+	getPolicyVersionArgsForCall []struct {
+		arg1 string
+		arg2 int
+	}
+	getPolicyVersionReturns struct {
+		result1 *models.PolicyVersion
+		result2 error
+	}
+	getPolicyVersionReturnsOnCall map[int]struct {
+		result1 *models.PolicyVersion
+		result2 error
+	}
+	GetScalingGroupStub        func(string) (*models.ScalingGroup, error)
+	getScalingGroupMutex       sync.RWMutex
+	getScalingGroupArgsForCall []struct {
+		arg1 string
+	}
+	getScalingGroupReturns struct {
+		result1 *models.ScalingGroup
+		result2 error
+	}
+	getScalingGroupReturnsOnCall map[int]struct {
+		result1 *models.ScalingGroup
+		result2 error
+	}
+	RetrieveAppPausesStub        func() (map[string]*models.AppPause, error)
+	retrieveAppPausesMutex       sync.RWMutex
+	retrieveAppPausesArgsForCall []struct {
+	}
+	retrieveAppPausesReturns struct {
+		result1 map[string]*models.AppPause
+		result2 error
+	}
+	retrieveAppPausesReturnsOnCall map[int]struct {
+		result1 map[string]*models.AppPause
+		result2 error
+	}
 	RetrievePoliciesStub        func() ([]*models.PolicyJson, error)
 	retrievePoliciesMutex       sync.RWMutex
 	retrievePoliciesArgsForCall []struct {
@@ -92,12 +189,64 @@ type FakePolicyDB struct {
 		result1 []*models.PolicyJson
 		result2 error
 	}
-	SaveAppPolicyStub        func(string, string, string) error
+	RetrievePolicyVersionsStub        func(string) ([]*models.PolicyVersion, error)
+	retrievePolicyVersionsMutex       sync.RWMutex
+	retrievePolicyVersionsArgsForCall []struct {
+		arg1 string
+	}
+	retrievePolicyVersionsReturns struct {
+		result1 []*models.PolicyVersion
+		result2 error
+	}
+	retrievePolicyVersionsReturnsOnCall map[int]struct {
+		result1 []*models.PolicyVersion
+		result2 error
+	}
+	RevertAppPolicyStub        func(string, string, *models.PolicyJson) error
+	revertAppPolicyMutex       sync.RWMutex
+	revertAppPolicyArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *models.PolicyJson
+	}
+	revertAppPolicyReturns struct {
+		result1 error
+	}
+	revertAppPolicyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RotateCustomMetricsCredsStub        func(string, string, string, int64) error
+	rotateCustomMetricsCredsMutex       sync.RWMutex
+	rotateCustomMetricsCredsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 int64
+	}
+	rotateCustomMetricsCredsReturns struct {
+		result1 error
+	}
+	rotateCustomMetricsCredsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SaveAppPauseStub        func(*models.AppPause) error
+	saveAppPauseMutex       sync.RWMutex
+	saveAppPauseArgsForCall []struct {
+		arg1 *models.AppPause
+	}
+	saveAppPauseReturns struct {
+		result1 error
+	}
+	saveAppPauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SaveAppPolicyStub        func(string, string, string, string) error
 	saveAppPolicyMutex       sync.RWMutex
 	saveAppPolicyArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	saveAppPolicyReturns struct {
 		result1 error
@@ -105,6 +254,17 @@ type FakePolicyDB struct {
 	saveAppPolicyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SaveScalingGroupStub        func(*models.ScalingGroup) error
+	saveScalingGroupMutex       sync.RWMutex
+	saveScalingGroupArgsForCall []struct {
+		arg1 *models.ScalingGroup
+	}
+	saveScalingGroupReturns struct {
+		result1 error
+	}
+	saveScalingGroupReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -161,6 +321,127 @@ func (fake *FakePolicyDB) CloseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePolicyDB) DeleteAppPause(arg1 string) error {
+	fake.deleteAppPauseMutex.Lock()
+	ret, specificReturn := fake.deleteAppPauseReturnsOnCall[len(fake.deleteAppPauseArgsForCall)]
+	fake.deleteAppPauseArgsForCall = append(fake.deleteAppPauseArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteAppPause", []interface{}{arg1})
+	fake.deleteAppPauseMutex.Unlock()
+	if fake.DeleteAppPauseStub != nil {
+		return fake.DeleteAppPauseStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteAppPauseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) DeleteAppPauseCallCount() int {
+	fake.deleteAppPauseMutex.RLock()
+	defer fake.deleteAppPauseMutex.RUnlock()
+	return len(fake.deleteAppPauseArgsForCall)
+}
+
+func (fake *FakePolicyDB) DeleteAppPauseCalls(stub func(string) error) {
+	fake.deleteAppPauseMutex.Lock()
+	defer fake.deleteAppPauseMutex.Unlock()
+	fake.DeleteAppPauseStub = stub
+}
+
+func (fake *FakePolicyDB) DeleteAppPauseArgsForCall(i int) string {
+	fake.deleteAppPauseMutex.RLock()
+	defer fake.deleteAppPauseMutex.RUnlock()
+	argsForCall := fake.deleteAppPauseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) DeleteAppPauseReturns(result1 error) {
+	fake.deleteAppPauseMutex.Lock()
+	defer fake.deleteAppPauseMutex.Unlock()
+	fake.DeleteAppPauseStub = nil
+	fake.deleteAppPauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) DeleteAppPauseReturnsOnCall(i int, result1 error) {
+	fake.deleteAppPauseMutex.Lock()
+	defer fake.deleteAppPauseMutex.Unlock()
+	fake.DeleteAppPauseStub = nil
+	if fake.deleteAppPauseReturnsOnCall == nil {
+		fake.deleteAppPauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteAppPauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPause(arg1 string, arg2 int64) error {
+	fake.deleteExpiredAppPauseMutex.Lock()
+	ret, specificReturn := fake.deleteExpiredAppPauseReturnsOnCall[len(fake.deleteExpiredAppPauseArgsForCall)]
+	fake.deleteExpiredAppPauseArgsForCall = append(fake.deleteExpiredAppPauseArgsForCall, struct {
+		arg1 string
+		arg2 int64
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteExpiredAppPause", []interface{}{arg1, arg2})
+	fake.deleteExpiredAppPauseMutex.Unlock()
+	if fake.DeleteExpiredAppPauseStub != nil {
+		return fake.DeleteExpiredAppPauseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteExpiredAppPauseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPauseCallCount() int {
+	fake.deleteExpiredAppPauseMutex.RLock()
+	defer fake.deleteExpiredAppPauseMutex.RUnlock()
+	return len(fake.deleteExpiredAppPauseArgsForCall)
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPauseCalls(stub func(string, int64) error) {
+	fake.deleteExpiredAppPauseMutex.Lock()
+	defer fake.deleteExpiredAppPauseMutex.Unlock()
+	fake.DeleteExpiredAppPauseStub = stub
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPauseArgsForCall(i int) (string, int64) {
+	fake.deleteExpiredAppPauseMutex.RLock()
+	defer fake.deleteExpiredAppPauseMutex.RUnlock()
+	argsForCall := fake.deleteExpiredAppPauseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPauseReturns(result1 error) {
+	fake.deleteExpiredAppPauseMutex.Lock()
+	defer fake.deleteExpiredAppPauseMutex.Unlock()
+	fake.DeleteExpiredAppPauseStub = nil
+	fake.deleteExpiredAppPauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) DeleteExpiredAppPauseReturnsOnCall(i int, result1 error) {
+	fake.deleteExpiredAppPauseMutex.Lock()
+	defer fake.deleteExpiredAppPauseMutex.Unlock()
+	fake.DeleteExpiredAppPauseStub = nil
+	if fake.deleteExpiredAppPauseReturnsOnCall == nil {
+		fake.deleteExpiredAppPauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteExpiredAppPauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePolicyDB) DeletePolicy(arg1 string) error {
 	fake.deletePolicyMutex.Lock()
 	ret, specificReturn := fake.deletePolicyReturnsOnCall[len(fake.deletePolicyArgsForCall)]
@@ -221,6 +502,66 @@ func (fake *FakePolicyDB) DeletePolicyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePolicyDB) DeleteScalingGroup(arg1 string) error {
+	fake.deleteScalingGroupMutex.Lock()
+	ret, specificReturn := fake.deleteScalingGroupReturnsOnCall[len(fake.deleteScalingGroupArgsForCall)]
+	fake.deleteScalingGroupArgsForCall = append(fake.deleteScalingGroupArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteScalingGroup", []interface{}{arg1})
+	fake.deleteScalingGroupMutex.Unlock()
+	if fake.DeleteScalingGroupStub != nil {
+		return fake.DeleteScalingGroupStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteScalingGroupReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) DeleteScalingGroupCallCount() int {
+	fake.deleteScalingGroupMutex.RLock()
+	defer fake.deleteScalingGroupMutex.RUnlock()
+	return len(fake.deleteScalingGroupArgsForCall)
+}
+
+func (fake *FakePolicyDB) DeleteScalingGroupCalls(stub func(string) error) {
+	fake.deleteScalingGroupMutex.Lock()
+	defer fake.deleteScalingGroupMutex.Unlock()
+	fake.DeleteScalingGroupStub = stub
+}
+
+func (fake *FakePolicyDB) DeleteScalingGroupArgsForCall(i int) string {
+	fake.deleteScalingGroupMutex.RLock()
+	defer fake.deleteScalingGroupMutex.RUnlock()
+	argsForCall := fake.deleteScalingGroupArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) DeleteScalingGroupReturns(result1 error) {
+	fake.deleteScalingGroupMutex.Lock()
+	defer fake.deleteScalingGroupMutex.Unlock()
+	fake.DeleteScalingGroupStub = nil
+	fake.deleteScalingGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) DeleteScalingGroupReturnsOnCall(i int, result1 error) {
+	fake.deleteScalingGroupMutex.Lock()
+	defer fake.deleteScalingGroupMutex.Unlock()
+	fake.DeleteScalingGroupStub = nil
+	if fake.deleteScalingGroupReturnsOnCall == nil {
+		fake.deleteScalingGroupReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteScalingGroupReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePolicyDB) GetAppIds() (map[string]bool, error) {
 	fake.getAppIdsMutex.Lock()
 	ret, specificReturn := fake.getAppIdsReturnsOnCall[len(fake.getAppIdsArgsForCall)]
@@ -276,6 +617,69 @@ func (fake *FakePolicyDB) GetAppIdsReturnsOnCall(i int, result1 map[string]bool,
 	}{result1, result2}
 }
 
+func (fake *FakePolicyDB) GetAppPause(arg1 string) (*models.AppPause, error) {
+	fake.getAppPauseMutex.Lock()
+	ret, specificReturn := fake.getAppPauseReturnsOnCall[len(fake.getAppPauseArgsForCall)]
+	fake.getAppPauseArgsForCall = append(fake.getAppPauseArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetAppPause", []interface{}{arg1})
+	fake.getAppPauseMutex.Unlock()
+	if fake.GetAppPauseStub != nil {
+		return fake.GetAppPauseStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAppPauseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) GetAppPauseCallCount() int {
+	fake.getAppPauseMutex.RLock()
+	defer fake.getAppPauseMutex.RUnlock()
+	return len(fake.getAppPauseArgsForCall)
+}
+
+func (fake *FakePolicyDB) GetAppPauseCalls(stub func(string) (*models.AppPause, error)) {
+	fake.getAppPauseMutex.Lock()
+	defer fake.getAppPauseMutex.Unlock()
+	fake.GetAppPauseStub = stub
+}
+
+func (fake *FakePolicyDB) GetAppPauseArgsForCall(i int) string {
+	fake.getAppPauseMutex.RLock()
+	defer fake.getAppPauseMutex.RUnlock()
+	argsForCall := fake.getAppPauseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) GetAppPauseReturns(result1 *models.AppPause, result2 error) {
+	fake.getAppPauseMutex.Lock()
+	defer fake.getAppPauseMutex.Unlock()
+	fake.GetAppPauseStub = nil
+	fake.getAppPauseReturns = struct {
+		result1 *models.AppPause
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetAppPauseReturnsOnCall(i int, result1 *models.AppPause, result2 error) {
+	fake.getAppPauseMutex.Lock()
+	defer fake.getAppPauseMutex.Unlock()
+	fake.GetAppPauseStub = nil
+	if fake.getAppPauseReturnsOnCall == nil {
+		fake.getAppPauseReturnsOnCall = make(map[int]struct {
+			result1 *models.AppPause
+			result2 error
+		})
+	}
+	fake.getAppPauseReturnsOnCall[i] = struct {
+		result1 *models.AppPause
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePolicyDB) GetAppPolicy(arg1 string) (*models.ScalingPolicy, error) {
 	fake.getAppPolicyMutex.Lock()
 	ret, specificReturn := fake.getAppPolicyReturnsOnCall[len(fake.getAppPolicyArgsForCall)]
@@ -339,7 +743,70 @@ func (fake *FakePolicyDB) GetAppPolicyReturnsOnCall(i int, result1 *models.Scali
 	}{result1, result2}
 }
 
-func (fake *FakePolicyDB) GetCustomMetricsCreds(arg1 string) (string, string, error) {
+func (fake *FakePolicyDB) GetAppPolicyJson(arg1 string) (*models.PolicyJson, error) {
+	fake.getAppPolicyJsonMutex.Lock()
+	ret, specificReturn := fake.getAppPolicyJsonReturnsOnCall[len(fake.getAppPolicyJsonArgsForCall)]
+	fake.getAppPolicyJsonArgsForCall = append(fake.getAppPolicyJsonArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetAppPolicyJson", []interface{}{arg1})
+	fake.getAppPolicyJsonMutex.Unlock()
+	if fake.GetAppPolicyJsonStub != nil {
+		return fake.GetAppPolicyJsonStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAppPolicyJsonReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) GetAppPolicyJsonCallCount() int {
+	fake.getAppPolicyJsonMutex.RLock()
+	defer fake.getAppPolicyJsonMutex.RUnlock()
+	return len(fake.getAppPolicyJsonArgsForCall)
+}
+
+func (fake *FakePolicyDB) GetAppPolicyJsonCalls(stub func(string) (*models.PolicyJson, error)) {
+	fake.getAppPolicyJsonMutex.Lock()
+	defer fake.getAppPolicyJsonMutex.Unlock()
+	fake.GetAppPolicyJsonStub = stub
+}
+
+func (fake *FakePolicyDB) GetAppPolicyJsonArgsForCall(i int) string {
+	fake.getAppPolicyJsonMutex.RLock()
+	defer fake.getAppPolicyJsonMutex.RUnlock()
+	argsForCall := fake.getAppPolicyJsonArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) GetAppPolicyJsonReturns(result1 *models.PolicyJson, result2 error) {
+	fake.getAppPolicyJsonMutex.Lock()
+	defer fake.getAppPolicyJsonMutex.Unlock()
+	fake.GetAppPolicyJsonStub = nil
+	fake.getAppPolicyJsonReturns = struct {
+		result1 *models.PolicyJson
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetAppPolicyJsonReturnsOnCall(i int, result1 *models.PolicyJson, result2 error) {
+	fake.getAppPolicyJsonMutex.Lock()
+	defer fake.getAppPolicyJsonMutex.Unlock()
+	fake.GetAppPolicyJsonStub = nil
+	if fake.getAppPolicyJsonReturnsOnCall == nil {
+		fake.getAppPolicyJsonReturnsOnCall = make(map[int]struct {
+			result1 *models.PolicyJson
+			result2 error
+		})
+	}
+	fake.getAppPolicyJsonReturnsOnCall[i] = struct {
+		result1 *models.PolicyJson
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetCustomMetricsCreds(arg1 string) ([]*models.CustomMetricCredentials, error) {
 	fake.getCustomMetricsCredsMutex.Lock()
 	ret, specificReturn := fake.getCustomMetricsCredsReturnsOnCall[len(fake.getCustomMetricsCredsArgsForCall)]
 	fake.getCustomMetricsCredsArgsForCall = append(fake.getCustomMetricsCredsArgsForCall, struct {
@@ -351,10 +818,10 @@ func (fake *FakePolicyDB) GetCustomMetricsCreds(arg1 string) (string, string, er
 		return fake.GetCustomMetricsCredsStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2, ret.result3
+		return ret.result1, ret.result2
 	}
 	fakeReturns := fake.getCustomMetricsCredsReturns
-	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakePolicyDB) GetCustomMetricsCredsCallCount() int {
@@ -363,7 +830,7 @@ func (fake *FakePolicyDB) GetCustomMetricsCredsCallCount() int {
 	return len(fake.getCustomMetricsCredsArgsForCall)
 }
 
-func (fake *FakePolicyDB) GetCustomMetricsCredsCalls(stub func(string) (string, string, error)) {
+func (fake *FakePolicyDB) GetCustomMetricsCredsCalls(stub func(string) ([]*models.CustomMetricCredentials, error)) {
 	fake.getCustomMetricsCredsMutex.Lock()
 	defer fake.getCustomMetricsCredsMutex.Unlock()
 	fake.GetCustomMetricsCredsStub = stub
@@ -376,33 +843,30 @@ func (fake *FakePolicyDB) GetCustomMetricsCredsArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
-func (fake *FakePolicyDB) GetCustomMetricsCredsReturns(result1 string, result2 string, result3 error) {
+func (fake *FakePolicyDB) GetCustomMetricsCredsReturns(result1 []*models.CustomMetricCredentials, result2 error) {
 	fake.getCustomMetricsCredsMutex.Lock()
 	defer fake.getCustomMetricsCredsMutex.Unlock()
 	fake.GetCustomMetricsCredsStub = nil
 	fake.getCustomMetricsCredsReturns = struct {
-		result1 string
-		result2 string
-		result3 error
-	}{result1, result2, result3}
+		result1 []*models.CustomMetricCredentials
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakePolicyDB) GetCustomMetricsCredsReturnsOnCall(i int, result1 string, result2 string, result3 error) {
+func (fake *FakePolicyDB) GetCustomMetricsCredsReturnsOnCall(i int, result1 []*models.CustomMetricCredentials, result2 error) {
 	fake.getCustomMetricsCredsMutex.Lock()
 	defer fake.getCustomMetricsCredsMutex.Unlock()
 	fake.GetCustomMetricsCredsStub = nil
 	if fake.getCustomMetricsCredsReturnsOnCall == nil {
 		fake.getCustomMetricsCredsReturnsOnCall = make(map[int]struct {
-			result1 string
-			result2 string
-			result3 error
+			result1 []*models.CustomMetricCredentials
+			result2 error
 		})
 	}
 	fake.getCustomMetricsCredsReturnsOnCall[i] = struct {
-		result1 string
-		result2 string
-		result3 error
-	}{result1, result2, result3}
+		result1 []*models.CustomMetricCredentials
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakePolicyDB) GetDBStatus() sql.DBStats {
@@ -457,6 +921,188 @@ func (fake *FakePolicyDB) GetDBStatusReturnsOnCall(i int, result1 sql.DBStats) {
 	}{result1}
 }
 
+func (fake *FakePolicyDB) GetPolicyVersion(arg1 string, arg2 int) (*models.PolicyVersion, error) {
+	fake.getPolicyVersionMutex.Lock()
+	ret, specificReturn := fake.getPolicyVersionReturnsOnCall[len(fake.getPolicyVersionArgsForCall)]
+	fake.getPolicyVersionArgsForCall = append(fake.getPolicyVersionArgsForCall, struct {
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("GetPolicyVersion", []interface{}{arg1, arg2})
+	fake.getPolicyVersionMutex.Unlock()
+	if fake.GetPolicyVersionStub != nil {
+		return fake.GetPolicyVersionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPolicyVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) GetPolicyVersionCallCount() int {
+	fake.getPolicyVersionMutex.RLock()
+	defer fake.getPolicyVersionMutex.RUnlock()
+	return len(fake.getPolicyVersionArgsForCall)
+}
+
+func (fake *FakePolicyDB) GetPolicyVersionCalls(stub func(string, int) (*models.PolicyVersion, error)) {
+	fake.getPolicyVersionMutex.Lock()
+	defer fake.getPolicyVersionMutex.Unlock()
+	fake.GetPolicyVersionStub = stub
+}
+
+func (fake *FakePolicyDB) GetPolicyVersionArgsForCall(i int) (string, int) {
+	fake.getPolicyVersionMutex.RLock()
+	defer fake.getPolicyVersionMutex.RUnlock()
+	argsForCall := fake.getPolicyVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePolicyDB) GetPolicyVersionReturns(result1 *models.PolicyVersion, result2 error) {
+	fake.getPolicyVersionMutex.Lock()
+	defer fake.getPolicyVersionMutex.Unlock()
+	fake.GetPolicyVersionStub = nil
+	fake.getPolicyVersionReturns = struct {
+		result1 *models.PolicyVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetPolicyVersionReturnsOnCall(i int, result1 *models.PolicyVersion, result2 error) {
+	fake.getPolicyVersionMutex.Lock()
+	defer fake.getPolicyVersionMutex.Unlock()
+	fake.GetPolicyVersionStub = nil
+	if fake.getPolicyVersionReturnsOnCall == nil {
+		fake.getPolicyVersionReturnsOnCall = make(map[int]struct {
+			result1 *models.PolicyVersion
+			result2 error
+		})
+	}
+	fake.getPolicyVersionReturnsOnCall[i] = struct {
+		result1 *models.PolicyVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetScalingGroup(arg1 string) (*models.ScalingGroup, error) {
+	fake.getScalingGroupMutex.Lock()
+	ret, specificReturn := fake.getScalingGroupReturnsOnCall[len(fake.getScalingGroupArgsForCall)]
+	fake.getScalingGroupArgsForCall = append(fake.getScalingGroupArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetScalingGroup", []interface{}{arg1})
+	fake.getScalingGroupMutex.Unlock()
+	if fake.GetScalingGroupStub != nil {
+		return fake.GetScalingGroupStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getScalingGroupReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) GetScalingGroupCallCount() int {
+	fake.getScalingGroupMutex.RLock()
+	defer fake.getScalingGroupMutex.RUnlock()
+	return len(fake.getScalingGroupArgsForCall)
+}
+
+func (fake *FakePolicyDB) GetScalingGroupCalls(stub func(string) (*models.ScalingGroup, error)) {
+	fake.getScalingGroupMutex.Lock()
+	defer fake.getScalingGroupMutex.Unlock()
+	fake.GetScalingGroupStub = stub
+}
+
+func (fake *FakePolicyDB) GetScalingGroupArgsForCall(i int) string {
+	fake.getScalingGroupMutex.RLock()
+	defer fake.getScalingGroupMutex.RUnlock()
+	argsForCall := fake.getScalingGroupArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) GetScalingGroupReturns(result1 *models.ScalingGroup, result2 error) {
+	fake.getScalingGroupMutex.Lock()
+	defer fake.getScalingGroupMutex.Unlock()
+	fake.GetScalingGroupStub = nil
+	fake.getScalingGroupReturns = struct {
+		result1 *models.ScalingGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) GetScalingGroupReturnsOnCall(i int, result1 *models.ScalingGroup, result2 error) {
+	fake.getScalingGroupMutex.Lock()
+	defer fake.getScalingGroupMutex.Unlock()
+	fake.GetScalingGroupStub = nil
+	if fake.getScalingGroupReturnsOnCall == nil {
+		fake.getScalingGroupReturnsOnCall = make(map[int]struct {
+			result1 *models.ScalingGroup
+			result2 error
+		})
+	}
+	fake.getScalingGroupReturnsOnCall[i] = struct {
+		result1 *models.ScalingGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) RetrieveAppPauses() (map[string]*models.AppPause, error) {
+	fake.retrieveAppPausesMutex.Lock()
+	ret, specificReturn := fake.retrieveAppPausesReturnsOnCall[len(fake.retrieveAppPausesArgsForCall)]
+	fake.retrieveAppPausesArgsForCall = append(fake.retrieveAppPausesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RetrieveAppPauses", []interface{}{})
+	fake.retrieveAppPausesMutex.Unlock()
+	if fake.RetrieveAppPausesStub != nil {
+		return fake.RetrieveAppPausesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.retrieveAppPausesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) RetrieveAppPausesCallCount() int {
+	fake.retrieveAppPausesMutex.RLock()
+	defer fake.retrieveAppPausesMutex.RUnlock()
+	return len(fake.retrieveAppPausesArgsForCall)
+}
+
+func (fake *FakePolicyDB) RetrieveAppPausesCalls(stub func() (map[string]*models.AppPause, error)) {
+	fake.retrieveAppPausesMutex.Lock()
+	defer fake.retrieveAppPausesMutex.Unlock()
+	fake.RetrieveAppPausesStub = stub
+}
+
+func (fake *FakePolicyDB) RetrieveAppPausesReturns(result1 map[string]*models.AppPause, result2 error) {
+	fake.retrieveAppPausesMutex.Lock()
+	defer fake.retrieveAppPausesMutex.Unlock()
+	fake.RetrieveAppPausesStub = nil
+	fake.retrieveAppPausesReturns = struct {
+		result1 map[string]*models.AppPause
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) RetrieveAppPausesReturnsOnCall(i int, result1 map[string]*models.AppPause, result2 error) {
+	fake.retrieveAppPausesMutex.Lock()
+	defer fake.retrieveAppPausesMutex.Unlock()
+	fake.RetrieveAppPausesStub = nil
+	if fake.retrieveAppPausesReturnsOnCall == nil {
+		fake.retrieveAppPausesReturnsOnCall = make(map[int]struct {
+			result1 map[string]*models.AppPause
+			result2 error
+		})
+	}
+	fake.retrieveAppPausesReturnsOnCall[i] = struct {
+		result1 map[string]*models.AppPause
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePolicyDB) RetrievePolicies() ([]*models.PolicyJson, error) {
 	fake.retrievePoliciesMutex.Lock()
 	ret, specificReturn := fake.retrievePoliciesReturnsOnCall[len(fake.retrievePoliciesArgsForCall)]
@@ -512,18 +1158,267 @@ func (fake *FakePolicyDB) RetrievePoliciesReturnsOnCall(i int, result1 []*models
 	}{result1, result2}
 }
 
-func (fake *FakePolicyDB) SaveAppPolicy(arg1 string, arg2 string, arg3 string) error {
+func (fake *FakePolicyDB) RetrievePolicyVersions(arg1 string) ([]*models.PolicyVersion, error) {
+	fake.retrievePolicyVersionsMutex.Lock()
+	ret, specificReturn := fake.retrievePolicyVersionsReturnsOnCall[len(fake.retrievePolicyVersionsArgsForCall)]
+	fake.retrievePolicyVersionsArgsForCall = append(fake.retrievePolicyVersionsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("RetrievePolicyVersions", []interface{}{arg1})
+	fake.retrievePolicyVersionsMutex.Unlock()
+	if fake.RetrievePolicyVersionsStub != nil {
+		return fake.RetrievePolicyVersionsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.retrievePolicyVersionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePolicyDB) RetrievePolicyVersionsCallCount() int {
+	fake.retrievePolicyVersionsMutex.RLock()
+	defer fake.retrievePolicyVersionsMutex.RUnlock()
+	return len(fake.retrievePolicyVersionsArgsForCall)
+}
+
+func (fake *FakePolicyDB) RetrievePolicyVersionsCalls(stub func(string) ([]*models.PolicyVersion, error)) {
+	fake.retrievePolicyVersionsMutex.Lock()
+	defer fake.retrievePolicyVersionsMutex.Unlock()
+	fake.RetrievePolicyVersionsStub = stub
+}
+
+func (fake *FakePolicyDB) RetrievePolicyVersionsArgsForCall(i int) string {
+	fake.retrievePolicyVersionsMutex.RLock()
+	defer fake.retrievePolicyVersionsMutex.RUnlock()
+	argsForCall := fake.retrievePolicyVersionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) RetrievePolicyVersionsReturns(result1 []*models.PolicyVersion, result2 error) {
+	fake.retrievePolicyVersionsMutex.Lock()
+	defer fake.retrievePolicyVersionsMutex.Unlock()
+	fake.RetrievePolicyVersionsStub = nil
+	fake.retrievePolicyVersionsReturns = struct {
+		result1 []*models.PolicyVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) RetrievePolicyVersionsReturnsOnCall(i int, result1 []*models.PolicyVersion, result2 error) {
+	fake.retrievePolicyVersionsMutex.Lock()
+	defer fake.retrievePolicyVersionsMutex.Unlock()
+	fake.RetrievePolicyVersionsStub = nil
+	if fake.retrievePolicyVersionsReturnsOnCall == nil {
+		fake.retrievePolicyVersionsReturnsOnCall = make(map[int]struct {
+			result1 []*models.PolicyVersion
+			result2 error
+		})
+	}
+	fake.retrievePolicyVersionsReturnsOnCall[i] = struct {
+		result1 []*models.PolicyVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyDB) RevertAppPolicy(arg1 string, arg2 string, arg3 *models.PolicyJson) error {
+	fake.revertAppPolicyMutex.Lock()
+	ret, specificReturn := fake.revertAppPolicyReturnsOnCall[len(fake.revertAppPolicyArgsForCall)]
+	fake.revertAppPolicyArgsForCall = append(fake.revertAppPolicyArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *models.PolicyJson
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("RevertAppPolicy", []interface{}{arg1, arg2, arg3})
+	fake.revertAppPolicyMutex.Unlock()
+	if fake.RevertAppPolicyStub != nil {
+		return fake.RevertAppPolicyStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.revertAppPolicyReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) RevertAppPolicyCallCount() int {
+	fake.revertAppPolicyMutex.RLock()
+	defer fake.revertAppPolicyMutex.RUnlock()
+	return len(fake.revertAppPolicyArgsForCall)
+}
+
+func (fake *FakePolicyDB) RevertAppPolicyCalls(stub func(string, string, *models.PolicyJson) error) {
+	fake.revertAppPolicyMutex.Lock()
+	defer fake.revertAppPolicyMutex.Unlock()
+	fake.RevertAppPolicyStub = stub
+}
+
+func (fake *FakePolicyDB) RevertAppPolicyArgsForCall(i int) (string, string, *models.PolicyJson) {
+	fake.revertAppPolicyMutex.RLock()
+	defer fake.revertAppPolicyMutex.RUnlock()
+	argsForCall := fake.revertAppPolicyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePolicyDB) RevertAppPolicyReturns(result1 error) {
+	fake.revertAppPolicyMutex.Lock()
+	defer fake.revertAppPolicyMutex.Unlock()
+	fake.RevertAppPolicyStub = nil
+	fake.revertAppPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) RevertAppPolicyReturnsOnCall(i int, result1 error) {
+	fake.revertAppPolicyMutex.Lock()
+	defer fake.revertAppPolicyMutex.Unlock()
+	fake.RevertAppPolicyStub = nil
+	if fake.revertAppPolicyReturnsOnCall == nil {
+		fake.revertAppPolicyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revertAppPolicyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCreds(arg1 string, arg2 string, arg3 string, arg4 int64) error {
+	fake.rotateCustomMetricsCredsMutex.Lock()
+	ret, specificReturn := fake.rotateCustomMetricsCredsReturnsOnCall[len(fake.rotateCustomMetricsCredsArgsForCall)]
+	fake.rotateCustomMetricsCredsArgsForCall = append(fake.rotateCustomMetricsCredsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 int64
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("RotateCustomMetricsCreds", []interface{}{arg1, arg2, arg3, arg4})
+	fake.rotateCustomMetricsCredsMutex.Unlock()
+	if fake.RotateCustomMetricsCredsStub != nil {
+		return fake.RotateCustomMetricsCredsStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.rotateCustomMetricsCredsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCredsCallCount() int {
+	fake.rotateCustomMetricsCredsMutex.RLock()
+	defer fake.rotateCustomMetricsCredsMutex.RUnlock()
+	return len(fake.rotateCustomMetricsCredsArgsForCall)
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCredsCalls(stub func(string, string, string, int64) error) {
+	fake.rotateCustomMetricsCredsMutex.Lock()
+	defer fake.rotateCustomMetricsCredsMutex.Unlock()
+	fake.RotateCustomMetricsCredsStub = stub
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCredsArgsForCall(i int) (string, string, string, int64) {
+	fake.rotateCustomMetricsCredsMutex.RLock()
+	defer fake.rotateCustomMetricsCredsMutex.RUnlock()
+	argsForCall := fake.rotateCustomMetricsCredsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCredsReturns(result1 error) {
+	fake.rotateCustomMetricsCredsMutex.Lock()
+	defer fake.rotateCustomMetricsCredsMutex.Unlock()
+	fake.RotateCustomMetricsCredsStub = nil
+	fake.rotateCustomMetricsCredsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) RotateCustomMetricsCredsReturnsOnCall(i int, result1 error) {
+	fake.rotateCustomMetricsCredsMutex.Lock()
+	defer fake.rotateCustomMetricsCredsMutex.Unlock()
+	fake.RotateCustomMetricsCredsStub = nil
+	if fake.rotateCustomMetricsCredsReturnsOnCall == nil {
+		fake.rotateCustomMetricsCredsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rotateCustomMetricsCredsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) SaveAppPause(arg1 *models.AppPause) error {
+	fake.saveAppPauseMutex.Lock()
+	ret, specificReturn := fake.saveAppPauseReturnsOnCall[len(fake.saveAppPauseArgsForCall)]
+	fake.saveAppPauseArgsForCall = append(fake.saveAppPauseArgsForCall, struct {
+		arg1 *models.AppPause
+	}{arg1})
+	fake.recordInvocation("SaveAppPause", []interface{}{arg1})
+	fake.saveAppPauseMutex.Unlock()
+	if fake.SaveAppPauseStub != nil {
+		return fake.SaveAppPauseStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.saveAppPauseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) SaveAppPauseCallCount() int {
+	fake.saveAppPauseMutex.RLock()
+	defer fake.saveAppPauseMutex.RUnlock()
+	return len(fake.saveAppPauseArgsForCall)
+}
+
+func (fake *FakePolicyDB) SaveAppPauseCalls(stub func(*models.AppPause) error) {
+	fake.saveAppPauseMutex.Lock()
+	defer fake.saveAppPauseMutex.Unlock()
+	fake.SaveAppPauseStub = stub
+}
+
+func (fake *FakePolicyDB) SaveAppPauseArgsForCall(i int) *models.AppPause {
+	fake.saveAppPauseMutex.RLock()
+	defer fake.saveAppPauseMutex.RUnlock()
+	argsForCall := fake.saveAppPauseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) SaveAppPauseReturns(result1 error) {
+	fake.saveAppPauseMutex.Lock()
+	defer fake.saveAppPauseMutex.Unlock()
+	fake.SaveAppPauseStub = nil
+	fake.saveAppPauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) SaveAppPauseReturnsOnCall(i int, result1 error) {
+	fake.saveAppPauseMutex.Lock()
+	defer fake.saveAppPauseMutex.Unlock()
+	fake.SaveAppPauseStub = nil
+	if fake.saveAppPauseReturnsOnCall == nil {
+		fake.saveAppPauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveAppPauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) SaveAppPolicy(arg1 string, arg2 string, arg3 string, arg4 string) error {
 	fake.saveAppPolicyMutex.Lock()
 	ret, specificReturn := fake.saveAppPolicyReturnsOnCall[len(fake.saveAppPolicyArgsForCall)]
 	fake.saveAppPolicyArgsForCall = append(fake.saveAppPolicyArgsForCall, struct {
 		arg1 string
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("SaveAppPolicy", []interface{}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("SaveAppPolicy", []interface{}{arg1, arg2, arg3, arg4})
 	fake.saveAppPolicyMutex.Unlock()
 	if fake.SaveAppPolicyStub != nil {
-		return fake.SaveAppPolicyStub(arg1, arg2, arg3)
+		return fake.SaveAppPolicyStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -538,17 +1433,17 @@ func (fake *FakePolicyDB) SaveAppPolicyCallCount() int {
 	return len(fake.saveAppPolicyArgsForCall)
 }
 
-func (fake *FakePolicyDB) SaveAppPolicyCalls(stub func(string, string, string) error) {
+func (fake *FakePolicyDB) SaveAppPolicyCalls(stub func(string, string, string, string) error) {
 	fake.saveAppPolicyMutex.Lock()
 	defer fake.saveAppPolicyMutex.Unlock()
 	fake.SaveAppPolicyStub = stub
 }
 
-func (fake *FakePolicyDB) SaveAppPolicyArgsForCall(i int) (string, string, string) {
+func (fake *FakePolicyDB) SaveAppPolicyArgsForCall(i int) (string, string, string, string) {
 	fake.saveAppPolicyMutex.RLock()
 	defer fake.saveAppPolicyMutex.RUnlock()
 	argsForCall := fake.saveAppPolicyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakePolicyDB) SaveAppPolicyReturns(result1 error) {
@@ -574,25 +1469,111 @@ func (fake *FakePolicyDB) SaveAppPolicyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePolicyDB) SaveScalingGroup(arg1 *models.ScalingGroup) error {
+	fake.saveScalingGroupMutex.Lock()
+	ret, specificReturn := fake.saveScalingGroupReturnsOnCall[len(fake.saveScalingGroupArgsForCall)]
+	fake.saveScalingGroupArgsForCall = append(fake.saveScalingGroupArgsForCall, struct {
+		arg1 *models.ScalingGroup
+	}{arg1})
+	fake.recordInvocation("SaveScalingGroup", []interface{}{arg1})
+	fake.saveScalingGroupMutex.Unlock()
+	if fake.SaveScalingGroupStub != nil {
+		return fake.SaveScalingGroupStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.saveScalingGroupReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePolicyDB) SaveScalingGroupCallCount() int {
+	fake.saveScalingGroupMutex.RLock()
+	defer fake.saveScalingGroupMutex.RUnlock()
+	return len(fake.saveScalingGroupArgsForCall)
+}
+
+func (fake *FakePolicyDB) SaveScalingGroupCalls(stub func(*models.ScalingGroup) error) {
+	fake.saveScalingGroupMutex.Lock()
+	defer fake.saveScalingGroupMutex.Unlock()
+	fake.SaveScalingGroupStub = stub
+}
+
+func (fake *FakePolicyDB) SaveScalingGroupArgsForCall(i int) *models.ScalingGroup {
+	fake.saveScalingGroupMutex.RLock()
+	defer fake.saveScalingGroupMutex.RUnlock()
+	argsForCall := fake.saveScalingGroupArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePolicyDB) SaveScalingGroupReturns(result1 error) {
+	fake.saveScalingGroupMutex.Lock()
+	defer fake.saveScalingGroupMutex.Unlock()
+	fake.SaveScalingGroupStub = nil
+	fake.saveScalingGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyDB) SaveScalingGroupReturnsOnCall(i int, result1 error) {
+	fake.saveScalingGroupMutex.Lock()
+	defer fake.saveScalingGroupMutex.Unlock()
+	fake.SaveScalingGroupStub = nil
+	if fake.saveScalingGroupReturnsOnCall == nil {
+		fake.saveScalingGroupReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveScalingGroupReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePolicyDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
+	fake.deleteAppPauseMutex.RLock()
+	defer fake.deleteAppPauseMutex.RUnlock()
+	fake.deleteExpiredAppPauseMutex.RLock()
+	defer fake.deleteExpiredAppPauseMutex.RUnlock()
 	fake.deletePolicyMutex.RLock()
 	defer fake.deletePolicyMutex.RUnlock()
+	fake.deleteScalingGroupMutex.RLock()
+	defer fake.deleteScalingGroupMutex.RUnlock()
 	fake.getAppIdsMutex.RLock()
 	defer fake.getAppIdsMutex.RUnlock()
+	fake.getAppPauseMutex.RLock()
+	defer fake.getAppPauseMutex.RUnlock()
 	fake.getAppPolicyMutex.RLock()
 	defer fake.getAppPolicyMutex.RUnlock()
+	fake.getAppPolicyJsonMutex.RLock()
+	defer fake.getAppPolicyJsonMutex.RUnlock()
 	fake.getCustomMetricsCredsMutex.RLock()
 	defer fake.getCustomMetricsCredsMutex.RUnlock()
 	fake.getDBStatusMutex.RLock()
 	defer fake.getDBStatusMutex.RUnlock()
+	fake.getPolicyVersionMutex.RLock()
+	defer fake.getPolicyVersionMutex.RUnlock()
+	fake.getScalingGroupMutex.RLock()
+	defer fake.getScalingGroupMutex.RUnlock()
+	fake.retrieveAppPausesMutex.RLock()
+	defer fake.retrieveAppPausesMutex.RUnlock()
 	fake.retrievePoliciesMutex.RLock()
 	defer fake.retrievePoliciesMutex.RUnlock()
+	fake.retrievePolicyVersionsMutex.RLock()
+	defer fake.retrievePolicyVersionsMutex.RUnlock()
+	fake.revertAppPolicyMutex.RLock()
+	defer fake.revertAppPolicyMutex.RUnlock()
+	fake.rotateCustomMetricsCredsMutex.RLock()
+	defer fake.rotateCustomMetricsCredsMutex.RUnlock()
+	fake.saveAppPauseMutex.RLock()
+	defer fake.saveAppPauseMutex.RUnlock()
 	fake.saveAppPolicyMutex.RLock()
 	defer fake.saveAppPolicyMutex.RUnlock()
+	fake.saveScalingGroupMutex.RLock()
+	defer fake.saveScalingGroupMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,64 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"autoscaler/healthendpoint"
+	"sync"
+)
+
+type FakeRateLimitStatusProvider struct {
+	GetRateLimitStatusStub        func() *healthendpoint.RateLimitStatus
+	getRateLimitStatusMutex       sync.RWMutex
+	getRateLimitStatusArgsForCall []struct{}
+	getRateLimitStatusReturns     struct {
+		result1 *healthendpoint.RateLimitStatus
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRateLimitStatusProvider) GetRateLimitStatus() *healthendpoint.RateLimitStatus {
+	fake.getRateLimitStatusMutex.Lock()
+	fake.getRateLimitStatusArgsForCall = append(fake.getRateLimitStatusArgsForCall, struct{}{})
+	fake.recordInvocation("GetRateLimitStatus", []interface{}{})
+	fake.getRateLimitStatusMutex.Unlock()
+	if fake.GetRateLimitStatusStub != nil {
+		return fake.GetRateLimitStatusStub()
+	}
+	return fake.getRateLimitStatusReturns.result1
+}
+
+func (fake *FakeRateLimitStatusProvider) GetRateLimitStatusCallCount() int {
+	fake.getRateLimitStatusMutex.RLock()
+	defer fake.getRateLimitStatusMutex.RUnlock()
+	return len(fake.getRateLimitStatusArgsForCall)
+}
+
+func (fake *FakeRateLimitStatusProvider) GetRateLimitStatusReturns(result1 *healthendpoint.RateLimitStatus) {
+	fake.GetRateLimitStatusStub = nil
+	fake.getRateLimitStatusReturns = struct {
+		result1 *healthendpoint.RateLimitStatus
+	}{result1}
+}
+
+func (fake *FakeRateLimitStatusProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getRateLimitStatusMutex.RLock()
+	defer fake.getRateLimitStatusMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeRateLimitStatusProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ healthendpoint.RateLimitStatusProvider = new(FakeRateLimitStatusProvider)
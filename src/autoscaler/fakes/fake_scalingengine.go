@@ -8,6 +8,18 @@ import (
 )
 
 type FakeScalingEngine struct {
+	ResumeAppStub        func(string, string) error
+	resumeAppMutex       sync.RWMutex
+	resumeAppArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	resumeAppReturns struct {
+		result1 error
+	}
+	resumeAppReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ScaleStub        func(appId string, trigger *models.Trigger) (*models.AppScalingResult, error)
 	scaleMutex       sync.RWMutex
 	scaleArgsForCall []struct {
@@ -22,11 +34,11 @@ type FakeScalingEngine struct {
 		result1 *models.AppScalingResult
 		result2 error
 	}
-	ComputeNewInstancesStub        func(currentInstances int, adjustment string) (int, error)
+	ComputeNewInstancesStub        func(currentInstances int, trigger *models.Trigger) (int, error)
 	computeNewInstancesMutex       sync.RWMutex
 	computeNewInstancesArgsForCall []struct {
 		currentInstances int
-		adjustment       string
+		trigger          *models.Trigger
 	}
 	computeNewInstancesReturns struct {
 		result1 int
@@ -48,6 +60,29 @@ type FakeScalingEngine struct {
 	setActiveScheduleReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExpireAppPauseStub        func(string) error
+	expireAppPauseMutex       sync.RWMutex
+	expireAppPauseArgsForCall []struct {
+		arg1 string
+	}
+	expireAppPauseReturns struct {
+		result1 error
+	}
+	expireAppPauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PauseAppStub        func(string, *models.AppPause) error
+	pauseAppMutex       sync.RWMutex
+	pauseAppArgsForCall []struct {
+		arg1 string
+		arg2 *models.AppPause
+	}
+	pauseAppReturns struct {
+		result1 error
+	}
+	pauseAppReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RemoveActiveScheduleStub        func(appId string, scheduleId string) error
 	removeActiveScheduleMutex       sync.RWMutex
 	removeActiveScheduleArgsForCall []struct {
@@ -64,6 +99,67 @@ type FakeScalingEngine struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeScalingEngine) ResumeApp(arg1 string, arg2 string) error {
+	fake.resumeAppMutex.Lock()
+	ret, specificReturn := fake.resumeAppReturnsOnCall[len(fake.resumeAppArgsForCall)]
+	fake.resumeAppArgsForCall = append(fake.resumeAppArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ResumeApp", []interface{}{arg1, arg2})
+	fake.resumeAppMutex.Unlock()
+	if fake.ResumeAppStub != nil {
+		return fake.ResumeAppStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.resumeAppReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeScalingEngine) ResumeAppCallCount() int {
+	fake.resumeAppMutex.RLock()
+	defer fake.resumeAppMutex.RUnlock()
+	return len(fake.resumeAppArgsForCall)
+}
+
+func (fake *FakeScalingEngine) ResumeAppCalls(stub func(string, string) error) {
+	fake.resumeAppMutex.Lock()
+	defer fake.resumeAppMutex.Unlock()
+	fake.ResumeAppStub = stub
+}
+
+func (fake *FakeScalingEngine) ResumeAppArgsForCall(i int) (string, string) {
+	fake.resumeAppMutex.RLock()
+	defer fake.resumeAppMutex.RUnlock()
+	argsForCall := fake.resumeAppArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeScalingEngine) ResumeAppReturns(result1 error) {
+	fake.resumeAppMutex.Lock()
+	defer fake.resumeAppMutex.Unlock()
+	fake.ResumeAppStub = nil
+	fake.resumeAppReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngine) ResumeAppReturnsOnCall(i int, result1 error) {
+	fake.resumeAppMutex.Lock()
+	defer fake.resumeAppMutex.Unlock()
+	fake.ResumeAppStub = nil
+	if fake.resumeAppReturnsOnCall == nil {
+		fake.resumeAppReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resumeAppReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeScalingEngine) Scale(appId string, trigger *models.Trigger) (*models.AppScalingResult, error) {
 	fake.scaleMutex.Lock()
 	ret, specificReturn := fake.scaleReturnsOnCall[len(fake.scaleArgsForCall)]
@@ -116,17 +212,17 @@ func (fake *FakeScalingEngine) ScaleReturnsOnCall(i int, result1 *models.AppScal
 	}{result1, result2}
 }
 
-func (fake *FakeScalingEngine) ComputeNewInstances(currentInstances int, adjustment string) (int, error) {
+func (fake *FakeScalingEngine) ComputeNewInstances(currentInstances int, trigger *models.Trigger) (int, error) {
 	fake.computeNewInstancesMutex.Lock()
 	ret, specificReturn := fake.computeNewInstancesReturnsOnCall[len(fake.computeNewInstancesArgsForCall)]
 	fake.computeNewInstancesArgsForCall = append(fake.computeNewInstancesArgsForCall, struct {
 		currentInstances int
-		adjustment       string
-	}{currentInstances, adjustment})
-	fake.recordInvocation("ComputeNewInstances", []interface{}{currentInstances, adjustment})
+		trigger          *models.Trigger
+	}{currentInstances, trigger})
+	fake.recordInvocation("ComputeNewInstances", []interface{}{currentInstances, trigger})
 	fake.computeNewInstancesMutex.Unlock()
 	if fake.ComputeNewInstancesStub != nil {
-		return fake.ComputeNewInstancesStub(currentInstances, adjustment)
+		return fake.ComputeNewInstancesStub(currentInstances, trigger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -140,10 +236,10 @@ func (fake *FakeScalingEngine) ComputeNewInstancesCallCount() int {
 	return len(fake.computeNewInstancesArgsForCall)
 }
 
-func (fake *FakeScalingEngine) ComputeNewInstancesArgsForCall(i int) (int, string) {
+func (fake *FakeScalingEngine) ComputeNewInstancesArgsForCall(i int) (int, *models.Trigger) {
 	fake.computeNewInstancesMutex.RLock()
 	defer fake.computeNewInstancesMutex.RUnlock()
-	return fake.computeNewInstancesArgsForCall[i].currentInstances, fake.computeNewInstancesArgsForCall[i].adjustment
+	return fake.computeNewInstancesArgsForCall[i].currentInstances, fake.computeNewInstancesArgsForCall[i].trigger
 }
 
 func (fake *FakeScalingEngine) ComputeNewInstancesReturns(result1 int, result2 error) {
@@ -217,6 +313,127 @@ func (fake *FakeScalingEngine) SetActiveScheduleReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
+func (fake *FakeScalingEngine) ExpireAppPause(arg1 string) error {
+	fake.expireAppPauseMutex.Lock()
+	ret, specificReturn := fake.expireAppPauseReturnsOnCall[len(fake.expireAppPauseArgsForCall)]
+	fake.expireAppPauseArgsForCall = append(fake.expireAppPauseArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ExpireAppPause", []interface{}{arg1})
+	fake.expireAppPauseMutex.Unlock()
+	if fake.ExpireAppPauseStub != nil {
+		return fake.ExpireAppPauseStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.expireAppPauseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeScalingEngine) ExpireAppPauseCallCount() int {
+	fake.expireAppPauseMutex.RLock()
+	defer fake.expireAppPauseMutex.RUnlock()
+	return len(fake.expireAppPauseArgsForCall)
+}
+
+func (fake *FakeScalingEngine) ExpireAppPauseCalls(stub func(string) error) {
+	fake.expireAppPauseMutex.Lock()
+	defer fake.expireAppPauseMutex.Unlock()
+	fake.ExpireAppPauseStub = stub
+}
+
+func (fake *FakeScalingEngine) ExpireAppPauseArgsForCall(i int) string {
+	fake.expireAppPauseMutex.RLock()
+	defer fake.expireAppPauseMutex.RUnlock()
+	argsForCall := fake.expireAppPauseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScalingEngine) ExpireAppPauseReturns(result1 error) {
+	fake.expireAppPauseMutex.Lock()
+	defer fake.expireAppPauseMutex.Unlock()
+	fake.ExpireAppPauseStub = nil
+	fake.expireAppPauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngine) ExpireAppPauseReturnsOnCall(i int, result1 error) {
+	fake.expireAppPauseMutex.Lock()
+	defer fake.expireAppPauseMutex.Unlock()
+	fake.ExpireAppPauseStub = nil
+	if fake.expireAppPauseReturnsOnCall == nil {
+		fake.expireAppPauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.expireAppPauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngine) PauseApp(arg1 string, arg2 *models.AppPause) error {
+	fake.pauseAppMutex.Lock()
+	ret, specificReturn := fake.pauseAppReturnsOnCall[len(fake.pauseAppArgsForCall)]
+	fake.pauseAppArgsForCall = append(fake.pauseAppArgsForCall, struct {
+		arg1 string
+		arg2 *models.AppPause
+	}{arg1, arg2})
+	fake.recordInvocation("PauseApp", []interface{}{arg1, arg2})
+	fake.pauseAppMutex.Unlock()
+	if fake.PauseAppStub != nil {
+		return fake.PauseAppStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.pauseAppReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeScalingEngine) PauseAppCallCount() int {
+	fake.pauseAppMutex.RLock()
+	defer fake.pauseAppMutex.RUnlock()
+	return len(fake.pauseAppArgsForCall)
+}
+
+func (fake *FakeScalingEngine) PauseAppCalls(stub func(string, *models.AppPause) error) {
+	fake.pauseAppMutex.Lock()
+	defer fake.pauseAppMutex.Unlock()
+	fake.PauseAppStub = stub
+}
+
+func (fake *FakeScalingEngine) PauseAppArgsForCall(i int) (string, *models.AppPause) {
+	fake.pauseAppMutex.RLock()
+	defer fake.pauseAppMutex.RUnlock()
+	argsForCall := fake.pauseAppArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeScalingEngine) PauseAppReturns(result1 error) {
+	fake.pauseAppMutex.Lock()
+	defer fake.pauseAppMutex.Unlock()
+	fake.PauseAppStub = nil
+	fake.pauseAppReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngine) PauseAppReturnsOnCall(i int, result1 error) {
+	fake.pauseAppMutex.Lock()
+	defer fake.pauseAppMutex.Unlock()
+	fake.PauseAppStub = nil
+	if fake.pauseAppReturnsOnCall == nil {
+		fake.pauseAppReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseAppReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeScalingEngine) RemoveActiveSchedule(appId string, scheduleId string) error {
 	fake.removeActiveScheduleMutex.Lock()
 	ret, specificReturn := fake.removeActiveScheduleReturnsOnCall[len(fake.removeActiveScheduleArgsForCall)]
@@ -269,12 +486,18 @@ func (fake *FakeScalingEngine) RemoveActiveScheduleReturnsOnCall(i int, result1
 func (fake *FakeScalingEngine) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.resumeAppMutex.RLock()
+	defer fake.resumeAppMutex.RUnlock()
 	fake.scaleMutex.RLock()
 	defer fake.scaleMutex.RUnlock()
 	fake.computeNewInstancesMutex.RLock()
 	defer fake.computeNewInstancesMutex.RUnlock()
 	fake.setActiveScheduleMutex.RLock()
 	defer fake.setActiveScheduleMutex.RUnlock()
+	fake.expireAppPauseMutex.RLock()
+	defer fake.expireAppPauseMutex.RUnlock()
+	fake.pauseAppMutex.RLock()
+	defer fake.pauseAppMutex.RUnlock()
 	fake.removeActiveScheduleMutex.RLock()
 	defer fake.removeActiveScheduleMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
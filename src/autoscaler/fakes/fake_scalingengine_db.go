@@ -36,6 +36,54 @@ type FakeScalingEngineDB struct {
 		result1 []*models.AppScalingHistory
 		result2 error
 	}
+	SaveScalingExplanationStub        func(history *models.AppScalingHistory, explanation *models.ScalingExplanation) error
+	saveScalingExplanationMutex       sync.RWMutex
+	saveScalingExplanationArgsForCall []struct {
+		history     *models.AppScalingHistory
+		explanation *models.ScalingExplanation
+	}
+	saveScalingExplanationReturns struct {
+		result1 error
+	}
+	SaveScalingHistoryWithWebhookDeliveriesStub        func(history *models.AppScalingHistory, deliveries []*models.WebhookDelivery) error
+	saveScalingHistoryWithWebhookDeliveriesMutex       sync.RWMutex
+	saveScalingHistoryWithWebhookDeliveriesArgsForCall []struct {
+		history    *models.AppScalingHistory
+		deliveries []*models.WebhookDelivery
+	}
+	saveScalingHistoryWithWebhookDeliveriesReturns struct {
+		result1 error
+	}
+	RetrieveScalingExplanationStub        func(appId string, historyId int64) (*models.ScalingExplanation, error)
+	retrieveScalingExplanationMutex       sync.RWMutex
+	retrieveScalingExplanationArgsForCall []struct {
+		appId     string
+		historyId int64
+	}
+	retrieveScalingExplanationReturns struct {
+		result1 *models.ScalingExplanation
+		result2 error
+	}
+	SaveScalingRecommendationStub        func(appId string, timestamp int64, instances int) error
+	saveScalingRecommendationMutex       sync.RWMutex
+	saveScalingRecommendationArgsForCall []struct {
+		appId     string
+		timestamp int64
+		instances int
+	}
+	saveScalingRecommendationReturns struct {
+		result1 error
+	}
+	GetMaxScalingRecommendationStub        func(appId string, start int64) (int, error)
+	getMaxScalingRecommendationMutex       sync.RWMutex
+	getMaxScalingRecommendationArgsForCall []struct {
+		appId string
+		start int64
+	}
+	getMaxScalingRecommendationReturns struct {
+		result1 int
+		result2 error
+	}
 	PruneScalingHistoriesStub        func(before int64) error
 	pruneScalingHistoriesMutex       sync.RWMutex
 	pruneScalingHistoriesArgsForCall []struct {
@@ -44,19 +92,23 @@ type FakeScalingEngineDB struct {
 	pruneScalingHistoriesReturns struct {
 		result1 error
 	}
-	UpdateScalingCooldownExpireTimeStub        func(appId string, expireAt int64) error
+	UpdateScalingCooldownExpireTimeStub        func(appId string, direction string, ruleKey string, expireAt int64) error
 	updateScalingCooldownExpireTimeMutex       sync.RWMutex
 	updateScalingCooldownExpireTimeArgsForCall []struct {
-		appId    string
-		expireAt int64
+		appId     string
+		direction string
+		ruleKey   string
+		expireAt  int64
 	}
 	updateScalingCooldownExpireTimeReturns struct {
 		result1 error
 	}
-	CanScaleAppStub        func(appId string) (bool, int64, error)
+	CanScaleAppStub        func(appId string, direction string, ruleKey string) (bool, int64, error)
 	canScaleAppMutex       sync.RWMutex
 	canScaleAppArgsForCall []struct {
-		appId string
+		appId     string
+		direction string
+		ruleKey   string
 	}
 	canScaleAppReturns struct {
 		result1 bool
@@ -96,6 +148,33 @@ type FakeScalingEngineDB struct {
 	removeActiveScheduleReturns struct {
 		result1 error
 	}
+	ClaimWebhookDeliveriesStub        func(now int64, leaseUntil int64, limit int) ([]*models.WebhookDelivery, error)
+	claimWebhookDeliveriesMutex       sync.RWMutex
+	claimWebhookDeliveriesArgsForCall []struct {
+		now        int64
+		leaseUntil int64
+		limit      int
+	}
+	claimWebhookDeliveriesReturns struct {
+		result1 []*models.WebhookDelivery
+		result2 error
+	}
+	UpdateWebhookDeliveryStub        func(delivery *models.WebhookDelivery) error
+	updateWebhookDeliveryMutex       sync.RWMutex
+	updateWebhookDeliveryArgsForCall []struct {
+		delivery *models.WebhookDelivery
+	}
+	updateWebhookDeliveryReturns struct {
+		result1 error
+	}
+	DeleteWebhookDeliveryStub        func(id int64) error
+	deleteWebhookDeliveryMutex       sync.RWMutex
+	deleteWebhookDeliveryArgsForCall []struct {
+		id int64
+	}
+	deleteWebhookDeliveryReturns struct {
+		result1 error
+	}
 	CloseStub        func() error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct{}
@@ -199,6 +278,179 @@ func (fake *FakeScalingEngineDB) RetrieveScalingHistoriesReturns(result1 []*mode
 	}{result1, result2}
 }
 
+func (fake *FakeScalingEngineDB) SaveScalingExplanation(history *models.AppScalingHistory, explanation *models.ScalingExplanation) error {
+	fake.saveScalingExplanationMutex.Lock()
+	fake.saveScalingExplanationArgsForCall = append(fake.saveScalingExplanationArgsForCall, struct {
+		history     *models.AppScalingHistory
+		explanation *models.ScalingExplanation
+	}{history, explanation})
+	fake.recordInvocation("SaveScalingExplanation", []interface{}{history, explanation})
+	fake.saveScalingExplanationMutex.Unlock()
+	if fake.SaveScalingExplanationStub != nil {
+		return fake.SaveScalingExplanationStub(history, explanation)
+	}
+	return fake.saveScalingExplanationReturns.result1
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingExplanationCallCount() int {
+	fake.saveScalingExplanationMutex.RLock()
+	defer fake.saveScalingExplanationMutex.RUnlock()
+	return len(fake.saveScalingExplanationArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingExplanationArgsForCall(i int) (*models.AppScalingHistory, *models.ScalingExplanation) {
+	fake.saveScalingExplanationMutex.RLock()
+	defer fake.saveScalingExplanationMutex.RUnlock()
+	return fake.saveScalingExplanationArgsForCall[i].history, fake.saveScalingExplanationArgsForCall[i].explanation
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingExplanationReturns(result1 error) {
+	fake.SaveScalingExplanationStub = nil
+	fake.saveScalingExplanationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingHistoryWithWebhookDeliveries(history *models.AppScalingHistory, deliveries []*models.WebhookDelivery) error {
+	var deliveriesCopy []*models.WebhookDelivery
+	if deliveries != nil {
+		deliveriesCopy = make([]*models.WebhookDelivery, len(deliveries))
+		copy(deliveriesCopy, deliveries)
+	}
+	fake.saveScalingHistoryWithWebhookDeliveriesMutex.Lock()
+	fake.saveScalingHistoryWithWebhookDeliveriesArgsForCall = append(fake.saveScalingHistoryWithWebhookDeliveriesArgsForCall, struct {
+		history    *models.AppScalingHistory
+		deliveries []*models.WebhookDelivery
+	}{history, deliveriesCopy})
+	fake.recordInvocation("SaveScalingHistoryWithWebhookDeliveries", []interface{}{history, deliveriesCopy})
+	fake.saveScalingHistoryWithWebhookDeliveriesMutex.Unlock()
+	if fake.SaveScalingHistoryWithWebhookDeliveriesStub != nil {
+		return fake.SaveScalingHistoryWithWebhookDeliveriesStub(history, deliveries)
+	}
+	return fake.saveScalingHistoryWithWebhookDeliveriesReturns.result1
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingHistoryWithWebhookDeliveriesCallCount() int {
+	fake.saveScalingHistoryWithWebhookDeliveriesMutex.RLock()
+	defer fake.saveScalingHistoryWithWebhookDeliveriesMutex.RUnlock()
+	return len(fake.saveScalingHistoryWithWebhookDeliveriesArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingHistoryWithWebhookDeliveriesArgsForCall(i int) (*models.AppScalingHistory, []*models.WebhookDelivery) {
+	fake.saveScalingHistoryWithWebhookDeliveriesMutex.RLock()
+	defer fake.saveScalingHistoryWithWebhookDeliveriesMutex.RUnlock()
+	return fake.saveScalingHistoryWithWebhookDeliveriesArgsForCall[i].history, fake.saveScalingHistoryWithWebhookDeliveriesArgsForCall[i].deliveries
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingHistoryWithWebhookDeliveriesReturns(result1 error) {
+	fake.SaveScalingHistoryWithWebhookDeliveriesStub = nil
+	fake.saveScalingHistoryWithWebhookDeliveriesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngineDB) RetrieveScalingExplanation(appId string, historyId int64) (*models.ScalingExplanation, error) {
+	fake.retrieveScalingExplanationMutex.Lock()
+	fake.retrieveScalingExplanationArgsForCall = append(fake.retrieveScalingExplanationArgsForCall, struct {
+		appId     string
+		historyId int64
+	}{appId, historyId})
+	fake.recordInvocation("RetrieveScalingExplanation", []interface{}{appId, historyId})
+	fake.retrieveScalingExplanationMutex.Unlock()
+	if fake.RetrieveScalingExplanationStub != nil {
+		return fake.RetrieveScalingExplanationStub(appId, historyId)
+	}
+	return fake.retrieveScalingExplanationReturns.result1, fake.retrieveScalingExplanationReturns.result2
+}
+
+func (fake *FakeScalingEngineDB) RetrieveScalingExplanationCallCount() int {
+	fake.retrieveScalingExplanationMutex.RLock()
+	defer fake.retrieveScalingExplanationMutex.RUnlock()
+	return len(fake.retrieveScalingExplanationArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) RetrieveScalingExplanationArgsForCall(i int) (string, int64) {
+	fake.retrieveScalingExplanationMutex.RLock()
+	defer fake.retrieveScalingExplanationMutex.RUnlock()
+	return fake.retrieveScalingExplanationArgsForCall[i].appId, fake.retrieveScalingExplanationArgsForCall[i].historyId
+}
+
+func (fake *FakeScalingEngineDB) RetrieveScalingExplanationReturns(result1 *models.ScalingExplanation, result2 error) {
+	fake.RetrieveScalingExplanationStub = nil
+	fake.retrieveScalingExplanationReturns = struct {
+		result1 *models.ScalingExplanation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingRecommendation(appId string, timestamp int64, instances int) error {
+	fake.saveScalingRecommendationMutex.Lock()
+	fake.saveScalingRecommendationArgsForCall = append(fake.saveScalingRecommendationArgsForCall, struct {
+		appId     string
+		timestamp int64
+		instances int
+	}{appId, timestamp, instances})
+	fake.recordInvocation("SaveScalingRecommendation", []interface{}{appId, timestamp, instances})
+	fake.saveScalingRecommendationMutex.Unlock()
+	if fake.SaveScalingRecommendationStub != nil {
+		return fake.SaveScalingRecommendationStub(appId, timestamp, instances)
+	}
+	return fake.saveScalingRecommendationReturns.result1
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingRecommendationCallCount() int {
+	fake.saveScalingRecommendationMutex.RLock()
+	defer fake.saveScalingRecommendationMutex.RUnlock()
+	return len(fake.saveScalingRecommendationArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingRecommendationArgsForCall(i int) (string, int64, int) {
+	fake.saveScalingRecommendationMutex.RLock()
+	defer fake.saveScalingRecommendationMutex.RUnlock()
+	return fake.saveScalingRecommendationArgsForCall[i].appId, fake.saveScalingRecommendationArgsForCall[i].timestamp, fake.saveScalingRecommendationArgsForCall[i].instances
+}
+
+func (fake *FakeScalingEngineDB) SaveScalingRecommendationReturns(result1 error) {
+	fake.SaveScalingRecommendationStub = nil
+	fake.saveScalingRecommendationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngineDB) GetMaxScalingRecommendation(appId string, start int64) (int, error) {
+	fake.getMaxScalingRecommendationMutex.Lock()
+	fake.getMaxScalingRecommendationArgsForCall = append(fake.getMaxScalingRecommendationArgsForCall, struct {
+		appId string
+		start int64
+	}{appId, start})
+	fake.recordInvocation("GetMaxScalingRecommendation", []interface{}{appId, start})
+	fake.getMaxScalingRecommendationMutex.Unlock()
+	if fake.GetMaxScalingRecommendationStub != nil {
+		return fake.GetMaxScalingRecommendationStub(appId, start)
+	}
+	return fake.getMaxScalingRecommendationReturns.result1, fake.getMaxScalingRecommendationReturns.result2
+}
+
+func (fake *FakeScalingEngineDB) GetMaxScalingRecommendationCallCount() int {
+	fake.getMaxScalingRecommendationMutex.RLock()
+	defer fake.getMaxScalingRecommendationMutex.RUnlock()
+	return len(fake.getMaxScalingRecommendationArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) GetMaxScalingRecommendationArgsForCall(i int) (string, int64) {
+	fake.getMaxScalingRecommendationMutex.RLock()
+	defer fake.getMaxScalingRecommendationMutex.RUnlock()
+	return fake.getMaxScalingRecommendationArgsForCall[i].appId, fake.getMaxScalingRecommendationArgsForCall[i].start
+}
+
+func (fake *FakeScalingEngineDB) GetMaxScalingRecommendationReturns(result1 int, result2 error) {
+	fake.GetMaxScalingRecommendationStub = nil
+	fake.getMaxScalingRecommendationReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeScalingEngineDB) PruneScalingHistories(before int64) error {
 	fake.pruneScalingHistoriesMutex.Lock()
 	fake.pruneScalingHistoriesArgsForCall = append(fake.pruneScalingHistoriesArgsForCall, struct {
@@ -213,6 +465,16 @@ func (fake *FakeScalingEngineDB) PruneScalingHistories(before int64) error {
 }
 
 func (fake *FakeScalingEngineDB) PruneScalingHistoriesCallCount() int {
+	fake.saveScalingExplanationMutex.RLock()
+	defer fake.saveScalingExplanationMutex.RUnlock()
+	fake.saveScalingHistoryWithWebhookDeliveriesMutex.RLock()
+	defer fake.saveScalingHistoryWithWebhookDeliveriesMutex.RUnlock()
+	fake.retrieveScalingExplanationMutex.RLock()
+	defer fake.retrieveScalingExplanationMutex.RUnlock()
+	fake.saveScalingRecommendationMutex.RLock()
+	defer fake.saveScalingRecommendationMutex.RUnlock()
+	fake.getMaxScalingRecommendationMutex.RLock()
+	defer fake.getMaxScalingRecommendationMutex.RUnlock()
 	fake.pruneScalingHistoriesMutex.RLock()
 	defer fake.pruneScalingHistoriesMutex.RUnlock()
 	return len(fake.pruneScalingHistoriesArgsForCall)
@@ -231,16 +493,18 @@ func (fake *FakeScalingEngineDB) PruneScalingHistoriesReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTime(appId string, expireAt int64) error {
+func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTime(appId string, direction string, ruleKey string, expireAt int64) error {
 	fake.updateScalingCooldownExpireTimeMutex.Lock()
 	fake.updateScalingCooldownExpireTimeArgsForCall = append(fake.updateScalingCooldownExpireTimeArgsForCall, struct {
-		appId    string
-		expireAt int64
-	}{appId, expireAt})
-	fake.recordInvocation("UpdateScalingCooldownExpireTime", []interface{}{appId, expireAt})
+		appId     string
+		direction string
+		ruleKey   string
+		expireAt  int64
+	}{appId, direction, ruleKey, expireAt})
+	fake.recordInvocation("UpdateScalingCooldownExpireTime", []interface{}{appId, direction, ruleKey, expireAt})
 	fake.updateScalingCooldownExpireTimeMutex.Unlock()
 	if fake.UpdateScalingCooldownExpireTimeStub != nil {
-		return fake.UpdateScalingCooldownExpireTimeStub(appId, expireAt)
+		return fake.UpdateScalingCooldownExpireTimeStub(appId, direction, ruleKey, expireAt)
 	}
 	return fake.updateScalingCooldownExpireTimeReturns.result1
 }
@@ -251,10 +515,10 @@ func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTimeCallCount() int
 	return len(fake.updateScalingCooldownExpireTimeArgsForCall)
 }
 
-func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTimeArgsForCall(i int) (string, int64) {
+func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTimeArgsForCall(i int) (string, string, string, int64) {
 	fake.updateScalingCooldownExpireTimeMutex.RLock()
 	defer fake.updateScalingCooldownExpireTimeMutex.RUnlock()
-	return fake.updateScalingCooldownExpireTimeArgsForCall[i].appId, fake.updateScalingCooldownExpireTimeArgsForCall[i].expireAt
+	return fake.updateScalingCooldownExpireTimeArgsForCall[i].appId, fake.updateScalingCooldownExpireTimeArgsForCall[i].direction, fake.updateScalingCooldownExpireTimeArgsForCall[i].ruleKey, fake.updateScalingCooldownExpireTimeArgsForCall[i].expireAt
 }
 
 func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTimeReturns(result1 error) {
@@ -264,15 +528,17 @@ func (fake *FakeScalingEngineDB) UpdateScalingCooldownExpireTimeReturns(result1
 	}{result1}
 }
 
-func (fake *FakeScalingEngineDB) CanScaleApp(appId string) (bool, int64, error) {
+func (fake *FakeScalingEngineDB) CanScaleApp(appId string, direction string, ruleKey string) (bool, int64, error) {
 	fake.canScaleAppMutex.Lock()
 	fake.canScaleAppArgsForCall = append(fake.canScaleAppArgsForCall, struct {
-		appId string
-	}{appId})
-	fake.recordInvocation("CanScaleApp", []interface{}{appId})
+		appId     string
+		direction string
+		ruleKey   string
+	}{appId, direction, ruleKey})
+	fake.recordInvocation("CanScaleApp", []interface{}{appId, direction, ruleKey})
 	fake.canScaleAppMutex.Unlock()
 	if fake.CanScaleAppStub != nil {
-		return fake.CanScaleAppStub(appId)
+		return fake.CanScaleAppStub(appId, direction, ruleKey)
 	}
 	return fake.canScaleAppReturns.result1, fake.canScaleAppReturns.result2, fake.canScaleAppReturns.result3
 }
@@ -283,10 +549,10 @@ func (fake *FakeScalingEngineDB) CanScaleAppCallCount() int {
 	return len(fake.canScaleAppArgsForCall)
 }
 
-func (fake *FakeScalingEngineDB) CanScaleAppArgsForCall(i int) string {
+func (fake *FakeScalingEngineDB) CanScaleAppArgsForCall(i int) (string, string, string) {
 	fake.canScaleAppMutex.RLock()
 	defer fake.canScaleAppMutex.RUnlock()
-	return fake.canScaleAppArgsForCall[i].appId
+	return fake.canScaleAppArgsForCall[i].appId, fake.canScaleAppArgsForCall[i].direction, fake.canScaleAppArgsForCall[i].ruleKey
 }
 
 func (fake *FakeScalingEngineDB) CanScaleAppReturns(result1 bool, result2 int64, result3 error) {
@@ -405,6 +671,12 @@ func (fake *FakeScalingEngineDB) RemoveActiveSchedule(appId string) error {
 func (fake *FakeScalingEngineDB) RemoveActiveScheduleCallCount() int {
 	fake.removeActiveScheduleMutex.RLock()
 	defer fake.removeActiveScheduleMutex.RUnlock()
+	fake.claimWebhookDeliveriesMutex.RLock()
+	defer fake.claimWebhookDeliveriesMutex.RUnlock()
+	fake.updateWebhookDeliveryMutex.RLock()
+	defer fake.updateWebhookDeliveryMutex.RUnlock()
+	fake.deleteWebhookDeliveryMutex.RLock()
+	defer fake.deleteWebhookDeliveryMutex.RUnlock()
 	return len(fake.removeActiveScheduleArgsForCall)
 }
 
@@ -421,6 +693,105 @@ func (fake *FakeScalingEngineDB) RemoveActiveScheduleReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeScalingEngineDB) ClaimWebhookDeliveries(now int64, leaseUntil int64, limit int) ([]*models.WebhookDelivery, error) {
+	fake.claimWebhookDeliveriesMutex.Lock()
+	fake.claimWebhookDeliveriesArgsForCall = append(fake.claimWebhookDeliveriesArgsForCall, struct {
+		now        int64
+		leaseUntil int64
+		limit      int
+	}{now, leaseUntil, limit})
+	fake.recordInvocation("ClaimWebhookDeliveries", []interface{}{now, leaseUntil, limit})
+	fake.claimWebhookDeliveriesMutex.Unlock()
+	if fake.ClaimWebhookDeliveriesStub != nil {
+		return fake.ClaimWebhookDeliveriesStub(now, leaseUntil, limit)
+	}
+	return fake.claimWebhookDeliveriesReturns.result1, fake.claimWebhookDeliveriesReturns.result2
+}
+
+func (fake *FakeScalingEngineDB) ClaimWebhookDeliveriesCallCount() int {
+	fake.claimWebhookDeliveriesMutex.RLock()
+	defer fake.claimWebhookDeliveriesMutex.RUnlock()
+	return len(fake.claimWebhookDeliveriesArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) ClaimWebhookDeliveriesArgsForCall(i int) (int64, int64, int) {
+	fake.claimWebhookDeliveriesMutex.RLock()
+	defer fake.claimWebhookDeliveriesMutex.RUnlock()
+	return fake.claimWebhookDeliveriesArgsForCall[i].now, fake.claimWebhookDeliveriesArgsForCall[i].leaseUntil, fake.claimWebhookDeliveriesArgsForCall[i].limit
+}
+
+func (fake *FakeScalingEngineDB) ClaimWebhookDeliveriesReturns(result1 []*models.WebhookDelivery, result2 error) {
+	fake.ClaimWebhookDeliveriesStub = nil
+	fake.claimWebhookDeliveriesReturns = struct {
+		result1 []*models.WebhookDelivery
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScalingEngineDB) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	fake.updateWebhookDeliveryMutex.Lock()
+	fake.updateWebhookDeliveryArgsForCall = append(fake.updateWebhookDeliveryArgsForCall, struct {
+		delivery *models.WebhookDelivery
+	}{delivery})
+	fake.recordInvocation("UpdateWebhookDelivery", []interface{}{delivery})
+	fake.updateWebhookDeliveryMutex.Unlock()
+	if fake.UpdateWebhookDeliveryStub != nil {
+		return fake.UpdateWebhookDeliveryStub(delivery)
+	}
+	return fake.updateWebhookDeliveryReturns.result1
+}
+
+func (fake *FakeScalingEngineDB) UpdateWebhookDeliveryCallCount() int {
+	fake.updateWebhookDeliveryMutex.RLock()
+	defer fake.updateWebhookDeliveryMutex.RUnlock()
+	return len(fake.updateWebhookDeliveryArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) UpdateWebhookDeliveryArgsForCall(i int) *models.WebhookDelivery {
+	fake.updateWebhookDeliveryMutex.RLock()
+	defer fake.updateWebhookDeliveryMutex.RUnlock()
+	return fake.updateWebhookDeliveryArgsForCall[i].delivery
+}
+
+func (fake *FakeScalingEngineDB) UpdateWebhookDeliveryReturns(result1 error) {
+	fake.UpdateWebhookDeliveryStub = nil
+	fake.updateWebhookDeliveryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScalingEngineDB) DeleteWebhookDelivery(id int64) error {
+	fake.deleteWebhookDeliveryMutex.Lock()
+	fake.deleteWebhookDeliveryArgsForCall = append(fake.deleteWebhookDeliveryArgsForCall, struct {
+		id int64
+	}{id})
+	fake.recordInvocation("DeleteWebhookDelivery", []interface{}{id})
+	fake.deleteWebhookDeliveryMutex.Unlock()
+	if fake.DeleteWebhookDeliveryStub != nil {
+		return fake.DeleteWebhookDeliveryStub(id)
+	}
+	return fake.deleteWebhookDeliveryReturns.result1
+}
+
+func (fake *FakeScalingEngineDB) DeleteWebhookDeliveryCallCount() int {
+	fake.deleteWebhookDeliveryMutex.RLock()
+	defer fake.deleteWebhookDeliveryMutex.RUnlock()
+	return len(fake.deleteWebhookDeliveryArgsForCall)
+}
+
+func (fake *FakeScalingEngineDB) DeleteWebhookDeliveryArgsForCall(i int) int64 {
+	fake.deleteWebhookDeliveryMutex.RLock()
+	defer fake.deleteWebhookDeliveryMutex.RUnlock()
+	return fake.deleteWebhookDeliveryArgsForCall[i].id
+}
+
+func (fake *FakeScalingEngineDB) DeleteWebhookDeliveryReturns(result1 error) {
+	fake.DeleteWebhookDeliveryStub = nil
+	fake.deleteWebhookDeliveryReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeScalingEngineDB) Close() error {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
@@ -5,3 +5,7 @@ package fakes
 //go:generate counterfeiter -o ./fake_scalingengine_db.go ../../db ScalingEngineDB
 //go:generate counterfeiter -o ./fake_scheduler_db.go ../../db SchedulerDB
 //go:generate counterfeiter -o ./fake_scalingengine.go ../ ScalingEngine
+//go:generate counterfeiter -o ./fake_scaling_notifier.go ../scalingengine ScalingNotifier
+//go:generate counterfeiter -o ./fake_app_status_provider.go ../healthendpoint AppStatusProvider
+//go:generate counterfeiter -o ./fake_rate_limit_status_provider.go ../healthendpoint RateLimitStatusProvider
+//go:generate counterfeiter -o ./fake_anomaly_record_status_provider.go ../healthendpoint AnomalyRecordStatusProvider
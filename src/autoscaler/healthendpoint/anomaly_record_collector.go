@@ -0,0 +1,48 @@
+package healthendpoint
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type AnomalyRecordStatus struct {
+	// the number of anomalies saved in notify mode
+	Recorded int64
+	// the number of anomalies that could not be saved
+	Failed int64
+}
+
+type AnomalyRecordStatusProvider interface {
+	GetAnomalyRecordStatus() *AnomalyRecordStatus
+}
+
+type anomalyRecordCollector struct {
+	recordedDesc *prometheus.Desc
+	failedDesc   *prometheus.Desc
+
+	provider AnomalyRecordStatusProvider
+}
+
+func NewAnomalyRecordCollector(namespace, subSystem string, provider AnomalyRecordStatusProvider) prometheus.Collector {
+	return &anomalyRecordCollector{
+		recordedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "anomaly_records_total"),
+			"Number of anomalies recorded for the anomaly rules in notify mode",
+			nil, nil),
+		failedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "anomaly_record_failures_total"),
+			"Number of anomalies of the anomaly rules in notify mode that failed to be recorded",
+			nil, nil),
+		provider: provider,
+	}
+}
+
+func (c *anomalyRecordCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.recordedDesc
+	ch <- c.failedDesc
+}
+
+func (c *anomalyRecordCollector) Collect(ch chan<- prometheus.Metric) {
+	status := c.provider.GetAnomalyRecordStatus()
+	ch <- prometheus.MustNewConstMetric(c.recordedDesc, prometheus.CounterValue, float64(status.Recorded))
+	ch <- prometheus.MustNewConstMetric(c.failedDesc, prometheus.CounterValue, float64(status.Failed))
+}
@@ -0,0 +1,64 @@
+package healthendpoint_test
+
+import (
+	"autoscaler/fakes"
+	. "autoscaler/healthendpoint"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("AnomalyRecordCollector", func() {
+	var (
+		anomalyRecordCollector prometheus.Collector
+		namespace              string = "test_name_space"
+		subSystem              string = "test_sub_system"
+		provider               *fakes.FakeAnomalyRecordStatusProvider
+		metrics                []prometheus.Metric
+
+		recordedDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "anomaly_records_total"),
+			"Number of anomalies recorded for the anomaly rules in notify mode",
+			nil, nil)
+		failedDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "anomaly_record_failures_total"),
+			"Number of anomalies of the anomaly rules in notify mode that failed to be recorded",
+			nil, nil)
+	)
+
+	BeforeEach(func() {
+		provider = &fakes.FakeAnomalyRecordStatusProvider{}
+		provider.GetAnomalyRecordStatusReturns(&AnomalyRecordStatus{Recorded: 7, Failed: 2})
+	})
+
+	JustBeforeEach(func() {
+		anomalyRecordCollector = NewAnomalyRecordCollector(namespace, subSystem, provider)
+		metricChan := make(chan prometheus.Metric, 10)
+		anomalyRecordCollector.Collect(metricChan)
+		close(metricChan)
+		metrics = []prometheus.Metric{}
+		for metric := range metricChan {
+			metrics = append(metrics, metric)
+		}
+	})
+
+	Context("Describe", func() {
+		It("Receive descs", func() {
+			descChan := make(chan *prometheus.Desc, 10)
+			anomalyRecordCollector.Describe(descChan)
+			Eventually(descChan).Should(Receive(Equal(recordedDesc)))
+			Eventually(descChan).Should(Receive(Equal(failedDesc)))
+		})
+	})
+
+	Context("Collect", func() {
+		It("publishes the recorded and the failed anomalies", func() {
+			Expect(metrics).To(ConsistOf(
+				prometheus.MustNewConstMetric(recordedDesc, prometheus.CounterValue, 7),
+				prometheus.MustNewConstMetric(failedDesc, prometheus.CounterValue, 2),
+			))
+		})
+	})
+})
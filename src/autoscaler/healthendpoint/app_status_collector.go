@@ -0,0 +1,159 @@
+package healthendpoint
+
+import (
+	"autoscaler/models"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type AppStatus struct {
+	AppId       string
+	Instances   int // negative when no scaling decision has been made for the app yet
+	InstanceMin int
+	InstanceMax int
+	// the latest aggregated metric of every metric type and aggregation
+	Metrics []*models.AppMetric
+	// the breach state of the rules, keyed by metric type or by condition for compound rules
+	Breaches                          map[string]bool
+	CooldownRemaining                 time.Duration
+	CircuitBreakerTripped             bool
+	CircuitBreakerConsecutiveFailures int64
+	// the number of scaling decisions by status
+	ScalingDecisions map[string]int64
+}
+
+type AppStatusProvider interface {
+	GetAppStatuses() []*AppStatus
+}
+
+type appStatusCollector struct {
+	instancesDesc             *prometheus.Desc
+	instanceMinDesc           *prometheus.Desc
+	instanceMaxDesc           *prometheus.Desc
+	metricValueDesc           *prometheus.Desc
+	breachedDesc              *prometheus.Desc
+	cooldownRemainingDesc     *prometheus.Desc
+	breakerTrippedDesc        *prometheus.Desc
+	breakerConsecFailuresDesc *prometheus.Desc
+	scalingDecisionsDesc      *prometheus.Desc
+	omittedAppsDesc           *prometheus.Desc
+
+	provider AppStatusProvider
+	appIds   map[string]bool
+	maxApps  int
+}
+
+// NewAppStatusCollector publishes the status of every app with app_id labels. To keep the label cardinality under
+// control, only the apps in appIds are published when it is not empty, and no more than maxApps apps are published
+// when it is positive.
+func NewAppStatusCollector(namespace, subSystem string, provider AppStatusProvider, appIds []string, maxApps int) prometheus.Collector {
+	appIdSet := map[string]bool{}
+	for _, appId := range appIds {
+		appIdSet[appId] = true
+	}
+	return &appStatusCollector{
+		instancesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_instances"),
+			"Number of instances of the app after the latest scaling decision",
+			[]string{"app_id"}, nil),
+		instanceMinDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_instance_min"),
+			"Minimum number of instances of the app in the scaling policy",
+			[]string{"app_id"}, nil),
+		instanceMaxDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_instance_max"),
+			"Maximum number of instances of the app in the scaling policy",
+			[]string{"app_id"}, nil),
+		metricValueDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_metric_value"),
+			"The latest aggregated value of the metric type",
+			[]string{"app_id", "metric_type", "aggregation"}, nil),
+		breachedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_rule_breached"),
+			"Whether the scaling rule was breached in the latest evaluation",
+			[]string{"app_id", "metric_type"}, nil),
+		cooldownRemainingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_cooldown_remaining_seconds"),
+			"Remaining seconds of the cooldown period of the app",
+			[]string{"app_id"}, nil),
+		breakerTrippedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_circuit_breaker_tripped"),
+			"Whether the circuit breaker to the scaling engine is tripped for the app",
+			[]string{"app_id"}, nil),
+		breakerConsecFailuresDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_circuit_breaker_consecutive_failures"),
+			"Number of consecutive failures of the circuit breaker to the scaling engine for the app",
+			[]string{"app_id"}, nil),
+		scalingDecisionsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_scaling_decisions_total"),
+			"Number of scaling decisions for the app by status",
+			[]string{"app_id", "status"}, nil),
+		omittedAppsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_status_omitted_apps"),
+			"Number of apps whose status is not published because of the max apps limit",
+			nil, nil),
+		provider: provider,
+		appIds:   appIdSet,
+		maxApps:  maxApps,
+	}
+}
+
+func (c *appStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.instancesDesc
+	ch <- c.instanceMinDesc
+	ch <- c.instanceMaxDesc
+	ch <- c.metricValueDesc
+	ch <- c.breachedDesc
+	ch <- c.cooldownRemainingDesc
+	ch <- c.breakerTrippedDesc
+	ch <- c.breakerConsecFailuresDesc
+	ch <- c.scalingDecisionsDesc
+	ch <- c.omittedAppsDesc
+}
+
+func (c *appStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	statuses := []*AppStatus{}
+	for _, status := range c.provider.GetAppStatuses() {
+		if len(c.appIds) == 0 || c.appIds[status.AppId] {
+			statuses = append(statuses, status)
+		}
+	}
+	// sort to publish the same apps on every scrape when the limit is reached
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].AppId < statuses[j].AppId })
+	omitted := 0
+	if c.maxApps > 0 && len(statuses) > c.maxApps {
+		omitted = len(statuses) - c.maxApps
+		statuses = statuses[:c.maxApps]
+	}
+	ch <- prometheus.MustNewConstMetric(c.omittedAppsDesc, prometheus.GaugeValue, float64(omitted))
+
+	for _, status := range statuses {
+		appId := status.AppId
+		if status.Instances >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.instancesDesc, prometheus.GaugeValue, float64(status.Instances), appId)
+		}
+		ch <- prometheus.MustNewConstMetric(c.instanceMinDesc, prometheus.GaugeValue, float64(status.InstanceMin), appId)
+		ch <- prometheus.MustNewConstMetric(c.instanceMaxDesc, prometheus.GaugeValue, float64(status.InstanceMax), appId)
+		for _, metric := range status.Metrics {
+			ch <- prometheus.MustNewConstMetric(c.metricValueDesc, prometheus.GaugeValue, metric.Value, appId, metric.MetricType, metric.Aggregation)
+		}
+		for metricType, breached := range status.Breaches {
+			ch <- prometheus.MustNewConstMetric(c.breachedDesc, prometheus.GaugeValue, boolToFloat(breached), appId, metricType)
+		}
+		ch <- prometheus.MustNewConstMetric(c.cooldownRemainingDesc, prometheus.GaugeValue, status.CooldownRemaining.Seconds(), appId)
+		ch <- prometheus.MustNewConstMetric(c.breakerTrippedDesc, prometheus.GaugeValue, boolToFloat(status.CircuitBreakerTripped), appId)
+		ch <- prometheus.MustNewConstMetric(c.breakerConsecFailuresDesc, prometheus.GaugeValue, float64(status.CircuitBreakerConsecutiveFailures), appId)
+		for scalingStatus, count := range status.ScalingDecisions {
+			ch <- prometheus.MustNewConstMetric(c.scalingDecisionsDesc, prometheus.CounterValue, float64(count), appId, scalingStatus)
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
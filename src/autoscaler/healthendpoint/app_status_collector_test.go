@@ -0,0 +1,147 @@
+package healthendpoint_test
+
+import (
+	"autoscaler/fakes"
+	. "autoscaler/healthendpoint"
+	"autoscaler/models"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("AppStatusCollector", func() {
+	var (
+		appStatusCollector prometheus.Collector
+		namespace          string = "test_name_space"
+		subSystem          string = "test_sub_system"
+		provider           *fakes.FakeAppStatusProvider
+		appIds             []string
+		maxApps            int
+		metrics            []prometheus.Metric
+
+		instancesDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_instances"),
+			"Number of instances of the app after the latest scaling decision",
+			[]string{"app_id"}, nil)
+		instanceMinDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_instance_min"),
+			"Minimum number of instances of the app in the scaling policy",
+			[]string{"app_id"}, nil)
+		metricValueDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_metric_value"),
+			"The latest aggregated value of the metric type",
+			[]string{"app_id", "metric_type", "aggregation"}, nil)
+		breachedDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_rule_breached"),
+			"Whether the scaling rule was breached in the latest evaluation",
+			[]string{"app_id", "metric_type"}, nil)
+		cooldownRemainingDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_cooldown_remaining_seconds"),
+			"Remaining seconds of the cooldown period of the app",
+			[]string{"app_id"}, nil)
+		breakerTrippedDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_circuit_breaker_tripped"),
+			"Whether the circuit breaker to the scaling engine is tripped for the app",
+			[]string{"app_id"}, nil)
+		scalingDecisionsDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_scaling_decisions_total"),
+			"Number of scaling decisions for the app by status",
+			[]string{"app_id", "status"}, nil)
+		omittedAppsDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "app_status_omitted_apps"),
+			"Number of apps whose status is not published because of the max apps limit",
+			nil, nil)
+	)
+
+	BeforeEach(func() {
+		provider = &fakes.FakeAppStatusProvider{}
+		provider.GetAppStatusesReturns([]*AppStatus{
+			{
+				AppId:       "app-2",
+				Instances:   -1,
+				InstanceMin: 1,
+				InstanceMax: 5,
+			},
+			{
+				AppId:       "app-1",
+				Instances:   3,
+				InstanceMin: 2,
+				InstanceMax: 10,
+				Metrics: []*models.AppMetric{
+					{AppId: "app-1", MetricType: "memoryused", Aggregation: models.AggregationMax, Value: 300, Unit: "MB"},
+				},
+				Breaches:                          map[string]bool{"memoryused": true},
+				CooldownRemaining:                 90 * time.Second,
+				CircuitBreakerTripped:             true,
+				CircuitBreakerConsecutiveFailures: 3,
+				ScalingDecisions:                  map[string]int64{"succeeded": 2},
+			},
+		})
+		appIds = nil
+		maxApps = 0
+	})
+
+	JustBeforeEach(func() {
+		appStatusCollector = NewAppStatusCollector(namespace, subSystem, provider, appIds, maxApps)
+		metricChan := make(chan prometheus.Metric, 100)
+		appStatusCollector.Collect(metricChan)
+		close(metricChan)
+		metrics = []prometheus.Metric{}
+		for metric := range metricChan {
+			metrics = append(metrics, metric)
+		}
+	})
+
+	Context("Describe", func() {
+		It("Receive descs", func() {
+			descChan := make(chan *prometheus.Desc, 20)
+			appStatusCollector.Describe(descChan)
+			Eventually(descChan).Should(Receive(Equal(instancesDesc)))
+			Eventually(descChan).Should(Receive(Equal(instanceMinDesc)))
+		})
+	})
+
+	Context("Collect", func() {
+		It("publishes the status of every app", func() {
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(omittedAppsDesc, prometheus.GaugeValue, 0)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instancesDesc, prometheus.GaugeValue, 3, "app-1")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 2, "app-1")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(metricValueDesc, prometheus.GaugeValue, 300, "app-1", "memoryused", models.AggregationMax)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(breachedDesc, prometheus.GaugeValue, 1, "app-1", "memoryused")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(cooldownRemainingDesc, prometheus.GaugeValue, 90, "app-1")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(breakerTrippedDesc, prometheus.GaugeValue, 1, "app-1")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(scalingDecisionsDesc, prometheus.CounterValue, 2, "app-1", "succeeded")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 1, "app-2")))
+		})
+
+		It("does not publish unknown instances and unparsable metric values", func() {
+			Expect(metrics).NotTo(ContainElement(prometheus.MustNewConstMetric(instancesDesc, prometheus.GaugeValue, -1, "app-2")))
+			// the omitted apps, 9 of app-1 with a metric value, a breach and a scaling decision, 5 of app-2
+			Expect(metrics).To(HaveLen(1 + 9 + 5))
+		})
+
+		Context("when app ids are set", func() {
+			BeforeEach(func() {
+				appIds = []string{"app-2"}
+			})
+			It("publishes the status of the apps only", func() {
+				Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 1, "app-2")))
+				Expect(metrics).NotTo(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 2, "app-1")))
+			})
+		})
+
+		Context("when there are more apps than max apps", func() {
+			BeforeEach(func() {
+				maxApps = 1
+			})
+			It("publishes the status of max apps in the order of app id", func() {
+				Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(omittedAppsDesc, prometheus.GaugeValue, 1)))
+				Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 2, "app-1")))
+				Expect(metrics).NotTo(ContainElement(prometheus.MustNewConstMetric(instanceMinDesc, prometheus.GaugeValue, 1, "app-2")))
+			})
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package healthendpoint
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type RateLimitStatus struct {
+	AppMetricsPerSecond      float64
+	AppBurst                 int
+	InstanceMetricsPerSecond float64
+	InstanceBurst            int
+	MaxMetricNamesPerApp     int
+	MaxInstancesPerApp       int
+	// the number of apps whose rate limit state is kept
+	Apps int
+	// the number of rejected requests by the limit they exceeded
+	RejectedRequests map[string]int64
+}
+
+type RateLimitStatusProvider interface {
+	GetRateLimitStatus() *RateLimitStatus
+}
+
+type rateLimitCollector struct {
+	appMetricsPerSecondDesc      *prometheus.Desc
+	appBurstDesc                 *prometheus.Desc
+	instanceMetricsPerSecondDesc *prometheus.Desc
+	instanceBurstDesc            *prometheus.Desc
+	maxMetricNamesPerAppDesc     *prometheus.Desc
+	maxInstancesPerAppDesc       *prometheus.Desc
+	appsDesc                     *prometheus.Desc
+	rejectedRequestsDesc         *prometheus.Desc
+
+	provider RateLimitStatusProvider
+}
+
+func NewRateLimitCollector(namespace, subSystem string, provider RateLimitStatusProvider) prometheus.Collector {
+	return &rateLimitCollector{
+		appMetricsPerSecondDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_app_metrics_per_second"),
+			"Number of metrics an app can publish per second, 0 when the limit is disabled",
+			nil, nil),
+		appBurstDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_app_burst"),
+			"Number of metrics an app can publish at once",
+			nil, nil),
+		instanceMetricsPerSecondDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_instance_metrics_per_second"),
+			"Number of metrics an app instance can publish per second, 0 when the limit is disabled",
+			nil, nil),
+		instanceBurstDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_instance_burst"),
+			"Number of metrics an app instance can publish at once",
+			nil, nil),
+		maxMetricNamesPerAppDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_max_metric_names_per_app"),
+			"Maximum number of metric names an app can publish, 0 when the limit is disabled",
+			nil, nil),
+		maxInstancesPerAppDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_max_instances_per_app"),
+			"Maximum number of instances of an app which are rate limited at the same time, 0 when the limit is disabled",
+			nil, nil),
+		appsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_apps"),
+			"Number of apps tracked by the rate limiter",
+			nil, nil),
+		rejectedRequestsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_rejected_requests_total"),
+			"Number of requests rejected by the rate limiter by the limit they exceeded",
+			[]string{"limit"}, nil),
+		provider: provider,
+	}
+}
+
+func (c *rateLimitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.appMetricsPerSecondDesc
+	ch <- c.appBurstDesc
+	ch <- c.instanceMetricsPerSecondDesc
+	ch <- c.instanceBurstDesc
+	ch <- c.maxMetricNamesPerAppDesc
+	ch <- c.maxInstancesPerAppDesc
+	ch <- c.appsDesc
+	ch <- c.rejectedRequestsDesc
+}
+
+func (c *rateLimitCollector) Collect(ch chan<- prometheus.Metric) {
+	status := c.provider.GetRateLimitStatus()
+	ch <- prometheus.MustNewConstMetric(c.appMetricsPerSecondDesc, prometheus.GaugeValue, status.AppMetricsPerSecond)
+	ch <- prometheus.MustNewConstMetric(c.appBurstDesc, prometheus.GaugeValue, float64(status.AppBurst))
+	ch <- prometheus.MustNewConstMetric(c.instanceMetricsPerSecondDesc, prometheus.GaugeValue, status.InstanceMetricsPerSecond)
+	ch <- prometheus.MustNewConstMetric(c.instanceBurstDesc, prometheus.GaugeValue, float64(status.InstanceBurst))
+	ch <- prometheus.MustNewConstMetric(c.maxMetricNamesPerAppDesc, prometheus.GaugeValue, float64(status.MaxMetricNamesPerApp))
+	ch <- prometheus.MustNewConstMetric(c.maxInstancesPerAppDesc, prometheus.GaugeValue, float64(status.MaxInstancesPerApp))
+	ch <- prometheus.MustNewConstMetric(c.appsDesc, prometheus.GaugeValue, float64(status.Apps))
+	for limit, count := range status.RejectedRequests {
+		ch <- prometheus.MustNewConstMetric(c.rejectedRequestsDesc, prometheus.CounterValue, float64(count), limit)
+	}
+}
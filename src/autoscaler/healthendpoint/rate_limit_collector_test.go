@@ -0,0 +1,92 @@
+package healthendpoint_test
+
+import (
+	"autoscaler/fakes"
+	. "autoscaler/healthendpoint"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("RateLimitCollector", func() {
+	var (
+		rateLimitCollector prometheus.Collector
+		namespace          string = "test_name_space"
+		subSystem          string = "test_sub_system"
+		provider           *fakes.FakeRateLimitStatusProvider
+		metrics            []prometheus.Metric
+
+		appMetricsPerSecondDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_app_metrics_per_second"),
+			"Number of metrics an app can publish per second, 0 when the limit is disabled",
+			nil, nil)
+		instanceBurstDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_instance_burst"),
+			"Number of metrics an app instance can publish at once",
+			nil, nil)
+		maxMetricNamesPerAppDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_max_metric_names_per_app"),
+			"Maximum number of metric names an app can publish, 0 when the limit is disabled",
+			nil, nil)
+		maxInstancesPerAppDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_max_instances_per_app"),
+			"Maximum number of instances of an app which are rate limited at the same time, 0 when the limit is disabled",
+			nil, nil)
+		appsDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_apps"),
+			"Number of apps tracked by the rate limiter",
+			nil, nil)
+		rejectedRequestsDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subSystem, "rate_limit_rejected_requests_total"),
+			"Number of requests rejected by the rate limiter by the limit they exceeded",
+			[]string{"limit"}, nil)
+	)
+
+	BeforeEach(func() {
+		provider = &fakes.FakeRateLimitStatusProvider{}
+		provider.GetRateLimitStatusReturns(&RateLimitStatus{
+			AppMetricsPerSecond:      100,
+			AppBurst:                 1000,
+			InstanceMetricsPerSecond: 0.5,
+			InstanceBurst:            10,
+			MaxMetricNamesPerApp:     20,
+			MaxInstancesPerApp:       50,
+			Apps:                     3,
+			RejectedRequests:         map[string]int64{"app_rate": 5, "metric_names": 1},
+		})
+	})
+
+	JustBeforeEach(func() {
+		rateLimitCollector = NewRateLimitCollector(namespace, subSystem, provider)
+		metricChan := make(chan prometheus.Metric, 100)
+		rateLimitCollector.Collect(metricChan)
+		close(metricChan)
+		metrics = []prometheus.Metric{}
+		for metric := range metricChan {
+			metrics = append(metrics, metric)
+		}
+	})
+
+	Context("Describe", func() {
+		It("Receive descs", func() {
+			descChan := make(chan *prometheus.Desc, 20)
+			rateLimitCollector.Describe(descChan)
+			Eventually(descChan).Should(Receive(Equal(appMetricsPerSecondDesc)))
+		})
+	})
+
+	Context("Collect", func() {
+		It("publishes the limits and the rejected requests", func() {
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(appMetricsPerSecondDesc, prometheus.GaugeValue, 100)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(instanceBurstDesc, prometheus.GaugeValue, 10)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(maxMetricNamesPerAppDesc, prometheus.GaugeValue, 20)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(maxInstancesPerAppDesc, prometheus.GaugeValue, 50)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(appsDesc, prometheus.GaugeValue, 3)))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(rejectedRequestsDesc, prometheus.CounterValue, 5, "app_rate")))
+			Expect(metrics).To(ContainElement(prometheus.MustNewConstMetric(rejectedRequestsDesc, prometheus.CounterValue, 1, "metric_names")))
+			Expect(metrics).To(HaveLen(7 + 2))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath selects a value of a decoded JSON document with the subset of JSONPath made of member names and array
+// indexes, e.g. $.queues[0].messages or $['consumer-groups'].lag.
+type JSONPath []interface{}
+
+func ParseJSONPath(path string) (JSONPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %q does not start with $", path)
+	}
+	jsonPath := JSONPath{}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("json path %q has an empty member name", path)
+			}
+			jsonPath = append(jsonPath, name)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("json path %q has an unclosed [", path)
+			}
+			selector := rest[1:end]
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				jsonPath = append(jsonPath, selector[1:len(selector)-1])
+			} else {
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("json path %q has an invalid selector [%s]", path, selector)
+				}
+				jsonPath = append(jsonPath, index)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("json path %q has an unexpected %q", path, rest[0])
+		}
+	}
+	return jsonPath, nil
+}
+
+func (p JSONPath) Select(document interface{}) (interface{}, error) {
+	value := document
+	for _, element := range p {
+		switch selector := element.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an object", selector)
+			}
+			if value, ok = object[selector]; !ok {
+				return nil, fmt.Errorf("member %s is not found", selector)
+			}
+		case int:
+			array, ok := value.([]interface{})
+			if !ok || selector >= len(array) {
+				return nil, fmt.Errorf("index %d is not found", selector)
+			}
+			value = array[selector]
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package helpers_test
+
+import (
+	. "autoscaler/helpers"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONPath", func() {
+	var (
+		path     string
+		jsonPath JSONPath
+		err      error
+	)
+
+	JustBeforeEach(func() {
+		jsonPath, err = ParseJSONPath(path)
+	})
+
+	Context("when the path has member names and indexes", func() {
+		var document interface{}
+		BeforeEach(func() {
+			path = `$.queues[1]['consumer-lag'].value`
+			Expect(json.Unmarshal([]byte(`{"queues":[{},{"consumer-lag":{"value":42}}]}`), &document)).To(Succeed())
+		})
+		It("selects the value", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jsonPath).To(Equal(JSONPath{"queues", 1, "consumer-lag", "value"}))
+			value, err := jsonPath.Select(document)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(42.0))
+		})
+		It("fails to select from a document without the value", func() {
+			_, err := jsonPath.Select(map[string]interface{}{"queues": []interface{}{}})
+			Expect(err).To(MatchError("index 1 is not found"))
+		})
+	})
+
+	Context("when the path is the root", func() {
+		BeforeEach(func() {
+			path = "$"
+		})
+		It("selects the document", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jsonPath.Select(12.0)).To(Equal(12.0))
+		})
+	})
+
+	Context("when the path does not start with $", func() {
+		BeforeEach(func() {
+			path = "queues.messages"
+		})
+		It("should error", func() {
+			Expect(err).To(MatchError(`json path "queues.messages" does not start with $`))
+		})
+	})
+
+	Context("when the path has an invalid index", func() {
+		BeforeEach(func() {
+			path = "$.queues[*]"
+		})
+		It("should error", func() {
+			Expect(err).To(MatchError(`json path "$.queues[*]" has an invalid selector [*]`))
+		})
+	})
+
+	Context("when the path has an empty member name", func() {
+		BeforeEach(func() {
+			path = "$..messages"
+		})
+		It("should error", func() {
+			Expect(err).To(MatchError(`json path "$..messages" has an empty member name`))
+		})
+	})
+})
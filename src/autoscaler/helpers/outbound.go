@@ -0,0 +1,155 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cfhttp"
+)
+
+// OutboundConfig restricts the hosts which are called on behalf of the apps, e.g. the metric sources and the webhooks
+// of the policies, so that a policy cannot make the components call the internal endpoints of the platform.
+type OutboundConfig struct {
+	// host names, or *.domain for all the subdomains of domain; no host can be called when it is empty
+	AllowedHosts []string `yaml:"allowed_hosts"`
+	// networks in CIDR notation which can be called although they are loopback, link-local or private networks
+	AllowedNetworks []string `yaml:"allowed_networks"`
+}
+
+var refusedNetworks = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// OutboundGuard checks the URLs and the resolved addresses of the outbound requests against an OutboundConfig.
+type OutboundGuard struct {
+	allowedHosts    []string
+	allowedNetworks []*net.IPNet
+}
+
+func NewOutboundGuard(conf OutboundConfig) (*OutboundGuard, error) {
+	guard := &OutboundGuard{}
+	for _, host := range conf.AllowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || host == "*." {
+			return nil, fmt.Errorf("invalid allowed host %q", host)
+		}
+		guard.allowedHosts = append(guard.allowedHosts, host)
+	}
+	for _, cidr := range conf.AllowedNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %q: %s", cidr, err.Error())
+		}
+		guard.allowedNetworks = append(guard.allowedNetworks, network)
+	}
+	return guard, nil
+}
+
+// CheckURL refuses the URLs which are not http or https, or whose host is not allowed.
+func (g *OutboundGuard) CheckURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme %q of %s is not allowed", u.Scheme, u.Host)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range g.allowedHosts {
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %s is not allowed", host)
+}
+
+// CheckIP refuses the loopback, link-local, multicast and private addresses unless their network is allowed.
+func (g *OutboundGuard) CheckIP(ip net.IP) error {
+	for _, network := range g.allowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("address %s is not allowed", ip)
+	}
+	for _, network := range refusedNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("address %s is not allowed", ip)
+		}
+	}
+	return nil
+}
+
+// NewHTTPClient creates a client which checks the URL of every request, including the redirects, and dials only the
+// addresses which pass CheckIP after the host is resolved, so that a host cannot resolve to another address in between.
+func (g *OutboundGuard) NewHTTPClient() *http.Client {
+	client := cfhttp.NewClient()
+	transport := client.Transport.(*http.Transport)
+	// a proxy would resolve the host instead of the guard
+	transport.Proxy = nil
+	transport.Dial = nil
+	transport.DialContext = g.dialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	client.Transport = &guardedTransport{guard: g, transport: transport}
+	return client
+}
+
+func (g *OutboundGuard) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("host %s has no address", host)
+		}
+		for _, a := range addrs {
+			if err := g.CheckIP(a.IP); err != nil {
+				return nil, fmt.Errorf("host %s: %s", host, err.Error())
+			}
+		}
+		return dialer.DialContext(ctx, network, net.JoinHostPort(addrs[0].IP.String(), port))
+	}
+}
+
+type guardedTransport struct {
+	guard     *OutboundGuard
+	transport http.RoundTripper
+}
+
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.guard.CheckURL(req.URL); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.transport.RoundTrip(req)
+}
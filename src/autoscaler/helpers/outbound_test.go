@@ -0,0 +1,144 @@
+package helpers_test
+
+import (
+	. "autoscaler/helpers"
+
+	"net"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("OutboundGuard", func() {
+	var (
+		conf  OutboundConfig
+		guard *OutboundGuard
+		err   error
+	)
+
+	BeforeEach(func() {
+		conf = OutboundConfig{
+			AllowedHosts: []string{"metrics.example.com", "*.example.org", "127.0.0.1"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		guard, err = NewOutboundGuard(conf)
+	})
+
+	Context("NewOutboundGuard", func() {
+		Context("when an allowed network is invalid", func() {
+			BeforeEach(func() {
+				conf.AllowedNetworks = []string{"10.0.0.0"}
+			})
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("invalid allowed network")))
+			})
+		})
+	})
+
+	Context("CheckURL", func() {
+		check := func(rawURL string) error {
+			u, err := url.Parse(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+			return guard.CheckURL(u)
+		}
+
+		It("allows the allowed hosts and the subdomains of the allowed domains", func() {
+			Expect(check("https://metrics.example.com/api")).To(Succeed())
+			Expect(check("http://Metrics.Example.com:9090/api")).To(Succeed())
+			Expect(check("https://prometheus.example.org/api")).To(Succeed())
+		})
+
+		It("refuses the other hosts", func() {
+			Expect(check("https://example.org/api")).To(MatchError("host example.org is not allowed"))
+			Expect(check("https://evilexample.org/api")).To(MatchError("host evilexample.org is not allowed"))
+			Expect(check("https://metrics.example.com.evil.com/api")).To(MatchError("host metrics.example.com.evil.com is not allowed"))
+		})
+
+		It("refuses the schemes other than http and https", func() {
+			Expect(check("file://metrics.example.com/etc/passwd")).To(HaveOccurred())
+		})
+
+		Context("when no host is allowed", func() {
+			BeforeEach(func() {
+				conf.AllowedHosts = nil
+			})
+			It("refuses all hosts", func() {
+				Expect(check("https://metrics.example.com/api")).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("CheckIP", func() {
+		It("refuses the loopback, link-local and private addresses", func() {
+			for _, ip := range []string{"127.0.0.1", "::1", "169.254.169.254", "fe80::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "fd00::1", "0.0.0.0", "::ffff:10.1.2.3"} {
+				Expect(guard.CheckIP(net.ParseIP(ip))).To(HaveOccurred(), ip)
+			}
+		})
+
+		It("allows the public addresses", func() {
+			Expect(guard.CheckIP(net.ParseIP("93.184.216.34"))).To(Succeed())
+			Expect(guard.CheckIP(net.ParseIP("2606:2800:220:1::1"))).To(Succeed())
+		})
+
+		Context("when the network of a private address is allowed", func() {
+			BeforeEach(func() {
+				conf.AllowedNetworks = []string{"10.0.0.0/8"}
+			})
+			It("allows the address", func() {
+				Expect(guard.CheckIP(net.ParseIP("10.1.2.3"))).To(Succeed())
+				Expect(guard.CheckIP(net.ParseIP("192.168.1.1"))).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("NewHTTPClient", func() {
+		var (
+			server *ghttp.Server
+			resp   *http.Response
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			server.RouteToHandler(http.MethodGet, "/metrics", ghttp.RespondWith(http.StatusOK, "{}"))
+			server.RouteToHandler(http.MethodGet, "/redirect", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "http://localhost:"+server.URL()[len("http://127.0.0.1:"):]+"/metrics", http.StatusFound)
+			})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		Context("when the host resolves to a loopback address", func() {
+			It("refuses to connect", func() {
+				_, err = guard.NewHTTPClient().Get(server.URL() + "/metrics")
+				Expect(err).To(MatchError(ContainSubstring("address 127.0.0.1 is not allowed")))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		Context("when the network of the host is allowed", func() {
+			BeforeEach(func() {
+				conf.AllowedNetworks = []string{"127.0.0.0/8"}
+			})
+
+			It("connects", func() {
+				resp, err = guard.NewHTTPClient().Get(server.URL() + "/metrics")
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("refuses to follow a redirect to a host which is not allowed", func() {
+				_, err = guard.NewHTTPClient().Get(server.URL() + "/redirect")
+				Expect(err).To(MatchError(ContainSubstring("host localhost is not allowed")))
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+	})
+})
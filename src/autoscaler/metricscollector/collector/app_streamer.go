@@ -10,7 +10,6 @@ import (
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/sonde-go/events"
 
-	"fmt"
 	"time"
 )
 
@@ -123,7 +122,7 @@ func (as *appStreamer) computeAndSaveMetrics() {
 			CollectedAt:   as.sclock.Now().UnixNano(),
 			Name:          models.MetricNameThroughput,
 			Unit:          models.UnitRPS,
-			Value:         "0",
+			Value:         0,
 			Timestamp:     as.sclock.Now().UnixNano(),
 		}
 		as.logger.Debug("compute-throughput", lager.Data{"message": "write 0 throughput due to no requests"})
@@ -141,7 +140,7 @@ func (as *appStreamer) computeAndSaveMetrics() {
 			CollectedAt:   as.sclock.Now().UnixNano(),
 			Name:          models.MetricNameThroughput,
 			Unit:          models.UnitRPS,
-			Value:         fmt.Sprintf("%d", int(float64(numReq)/as.collectInterval.Seconds()+0.5)),
+			Value:         float64(numReq) / as.collectInterval.Seconds(),
 			Timestamp:     as.sclock.Now().UnixNano(),
 		}
 		as.logger.Debug("compute-throughput", lager.Data{"throughput": throughput})
@@ -152,7 +151,7 @@ func (as *appStreamer) computeAndSaveMetrics() {
 			CollectedAt:   as.sclock.Now().UnixNano(),
 			Name:          models.MetricNameResponseTime,
 			Unit:          models.UnitMilliseconds,
-			Value:         fmt.Sprintf("%d", as.sumReponseTimes[instanceIdx]/(numReq*1000*1000)),
+			Value:         float64(as.sumReponseTimes[instanceIdx]) / float64(numReq*1000*1000),
 			Timestamp:     as.sclock.Now().UnixNano(),
 		}
 		as.logger.Debug("compute-responsetime", lager.Data{"responsetime": responseTime})
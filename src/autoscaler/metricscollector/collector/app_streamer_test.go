@@ -78,7 +78,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUsed,
 						Unit:          models.UnitMegaBytes,
-						Value:         "95",
+						Value:         95.367431640625,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -87,7 +87,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "33",
+						Value:         33.33333333333333,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -96,7 +96,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameCPUUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "13",
+						Value:         12.8,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -105,7 +105,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUsed,
 						Unit:          models.UnitMegaBytes,
-						Value:         "191",
+						Value:         190.73486328125,
 						Timestamp:     222222,
 					},
 					&models.AppInstanceMetric{
@@ -114,7 +114,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "67",
+						Value:         66.66666666666666,
 						Timestamp:     222222,
 					},
 					&models.AppInstanceMetric{
@@ -123,7 +123,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameCPUUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "31",
+						Value:         30.6,
 						Timestamp:     222222,
 					},
 				}
@@ -168,7 +168,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "0",
+						Value:         0,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 
@@ -216,7 +216,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "0",
+						Value:         0,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 					Expect(<-dataChan).To(Equal(metric))
@@ -243,7 +243,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "2",
+						Value:         2,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 					metric2 := &models.AppInstanceMetric{
@@ -252,7 +252,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameResponseTime,
 						Unit:          models.UnitMilliseconds,
-						Value:         "200",
+						Value:         200,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 
@@ -276,7 +276,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "3",
+						Value:         3,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 					metric4 := &models.AppInstanceMetric{
@@ -285,7 +285,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameResponseTime,
 						Unit:          models.UnitMilliseconds,
-						Value:         "300",
+						Value:         300,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 
@@ -314,7 +314,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "2",
+						Value:         2,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 					metric2 := &models.AppInstanceMetric{
@@ -323,7 +323,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameResponseTime,
 						Unit:          models.UnitMilliseconds,
-						Value:         "200",
+						Value:         200,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 
@@ -346,7 +346,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameThroughput,
 						Unit:          models.UnitRPS,
-						Value:         "3",
+						Value:         3,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 					metric4 := &models.AppInstanceMetric{
@@ -355,7 +355,7 @@ var _ = Describe("AppStreamer", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameResponseTime,
 						Unit:          models.UnitMilliseconds,
-						Value:         "300",
+						Value:         300,
 						Timestamp:     fclock.Now().UnixNano(),
 					}
 
@@ -409,7 +409,7 @@ var _ = Describe("AppStreamer", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameThroughput,
 					Unit:          models.UnitRPS,
-					Value:         "0",
+					Value:         0,
 					Timestamp:     fclock.Now().UnixNano(),
 				}
 
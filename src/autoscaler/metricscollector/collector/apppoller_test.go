@@ -105,7 +105,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameMemoryUsed,
 							Unit:          models.UnitMegaBytes,
-							Value:         "95",
+							Value:         95.367431640625,
 							Timestamp:     111111,
 						}
 
@@ -115,7 +115,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameMemoryUtil,
 							Unit:          models.UnitPercentage,
-							Value:         "33",
+							Value:         33.33333333333333,
 							Timestamp:     111111,
 						}
 
@@ -125,7 +125,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameCPUUtil,
 							Unit:          models.UnitPercentage,
-							Value:         "12",
+							Value:         12.2,
 							Timestamp:     111111,
 						}
 
@@ -159,7 +159,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameMemoryUsed,
 							Unit:          models.UnitMegaBytes,
-							Value:         "95",
+							Value:         95.367431640625,
 							Timestamp:     111111,
 						}
 
@@ -169,7 +169,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameMemoryUtil,
 							Unit:          models.UnitPercentage,
-							Value:         "33",
+							Value:         33.33333333333333,
 							Timestamp:     111111,
 						}
 
@@ -179,7 +179,7 @@ var _ = Describe("Apppoller", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameCPUUtil,
 							Unit:          models.UnitPercentage,
-							Value:         "12",
+							Value:         12.2,
 							Timestamp:     111111,
 						}
 
@@ -269,7 +269,7 @@ var _ = Describe("Apppoller", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUsed,
 						Unit:          models.UnitMegaBytes,
-						Value:         "95",
+						Value:         95.367431640625,
 						Timestamp:     111111,
 					}
 					metric2 := &models.AppInstanceMetric{
@@ -278,7 +278,7 @@ var _ = Describe("Apppoller", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameMemoryUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "33",
+						Value:         33.33333333333333,
 						Timestamp:     111111,
 					}
 					metric3 := &models.AppInstanceMetric{
@@ -287,7 +287,7 @@ var _ = Describe("Apppoller", func() {
 						CollectedAt:   fclock.Now().UnixNano(),
 						Name:          models.MetricNameCPUUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "12",
+						Value:         12.2,
 						Timestamp:     111111,
 					}
 
@@ -321,7 +321,7 @@ var _ = Describe("Collector", func() {
 				CollectedAt:   2222,
 				Name:          models.MetricNameThroughput,
 				Unit:          models.UnitRPS,
-				Value:         "3",
+				Value:         3,
 				Timestamp:     2222,
 			}
 			metric2 = &models.AppInstanceMetric{
@@ -330,7 +330,7 @@ var _ = Describe("Collector", func() {
 				CollectedAt:   3333,
 				Name:          models.MetricNameResponseTime,
 				Unit:          models.UnitMilliseconds,
-				Value:         "300",
+				Value:         300,
 				Timestamp:     3333,
 			}
 			metric3 = &models.AppInstanceMetric{
@@ -339,7 +339,7 @@ var _ = Describe("Collector", func() {
 				CollectedAt:   1111,
 				Name:          models.MetricNameThroughput,
 				Unit:          models.UnitRPS,
-				Value:         "3",
+				Value:         3,
 				Timestamp:     1111,
 			}
 
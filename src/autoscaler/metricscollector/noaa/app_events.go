@@ -2,7 +2,6 @@ package noaa
 
 import (
 	"autoscaler/models"
-	"fmt"
 
 	"github.com/cloudfoundry/sonde-go/events"
 )
@@ -55,7 +54,7 @@ func GetMetricsFromContainerEnvelope(collectAt int64, appId string, event *event
 			CollectedAt:   collectAt,
 			Name:          models.MetricNameMemoryUsed,
 			Unit:          models.UnitMegaBytes,
-			Value:         fmt.Sprintf("%d", int(float64(cm.GetMemoryBytes())/(1024*1024)+0.5)),
+			Value:         float64(cm.GetMemoryBytes()) / (1024 * 1024),
 			Timestamp:     event.GetTimestamp(),
 		})
 
@@ -66,7 +65,7 @@ func GetMetricsFromContainerEnvelope(collectAt int64, appId string, event *event
 				CollectedAt:   collectAt,
 				Name:          models.MetricNameMemoryUtil,
 				Unit:          models.UnitPercentage,
-				Value:         fmt.Sprintf("%d", int(float64(cm.GetMemoryBytes())/float64(cm.GetMemoryBytesQuota())*100+0.5)),
+				Value:         float64(cm.GetMemoryBytes()) / float64(cm.GetMemoryBytesQuota()) * 100,
 				Timestamp:     event.GetTimestamp(),
 			})
 		}
@@ -76,7 +75,7 @@ func GetMetricsFromContainerEnvelope(collectAt int64, appId string, event *event
 			CollectedAt:   collectAt,
 			Name:          models.MetricNameCPUUtil,
 			Unit:          models.UnitPercentage,
-			Value:         fmt.Sprintf("%d", int(float64(cm.GetCpuPercentage()+0.5))),
+			Value:         cm.GetCpuPercentage(),
 			Timestamp:     event.GetTimestamp(),
 		})
 	}
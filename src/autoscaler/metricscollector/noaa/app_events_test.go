@@ -61,7 +61,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameMemoryUsed,
 						Unit:          models.UnitMegaBytes,
-						Value:         "95",
+						Value:         95.367431640625,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -70,7 +70,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameMemoryUsed,
 						Unit:          models.UnitMegaBytes,
-						Value:         "191",
+						Value:         190.73486328125,
 						Timestamp:     333333,
 					},
 					&models.AppInstanceMetric{
@@ -79,7 +79,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameMemoryUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "33",
+						Value:         33.33333333333333,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -88,7 +88,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameMemoryUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "67",
+						Value:         66.66666666666666,
 						Timestamp:     333333,
 					},
 					&models.AppInstanceMetric{
@@ -97,7 +97,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameCPUUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "12",
+						Value:         12.11,
 						Timestamp:     111111,
 					},
 					&models.AppInstanceMetric{
@@ -106,7 +106,7 @@ var _ = Describe("AppEvents", func() {
 						CollectedAt:   123456,
 						Name:          models.MetricNameCPUUtil,
 						Unit:          models.UnitPercentage,
-						Value:         "6",
+						Value:         5.711,
 						Timestamp:     333333,
 					},
 				))
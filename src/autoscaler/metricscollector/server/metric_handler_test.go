@@ -332,7 +332,7 @@ var _ = Describe("MetricHandler", func() {
 						CollectedAt:   111,
 						Name:          "a-metric-type",
 						Unit:          "metric-unit",
-						Value:         "12345678",
+						Value:         12345678,
 						Timestamp:     345,
 					}
 
@@ -342,7 +342,7 @@ var _ = Describe("MetricHandler", func() {
 						CollectedAt:   222,
 						Name:          "a-metric-type",
 						Unit:          "metric-unit",
-						Value:         "87654321",
+						Value:         87654321,
 						Timestamp:     456,
 					}
 					metrics = []*models.AppInstanceMetric{&metric1, &metric2}
@@ -6,6 +6,7 @@ import (
 	"autoscaler/healthendpoint"
 	helpers "autoscaler/helpers"
 	"autoscaler/metricsforwarder/config"
+	"autoscaler/metricsforwarder/ratelimiter"
 	"autoscaler/metricsforwarder/server"
 	"flag"
 	"fmt"
@@ -62,17 +63,20 @@ func main() {
 	}
 	defer policyDB.Close()
 
+	rateLimiter := ratelimiter.NewRateLimiter(logger, conf.RateLimit, mfClock)
+
 	httpStatusCollector := healthendpoint.NewHTTPStatusCollector("autoscaler", "metricsforwarder")
 	promRegistry := prometheus.NewRegistry()
 	healthendpoint.RegisterCollectors(promRegistry, []prometheus.Collector{
 		healthendpoint.NewDatabaseStatusCollector("autoscaler", "metricsforwarder", "policyDB", policyDB),
 		httpStatusCollector,
+		healthendpoint.NewRateLimitCollector("autoscaler", "metricsforwarder", rateLimiter),
 	}, true, logger.Session("metricsforwarder-prometheus"))
 
 	credentialCache := cache.New(conf.CacheTTL, conf.CacheCleanupInterval)
 	allowedMetricCache := cache.New(conf.CacheTTL, conf.CacheCleanupInterval)
 
-	httpServer, err := server.NewServer(logger.Session("custom_metrics_server"), conf, policyDB, *credentialCache, *allowedMetricCache, httpStatusCollector)
+	httpServer, err := server.NewServer(logger.Session("custom_metrics_server"), conf, policyDB, *credentialCache, *allowedMetricCache, httpStatusCollector, rateLimiter)
 	if err != nil {
 		logger.Error("failed-to-create-custommetrics-server", err)
 		os.Exit(1)
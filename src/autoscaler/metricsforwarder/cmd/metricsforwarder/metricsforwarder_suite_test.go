@@ -108,6 +108,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	cfg.LoggregatorConfig.TLS.CertFile = filepath.Join(testCertDir, "metron.crt")
 	cfg.LoggregatorConfig.TLS.KeyFile = filepath.Join(testCertDir, "metron.key")
 	cfg.LoggregatorConfig.MetronAddress = grpcIngressTestServer.GetAddr()
+	cfg.LoggregatorConfig.EmitQueueSize = 100
 
 	cfg.Logging.Level = "debug"
 
@@ -116,6 +117,15 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	cfg.Health.Port = healthport
 	cfg.CacheCleanupInterval = 10 * time.Minute
 	cfg.PolicyPollerInterval = 40 * time.Second
+	cfg.BatchMaxSize = 100
+	cfg.RateLimit = config.RateLimitConfig{
+		AppMetricsPerSecond:      100,
+		AppBurst:                 1000,
+		InstanceMetricsPerSecond: 20,
+		InstanceBurst:            200,
+		MaxMetricNamesPerApp:     100,
+		Expiration:               10 * time.Minute,
+	}
 	cfg.Db.PolicyDb = db.DatabaseConfig{
 		URL:                   os.Getenv("DBURL"),
 		MaxOpenConnections:    10,
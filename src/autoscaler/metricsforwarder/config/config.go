@@ -13,10 +13,19 @@ import (
 )
 
 const (
-	DefaultMetronAddress                      = "127.0.0.1:3458"
-	DefaultCacheTTL             time.Duration = 15 * time.Minute
-	DefaultCacheCleanupInterval time.Duration = 6 * time.Hour
-	DefaultPolicyPollerInterval time.Duration = 40 * time.Second
+	DefaultMetronAddress                          = "127.0.0.1:3458"
+	DefaultCacheTTL                 time.Duration = 15 * time.Minute
+	DefaultCacheCleanupInterval     time.Duration = 6 * time.Hour
+	DefaultPolicyPollerInterval     time.Duration = 40 * time.Second
+	DefaultBatchMaxSize                           = 1000
+	DefaultEmitQueueSize                          = 10000
+	DefaultAppMetricsPerSecond                    = 100
+	DefaultAppBurst                               = 1000
+	DefaultInstanceMetricsPerSecond               = 20
+	DefaultInstanceBurst                          = 200
+	DefaultMaxMetricNamesPerApp                   = 100
+	DefaultMaxInstancesPerApp                     = 2000
+	DefaultRateLimitExpiration      time.Duration = 10 * time.Minute
 )
 
 type Config struct {
@@ -27,11 +36,18 @@ type Config struct {
 	CacheTTL             time.Duration         `yaml:"cache_ttl"`
 	CacheCleanupInterval time.Duration         `yaml:"cache_cleanup_interval"`
 	PolicyPollerInterval time.Duration         `yaml:"policy_poller_interval"`
+	BatchMaxSize         int                   `yaml:"batch_max_size"`
+	RateLimit            RateLimitConfig       `yaml:"rate_limit"`
 	Health               models.HealthConfig   `yaml:"health"`
 }
 
+// ServerConfig is the server of the custom metrics. InstanceIdentityCAFile is the CA of the instance identity
+// certificates of the apps, the certificates are accepted instead of the credentials of the binding only when it is
+// set.
 type ServerConfig struct {
-	Port int `yaml:"port"`
+	Port                   int             `yaml:"port"`
+	TLS                    models.TLSCerts `yaml:"tls"`
+	InstanceIdentityCAFile string          `yaml:"instance_identity_ca_file"`
 }
 
 var defaultServerConfig = ServerConfig{
@@ -53,6 +69,30 @@ type LoggingConfig struct {
 type LoggregatorConfig struct {
 	MetronAddress string          `yaml:"metron_address"`
 	TLS           models.TLSCerts `yaml:"tls"`
+	EmitQueueSize int             `yaml:"emit_queue_size"`
+}
+
+// RateLimitConfig bounds the metrics an app can publish, a rate of 0 or a max of 0 disables the limit. MaxInstancesPerApp
+// bounds the instance indexes of an app which are rate limited at the same time. Expiration is how long a metric name
+// counts towards the max after it was published last, and how long the state of an idle app is kept.
+type RateLimitConfig struct {
+	AppMetricsPerSecond      float64       `yaml:"app_metrics_per_second"`
+	AppBurst                 int           `yaml:"app_burst"`
+	InstanceMetricsPerSecond float64       `yaml:"instance_metrics_per_second"`
+	InstanceBurst            int           `yaml:"instance_burst"`
+	MaxMetricNamesPerApp     int           `yaml:"max_metric_names_per_app"`
+	MaxInstancesPerApp       int           `yaml:"max_instances_per_app"`
+	Expiration               time.Duration `yaml:"expiration"`
+}
+
+var defaultRateLimitConfig = RateLimitConfig{
+	AppMetricsPerSecond:      DefaultAppMetricsPerSecond,
+	AppBurst:                 DefaultAppBurst,
+	InstanceMetricsPerSecond: DefaultInstanceMetricsPerSecond,
+	InstanceBurst:            DefaultInstanceBurst,
+	MaxMetricNamesPerApp:     DefaultMaxMetricNamesPerApp,
+	MaxInstancesPerApp:       DefaultMaxInstancesPerApp,
+	Expiration:               DefaultRateLimitExpiration,
 }
 
 type DbConfig struct {
@@ -66,11 +106,14 @@ func LoadConfig(reader io.Reader) (*Config, error) {
 		Logging: defaultLoggingConfig,
 		LoggregatorConfig: LoggregatorConfig{
 			MetronAddress: DefaultMetronAddress,
+			EmitQueueSize: DefaultEmitQueueSize,
 		},
 		Health:               defaultHealthConfig,
 		CacheTTL:             DefaultCacheTTL,
 		CacheCleanupInterval: DefaultCacheCleanupInterval,
 		PolicyPollerInterval: DefaultPolicyPollerInterval,
+		BatchMaxSize:         DefaultBatchMaxSize,
+		RateLimit:            defaultRateLimitConfig,
 	}
 
 	bytes, err := ioutil.ReadAll(reader)
@@ -100,6 +143,36 @@ func (c *Config) Validate() error {
 	if c.LoggregatorConfig.TLS.KeyFile == "" {
 		return fmt.Errorf("Configuration error: Loggregator ClientKey is empty")
 	}
+	if c.Server.InstanceIdentityCAFile != "" && (c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "") {
+		return fmt.Errorf("Configuration error: Server TLS cert_file and key_file are required to verify instance identity certificates")
+	}
+	if c.LoggregatorConfig.EmitQueueSize <= 0 {
+		return fmt.Errorf("Configuration error: Loggregator EmitQueueSize is less than or equal to 0")
+	}
+	if c.BatchMaxSize <= 0 {
+		return fmt.Errorf("Configuration error: BatchMaxSize is less than or equal to 0")
+	}
+	if c.RateLimit.AppMetricsPerSecond < 0 {
+		return fmt.Errorf("Configuration error: RateLimit AppMetricsPerSecond is less than 0")
+	}
+	if c.RateLimit.AppMetricsPerSecond > 0 && c.RateLimit.AppBurst <= 0 {
+		return fmt.Errorf("Configuration error: RateLimit AppBurst is less than or equal to 0")
+	}
+	if c.RateLimit.InstanceMetricsPerSecond < 0 {
+		return fmt.Errorf("Configuration error: RateLimit InstanceMetricsPerSecond is less than 0")
+	}
+	if c.RateLimit.InstanceMetricsPerSecond > 0 && c.RateLimit.InstanceBurst <= 0 {
+		return fmt.Errorf("Configuration error: RateLimit InstanceBurst is less than or equal to 0")
+	}
+	if c.RateLimit.MaxMetricNamesPerApp < 0 {
+		return fmt.Errorf("Configuration error: RateLimit MaxMetricNamesPerApp is less than 0")
+	}
+	if c.RateLimit.MaxInstancesPerApp < 0 {
+		return fmt.Errorf("Configuration error: RateLimit MaxInstancesPerApp is less than 0")
+	}
+	if c.RateLimit.Expiration <= 0 {
+		return fmt.Errorf("Configuration error: RateLimit Expiration is less than or equal to 0")
+	}
 	return nil
 
 }
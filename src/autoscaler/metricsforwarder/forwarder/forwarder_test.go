@@ -42,6 +42,7 @@ var _ = Describe("MetricForwarder", func() {
 		Expect(err).NotTo(HaveOccurred())
 		loggregatorConfig := config.LoggregatorConfig{
 			MetronAddress: grpcIngressTestServer.GetAddr(),
+			EmitQueueSize: 100,
 			TLS: models.TLSCerts{
 				KeyFile:    filepath.Join(testCertDir, "metron.key"),
 				CertFile:   filepath.Join(testCertDir, "metron.crt"),
@@ -94,6 +95,26 @@ var _ = Describe("MetricForwarder", func() {
 		})
 	})
 
+	Describe("EnqueueMetrics", func() {
+		var queued int
+
+		Context("when metrics with timestamps are enqueued", func() {
+			BeforeEach(func() {
+				queued = metricForwarder.EnqueueMetrics([]*models.CustomMetric{
+					{Name: "queuelength", Value: 12.5, Unit: "unit", InstanceIndex: 1, AppGUID: "dummy-guid", Timestamp: 1560000000000000000},
+				})
+			})
+
+			It("Should emit gauge metrics with the timestamps", func() {
+				Expect(queued).To(Equal(1))
+				env, err := getEnvelopeAt(grpcIngressTestServer.Receivers, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.Timestamp).To(Equal(int64(1560000000000000000)))
+				Expect(env.GetGauge().GetMetrics()["queuelength"].Value).To(Equal(12.5))
+			})
+		})
+	})
+
 	AfterEach(func() {
 		grpcIngressTestServer.Stop()
 	})
@@ -5,16 +5,22 @@ import (
 	"autoscaler/models"
 
 	"code.cloudfoundry.org/go-loggregator"
+	"code.cloudfoundry.org/go-loggregator/rpc/loggregator_v2"
 	"code.cloudfoundry.org/lager"
+	"github.com/golang/protobuf/proto"
 )
 
 type MetricForwarder interface {
 	EmitMetric(*models.CustomMetric)
+	// EnqueueMetrics queues the metrics to be emitted in the order of the queue and returns how many of them are
+	// queued, the metrics which do not fit in the queue are not queued.
+	EnqueueMetrics([]*models.CustomMetric) int
 }
 
 type metricForwarder struct {
 	client *loggregator.IngressClient
 	logger lager.Logger
+	queue  chan *models.CustomMetric
 }
 
 const METRICS_FORWARDER_ORIGIN = "autoscaler_metrics_forwarder"
@@ -41,10 +47,13 @@ func NewMetricForwarder(logger lager.Logger, conf *config.Config) (MetricForward
 		return &metricForwarder{}, err
 	}
 
-	return &metricForwarder{
+	mf := &metricForwarder{
 		client: client,
 		logger: logger,
-	}, nil
+		queue:  make(chan *models.CustomMetric, conf.LoggregatorConfig.EmitQueueSize),
+	}
+	go mf.emitQueuedMetrics()
+	return mf, nil
 }
 
 func (mf *metricForwarder) EmitMetric(metric *models.CustomMetric) {
@@ -54,5 +63,34 @@ func (mf *metricForwarder) EmitMetric(metric *models.CustomMetric) {
 		loggregator.WithGaugeAppInfo(metric.AppGUID, int(metric.InstanceIndex)),
 		loggregator.WithGaugeValue(metric.Name, metric.Value, metric.Unit),
 	}
+	if metric.Timestamp != 0 {
+		options = append(options, withGaugeTimestamp(metric.Timestamp))
+	}
 	mf.client.EmitGauge(options...)
 }
+
+func (mf *metricForwarder) EnqueueMetrics(metrics []*models.CustomMetric) int {
+	for i, metric := range metrics {
+		select {
+		case mf.queue <- metric:
+		default:
+			mf.logger.Info("emit-queue-is-full", lager.Data{"queued": i, "dropped": len(metrics) - i})
+			return i
+		}
+	}
+	return len(metrics)
+}
+
+func (mf *metricForwarder) emitQueuedMetrics() {
+	for metric := range mf.queue {
+		mf.EmitMetric(metric)
+	}
+}
+
+func withGaugeTimestamp(timestamp int64) loggregator.EmitGaugeOption {
+	return func(m proto.Message) {
+		if envelope, ok := m.(*loggregator_v2.Envelope); ok {
+			envelope.Timestamp = timestamp
+		}
+	}
+}
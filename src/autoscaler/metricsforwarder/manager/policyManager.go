@@ -99,14 +99,17 @@ func (pm *PolicyManager) computePolicies(policyJsons []*models.PolicyJson) map[s
 func (pm *PolicyManager) RefreshAllowedMetricCache(policies map[string]*models.AppPolicy) error {
 	pm.mLock.Lock()
 	defer pm.mLock.Unlock()
-	allowedMetricTypeSet := make(map[string]struct{})
 	allowedMetricMap := pm.allowedMetricCache.Items()
 	//Iterating over the cache and replace the allowed metrics for existing policy
 	for applicationId := range allowedMetricMap {
 		if policy, ok := policies[applicationId]; ok {
+			allowedMetricTypeSet := make(map[string]struct{})
 			scalingPolicy := policy.ScalingPolicy
-			for _, metrictype := range scalingPolicy.ScalingRules {
-				allowedMetricTypeSet[metrictype.MetricType] = struct{}{}
+			// the rules of the "rules" overrides of the schedules use custom metrics as well
+			for _, rule := range append(scalingPolicy.ScheduleOnlyRules(), scalingPolicy.ScalingRules...) {
+				for _, metricType := range rule.MetricTypes() {
+					allowedMetricTypeSet[metricType] = struct{}{}
+				}
 			}
 			err := pm.allowedMetricCache.Replace(applicationId, allowedMetricTypeSet, pm.cacheTTL)
 			if err != nil {
@@ -131,6 +131,45 @@ var _ = Describe("PolicyManager", func() {
 				Expect(maps).ShouldNot(HaveKey("queuelength"))
 			})
 		})
+		Context("when the policies have the rules of schedule overrides", func() {
+			BeforeEach(func() {
+				allowedMetricCache.Set(testAppId, allowedMetricTypeSet, 10*time.Minute)
+				allowedMetricCache.Set("another-app-id", map[string]struct{}{}, 10*time.Minute)
+
+				overridePolicyStr := `{
+				   "instance_min_count":1,
+				   "instance_max_count":5,
+				   "schedules":{
+				      "timezone":"UTC",
+				      "specific_date":[{
+				         "start_date_time":"2099-01-04T20:00",
+				         "end_date_time":"2099-02-19T23:15",
+				         "instance_min_count":2,
+				         "instance_max_count":5,
+				         "override":{"mode":"rules","scaling_rules":[{"metric_type":"override-metric-name","threshold":90,"operator":">","adjustment":"+2"}]}
+				      }]
+				   }
+				}`
+				database.RetrievePoliciesStub = func() ([]*models.PolicyJson, error) {
+					return []*models.PolicyJson{
+						{AppId: testAppId, PolicyStr: policyStr},
+						{AppId: "another-app-id", PolicyStr: overridePolicyStr},
+					}, nil
+				}
+			})
+			It("allows the metrics of the override rules of each app only", func() {
+				Eventually(database.RetrievePoliciesCallCount).Should(Equal(1))
+				clock.Increment(1 * testPolicyPollerInterval)
+				Eventually(database.RetrievePoliciesCallCount).Should(Equal(2))
+
+				Eventually(func() map[string]struct{} {
+					res, _ := allowedMetricCache.Get("another-app-id")
+					return res.(map[string]struct{})
+				}).Should(Equal(map[string]struct{}{"override-metric-name": {}}))
+				res, _ := allowedMetricCache.Get(testAppId)
+				Expect(res).To(Equal(map[string]struct{}{"test-metric-name": {}}))
+			})
+		})
 	})
 
 	Context("Stop", func() {
@@ -0,0 +1,140 @@
+package otlp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages below are the subset of the OTLP metrics protocol
+// (opentelemetry/proto/collector/metrics/v1/metrics_service.proto) which is mapped to custom metrics, the fields
+// which are not declared are skipped when a request is unmarshaled.
+
+type ExportMetricsServiceRequest struct {
+	ResourceMetrics []*ResourceMetrics `protobuf:"bytes,1,rep,name=resource_metrics,json=resourceMetrics" json:"resourceMetrics,omitempty"`
+}
+
+func (m *ExportMetricsServiceRequest) Reset()         { *m = ExportMetricsServiceRequest{} }
+func (m *ExportMetricsServiceRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportMetricsServiceRequest) ProtoMessage()    {}
+
+type ResourceMetrics struct {
+	Resource     *Resource       `protobuf:"bytes,1,opt,name=resource" json:"resource,omitempty"`
+	ScopeMetrics []*ScopeMetrics `protobuf:"bytes,2,rep,name=scope_metrics,json=scopeMetrics" json:"scopeMetrics,omitempty"`
+}
+
+func (m *ResourceMetrics) Reset()         { *m = ResourceMetrics{} }
+func (m *ResourceMetrics) String() string { return proto.CompactTextString(m) }
+func (*ResourceMetrics) ProtoMessage()    {}
+
+type Resource struct {
+	Attributes []*KeyValue `protobuf:"bytes,1,rep,name=attributes" json:"attributes,omitempty"`
+}
+
+func (m *Resource) Reset()         { *m = Resource{} }
+func (m *Resource) String() string { return proto.CompactTextString(m) }
+func (*Resource) ProtoMessage()    {}
+
+type KeyValue struct {
+	Key   string    `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value *AnyValue `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *KeyValue) Reset()         { *m = KeyValue{} }
+func (m *KeyValue) String() string { return proto.CompactTextString(m) }
+func (*KeyValue) ProtoMessage()    {}
+
+type AnyValue struct {
+	StringValue *string `protobuf:"bytes,1,opt,name=string_value,json=stringValue" json:"stringValue,omitempty"`
+	IntValue    *int64  `protobuf:"varint,3,opt,name=int_value,json=intValue" json:"intValue,omitempty"`
+}
+
+func (m *AnyValue) Reset()         { *m = AnyValue{} }
+func (m *AnyValue) String() string { return proto.CompactTextString(m) }
+func (*AnyValue) ProtoMessage()    {}
+
+func (m *AnyValue) UnmarshalJSON(data []byte) error {
+	var value struct {
+		StringValue *string    `json:"stringValue"`
+		IntValue    *jsonInt64 `json:"intValue"`
+	}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	m.StringValue = value.StringValue
+	m.IntValue = (*int64)(value.IntValue)
+	return nil
+}
+
+type ScopeMetrics struct {
+	Metrics []*Metric `protobuf:"bytes,2,rep,name=metrics" json:"metrics,omitempty"`
+}
+
+func (m *ScopeMetrics) Reset()         { *m = ScopeMetrics{} }
+func (m *ScopeMetrics) String() string { return proto.CompactTextString(m) }
+func (*ScopeMetrics) ProtoMessage()    {}
+
+type Metric struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Unit  string `protobuf:"bytes,3,opt,name=unit" json:"unit,omitempty"`
+	Gauge *Gauge `protobuf:"bytes,5,opt,name=gauge" json:"gauge,omitempty"`
+	Sum   *Sum   `protobuf:"bytes,7,opt,name=sum" json:"sum,omitempty"`
+}
+
+func (m *Metric) Reset()         { *m = Metric{} }
+func (m *Metric) String() string { return proto.CompactTextString(m) }
+func (*Metric) ProtoMessage()    {}
+
+type Gauge struct {
+	DataPoints []*NumberDataPoint `protobuf:"bytes,1,rep,name=data_points,json=dataPoints" json:"dataPoints,omitempty"`
+}
+
+func (m *Gauge) Reset()         { *m = Gauge{} }
+func (m *Gauge) String() string { return proto.CompactTextString(m) }
+func (*Gauge) ProtoMessage()    {}
+
+type Sum struct {
+	DataPoints []*NumberDataPoint `protobuf:"bytes,1,rep,name=data_points,json=dataPoints" json:"dataPoints,omitempty"`
+}
+
+func (m *Sum) Reset()         { *m = Sum{} }
+func (m *Sum) String() string { return proto.CompactTextString(m) }
+func (*Sum) ProtoMessage()    {}
+
+type NumberDataPoint struct {
+	AsDouble *float64 `protobuf:"fixed64,4,opt,name=as_double,json=asDouble" json:"asDouble,omitempty"`
+	AsInt    *int64   `protobuf:"fixed64,6,opt,name=as_int,json=asInt" json:"asInt,omitempty"`
+}
+
+func (m *NumberDataPoint) Reset()         { *m = NumberDataPoint{} }
+func (m *NumberDataPoint) String() string { return proto.CompactTextString(m) }
+func (*NumberDataPoint) ProtoMessage()    {}
+
+func (m *NumberDataPoint) UnmarshalJSON(data []byte) error {
+	var point struct {
+		AsDouble *float64   `json:"asDouble"`
+		AsInt    *jsonInt64 `json:"asInt"`
+	}
+	err := json.Unmarshal(data, &point)
+	if err != nil {
+		return err
+	}
+	m.AsDouble = point.AsDouble
+	m.AsInt = (*int64)(point.AsInt)
+	return nil
+}
+
+// jsonInt64 is a 64 bit integer of the OTLP JSON encoding, which is a decimal string or a number.
+type jsonInt64 int64
+
+func (i *jsonInt64) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = jsonInt64(value)
+	return nil
+}
@@ -0,0 +1,122 @@
+package otlp
+
+import (
+	"autoscaler/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+
+	// InstanceIndexAttribute is the resource attribute of the OpenTelemetry semantic conventions for the index of the
+	// app instance which reports the metrics.
+	InstanceIndexAttribute = "cloudfoundry.app.instance.id"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// UnmarshalMetricsRequest unmarshals an OTLP/HTTP export metrics request body encoded as protobuf or JSON according
+// to the content type.
+func UnmarshalMetricsRequest(contentType string, body []byte) (*ExportMetricsServiceRequest, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedContentType
+	}
+
+	request := &ExportMetricsServiceRequest{}
+	switch mediaType {
+	case ContentTypeProtobuf:
+		err = proto.Unmarshal(body, request)
+	case ContentTypeJSON:
+		err = json.Unmarshal(body, request)
+	default:
+		return nil, ErrUnsupportedContentType
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// CustomMetrics maps the data points of the gauges and sums in the request to custom metrics of the app, the other
+// metric types are ignored.
+func CustomMetrics(appID string, request *ExportMetricsServiceRequest) ([]*models.CustomMetric, error) {
+	metrics := []*models.CustomMetric{}
+	for _, resourceMetrics := range request.ResourceMetrics {
+		instanceIndex, err := resourceMetrics.Resource.instanceIndex()
+		if err != nil {
+			return nil, err
+		}
+		for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+			for _, metric := range scopeMetrics.Metrics {
+				for _, point := range metric.dataPoints() {
+					value, ok := point.value()
+					if !ok {
+						continue
+					}
+					metrics = append(metrics, &models.CustomMetric{
+						AppGUID:       appID,
+						InstanceIndex: instanceIndex,
+						Name:          metric.Name,
+						Value:         value,
+						Unit:          metric.Unit,
+					})
+				}
+			}
+		}
+	}
+	return metrics, nil
+}
+
+func (m *Resource) instanceIndex() (uint32, error) {
+	if m == nil {
+		return 0, nil
+	}
+	for _, attribute := range m.Attributes {
+		if attribute.Key != InstanceIndexAttribute || attribute.Value == nil {
+			continue
+		}
+		switch {
+		case attribute.Value.IntValue != nil:
+			if *attribute.Value.IntValue < 0 || *attribute.Value.IntValue > math.MaxUint32 {
+				return 0, fmt.Errorf("%s %d is not a valid instance index", InstanceIndexAttribute, *attribute.Value.IntValue)
+			}
+			return uint32(*attribute.Value.IntValue), nil
+		case attribute.Value.StringValue != nil:
+			index, err := strconv.ParseUint(*attribute.Value.StringValue, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("%s %s is not a valid instance index", InstanceIndexAttribute, *attribute.Value.StringValue)
+			}
+			return uint32(index), nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *Metric) dataPoints() []*NumberDataPoint {
+	switch {
+	case m.Gauge != nil:
+		return m.Gauge.DataPoints
+	case m.Sum != nil:
+		return m.Sum.DataPoints
+	}
+	return nil
+}
+
+func (m *NumberDataPoint) value() (float64, bool) {
+	switch {
+	case m.AsDouble != nil:
+		return *m.AsDouble, true
+	case m.AsInt != nil:
+		return float64(*m.AsInt), true
+	}
+	return 0, false
+}
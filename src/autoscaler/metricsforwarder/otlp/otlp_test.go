@@ -0,0 +1,170 @@
+package otlp_test
+
+import (
+	. "autoscaler/metricsforwarder/otlp"
+	"autoscaler/models"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OTLP", func() {
+	var (
+		contentType string
+		body        []byte
+		request     *ExportMetricsServiceRequest
+		err         error
+	)
+
+	float64Ptr := func(v float64) *float64 { return &v }
+	int64Ptr := func(v int64) *int64 { return &v }
+	stringPtr := func(v string) *string { return &v }
+
+	Describe("UnmarshalMetricsRequest", func() {
+		JustBeforeEach(func() {
+			request, err = UnmarshalMetricsRequest(contentType, body)
+		})
+
+		Context("when the request is encoded as protobuf", func() {
+			BeforeEach(func() {
+				contentType = "application/x-protobuf"
+				body, err = proto.Marshal(&ExportMetricsServiceRequest{
+					ResourceMetrics: []*ResourceMetrics{{
+						Resource: &Resource{Attributes: []*KeyValue{
+							{Key: "cloudfoundry.app.instance.id", Value: &AnyValue{StringValue: stringPtr("1")}},
+						}},
+						ScopeMetrics: []*ScopeMetrics{{
+							Metrics: []*Metric{
+								{Name: "queue_length", Unit: "{messages}", Gauge: &Gauge{DataPoints: []*NumberDataPoint{{AsDouble: float64Ptr(12.5)}}}},
+								{Name: "orders", Sum: &Sum{DataPoints: []*NumberDataPoint{{AsInt: int64Ptr(-3)}}}},
+							},
+						}},
+					}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("unmarshals the request", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(request.ResourceMetrics).To(HaveLen(1))
+				Expect(*request.ResourceMetrics[0].Resource.Attributes[0].Value.StringValue).To(Equal("1"))
+				metrics := request.ResourceMetrics[0].ScopeMetrics[0].Metrics
+				Expect(metrics).To(HaveLen(2))
+				Expect(*metrics[0].Gauge.DataPoints[0].AsDouble).To(Equal(12.5))
+				Expect(*metrics[1].Sum.DataPoints[0].AsInt).To(Equal(int64(-3)))
+			})
+		})
+
+		Context("when the request is encoded as JSON", func() {
+			BeforeEach(func() {
+				contentType = "application/json; charset=utf-8"
+				body = []byte(`{
+					"resourceMetrics": [{
+						"resource": {"attributes": [{"key": "cloudfoundry.app.instance.id", "value": {"intValue": "2"}}]},
+						"scopeMetrics": [{
+							"scope": {"name": "orders-service"},
+							"metrics": [
+								{"name": "queue_length", "unit": "{messages}", "gauge": {"dataPoints": [{"timeUnixNano": "1700000000000000000", "asDouble": 12.5}]}},
+								{"name": "orders", "sum": {"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": [{"asInt": "42"}, {"asInt": 43}]}}
+							]
+						}]
+					}]
+				}`)
+			})
+
+			It("unmarshals the request", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*request.ResourceMetrics[0].Resource.Attributes[0].Value.IntValue).To(Equal(int64(2)))
+				metrics := request.ResourceMetrics[0].ScopeMetrics[0].Metrics
+				Expect(*metrics[0].Gauge.DataPoints[0].AsDouble).To(Equal(12.5))
+				Expect(*metrics[1].Sum.DataPoints[0].AsInt).To(Equal(int64(42)))
+				Expect(*metrics[1].Sum.DataPoints[1].AsInt).To(Equal(int64(43)))
+			})
+		})
+
+		Context("when the JSON is invalid", func() {
+			BeforeEach(func() {
+				contentType = "application/json"
+				body = []byte(`{"resourceMetrics": [{"scopeMetrics": [{"metrics": [{"gauge": {"dataPoints": [{"asInt": "many"}]}}]}]}]}`)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when the content type is not supported", func() {
+			BeforeEach(func() {
+				contentType = "text/plain"
+				body = []byte("queue_length 12.5")
+			})
+
+			It("returns ErrUnsupportedContentType", func() {
+				Expect(err).To(Equal(ErrUnsupportedContentType))
+			})
+		})
+	})
+
+	Describe("CustomMetrics", func() {
+		var metrics []*models.CustomMetric
+
+		JustBeforeEach(func() {
+			metrics, err = CustomMetrics("an-app-id", request)
+		})
+
+		Context("when the request has gauges, sums and other metric types", func() {
+			BeforeEach(func() {
+				request = &ExportMetricsServiceRequest{
+					ResourceMetrics: []*ResourceMetrics{
+						{
+							Resource: &Resource{Attributes: []*KeyValue{
+								{Key: "service.name", Value: &AnyValue{StringValue: stringPtr("orders")}},
+								{Key: "cloudfoundry.app.instance.id", Value: &AnyValue{IntValue: int64Ptr(1)}},
+							}},
+							ScopeMetrics: []*ScopeMetrics{{
+								Metrics: []*Metric{
+									{Name: "queue_length", Unit: "{messages}", Gauge: &Gauge{DataPoints: []*NumberDataPoint{{AsDouble: float64Ptr(12.5)}}}},
+									{Name: "orders", Sum: &Sum{DataPoints: []*NumberDataPoint{{AsInt: int64Ptr(42)}, {}}}},
+									{Name: "latency"},
+								},
+							}},
+						},
+						{
+							ScopeMetrics: []*ScopeMetrics{{
+								Metrics: []*Metric{
+									{Name: "queue_length", Unit: "{messages}", Gauge: &Gauge{DataPoints: []*NumberDataPoint{{AsDouble: float64Ptr(7)}}}},
+								},
+							}},
+						},
+					},
+				}
+			})
+
+			It("maps the data points of the gauges and sums to custom metrics", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metrics).To(Equal([]*models.CustomMetric{
+					{AppGUID: "an-app-id", InstanceIndex: 1, Name: "queue_length", Value: 12.5, Unit: "{messages}"},
+					{AppGUID: "an-app-id", InstanceIndex: 1, Name: "orders", Value: 42},
+					{AppGUID: "an-app-id", InstanceIndex: 0, Name: "queue_length", Value: 7, Unit: "{messages}"},
+				}))
+			})
+		})
+
+		Context("when the instance index attribute is invalid", func() {
+			BeforeEach(func() {
+				request = &ExportMetricsServiceRequest{
+					ResourceMetrics: []*ResourceMetrics{{
+						Resource: &Resource{Attributes: []*KeyValue{
+							{Key: "cloudfoundry.app.instance.id", Value: &AnyValue{StringValue: stringPtr("first")}},
+						}},
+					}},
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("cloudfoundry.app.instance.id first is not a valid instance index"))
+			})
+		})
+	})
+})
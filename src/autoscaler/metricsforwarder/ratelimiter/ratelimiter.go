@@ -0,0 +1,304 @@
+package ratelimiter
+
+import (
+	"autoscaler/healthendpoint"
+	"autoscaler/metricsforwarder/config"
+	"autoscaler/models"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	LimitAppRate      = "app_rate"
+	LimitInstanceRate = "instance_rate"
+	LimitMetricNames  = "metric_names"
+	LimitInstances    = "instances"
+)
+
+// LimitExceededError is returned when the metrics of a request exceed a limit, the request can be retried after
+// RetryAfter.
+type LimitExceededError struct {
+	Limit      string
+	RetryAfter time.Duration
+	message    string
+}
+
+func (e *LimitExceededError) Error() string {
+	return e.message
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// wait returns how long it takes to have n tokens in the bucket. A full bucket is enough for more tokens than the
+// burst, the tokens which are taken beyond the burst are paid back before the next request.
+func (b *tokenBucket) wait(n int) time.Duration {
+	needed := math.Min(float64(n), b.burst)
+	if b.tokens >= needed {
+		return 0
+	}
+	return time.Duration((needed - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(n int) {
+	b.tokens -= float64(n)
+}
+
+func (b *tokenBucket) full() bool {
+	return b.tokens >= b.burst
+}
+
+type appState struct {
+	bucket          *tokenBucket
+	instanceBuckets map[uint32]*tokenBucket
+	// the last time every metric name was published
+	metricNames map[string]time.Time
+	lastSeen    time.Time
+}
+
+// RateLimiter limits the rate of the metrics of every app and of every app instance with token buckets, and the number
+// of metric names every app can publish.
+type RateLimiter struct {
+	logger    lager.Logger
+	conf      config.RateLimitConfig
+	clock     clock.Clock
+	lock      sync.Mutex
+	apps      map[string]*appState
+	lastSweep time.Time
+	rejected  map[string]int64
+}
+
+func NewRateLimiter(logger lager.Logger, conf config.RateLimitConfig, clock clock.Clock) *RateLimiter {
+	return &RateLimiter{
+		logger:    logger.Session("rate-limiter"),
+		conf:      conf,
+		clock:     clock,
+		apps:      map[string]*appState{},
+		lastSweep: clock.Now(),
+		rejected:  map[string]int64{},
+	}
+}
+
+// Allow takes the tokens for the metrics of an app from the buckets of the app and of its instances. Nothing is taken
+// when a limit is exceeded, a *LimitExceededError is returned instead.
+func (rl *RateLimiter) Allow(appID string, metrics []*models.CustomMetric) error {
+	now := rl.clock.Now()
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rl.sweep(now)
+	app, found := rl.apps[appID]
+	if !found {
+		app = &appState{
+			instanceBuckets: map[uint32]*tokenBucket{},
+			metricNames:     map[string]time.Time{},
+		}
+		if rl.conf.AppMetricsPerSecond > 0 {
+			app.bucket = newTokenBucket(rl.conf.AppMetricsPerSecond, rl.conf.AppBurst, now)
+		}
+		rl.apps[appID] = app
+	}
+	app.lastSeen = now
+
+	err := rl.checkMetricNames(appID, app, metrics, now)
+	if err == nil {
+		err = rl.checkRates(appID, app, metrics, now)
+	}
+	if err != nil {
+		limitErr := err.(*LimitExceededError)
+		rl.rejected[limitErr.Limit]++
+		rl.logger.Info("limit-exceeded", lager.Data{"appid": appID, "limit": limitErr.Limit, "retryAfter": limitErr.RetryAfter})
+		return err
+	}
+
+	for _, metric := range metrics {
+		app.metricNames[metric.Name] = now
+	}
+	return nil
+}
+
+func (rl *RateLimiter) checkMetricNames(appID string, app *appState, metrics []*models.CustomMetric, now time.Time) error {
+	if rl.conf.MaxMetricNamesPerApp <= 0 {
+		return nil
+	}
+	oldest := now
+	for name, lastSeen := range app.metricNames {
+		if now.Sub(lastSeen) >= rl.conf.Expiration {
+			delete(app.metricNames, name)
+			continue
+		}
+		if lastSeen.Before(oldest) {
+			oldest = lastSeen
+		}
+	}
+
+	newNames := map[string]struct{}{}
+	for _, metric := range metrics {
+		if _, exists := app.metricNames[metric.Name]; !exists {
+			newNames[metric.Name] = struct{}{}
+		}
+	}
+	if len(app.metricNames)+len(newNames) <= rl.conf.MaxMetricNamesPerApp {
+		return nil
+	}
+	return &LimitExceededError{
+		Limit:      LimitMetricNames,
+		RetryAfter: oldest.Add(rl.conf.Expiration).Sub(now),
+		message:    fmt.Sprintf("App %s exceeds the limit of %d metric names", appID, rl.conf.MaxMetricNamesPerApp),
+	}
+}
+
+func (rl *RateLimiter) checkRates(appID string, app *appState, metrics []*models.CustomMetric, now time.Time) error {
+	if app.bucket != nil {
+		app.bucket.refill(now)
+		if wait := app.bucket.wait(len(metrics)); wait > 0 {
+			return &LimitExceededError{
+				Limit:      LimitAppRate,
+				RetryAfter: wait,
+				message:    fmt.Sprintf("App %s exceeds the rate limit of %g metrics per second", appID, rl.conf.AppMetricsPerSecond),
+			}
+		}
+	}
+
+	instanceCounts := map[uint32]int{}
+	if rl.conf.InstanceMetricsPerSecond > 0 {
+		for _, metric := range metrics {
+			instanceCounts[metric.InstanceIndex]++
+		}
+	}
+	err := rl.checkInstances(appID, app, instanceCounts, now)
+	if err != nil {
+		return err
+	}
+	for instanceIndex, count := range instanceCounts {
+		bucket, found := app.instanceBuckets[instanceIndex]
+		if !found {
+			bucket = newTokenBucket(rl.conf.InstanceMetricsPerSecond, rl.conf.InstanceBurst, now)
+			app.instanceBuckets[instanceIndex] = bucket
+		}
+		bucket.refill(now)
+		if wait := bucket.wait(count); wait > 0 {
+			return &LimitExceededError{
+				Limit:      LimitInstanceRate,
+				RetryAfter: wait,
+				message: fmt.Sprintf("Instance %d of app %s exceeds the rate limit of %g metrics per second",
+					instanceIndex, appID, rl.conf.InstanceMetricsPerSecond),
+			}
+		}
+	}
+
+	if app.bucket != nil {
+		app.bucket.take(len(metrics))
+	}
+	for instanceIndex, count := range instanceCounts {
+		app.instanceBuckets[instanceIndex].take(count)
+	}
+	return nil
+}
+
+// checkInstances bounds the instances of an app which have a bucket, as the instance indexes are not verified. The full
+// buckets are dropped first, a full bucket is the same as a new one.
+func (rl *RateLimiter) checkInstances(appID string, app *appState, instanceCounts map[uint32]int, now time.Time) error {
+	if rl.conf.MaxInstancesPerApp <= 0 || rl.countInstances(app, instanceCounts) <= rl.conf.MaxInstancesPerApp {
+		return nil
+	}
+
+	var retryAfter time.Duration
+	for instanceIndex, bucket := range app.instanceBuckets {
+		bucket.refill(now)
+		if bucket.full() {
+			delete(app.instanceBuckets, instanceIndex)
+			continue
+		}
+		if wait := bucket.wait(rl.conf.InstanceBurst); retryAfter == 0 || wait < retryAfter {
+			retryAfter = wait
+		}
+	}
+	if rl.countInstances(app, instanceCounts) <= rl.conf.MaxInstancesPerApp {
+		return nil
+	}
+	return &LimitExceededError{
+		Limit:      LimitInstances,
+		RetryAfter: retryAfter,
+		message:    fmt.Sprintf("App %s exceeds the limit of %d instances", appID, rl.conf.MaxInstancesPerApp),
+	}
+}
+
+// countInstances returns the number of instances of an app which have a bucket once the metrics are allowed.
+func (rl *RateLimiter) countInstances(app *appState, instanceCounts map[uint32]int) int {
+	count := len(app.instanceBuckets)
+	for instanceIndex := range instanceCounts {
+		if _, found := app.instanceBuckets[instanceIndex]; !found {
+			count++
+		}
+	}
+	return count
+}
+
+// sweep drops the full buckets and the state of the apps which have not published metrics for the expiration, at
+// most once per expiration.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.conf.Expiration {
+		return
+	}
+	rl.lastSweep = now
+	for appID, app := range rl.apps {
+		if now.Sub(app.lastSeen) >= rl.conf.Expiration {
+			delete(rl.apps, appID)
+			continue
+		}
+		for instanceIndex, bucket := range app.instanceBuckets {
+			bucket.refill(now)
+			if bucket.full() {
+				delete(app.instanceBuckets, instanceIndex)
+			}
+		}
+	}
+}
+
+func (rl *RateLimiter) GetRateLimitStatus() *healthendpoint.RateLimitStatus {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rejected := make(map[string]int64, len(rl.rejected))
+	for limit, count := range rl.rejected {
+		rejected[limit] = count
+	}
+	return &healthendpoint.RateLimitStatus{
+		AppMetricsPerSecond:      rl.conf.AppMetricsPerSecond,
+		AppBurst:                 rl.conf.AppBurst,
+		InstanceMetricsPerSecond: rl.conf.InstanceMetricsPerSecond,
+		InstanceBurst:            rl.conf.InstanceBurst,
+		MaxMetricNamesPerApp:     rl.conf.MaxMetricNamesPerApp,
+		MaxInstancesPerApp:       rl.conf.MaxInstancesPerApp,
+		Apps:                     len(rl.apps),
+		RejectedRequests:         rejected,
+	}
+}
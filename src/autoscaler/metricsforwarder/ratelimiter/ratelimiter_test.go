@@ -0,0 +1,198 @@
+package ratelimiter_test
+
+import (
+	"autoscaler/metricsforwarder/config"
+	. "autoscaler/metricsforwarder/ratelimiter"
+	"autoscaler/models"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var (
+		rateLimiter *RateLimiter
+		conf        config.RateLimitConfig
+		clock       *fakeclock.FakeClock
+		err         error
+	)
+
+	metricsOf := func(instanceIndex uint32, names ...string) []*models.CustomMetric {
+		metrics := []*models.CustomMetric{}
+		for _, name := range names {
+			metrics = append(metrics, &models.CustomMetric{AppGUID: "an-app-id", InstanceIndex: instanceIndex, Name: name, Value: 1})
+		}
+		return metrics
+	}
+
+	expectLimitExceeded := func(err error, limit string, retryAfter time.Duration) {
+		Expect(err).To(BeAssignableToTypeOf(&LimitExceededError{}))
+		limitErr := err.(*LimitExceededError)
+		Expect(limitErr.Limit).To(Equal(limit))
+		Expect(limitErr.RetryAfter).To(Equal(retryAfter))
+	}
+
+	BeforeEach(func() {
+		clock = fakeclock.NewFakeClock(time.Now())
+		conf = config.RateLimitConfig{
+			AppMetricsPerSecond:      2,
+			AppBurst:                 4,
+			InstanceMetricsPerSecond: 1,
+			InstanceBurst:            3,
+			MaxMetricNamesPerApp:     3,
+			MaxInstancesPerApp:       3,
+			Expiration:               10 * time.Minute,
+		}
+	})
+
+	JustBeforeEach(func() {
+		rateLimiter = NewRateLimiter(lager.NewLogger("ratelimiter-test"), conf, clock)
+	})
+
+	Context("when the metrics are within the limits", func() {
+		It("allows them", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b"))).To(Succeed())
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a", "c"))).To(Succeed())
+			Expect(rateLimiter.Allow("another-app-id", metricsOf(0, "a", "b", "c"))).To(Succeed())
+		})
+	})
+
+	Context("when an app exceeds its rate limit", func() {
+		JustBeforeEach(func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b"))).To(Succeed())
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a"))).To(Succeed())
+			err = rateLimiter.Allow("an-app-id", metricsOf(2, "a", "b"))
+		})
+
+		It("rejects the metrics until there are enough tokens", func() {
+			expectLimitExceeded(err, LimitAppRate, 500*time.Millisecond)
+			Expect(err).To(MatchError("App an-app-id exceeds the rate limit of 2 metrics per second"))
+
+			clock.Increment(500 * time.Millisecond)
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(2, "a", "b"))).To(Succeed())
+		})
+
+		It("does not limit the other apps", func() {
+			Expect(rateLimiter.Allow("another-app-id", metricsOf(0, "a", "b"))).To(Succeed())
+		})
+	})
+
+	Context("when the metrics of a request are more than the burst", func() {
+		BeforeEach(func() {
+			conf.InstanceMetricsPerSecond = 0
+			conf.MaxMetricNamesPerApp = 0
+		})
+
+		It("allows them when the bucket is full and waits for the tokens beyond the burst", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "a", "a", "a", "a", "a"))).To(Succeed())
+			expectLimitExceeded(rateLimiter.Allow("an-app-id", metricsOf(0, "a")), LimitAppRate, 1500*time.Millisecond)
+		})
+	})
+
+	Context("when an app instance exceeds its rate limit", func() {
+		JustBeforeEach(func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b", "c"))).To(Succeed())
+			err = rateLimiter.Allow("an-app-id", metricsOf(0, "a"))
+		})
+
+		It("rejects the metrics of the instance", func() {
+			expectLimitExceeded(err, LimitInstanceRate, time.Second)
+			Expect(err).To(MatchError("Instance 0 of app an-app-id exceeds the rate limit of 1 metrics per second"))
+		})
+
+		It("does not take tokens from the app", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a"))).To(Succeed())
+		})
+	})
+
+	Context("when an app exceeds the max instances", func() {
+		BeforeEach(func() {
+			conf.MaxInstancesPerApp = 2
+		})
+
+		JustBeforeEach(func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b"))).To(Succeed())
+			clock.Increment(500 * time.Millisecond)
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a"))).To(Succeed())
+			err = rateLimiter.Allow("an-app-id", metricsOf(2, "a"))
+		})
+
+		It("rejects the new instances until the bucket of an instance is full", func() {
+			expectLimitExceeded(err, LimitInstances, time.Second)
+			Expect(err).To(MatchError("App an-app-id exceeds the limit of 2 instances"))
+
+			clock.Increment(time.Second)
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(2, "a"))).To(Succeed())
+		})
+
+		It("allows the known instances", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a"))).To(Succeed())
+		})
+	})
+
+	Context("when an app exceeds the max metric names", func() {
+		JustBeforeEach(func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b"))).To(Succeed())
+			clock.Increment(2 * time.Minute)
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "c"))).To(Succeed())
+			clock.Increment(time.Minute)
+			err = rateLimiter.Allow("an-app-id", metricsOf(1, "d"))
+		})
+
+		It("rejects the new metric names until the oldest name expires", func() {
+			expectLimitExceeded(err, LimitMetricNames, 7*time.Minute)
+			Expect(err).To(MatchError("App an-app-id exceeds the limit of 3 metric names"))
+
+			clock.Increment(7 * time.Minute)
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "d"))).To(Succeed())
+		})
+
+		It("allows the known metric names", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "a"))).To(Succeed())
+		})
+	})
+
+	Context("when the limits are disabled", func() {
+		BeforeEach(func() {
+			conf.AppMetricsPerSecond = 0
+			conf.InstanceMetricsPerSecond = 0
+			conf.MaxMetricNamesPerApp = 0
+			conf.MaxInstancesPerApp = 0
+		})
+
+		It("allows all the metrics", func() {
+			for i := 0; i < 10; i++ {
+				Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b", "c", "d"))).To(Succeed())
+			}
+		})
+	})
+
+	Describe("GetRateLimitStatus", func() {
+		It("returns the limits, the apps and the rejected requests", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a", "b", "c"))).To(Succeed())
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a"))).NotTo(Succeed())
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(1, "d"))).NotTo(Succeed())
+			Expect(rateLimiter.Allow("another-app-id", metricsOf(0, "a"))).To(Succeed())
+
+			status := rateLimiter.GetRateLimitStatus()
+			Expect(status.AppMetricsPerSecond).To(Equal(2.0))
+			Expect(status.AppBurst).To(Equal(4))
+			Expect(status.InstanceMetricsPerSecond).To(Equal(1.0))
+			Expect(status.InstanceBurst).To(Equal(3))
+			Expect(status.MaxMetricNamesPerApp).To(Equal(3))
+			Expect(status.MaxInstancesPerApp).To(Equal(3))
+			Expect(status.Apps).To(Equal(2))
+			Expect(status.RejectedRequests).To(Equal(map[string]int64{LimitInstanceRate: 1, LimitMetricNames: 1}))
+		})
+
+		It("drops the apps which have not published metrics for the expiration", func() {
+			Expect(rateLimiter.Allow("an-app-id", metricsOf(0, "a"))).To(Succeed())
+			clock.Increment(10 * time.Minute)
+			Expect(rateLimiter.Allow("another-app-id", metricsOf(0, "a"))).To(Succeed())
+			Expect(rateLimiter.GetRateLimitStatus().Apps).To(Equal(1))
+		})
+	})
+})
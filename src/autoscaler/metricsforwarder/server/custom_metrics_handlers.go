@@ -3,11 +3,21 @@ package server
 import (
 	"autoscaler/db"
 	"autoscaler/metricsforwarder/forwarder"
+	"autoscaler/metricsforwarder/otlp"
+	"autoscaler/metricsforwarder/ratelimiter"
 	"autoscaler/models"
+	"compress/gzip"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/cfhttp/handlers"
@@ -21,24 +31,309 @@ type CustomMetricsHandler struct {
 	policyDB           db.PolicyDB
 	credentialCache    cache.Cache
 	allowedMetricCache cache.Cache
+	instanceIndexCache cache.Cache
+	instanceIndexLock  sync.Mutex
 	cacheTTL           time.Duration
+	batchMaxSize       int
+	rateLimiter        *ratelimiter.RateLimiter
 	logger             lager.Logger
+	// instanceIdentityEnabled is whether the instance identity certificates are verified by the server, the client
+	// certificates are ignored when they are not
+	instanceIdentityEnabled bool
 }
 
-func NewCustomMetricsHandler(logger lager.Logger, metricForwarder forwarder.MetricForwarder, policyDB db.PolicyDB, credentialCache cache.Cache, allowedMetricCache cache.Cache, cacheTTL time.Duration) *CustomMetricsHandler {
+var standardMetricsTypes = map[string]struct{}{
+	models.MetricNameMemoryUsed:   {},
+	models.MetricNameMemoryUtil:   {},
+	models.MetricNameThroughput:   {},
+	models.MetricNameResponseTime: {},
+	models.MetricNameCPUUtil:      {},
+}
+
+func NewCustomMetricsHandler(logger lager.Logger, metricForwarder forwarder.MetricForwarder, policyDB db.PolicyDB, credentialCache cache.Cache, allowedMetricCache cache.Cache, cacheTTL time.Duration, batchMaxSize int, rateLimiter *ratelimiter.RateLimiter, instanceIdentityEnabled bool) *CustomMetricsHandler {
 	return &CustomMetricsHandler{
-		metricForwarder:    metricForwarder,
-		policyDB:           policyDB,
-		credentialCache:    credentialCache,
-		allowedMetricCache: allowedMetricCache,
-		cacheTTL:           cacheTTL,
-		logger:             logger,
+		metricForwarder:         metricForwarder,
+		policyDB:                policyDB,
+		credentialCache:         credentialCache,
+		allowedMetricCache:      allowedMetricCache,
+		instanceIndexCache:      *cache.New(cacheTTL, cacheTTL),
+		cacheTTL:                cacheTTL,
+		batchMaxSize:            batchMaxSize,
+		rateLimiter:             rateLimiter,
+		logger:                  logger,
+		instanceIdentityEnabled: instanceIdentityEnabled,
 	}
 }
 
 func (mh *CustomMetricsHandler) PublishMetrics(w http.ResponseWriter, r *http.Request, vars map[string]string) {
 	w.Header().Set("Content-Type", "application/json")
 
+	appID := vars["appid"]
+	identity, ok := mh.authenticate(w, r, appID)
+	if !ok {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		mh.logger.Error("error-reading-request-body", err, lager.Data{"body": r.Body})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error reading custom metrics request body"})
+		return
+	}
+	var metricsConsumer *models.MetricsConsumer
+	err = json.Unmarshal(body, &metricsConsumer)
+	if err != nil {
+		mh.logger.Error("error-unmarshaling-metrics", err, lager.Data{"body": r.Body})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Error unmarshaling custom metrics request body"})
+		return
+	}
+	err = mh.verifyInstance(identity, metricsConsumer.AppGUID, metricsConsumer.InstanceIndex)
+	if err != nil {
+		mh.writeInstanceIdentityError(w, identity, err)
+		return
+	}
+	err = mh.validateCustomMetricTypes(appID, metricsConsumer.CustomMetrics)
+	if err != nil {
+		mh.logger.Error("failed-validating-metrictypes", err, lager.Data{"metrics": metricsConsumer})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: err.Error()})
+		return
+	}
+
+	metrics := mh.getMetrics(appID, metricsConsumer)
+
+	if len(metrics) <= 0 {
+		mh.logger.Debug("failed-parsing-custom-metrics-request-body", lager.Data{"metrics": metrics})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Error parsing request body"})
+		return
+	}
+
+	if !mh.allowMetrics(w, appID, metrics) {
+		return
+	}
+
+	mh.logger.Debug("custom-metrics-parsed-successfully", lager.Data{"metrics": metrics})
+	for _, metric := range metrics {
+		mh.metricForwarder.EmitMetric(metric)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// PublishMetricsBatch validates every metric of a batch separately and queues the valid ones to be emitted, the
+// response has the result of every metric.
+func (mh *CustomMetricsHandler) PublishMetricsBatch(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	appID := vars["appid"]
+	identity, ok := mh.authenticate(w, r, appID)
+	if !ok {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		mh.logger.Error("error-reading-request-body", err)
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error reading custom metrics request body"})
+		return
+	}
+	batch := &models.CustomMetricsBatch{}
+	err = json.Unmarshal(body, batch)
+	if err != nil {
+		mh.logger.Error("error-unmarshaling-metrics-batch", err)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Error unmarshaling custom metrics batch request body"})
+		return
+	}
+	if len(batch.CustomMetrics) == 0 {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Custom metrics batch is empty"})
+		return
+	}
+	if len(batch.CustomMetrics) > mh.batchMaxSize {
+		mh.logger.Info("metrics-batch-too-large", lager.Data{"appid": appID, "size": len(batch.CustomMetrics)})
+		handlers.WriteJSONResponse(w, http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Code:    "Request-Entity-Too-Large",
+			Message: fmt.Sprintf("Custom metrics batch has %d metrics, the maximum is %d", len(batch.CustomMetrics), mh.batchMaxSize)})
+		return
+	}
+
+	allowedMetricTypeSet, err := mh.getAllowedMetricTypes(appID)
+	if err != nil {
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: err.Error()})
+		return
+	}
+
+	now := time.Now().UnixNano()
+	result := &models.CustomMetricsBatchResult{Results: make([]*models.CustomMetricResult, len(batch.CustomMetrics))}
+	metrics := []*models.CustomMetric{}
+	metricIndexes := []int{}
+	for i, metric := range batch.CustomMetrics {
+		if metric == nil {
+			result.Results[i] = &models.CustomMetricResult{Error: "Custom Metric is empty"}
+			continue
+		}
+		err = mh.verifyInstance(identity, metric.AppGUID, metric.InstanceIndex)
+		if err == nil {
+			err = mh.validateCustomMetricType(metric.Name, allowedMetricTypeSet)
+		}
+		if err == nil && metric.Timestamp < 0 {
+			err = fmt.Errorf("Custom Metric: %s has an invalid timestamp %d", metric.Name, metric.Timestamp)
+		}
+		if err != nil {
+			result.Results[i] = &models.CustomMetricResult{Error: err.Error()}
+			continue
+		}
+		timestamp := metric.Timestamp
+		if timestamp == 0 {
+			timestamp = now
+		}
+		metrics = append(metrics, &models.CustomMetric{
+			AppGUID:       appID,
+			InstanceIndex: metric.InstanceIndex,
+			Name:          metric.Name,
+			Value:         metric.Value,
+			Unit:          metric.Unit,
+			Timestamp:     timestamp,
+		})
+		metricIndexes = append(metricIndexes, i)
+	}
+
+	if !mh.allowMetrics(w, appID, metrics) {
+		return
+	}
+
+	queued := mh.metricForwarder.EnqueueMetrics(metrics)
+	for n, i := range metricIndexes {
+		if n < queued {
+			result.Results[i] = &models.CustomMetricResult{Accepted: true}
+		} else {
+			result.Results[i] = &models.CustomMetricResult{Error: "Custom metrics forwarder is busy, retry later"}
+		}
+	}
+	for _, metricResult := range result.Results {
+		if metricResult.Accepted {
+			result.Accepted++
+		} else {
+			result.Rejected++
+		}
+	}
+
+	mh.logger.Debug("custom-metrics-batch-processed", lager.Data{"appid": appID, "accepted": result.Accepted, "rejected": result.Rejected})
+	handlers.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// PublishOTLPMetrics receives the metrics of an OTLP/HTTP exporter, the data points of its gauges and sums are
+// forwarded as custom metrics.
+func (mh *CustomMetricsHandler) PublishOTLPMetrics(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	appID := vars["appid"]
+	identity, ok := mh.authenticate(w, r, appID)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			mh.logger.Error("error-decompressing-otlp-request-body", err)
+			handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "Bad-Request",
+				Message: "Error decompressing OTLP metrics request body"})
+			return
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		mh.logger.Error("error-reading-otlp-request-body", err)
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error reading OTLP metrics request body"})
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	request, err := otlp.UnmarshalMetricsRequest(contentType, body)
+	if err == otlp.ErrUnsupportedContentType {
+		mh.logger.Info("unsupported-otlp-content-type", lager.Data{"contentType": contentType})
+		handlers.WriteJSONResponse(w, http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Code:    "Unsupported-Media-Type",
+			Message: "Content-Type must be " + otlp.ContentTypeProtobuf + " or " + otlp.ContentTypeJSON})
+		return
+	}
+	if err != nil {
+		mh.logger.Error("error-unmarshaling-otlp-metrics", err)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Error unmarshaling OTLP metrics request body"})
+		return
+	}
+
+	metrics, err := otlp.CustomMetrics(appID, request)
+	if err != nil {
+		mh.logger.Error("error-mapping-otlp-metrics", err)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: err.Error()})
+		return
+	}
+	for _, metric := range metrics {
+		err = mh.verifyInstance(identity, metric.AppGUID, metric.InstanceIndex)
+		if err != nil {
+			mh.writeInstanceIdentityError(w, identity, err)
+			return
+		}
+	}
+	err = mh.validateCustomMetricTypes(appID, metrics)
+	if err != nil {
+		mh.logger.Error("failed-validating-metrictypes", err, lager.Data{"metrics": metrics})
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: err.Error()})
+		return
+	}
+
+	if !mh.allowMetrics(w, appID, metrics) {
+		return
+	}
+
+	mh.logger.Debug("otlp-metrics-parsed-successfully", lager.Data{"metrics": metrics})
+	for _, metric := range metrics {
+		mh.metricForwarder.EmitMetric(metric)
+	}
+
+	// an empty ExportMetricsServiceResponse in the encoding of the request
+	if strings.HasPrefix(contentType, otlp.ContentTypeProtobuf) {
+		w.Header().Set("Content-Type", otlp.ContentTypeProtobuf)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	handlers.WriteJSONResponse(w, http.StatusOK, struct{}{})
+}
+
+// authenticate accepts the instance identity certificate of an instance of the app or the credentials of the binding
+// of the app, the identity is nil when the credentials are used.
+func (mh *CustomMetricsHandler) authenticate(w http.ResponseWriter, r *http.Request, appID string) (*InstanceIdentity, bool) {
+	if mh.instanceIdentityEnabled && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return mh.authenticateInstanceIdentity(w, r.TLS.VerifiedChains[0][0], appID)
+	}
+
 	username, password, authOK := r.BasicAuth()
 
 	if authOK == false {
@@ -46,111 +341,152 @@ func (mh *CustomMetricsHandler) PublishMetrics(w http.ResponseWriter, r *http.Re
 		handlers.WriteJSONResponse(w, http.StatusUnauthorized, models.ErrorResponse{
 			Code:    "Authorization-Failure-Error",
 			Message: "Incorrect credentials. Basic authorization is not used properly"})
-		return
+		return nil, false
 	}
 
 	var isValid bool
-	var credentials models.CustomMetricCredentials
+	var credentials []*models.CustomMetricCredentials
 
-	appID := vars["appid"]
 	res, found := mh.credentialCache.Get(appID)
 	if found {
 		// Credentials found in cache
-		credentials = res.(models.CustomMetricCredentials)
-		isValid = mh.validateCredentials(username, credentials.Username, password, credentials.Password)
+		credentials = res.([]*models.CustomMetricCredentials)
+		isValid = mh.validateCredentials(username, password, credentials)
 	}
 
 	// Credentials not found in cache or
 	// stale cache entry with invalid credential found in cache
 	// search in the database and update the cache
 	if !found || !isValid {
-		usernameHash, passwordHash, err := mh.policyDB.GetCustomMetricsCreds(appID)
+		var err error
+		credentials, err = mh.policyDB.GetCustomMetricsCreds(appID)
 		if err != nil {
 			mh.logger.Error("error-during-getting-binding-credentials-from-policyDB", err, lager.Data{"appid": appID})
 			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
 				Code:    "Interal-Server-Error",
 				Message: "Error getting binding crededntials from policyDB"})
-			return
-		}
-		credentials = models.CustomMetricCredentials{
-			Username: usernameHash,
-			Password: passwordHash,
+			return nil, false
 		}
 		// update the cache
 		mh.credentialCache.Set(appID, credentials, mh.cacheTTL)
 
-		isValid = mh.validateCredentials(username, credentials.Username, password, credentials.Password)
+		isValid = mh.validateCredentials(username, password, credentials)
 		// If Credentials in DB is not valid
 		if !isValid {
 			mh.logger.Error("error-validating-authorizaion-header", err)
 			handlers.WriteJSONResponse(w, http.StatusUnauthorized, models.ErrorResponse{
 				Code:    "Authorization-Failure-Error",
 				Message: "Incorrect credentials. Basic authorization credential does not match"})
-			return
+			return nil, false
 		}
 	}
+	return nil, true
+}
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		mh.logger.Error("error-reading-request-body", err, lager.Data{"body": r.Body})
-		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
-			Code:    "Interal-Server-Error",
-			Message: "Error reading custom metrics request body"})
-		return
-	}
-	var metricsConsumer *models.MetricsConsumer
-	err = json.Unmarshal(body, &metricsConsumer)
+func (mh *CustomMetricsHandler) authenticateInstanceIdentity(w http.ResponseWriter, cert *x509.Certificate, appID string) (*InstanceIdentity, bool) {
+	identity, err := ParseInstanceIdentity(cert)
 	if err != nil {
-		mh.logger.Error("error-unmarshaling-metrics", err, lager.Data{"body": r.Body})
-		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
-			Code:    "Bad-Request",
-			Message: "Error unmarshaling custom metrics request body"})
-		return
+		mh.logger.Info("error-parsing-instance-identity", lager.Data{"subject": cert.Subject.String(), "error": err.Error()})
+		handlers.WriteJSONResponse(w, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "Authorization-Failure-Error",
+			Message: "Incorrect credentials. Client certificate is not an instance identity certificate"})
+		return nil, false
 	}
-	err = mh.validateCustomMetricTypes(appID, metricsConsumer)
-	if err != nil {
-		mh.logger.Error("failed-validating-metrictypes", err, lager.Data{"metrics": metricsConsumer})
-		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
-			Code:    "Bad-Request",
-			Message: err.Error()})
-		return
+	if identity.AppGUID != appID {
+		mh.logger.Info("instance-identity-of-another-app", lager.Data{"appid": appID, "identity": identity})
+		handlers.WriteJSONResponse(w, http.StatusForbidden, models.ErrorResponse{
+			Code:    "Authorization-Failure-Error",
+			Message: "Instance identity certificate does not belong to app " + appID})
+		return nil, false
 	}
+	mh.logger.Debug("instance-identity-authenticated", lager.Data{"identity": identity})
+	return identity, true
+}
 
-	metrics := mh.getMetrics(appID, metricsConsumer)
-
-	if len(metrics) <= 0 {
-		mh.logger.Debug("failed-parsing-custom-metrics-request-body", lager.Data{"metrics": metrics})
-		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
-			Code:    "Bad-Request",
-			Message: "Error parsing request body"})
-		return
+// verifyInstance checks that a metric of a request authenticated with an instance identity certificate is of the
+// instance of the certificate. The certificate has the guid of the instance but not its index, and the metrics
+// forwarder cannot look the index up in CF, so an instance is bound to the index of its first metric instead, until
+// it has not published any metric for the cache TTL. This is a known limitation: an instance cannot publish the
+// metrics of several indexes, but a new instance is trusted with the index of its first metric. The binding is made
+// under a lock, so that the concurrent requests of an instance cannot bind it to different indexes.
+func (mh *CustomMetricsHandler) verifyInstance(identity *InstanceIdentity, appGUID string, instanceIndex uint32) error {
+	if identity == nil {
+		return nil
+	}
+	if appGUID != "" && appGUID != identity.AppGUID {
+		return fmt.Errorf("Custom Metric of app %s does not belong to app %s of the instance identity certificate", appGUID, identity.AppGUID)
 	}
 
-	mh.logger.Debug("custom-metrics-parsed-successfully", lager.Data{"metrics": metrics})
-	for _, metric := range metrics {
-		mh.metricForwarder.EmitMetric(metric)
+	mh.instanceIndexLock.Lock()
+	defer mh.instanceIndexLock.Unlock()
+	res, found := mh.instanceIndexCache.Get(identity.InstanceID)
+	if found && res.(uint32) != instanceIndex {
+		return fmt.Errorf("Instance %s publishes the metrics of instance index %d, not %d", identity.InstanceID, res.(uint32), instanceIndex)
 	}
-	w.WriteHeader(http.StatusOK)
+	mh.instanceIndexCache.Set(identity.InstanceID, instanceIndex, mh.cacheTTL)
+	return nil
 }
 
-func (mh *CustomMetricsHandler) validateCredentials(username string, usernameHash string, password string, passwordHash string) bool {
-	usernameAuthErr := bcrypt.CompareHashAndPassword([]byte(usernameHash), []byte(username))
-	passwordAuthErr := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
-	if usernameAuthErr == nil && passwordAuthErr == nil { // password matching successfull
+func (mh *CustomMetricsHandler) writeInstanceIdentityError(w http.ResponseWriter, identity *InstanceIdentity, err error) {
+	mh.logger.Info("metrics-of-another-instance", lager.Data{"identity": identity, "error": err.Error()})
+	handlers.WriteJSONResponse(w, http.StatusForbidden, models.ErrorResponse{
+		Code:    "Authorization-Failure-Error",
+		Message: err.Error()})
+}
+
+// allowMetrics takes the metrics from the rate limits of the app, the request is rejected with the time to retry it
+// when a limit is exceeded.
+func (mh *CustomMetricsHandler) allowMetrics(w http.ResponseWriter, appID string, metrics []*models.CustomMetric) bool {
+	err := mh.rateLimiter.Allow(appID, metrics)
+	if err == nil {
 		return true
 	}
+	if limitErr, ok := err.(*ratelimiter.LimitExceededError); ok {
+		retryAfter := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	handlers.WriteJSONResponse(w, http.StatusTooManyRequests, models.ErrorResponse{
+		Code:    "Too-Many-Requests",
+		Message: err.Error()})
+	return false
+}
+
+// validateCredentials accepts any of the credentials of the app which have not expired, there are several during the
+// overlap period of a rotation.
+func (mh *CustomMetricsHandler) validateCredentials(username string, password string, credentials []*models.CustomMetricCredentials) bool {
+	now := time.Now().UnixNano()
+	for _, credential := range credentials {
+		if credential.IsExpired(now) {
+			continue
+		}
+		usernameAuthErr := bcrypt.CompareHashAndPassword([]byte(credential.Username), []byte(username))
+		passwordAuthErr := bcrypt.CompareHashAndPassword([]byte(credential.Password), []byte(password))
+		if usernameAuthErr == nil && passwordAuthErr == nil { // password matching successfull
+			return true
+		}
+	}
 	mh.logger.Debug("failed-to-authorize-credentials")
 	return false
 }
 
-func (mh *CustomMetricsHandler) validateCustomMetricTypes(appGUID string, metricsConsumer *models.MetricsConsumer) error {
-	standardMetricsTypes := make(map[string]struct{})
-	standardMetricsTypes[models.MetricNameMemoryUsed] = struct{}{}
-	standardMetricsTypes[models.MetricNameMemoryUtil] = struct{}{}
-	standardMetricsTypes[models.MetricNameThroughput] = struct{}{}
-	standardMetricsTypes[models.MetricNameResponseTime] = struct{}{}
-	standardMetricsTypes[models.MetricNameCPUUtil] = struct{}{}
+func (mh *CustomMetricsHandler) validateCustomMetricTypes(appGUID string, customMetrics []*models.CustomMetric) error {
+	allowedMetricTypeSet, err := mh.getAllowedMetricTypes(appGUID)
+	if err != nil {
+		return err
+	}
+	for _, metric := range customMetrics {
+		err = mh.validateCustomMetricType(metric.Name, allowedMetricTypeSet)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func (mh *CustomMetricsHandler) getAllowedMetricTypes(appGUID string) (map[string]struct{}, error) {
 	allowedMetricTypeSet := make(map[string]struct{})
 	res, found := mh.allowedMetricCache.Get(appGUID)
 	if found {
@@ -161,32 +497,37 @@ func (mh *CustomMetricsHandler) validateCustomMetricTypes(appGUID string, metric
 		scalingPolicy, err := mh.policyDB.GetAppPolicy(appGUID)
 		if err != nil {
 			mh.logger.Error("error-getting-policy", err, lager.Data{"appId": appGUID})
-			return errors.New("not able to get policy details")
+			return nil, errors.New("not able to get policy details")
 		}
 		if err == nil && scalingPolicy == nil {
 			mh.logger.Debug("no-policy-found", lager.Data{"appId": appGUID})
-			return errors.New("no policy defined")
+			return nil, errors.New("no policy defined")
 		}
-		for _, metrictype := range scalingPolicy.ScalingRules {
-			allowedMetricTypeSet[metrictype.MetricType] = struct{}{}
+		// the rules of the "rules" overrides of the schedules use custom metrics as well
+		for _, rule := range append(scalingPolicy.ScheduleOnlyRules(), scalingPolicy.ScalingRules...) {
+			for _, metricType := range rule.MetricTypes() {
+				allowedMetricTypeSet[metricType] = struct{}{}
+			}
 		}
 		//update the cache
 		mh.allowedMetricCache.Set(appGUID, allowedMetricTypeSet, mh.cacheTTL)
 	}
 	mh.logger.Debug("allowed-metrics-types", lager.Data{"metrics": allowedMetricTypeSet})
-	for _, metric := range metricsConsumer.CustomMetrics {
-		// check if the custom metric name matches with any standard metrics name
-		_, stdMetricsExists := standardMetricsTypes[metric.Name]
-		if stdMetricsExists { //it should fail
-			mh.logger.Info("custom-metric-name-matches-with-standard-metrics-name", lager.Data{"metric": metric.Name})
-			return errors.New("Custom Metric: " + metric.Name + " matches with standard metrics name")
-		}
-		// check if any of the custom metrics not defined during policy binding
-		_, ok := allowedMetricTypeSet[metric.Name]
-		if !ok { // If any of the custom metrics is not defined during policy binding, it should fail
-			mh.logger.Info("unmatched-custom-metric-type", lager.Data{"metric": metric.Name})
-			return errors.New("Custom Metric: " + metric.Name + " does not match with metrics defined in policy")
-		}
+	return allowedMetricTypeSet, nil
+}
+
+func (mh *CustomMetricsHandler) validateCustomMetricType(name string, allowedMetricTypeSet map[string]struct{}) error {
+	// check if the custom metric name matches with any standard metrics name
+	_, stdMetricsExists := standardMetricsTypes[name]
+	if stdMetricsExists { //it should fail
+		mh.logger.Info("custom-metric-name-matches-with-standard-metrics-name", lager.Data{"metric": name})
+		return errors.New("Custom Metric: " + name + " matches with standard metrics name")
+	}
+	// check if any of the custom metrics not defined during policy binding
+	_, ok := allowedMetricTypeSet[name]
+	if !ok { // If any of the custom metrics is not defined during policy binding, it should fail
+		mh.logger.Info("unmatched-custom-metric-type", lager.Data{"metric": name})
+		return errors.New("Custom Metric: " + name + " does not match with metrics defined in policy")
 	}
 	return nil
 }
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+const appOrganizationalUnitPrefix = "app:"
+
+// InstanceIdentity is the app instance of a Cloud Foundry instance identity certificate, whose organizational units
+// are organization:<guid>, space:<guid> and app:<guid> and whose common name is the guid of the instance.
+type InstanceIdentity struct {
+	AppGUID    string
+	InstanceID string
+}
+
+func ParseInstanceIdentity(cert *x509.Certificate) (*InstanceIdentity, error) {
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if strings.HasPrefix(ou, appOrganizationalUnitPrefix) {
+			return &InstanceIdentity{
+				AppGUID:    strings.TrimPrefix(ou, appOrganizationalUnitPrefix),
+				InstanceID: cert.Subject.CommonName,
+			}, nil
+		}
+	}
+	return nil, errors.New("certificate has no app organizational unit")
+}
@@ -0,0 +1,47 @@
+package server_test
+
+import (
+	. "autoscaler/metricsforwarder/server"
+
+	"crypto/x509"
+	"crypto/x509/pkix"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InstanceIdentity", func() {
+	var (
+		cert     *x509.Certificate
+		identity *InstanceIdentity
+		err      error
+	)
+
+	JustBeforeEach(func() {
+		identity, err = ParseInstanceIdentity(cert)
+	})
+
+	Context("when the certificate is an instance identity certificate", func() {
+		BeforeEach(func() {
+			cert = loadCertificate("cf_instance.crt")
+		})
+
+		It("returns the app and the instance of the certificate", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identity).To(Equal(&InstanceIdentity{AppGUID: "an-app-id", InstanceID: "an-instance-id"}))
+		})
+	})
+
+	Context("when the certificate has no app organizational unit", func() {
+		BeforeEach(func() {
+			cert = &x509.Certificate{Subject: pkix.Name{
+				CommonName:         "an-instance-id",
+				OrganizationalUnit: []string{"organization:an-org-id", "space:a-space-id"},
+			}}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("certificate has no app organizational unit"))
+		})
+	})
+})
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 
@@ -9,8 +12,10 @@ import (
 	"autoscaler/healthendpoint"
 	"autoscaler/metricsforwarder/config"
 	"autoscaler/metricsforwarder/forwarder"
+	"autoscaler/metricsforwarder/ratelimiter"
 	"autoscaler/routes"
 
+	"code.cloudfoundry.org/cfhttp"
 	"code.cloudfoundry.org/lager"
 	"github.com/gorilla/mux"
 	cache "github.com/patrickmn/go-cache"
@@ -25,7 +30,7 @@ func (vh VarsFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vh(w, r, vars)
 }
 
-func NewServer(logger lager.Logger, conf *config.Config, policyDB db.PolicyDB, credentialCache cache.Cache, allowedMetricCache cache.Cache, httpStatusCollector healthendpoint.HTTPStatusCollector) (ifrit.Runner, error) {
+func NewServer(logger lager.Logger, conf *config.Config, policyDB db.PolicyDB, credentialCache cache.Cache, allowedMetricCache cache.Cache, httpStatusCollector healthendpoint.HTTPStatusCollector, rateLimiter *ratelimiter.RateLimiter) (ifrit.Runner, error) {
 
 	metricForwarder, err := forwarder.NewMetricForwarder(logger, conf)
 	if err != nil {
@@ -33,19 +38,55 @@ func NewServer(logger lager.Logger, conf *config.Config, policyDB db.PolicyDB, c
 		os.Exit(1)
 	}
 
-	mh := NewCustomMetricsHandler(logger, metricForwarder, policyDB, credentialCache, allowedMetricCache, conf.CacheTTL)
+	instanceIdentityEnabled := conf.Server.InstanceIdentityCAFile != ""
+	mh := NewCustomMetricsHandler(logger, metricForwarder, policyDB, credentialCache, allowedMetricCache, conf.CacheTTL, conf.BatchMaxSize, rateLimiter, instanceIdentityEnabled)
 
 	httpStatusCollectMiddleware := healthendpoint.NewHTTPStatusCollectMiddleware(httpStatusCollector)
 
 	r := routes.MetricsForwarderRoutes()
 	r.Use(httpStatusCollectMiddleware.Collect)
 	r.Get(routes.PostCustomMetricsRouteName).Handler(VarsFunc(mh.PublishMetrics))
+	r.Get(routes.PostCustomMetricsBatchRouteName).Handler(VarsFunc(mh.PublishMetricsBatch))
+	r.Get(routes.PostOTLPMetricsRouteName).Handler(VarsFunc(mh.PublishOTLPMetrics))
 
 	addr := fmt.Sprintf("0.0.0.0:%d", conf.Server.Port)
 
 	var runner ifrit.Runner
-	runner = http_server.New(addr, r)
+	if (conf.Server.TLS.KeyFile != "") && (conf.Server.TLS.CertFile != "") {
+		tlsConfig, err := cfhttp.NewTLSConfig(conf.Server.TLS.CertFile, conf.Server.TLS.KeyFile, "")
+		if err != nil {
+			logger.Error("failed-new-server-new-tls-config", err, lager.Data{"tls": conf.Server.TLS})
+			return nil, err
+		}
+		// the client certificates are only verified with the CA of the instance identity certificates, never with the
+		// system roots, and the instance identity certificate is optional, the credentials of the binding are
+		// accepted without it
+		tlsConfig.ClientAuth = tls.NoClientCert
+		if instanceIdentityEnabled {
+			tlsConfig.ClientCAs, err = loadCertPool(conf.Server.InstanceIdentityCAFile)
+			if err != nil {
+				logger.Error("failed-new-server-load-instance-identity-ca", err, lager.Data{"instanceIdentityCAFile": conf.Server.InstanceIdentityCAFile})
+				return nil, err
+			}
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		runner = http_server.NewTLSServer(addr, r, tlsConfig)
+	} else {
+		runner = http_server.New(addr, r)
+	}
 
 	logger.Info("metrics-forwarder-http-server-created", lager.Data{"config": conf})
 	return runner, nil
 }
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	caBytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+	return pool, nil
+}
@@ -4,6 +4,7 @@ import (
 	"autoscaler/fakes"
 	"autoscaler/helpers"
 	"autoscaler/metricsforwarder/config"
+	"autoscaler/metricsforwarder/ratelimiter"
 	. "autoscaler/metricsforwarder/server"
 	"autoscaler/models"
 
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -41,6 +43,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 	testCertDir := "../../../../test-certs"
 	loggregatorConfig := config.LoggregatorConfig{
+		EmitQueueSize: 100,
 		TLS: models.TLSCerts{
 			KeyFile:    filepath.Join(testCertDir, "metron.key"),
 			CertFile:   filepath.Join(testCertDir, "metron.crt"),
@@ -59,12 +62,22 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 		Server:            serverConfig,
 		Logging:           loggerConfig,
 		LoggregatorConfig: loggregatorConfig,
+		BatchMaxSize:      100,
+		RateLimit: config.RateLimitConfig{
+			AppMetricsPerSecond:      100,
+			AppBurst:                 1000,
+			InstanceMetricsPerSecond: 20,
+			InstanceBurst:            200,
+			MaxMetricNamesPerApp:     100,
+			Expiration:               10 * time.Minute,
+		},
 	}
 	policyDB = &fakes.FakePolicyDB{}
 	credentialCache = *cache.New(10*time.Minute, -1)
 	allowedMetricCache = *cache.New(10*time.Minute, -1)
 	httpStatusCollector := &fakes.FakeHTTPStatusCollector{}
-	httpServer, err := NewServer(lager.NewLogger("test"), conf, policyDB, credentialCache, allowedMetricCache, httpStatusCollector)
+	rateLimiter := ratelimiter.NewRateLimiter(lager.NewLogger("test"), conf.RateLimit, clock.NewClock())
+	httpServer, err := NewServer(lager.NewLogger("test"), conf, policyDB, credentialCache, allowedMetricCache, httpStatusCollector, rateLimiter)
 	Expect(err).NotTo(HaveOccurred())
 	serverUrl = fmt.Sprintf("http://127.0.0.1:%d", conf.Server.Port)
 	serverProcess = ginkgomon.Invoke(httpServer)
@@ -47,7 +47,7 @@ var _ = Describe("CustomMetrics Server", func() {
 			Expect(err).NotTo(HaveOccurred())
 			credentials.Username = "$2a$10$YnQNQYcvl/Q2BKtThOKFZ.KB0nTIZwhKr5q1pWTTwC/PUAHsbcpFu"
 			credentials.Password = "$2a$10$6nZ73cm7IV26wxRnmm5E1.nbk9G.0a4MrbzBFPChkm5fPftsUwj9G"
-			credentialCache.Set("an-app-id", credentials, 10*time.Minute)
+			credentialCache.Set("an-app-id", []*models.CustomMetricCredentials{&credentials}, 10*time.Minute)
 			client := &http.Client{}
 			req, err = http.NewRequest("POST", serverUrl+"/v1/apps/an-app-id/metrics", bytes.NewReader(body))
 			req.Header.Add("Content-Type", "application/json")
@@ -68,7 +68,7 @@ var _ = Describe("CustomMetrics Server", func() {
 			credentials = models.CustomMetricCredentials{}
 			credentials.Username = "$2a$10$YnQNQYcvl/Q2BKtThOKFZ.KB0nTIZwhKr5q1pWTTwC/PUAHsbcpFu"
 			credentials.Password = "$2a$10$6nZ73cm7IV26wxRnmm5E1.nbk9G.0a4MrbzBFPChkm5fPftsUwj9G"
-			credentialCache.Set("an-app-id", credentials, 10*time.Minute)
+			credentialCache.Set("an-app-id", []*models.CustomMetricCredentials{&credentials}, 10*time.Minute)
 			body, err = json.Marshal(models.CustomMetric{Name: "queuelength", Value: 12, Unit: "unit", InstanceIndex: 123, AppGUID: "an-app-id"})
 			Expect(err).NotTo(HaveOccurred())
 			client := &http.Client{}
@@ -90,7 +90,7 @@ var _ = Describe("CustomMetrics Server", func() {
 			credentials = models.CustomMetricCredentials{}
 			credentials.Username = "$2a$10$YnQNQYcvl/Q2BKtThOKFZ.KB0nTIZwhKr5q1pWTTwC/PUAHsbcpFu"
 			credentials.Password = "$2a$10$6nZ73cm7IV26wxRnmm5E1.nbk9G.0a4MrbzBFPChkm5fPftsUwj9G"
-			credentialCache.Set("an-app-id", credentials, 10*time.Minute)
+			credentialCache.Set("an-app-id", []*models.CustomMetricCredentials{&credentials}, 10*time.Minute)
 			body, err = json.Marshal(models.CustomMetric{Name: "queuelength", Value: 12, Unit: "unit", InstanceIndex: 123, AppGUID: "an-app-id"})
 			Expect(err).NotTo(HaveOccurred())
 			client := &http.Client{}
@@ -113,7 +113,7 @@ var _ = Describe("CustomMetrics Server", func() {
 			credentials = models.CustomMetricCredentials{}
 			credentials.Username = "$2a$10$YnQNQYcvl/Q2BKtThOKFZ.KB0nTIZwhKr5q1pWTTwC/PUAHsbcpFu"
 			credentials.Password = "$2a$10$6nZ73cm7IV26wxRnmm5E1.nbk9G.0a4MrbzBFPChkm5fPftsUwj9G"
-			credentialCache.Set("an-app-id", credentials, 10*time.Minute)
+			credentialCache.Set("an-app-id", []*models.CustomMetricCredentials{&credentials}, 10*time.Minute)
 			body, err = json.Marshal(models.CustomMetric{Name: "queuelength", Value: 12, Unit: "unit", InstanceIndex: 123, AppGUID: "an-app-id"})
 			Expect(err).NotTo(HaveOccurred())
 			client := &http.Client{}
@@ -136,7 +136,7 @@ var _ = Describe("CustomMetrics Server", func() {
 			credentials = models.CustomMetricCredentials{}
 			credentials.Username = "$2a$10$YnQNQYcvl/Q2BKtThOKFZ.KB0nTIZwhKr5q1pWTTwC/PUAHsbcpFu"
 			credentials.Password = "$2a$10$6nZ73cm7IV26wxRnmm5E1.nbk9G.0a4MrbzBFPChkm5fPftsUwj9G"
-			credentialCache.Set("an-app-id", credentials, 10*time.Minute)
+			credentialCache.Set("an-app-id", []*models.CustomMetricCredentials{&credentials}, 10*time.Minute)
 			body, err = json.Marshal(models.CustomMetric{Name: "queuelength", Value: 12, Unit: "unit", InstanceIndex: 123, AppGUID: "an-app-id"})
 			Expect(err).NotTo(HaveOccurred())
 			client := &http.Client{}
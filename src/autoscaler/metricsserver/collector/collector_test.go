@@ -49,7 +49,7 @@ var _ = Describe("Collector", func() {
 			CollectedAt:   2222,
 			Name:          models.MetricNameThroughput,
 			Unit:          models.UnitRPS,
-			Value:         "3",
+			Value:         3,
 			Timestamp:     2222,
 		}
 		metric2 = &models.AppInstanceMetric{
@@ -58,7 +58,7 @@ var _ = Describe("Collector", func() {
 			CollectedAt:   3333,
 			Name:          models.MetricNameThroughput,
 			Unit:          models.UnitRPS,
-			Value:         "5",
+			Value:         5,
 			Timestamp:     3333,
 		}
 
@@ -68,7 +68,7 @@ var _ = Describe("Collector", func() {
 			CollectedAt:   6666,
 			Name:          models.MetricNameThroughput,
 			Unit:          models.UnitRPS,
-			Value:         "5",
+			Value:         5,
 			Timestamp:     6666,
 		}
 
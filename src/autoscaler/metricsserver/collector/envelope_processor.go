@@ -3,7 +3,6 @@ package collector
 import (
 	"autoscaler/helpers"
 	"autoscaler/models"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -103,7 +102,7 @@ func (ep *envelopeProcessor) processContainerMetrics(appID string, instanceIndex
 			CollectedAt:   ep.clock.Now().UnixNano(),
 			Name:          models.MetricNameMemoryUsed,
 			Unit:          models.UnitMegaBytes,
-			Value:         fmt.Sprintf("%d", int(memory.GetValue()/(1024*1024)+0.5)),
+			Value:         memory.GetValue() / (1024 * 1024),
 			Timestamp:     timestamp,
 		}
 		ep.metricChan <- memoryUsedMetric
@@ -116,7 +115,7 @@ func (ep *envelopeProcessor) processContainerMetrics(appID string, instanceIndex
 				CollectedAt:   ep.clock.Now().UnixNano(),
 				Name:          models.MetricNameMemoryUtil,
 				Unit:          models.UnitPercentage,
-				Value:         fmt.Sprintf("%d", int(memory.GetValue()/memoryQuota.GetValue()*100+0.5)),
+				Value:         memory.GetValue() / memoryQuota.GetValue() * 100,
 				Timestamp:     timestamp,
 			}
 			ep.metricChan <- memoryUtilMetric
@@ -131,7 +130,7 @@ func (ep *envelopeProcessor) processContainerMetrics(appID string, instanceIndex
 			CollectedAt:   ep.clock.Now().UnixNano(),
 			Name:          models.MetricNameCPUUtil,
 			Unit:          models.UnitPercentage,
-			Value:         fmt.Sprintf("%d", int64(cpu.GetValue()+0.5)),
+			Value:         cpu.GetValue(),
 			Timestamp:     timestamp,
 		}
 		ep.metricChan <- cpuMetric
@@ -159,7 +158,7 @@ func (ep *envelopeProcessor) processCustomMetrics(appID string, instanceIndex ui
 			CollectedAt:   ep.clock.Now().UnixNano(),
 			Name:          n,
 			Unit:          v.Unit,
-			Value:         fmt.Sprintf("%d", int64(v.Value+0.5)),
+			Value:         v.Value,
 			Timestamp:     timestamp,
 		}
 		ep.metricChan <- customMetric
@@ -178,7 +177,7 @@ func (ep *envelopeProcessor) computeAndSaveMetrics() {
 					CollectedAt:   ep.clock.Now().UnixNano(),
 					Name:          models.MetricNameThroughput,
 					Unit:          models.UnitRPS,
-					Value:         "0",
+					Value:         0,
 					Timestamp:     ep.clock.Now().UnixNano(),
 				}
 				ep.metricChan <- throughputMetric
@@ -192,7 +191,7 @@ func (ep *envelopeProcessor) computeAndSaveMetrics() {
 				CollectedAt:   ep.clock.Now().UnixNano(),
 				Name:          models.MetricNameThroughput,
 				Unit:          models.UnitRPS,
-				Value:         fmt.Sprintf("%d", int(float64(numReq)/ep.collectInterval.Seconds()+0.5)),
+				Value:         float64(numReq) / ep.collectInterval.Seconds(),
 				Timestamp:     ep.clock.Now().UnixNano(),
 			}
 			ep.metricChan <- throughputMetric
@@ -203,7 +202,7 @@ func (ep *envelopeProcessor) computeAndSaveMetrics() {
 				CollectedAt:   ep.clock.Now().UnixNano(),
 				Name:          models.MetricNameResponseTime,
 				Unit:          models.UnitMilliseconds,
-				Value:         fmt.Sprintf("%d", ep.sumReponseTimes[appID][instanceIdx]/(numReq*1000*1000)),
+				Value:         float64(ep.sumReponseTimes[appID][instanceIdx]) / float64(numReq*1000*1000),
 				Timestamp:     ep.clock.Now().UnixNano(),
 			}
 			ep.metricChan <- responseTimeMetric
@@ -61,7 +61,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameMemoryUsed,
 					Unit:          models.UnitMegaBytes,
-					Value:         "10",
+					Value:         10,
 					Timestamp:     1111,
 				})))
 
@@ -71,7 +71,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameMemoryUtil,
 					Unit:          models.UnitPercentage,
-					Value:         "50",
+					Value:         50,
 					Timestamp:     1111,
 				})))
 
@@ -81,7 +81,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameCPUUtil,
 					Unit:          models.UnitPercentage,
-					Value:         "10",
+					Value:         10.2,
 					Timestamp:     1111,
 				})))
 			})
@@ -98,7 +98,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          "custom_name",
 					Unit:          "custom_unit",
-					Value:         "12",
+					Value:         11.88,
 					Timestamp:     1111,
 				})))
 
@@ -133,7 +133,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameThroughput,
 					Unit:          models.UnitRPS,
-					Value:         "2",
+					Value:         2,
 					Timestamp:     fclock.Now().UnixNano(),
 				}))
 
@@ -143,7 +143,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameResponseTime,
 					Unit:          models.UnitMilliseconds,
-					Value:         "10",
+					Value:         10,
 					Timestamp:     fclock.Now().UnixNano(),
 				}))
 
@@ -153,7 +153,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameThroughput,
 					Unit:          models.UnitRPS,
-					Value:         "3",
+					Value:         3,
 					Timestamp:     fclock.Now().UnixNano(),
 				}))
 
@@ -163,7 +163,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 					CollectedAt:   fclock.Now().UnixNano(),
 					Name:          models.MetricNameResponseTime,
 					Unit:          models.UnitMilliseconds,
-					Value:         "20",
+					Value:         20,
 					Timestamp:     fclock.Now().UnixNano(),
 				}))
 
@@ -191,7 +191,7 @@ var _ = Describe("EnvelopeProcessor", func() {
 							CollectedAt:   fclock.Now().UnixNano(),
 							Name:          models.MetricNameThroughput,
 							Unit:          models.UnitRPS,
-							Value:         "0",
+							Value:         0,
 							Timestamp:     fclock.Now().UnixNano(),
 						})))
 					})
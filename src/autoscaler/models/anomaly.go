@@ -0,0 +1,87 @@
+package models
+
+import "math"
+
+const (
+	// the standard deviation of a baseline is at least this fraction of its mean, so that a metric which has been
+	// constant is still scored
+	minRelativeStdDev = 0.01
+	// and at least this value, for a metric which has been constantly 0
+	minStdDev = 0.01
+)
+
+// MetricBaseline is the exponentially weighted moving average and variance of an aggregated metric of an app.
+type MetricBaseline struct {
+	Mean     float64
+	Variance float64
+	Samples  int
+}
+
+// Update adds the value to the baseline, the smoothing factor is the weight of the value against the history.
+func (b *MetricBaseline) Update(value float64, smoothingFactor float64) {
+	if b.Samples == 0 {
+		b.Mean = value
+		b.Samples++
+		return
+	}
+	diff := value - b.Mean
+	increment := smoothingFactor * diff
+	b.Mean += increment
+	b.Variance = (1 - smoothingFactor) * (b.Variance + diff*increment)
+	b.Samples++
+}
+
+// StdDev returns the standard deviation of the baseline, with a floor so that a small variation of a metric which has
+// been nearly constant does not score as an anomaly, and a large one still does.
+func (b *MetricBaseline) StdDev() float64 {
+	floor := math.Max(minRelativeStdDev*math.Abs(b.Mean), minStdDev)
+	return math.Max(math.Sqrt(b.Variance), floor)
+}
+
+// Score returns the number of standard deviations the value is away from the baseline. There is no score until the
+// baseline has minSamples samples.
+func (b *MetricBaseline) Score(value float64, minSamples int) (float64, bool) {
+	if b.Samples < minSamples {
+		return 0, false
+	}
+	return (value - b.Mean) / b.StdDev(), true
+}
+
+// AnomalyScore is an aggregated metric scored against the baseline of the metric before it.
+type AnomalyScore struct {
+	AppId       string  `json:"app_id"`
+	MetricType  string  `json:"name"`
+	Aggregation string  `json:"aggregation"`
+	Value       float64 `json:"value"`
+	Unit        string  `json:"unit"`
+	Baseline    float64 `json:"baseline"`
+	StdDev      float64 `json:"std_dev"`
+	Score       float64 `json:"score"`
+	Timestamp   int64   `json:"timestamp"`
+}
+
+func (s *AnomalyScore) GetTimestamp() int64 {
+	return s.Timestamp
+}
+
+func (s *AnomalyScore) HasLabels(labels map[string]string) bool {
+	for k, v := range labels {
+		switch k {
+		case MetricLabelAppID:
+			if v != s.AppId {
+				return false
+			}
+		case MetricLabelName:
+			if v != s.MetricType {
+				return false
+			}
+		case MetricLabelAggregation:
+			if v != s.Aggregation {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
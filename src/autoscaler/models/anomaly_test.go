@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	. "autoscaler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MetricBaseline", func() {
+	var baseline *MetricBaseline
+
+	BeforeEach(func() {
+		baseline = &MetricBaseline{}
+	})
+
+	Context("when the baseline has not enough samples", func() {
+		BeforeEach(func() {
+			baseline.Update(100, 0.5)
+			baseline.Update(200, 0.5)
+		})
+		It("should not score the value", func() {
+			_, ok := baseline.Score(300, 3)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when the metric has been constant", func() {
+		BeforeEach(func() {
+			for i := 0; i < 5; i++ {
+				baseline.Update(100, 0.5)
+			}
+		})
+		It("should score the value against the minimum standard deviation", func() {
+			Expect(baseline.Mean).To(Equal(100.0))
+			Expect(baseline.StdDev()).To(Equal(1.0))
+			same, ok := baseline.Score(100, 3)
+			Expect(ok).To(BeTrue())
+			Expect(same).To(Equal(0.0))
+			far, ok := baseline.Score(300, 3)
+			Expect(ok).To(BeTrue())
+			Expect(far).To(Equal(200.0))
+		})
+
+		Context("when the metric has been constantly 0", func() {
+			BeforeEach(func() {
+				baseline = &MetricBaseline{}
+				for i := 0; i < 5; i++ {
+					baseline.Update(0, 0.5)
+				}
+			})
+			It("should score the value against the minimum standard deviation", func() {
+				score, ok := baseline.Score(1, 3)
+				Expect(ok).To(BeTrue())
+				Expect(score).To(BeNumerically("~", 100, 0.001))
+			})
+		})
+	})
+
+	Context("when the metric varies", func() {
+		BeforeEach(func() {
+			for _, value := range []float64{100, 110, 90, 100, 110, 90, 100} {
+				baseline.Update(value, 0.5)
+			}
+		})
+		It("should follow the metric", func() {
+			Expect(baseline.Mean).To(BeNumerically("~", 98.59, 0.01))
+			Expect(baseline.StdDev()).To(BeNumerically(">", 0))
+		})
+		It("should score the values far from the baseline higher", func() {
+			near, ok := baseline.Score(105, 3)
+			Expect(ok).To(BeTrue())
+			far, ok := baseline.Score(200, 3)
+			Expect(ok).To(BeTrue())
+			low, ok := baseline.Score(0, 3)
+			Expect(ok).To(BeTrue())
+			Expect(far).To(BeNumerically(">", near))
+			Expect(far).To(BeNumerically(">", 3))
+			Expect(low).To(BeNumerically("<", -3))
+		})
+	})
+})
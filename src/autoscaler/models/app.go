@@ -13,12 +13,14 @@ type ScalingStatus int
 const (
 	ScalingTypeDynamic ScalingType = iota
 	ScalingTypeSchedule
+	ScalingTypePause
 )
 
 const (
 	ScalingStatusSucceeded ScalingStatus = iota
 	ScalingStatusFailed
 	ScalingStatusIgnored
+	ScalingStatusSimulated
 )
 
 const (
@@ -27,6 +29,7 @@ const (
 )
 
 type AppScalingHistory struct {
+	Id           int64         `json:"id,omitempty"`
 	AppId        string        `json:"app_id"`
 	Timestamp    int64         `json:"timestamp"`
 	ScalingType  ScalingType   `json:"scaling_type"`
@@ -36,17 +39,22 @@ type AppScalingHistory struct {
 	Reason       string        `json:"reason"`
 	Message      string        `json:"message"`
 	Error        string        `json:"error"`
+	GroupId      string        `json:"group_id,omitempty"`
 }
 
 type AppMonitor struct {
-	AppId      string
-	MetricType string
-	StatWindow time.Duration
+	AppId       string
+	MetricType  string
+	Aggregation string
+	StatWindow  time.Duration
+	Source      *MetricSourceConfig
 }
 
 type AppScalingResult struct {
 	AppId             string        `json:"app_id"`
 	Status            ScalingStatus `json:"status"`
 	Adjustment        int           `json:"adjustment"`
+	Instances         int           `json:"instances"`
 	CooldownExpiredAt int64         `json:"cool_down_expired_at"`
+	Direction         string        `json:"direction,omitempty"`
 }
@@ -6,6 +6,7 @@ type CustomMetric struct {
 	Unit          string  `json:"unit"`
 	AppGUID       string  `json:"app_guid"`
 	InstanceIndex uint32  `json:"instance_index"`
+	Timestamp     int64   `json:"timestamp,omitempty"`
 }
 
 type MetricsConsumer struct {
@@ -14,7 +15,39 @@ type MetricsConsumer struct {
 	CustomMetrics []*CustomMetric `json:"metrics"`
 }
 
+// CustomMetricsBatch is a batch of custom metrics of any instances of an app, a metric without timestamp is
+// timestamped when it is received.
+type CustomMetricsBatch struct {
+	CustomMetrics []*CustomMetric `json:"metrics"`
+}
+
+type CustomMetricResult struct {
+	Accepted bool   `json:"accepted"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CustomMetricsBatchResult has the result of every metric of a batch in the order of the batch.
+type CustomMetricsBatchResult struct {
+	Accepted int                   `json:"accepted"`
+	Rejected int                   `json:"rejected"`
+	Results  []*CustomMetricResult `json:"results"`
+}
+
 type CustomMetricCredentials struct {
 	Username string
 	Password string
+	// in nanoseconds, 0 when the credentials do not expire
+	ExpireAt int64
+}
+
+func (c *CustomMetricCredentials) IsExpired(now int64) bool {
+	return c.ExpireAt > 0 && c.ExpireAt <= now
+}
+
+// CustomMetricCredentialsRotation is the new credentials of a rotation, the previous credentials stay valid until
+// PreviousExpireAt.
+type CustomMetricCredentialsRotation struct {
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+	PreviousExpireAt int64  `json:"previous_expire_at"`
 }
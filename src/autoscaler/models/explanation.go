@@ -0,0 +1,64 @@
+package models
+
+// MetricComparison is the comparison of a metric type with the threshold of a rule in the evaluation which sent a
+// trigger.
+type MetricComparison struct {
+	MetricType   string            `json:"metric_type"`
+	Aggregation  string            `json:"aggregation"`
+	Operator     string            `json:"operator,omitempty"`
+	Threshold    float64           `json:"threshold"`
+	MetricPoints []*AppMetric      `json:"metric_points"`
+	Forecast     *float64          `json:"forecast,omitempty"`
+	RateOfChange *RateOfChange     `json:"rate_of_change,omitempty"`
+	Anomaly      *AnomalyDetection `json:"anomaly,omitempty"`
+	Breached     bool              `json:"breached"`
+}
+
+type CircuitBreakerStatus struct {
+	Tripped             bool  `json:"tripped"`
+	ConsecutiveFailures int64 `json:"consecutive_failures"`
+}
+
+// TriggerEvidence is what the event generator evaluated to send a trigger.
+type TriggerEvidence struct {
+	Comparisons    []*MetricComparison   `json:"comparisons"`
+	CircuitBreaker *CircuitBreakerStatus `json:"circuit_breaker,omitempty"`
+}
+
+type CooldownStatus struct {
+	Direction  string `json:"direction"`
+	InCooldown bool   `json:"in_cooldown"`
+	ExpireAt   int64  `json:"expire_at"`
+}
+
+// ScalingExplanation is the structured record of a dynamic scaling decision, from the evaluation of the event
+// generator to the instance count computed by the scaling engine.
+type ScalingExplanation struct {
+	Trigger           *Trigger              `json:"trigger"`
+	Comparisons       []*MetricComparison   `json:"comparisons"`
+	CircuitBreaker    *CircuitBreakerStatus `json:"circuit_breaker,omitempty"`
+	Cooldown          *CooldownStatus       `json:"cooldown,omitempty"`
+	ActiveSchedule    *ActiveSchedule       `json:"active_schedule,omitempty"`
+	CurrentInstances  int                   `json:"current_instances"`
+	ComputedInstances int                   `json:"computed_instances"`
+	InstanceMin       int                   `json:"instance_min"`
+	InstanceMax       int                   `json:"instance_max"`
+	NewInstances      int                   `json:"new_instances"`
+}
+
+func NewScalingExplanation(trigger *Trigger) *ScalingExplanation {
+	t := *trigger
+	t.Evidence = nil
+	explanation := &ScalingExplanation{
+		Trigger:           &t,
+		Comparisons:       []*MetricComparison{},
+		CurrentInstances:  -1,
+		ComputedInstances: -1,
+		NewInstances:      -1,
+	}
+	if trigger.Evidence != nil {
+		explanation.Comparisons = trigger.Evidence.Comparisons
+		explanation.CircuitBreaker = trigger.Evidence.CircuitBreaker
+	}
+	return explanation
+}
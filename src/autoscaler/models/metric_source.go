@@ -0,0 +1,18 @@
+package models
+
+const (
+	MetricSourceTypeHTTPJSON   = "http_json"
+	MetricSourceTypePrometheus = "prometheus"
+)
+
+// MetricSourceConfig is where the event generator pulls a metric type of the app from, instead of collecting it from
+// the app instances. It suits the metrics no single instance owns, such as the lag of a queue.
+type MetricSourceConfig struct {
+	MetricType string            `json:"metric_type"`
+	Type       string            `json:"type"`
+	URL        string            `json:"url"`
+	JSONPath   string            `json:"json_path,omitempty"`
+	Query      string            `json:"query,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Unit       string            `json:"unit,omitempty"`
+}
@@ -20,16 +20,36 @@ const (
 	MetricLabelAppID         = "app_id"
 	MetricLabelInstanceIndex = "instance_index"
 	MetricLabelName          = "name"
+	MetricLabelAggregation   = "aggregation"
+
+	AggregationAvg = "avg"
+	AggregationMax = "max"
+	AggregationMin = "min"
+	AggregationSum = "sum"
+	AggregationP50 = "p50"
+	AggregationP90 = "p90"
+	AggregationP99 = "p99"
 )
 
+var Aggregations = []string{AggregationAvg, AggregationMax, AggregationMin, AggregationSum, AggregationP50, AggregationP90, AggregationP99}
+
+func IsValidAggregation(aggregation string) bool {
+	for _, a := range Aggregations {
+		if a == aggregation {
+			return true
+		}
+	}
+	return false
+}
+
 type AppInstanceMetric struct {
-	AppId         string `json:"app_id"`
-	InstanceIndex uint32 `json:"instance_index"`
-	CollectedAt   int64  `json:"collected_at"`
-	Name          string `json:"name"`
-	Unit          string `json:"unit"`
-	Value         string `json:"value"`
-	Timestamp     int64  `json:"timestamp"`
+	AppId         string  `json:"app_id"`
+	InstanceIndex uint32  `json:"instance_index"`
+	CollectedAt   int64   `json:"collected_at"`
+	Name          string  `json:"name"`
+	Unit          string  `json:"unit"`
+	Value         float64 `json:"value"`
+	Timestamp     int64   `json:"timestamp"`
 }
 
 func (m *AppInstanceMetric) GetTimestamp() int64 {
@@ -65,11 +85,12 @@ func (m *AppInstanceMetric) HasLabels(labels map[string]string) bool {
 }
 
 type AppMetric struct {
-	AppId      string `json:"app_id"`
-	MetricType string `json:"name"`
-	Value      string `json:"value"`
-	Unit       string `json:"unit"`
-	Timestamp  int64  `json:"timestamp"`
+	AppId       string  `json:"app_id"`
+	MetricType  string  `json:"name"`
+	Aggregation string  `json:"aggregation"`
+	Value       float64 `json:"value"`
+	Unit        string  `json:"unit"`
+	Timestamp   int64   `json:"timestamp"`
 }
 
 func (m *AppMetric) GetTimestamp() int64 {
@@ -91,6 +112,12 @@ func (m *AppMetric) HasLabels(labels map[string]string) bool {
 			} else {
 				return false
 			}
+		case MetricLabelAggregation:
+			if v == m.Aggregation {
+				continue
+			} else {
+				return false
+			}
 		default:
 			return false
 		}
@@ -0,0 +1,14 @@
+package models
+
+// AppPause stops the autoscaler from scaling an app until it is resumed, or until ExpireAt when it is not 0.
+type AppPause struct {
+	AppId    string `json:"app_id"`
+	PausedBy string `json:"paused_by"`
+	Reason   string `json:"reason,omitempty"`
+	PausedAt int64  `json:"paused_at"`
+	ExpireAt int64  `json:"expire_at,omitempty"`
+}
+
+func (p *AppPause) IsActive(now int64) bool {
+	return p != nil && (p.ExpireAt == 0 || now < p.ExpireAt)
+}
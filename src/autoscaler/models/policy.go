@@ -2,17 +2,24 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type AppPolicy struct {
 	AppId         string
 	ScalingPolicy *ScalingPolicy
+	Pause         *AppPause
 }
 
 type PolicyJson struct {
 	AppId     string
 	PolicyStr string
+	// only set by PolicyDB.GetAppPolicyJson
+	PolicyGuid string
 }
 
 func (p1 *PolicyJson) Equals(p2 *PolicyJson) bool {
@@ -34,20 +41,274 @@ func (p *PolicyJson) GetAppPolicy() *AppPolicy {
 	return &AppPolicy{AppId: p.AppId, ScalingPolicy: &scalingPolicy}
 }
 
+const (
+	PolicyModeActive = "active"
+	PolicyModeShadow = "shadow"
+)
+
+const (
+	ScalingDirectionOut = "out"
+	ScalingDirectionIn  = "in"
+)
+
 type ScalingPolicy struct {
-	InstanceMin  int               `json:"instance_min_count"`
-	InstanceMax  int               `json:"instance_max_count"`
-	ScalingRules []*ScalingRule    `json:"scaling_rules,omitempty"`
-	Schedules    *ScalingSchedules `json:"schedules,omitempty"`
+	InstanceMin                 int                   `json:"instance_min_count"`
+	InstanceMax                 int                   `json:"instance_max_count"`
+	Mode                        string                `json:"mode,omitempty"`
+	ScaleInStabilizationSeconds int                   `json:"scale_in_stabilization_secs,omitempty"`
+	ScaleInMaxStep              int                   `json:"scale_in_max_step,omitempty"`
+	CoolDownPerRule             bool                  `json:"cool_down_per_rule,omitempty"`
+	ScalingRules                []*ScalingRule        `json:"scaling_rules,omitempty"`
+	Schedules                   *ScalingSchedules     `json:"schedules,omitempty"`
+	Webhooks                    []*Webhook            `json:"webhooks,omitempty"`
+	MetricSources               []*MetricSourceConfig `json:"metric_sources,omitempty"`
+}
+
+// ScheduleOnlyRules returns the rules of the "rules" overrides of the schedules. They are evaluated besides the rules
+// of the policy, but only scale the app while their schedule is active.
+func (p ScalingPolicy) ScheduleOnlyRules() []*ScalingRule {
+	rules := []*ScalingRule{}
+	if p.Schedules == nil {
+		return rules
+	}
+	overrides := []*ScheduleOverride{}
+	for _, schedule := range p.Schedules.RecurringSchedules {
+		overrides = append(overrides, schedule.Override)
+	}
+	for _, schedule := range p.Schedules.SpecificDateSchedules {
+		overrides = append(overrides, schedule.Override)
+	}
+	for _, override := range overrides {
+		if override == nil || override.Mode != ScheduleOverrideRules {
+			continue
+		}
+	next:
+		for _, rule := range override.ScalingRules {
+			for _, existing := range rules {
+				if reflect.DeepEqual(existing, rule) {
+					continue next
+				}
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// MetricSource returns the source the metric type is pulled from, it is nil for the metric types collected from the
+// app instances.
+func (p ScalingPolicy) MetricSource(metricType string) *MetricSourceConfig {
+	for _, source := range p.MetricSources {
+		if source.MetricType == metricType {
+			return source
+		}
+	}
+	return nil
+}
+
+// IsShadow returns whether the dynamic scaling decisions of the policy are only recorded but not applied.
+func (p ScalingPolicy) IsShadow() bool {
+	return p.Mode == PolicyModeShadow
+}
+
+// HasAnomalyRules returns whether a rule of the policy or of its schedules scales on anomalies.
+func (p ScalingPolicy) HasAnomalyRules() bool {
+	for _, rule := range append(p.ScheduleOnlyRules(), p.ScalingRules...) {
+		if rule.Anomaly != nil {
+			return true
+		}
+	}
+	return false
 }
 
 type ScalingRule struct {
-	MetricType            string `json:"metric_type"`
-	BreachDurationSeconds int    `json:"breach_duration_secs,omitempty"`
-	Threshold             int64  `json:"threshold"`
-	Operator              string `json:"operator"`
-	CoolDownSeconds       int    `json:"cool_down_secs,omitempty"`
-	Adjustment            string `json:"adjustment"`
+	MetricType            string             `json:"metric_type"`
+	BreachDurationSeconds int                `json:"breach_duration_secs,omitempty"`
+	Threshold             float64            `json:"threshold"`
+	Operator              string             `json:"operator"`
+	CoolDownSeconds       int                `json:"cool_down_secs,omitempty"`
+	Adjustment            string             `json:"adjustment"`
+	Predictive            *PredictiveScaling `json:"predictive,omitempty"`
+	TargetValue           float64            `json:"target_value,omitempty"`
+	ScaleInDamping        float64            `json:"scale_in_damping,omitempty"`
+	Aggregation           string             `json:"aggregation,omitempty"`
+	Condition             *ScalingCondition  `json:"condition,omitempty"`
+	RateOfChange          *RateOfChange      `json:"rate_of_change,omitempty"`
+	Anomaly               *AnomalyDetection  `json:"anomaly,omitempty"`
+}
+
+func (r ScalingRule) IsTargetTracking() bool {
+	return r.TargetValue != 0
+}
+
+func (r ScalingRule) IsCompound() bool {
+	return r.Condition != nil
+}
+
+// MetricTypes returns the metric types the rule is evaluated on.
+func (r ScalingRule) MetricTypes() []string {
+	if r.IsCompound() {
+		return r.Condition.MetricTypes()
+	}
+	return []string{r.MetricType}
+}
+
+// Key identifies the rule among the rules of the policy, rules on the same metrics differ in their kind, operator,
+// threshold, target value, aggregation or adjustment.
+func (r ScalingRule) Key() string {
+	return ruleKey(r.MetricType, r.Condition, r.Operator, r.Threshold, r.TargetValue, r.AggregationFunction(),
+		r.Adjustment, ruleKind(r.Predictive, r.RateOfChange, r.Anomaly))
+}
+
+// ScalingDirection returns the direction the rule scales the app in, it is empty for target tracking rules
+// which scale in both directions.
+func (r ScalingRule) ScalingDirection() string {
+	if r.IsTargetTracking() {
+		return ""
+	}
+	if strings.HasPrefix(r.Adjustment, "-") {
+		return ScalingDirectionIn
+	}
+	return ScalingDirectionOut
+}
+
+func (r ScalingRule) AggregationFunction() string {
+	if r.Aggregation == "" {
+		return AggregationAvg
+	}
+	return r.Aggregation
+}
+
+// ScalingCondition is either a comparison of one metric with a threshold or a group of conditions
+// that must all (And) or in part (Or) be breached.
+type ScalingCondition struct {
+	And         []*ScalingCondition `json:"and,omitempty"`
+	Or          []*ScalingCondition `json:"or,omitempty"`
+	MetricType  string              `json:"metric_type,omitempty"`
+	Operator    string              `json:"operator,omitempty"`
+	Threshold   float64             `json:"threshold,omitempty"`
+	Aggregation string              `json:"aggregation,omitempty"`
+}
+
+func (c ScalingCondition) AggregationFunction() string {
+	if c.Aggregation == "" {
+		return AggregationAvg
+	}
+	return c.Aggregation
+}
+
+// Comparisons returns the metric comparisons at the leaves of the condition.
+func (c *ScalingCondition) Comparisons() []*ScalingCondition {
+	if len(c.And) == 0 && len(c.Or) == 0 {
+		return []*ScalingCondition{c}
+	}
+	comparisons := []*ScalingCondition{}
+	for _, child := range c.And {
+		comparisons = append(comparisons, child.Comparisons()...)
+	}
+	for _, child := range c.Or {
+		comparisons = append(comparisons, child.Comparisons()...)
+	}
+	return comparisons
+}
+
+func (c *ScalingCondition) MetricTypes() []string {
+	metricTypes := []string{}
+	seen := map[string]bool{}
+	for _, comparison := range c.Comparisons() {
+		if !seen[comparison.MetricType] {
+			seen[comparison.MetricType] = true
+			metricTypes = append(metricTypes, comparison.MetricType)
+		}
+	}
+	return metricTypes
+}
+
+func (c *ScalingCondition) String() string {
+	children, op := c.And, " and "
+	if len(c.Or) > 0 {
+		children, op = c.Or, " or "
+	}
+	if len(children) == 0 {
+		return fmt.Sprintf("%s %s %s", c.MetricType, c.Operator, strconv.FormatFloat(c.Threshold, 'f', -1, 64))
+	}
+	parts := make([]string, len(children))
+	for i, child := range children {
+		parts[i] = child.String()
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+// key is String with the aggregation of every comparison.
+func (c *ScalingCondition) key() string {
+	children, op := c.And, " and "
+	if len(c.Or) > 0 {
+		children, op = c.Or, " or "
+	}
+	if len(children) == 0 {
+		return fmt.Sprintf("%s:%s %s %s", c.MetricType, c.AggregationFunction(), c.Operator, strconv.FormatFloat(c.Threshold, 'f', -1, 64))
+	}
+	parts := make([]string, len(children))
+	for i, child := range children {
+		parts[i] = child.key()
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+const (
+	ForecastMethodLinearRegression = "linear_regression"
+	ForecastMethodHoltWinters      = "holt_winters"
+)
+
+type PredictiveScaling struct {
+	Method                 string `json:"method"`
+	ForecastHorizonSeconds int    `json:"forecast_horizon_secs"`
+	HistoryWindowSeconds   int    `json:"history_window_secs,omitempty"`
+	DailySeasonality       bool   `json:"daily_seasonality,omitempty"`
+}
+
+func (p PredictiveScaling) ForecastHorizon() time.Duration {
+	return time.Duration(p.ForecastHorizonSeconds) * time.Second
+}
+
+func (p PredictiveScaling) HistoryWindow() time.Duration {
+	return time.Duration(p.HistoryWindowSeconds) * time.Second
+}
+
+const (
+	RateOfChangePercent  = "percent"
+	RateOfChangeAbsolute = "absolute"
+)
+
+// RateOfChange makes a rule compare the change of the metric over the window with the threshold, instead of the
+// metric value. The change is in percent of the metric value at the start of the window unless it is absolute.
+type RateOfChange struct {
+	WindowSeconds int    `json:"window_secs"`
+	Change        string `json:"change,omitempty"`
+}
+
+func (r RateOfChange) Window() time.Duration {
+	return time.Duration(r.WindowSeconds) * time.Second
+}
+
+func (r RateOfChange) IsPercent() bool {
+	return r.Change != RateOfChangeAbsolute
+}
+
+const (
+	AnomalyActionScale  = "scale"
+	AnomalyActionNotify = "notify"
+)
+
+// AnomalyDetection makes a rule compare the anomaly score of the metric, the number of standard deviations it is away
+// from the rolling baseline of the metric, with the threshold instead of the metric value. A rule which notifies
+// records the anomaly instead of scaling the app.
+type AnomalyDetection struct {
+	Action string `json:"action,omitempty"`
+}
+
+func (a AnomalyDetection) IsNotify() bool {
+	return a.Action == AnomalyActionNotify
 }
 
 type ScalingSchedules struct {
@@ -57,23 +318,50 @@ type ScalingSchedules struct {
 }
 
 type RecurringSchedule struct {
-	StartTime             string `json:"start_time"`
-	EndTime               string `json:"end_time"`
-	DaysOfWeek            []int  `json:"days_of_week,omitempty"`
-	DaysOfMonth           []int  `json:"days_of_month,omitempty"`
-	StartDate             string `json:"start_date,omitempty"`
-	EndDate               string `json:"end_date,omitempty"`
-	ScheduledInstanceMin  int    `json:"instance_min_count"`
-	ScheduledInstanceMax  int    `json:"instance_max_count"`
-	ScheduledInstanceInit int    `json:"initial_min_instance_count"`
+	StartTime             string            `json:"start_time"`
+	EndTime               string            `json:"end_time"`
+	DaysOfWeek            []int             `json:"days_of_week,omitempty"`
+	DaysOfMonth           []int             `json:"days_of_month,omitempty"`
+	StartDate             string            `json:"start_date,omitempty"`
+	EndDate               string            `json:"end_date,omitempty"`
+	ScheduledInstanceMin  int               `json:"instance_min_count"`
+	ScheduledInstanceMax  int               `json:"instance_max_count"`
+	ScheduledInstanceInit int               `json:"initial_min_instance_count"`
+	Override              *ScheduleOverride `json:"override,omitempty"`
 }
 
 type SpecificDateSchedule struct {
-	StartDateTime         string `json:"start_date_time"`
-	EndDateTime           string `json:"end_date_time"`
-	ScheduledInstanceMin  int    `json:"instance_min_count"`
-	ScheduledInstanceMax  int    `json:"instance_max_count"`
-	ScheduledInstanceInit int    `json:"initial_min_instance_count"`
+	StartDateTime         string            `json:"start_date_time"`
+	EndDateTime           string            `json:"end_date_time"`
+	ScheduledInstanceMin  int               `json:"instance_min_count"`
+	ScheduledInstanceMax  int               `json:"instance_max_count"`
+	ScheduledInstanceInit int               `json:"initial_min_instance_count"`
+	Override              *ScheduleOverride `json:"override,omitempty"`
+}
+
+const (
+	ScheduleOverrideSuspend = "suspend"
+	ScheduleOverridePin     = "pin"
+	ScheduleOverrideRules   = "rules"
+)
+
+// ScheduleOverride changes the dynamic scaling of an app while its schedule is active: "suspend" ignores all
+// scaling rules, "pin" keeps the app at InstanceCount instances and "rules" evaluates ScalingRules instead of the
+// rules of the policy.
+type ScheduleOverride struct {
+	Mode          string         `json:"mode"`
+	InstanceCount int            `json:"instance_count,omitempty"`
+	ScalingRules  []*ScalingRule `json:"scaling_rules,omitempty"`
+}
+
+// HasRule returns whether the rule with the given key is one of the rules of a "rules" override.
+func (o *ScheduleOverride) HasRule(ruleKey string) bool {
+	for _, rule := range o.ScalingRules {
+		if rule.Key() == ruleKey {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *ScalingRule) BreachDuration(defaultBreachDurationSecs int) time.Duration {
@@ -91,14 +379,100 @@ func (r *ScalingRule) CoolDown(defaultCoolDownSecs int) time.Duration {
 }
 
 type Trigger struct {
-	AppId                 string `json:"app_id"`
-	MetricType            string `json:"metric_type"`
-	MetricUnit            string `json:"metric_unit"`
-	BreachDurationSeconds int    `json:"breach_duration_secs"`
-	Threshold             int64  `json:"threshold"`
-	Operator              string `json:"operator"`
-	CoolDownSeconds       int    `json:"cool_down_secs"`
-	Adjustment            string `json:"adjustment"`
+	AppId                       string             `json:"app_id"`
+	MetricType                  string             `json:"metric_type"`
+	MetricUnit                  string             `json:"metric_unit"`
+	BreachDurationSeconds       int                `json:"breach_duration_secs"`
+	Threshold                   float64            `json:"threshold"`
+	Operator                    string             `json:"operator"`
+	CoolDownSeconds             int                `json:"cool_down_secs"`
+	Adjustment                  string             `json:"adjustment"`
+	Predictive                  *PredictiveScaling `json:"predictive,omitempty"`
+	TargetValue                 float64            `json:"target_value,omitempty"`
+	ScaleInDamping              float64            `json:"scale_in_damping,omitempty"`
+	MetricValue                 float64            `json:"metric_value,omitempty"`
+	Aggregation                 string             `json:"aggregation,omitempty"`
+	Condition                   *ScalingCondition  `json:"condition,omitempty"`
+	RateOfChange                *RateOfChange      `json:"rate_of_change,omitempty"`
+	Anomaly                     *AnomalyDetection  `json:"anomaly,omitempty"`
+	Mode                        string             `json:"mode,omitempty"`
+	ScaleInStabilizationSeconds int                `json:"scale_in_stabilization_secs,omitempty"`
+	ScaleInMaxStep              int                `json:"scale_in_max_step,omitempty"`
+	CoolDownPerRule             bool               `json:"cool_down_per_rule,omitempty"`
+	ScheduleOnly                bool               `json:"schedule_only,omitempty"`
+	Evidence                    *TriggerEvidence   `json:"evidence,omitempty"`
+}
+
+func (t Trigger) IsShadow() bool {
+	return t.Mode == PolicyModeShadow
+}
+
+func (t Trigger) IsTargetTracking() bool {
+	return t.TargetValue != 0
+}
+
+func (t Trigger) IsCompound() bool {
+	return t.Condition != nil
+}
+
+// RuleKey identifies the rule of the trigger among the rules of the policy, it is the Key of the rule.
+func (t Trigger) RuleKey() string {
+	return ruleKey(t.MetricType, t.Condition, t.Operator, t.Threshold, t.TargetValue, t.AggregationFunction(),
+		t.Adjustment, ruleKind(t.Predictive, t.RateOfChange, t.Anomaly))
+}
+
+func ruleKind(predictive *PredictiveScaling, rateOfChange *RateOfChange, anomaly *AnomalyDetection) string {
+	switch {
+	case predictive != nil:
+		return "predictive"
+	case rateOfChange != nil:
+		return "rate_of_change"
+	case anomaly != nil:
+		return "anomaly"
+	}
+	return ""
+}
+
+// ruleKey returns e.g. "memoryused:avg > 800 +1", "cpu:max:predictive >= 80 +2", "cpu:avg target 60" or
+// "(cpu:avg > 70 and throughput:avg > 500) +1".
+func ruleKey(metricType string, condition *ScalingCondition, operator string, threshold float64, targetValue float64,
+	aggregation string, adjustment string, kind string) string {
+	parts := []string{}
+	switch {
+	case condition != nil:
+		parts = append(parts, condition.key())
+	case targetValue != 0:
+		parts = append(parts, metricType+":"+aggregation, "target", strconv.FormatFloat(targetValue, 'f', -1, 64))
+	default:
+		subject := metricType + ":" + aggregation
+		if kind != "" {
+			subject += ":" + kind
+		}
+		parts = append(parts, subject, operator, strconv.FormatFloat(threshold, 'f', -1, 64))
+	}
+	if adjustment != "" {
+		parts = append(parts, adjustment)
+	}
+	return strings.Join(parts, " ")
+}
+
+// CoolDownKey returns the rule key when the cooldown is tracked per rule, and empty when it is tracked per app.
+func (t Trigger) CoolDownKey() string {
+	if t.CoolDownPerRule {
+		return t.RuleKey()
+	}
+	return ""
+}
+
+func (t Trigger) AggregationFunction() string {
+	if t.Aggregation == "" {
+		return AggregationAvg
+	}
+	return t.Aggregation
+}
+
+func (t Trigger) ScaleInStabilizationWindow() time.Duration {
+	return time.Duration(t.ScaleInStabilizationSeconds) * time.Second
 }
 
 func (t Trigger) BreachDuration() time.Duration {
@@ -114,7 +488,8 @@ func (t Trigger) CoolDown(defaultCoolDownSecs int) time.Duration {
 
 type ActiveSchedule struct {
 	ScheduleId         string
-	InstanceMin        int `json:"instance_min_count"`
-	InstanceMax        int `json:"instance_max_count"`
-	InstanceMinInitial int `json:"initial_min_instance_count"`
+	InstanceMin        int               `json:"instance_min_count"`
+	InstanceMax        int               `json:"instance_max_count"`
+	InstanceMinInitial int               `json:"initial_min_instance_count"`
+	Override           *ScheduleOverride `json:"override,omitempty"`
 }
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the secrets of a policy, e.g. the secrets of its webhooks or the headers of its metric sources,
+// in the responses, the policy versions and the logs. A policy which is saved with RedactedValue keeps the secret of
+// the current policy.
+const RedactedValue = "[REDACTED]"
+
+// secretPaths match the paths of the secrets in a policy, the paths are like the paths of PolicyChange.
+var secretPaths = []*regexp.Regexp{
+	regexp.MustCompile(`^webhooks\[\d+\]\.secret$`),
+	regexp.MustCompile(`^metric_sources\[\d+\]\.headers\..+$`),
+}
+
+// secretURLPaths match the paths of the URLs in a policy whose user info is a secret.
+var secretURLPaths = []*regexp.Regexp{
+	regexp.MustCompile(`^metric_sources\[\d+\]\.url$`),
+}
+
+func isSecretPath(path string) bool {
+	return matchesAny(secretPaths, path)
+}
+
+func isSecretURLPath(path string) bool {
+	return matchesAny(secretURLPaths, path)
+}
+
+func matchesAny(paths []*regexp.Regexp, path string) bool {
+	for _, p := range paths {
+		if p.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL returns the URL with its user info replaced by RedactedValue, so that a URL can be returned or logged
+// without its credentials.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = nil
+	return strings.Replace(u.String(), "//", "//"+RedactedValue+"@", 1)
+}
+
+func isRedactedURL(rawURL string) bool {
+	return strings.Contains(rawURL, "//"+RedactedValue+"@")
+}
+
+// redactValue replaces the secrets within the value of the policy at path, and reports whether it replaced any.
+func redactValue(path string, value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if isSecretPath(path) {
+		return RedactedValue, true
+	}
+	if rawURL, ok := value.(string); ok && isSecretURLPath(path) {
+		redacted := RedactURL(rawURL)
+		return redacted, redacted != rawURL
+	}
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		items := map[string]interface{}{}
+		for key, item := range v {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			var itemRedacted bool
+			items[key], itemRedacted = redactValue(keyPath, item)
+			redacted = redacted || itemRedacted
+		}
+		return items, redacted
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			var itemRedacted bool
+			items[i], itemRedacted = redactValue(fmt.Sprintf("%s[%d]", path, i), item)
+			redacted = redacted || itemRedacted
+		}
+		return items, redacted
+	}
+	return value, false
+}
+
+// RedactPolicy returns the policy JSON with its secrets replaced by RedactedValue. A policy without secrets is
+// returned as it is.
+func RedactPolicy(policyJSON []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(policyJSON, &value); err != nil {
+		return nil, err
+	}
+	redacted, ok := redactValue("", value)
+	if !ok {
+		return policyJSON, nil
+	}
+	return json.Marshal(redacted)
+}
+
+// RedactPolicyForLog returns the policy JSON with its secrets replaced by RedactedValue, or a placeholder when it is
+// not valid JSON, so that a policy can be logged without its secrets.
+func RedactPolicyForLog(policyJSON string) string {
+	redacted, err := RedactPolicy([]byte(policyJSON))
+	if err != nil {
+		return "<invalid policy json>"
+	}
+	return string(redacted)
+}
+
+// RedactPolicyChanges replaces the secrets within the old and new values of the changes by RedactedValue, so that a
+// change of a secret is still listed.
+func RedactPolicyChanges(changes []*PolicyChange) []*PolicyChange {
+	redacted := make([]*PolicyChange, len(changes))
+	for i, change := range changes {
+		redactedChange := &PolicyChange{Path: change.Path}
+		redactedChange.Old, _ = redactValue(change.Path, change.Old)
+		redactedChange.New, _ = redactValue(change.Path, change.New)
+		redacted[i] = redactedChange
+	}
+	return redacted
+}
+
+// RestorePolicySecrets replaces the secrets of the policy JSON which are RedactedValue by the secrets of the current
+// policy, a webhook keeps the secret of the webhook with the same url and a metric source keeps the headers and the
+// user info of the metric source with the same metric type. It returns an error when a redacted secret cannot be found
+// in the current policy.
+func RestorePolicySecrets(policyJSON string, current *ScalingPolicy) (string, error) {
+	policy := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+		return "", err
+	}
+
+	restored := false
+	webhooks, _ := policy["webhooks"].([]interface{})
+	for _, item := range webhooks {
+		webhook, ok := item.(map[string]interface{})
+		if !ok || webhook["secret"] != RedactedValue {
+			continue
+		}
+		url, _ := webhook["url"].(string)
+		secret, found := currentWebhookSecret(current, url)
+		if !found {
+			return "", fmt.Errorf("the secret of webhook %s is redacted, but the current policy has no webhook with this url", url)
+		}
+		webhook["secret"] = secret
+		restored = true
+	}
+
+	sources, _ := policy["metric_sources"].([]interface{})
+	for _, item := range sources {
+		source, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sourceRestored, err := restoreMetricSourceSecrets(source, current)
+		if err != nil {
+			return "", err
+		}
+		restored = restored || sourceRestored
+	}
+
+	if !restored {
+		return policyJSON, nil
+	}
+	restoredJSON, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return string(restoredJSON), nil
+}
+
+func currentWebhookSecret(current *ScalingPolicy, url string) (string, bool) {
+	if current == nil {
+		return "", false
+	}
+	for _, webhook := range current.Webhooks {
+		if webhook.URL == url {
+			return webhook.Secret, true
+		}
+	}
+	return "", false
+}
+
+func restoreMetricSourceSecrets(source map[string]interface{}, current *ScalingPolicy) (bool, error) {
+	metricType, _ := source["metric_type"].(string)
+	currentSource := currentMetricSource(current, metricType)
+	restored := false
+
+	headers, _ := source["headers"].(map[string]interface{})
+	for name, value := range headers {
+		if value != RedactedValue {
+			continue
+		}
+		var secret string
+		found := false
+		if currentSource != nil {
+			secret, found = currentSource.Headers[name]
+		}
+		if !found {
+			return false, fmt.Errorf("the header %s of metric source %s is redacted, but the current policy has no metric source with this header", name, metricType)
+		}
+		headers[name] = secret
+		restored = true
+	}
+
+	rawURL, _ := source["url"].(string)
+	if isRedactedURL(rawURL) {
+		var userInfo *url.Userinfo
+		if currentSource != nil {
+			if currentURL, err := url.Parse(currentSource.URL); err == nil {
+				userInfo = currentURL.User
+			}
+		}
+		if userInfo == nil {
+			return false, fmt.Errorf("the user info of the url of metric source %s is redacted, but the current policy has no metric source with user info", metricType)
+		}
+		source["url"] = strings.Replace(rawURL, "//"+RedactedValue+"@", "//"+userInfo.String()+"@", 1)
+		restored = true
+	}
+	return restored, nil
+}
+
+func currentMetricSource(current *ScalingPolicy, metricType string) *MetricSourceConfig {
+	if current == nil {
+		return nil
+	}
+	for _, source := range current.MetricSources {
+		if source.MetricType == metricType {
+			return source
+		}
+	}
+	return nil
+}
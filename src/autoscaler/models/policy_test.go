@@ -166,5 +166,121 @@ var _ = Describe("Policy", func() {
 					time.Duration(DefaultCoolDownSecs) * time.Second))
 			})
 		})
+		Context("When scaling rule has a condition", func() {
+			BeforeEach(func() {
+				policyJson = &PolicyJson{AppId: testAppId, PolicyStr: `{
+					"instance_min_count":1,
+					"instance_max_count":5,
+					"scaling_rules":[{
+						"condition":{"and":[
+							{"metric_type":"cpu","operator":">","threshold":70},
+							{"or":[
+								{"metric_type":"throughput","operator":">","threshold":500},
+								{"metric_type":"cpu","operator":">","threshold":90,"aggregation":"max"}
+							]}
+						]},
+						"adjustment":"+1"
+					}]
+				}`}
+			})
+			It("should parse the condition", func() {
+				rule := policy.ScalingPolicy.ScalingRules[0]
+				Expect(rule.IsCompound()).To(BeTrue())
+				Expect(rule.Condition.String()).To(Equal("(cpu > 70 and (throughput > 500 or cpu > 90))"))
+				Expect(rule.MetricTypes()).To(Equal([]string{"cpu", "throughput"}))
+				Expect(rule.Key()).To(Equal("(cpu:avg > 70 and (throughput:avg > 500 or cpu:max > 90)) +1"))
+				Expect(rule.ScalingDirection()).To(Equal(ScalingDirectionOut))
+
+				comparisons := rule.Condition.Comparisons()
+				Expect(comparisons).To(HaveLen(3))
+				Expect(comparisons[0].AggregationFunction()).To(Equal(AggregationAvg))
+				Expect(comparisons[2].AggregationFunction()).To(Equal(AggregationMax))
+			})
+		})
+		Context("When scaling rule has no condition", func() {
+			BeforeEach(func() {
+				policyJson = &PolicyJson{AppId: testAppId, PolicyStr: policyStr}
+			})
+			It("should be evaluated on its own metric type", func() {
+				rule := policy.ScalingPolicy.ScalingRules[0]
+				Expect(rule.IsCompound()).To(BeFalse())
+				Expect(rule.MetricTypes()).To(Equal([]string{rule.MetricType}))
+				Expect(rule.Key()).To(Equal("memoryused:avg < 30 -1"))
+				Expect(rule.ScalingDirection()).To(Equal(ScalingDirectionIn))
+			})
+		})
+		Context("When scaling rules are on the same metric", func() {
+			It("should have a key per rule", func() {
+				rules := []ScalingRule{
+					{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1"},
+					{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+2"},
+					{MetricType: "cpu", Operator: ">", Threshold: 90, Adjustment: "+1"},
+					{MetricType: "cpu", Operator: ">=", Threshold: 80, Adjustment: "+1"},
+					{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1", Aggregation: AggregationMax},
+					{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1", Predictive: &PredictiveScaling{}},
+					{MetricType: "cpu", Operator: "<", Threshold: 20, Adjustment: "-1"},
+					{MetricType: "cpu", TargetValue: 60},
+				}
+				keys := map[string]bool{}
+				for _, rule := range rules {
+					keys[rule.Key()] = true
+				}
+				Expect(keys).To(HaveLen(len(rules)))
+				Expect(rules[5].Key()).To(Equal("cpu:avg:predictive > 80 +1"))
+				Expect(rules[7].Key()).To(Equal("cpu:avg target 60"))
+			})
+
+			It("should have the same key as the triggers of the rules", func() {
+				rule := ScalingRule{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1", Aggregation: AggregationAvg}
+				trigger := Trigger{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1"}
+				Expect(trigger.RuleKey()).To(Equal(rule.Key()))
+			})
+		})
+		Context("When scaling rule tracks a target value", func() {
+			It("should scale in both directions", func() {
+				rule := ScalingRule{MetricType: "cpu", TargetValue: 60}
+				Expect(rule.ScalingDirection()).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("ScheduleOnlyRules", func() {
+		var scalingPolicy *ScalingPolicy
+		BeforeEach(func() {
+			rule := &ScalingRule{MetricType: "throughput", Operator: ">", Threshold: 100, Adjustment: "+1"}
+			scalingPolicy = &ScalingPolicy{
+				ScalingRules: []*ScalingRule{{MetricType: "cpu", Operator: ">", Threshold: 80, Adjustment: "+1"}},
+				Schedules: &ScalingSchedules{
+					RecurringSchedules: []*RecurringSchedule{
+						{Override: &ScheduleOverride{Mode: ScheduleOverrideRules, ScalingRules: []*ScalingRule{rule}}},
+						{Override: &ScheduleOverride{Mode: ScheduleOverridePin, InstanceCount: 3}},
+						{},
+					},
+					SpecificDateSchedules: []*SpecificDateSchedule{
+						{Override: &ScheduleOverride{Mode: ScheduleOverrideRules, ScalingRules: []*ScalingRule{
+							{MetricType: "throughput", Operator: ">", Threshold: 100, Adjustment: "+1"},
+							{MetricType: "cpu", Operator: ">", Threshold: 90, Adjustment: "+2"},
+						}}},
+					},
+				},
+			}
+		})
+		It("returns the distinct rules of the rules overrides", func() {
+			Expect(scalingPolicy.ScheduleOnlyRules()).To(Equal([]*ScalingRule{
+				{MetricType: "throughput", Operator: ">", Threshold: 100, Adjustment: "+1"},
+				{MetricType: "cpu", Operator: ">", Threshold: 90, Adjustment: "+2"},
+			}))
+		})
+		It("checks whether a rule is in an override", func() {
+			override := scalingPolicy.Schedules.SpecificDateSchedules[0].Override
+			Expect(override.HasRule("cpu:avg > 90 +2")).To(BeTrue())
+			Expect(override.HasRule(scalingPolicy.ScalingRules[0].Key())).To(BeFalse())
+			Expect(override.HasRule("memoryused:avg > 90 +2")).To(BeFalse())
+		})
+		Context("when the policy has no schedules", func() {
+			It("returns no rules", func() {
+				Expect(ScalingPolicy{}.ScheduleOnlyRules()).To(BeEmpty())
+			})
+		})
 	})
 })
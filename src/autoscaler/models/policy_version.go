@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// PolicyVersion is a saved policy of an app. Versions are never changed or deleted, so that a policy which was
+// overwritten or detached can be rolled back.
+type PolicyVersion struct {
+	AppId      string          `json:"app_id"`
+	Version    int             `json:"version"`
+	PolicyGuid string          `json:"policy_guid"`
+	Author     string          `json:"author"`
+	CreatedAt  int64           `json:"created_at"`
+	Diff       []*PolicyChange `json:"diff"`
+	Policy     json.RawMessage `json:"policy,omitempty"`
+}
+
+// PolicyChange is a value of the policy which was added, changed or removed by a version. Path is like
+// "scaling_rules[0].threshold".
+type PolicyChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// DiffPolicies returns the changes from the old policy to the new one. The old policy is "" when the app did not
+// have a policy.
+func DiffPolicies(oldPolicy string, newPolicy string) ([]*PolicyChange, error) {
+	var oldValue, newValue interface{} = map[string]interface{}{}, nil
+	if oldPolicy != "" {
+		if err := json.Unmarshal([]byte(oldPolicy), &oldValue); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal([]byte(newPolicy), &newValue); err != nil {
+		return nil, err
+	}
+	return diffValues("", oldValue, newValue, []*PolicyChange{}), nil
+}
+
+func diffValues(path string, oldValue interface{}, newValue interface{}, changes []*PolicyChange) []*PolicyChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := []string{}
+		for key := range oldMap {
+			keys = append(keys, key)
+		}
+		for key := range newMap {
+			if _, exists := oldMap[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			changes = diffValues(keyPath, oldMap[key], newMap[key], changes)
+		}
+		return changes
+	}
+
+	oldSlice, oldIsSlice := oldValue.([]interface{})
+	newSlice, newIsSlice := newValue.([]interface{})
+	if oldIsSlice && newIsSlice {
+		for i := 0; i < len(oldSlice) || i < len(newSlice); i++ {
+			var oldItem, newItem interface{}
+			if i < len(oldSlice) {
+				oldItem = oldSlice[i]
+			}
+			if i < len(newSlice) {
+				newItem = newSlice[i]
+			}
+			changes = diffValues(fmt.Sprintf("%s[%d]", path, i), oldItem, newItem, changes)
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		changes = append(changes, &PolicyChange{Path: path, Old: oldValue, New: newValue})
+	}
+	return changes
+}
@@ -0,0 +1,75 @@
+package models_test
+
+import (
+	. "autoscaler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyVersion", func() {
+	Describe("DiffPolicies", func() {
+		var (
+			oldPolicy string
+			newPolicy string
+			changes   []*PolicyChange
+			err       error
+		)
+
+		BeforeEach(func() {
+			oldPolicy = `{"instance_min_count":1,"instance_max_count":4,"scaling_rules":[{"metric_type":"memoryused","threshold":30},{"metric_type":"cpu","threshold":80}]}`
+		})
+
+		JustBeforeEach(func() {
+			changes, err = DiffPolicies(oldPolicy, newPolicy)
+		})
+
+		Context("when the policy is changed", func() {
+			BeforeEach(func() {
+				newPolicy = `{"instance_min_count":2,"instance_max_count":4,"scaling_rules":[{"metric_type":"memoryused","threshold":50}],"cool_down_per_rule":true}`
+			})
+			It("returns the changed, added and removed values", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(Equal([]*PolicyChange{
+					{Path: "cool_down_per_rule", New: true},
+					{Path: "instance_min_count", Old: float64(1), New: float64(2)},
+					{Path: "scaling_rules[0].threshold", Old: float64(30), New: float64(50)},
+					{Path: "scaling_rules[1]", Old: map[string]interface{}{"metric_type": "cpu", "threshold": float64(80)}},
+				}))
+			})
+		})
+
+		Context("when the policy is not changed", func() {
+			BeforeEach(func() {
+				newPolicy = oldPolicy
+			})
+			It("returns no changes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(BeEmpty())
+			})
+		})
+
+		Context("when there is no old policy", func() {
+			BeforeEach(func() {
+				oldPolicy = ""
+				newPolicy = `{"instance_min_count":1,"instance_max_count":4}`
+			})
+			It("returns the values of the new policy", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(Equal([]*PolicyChange{
+					{Path: "instance_max_count", New: float64(4)},
+					{Path: "instance_min_count", New: float64(1)},
+				}))
+			})
+		})
+
+		Context("when a policy is not valid json", func() {
+			BeforeEach(func() {
+				newPolicy = `{"instance_min_count":`
+			})
+			It("errors", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package models
+
+// ScalingGroup is a set of apps which scale together. When a dynamic scaling changes the instances of one
+// member, the instances of the other members follow by their ratio and offset.
+type ScalingGroup struct {
+	GroupId string                `json:"group_id"`
+	Members []*ScalingGroupMember `json:"members"`
+}
+
+// ScalingGroupMember runs ratio * n + offset instances for a group-wide count n.
+type ScalingGroupMember struct {
+	AppId  string  `json:"app_id"`
+	Ratio  float64 `json:"ratio,omitempty"`
+	Offset int     `json:"offset,omitempty"`
+}
+
+func (g *ScalingGroup) Member(appId string) *ScalingGroupMember {
+	for _, member := range g.Members {
+		if member.AppId == appId {
+			return member
+		}
+	}
+	return nil
+}
+
+func (m *ScalingGroupMember) RatioOrDefault() float64 {
+	if m.Ratio <= 0 {
+		return 1
+	}
+	return m.Ratio
+}
+
+// InstancesFor returns the instances of the member which match the instances of the other member of the group.
+func (m *ScalingGroupMember) InstancesFor(other *ScalingGroupMember, otherInstances int) int {
+	count := float64(otherInstances-other.Offset) / other.RatioOrDefault()
+	return int(count*m.RatioOrDefault()+0.5) + m.Offset
+}
@@ -0,0 +1,41 @@
+package models_test
+
+import (
+	. "autoscaler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScalingGroup", func() {
+	var group *ScalingGroup
+
+	BeforeEach(func() {
+		group = &ScalingGroup{
+			GroupId: "a-group-id",
+			Members: []*ScalingGroupMember{
+				{AppId: "frontend"},
+				{AppId: "bff", Ratio: 0.5},
+				{AppId: "worker", Ratio: 2, Offset: 1},
+			},
+		}
+	})
+
+	Describe("Member", func() {
+		It("returns the member of the app", func() {
+			Expect(group.Member("bff")).To(Equal(group.Members[1]))
+			Expect(group.Member("not-a-member")).To(BeNil())
+		})
+	})
+
+	Describe("InstancesFor", func() {
+		It("scales the instances by the ratios and offsets of the members", func() {
+			frontend, bff, worker := group.Members[0], group.Members[1], group.Members[2]
+			Expect(bff.InstancesFor(frontend, 4)).To(Equal(2))
+			Expect(bff.InstancesFor(frontend, 5)).To(Equal(3))
+			Expect(worker.InstancesFor(frontend, 4)).To(Equal(9))
+			Expect(frontend.InstancesFor(worker, 9)).To(Equal(4))
+			Expect(frontend.InstancesFor(bff, 3)).To(Equal(6))
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package models
+
+const (
+	WebhookEventSucceeded           = "succeeded"
+	WebhookEventFailed              = "failed"
+	WebhookEventIgnored             = "ignored"
+	WebhookEventSimulated           = "simulated"
+	WebhookEventMaxInstancesReached = "max_instances_reached"
+)
+
+type Webhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events,omitempty"`
+}
+
+// Subscribes returns whether the webhook wants to be notified of any of the events, a webhook without events
+// subscribes to all of them.
+func (w Webhook) Subscribes(events []string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range w.Events {
+		for _, event := range events {
+			if subscribed == event {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type ScalingNotification struct {
+	AppId          string             `json:"app_id"`
+	Events         []string           `json:"events"`
+	ScalingHistory *AppScalingHistory `json:"scaling_history"`
+}
+
+type WebhookDelivery struct {
+	Id            int64
+	AppId         string
+	URL           string
+	Payload       string
+	Signature     string
+	Attempts      int
+	NextAttemptAt int64
+	LastError     string
+	CreatedAt     int64
+}
@@ -13,12 +13,18 @@ const (
 	AggregatedMetricHistoriesPath         = "/v1/apps/{appid}/aggregated_metric_histories/{metrictype}"
 	GetAggregatedMetricHistoriesRouteName = "GetAggregatedMetricHistories"
 
+	AnomaliesPath         = "/v1/apps/{appid}/anomalies"
+	GetAnomaliesRouteName = "GetAnomalies"
+
 	ScalePath      = "/v1/apps/{appid}/scale"
 	ScaleRouteName = "Scale"
 
 	ScalingHistoriesPath         = "/v1/apps/{appid}/scaling_histories"
 	GetScalingHistoriesRouteName = "GetScalingHistories"
 
+	ScalingHistoryExplanationPath         = "/v1/apps/{appid}/scaling_histories/{historyid}/explanation"
+	GetScalingHistoryExplanationRouteName = "GetScalingHistoryExplanation"
+
 	ActiveSchedulePath            = "/v1/apps/{appid}/active_schedules/{scheduleid}"
 	SetActiveScheduleRouteName    = "SetActiveSchedule"
 	DeleteActiveScheduleRouteName = "DeleteActiveSchedule"
@@ -26,6 +32,10 @@ const (
 	ActiveSchedulesPath         = "/v1/apps/{appid}/active_schedules"
 	GetActiveSchedulesRouteName = "GetActiveSchedules"
 
+	PausePath          = "/v1/apps/{appid}/pause"
+	PauseAppRouteName  = "PauseApp"
+	ResumeAppRouteName = "ResumeApp"
+
 	SyncActiveSchedulesPath      = "/v1/syncSchedules"
 	SyncActiveSchedulesRouteName = "SyncActiveSchedules"
 
@@ -40,23 +50,64 @@ const (
 	BrokerCreateBindingRouteName = "CreateBinding"
 	BrokerDeleteBindingRouteName = "DeleteBinding"
 
-	EnvelopePath               = "/v1/envelopes"
-	EnvelopeReportRouteName    = "ReportEnvelope"
-	CustomMetricsPath          = "/v1/apps/{appid}/metrics"
-	PostCustomMetricsRouteName = "PostCustomMetrics"
-	SchedulePath               = "/v1/apps/{appId}/schedules"
-	UpdateScheduleRouteName    = "UpdateSchedule"
-	DeleteScheduleRouteName    = "DeleteSchedule"
+	EnvelopePath                    = "/v1/envelopes"
+	EnvelopeReportRouteName         = "ReportEnvelope"
+	CustomMetricsPath               = "/v1/apps/{appid}/metrics"
+	PostCustomMetricsRouteName      = "PostCustomMetrics"
+	CustomMetricsBatchPath          = "/v1/apps/{appid}/metrics/batch"
+	PostCustomMetricsBatchRouteName = "PostCustomMetricsBatch"
+	OTLPMetricsPath                 = "/v1/apps/{appid}/otlp/v1/metrics"
+	PostOTLPMetricsRouteName        = "PostOTLPMetrics"
+	SchedulePath                    = "/v1/apps/{appId}/schedules"
+	UpdateScheduleRouteName         = "UpdateSchedule"
+	DeleteScheduleRouteName         = "DeleteSchedule"
 
 	PublicApiScalingHistoryPath      = "/{appId}/scaling_histories"
 	PublicApiScalingHistoryRouteName = "GetPublicApiScalingHistories"
 
+	PublicApiScalingHistoryExplanationPath      = "/{appId}/scaling_histories/{historyId}/explanation"
+	PublicApiScalingHistoryExplanationRouteName = "GetPublicApiScalingHistoryExplanation"
+
 	PublicApiMetricsHistoryPath      = "/{appId}/metric_histories/{metricType}"
 	PublicApiMetricsHistoryRouteName = "GetPublicApiMetricsHistories"
 
 	PublicApiAggregatedMetricsHistoryPath      = "/{appId}/aggregated_metric_histories/{metricType}"
 	PublicApiAggregatedMetricsHistoryRouteName = "GetPublicApiAggregatedMetricsHistories"
 
+	PublicApiAnomaliesPath      = "/{appId}/anomalies"
+	PublicApiAnomaliesRouteName = "GetPublicApiAnomalies"
+
+	PublicApiPolicyPath            = "/{appId}/policy"
+	PublicApiGetPolicyRouteName    = "GetPolicy"
+	PublicApiAttachPolicyRouteName = "AttachPolicy"
+	PublicApiDetachPolicyRouteName = "DetachPolicy"
+
+	PublicApiPolicyVersionsPath         = "/{appId}/policy/versions"
+	PublicApiGetPolicyVersionsRouteName = "GetPolicyVersions"
+
+	PublicApiPolicyVersionPath         = "/{appId}/policy/versions/{version}"
+	PublicApiGetPolicyVersionRouteName = "GetPolicyVersion"
+
+	PublicApiRollbackPolicyPath      = "/{appId}/policy/versions/{version}/rollback"
+	PublicApiRollbackPolicyRouteName = "RollbackPolicy"
+
+	PublicApiEventStreamPath      = "/{appId}/events"
+	PublicApiEventStreamRouteName = "StreamPublicApiEvents"
+
+	PublicApiScalingGroupPath            = "/{appId}/scaling_group"
+	PublicApiGetScalingGroupRouteName    = "GetScalingGroup"
+	PublicApiSaveScalingGroupRouteName   = "SaveScalingGroup"
+	PublicApiDeleteScalingGroupRouteName = "DeleteScalingGroup"
+
+	PublicApiPauseAutoscalingPath      = "/{appId}/autoscaling:pause"
+	PublicApiPauseAutoscalingRouteName = "PauseAutoscaling"
+
+	PublicApiResumeAutoscalingPath      = "/{appId}/autoscaling:resume"
+	PublicApiResumeAutoscalingRouteName = "ResumeAutoscaling"
+
+	PublicApiRotateCustomMetricsCredentialsPath      = "/{appId}/custom_metrics_credentials:rotate"
+	PublicApiRotateCustomMetricsCredentialsRouteName = "RotateCustomMetricsCredentials"
+
 	PublicApiInfoPath      = "/v1/info"
 	PublicApiInfoRouteName = "GetPublicApiInfo"
 
@@ -94,12 +145,16 @@ func newRouters() *AutoScalerRoute {
 	instance.metricsCollectorRoutes.Path(MetricHistoriesPath).Methods(http.MethodGet).Name(GetMetricHistoriesRouteName)
 
 	instance.eventGeneratorRoutes.Path(AggregatedMetricHistoriesPath).Methods(http.MethodGet).Name(GetAggregatedMetricHistoriesRouteName)
+	instance.eventGeneratorRoutes.Path(AnomaliesPath).Methods(http.MethodGet).Name(GetAnomaliesRouteName)
 
 	instance.scalingEngineRoutes.Path(ScalePath).Methods(http.MethodPost).Name(ScaleRouteName)
 	instance.scalingEngineRoutes.Path(ScalingHistoriesPath).Methods(http.MethodGet).Name(GetScalingHistoriesRouteName)
+	instance.scalingEngineRoutes.Path(ScalingHistoryExplanationPath).Methods(http.MethodGet).Name(GetScalingHistoryExplanationRouteName)
 	instance.scalingEngineRoutes.Path(ActiveSchedulePath).Methods(http.MethodPut).Name(SetActiveScheduleRouteName)
 	instance.scalingEngineRoutes.Path(ActiveSchedulePath).Methods(http.MethodDelete).Name(DeleteActiveScheduleRouteName)
 	instance.scalingEngineRoutes.Path(ActiveSchedulesPath).Methods(http.MethodGet).Name(GetActiveSchedulesRouteName)
+	instance.scalingEngineRoutes.Path(PausePath).Methods(http.MethodPut).Name(PauseAppRouteName)
+	instance.scalingEngineRoutes.Path(PausePath).Methods(http.MethodDelete).Name(ResumeAppRouteName)
 	instance.scalingEngineRoutes.Path(SyncActiveSchedulesPath).Methods(http.MethodPut).Name(SyncActiveSchedulesRouteName)
 
 	instance.brokerRoutes.Path(BrokerCatalogPath).Methods(http.MethodGet).Name(BrokerCatalogRouteName)
@@ -110,6 +165,8 @@ func newRouters() *AutoScalerRoute {
 	instance.brokerRoutes.Path(BrokerBindingPath).Methods(http.MethodPut).Name(BrokerCreateBindingRouteName)
 	instance.brokerRoutes.Path(BrokerBindingPath).Methods(http.MethodDelete).Name(BrokerDeleteBindingRouteName)
 	instance.metricsForwarderRoutes.Path(CustomMetricsPath).Methods(http.MethodPost).Name(PostCustomMetricsRouteName)
+	instance.metricsForwarderRoutes.Path(CustomMetricsBatchPath).Methods(http.MethodPost).Name(PostCustomMetricsBatchRouteName)
+	instance.metricsForwarderRoutes.Path(OTLPMetricsPath).Methods(http.MethodPost).Name(PostOTLPMetricsRouteName)
 
 	instance.metricServerRoutes.Path(EnvelopePath).Name(EnvelopeReportRouteName)
 
@@ -120,8 +177,23 @@ func newRouters() *AutoScalerRoute {
 
 	instance.publicApiProtectedRoutes = instance.publicApiRoutes.PathPrefix("/v1/apps").Subrouter()
 	instance.publicApiProtectedRoutes.Path(PublicApiScalingHistoryPath).Methods(http.MethodGet).Name(PublicApiScalingHistoryRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiScalingHistoryExplanationPath).Methods(http.MethodGet).Name(PublicApiScalingHistoryExplanationRouteName)
 	instance.publicApiProtectedRoutes.Path(PublicApiMetricsHistoryPath).Methods(http.MethodGet).Name(PublicApiMetricsHistoryRouteName)
 	instance.publicApiProtectedRoutes.Path(PublicApiAggregatedMetricsHistoryPath).Methods(http.MethodGet).Name(PublicApiAggregatedMetricsHistoryRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiAnomaliesPath).Methods(http.MethodGet).Name(PublicApiAnomaliesRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPolicyPath).Methods(http.MethodGet).Name(PublicApiGetPolicyRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPolicyPath).Methods(http.MethodPut).Name(PublicApiAttachPolicyRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPolicyPath).Methods(http.MethodDelete).Name(PublicApiDetachPolicyRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPolicyVersionsPath).Methods(http.MethodGet).Name(PublicApiGetPolicyVersionsRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPolicyVersionPath).Methods(http.MethodGet).Name(PublicApiGetPolicyVersionRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiRollbackPolicyPath).Methods(http.MethodPost).Name(PublicApiRollbackPolicyRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiEventStreamPath).Methods(http.MethodGet).Name(PublicApiEventStreamRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiScalingGroupPath).Methods(http.MethodGet).Name(PublicApiGetScalingGroupRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiScalingGroupPath).Methods(http.MethodPut).Name(PublicApiSaveScalingGroupRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiScalingGroupPath).Methods(http.MethodDelete).Name(PublicApiDeleteScalingGroupRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiPauseAutoscalingPath).Methods(http.MethodPost).Name(PublicApiPauseAutoscalingRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiResumeAutoscalingPath).Methods(http.MethodPost).Name(PublicApiResumeAutoscalingRouteName)
+	instance.publicApiProtectedRoutes.Path(PublicApiRotateCustomMetricsCredentialsPath).Methods(http.MethodPost).Name(PublicApiRotateCustomMetricsCredentialsRouteName)
 
 	return instance
 
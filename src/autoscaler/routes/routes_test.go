@@ -89,6 +89,31 @@ var _ = Describe("Routes", func() {
 				})
 			})
 		})
+		Context("PublicApiScalingHistoryExplanationRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiScalingHistoryExplanationRouteName).URLPath("appId", testAppId, "historyId", "123")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/scaling_histories/123/explanation"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiScalingHistoryExplanationRouteName).URLPath("wrongVariable", testAppId, "historyId", "123")
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+
+			Context("when provide not enough route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiScalingHistoryExplanationRouteName).URLPath("appId", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
 		Context("PublicApiMetricsHistoryRouteName", func() {
 
 			Context("when provide correct route variable", func() {
@@ -139,6 +164,265 @@ var _ = Describe("Routes", func() {
 				})
 			})
 		})
+		Context("PublicApiAnomaliesRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiAnomaliesRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/anomalies"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiAnomaliesRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiGetPolicyRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+
+			Context("when provide not enough route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyRouteName).URLPath()
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiAttachPolicyRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiAttachPolicyRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiAttachPolicyRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+
+			Context("when provide not enough route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiAttachPolicyRouteName).URLPath()
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiDetachPolicyRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiDetachPolicyRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiDetachPolicyRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+
+			Context("when provide not enough route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiDetachPolicyRouteName).URLPath()
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiEventStreamRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiEventStreamRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/events"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiEventStreamRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiGetPolicyVersionsRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyVersionsRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy/versions"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyVersionsRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiGetPolicyVersionRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyVersionRouteName).URLPath("appId", testAppId, "version", "3")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy/versions/3"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetPolicyVersionRouteName).URLPath("appId", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiRollbackPolicyRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiRollbackPolicyRouteName).URLPath("appId", testAppId, "version", "3")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/policy/versions/3/rollback"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiRollbackPolicyRouteName).URLPath("appId", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiGetScalingGroupRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetScalingGroupRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/scaling_group"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiGetScalingGroupRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiSaveScalingGroupRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiSaveScalingGroupRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/scaling_group"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiSaveScalingGroupRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiDeleteScalingGroupRouteName", func() {
+
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiDeleteScalingGroupRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/scaling_group"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiDeleteScalingGroupRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiPauseAutoscalingRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiPauseAutoscalingRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/autoscaling:pause"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiPauseAutoscalingRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiResumeAutoscalingRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiResumeAutoscalingRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/autoscaling:resume"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiResumeAutoscalingRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("PublicApiRotateCustomMetricsCredentialsRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiRotateCustomMetricsCredentialsRouteName).URLPath("appId", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/custom_metrics_credentials:rotate"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.PublicApiProtectedRoutes().Get(routes.PublicApiRotateCustomMetricsCredentialsRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Describe("BrokerRoutes", func() {
@@ -276,6 +560,23 @@ var _ = Describe("Routes", func() {
 			})
 		})
 
+		Context("GetAnomaliesRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.EventGeneratorRoutes().Get(routes.GetAnomaliesRouteName).URLPath("appid", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/anomalies"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.EventGeneratorRoutes().Get(routes.GetAnomaliesRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+
 	})
 
 	Describe("ScalingEngineRoutes", func() {
@@ -331,6 +632,32 @@ var _ = Describe("Routes", func() {
 			})
 		})
 
+		Context("GetScalingHistoryExplanationRoute", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.ScalingEngineRoutes().Get(routes.GetScalingHistoryExplanationRouteName).URLPath("appid", testAppId, "historyid", "123")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/scaling_histories/123/explanation"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.ScalingEngineRoutes().Get(routes.GetScalingHistoryExplanationRouteName).URLPath("wrongVariable", testAppId, "historyid", "123")
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+
+			Context("when provide not enough route variable", func() {
+				It("should return error", func() {
+					_, err := routes.ScalingEngineRoutes().Get(routes.GetScalingHistoryExplanationRouteName).URLPath("appid", testAppId)
+					Expect(err).To(HaveOccurred())
+
+				})
+			})
+		})
+
 		Context("SetActiveScheduleRoute", func() {
 			Context("when provide correct route variable", func() {
 				It("should return the correct path", func() {
@@ -408,6 +735,40 @@ var _ = Describe("Routes", func() {
 				})
 			})
 		})
+
+		Context("PauseAppRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.ScalingEngineRoutes().Get(routes.PauseAppRouteName).URLPath("appid", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/pause"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.ScalingEngineRoutes().Get(routes.PauseAppRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+
+		Context("ResumeAppRouteName", func() {
+			Context("when provide correct route variable", func() {
+				It("should return the correct path", func() {
+					path, err := routes.ScalingEngineRoutes().Get(routes.ResumeAppRouteName).URLPath("appid", testAppId)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/pause"))
+				})
+			})
+
+			Context("when provide wrong route variable", func() {
+				It("should return error", func() {
+					_, err := routes.ScalingEngineRoutes().Get(routes.ResumeAppRouteName).URLPath("wrongVariable", testAppId)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Describe("MetricServerRoutes", func() {
@@ -429,4 +790,24 @@ var _ = Describe("Routes", func() {
 			})
 		})
 	})
+
+	Context("PostCustomMetricsBatchRoute", func() {
+		Context("when provide correct route variable", func() {
+			It("should return the correct path", func() {
+				path, err := routes.MetricsForwarderRoutes().Get(routes.PostCustomMetricsBatchRouteName).URLPath("appid", testAppId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/metrics/batch"))
+			})
+		})
+	})
+
+	Context("PostOTLPMetricsRoute", func() {
+		Context("when provide correct route variable", func() {
+			It("should return the correct path", func() {
+				path, err := routes.MetricsForwarderRoutes().Get(routes.PostOTLPMetricsRouteName).URLPath("appid", testAppId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(path.Path).To(Equal("/v1/apps/" + testAppId + "/otlp/v1/metrics"))
+			})
+		})
+	})
 })
@@ -12,8 +12,10 @@ import (
 	"autoscaler/healthendpoint"
 	"autoscaler/scalingengine"
 	"autoscaler/scalingengine/config"
+	"autoscaler/scalingengine/pause"
 	"autoscaler/scalingengine/schedule"
 	"autoscaler/scalingengine/server"
+	"autoscaler/scalingengine/webhook"
 
 	"code.cloudfoundry.org/cfhttp"
 	"code.cloudfoundry.org/clock"
@@ -98,7 +100,8 @@ func main() {
 		httpStatusCollector,
 	}, true, logger.Session("scalingengine-prometheus"))
 
-	scalingEngine := scalingengine.NewScalingEngine(logger, cfClient, policyDB, scalingEngineDB, eClock, conf.DefaultCoolDownSecs, conf.LockSize)
+	notifier := webhook.NewNotifier(logger, policyDB, scalingEngineDB, eClock)
+	scalingEngine := scalingengine.NewScalingEngine(logger, cfClient, policyDB, scalingEngineDB, notifier, eClock, conf.DefaultCoolDownSecs, conf.LockSize)
 	synchronizer := schedule.NewActiveScheduleSychronizer(logger, schedulerDB, scalingEngineDB, scalingEngine)
 
 	httpServer, err := server.NewServer(logger.Session("http-server"), conf, scalingEngineDB, scalingEngine, synchronizer, httpStatusCollector)
@@ -113,9 +116,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	webhookGuard, err := helpers.NewOutboundGuard(conf.Webhook.OutboundConfig)
+	if err != nil {
+		logger.Error("failed to create outbound guard for webhooks", err, lager.Data{"webhook": conf.Webhook})
+		os.Exit(1)
+	}
+	deliverer := webhook.NewDeliverer(logger, scalingEngineDB, webhookGuard.NewHTTPClient(), eClock, webhook.DelivererConfig{
+		PollInterval:   conf.Webhook.PollInterval,
+		BatchSize:      conf.Webhook.BatchSize,
+		MaxAttempts:    conf.Webhook.MaxAttempts,
+		InitialBackoff: conf.Webhook.InitialBackoff,
+		MaxBackoff:     conf.Webhook.MaxBackoff,
+		Lease:          conf.Webhook.Lease,
+	})
+
+	pauseExpirer := pause.NewExpirer(logger, policyDB, scalingEngine, eClock, conf.PauseExpiryInterval)
+
 	members := grouper.Members{
 		{"http_server", httpServer},
 		{"health_server", healthServer},
+		{"webhook_deliverer", deliverer},
+		{"pause_expirer", pauseExpirer},
 	}
 
 	monitor := ifrit.Invoke(sigmon.New(grouper.NewOrdered(os.Interrupt, members)))
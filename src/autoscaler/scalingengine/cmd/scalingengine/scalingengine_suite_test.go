@@ -105,6 +105,14 @@ var _ = SynchronizedBeforeSuite(
 		conf.DefaultCoolDownSecs = 300
 		conf.LockSize = 32
 		conf.HttpClientTimeout = 10 * time.Second
+		conf.Webhook = config.WebhookConfig{
+			PollInterval:   time.Second,
+			BatchSize:      100,
+			MaxAttempts:    10,
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     time.Minute,
+			Lease:          time.Minute,
+		}
 
 		configFile = writeConfig(&conf)
 
@@ -23,6 +23,13 @@ const (
 	DefaultDBLockRetryInterval        time.Duration = 5 * time.Second
 	DefaultDBLockTTL                  time.Duration = 15 * time.Second
 	DefaultHttpClientTimeout          time.Duration = 5 * time.Second
+	DefaultWebhookPollInterval        time.Duration = 5 * time.Second
+	DefaultWebhookBatchSize                         = 100
+	DefaultWebhookMaxAttempts                       = 10
+	DefaultWebhookInitialBackoff      time.Duration = 10 * time.Second
+	DefaultWebhookMaxBackoff          time.Duration = 30 * time.Minute
+	DefaultWebhookLease               time.Duration = 5 * time.Minute
+	DefaultPauseExpiryInterval        time.Duration = 30 * time.Second
 )
 
 var defaultCFConfig = cf.CFConfig{
@@ -61,6 +68,25 @@ var defaultSynchronizerConfig = SynchronizerConfig{
 	ActiveScheduleSyncInterval: DefaultActiveScheduleSyncInterval,
 }
 
+type WebhookConfig struct {
+	PollInterval           time.Duration `yaml:"poll_interval"`
+	BatchSize              int           `yaml:"batch_size"`
+	MaxAttempts            int           `yaml:"max_attempts"`
+	InitialBackoff         time.Duration `yaml:"initial_backoff"`
+	MaxBackoff             time.Duration `yaml:"max_backoff"`
+	Lease                  time.Duration `yaml:"lease"`
+	helpers.OutboundConfig `yaml:",inline"`
+}
+
+var defaultWebhookConfig = WebhookConfig{
+	PollInterval:   DefaultWebhookPollInterval,
+	BatchSize:      DefaultWebhookBatchSize,
+	MaxAttempts:    DefaultWebhookMaxAttempts,
+	InitialBackoff: DefaultWebhookInitialBackoff,
+	MaxBackoff:     DefaultWebhookMaxBackoff,
+	Lease:          DefaultWebhookLease,
+}
+
 type Config struct {
 	CF                  cf.CFConfig           `yaml:"cf"`
 	Logging             helpers.LoggingConfig `yaml:"logging"`
@@ -70,15 +96,19 @@ type Config struct {
 	DefaultCoolDownSecs int                   `yaml:"defaultCoolDownSecs"`
 	LockSize            int                   `yaml:"lockSize"`
 	HttpClientTimeout   time.Duration         `yaml:"http_client_timeout"`
+	Webhook             WebhookConfig         `yaml:"webhook"`
+	PauseExpiryInterval time.Duration         `yaml:"pause_expiry_interval"`
 }
 
 func LoadConfig(reader io.Reader) (*Config, error) {
 	conf := &Config{
-		CF:                defaultCFConfig,
-		Logging:           defaultLoggingConfig,
-		Server:            defaultServerConfig,
-		Health:            defaultHealthConfig,
-		HttpClientTimeout: DefaultHttpClientTimeout,
+		CF:                  defaultCFConfig,
+		Logging:             defaultLoggingConfig,
+		Server:              defaultServerConfig,
+		Health:              defaultHealthConfig,
+		HttpClientTimeout:   DefaultHttpClientTimeout,
+		Webhook:             defaultWebhookConfig,
+		PauseExpiryInterval: DefaultPauseExpiryInterval,
 	}
 
 	bytes, err := ioutil.ReadAll(reader)
@@ -126,6 +156,38 @@ func (c *Config) Validate() error {
 	if c.HttpClientTimeout <= time.Duration(0) {
 		return fmt.Errorf("Configuration error: http_client_timeout is less-equal than 0")
 	}
+
+	if c.Webhook.PollInterval <= time.Duration(0) {
+		return fmt.Errorf("Configuration error: webhook.poll_interval is less-equal than 0")
+	}
+
+	if c.Webhook.BatchSize <= 0 {
+		return fmt.Errorf("Configuration error: webhook.batch_size is less-equal than 0")
+	}
+
+	if c.Webhook.MaxAttempts <= 0 {
+		return fmt.Errorf("Configuration error: webhook.max_attempts is less-equal than 0")
+	}
+
+	if c.Webhook.InitialBackoff <= time.Duration(0) {
+		return fmt.Errorf("Configuration error: webhook.initial_backoff is less-equal than 0")
+	}
+
+	if c.Webhook.MaxBackoff < c.Webhook.InitialBackoff {
+		return fmt.Errorf("Configuration error: webhook.max_backoff is less than webhook.initial_backoff")
+	}
+
+	if c.Webhook.Lease <= c.HttpClientTimeout {
+		return fmt.Errorf("Configuration error: webhook.lease is less-equal than http_client_timeout")
+	}
+
+	if _, err := helpers.NewOutboundGuard(c.Webhook.OutboundConfig); err != nil {
+		return fmt.Errorf("Configuration error: webhook: %s", err.Error())
+	}
+
+	if c.PauseExpiryInterval <= time.Duration(0) {
+		return fmt.Errorf("Configuration error: pause_expiry_interval is less-equal than 0")
+	}
 	return nil
 
 }
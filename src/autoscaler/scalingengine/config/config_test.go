@@ -3,6 +3,7 @@ package config_test
 import (
 	"autoscaler/cf"
 	"autoscaler/db"
+	"autoscaler/helpers"
 	. "autoscaler/scalingengine/config"
 
 	. "github.com/onsi/ginkgo"
@@ -89,6 +90,16 @@ db:
 defaultCoolDownSecs: 300
 lockSize: 32
 http_client_timeout: 10s
+webhook:
+  poll_interval: 10s
+  batch_size: 50
+  max_attempts: 5
+  initial_backoff: 30s
+  max_backoff: 1h
+  lease: 2m
+  allowed_hosts: [hooks.example.com, "*.example.org"]
+  allowed_networks: [10.10.0.0/16]
+pause_expiry_interval: 1m
 `)
 			})
 
@@ -138,6 +149,21 @@ http_client_timeout: 10s
 				Expect(conf.LockSize).To(Equal(32))
 
 				Expect(conf.HttpClientTimeout).To(Equal(10 * time.Second))
+
+				Expect(conf.Webhook).To(Equal(WebhookConfig{
+					PollInterval:   10 * time.Second,
+					BatchSize:      50,
+					MaxAttempts:    5,
+					InitialBackoff: 30 * time.Second,
+					MaxBackoff:     time.Hour,
+					Lease:          2 * time.Minute,
+					OutboundConfig: helpers.OutboundConfig{
+						AllowedHosts:    []string{"hooks.example.com", "*.example.org"},
+						AllowedNetworks: []string{"10.10.0.0/16"},
+					},
+				}))
+
+				Expect(conf.PauseExpiryInterval).To(Equal(time.Minute))
 			})
 		})
 
@@ -189,6 +215,17 @@ lockSize: 32
 					}))
 
 				Expect(conf.HttpClientTimeout).To(Equal(5 * time.Second))
+
+				Expect(conf.Webhook).To(Equal(WebhookConfig{
+					PollInterval:   DefaultWebhookPollInterval,
+					BatchSize:      DefaultWebhookBatchSize,
+					MaxAttempts:    DefaultWebhookMaxAttempts,
+					InitialBackoff: DefaultWebhookInitialBackoff,
+					MaxBackoff:     DefaultWebhookMaxBackoff,
+					Lease:          DefaultWebhookLease,
+				}))
+
+				Expect(conf.PauseExpiryInterval).To(Equal(DefaultPauseExpiryInterval))
 			})
 		})
 
@@ -811,6 +848,15 @@ http_client_timeout: 10k
 			conf.DefaultCoolDownSecs = 300
 			conf.LockSize = 32
 			conf.HttpClientTimeout = 10 * time.Second
+			conf.Webhook = WebhookConfig{
+				PollInterval:   5 * time.Second,
+				BatchSize:      100,
+				MaxAttempts:    10,
+				InitialBackoff: 10 * time.Second,
+				MaxBackoff:     30 * time.Minute,
+				Lease:          5 * time.Minute,
+			}
+			conf.PauseExpiryInterval = 30 * time.Second
 		})
 
 		JustBeforeEach(func() {
@@ -901,6 +947,78 @@ http_client_timeout: 10k
 				Expect(err).To(MatchError("Configuration error: http_client_timeout is less-equal than 0"))
 			})
 		})
+
+		Context("when webhook.poll_interval is <= 0", func() {
+			BeforeEach(func() {
+				conf.Webhook.PollInterval = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.poll_interval is less-equal than 0"))
+			})
+		})
+
+		Context("when webhook.batch_size is <= 0", func() {
+			BeforeEach(func() {
+				conf.Webhook.BatchSize = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.batch_size is less-equal than 0"))
+			})
+		})
+
+		Context("when webhook.max_attempts is <= 0", func() {
+			BeforeEach(func() {
+				conf.Webhook.MaxAttempts = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.max_attempts is less-equal than 0"))
+			})
+		})
+
+		Context("when webhook.initial_backoff is <= 0", func() {
+			BeforeEach(func() {
+				conf.Webhook.InitialBackoff = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.initial_backoff is less-equal than 0"))
+			})
+		})
+
+		Context("when webhook.max_backoff is less than webhook.initial_backoff", func() {
+			BeforeEach(func() {
+				conf.Webhook.MaxBackoff = 5 * time.Second
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.max_backoff is less than webhook.initial_backoff"))
+			})
+		})
+
+		Context("when webhook.lease is <= http_client_timeout", func() {
+			BeforeEach(func() {
+				conf.Webhook.Lease = 10 * time.Second
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: webhook.lease is less-equal than http_client_timeout"))
+			})
+		})
+
+		Context("when an allowed network of the webhooks is invalid", func() {
+			BeforeEach(func() {
+				conf.Webhook.AllowedNetworks = []string{"not-a-network"}
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError(ContainSubstring("Configuration error: webhook: invalid allowed network")))
+			})
+		})
+
+		Context("when pause_expiry_interval is <= 0", func() {
+			BeforeEach(func() {
+				conf.PauseExpiryInterval = 0
+			})
+			It("should error", func() {
+				Expect(err).To(MatchError("Configuration error: pause_expiry_interval is less-equal than 0"))
+			})
+		})
 	})
 
 })
@@ -0,0 +1,70 @@
+package pause
+
+import (
+	"autoscaler/db"
+	"autoscaler/scalingengine"
+
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+// Expirer expires the pauses of the apps once their expire_at has passed, so that the active schedules of the apps
+// are applied again, as they are when an app is resumed.
+type Expirer struct {
+	logger   lager.Logger
+	policyDB db.PolicyDB
+	engine   scalingengine.ScalingEngine
+	clock    clock.Clock
+	interval time.Duration
+}
+
+func NewExpirer(logger lager.Logger, policyDB db.PolicyDB, engine scalingengine.ScalingEngine, clock clock.Clock, interval time.Duration) *Expirer {
+	return &Expirer{
+		logger:   logger.Session("pause-expirer"),
+		policyDB: policyDB,
+		engine:   engine,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+func (e *Expirer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+	ticker := e.clock.NewTicker(e.interval)
+
+	e.logger.Info("started", lager.Data{"interval": e.interval})
+
+	for {
+		e.Expire()
+		select {
+		case <-signals:
+			ticker.Stop()
+			e.logger.Info("stopped")
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// Expire expires the pauses which have an expire_at in the past.
+func (e *Expirer) Expire() {
+	pauses, err := e.policyDB.RetrieveAppPauses()
+	if err != nil {
+		e.logger.Error("failed-to-retrieve-app-pauses", err)
+		return
+	}
+
+	now := e.clock.Now().UnixNano()
+	for appId, pause := range pauses {
+		if pause.ExpireAt == 0 || pause.IsActive(now) {
+			continue
+		}
+		err = e.engine.ExpireAppPause(appId)
+		if err != nil {
+			e.logger.Error("failed-to-expire-app-pause", err, lager.Data{"appId": appId})
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package pause_test
+
+import (
+	"autoscaler/fakes"
+	"autoscaler/models"
+	. "autoscaler/scalingengine/pause"
+
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Expirer", func() {
+	var (
+		expirer  *Expirer
+		policyDB *fakes.FakePolicyDB
+		engine   *fakes.FakeScalingEngine
+		clock    *fakeclock.FakeClock
+		buffer   *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		policyDB = &fakes.FakePolicyDB{}
+		engine = &fakes.FakeScalingEngine{}
+		logger := lagertest.NewTestLogger("expirer-test")
+		buffer = logger.Buffer()
+		clock = fakeclock.NewFakeClock(time.Now())
+		expirer = NewExpirer(logger, policyDB, engine, clock, 30*time.Second)
+	})
+
+	Describe("Expire", func() {
+		JustBeforeEach(func() {
+			expirer.Expire()
+		})
+
+		Context("when pauses have expired", func() {
+			BeforeEach(func() {
+				policyDB.RetrieveAppPausesReturns(map[string]*models.AppPause{
+					"expired-app-id":   {AppId: "expired-app-id", ExpireAt: clock.Now().Add(-time.Second).UnixNano()},
+					"paused-app-id":    {AppId: "paused-app-id", ExpireAt: clock.Now().Add(time.Second).UnixNano()},
+					"unlimited-app-id": {AppId: "unlimited-app-id"},
+					"expiring-app-id":  {AppId: "expiring-app-id", ExpireAt: clock.Now().UnixNano()},
+				}, nil)
+			})
+
+			It("expires only the pauses whose expire_at has passed", func() {
+				Expect(engine.ExpireAppPauseCallCount()).To(Equal(2))
+				Expect([]string{engine.ExpireAppPauseArgsForCall(0), engine.ExpireAppPauseArgsForCall(1)}).To(ConsistOf("expired-app-id", "expiring-app-id"))
+			})
+
+			Context("when expiring a pause fails", func() {
+				BeforeEach(func() {
+					engine.ExpireAppPauseReturns(errors.New("an error"))
+				})
+
+				It("logs the error and continues with the other pauses", func() {
+					Expect(engine.ExpireAppPauseCallCount()).To(Equal(2))
+					Eventually(buffer).Should(gbytes.Say("failed-to-expire-app-pause"))
+				})
+			})
+		})
+
+		Context("when retrieving the pauses fails", func() {
+			BeforeEach(func() {
+				policyDB.RetrieveAppPausesReturns(nil, errors.New("an error"))
+			})
+
+			It("logs the error", func() {
+				Expect(engine.ExpireAppPauseCallCount()).To(BeZero())
+				Eventually(buffer).Should(gbytes.Say("failed-to-retrieve-app-pauses"))
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		var process ifrit.Process
+
+		BeforeEach(func() {
+			policyDB.RetrieveAppPausesReturns(map[string]*models.AppPause{}, nil)
+			process = ifrit.Invoke(expirer)
+		})
+
+		AfterEach(func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("expires the pauses on every interval", func() {
+			Eventually(policyDB.RetrieveAppPausesCallCount).Should(Equal(1))
+			Eventually(clock.WatcherCount).Should(Equal(1))
+			clock.Increment(30 * time.Second)
+			Eventually(policyDB.RetrieveAppPausesCallCount).Should(Equal(2))
+		})
+	})
+})
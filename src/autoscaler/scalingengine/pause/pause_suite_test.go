@@ -0,0 +1,13 @@
+package pause_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPause(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pause Suite")
+}
@@ -7,8 +7,10 @@ import (
 
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
@@ -16,9 +18,17 @@ import (
 
 type ScalingEngine interface {
 	Scale(appId string, trigger *models.Trigger) (*models.AppScalingResult, error)
-	ComputeNewInstances(currentInstances int, adjustment string) (int, error)
+	ComputeNewInstances(currentInstances int, trigger *models.Trigger) (int, error)
 	SetActiveSchedule(appId string, schedule *models.ActiveSchedule) error
 	RemoveActiveSchedule(appId string, scheduleId string) error
+	PauseApp(appId string, pause *models.AppPause) error
+	ResumeApp(appId string, resumedBy string) error
+	ExpireAppPause(appId string) error
+}
+
+// ScalingNotifier returns the webhook deliveries of a scaling history, which are saved together with the history.
+type ScalingNotifier interface {
+	Deliveries(history *models.AppScalingHistory) []*models.WebhookDelivery
 }
 
 type scalingEngine struct {
@@ -26,11 +36,14 @@ type scalingEngine struct {
 	cfClient            cf.CFClient
 	policyDB            db.PolicyDB
 	scalingEngineDB     db.ScalingEngineDB
+	notifier            ScalingNotifier
 	appLock             *StripedLock
 	clock               clock.Clock
 	defaultCoolDownSecs int
 }
 
+const DefaultScaleInDamping = 0.5
+
 type ActiveScheduleNotFoundError struct {
 }
 
@@ -38,12 +51,20 @@ func (ase *ActiveScheduleNotFoundError) Error() string {
 	return fmt.Sprintf("active schedule not found")
 }
 
-func NewScalingEngine(logger lager.Logger, cfClient cf.CFClient, policyDB db.PolicyDB, scalingEngineDB db.ScalingEngineDB, clock clock.Clock, defaultCoolDownSecs int, lockSize int) ScalingEngine {
+type AppNotPausedError struct {
+}
+
+func (anp *AppNotPausedError) Error() string {
+	return "app is not paused"
+}
+
+func NewScalingEngine(logger lager.Logger, cfClient cf.CFClient, policyDB db.PolicyDB, scalingEngineDB db.ScalingEngineDB, notifier ScalingNotifier, clock clock.Clock, defaultCoolDownSecs int, lockSize int) ScalingEngine {
 	return &scalingEngine{
 		logger:              logger.Session("scalingEngine"),
 		cfClient:            cfClient,
 		policyDB:            policyDB,
 		scalingEngineDB:     scalingEngineDB,
+		notifier:            notifier,
 		appLock:             NewStripedLock(lockSize),
 		clock:               clock,
 		defaultCoolDownSecs: defaultCoolDownSecs,
@@ -51,6 +72,25 @@ func NewScalingEngine(logger lager.Logger, cfClient cf.CFClient, policyDB db.Pol
 }
 
 func (s *scalingEngine) Scale(appId string, trigger *models.Trigger) (*models.AppScalingResult, error) {
+	result, group, err := s.scaleApp(appId, trigger, nil)
+	if group != nil {
+		// the other members are scaled after the lock of the app is released, two locks are never held at once
+		s.scaleGroupMembers(group, appId, result.Instances)
+	}
+	return result, err
+}
+
+// groupFollow is the scaling of a member of a scaling group which follows the scaling of another member.
+type groupFollow struct {
+	groupId     string
+	sourceAppId string
+	instances   int
+}
+
+// scaleApp scales the app by the trigger, or to the instances of follow when it follows another member of its scaling
+// group, and returns the scaling group of the app when its instances are changed by the trigger. A member which follows
+// another member goes through the same checks with the mode, cooldown and scale-in limits of its own policy.
+func (s *scalingEngine) scaleApp(appId string, trigger *models.Trigger, follow *groupFollow) (*models.AppScalingResult, *models.ScalingGroup, error) {
 	logger := s.logger.WithData(lager.Data{"appId": appId})
 
 	s.appLock.GetLock(appId).Lock()
@@ -63,10 +103,23 @@ func (s *scalingEngine) Scale(appId string, trigger *models.Trigger) (*models.Ap
 		ScalingType:  models.ScalingTypeDynamic,
 		OldInstances: -1,
 		NewInstances: -1,
-		Reason:       getDynamicScalingReason(trigger),
 	}
+	if follow != nil {
+		// the trigger of a member is filled from its policy once the policy is loaded
+		trigger = &models.Trigger{AppId: appId}
+		history.Reason = fmt.Sprintf("follow app %s in scaling group %s", follow.sourceAppId, follow.groupId)
+		history.GroupId = follow.groupId
+	} else {
+		history.Reason = getDynamicScalingReason(trigger)
+	}
+	explanation := models.NewScalingExplanation(trigger)
 
-	defer s.scalingEngineDB.SaveScalingHistory(history)
+	if follow != nil {
+		// the explanation of a member is the explanation of the member it follows
+		defer s.saveDynamicScalingHistory(history, nil)
+	} else {
+		defer s.saveDynamicScalingHistory(history, explanation)
+	}
 
 	result := &models.AppScalingResult{
 		AppId:             appId,
@@ -79,41 +132,47 @@ func (s *scalingEngine) Scale(appId string, trigger *models.Trigger) (*models.Ap
 		logger.Error("failed-to-get-app-info", err)
 		history.Status = models.ScalingStatusFailed
 		history.Error = "failed to get app info: " + err.Error()
-		return nil, err
+		return nil, nil, err
 	}
 	history.OldInstances = appEntity.Instances
+	explanation.CurrentInstances = appEntity.Instances
 
-	if strings.ToUpper(*appEntity.State) != models.AppStatusStarted {
-		logger.Info("check-app-state", lager.Data{"message": "ignore scaling since app is not started"})
+	message, err := s.pauseIgnoreMessage(appId, now)
+	if err != nil {
+		logger.Error("failed-to-get-app-pause", err)
+		history.Status = models.ScalingStatusFailed
+		history.Error = "failed to get app pause"
+		return nil, nil, err
+	}
+	if message != "" {
+		logger.Info("check-app-pause", lager.Data{"message": message})
 		history.Status = models.ScalingStatusIgnored
 		history.NewInstances = appEntity.Instances
-		history.Message = "app is not started"
+		history.Message = message
 		result.Status = history.Status
-		return result, nil
+		result.Instances = appEntity.Instances
+		return result, nil, nil
 	}
 
-	ok, expiredAt, err := s.scalingEngineDB.CanScaleApp(appId)
-	if err != nil {
-		logger.Error("failed-to-check-cooldown", err)
-		history.Status = models.ScalingStatusFailed
-		history.Error = "failed to check app cooldown setting"
-		return nil, err
-	}
-	result.CooldownExpiredAt = expiredAt
-	if !ok {
+	if strings.ToUpper(*appEntity.State) != models.AppStatusStarted {
+		logger.Info("check-app-state", lager.Data{"message": "ignore scaling since app is not started"})
 		history.Status = models.ScalingStatusIgnored
 		history.NewInstances = appEntity.Instances
-		history.Message = "app in cooldown period"
+		history.Message = "app is not started"
 		result.Status = history.Status
-		return result, nil
+		result.Instances = appEntity.Instances
+		return result, nil, nil
 	}
 
-	newInstances, err := s.ComputeNewInstances(appEntity.Instances, trigger.Adjustment)
+	group, err := s.policyDB.GetScalingGroup(appId)
 	if err != nil {
-		logger.Error("failed-to-compute-new-instance", err, lager.Data{"instances": appEntity.Instances, "adjustment": trigger.Adjustment})
+		logger.Error("failed-to-get-scaling-group", err)
 		history.Status = models.ScalingStatusFailed
-		history.Error = "failed to compute new app instances"
-		return nil, err
+		history.Error = "failed to get scaling group"
+		return nil, nil, err
+	}
+	if group != nil {
+		history.GroupId = group.GroupId
 	}
 
 	schedule, err := s.scalingEngineDB.GetActiveSchedule(appId)
@@ -121,29 +180,96 @@ func (s *scalingEngine) Scale(appId string, trigger *models.Trigger) (*models.Ap
 		logger.Error("failed-to-get-active-schedule", err)
 		history.Status = models.ScalingStatusFailed
 		history.Error = "failed to get active schedule"
-		return nil, err
+		return nil, nil, err
 	}
+	explanation.ActiveSchedule = schedule
 
-	var instanceMin, instanceMax int
+	message = scheduleOverrideIgnoreMessage(schedule, trigger)
+	if follow != nil && schedule != nil && schedule.Override != nil && schedule.Override.Mode == models.ScheduleOverrideRules {
+		// the rules of an override replace the rules of the member, not the scaling of its group
+		message = ""
+	}
+	if message != "" {
+		logger.Info("check-schedule-override", lager.Data{"message": message})
+		history.Status = models.ScalingStatusIgnored
+		history.NewInstances = appEntity.Instances
+		history.Message = message
+		result.Status = history.Status
+		result.Instances = appEntity.Instances
+		return result, nil, nil
+	}
 
-	if schedule != nil {
-		instanceMin = schedule.InstanceMin
-		instanceMax = schedule.InstanceMax
+	var newInstances int
+	if follow != nil {
+		newInstances = follow.instances
 	} else {
-		policy, err := s.policyDB.GetAppPolicy(appId)
+		newInstances, err = s.ComputeNewInstances(appEntity.Instances, trigger)
+		if err != nil {
+			logger.Error("failed-to-compute-new-instance", err, lager.Data{"instances": appEntity.Instances, "trigger": trigger})
+			history.Status = models.ScalingStatusFailed
+			history.Error = "failed to compute new app instances"
+			return nil, nil, err
+		}
+	}
+	explanation.ComputedInstances = newInstances
+
+	// the cooldown is tracked per direction, a target tracking rule on target counts as scale-out
+	direction := models.ScalingDirectionOut
+	if newInstances < appEntity.Instances {
+		direction = models.ScalingDirectionIn
+	}
+
+	ok, expiredAt, err := s.scalingEngineDB.CanScaleApp(appId, direction, trigger.CoolDownKey())
+	if err != nil {
+		logger.Error("failed-to-check-cooldown", err)
+		history.Status = models.ScalingStatusFailed
+		history.Error = "failed to check app cooldown setting"
+		return nil, nil, err
+	}
+	result.Direction = direction
+	result.CooldownExpiredAt = expiredAt
+	explanation.Cooldown = &models.CooldownStatus{Direction: direction, InCooldown: !ok, ExpireAt: expiredAt}
+	if !ok {
+		if trigger.ScaleInStabilizationSeconds > 0 {
+			// a scale-out held back by its cooldown still holds back the scale-in within the stabilization window
+			s.saveScalingRecommendation(logger, appId, now.UnixNano(), newInstances)
+		}
+		history.Status = models.ScalingStatusIgnored
+		history.NewInstances = appEntity.Instances
+		history.Message = fmt.Sprintf("app in scale-%s cooldown period", direction)
+		result.Status = history.Status
+		result.Instances = appEntity.Instances
+		return result, nil, nil
+	}
+
+	// the trigger comes from the policy of the app, so the policy is only needed for the instance limits when there
+	// is no active schedule, but a member which follows another member is only scaled by its own policy
+	var policy *models.ScalingPolicy
+	if schedule == nil || follow != nil {
+		policy, err = s.policyDB.GetAppPolicy(appId)
 		if err != nil {
 			logger.Error("failed-to-get-app-policy", err)
 			history.Status = models.ScalingStatusFailed
 			history.Error = "failed to get scaling policy"
-			return nil, err
+			return nil, nil, err
 		}
 		if policy == nil {
 			history.Status = models.ScalingStatusFailed
 			history.Error = "app does not have policy set"
 			err = errors.New("app does not have policy set")
 			logger.Error("failed-to-get-app-policy", err)
-			return nil, err
+			return nil, nil, err
 		}
+	}
+	if follow != nil {
+		setFollowTrigger(trigger, policy)
+	}
+
+	var instanceMin, instanceMax int
+	if schedule != nil {
+		instanceMin = schedule.InstanceMin
+		instanceMax = schedule.InstanceMax
+	} else {
 		instanceMin = policy.InstanceMin
 		instanceMax = policy.InstanceMax
 	}
@@ -155,37 +281,119 @@ func (s *scalingEngine) Scale(appId string, trigger *models.Trigger) (*models.Ap
 		newInstances = instanceMax
 		history.Message = fmt.Sprintf("limited by max instances %d", instanceMax)
 	}
+
+	if trigger.ScaleInStabilizationSeconds > 0 {
+		err = s.scalingEngineDB.SaveScalingRecommendation(appId, now.UnixNano(), newInstances)
+		if err != nil {
+			logger.Error("failed-to-save-scaling-recommendation", err, lager.Data{"newInstances": newInstances})
+			history.Status = models.ScalingStatusFailed
+			history.Error = "failed to save scaling recommendation"
+			return nil, nil, err
+		}
+		if newInstances < appEntity.Instances {
+			// scale in no further than the highest recommendation within the window, which includes this one
+			highest, err := s.scalingEngineDB.GetMaxScalingRecommendation(appId, now.Add(0-trigger.ScaleInStabilizationWindow()).UnixNano())
+			if err != nil {
+				logger.Error("failed-to-get-max-scaling-recommendation", err)
+				history.Status = models.ScalingStatusFailed
+				history.Error = "failed to get scaling recommendations"
+				return nil, nil, err
+			}
+			if highest > appEntity.Instances {
+				highest = appEntity.Instances
+			}
+			if highest > newInstances {
+				newInstances = highest
+				history.Message = fmt.Sprintf("limited by highest recommendation %d in scale-in stabilization window", highest)
+			}
+		}
+	}
+
+	if trigger.ScaleInMaxStep > 0 && appEntity.Instances-newInstances > trigger.ScaleInMaxStep {
+		newInstances = appEntity.Instances - trigger.ScaleInMaxStep
+		history.Message = fmt.Sprintf("limited by max scale-in step %d", trigger.ScaleInMaxStep)
+	}
 	history.NewInstances = newInstances
+	explanation.InstanceMin = instanceMin
+	explanation.InstanceMax = instanceMax
+	explanation.NewInstances = newInstances
 
 	if newInstances == appEntity.Instances {
 		history.Status = models.ScalingStatusIgnored
 		result.Status = history.Status
 		result.Adjustment = 0
+		result.Instances = appEntity.Instances
 		result.CooldownExpiredAt = now.Add(trigger.CoolDown(s.defaultCoolDownSecs)).UnixNano()
-		return result, nil
+		return result, nil, nil
 	}
 
-	err = s.cfClient.SetAppInstances(appId, newInstances)
-	if err != nil {
-		logger.Error("failed-to-set-app-instances", err, lager.Data{"newInstances": newInstances})
-		history.Status = models.ScalingStatusFailed
-		history.Error = "failed to set app instances: " + err.Error()
-		return nil, err
+	if trigger.IsShadow() {
+		// the decision is recorded and starts the cooldown as if it was applied, so the history shows what would happen
+		logger.Info("simulate-scaling", lager.Data{"newInstances": newInstances})
+		history.Status = models.ScalingStatusSimulated
+	} else {
+		err = s.cfClient.SetAppInstances(appId, newInstances)
+		if err != nil {
+			logger.Error("failed-to-set-app-instances", err, lager.Data{"newInstances": newInstances})
+			history.Status = models.ScalingStatusFailed
+			history.Error = "failed to set app instances: " + err.Error()
+			return nil, nil, err
+		}
+		history.Status = models.ScalingStatusSucceeded
 	}
 
-	history.Status = models.ScalingStatusSucceeded
 	result.Status = history.Status
 	result.Adjustment = newInstances - appEntity.Instances
+	result.Instances = newInstances
 	result.CooldownExpiredAt = now.Add(trigger.CoolDown(s.defaultCoolDownSecs)).UnixNano()
-	err = s.scalingEngineDB.UpdateScalingCooldownExpireTime(appId, result.CooldownExpiredAt)
+	err = s.scalingEngineDB.UpdateScalingCooldownExpireTime(appId, direction, trigger.CoolDownKey(), result.CooldownExpiredAt)
 	if err != nil {
 		logger.Error("failed-to-update-scaling-cool-down-expire-time", err, lager.Data{"newInstances": newInstances})
 	}
 
-	return result, nil
+	if follow != nil {
+		// the scaling of a member does not cascade to the other members
+		return result, nil, nil
+	}
+	return result, group, nil
+}
+
+func (s *scalingEngine) scaleGroupMembers(group *models.ScalingGroup, appId string, instances int) {
+	source := group.Member(appId)
+	if source == nil {
+		return
+	}
+	for _, member := range group.Members {
+		if member.AppId != appId {
+			s.scaleApp(member.AppId, nil, &groupFollow{
+				groupId:     group.GroupId,
+				sourceAppId: appId,
+				instances:   member.InstancesFor(source, instances),
+			})
+		}
+	}
 }
 
-func (s *scalingEngine) ComputeNewInstances(currentInstances int, adjustment string) (int, error) {
+// setFollowTrigger fills the trigger of a member which follows another member of its scaling group from the policy of
+// the member. The cooldown it restarts is the longest cooldown of its rules, so that they do not scale it back right
+// away.
+func setFollowTrigger(trigger *models.Trigger, policy *models.ScalingPolicy) {
+	trigger.Mode = policy.Mode
+	trigger.ScaleInStabilizationSeconds = policy.ScaleInStabilizationSeconds
+	trigger.ScaleInMaxStep = policy.ScaleInMaxStep
+	for _, rule := range append(policy.ScheduleOnlyRules(), policy.ScalingRules...) {
+		if rule.CoolDownSeconds > trigger.CoolDownSeconds {
+			trigger.CoolDownSeconds = rule.CoolDownSeconds
+		}
+	}
+}
+
+func (s *scalingEngine) ComputeNewInstances(currentInstances int, trigger *models.Trigger) (int, error) {
+	if trigger.IsTargetTracking() {
+		return s.computeTargetTrackingInstances(currentInstances, trigger)
+	}
+
+	adjustment := trigger.Adjustment
 	var newInstances int
 	if strings.HasSuffix(adjustment, "%") {
 		percentage, err := strconv.ParseFloat(strings.TrimSuffix(adjustment, "%"), 32)
@@ -215,6 +423,27 @@ func (s *scalingEngine) ComputeNewInstances(currentInstances int, adjustment str
 	return newInstances, nil
 }
 
+func (s *scalingEngine) computeTargetTrackingInstances(currentInstances int, trigger *models.Trigger) (int, error) {
+	if trigger.TargetValue <= 0 {
+		err := fmt.Errorf("invalid target value %v", trigger.TargetValue)
+		s.logger.Error("failed-to-compute-target-tracking-instances", err, lager.Data{"trigger": trigger})
+		return -1, err
+	}
+
+	desiredInstances := int(math.Ceil(float64(currentInstances) * trigger.MetricValue / trigger.TargetValue))
+	if desiredInstances >= currentInstances {
+		return desiredInstances, nil
+	}
+
+	// only part of the scale-in is applied at once to avoid removing too much capacity on a short dip
+	damping := trigger.ScaleInDamping
+	if damping <= 0 || damping > 1 {
+		damping = DefaultScaleInDamping
+	}
+	removal := int(math.Ceil(float64(currentInstances-desiredInstances) * damping))
+	return currentInstances - removal, nil
+}
+
 func (s *scalingEngine) SetActiveSchedule(appId string, schedule *models.ActiveSchedule) error {
 	logger := s.logger.WithData(lager.Data{"appId": appId, "schedule": schedule})
 
@@ -250,7 +479,7 @@ func (s *scalingEngine) SetActiveSchedule(appId string, schedule *models.ActiveS
 		NewInstances: -1,
 		Reason:       getScheduledScalingReason(schedule),
 	}
-	defer s.scalingEngineDB.SaveScalingHistory(history)
+	defer s.saveScalingHistory(history)
 
 	appEntity, err := s.cfClient.GetApp(appId)
 	if err != nil {
@@ -261,13 +490,36 @@ func (s *scalingEngine) SetActiveSchedule(appId string, schedule *models.ActiveS
 	}
 	history.OldInstances = appEntity.Instances
 
+	message, err := s.pauseIgnoreMessage(appId, now)
+	if err != nil {
+		logger.Error("failed-to-get-app-pause", err)
+		history.Status = models.ScalingStatusFailed
+		history.Error = "failed to get app pause"
+		return err
+	}
+	if message != "" {
+		history.Status = models.ScalingStatusIgnored
+		history.NewInstances = appEntity.Instances
+		history.Message = message
+		return nil
+	}
+
+	return s.scaleToSchedule(logger, appId, schedule, appEntity.Instances, history)
+}
+
+// scaleToSchedule scales the app into the instance limits of the schedule, or to its pin, and records the result in
+// the history.
+func (s *scalingEngine) scaleToSchedule(logger lager.Logger, appId string, schedule *models.ActiveSchedule, currentInstances int, history *models.AppScalingHistory) error {
 	instanceMin := schedule.InstanceMinInitial
 	if schedule.InstanceMin > instanceMin {
 		instanceMin = schedule.InstanceMin
 	}
 
-	newInstances := appEntity.Instances
-	if newInstances < instanceMin {
+	newInstances := currentInstances
+	if schedule.Override != nil && schedule.Override.Mode == models.ScheduleOverridePin {
+		newInstances = schedule.Override.InstanceCount
+		history.Message = fmt.Sprintf("pinned at %d instances by schedule override", newInstances)
+	} else if newInstances < instanceMin {
 		newInstances = instanceMin
 		history.Message = fmt.Sprintf("limited by min instances %d", instanceMin)
 	} else if newInstances > schedule.InstanceMax {
@@ -277,12 +529,12 @@ func (s *scalingEngine) SetActiveSchedule(appId string, schedule *models.ActiveS
 
 	history.NewInstances = newInstances
 
-	if newInstances == appEntity.Instances {
+	if newInstances == currentInstances {
 		history.Status = models.ScalingStatusIgnored
 		return nil
 	}
 
-	err = s.cfClient.SetAppInstances(appId, newInstances)
+	err := s.cfClient.SetAppInstances(appId, newInstances)
 	if err != nil {
 		logger.Error("failed-to-set-app-instances", err)
 		history.Status = models.ScalingStatusFailed
@@ -290,6 +542,9 @@ func (s *scalingEngine) SetActiveSchedule(appId string, schedule *models.ActiveS
 		return err
 	}
 	history.Status = models.ScalingStatusSucceeded
+	if newInstances > currentInstances {
+		s.saveScalingRecommendation(logger, appId, history.Timestamp, newInstances)
+	}
 	return nil
 }
 
@@ -326,7 +581,7 @@ func (s *scalingEngine) RemoveActiveSchedule(appId string, scheduleId string) er
 		NewInstances: -1,
 		Reason:       "schedule ends",
 	}
-	defer s.scalingEngineDB.SaveScalingHistory(history)
+	defer s.saveScalingHistory(history)
 
 	appEntity, err := s.cfClient.GetApp(appId)
 	if err != nil {
@@ -337,6 +592,20 @@ func (s *scalingEngine) RemoveActiveSchedule(appId string, scheduleId string) er
 	}
 	history.OldInstances = appEntity.Instances
 
+	message, err := s.pauseIgnoreMessage(appId, now)
+	if err != nil {
+		logger.Error("failed-to-get-app-pause", err)
+		history.Status = models.ScalingStatusFailed
+		history.Error = "failed to get app pause"
+		return err
+	}
+	if message != "" {
+		history.Status = models.ScalingStatusIgnored
+		history.NewInstances = appEntity.Instances
+		history.Message = message
+		return nil
+	}
+
 	policy, err := s.policyDB.GetAppPolicy(appId)
 	if err != nil {
 		logger.Error("failed-to-get-app-policy", err)
@@ -374,20 +643,271 @@ func (s *scalingEngine) RemoveActiveSchedule(appId string, scheduleId string) er
 		return err
 	}
 	history.Status = models.ScalingStatusSucceeded
+	if newInstances > appEntity.Instances {
+		s.saveScalingRecommendation(logger, appId, history.Timestamp, newInstances)
+	}
 	return nil
 }
 
+// PauseApp stops the scaling of the app until it is resumed or the pause expires. The schedules of the app are still
+// saved while it is paused, but they do not change its instances.
+func (s *scalingEngine) PauseApp(appId string, pause *models.AppPause) error {
+	logger := s.logger.WithData(lager.Data{"appId": appId, "pause": pause})
+
+	s.appLock.GetLock(appId).Lock()
+	defer s.appLock.GetLock(appId).Unlock()
+
+	now := s.clock.Now()
+	pause.AppId = appId
+	pause.PausedAt = now.UnixNano()
+	err := s.policyDB.SaveAppPause(pause)
+	if err != nil {
+		logger.Error("failed-to-save-app-pause", err)
+		return err
+	}
+
+	reason := fmt.Sprintf("autoscaling paused by %s", pause.PausedBy)
+	if pause.ExpireAt != 0 {
+		reason += " until " + time.Unix(0, pause.ExpireAt).UTC().Format(time.RFC3339)
+	}
+	s.savePauseHistory(appId, now, reason, pause.Reason)
+	return nil
+}
+
+func (s *scalingEngine) ResumeApp(appId string, resumedBy string) error {
+	logger := s.logger.WithData(lager.Data{"appId": appId, "resumedBy": resumedBy})
+
+	s.appLock.GetLock(appId).Lock()
+	defer s.appLock.GetLock(appId).Unlock()
+
+	pause, err := s.policyDB.GetAppPause(appId)
+	if err != nil {
+		logger.Error("failed-to-get-app-pause", err)
+		return err
+	}
+	if pause == nil {
+		err = &AppNotPausedError{}
+		logger.Error("failed-to-resume-app", err)
+		return err
+	}
+
+	err = s.policyDB.DeleteAppPause(appId)
+	if err != nil {
+		logger.Error("failed-to-delete-app-pause", err)
+		return err
+	}
+
+	now := s.clock.Now()
+	reason := fmt.Sprintf("autoscaling resumed by %s", resumedBy)
+	s.savePauseHistory(appId, now, reason, "")
+	s.applyActiveSchedule(logger, appId, now, reason)
+	return nil
+}
+
+// ExpireAppPause deletes the pause of the app once it has expired, and applies the active schedule like ResumeApp.
+// Nothing is done when the pause has not expired, or when another scaling engine has expired it already.
+func (s *scalingEngine) ExpireAppPause(appId string) error {
+	logger := s.logger.WithData(lager.Data{"appId": appId})
+
+	s.appLock.GetLock(appId).Lock()
+	defer s.appLock.GetLock(appId).Unlock()
+
+	now := s.clock.Now()
+	err := s.policyDB.DeleteExpiredAppPause(appId, now.UnixNano())
+	if err == db.ErrDoesNotExist {
+		return nil
+	}
+	if err != nil {
+		logger.Error("failed-to-delete-expired-app-pause", err)
+		return err
+	}
+
+	reason := "autoscaling pause expired"
+	s.savePauseHistory(appId, now, reason, "")
+	s.applyActiveSchedule(logger, appId, now, reason)
+	return nil
+}
+
+// applyActiveSchedule scales the app into the limits of its active schedule, or to its pin, when the app is no longer
+// paused. A schedule which starts while the app is paused does not change its instances until then. A failure is
+// recorded in the scaling history and does not undo the resume.
+func (s *scalingEngine) applyActiveSchedule(logger lager.Logger, appId string, now time.Time, reason string) {
+	schedule, err := s.scalingEngineDB.GetActiveSchedule(appId)
+	if err != nil {
+		logger.Error("failed-to-get-active-schedule", err)
+		return
+	}
+	if schedule == nil {
+		return
+	}
+
+	history := &models.AppScalingHistory{
+		AppId:        appId,
+		Timestamp:    now.UnixNano(),
+		ScalingType:  models.ScalingTypeSchedule,
+		OldInstances: -1,
+		NewInstances: -1,
+		Reason:       fmt.Sprintf("%s, applied as %s", getScheduledScalingReason(schedule), reason),
+	}
+	defer s.saveScalingHistory(history)
+
+	appEntity, err := s.cfClient.GetApp(appId)
+	if err != nil {
+		logger.Error("failed-to-get-app-info", err)
+		history.Status = models.ScalingStatusFailed
+		history.Error = "failed to get app info: " + err.Error()
+		return
+	}
+	history.OldInstances = appEntity.Instances
+
+	s.scaleToSchedule(logger, appId, schedule, appEntity.Instances, history)
+}
+
+// savePauseHistory records a pause or a resume of the app, which does not change its instances.
+func (s *scalingEngine) savePauseHistory(appId string, now time.Time, reason string, message string) {
+	history := &models.AppScalingHistory{
+		AppId:        appId,
+		Timestamp:    now.UnixNano(),
+		ScalingType:  models.ScalingTypePause,
+		Status:       models.ScalingStatusSucceeded,
+		OldInstances: -1,
+		NewInstances: -1,
+		Reason:       reason,
+		Message:      message,
+	}
+	appEntity, err := s.cfClient.GetApp(appId)
+	if err != nil {
+		s.logger.Error("failed-to-get-app-info", err, lager.Data{"appId": appId})
+	} else {
+		history.OldInstances = appEntity.Instances
+		history.NewInstances = appEntity.Instances
+	}
+	s.saveScalingHistory(history)
+}
+
+// pauseIgnoreMessage returns why the app is not scaled while it is paused, and empty when it is not paused.
+func (s *scalingEngine) pauseIgnoreMessage(appId string, now time.Time) (string, error) {
+	pause, err := s.policyDB.GetAppPause(appId)
+	if err != nil {
+		return "", err
+	}
+	if !pause.IsActive(now.UnixNano()) {
+		return "", nil
+	}
+	message := fmt.Sprintf("autoscaling paused by %s", pause.PausedBy)
+	if pause.Reason != "" {
+		message += ": " + pause.Reason
+	}
+	return message, nil
+}
+
+// saveScalingRecommendation records instances which are not applied by a dynamic scaling decision, like a scale-out
+// by a schedule, so that the scale-in stabilization window of the app does not scale it back in right away. The
+// scaling goes on when it fails.
+func (s *scalingEngine) saveScalingRecommendation(logger lager.Logger, appId string, timestamp int64, instances int) {
+	err := s.scalingEngineDB.SaveScalingRecommendation(appId, timestamp, instances)
+	if err != nil {
+		logger.Error("failed-to-save-scaling-recommendation", err, lager.Data{"instances": instances})
+	}
+}
+
+func (s *scalingEngine) saveScalingHistory(history *models.AppScalingHistory) error {
+	deliveries := s.notifier.Deliveries(history)
+	if len(deliveries) == 0 {
+		return s.scalingEngineDB.SaveScalingHistory(history)
+	}
+	// the notification is stored in the outbox in the same transaction, so it is sent whenever the history is saved
+	return s.scalingEngineDB.SaveScalingHistoryWithWebhookDeliveries(history, deliveries)
+}
+
+func (s *scalingEngine) saveDynamicScalingHistory(history *models.AppScalingHistory, explanation *models.ScalingExplanation) {
+	err := s.saveScalingHistory(history)
+	if err == nil && explanation != nil {
+		err = s.scalingEngineDB.SaveScalingExplanation(history, explanation)
+		if err != nil {
+			s.logger.Error("failed-to-save-scaling-explanation", err, lager.Data{"appId": history.AppId})
+		}
+	}
+}
+
 func getDynamicScalingReason(trigger *models.Trigger) string {
-	return fmt.Sprintf("%s instance(s) because %s %s %d%s for %d seconds",
+	if trigger.IsTargetTracking() {
+		return fmt.Sprintf("track %s at target value %s%s with current value %s%s",
+			trigger.MetricType,
+			strconv.FormatFloat(trigger.TargetValue, 'f', -1, 64),
+			trigger.MetricUnit,
+			strconv.FormatFloat(trigger.MetricValue, 'f', -1, 64),
+			trigger.MetricUnit)
+	}
+	if trigger.IsCompound() {
+		return fmt.Sprintf("%s instance(s) because %s for %d seconds",
+			trigger.Adjustment,
+			trigger.Condition.String(),
+			trigger.BreachDurationSeconds)
+	}
+	if trigger.RateOfChange != nil {
+		return fmt.Sprintf("%s instance(s) because the change of %s over %d seconds %s %s%s for %d seconds",
+			trigger.Adjustment,
+			trigger.MetricType,
+			trigger.RateOfChange.WindowSeconds,
+			trigger.Operator,
+			strconv.FormatFloat(trigger.Threshold, 'f', -1, 64),
+			trigger.MetricUnit,
+			trigger.BreachDurationSeconds)
+	}
+	if trigger.Anomaly != nil {
+		return fmt.Sprintf("%s instance(s) because %s deviates from its baseline by %s %s standard deviations for %d seconds",
+			trigger.Adjustment,
+			trigger.MetricType,
+			trigger.Operator,
+			strconv.FormatFloat(trigger.Threshold, 'f', -1, 64),
+			trigger.BreachDurationSeconds)
+	}
+	if trigger.Predictive != nil {
+		return fmt.Sprintf("%s instance(s) because %s is forecast to be %s %s%s within %d seconds",
+			trigger.Adjustment,
+			trigger.MetricType,
+			trigger.Operator,
+			strconv.FormatFloat(trigger.Threshold, 'f', -1, 64),
+			trigger.MetricUnit,
+			trigger.Predictive.ForecastHorizonSeconds)
+	}
+	return fmt.Sprintf("%s instance(s) because %s %s %s%s for %d seconds",
 		trigger.Adjustment,
 		trigger.MetricType,
 		trigger.Operator,
-		trigger.Threshold,
+		strconv.FormatFloat(trigger.Threshold, 'f', -1, 64),
 		trigger.MetricUnit,
 		trigger.BreachDurationSeconds)
 }
 
 func getScheduledScalingReason(schedule *models.ActiveSchedule) string {
-	return fmt.Sprintf("schedule starts with instance min %d, instance max %d and instance min initial %d",
+	reason := fmt.Sprintf("schedule starts with instance min %d, instance max %d and instance min initial %d",
 		schedule.InstanceMin, schedule.InstanceMax, schedule.InstanceMinInitial)
+	if schedule.Override != nil {
+		reason += fmt.Sprintf(" and %s override", schedule.Override.Mode)
+	}
+	return reason
+}
+
+// scheduleOverrideIgnoreMessage returns why the trigger is ignored under the override of the active schedule, and
+// empty when the trigger can scale the app. The rules of a "rules" override only scale the app while it is active.
+func scheduleOverrideIgnoreMessage(schedule *models.ActiveSchedule, trigger *models.Trigger) string {
+	if schedule == nil || schedule.Override == nil {
+		if trigger.ScheduleOnly {
+			return "rule only applies during a schedule override"
+		}
+		return ""
+	}
+	switch schedule.Override.Mode {
+	case models.ScheduleOverrideSuspend:
+		return "dynamic scaling suspended by schedule override"
+	case models.ScheduleOverridePin:
+		return fmt.Sprintf("app pinned at %d instances by schedule override", schedule.Override.InstanceCount)
+	case models.ScheduleOverrideRules:
+		if !trigger.ScheduleOnly || !schedule.Override.HasRule(trigger.RuleKey()) {
+			return "rule replaced by schedule override"
+		}
+	}
+	return ""
 }
@@ -1,6 +1,7 @@
 package scalingengine_test
 
 import (
+	"autoscaler/db"
 	"autoscaler/fakes"
 	"autoscaler/models"
 	. "autoscaler/scalingengine"
@@ -23,6 +24,7 @@ var _ = Describe("ScalingEngine", func() {
 		cfc             *fakes.FakeCFClient
 		policyDB        *fakes.FakePolicyDB
 		scalingEngineDB *fakes.FakeScalingEngineDB
+		notifier        *fakes.FakeScalingNotifier
 		clock           *fakeclock.FakeClock
 
 		scalingResult *models.AppScalingResult
@@ -36,11 +38,12 @@ var _ = Describe("ScalingEngine", func() {
 		cfc = &fakes.FakeCFClient{}
 		policyDB = &fakes.FakePolicyDB{}
 		scalingEngineDB = &fakes.FakeScalingEngineDB{}
+		notifier = &fakes.FakeScalingNotifier{}
 
 		logger := lagertest.NewTestLogger("schedule-test")
 		buffer = logger.Buffer()
 		clock = fakeclock.NewFakeClock(time.Now())
-		scalingEngine = NewScalingEngine(logger, cfc, policyDB, scalingEngineDB, clock, 300, 32)
+		scalingEngine = NewScalingEngine(logger, cfc, policyDB, scalingEngineDB, notifier, clock, 300, 32)
 		appState = models.AppStatusStarted
 		activeSchedule = &models.ActiveSchedule{
 			ScheduleId:         "a-schedule-id",
@@ -81,8 +84,10 @@ var _ = Describe("ScalingEngine", func() {
 				Expect(id).To(Equal("an-app-id"))
 				Expect(num).To(Equal(3))
 
-				id, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
+				id, direction, ruleKey, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
 				Expect(id).To(Equal("an-app-id"))
+				Expect(direction).To(Equal(models.ScalingDirectionOut))
+				Expect(ruleKey).To(BeEmpty())
 				Expect(expiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
 
 				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
@@ -98,9 +103,204 @@ var _ = Describe("ScalingEngine", func() {
 				Expect(scalingResult.AppId).To(Equal("an-app-id"))
 				Expect(scalingResult.Status).To(Equal(models.ScalingStatusSucceeded))
 				Expect(scalingResult.Adjustment).To(Equal(1))
+				Expect(scalingResult.Instances).To(Equal(3))
 				Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
 
 			})
+
+			It("notifies the scaling history", func() {
+				Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+				Expect(notifier.DeliveriesArgsForCall(0)).To(Equal(scalingEngineDB.SaveScalingHistoryArgsForCall(0)))
+			})
+
+			Context("when the scaling history has webhook deliveries", func() {
+				var deliveries []*models.WebhookDelivery
+
+				BeforeEach(func() {
+					deliveries = []*models.WebhookDelivery{{AppId: "an-app-id", URL: "https://hooks.example.com/hook", Payload: "a-payload"}}
+					notifier.DeliveriesReturns(deliveries)
+				})
+
+				It("saves the history together with the deliveries", func() {
+					Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryWithWebhookDeliveriesCallCount()).To(Equal(1))
+					history, saved := scalingEngineDB.SaveScalingHistoryWithWebhookDeliveriesArgsForCall(0)
+					Expect(history).To(Equal(notifier.DeliveriesArgsForCall(0)))
+					Expect(saved).To(Equal(deliveries))
+					Expect(scalingEngineDB.SaveScalingExplanationCallCount()).To(Equal(1))
+				})
+
+				Context("when saving the history fails", func() {
+					BeforeEach(func() {
+						scalingEngineDB.SaveScalingHistoryWithWebhookDeliveriesReturns(errors.New("test error"))
+					})
+
+					It("does not save the explanation", func() {
+						Expect(scalingEngineDB.SaveScalingExplanationCallCount()).To(BeZero())
+					})
+				})
+			})
+
+			It("stores the explanation of the scaling decision", func() {
+				Expect(scalingEngineDB.SaveScalingExplanationCallCount()).To(Equal(1))
+				history, explanation := scalingEngineDB.SaveScalingExplanationArgsForCall(0)
+				Expect(history).To(Equal(scalingEngineDB.SaveScalingHistoryArgsForCall(0)))
+				Expect(explanation).To(Equal(&models.ScalingExplanation{
+					Trigger:           trigger,
+					Comparisons:       []*models.MetricComparison{},
+					Cooldown:          &models.CooldownStatus{Direction: models.ScalingDirectionOut, InCooldown: false, ExpireAt: clock.Now().Add(0 - 30*time.Second).UnixNano()},
+					CurrentInstances:  2,
+					ComputedInstances: 3,
+					InstanceMin:       1,
+					InstanceMax:       6,
+					NewInstances:      3,
+				}))
+			})
+
+			Context("when the trigger has evidence", func() {
+				var comparisons []*models.MetricComparison
+
+				BeforeEach(func() {
+					comparisons = []*models.MetricComparison{{
+						MetricType:   "test-metric-type",
+						Aggregation:  models.AggregationAvg,
+						Operator:     ">",
+						Threshold:    80,
+						MetricPoints: []*models.AppMetric{{AppId: "an-app-id", MetricType: "test-metric-type", Value: 90, Unit: "test-unit"}},
+						Breached:     true,
+					}}
+					trigger.Evidence = &models.TriggerEvidence{
+						Comparisons:    comparisons,
+						CircuitBreaker: &models.CircuitBreakerStatus{Tripped: false, ConsecutiveFailures: 1},
+					}
+				})
+
+				It("stores the evidence in the explanation", func() {
+					_, explanation := scalingEngineDB.SaveScalingExplanationArgsForCall(0)
+					Expect(explanation.Comparisons).To(Equal(comparisons))
+					Expect(explanation.CircuitBreaker).To(Equal(&models.CircuitBreakerStatus{Tripped: false, ConsecutiveFailures: 1}))
+					Expect(explanation.Trigger.Evidence).To(BeNil())
+				})
+			})
+
+			Context("when saving the explanation fails", func() {
+				BeforeEach(func() {
+					scalingEngineDB.SaveScalingExplanationReturns(errors.New("test error"))
+				})
+
+				It("logs the error and notifies the scaling history", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(buffer).Should(gbytes.Say("failed-to-save-scaling-explanation"))
+					Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when the trigger has a fractional threshold", func() {
+			BeforeEach(func() {
+				trigger.Threshold = 0.75
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("stores the scaling history with the exact threshold", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("+1 instance(s) because test-metric-type > 0.75test-unit for 100 seconds"))
+			})
+		})
+
+		Context("when scaling is triggered by a predictive rule", func() {
+			BeforeEach(func() {
+				trigger.Predictive = &models.PredictiveScaling{
+					Method:                 models.ForecastMethodLinearRegression,
+					ForecastHorizonSeconds: 300,
+				}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("stores the scaling history with the forecast reason", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("+1 instance(s) because test-metric-type is forecast to be > 80test-unit within 300 seconds"))
+			})
+		})
+
+		Context("when scaling is triggered by a rate of change rule", func() {
+			BeforeEach(func() {
+				trigger.MetricUnit = "%"
+				trigger.Threshold = 5
+				trigger.RateOfChange = &models.RateOfChange{WindowSeconds: 60}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("stores the scaling history with the change of the metric", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("+1 instance(s) because the change of test-metric-type over 60 seconds > 5% for 100 seconds"))
+			})
+		})
+
+		Context("when scaling is triggered by an anomaly rule", func() {
+			BeforeEach(func() {
+				trigger.MetricUnit = "stddev"
+				trigger.Threshold = 3
+				trigger.Anomaly = &models.AnomalyDetection{Action: models.AnomalyActionScale}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("stores the scaling history with the deviation of the metric", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("+1 instance(s) because test-metric-type deviates from its baseline by > 3 standard deviations for 100 seconds"))
+			})
+		})
+
+		Context("when scaling is triggered by a rule with a condition", func() {
+			BeforeEach(func() {
+				trigger.MetricType = ""
+				trigger.MetricUnit = ""
+				trigger.Condition = &models.ScalingCondition{
+					And: []*models.ScalingCondition{
+						{MetricType: "cpu", Operator: ">", Threshold: 70},
+						{MetricType: "throughput", Operator: ">", Threshold: 500},
+					},
+				}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("stores the scaling history with the condition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("+1 instance(s) because (cpu > 70 and throughput > 500) for 100 seconds"))
+			})
+		})
+
+		Context("when scaling is triggered by a target tracking rule", func() {
+			BeforeEach(func() {
+				trigger = &models.Trigger{
+					MetricType:      "test-metric-type",
+					MetricUnit:      "test-unit",
+					CoolDownSeconds: 30,
+					TargetValue:     60,
+					MetricValue:     90,
+				}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("sets the proportional instance number and stores the scaling history", func() {
+				Expect(err).NotTo(HaveOccurred())
+				_, num := cfc.SetAppInstancesArgsForCall(0)
+				Expect(num).To(Equal(3))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("track test-metric-type at target value 60test-unit with current value 90test-unit"))
+				Expect(scalingResult.Adjustment).To(Equal(1))
+			})
 		})
 
 		Context("When app is not started", func() {
@@ -126,11 +326,62 @@ var _ = Describe("ScalingEngine", func() {
 				Expect(scalingResult.AppId).To(Equal("an-app-id"))
 				Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
 				Expect(scalingResult.Adjustment).To(Equal(0))
+				Expect(scalingResult.Instances).To(Equal(2))
 				Expect(scalingResult.CooldownExpiredAt).To(Equal(int64(0)))
 
 			})
 		})
 
+		Context("when the app is paused", func() {
+			BeforeEach(func() {
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				policyDB.GetAppPauseReturns(&models.AppPause{AppId: "an-app-id", PausedBy: "a-user-id", Reason: "database migration"}, nil)
+			})
+
+			It("ignores the scaling and stores the ignored scaling history", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeDynamic,
+					Status:       models.ScalingStatusIgnored,
+					OldInstances: 2,
+					NewInstances: 2,
+					Reason:       "+1 instance(s) because test-metric-type > 80test-unit for 100 seconds",
+					Message:      "autoscaling paused by a-user-id: database migration",
+				}))
+				Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
+				Expect(scalingResult.Instances).To(Equal(2))
+			})
+
+			Context("when the pause has expired", func() {
+				BeforeEach(func() {
+					policyDB.GetAppPauseReturns(&models.AppPause{AppId: "an-app-id", PausedBy: "a-user-id", ExpireAt: clock.Now().UnixNano()}, nil)
+					scalingEngineDB.CanScaleAppReturns(true, 0, nil)
+					policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+				})
+
+				It("scales the app", func() {
+					Expect(err).NotTo(HaveOccurred())
+					_, instances := cfc.SetAppInstancesArgsForCall(0)
+					Expect(instances).To(Equal(3))
+				})
+			})
+
+			Context("when getting the pause fails", func() {
+				BeforeEach(func() {
+					policyDB.GetAppPauseReturns(nil, errors.New("get-pause-error"))
+				})
+
+				It("stores the failed scaling history and returns the error", func() {
+					Expect(err).To(MatchError("get-pause-error"))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusFailed))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Error).To(Equal("failed to get app pause"))
+				})
+			})
+		})
+
 		Context("when app is in cooldown period", func() {
 			BeforeEach(func() {
 				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
@@ -149,15 +400,309 @@ var _ = Describe("ScalingEngine", func() {
 					OldInstances: 2,
 					NewInstances: 2,
 					Reason:       "+1 instance(s) because test-metric-type > 80test-unit for 100 seconds",
-					Message:      "app in cooldown period",
+					Message:      "app in scale-out cooldown period",
 				}))
 
 				Expect(scalingResult.AppId).To(Equal("an-app-id"))
 				Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
 				Expect(scalingResult.Adjustment).To(Equal(0))
+				Expect(scalingResult.Direction).To(Equal(models.ScalingDirectionOut))
 				Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
 
 			})
+
+			It("checks the cooldown of the scaling direction", func() {
+				id, direction, ruleKey := scalingEngineDB.CanScaleAppArgsForCall(0)
+				Expect(id).To(Equal("an-app-id"))
+				Expect(direction).To(Equal(models.ScalingDirectionOut))
+				Expect(ruleKey).To(BeEmpty())
+			})
+
+			It("stores the cooldown state in the explanation", func() {
+				_, explanation := scalingEngineDB.SaveScalingExplanationArgsForCall(0)
+				Expect(explanation.Cooldown).To(Equal(&models.CooldownStatus{Direction: models.ScalingDirectionOut, InCooldown: true, ExpireAt: clock.Now().Add(30 * time.Second).UnixNano()}))
+				Expect(explanation.CurrentInstances).To(Equal(2))
+				Expect(explanation.ComputedInstances).To(Equal(3))
+				Expect(explanation.NewInstances).To(Equal(-1))
+			})
+		})
+
+		Context("when scaling in", func() {
+			BeforeEach(func() {
+				trigger.Adjustment = "-1"
+				cfc.GetAppReturns(&models.AppEntity{Instances: 3, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, 0, nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("checks and updates the scale-in cooldown", func() {
+				Expect(err).NotTo(HaveOccurred())
+				_, direction, _ := scalingEngineDB.CanScaleAppArgsForCall(0)
+				Expect(direction).To(Equal(models.ScalingDirectionIn))
+
+				_, direction, _, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
+				Expect(direction).To(Equal(models.ScalingDirectionIn))
+				Expect(expiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
+				Expect(scalingResult.Direction).To(Equal(models.ScalingDirectionIn))
+			})
+		})
+
+		Context("when the cooldown is tracked per rule", func() {
+			BeforeEach(func() {
+				trigger.CoolDownPerRule = true
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, 0, nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("checks and updates the cooldown of the rule", func() {
+				Expect(err).NotTo(HaveOccurred())
+				_, _, ruleKey := scalingEngineDB.CanScaleAppArgsForCall(0)
+				Expect(ruleKey).To(Equal("test-metric-type:avg > 80 +1"))
+
+				_, _, ruleKey, _ = scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
+				Expect(ruleKey).To(Equal("test-metric-type:avg > 80 +1"))
+			})
+		})
+
+		Context("when the trigger is from a rule of a schedule override which is not active", func() {
+			BeforeEach(func() {
+				trigger.ScheduleOnly = true
+				cfc.GetAppReturns(&models.AppEntity{Instances: 2, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, 0, nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil)
+			})
+
+			It("ignores the trigger", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusIgnored))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Message).To(Equal("rule only applies during a schedule override"))
+			})
+		})
+
+		Context("when the app is in a scaling group", func() {
+			var (
+				frontendInstances int
+				frontendPolicy    *models.ScalingPolicy
+			)
+
+			BeforeEach(func() {
+				frontendInstances = 2
+				cfc.GetAppStub = func(appId string) (*models.AppEntity, error) {
+					if appId == "an-app-id" {
+						return &models.AppEntity{Instances: 4, State: &appState}, nil
+					}
+					return &models.AppEntity{Instances: frontendInstances, State: &appState}, nil
+				}
+				scalingEngineDB.CanScaleAppReturns(true, 0, nil)
+				frontendPolicy = &models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6,
+					ScalingRules: []*models.ScalingRule{{MetricType: "memoryused", CoolDownSeconds: 120}}}
+				policyDB.GetAppPolicyStub = func(appId string) (*models.ScalingPolicy, error) {
+					if appId == "frontend-app-id" {
+						return frontendPolicy, nil
+					}
+					return &models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6}, nil
+				}
+				policyDB.GetScalingGroupReturns(&models.ScalingGroup{
+					GroupId: "a-group-id",
+					Members: []*models.ScalingGroupMember{
+						{AppId: "an-app-id", Ratio: 2},
+						{AppId: "frontend-app-id", Ratio: 1},
+					},
+				}, nil)
+			})
+
+			It("scales the other members and links the histories by the group id", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfc.SetAppInstancesCallCount()).To(Equal(2))
+				id, num := cfc.SetAppInstancesArgsForCall(1)
+				Expect(id).To(Equal("frontend-app-id"))
+				Expect(num).To(Equal(3))
+
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).GroupId).To(Equal("a-group-id"))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1)).To(Equal(&models.AppScalingHistory{
+					AppId:        "frontend-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeDynamic,
+					Status:       models.ScalingStatusSucceeded,
+					OldInstances: 2,
+					NewInstances: 3,
+					Reason:       "follow app an-app-id in scaling group a-group-id",
+					GroupId:      "a-group-id",
+				}))
+				Expect(scalingEngineDB.SaveScalingExplanationCallCount()).To(Equal(1))
+				Expect(notifier.DeliveriesCallCount()).To(Equal(2))
+
+				id, direction, _, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(1)
+				Expect(id).To(Equal("frontend-app-id"))
+				Expect(direction).To(Equal(models.ScalingDirectionOut))
+				Expect(expiredAt).To(Equal(clock.Now().Add(120 * time.Second).UnixNano()))
+
+				Expect(scalingResult.Instances).To(Equal(5))
+			})
+
+			It("checks the cooldown of the other member", func() {
+				Expect(scalingEngineDB.CanScaleAppCallCount()).To(Equal(2))
+				id, direction, key := scalingEngineDB.CanScaleAppArgsForCall(1)
+				Expect(id).To(Equal("frontend-app-id"))
+				Expect(direction).To(Equal(models.ScalingDirectionOut))
+				Expect(key).To(BeEmpty())
+			})
+
+			Context("when the other member is in cooldown period", func() {
+				BeforeEach(func() {
+					scalingEngineDB.CanScaleAppStub = func(appId string, direction string, key string) (bool, int64, error) {
+						return appId != "frontend-app-id", 0, nil
+					}
+				})
+
+				It("does not scale the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(1)
+					Expect(history.Status).To(Equal(models.ScalingStatusIgnored))
+					Expect(history.Message).To(Equal("app in scale-out cooldown period"))
+				})
+			})
+
+			Context("when the policy of the other member is in shadow mode", func() {
+				BeforeEach(func() {
+					frontendPolicy.Mode = models.PolicyModeShadow
+				})
+
+				It("simulates the scaling of the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					id, _ := cfc.SetAppInstancesArgsForCall(0)
+					Expect(id).To(Equal("an-app-id"))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Status).To(Equal(models.ScalingStatusSimulated))
+				})
+			})
+
+			Context("when the trigger is in shadow mode but the policy of the other member is not", func() {
+				BeforeEach(func() {
+					trigger.Mode = models.PolicyModeShadow
+				})
+
+				It("scales the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					id, num := cfc.SetAppInstancesArgsForCall(0)
+					Expect(id).To(Equal("frontend-app-id"))
+					Expect(num).To(Equal(3))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusSimulated))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Status).To(Equal(models.ScalingStatusSucceeded))
+				})
+			})
+
+			Context("when the other member scales in", func() {
+				BeforeEach(func() {
+					frontendInstances = 6
+					frontendPolicy.ScaleInMaxStep = 1
+					frontendPolicy.ScaleInStabilizationSeconds = 300
+					scalingEngineDB.GetMaxScalingRecommendationReturns(3, nil)
+				})
+
+				It("applies the scale-in limits of the member's policy", func() {
+					Expect(scalingEngineDB.SaveScalingRecommendationCallCount()).To(Equal(1))
+					id, _, recommendation := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+					Expect(id).To(Equal("frontend-app-id"))
+					Expect(recommendation).To(Equal(3))
+
+					id, num := cfc.SetAppInstancesArgsForCall(1)
+					Expect(id).To(Equal("frontend-app-id"))
+					Expect(num).To(Equal(5))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Message).To(Equal("limited by max scale-in step 1"))
+				})
+			})
+
+			Context("when the other member has no policy", func() {
+				BeforeEach(func() {
+					frontendPolicy = nil
+					scalingEngineDB.GetActiveScheduleStub = func(appId string) (*models.ActiveSchedule, error) {
+						return &models.ActiveSchedule{ScheduleId: "a-schedule-id", InstanceMin: 1, InstanceMax: 6}, nil
+					}
+				})
+
+				It("does not scale the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(1)
+					Expect(history.AppId).To(Equal("frontend-app-id"))
+					Expect(history.Status).To(Equal(models.ScalingStatusFailed))
+					Expect(history.Error).To(Equal("app does not have policy set"))
+				})
+			})
+
+			Context("when the other member already runs the instances", func() {
+				BeforeEach(func() {
+					frontendInstances = 3
+				})
+
+				It("stores the ignored scaling history of the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Status).To(Equal(models.ScalingStatusIgnored))
+				})
+			})
+
+			Context("when the app instances are not changed", func() {
+				BeforeEach(func() {
+					scalingEngineDB.CanScaleAppReturns(false, clock.Now().Add(30*time.Second).UnixNano(), nil)
+				})
+
+				It("does not scale the other members", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the other member is pinned by a schedule override", func() {
+				BeforeEach(func() {
+					scalingEngineDB.GetActiveScheduleStub = func(appId string) (*models.ActiveSchedule, error) {
+						if appId == "frontend-app-id" {
+							return &models.ActiveSchedule{ScheduleId: "a-schedule-id", InstanceMin: 1, InstanceMax: 6,
+								Override: &models.ScheduleOverride{Mode: models.ScheduleOverridePin, InstanceCount: 2}}, nil
+						}
+						return nil, nil
+					}
+				})
+
+				It("does not scale the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(1)
+					Expect(history.AppId).To(Equal("frontend-app-id"))
+					Expect(history.Status).To(Equal(models.ScalingStatusIgnored))
+					Expect(history.Message).To(Equal("app pinned at 2 instances by schedule override"))
+				})
+			})
+
+			Context("when the other member is paused", func() {
+				BeforeEach(func() {
+					policyDB.GetAppPauseStub = func(appId string) (*models.AppPause, error) {
+						if appId == "frontend-app-id" {
+							return &models.AppPause{AppId: appId, PausedBy: "a-user-id"}, nil
+						}
+						return nil, nil
+					}
+				})
+
+				It("does not scale the member", func() {
+					Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(1)
+					Expect(history.AppId).To(Equal("frontend-app-id"))
+					Expect(history.Status).To(Equal(models.ScalingStatusIgnored))
+					Expect(history.Message).To(Equal("autoscaling paused by a-user-id"))
+				})
+			})
+
+			Context("when getting the scaling group fails", func() {
+				BeforeEach(func() {
+					policyDB.GetScalingGroupReturns(nil, errors.New("test error"))
+				})
+
+				It("stores the failed scaling history", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Error).To(Equal("failed to get scaling group"))
+				})
+			})
 		})
 
 		Context("when app instances not changed", func() {
@@ -192,6 +737,61 @@ var _ = Describe("ScalingEngine", func() {
 			})
 		})
 
+		Context("when the policy is in shadow mode", func() {
+			BeforeEach(func() {
+				trigger.Mode = models.PolicyModeShadow
+				cfc.GetAppReturns(&models.AppEntity{Instances: 5, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 6, Mode: models.PolicyModeShadow}, nil)
+			})
+
+			Context("when the app instances would change", func() {
+				BeforeEach(func() {
+					trigger.Adjustment = "+2"
+				})
+
+				It("does not set the app instance number and stores the simulated scaling history", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+
+					id, direction, ruleKey, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
+					Expect(id).To(Equal("an-app-id"))
+					Expect(direction).To(Equal(models.ScalingDirectionOut))
+					Expect(ruleKey).To(BeEmpty())
+					Expect(expiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
+
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+						AppId:        "an-app-id",
+						Timestamp:    clock.Now().UnixNano(),
+						ScalingType:  models.ScalingTypeDynamic,
+						Status:       models.ScalingStatusSimulated,
+						OldInstances: 5,
+						NewInstances: 6,
+						Reason:       "+2 instance(s) because test-metric-type > 80test-unit for 100 seconds",
+						Message:      "limited by max instances 6",
+					}))
+
+					Expect(scalingResult.Status).To(Equal(models.ScalingStatusSimulated))
+					Expect(scalingResult.Adjustment).To(Equal(1))
+					Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
+				})
+			})
+
+			Context("when the app instances would not change", func() {
+				BeforeEach(func() {
+					trigger.Adjustment = "-10"
+					policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 5, InstanceMax: 6, Mode: models.PolicyModeShadow}, nil)
+				})
+
+				It("stores the ignored scaling history", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusIgnored))
+					Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
+				})
+			})
+		})
+
 		Context("when it exceeds max instances limit in scaling policy", func() {
 			BeforeEach(func() {
 				trigger.Adjustment = "+2"
@@ -208,8 +808,10 @@ var _ = Describe("ScalingEngine", func() {
 				Expect(id).To(Equal("an-app-id"))
 				Expect(num).To(Equal(6))
 
-				id, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
+				id, direction, ruleKey, expiredAt := scalingEngineDB.UpdateScalingCooldownExpireTimeArgsForCall(0)
 				Expect(id).To(Equal("an-app-id"))
+				Expect(direction).To(Equal(models.ScalingDirectionOut))
+				Expect(ruleKey).To(BeEmpty())
 				Expect(expiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
 
 				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
@@ -274,29 +876,209 @@ var _ = Describe("ScalingEngine", func() {
 
 			})
 
-			It("updates the app instance with  min instances and stores the succeeded scaling history", func() {
+			It("updates the app instance with  min instances and stores the succeeded scaling history", func() {
+				Expect(err).NotTo(HaveOccurred())
+
+				id, num := cfc.SetAppInstancesArgsForCall(0)
+				Expect(id).To(Equal("an-app-id"))
+				Expect(num).To(Equal(2))
+
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeDynamic,
+					Status:       models.ScalingStatusSucceeded,
+					OldInstances: 3,
+					NewInstances: 2,
+					Reason:       "-60% instance(s) because test-metric-type > 80test-unit for 100 seconds",
+					Message:      "limited by min instances 2",
+				}))
+
+				Expect(scalingResult.AppId).To(Equal("an-app-id"))
+				Expect(scalingResult.Status).To(Equal(models.ScalingStatusSucceeded))
+				Expect(scalingResult.Adjustment).To(Equal(-1))
+				Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
+
+			})
+		})
+
+		Context("when scale-in stabilization is set", func() {
+			BeforeEach(func() {
+				trigger.Adjustment = "-2"
+				trigger.ScaleInStabilizationSeconds = 300
+				cfc.GetAppReturns(&models.AppEntity{Instances: 5, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 10}, nil)
+				scalingEngineDB.GetMaxScalingRecommendationReturns(3, nil)
+			})
+
+			It("saves the recommendation and looks up the highest one in the window", func() {
+				Expect(err).NotTo(HaveOccurred())
+				appId, timestamp, instances := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(timestamp).To(Equal(clock.Now().UnixNano()))
+				Expect(instances).To(Equal(3))
+
+				appId, start := scalingEngineDB.GetMaxScalingRecommendationArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(start).To(Equal(clock.Now().Add(-300 * time.Second).UnixNano()))
+			})
+
+			Context("when there is no higher recommendation in the window", func() {
+				It("scales in to the recommendation", func() {
+					_, num := cfc.SetAppInstancesArgsForCall(0)
+					Expect(num).To(Equal(3))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Message).To(BeEmpty())
+				})
+			})
+
+			Context("when there is a higher recommendation in the window", func() {
+				BeforeEach(func() {
+					scalingEngineDB.GetMaxScalingRecommendationReturns(4, nil)
+				})
+
+				It("scales in to the highest recommendation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					_, num := cfc.SetAppInstancesArgsForCall(0)
+					Expect(num).To(Equal(4))
+
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+						AppId:        "an-app-id",
+						Timestamp:    clock.Now().UnixNano(),
+						ScalingType:  models.ScalingTypeDynamic,
+						Status:       models.ScalingStatusSucceeded,
+						OldInstances: 5,
+						NewInstances: 4,
+						Reason:       "-2 instance(s) because test-metric-type > 80test-unit for 100 seconds",
+						Message:      "limited by highest recommendation 4 in scale-in stabilization window",
+					}))
+					Expect(scalingResult.Instances).To(Equal(4))
+				})
+			})
+
+			Context("when the highest recommendation in the window is above the current instances", func() {
+				BeforeEach(func() {
+					scalingEngineDB.GetMaxScalingRecommendationReturns(8, nil)
+				})
+
+				It("does not scale in and stores the ignored scaling history", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(0)
+					Expect(history.Status).To(Equal(models.ScalingStatusIgnored))
+					Expect(history.NewInstances).To(Equal(5))
+					Expect(history.Message).To(Equal("limited by highest recommendation 5 in scale-in stabilization window"))
+					Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
+				})
+			})
+
+			Context("when scaling out", func() {
+				BeforeEach(func() {
+					trigger.Adjustment = "+2"
+				})
+
+				It("saves the recommendation and scales out immediately", func() {
+					Expect(err).NotTo(HaveOccurred())
+					_, _, instances := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+					Expect(instances).To(Equal(7))
+					Expect(scalingEngineDB.GetMaxScalingRecommendationCallCount()).To(BeZero())
+
+					_, num := cfc.SetAppInstancesArgsForCall(0)
+					Expect(num).To(Equal(7))
+				})
+			})
+
+			Context("when a scale-out is in cooldown period", func() {
+				BeforeEach(func() {
+					trigger.Adjustment = "+2"
+					scalingEngineDB.CanScaleAppReturns(false, clock.Now().Add(30*time.Second).UnixNano(), nil)
+				})
+
+				It("still saves the recommendation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					appId, timestamp, instances := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+					Expect(appId).To(Equal("an-app-id"))
+					Expect(timestamp).To(Equal(clock.Now().UnixNano()))
+					Expect(instances).To(Equal(7))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusIgnored))
+				})
+			})
+
+			Context("when saving the recommendation fails", func() {
+				BeforeEach(func() {
+					scalingEngineDB.SaveScalingRecommendationReturns(errors.New("test error"))
+				})
+
+				It("should error and store the failed scaling history", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusFailed))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Error).To(Equal("failed to save scaling recommendation"))
+				})
+			})
+
+			Context("when getting the highest recommendation fails", func() {
+				BeforeEach(func() {
+					scalingEngineDB.GetMaxScalingRecommendationReturns(-1, errors.New("test error"))
+				})
+
+				It("should error and store the failed scaling history", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Status).To(Equal(models.ScalingStatusFailed))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Error).To(Equal("failed to get scaling recommendations"))
+				})
+			})
+		})
+
+		Context("when scale-in stabilization is not set", func() {
+			BeforeEach(func() {
+				trigger.Adjustment = "-2"
+				cfc.GetAppReturns(&models.AppEntity{Instances: 5, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 10}, nil)
+			})
+
+			It("does not track recommendations", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingRecommendationCallCount()).To(BeZero())
+				Expect(scalingEngineDB.GetMaxScalingRecommendationCallCount()).To(BeZero())
+				_, num := cfc.SetAppInstancesArgsForCall(0)
+				Expect(num).To(Equal(3))
+			})
+		})
+
+		Context("when scale-in max step is set", func() {
+			BeforeEach(func() {
+				trigger.Adjustment = "-50%"
+				trigger.ScaleInMaxStep = 2
+				cfc.GetAppReturns(&models.AppEntity{Instances: 8, State: &appState}, nil)
+				scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				policyDB.GetAppPolicyReturns(&models.ScalingPolicy{InstanceMin: 1, InstanceMax: 10}, nil)
+			})
+
+			It("removes no more instances than the max step", func() {
 				Expect(err).NotTo(HaveOccurred())
+				_, num := cfc.SetAppInstancesArgsForCall(0)
+				Expect(num).To(Equal(6))
 
-				id, num := cfc.SetAppInstancesArgsForCall(0)
-				Expect(id).To(Equal("an-app-id"))
-				Expect(num).To(Equal(2))
-
-				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
-					AppId:        "an-app-id",
-					Timestamp:    clock.Now().UnixNano(),
-					ScalingType:  models.ScalingTypeDynamic,
-					Status:       models.ScalingStatusSucceeded,
-					OldInstances: 3,
-					NewInstances: 2,
-					Reason:       "-60% instance(s) because test-metric-type > 80test-unit for 100 seconds",
-					Message:      "limited by min instances 2",
-				}))
+				history := scalingEngineDB.SaveScalingHistoryArgsForCall(0)
+				Expect(history.NewInstances).To(Equal(6))
+				Expect(history.Message).To(Equal("limited by max scale-in step 2"))
+				Expect(scalingResult.Adjustment).To(Equal(-2))
+			})
 
-				Expect(scalingResult.AppId).To(Equal("an-app-id"))
-				Expect(scalingResult.Status).To(Equal(models.ScalingStatusSucceeded))
-				Expect(scalingResult.Adjustment).To(Equal(-1))
-				Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
+			Context("when scaling out", func() {
+				BeforeEach(func() {
+					trigger.Adjustment = "+50%"
+				})
 
+				It("does not limit the scaling", func() {
+					_, num := cfc.SetAppInstancesArgsForCall(0)
+					Expect(num).To(Equal(10))
+				})
 			})
 		})
 
@@ -341,6 +1123,113 @@ var _ = Describe("ScalingEngine", func() {
 					Expect(scalingResult.CooldownExpiredAt).To(Equal(clock.Now().Add(30 * time.Second).UnixNano()))
 
 				})
+
+				It("stores the active schedule and the clamped instances in the explanation", func() {
+					_, explanation := scalingEngineDB.SaveScalingExplanationArgsForCall(0)
+					Expect(explanation.ActiveSchedule).To(Equal(&models.ActiveSchedule{
+						ScheduleId:  "111111",
+						InstanceMin: 3,
+						InstanceMax: 7,
+					}))
+					Expect(explanation.ComputedInstances).To(Equal(8))
+					Expect(explanation.InstanceMin).To(Equal(3))
+					Expect(explanation.InstanceMax).To(Equal(7))
+					Expect(explanation.NewInstances).To(Equal(7))
+				})
+			})
+
+			Context("when the active schedule has an override", func() {
+				var override *models.ScheduleOverride
+
+				BeforeEach(func() {
+					override = &models.ScheduleOverride{}
+					scalingEngineDB.GetActiveScheduleReturns(&models.ActiveSchedule{
+						ScheduleId:  "111111",
+						InstanceMin: 3,
+						InstanceMax: 7,
+						Override:    override,
+					}, nil)
+					cfc.GetAppReturns(&models.AppEntity{Instances: 4, State: &appState}, nil)
+					scalingEngineDB.CanScaleAppReturns(true, clock.Now().Add(0-30*time.Second).UnixNano(), nil)
+				})
+
+				Context("when the override suspends dynamic scaling", func() {
+					BeforeEach(func() {
+						override.Mode = models.ScheduleOverrideSuspend
+					})
+
+					It("ignores the trigger and stores the ignored scaling history", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+						Expect(scalingEngineDB.CanScaleAppCallCount()).To(BeZero())
+						Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+							AppId:        "an-app-id",
+							Timestamp:    clock.Now().UnixNano(),
+							ScalingType:  models.ScalingTypeDynamic,
+							Status:       models.ScalingStatusIgnored,
+							OldInstances: 4,
+							NewInstances: 4,
+							Reason:       "+1 instance(s) because test-metric-type > 80test-unit for 100 seconds",
+							Message:      "dynamic scaling suspended by schedule override",
+						}))
+						Expect(scalingResult.Status).To(Equal(models.ScalingStatusIgnored))
+						Expect(scalingResult.Instances).To(Equal(4))
+						Expect(scalingResult.CooldownExpiredAt).To(BeZero())
+					})
+				})
+
+				Context("when the override pins the instances", func() {
+					BeforeEach(func() {
+						override.Mode = models.ScheduleOverridePin
+						override.InstanceCount = 4
+					})
+
+					It("ignores the trigger", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+						Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Message).To(Equal("app pinned at 4 instances by schedule override"))
+					})
+				})
+
+				Context("when the override replaces the scaling rules", func() {
+					BeforeEach(func() {
+						override.Mode = models.ScheduleOverrideRules
+						override.ScalingRules = []*models.ScalingRule{{MetricType: "test-metric-type", Threshold: 80, Operator: ">", Adjustment: "+1"}}
+					})
+
+					Context("when the trigger is from a rule of the override", func() {
+						BeforeEach(func() {
+							trigger.ScheduleOnly = true
+						})
+
+						It("scales the app within the active schedule", func() {
+							Expect(err).NotTo(HaveOccurred())
+							_, num := cfc.SetAppInstancesArgsForCall(0)
+							Expect(num).To(Equal(5))
+						})
+					})
+
+					Context("when the trigger is from another rule of an override on the same metric", func() {
+						BeforeEach(func() {
+							trigger.ScheduleOnly = true
+							trigger.Threshold = 90
+						})
+
+						It("ignores the trigger", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+							Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Message).To(Equal("rule replaced by schedule override"))
+						})
+					})
+
+					Context("when the trigger is from a rule of the policy", func() {
+						It("ignores the trigger", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+							Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Message).To(Equal("rule replaced by schedule override"))
+						})
+					})
+				})
 			})
 
 			Context("when it exceeds min instances limit  in active schedule", func() {
@@ -403,6 +1292,17 @@ var _ = Describe("ScalingEngine", func() {
 				Expect(scalingResult).To(BeNil())
 
 			})
+
+			It("notifies the failed scaling history", func() {
+				Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+				Expect(notifier.DeliveriesArgsForCall(0).Status).To(Equal(models.ScalingStatusFailed))
+			})
+
+			It("stores the explanation without instances", func() {
+				_, explanation := scalingEngineDB.SaveScalingExplanationArgsForCall(0)
+				Expect(explanation.CurrentInstances).To(Equal(-1))
+				Expect(explanation.NewInstances).To(Equal(-1))
+			})
 		})
 
 		Context("When checking cooldown fails", func() {
@@ -575,15 +1475,27 @@ var _ = Describe("ScalingEngine", func() {
 
 	Describe("ComputeNewInstances", func() {
 		var adjustment string
+		var targetValue float64
+		var metricValue float64
+		var scaleInDamping float64
+		var instances int
 		var newInstances int
 
 		BeforeEach(func() {
 			adjustment = ""
+			targetValue = 0
+			metricValue = 0
+			scaleInDamping = 0
+			instances = 3
 		})
 
 		JustBeforeEach(func() {
-			instances := 3
-			newInstances, err = scalingEngine.ComputeNewInstances(instances, adjustment)
+			newInstances, err = scalingEngine.ComputeNewInstances(instances, &models.Trigger{
+				Adjustment:     adjustment,
+				TargetValue:    targetValue,
+				MetricValue:    metricValue,
+				ScaleInDamping: scaleInDamping,
+			})
 		})
 
 		Context("when adjustment is not valid", func() {
@@ -638,6 +1550,73 @@ var _ = Describe("ScalingEngine", func() {
 				})
 			})
 		})
+
+		Context("when the trigger has a target value", func() {
+			BeforeEach(func() {
+				targetValue = 60
+				instances = 10
+			})
+
+			Context("when the metric is above the target", func() {
+				BeforeEach(func() {
+					metricValue = 75
+				})
+				It("scales out proportionally", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(newInstances).To(Equal(13))
+				})
+			})
+
+			Context("when the metric is slightly below the target", func() {
+				BeforeEach(func() {
+					metricValue = 55
+				})
+				It("keeps the instance number", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(newInstances).To(Equal(10))
+				})
+			})
+
+			Context("when the metric is below the target", func() {
+				BeforeEach(func() {
+					metricValue = 30
+				})
+				It("scales in with the default damping", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(newInstances).To(Equal(7))
+				})
+
+				Context("when scale in damping is set", func() {
+					BeforeEach(func() {
+						scaleInDamping = 1
+					})
+					It("scales in with the given damping", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(newInstances).To(Equal(5))
+					})
+				})
+			})
+
+			Context("when the scale in is smaller than one instance after damping", func() {
+				BeforeEach(func() {
+					instances = 2
+					metricValue = 20
+				})
+				It("removes one instance", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(newInstances).To(Equal(1))
+				})
+			})
+
+			Context("when the target value is invalid", func() {
+				BeforeEach(func() {
+					targetValue = -1
+				})
+				It("should error", func() {
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Describe("SetActiveSchedule", func() {
@@ -656,6 +1635,11 @@ var _ = Describe("ScalingEngine", func() {
 			Expect(schedule).To(Equal(activeSchedule))
 		})
 
+		It("notifies the scaling history", func() {
+			Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+			Expect(notifier.DeliveriesArgsForCall(0).ScalingType).To(Equal(models.ScalingTypeSchedule))
+		})
+
 		Context("when app instance number is greater than InstanceMax in active schedule", func() {
 			BeforeEach(func() {
 				cfc.GetAppReturns(&models.AppEntity{Instances: 12, State: &appState}, nil)
@@ -677,7 +1661,54 @@ var _ = Describe("ScalingEngine", func() {
 					Reason:       "schedule starts with instance min 2, instance max 10 and instance min initial 5",
 					Message:      "limited by max instances 10",
 				}))
+				Expect(scalingEngineDB.SaveScalingRecommendationCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the app is paused", func() {
+			BeforeEach(func() {
+				cfc.GetAppReturns(&models.AppEntity{Instances: 12, State: &appState}, nil)
+				policyDB.GetAppPauseReturns(&models.AppPause{AppId: "an-app-id", PausedBy: "a-user-id"}, nil)
+			})
+
+			It("saves the active schedule but does not change the app instances", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SetActiveScheduleCallCount()).To(Equal(1))
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeSchedule,
+					Status:       models.ScalingStatusIgnored,
+					OldInstances: 12,
+					NewInstances: 12,
+					Reason:       "schedule starts with instance min 2, instance max 10 and instance min initial 5",
+					Message:      "autoscaling paused by a-user-id",
+				}))
+			})
+		})
+
+		Context("when the active schedule pins the app instances", func() {
+			BeforeEach(func() {
+				activeSchedule.Override = &models.ScheduleOverride{Mode: models.ScheduleOverridePin, InstanceCount: 4}
+				cfc.GetAppReturns(&models.AppEntity{Instances: 7, State: &appState}, nil)
+			})
 
+			It("sets the app instances to the pinned count", func() {
+				Expect(err).NotTo(HaveOccurred())
+
+				_, instances := cfc.SetAppInstancesArgsForCall(0)
+				Expect(instances).To(Equal(4))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeSchedule,
+					Status:       models.ScalingStatusSucceeded,
+					OldInstances: 7,
+					NewInstances: 4,
+					Reason:       "schedule starts with instance min 2, instance max 10 and instance min initial 5 and pin override",
+					Message:      "pinned at 4 instances by schedule override",
+				}))
 			})
 		})
 
@@ -708,7 +1739,13 @@ var _ = Describe("ScalingEngine", func() {
 						Reason:       "schedule starts with instance min 2, instance max 10 and instance min initial 0",
 						Message:      "limited by min instances 2",
 					}))
+				})
 
+				It("saves the scale-out as a scaling recommendation", func() {
+					appId, timestamp, instances := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+					Expect(appId).To(Equal("an-app-id"))
+					Expect(timestamp).To(Equal(clock.Now().UnixNano()))
+					Expect(instances).To(Equal(2))
 				})
 			})
 
@@ -912,6 +1949,28 @@ var _ = Describe("ScalingEngine", func() {
 					Reason:       "schedule ends",
 				}))
 			})
+
+			It("notifies the scaling history", func() {
+				Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+				Expect(notifier.DeliveriesArgsForCall(0).Reason).To(Equal("schedule ends"))
+			})
+		})
+
+		Context("when the app is paused", func() {
+			BeforeEach(func() {
+				scalingEngineDB.GetActiveScheduleReturns(&models.ActiveSchedule{ScheduleId: "a-schedule-id"}, nil)
+				cfc.GetAppReturns(&models.AppEntity{Instances: 1, State: &appState}, nil)
+				policyDB.GetAppPauseReturns(&models.AppPause{AppId: "an-app-id", PausedBy: "a-user-id"}, nil)
+			})
+
+			It("removes the active schedule but does not change the app instances", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.RemoveActiveScheduleCallCount()).To(Equal(1))
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+				history := scalingEngineDB.SaveScalingHistoryArgsForCall(0)
+				Expect(history.Status).To(Equal(models.ScalingStatusIgnored))
+				Expect(history.Message).To(Equal("autoscaling paused by a-user-id"))
+			})
 		})
 
 		Context("when app instance number is below the default InstanceMin in the policy", func() {
@@ -934,7 +1993,13 @@ var _ = Describe("ScalingEngine", func() {
 					Reason:       "schedule ends",
 					Message:      "limited by min instances 3",
 				}))
+			})
 
+			It("saves the scale-out as a scaling recommendation", func() {
+				appId, timestamp, instances := scalingEngineDB.SaveScalingRecommendationArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(timestamp).To(Equal(clock.Now().UnixNano()))
+				Expect(instances).To(Equal(3))
 			})
 		})
 
@@ -1100,4 +2165,249 @@ var _ = Describe("ScalingEngine", func() {
 		})
 
 	})
+	Describe("PauseApp", func() {
+		var pause *models.AppPause
+
+		BeforeEach(func() {
+			pause = &models.AppPause{PausedBy: "a-user-id", Reason: "database migration"}
+			cfc.GetAppReturns(&models.AppEntity{Instances: 3, State: &appState}, nil)
+		})
+
+		JustBeforeEach(func() {
+			err = scalingEngine.PauseApp("an-app-id", pause)
+		})
+
+		It("saves the pause and records it in the scaling history", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policyDB.SaveAppPauseArgsForCall(0)).To(Equal(&models.AppPause{
+				AppId:    "an-app-id",
+				PausedBy: "a-user-id",
+				Reason:   "database migration",
+				PausedAt: clock.Now().UnixNano(),
+			}))
+			Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+				AppId:        "an-app-id",
+				Timestamp:    clock.Now().UnixNano(),
+				ScalingType:  models.ScalingTypePause,
+				Status:       models.ScalingStatusSucceeded,
+				OldInstances: 3,
+				NewInstances: 3,
+				Reason:       "autoscaling paused by a-user-id",
+				Message:      "database migration",
+			}))
+			Expect(notifier.DeliveriesCallCount()).To(Equal(1))
+		})
+
+		Context("when the pause expires", func() {
+			BeforeEach(func() {
+				pause.ExpireAt = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano()
+			})
+
+			It("records the expiry in the scaling history", func() {
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0).Reason).To(Equal("autoscaling paused by a-user-id until 2030-01-02T03:04:05Z"))
+			})
+		})
+
+		Context("when getting app info fails", func() {
+			BeforeEach(func() {
+				cfc.GetAppReturns(nil, errors.New("get-app-error"))
+			})
+
+			It("still records the pause", func() {
+				Expect(err).NotTo(HaveOccurred())
+				history := scalingEngineDB.SaveScalingHistoryArgsForCall(0)
+				Expect(history.OldInstances).To(Equal(-1))
+				Expect(history.NewInstances).To(Equal(-1))
+			})
+		})
+
+		Context("when saving the pause fails", func() {
+			BeforeEach(func() {
+				policyDB.SaveAppPauseReturns(errors.New("save-pause-error"))
+			})
+
+			It("returns the error and does not record the pause", func() {
+				Expect(err).To(MatchError("save-pause-error"))
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("ResumeApp", func() {
+		BeforeEach(func() {
+			policyDB.GetAppPauseReturns(&models.AppPause{AppId: "an-app-id", PausedBy: "a-user-id"}, nil)
+			cfc.GetAppReturns(&models.AppEntity{Instances: 3, State: &appState}, nil)
+		})
+
+		JustBeforeEach(func() {
+			err = scalingEngine.ResumeApp("an-app-id", "another-user-id")
+		})
+
+		It("deletes the pause and records the resume in the scaling history", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policyDB.DeleteAppPauseArgsForCall(0)).To(Equal("an-app-id"))
+			Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+				AppId:        "an-app-id",
+				Timestamp:    clock.Now().UnixNano(),
+				ScalingType:  models.ScalingTypePause,
+				Status:       models.ScalingStatusSucceeded,
+				OldInstances: 3,
+				NewInstances: 3,
+				Reason:       "autoscaling resumed by another-user-id",
+			}))
+		})
+
+		Context("when the app is not paused", func() {
+			BeforeEach(func() {
+				policyDB.GetAppPauseReturns(nil, nil)
+			})
+
+			It("returns AppNotPausedError", func() {
+				Expect(err).To(BeAssignableToTypeOf(&AppNotPausedError{}))
+				Expect(policyDB.DeleteAppPauseCallCount()).To(BeZero())
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+			})
+		})
+
+		It("does not scale the app when no schedule is active", func() {
+			Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(Equal(1))
+			Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+		})
+
+		Context("when a schedule started while the app was paused", func() {
+			BeforeEach(func() {
+				scalingEngineDB.GetActiveScheduleReturns(activeSchedule, nil)
+			})
+
+			It("scales the app into the limits of the schedule", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfc.SetAppInstancesCallCount()).To(Equal(1))
+				appId, instances := cfc.SetAppInstancesArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(instances).To(Equal(5))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1)).To(Equal(&models.AppScalingHistory{
+					AppId:        "an-app-id",
+					Timestamp:    clock.Now().UnixNano(),
+					ScalingType:  models.ScalingTypeSchedule,
+					Status:       models.ScalingStatusSucceeded,
+					OldInstances: 3,
+					NewInstances: 5,
+					Reason:       "schedule starts with instance min 2, instance max 10 and instance min initial 5, applied as autoscaling resumed by another-user-id",
+					Message:      "limited by min instances 5",
+				}))
+			})
+
+			Context("when the schedule pins the app", func() {
+				BeforeEach(func() {
+					activeSchedule.Override = &models.ScheduleOverride{Mode: models.ScheduleOverridePin, InstanceCount: 7}
+				})
+
+				It("scales the app to the pin", func() {
+					_, instances := cfc.SetAppInstancesArgsForCall(0)
+					Expect(instances).To(Equal(7))
+					Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Message).To(Equal("pinned at 7 instances by schedule override"))
+				})
+			})
+
+			Context("when scaling the app fails", func() {
+				BeforeEach(func() {
+					cfc.SetAppInstancesReturns(errors.New("set-instances-error"))
+				})
+
+				It("records the failure and still resumes the app", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(policyDB.DeleteAppPauseCallCount()).To(Equal(1))
+					history := scalingEngineDB.SaveScalingHistoryArgsForCall(1)
+					Expect(history.Status).To(Equal(models.ScalingStatusFailed))
+					Expect(history.Error).To(Equal("failed to set app instances: set-instances-error"))
+				})
+			})
+		})
+
+		Context("when getting the active schedule fails", func() {
+			BeforeEach(func() {
+				scalingEngineDB.GetActiveScheduleReturns(nil, errors.New("get-schedule-error"))
+			})
+
+			It("still resumes the app", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(Equal(1))
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+				Eventually(buffer).Should(gbytes.Say("failed-to-get-active-schedule"))
+			})
+		})
+
+		Context("when deleting the pause fails", func() {
+			BeforeEach(func() {
+				policyDB.DeleteAppPauseReturns(errors.New("delete-pause-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(MatchError("delete-pause-error"))
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("ExpireAppPause", func() {
+		BeforeEach(func() {
+			cfc.GetAppReturns(&models.AppEntity{Instances: 3, State: &appState}, nil)
+		})
+
+		JustBeforeEach(func() {
+			err = scalingEngine.ExpireAppPause("an-app-id")
+		})
+
+		It("deletes the expired pause and records the expiry in the scaling history", func() {
+			Expect(err).NotTo(HaveOccurred())
+			appId, now := policyDB.DeleteExpiredAppPauseArgsForCall(0)
+			Expect(appId).To(Equal("an-app-id"))
+			Expect(now).To(Equal(clock.Now().UnixNano()))
+			Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(0)).To(Equal(&models.AppScalingHistory{
+				AppId:        "an-app-id",
+				Timestamp:    clock.Now().UnixNano(),
+				ScalingType:  models.ScalingTypePause,
+				Status:       models.ScalingStatusSucceeded,
+				OldInstances: 3,
+				NewInstances: 3,
+				Reason:       "autoscaling pause expired",
+			}))
+		})
+
+		Context("when a schedule is active", func() {
+			BeforeEach(func() {
+				scalingEngineDB.GetActiveScheduleReturns(activeSchedule, nil)
+			})
+
+			It("scales the app into the limits of the schedule", func() {
+				_, instances := cfc.SetAppInstancesArgsForCall(0)
+				Expect(instances).To(Equal(5))
+				Expect(scalingEngineDB.SaveScalingHistoryArgsForCall(1).Reason).To(Equal("schedule starts with instance min 2, instance max 10 and instance min initial 5, applied as autoscaling pause expired"))
+			})
+		})
+
+		Context("when the pause has not expired or is expired by another scaling engine", func() {
+			BeforeEach(func() {
+				policyDB.DeleteExpiredAppPauseReturns(db.ErrDoesNotExist)
+				scalingEngineDB.GetActiveScheduleReturns(activeSchedule, nil)
+			})
+
+			It("does nothing", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+				Expect(cfc.SetAppInstancesCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when deleting the pause fails", func() {
+			BeforeEach(func() {
+				policyDB.DeleteExpiredAppPauseReturns(errors.New("delete-pause-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(MatchError("delete-pause-error"))
+				Expect(scalingEngineDB.SaveScalingHistoryCallCount()).To(BeZero())
+			})
+		})
+	})
 })
@@ -177,6 +177,40 @@ func (h *ScalingHandler) GetScalingHistories(w http.ResponseWriter, r *http.Requ
 	w.Write(body)
 }
 
+func (h *ScalingHandler) GetScalingHistoryExplanation(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+
+	appId := vars["appid"]
+	logger := h.logger.Session("get-scaling-history-explanation", lager.Data{"appId": appId, "historyId": vars["historyid"]})
+
+	historyId, err := strconv.ParseInt(vars["historyid"], 10, 64)
+	if err != nil {
+		logger.Error("failed-to-parse-history-id", err)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Error parsing scaling history id"})
+		return
+	}
+
+	explanation, err := h.scalingEngineDB.RetrieveScalingExplanation(appId, historyId)
+	if err != nil {
+		logger.Error("failed-to-retrieve-explanation", err)
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error getting scaling explanation from database"})
+		return
+	}
+
+	if explanation == nil {
+		handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+			Code:    "Not-Found",
+			Message: "Scaling explanation not found",
+		})
+		return
+	}
+
+	handlers.WriteJSONResponse(w, http.StatusOK, explanation)
+}
+
 func (h *ScalingHandler) StartActiveSchedule(w http.ResponseWriter, r *http.Request, vars map[string]string) {
 
 	appId := vars["appid"]
@@ -275,3 +309,60 @@ func (h *ScalingHandler) GetActiveSchedule(w http.ResponseWriter, r *http.Reques
 
 	w.Write(body)
 }
+
+func (h *ScalingHandler) PauseApp(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appid"]
+	logger := h.logger.Session("pause-app", lager.Data{"appid": appId})
+
+	pause := &models.AppPause{}
+	err := json.NewDecoder(r.Body).Decode(pause)
+	if err != nil {
+		logger.Error("failed-to-decode", err)
+		handlers.WriteJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "Bad-Request",
+			Message: "Incorrect pause in request body",
+		})
+		return
+	}
+
+	logger.Info("handling", lager.Data{"pause": pause})
+
+	err = h.scalingEngine.PauseApp(appId, pause)
+	if err != nil {
+		logger.Error("failed-to-pause-app", err, lager.Data{"pause": pause})
+		handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "Interal-Server-Error",
+			Message: "Error pausing app",
+		})
+		return
+	}
+
+	handlers.WriteJSONResponse(w, http.StatusOK, pause)
+}
+
+func (h *ScalingHandler) ResumeApp(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	appId := vars["appid"]
+	resumedBy := r.URL.Query().Get("resumed_by")
+	logger := h.logger.Session("resume-app", lager.Data{"appid": appId, "resumedBy": resumedBy})
+	logger.Info("handling")
+
+	err := h.scalingEngine.ResumeApp(appId, resumedBy)
+	if err != nil {
+		logger.Error("failed-to-resume-app", err)
+		switch err.(type) {
+		case *scalingengine.AppNotPausedError:
+			handlers.WriteJSONResponse(w, http.StatusNotFound, models.ErrorResponse{
+				Code:    "Not-Found",
+				Message: "App is not paused",
+			})
+		default:
+			handlers.WriteJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "Interal-Server-Error",
+				Message: "Error resuming app",
+			})
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -22,6 +22,7 @@ import (
 const testUrlScalingHistories = "http://localhost/v1/apps/an-app-id/scaling_histories"
 const testUrlActiveSchedules = "http://localhost/v1/apps/an-app-id/active_schedules/a-schedule-id"
 const testUrlAppActiveSchedule = "http://localhost/v1/apps/an-app-id/active_schedules"
+const testUrlPause = "http://localhost/v1/apps/an-app-id/pause"
 
 var _ = Describe("ScalingHandler", func() {
 	var (
@@ -449,6 +450,105 @@ var _ = Describe("ScalingHandler", func() {
 		})
 	})
 
+	Describe("GetScalingHistoryExplanation", func() {
+		var historyId string
+
+		BeforeEach(func() {
+			historyId = "123"
+			req, err = http.NewRequest(http.MethodGet, testUrlScalingHistories+"/123/explanation", nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			handler.GetScalingHistoryExplanation(resp, req, map[string]string{"appid": "an-app-id", "historyid": historyId})
+		})
+
+		Context("when the explanation exists", func() {
+			var explanation *models.ScalingExplanation
+
+			BeforeEach(func() {
+				explanation = &models.ScalingExplanation{
+					Trigger:           &models.Trigger{AppId: "an-app-id", MetricType: testMetricName, Operator: ">", Threshold: 80, Adjustment: "+1"},
+					Comparisons:       []*models.MetricComparison{{MetricType: testMetricName, Aggregation: models.AggregationAvg, Operator: ">", Threshold: 80, MetricPoints: []*models.AppMetric{}, Breached: true}},
+					Cooldown:          &models.CooldownStatus{InCooldown: false, ExpireAt: 10000},
+					CurrentInstances:  2,
+					ComputedInstances: 3,
+					InstanceMin:       1,
+					InstanceMax:       5,
+					NewInstances:      3,
+				}
+				scalingEngineDB.RetrieveScalingExplanationReturns(explanation, nil)
+			})
+
+			It("returns 200 with the explanation in message body", func() {
+				appId, id := scalingEngineDB.RetrieveScalingExplanationArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(id).To(Equal(int64(123)))
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				actual := &models.ScalingExplanation{}
+				err = json.Unmarshal(resp.Body.Bytes(), actual)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(explanation))
+			})
+		})
+
+		Context("when the history id is invalid", func() {
+			BeforeEach(func() {
+				historyId = "not-a-number"
+			})
+
+			It("returns 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(scalingEngineDB.RetrieveScalingExplanationCallCount()).To(BeZero())
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Bad-Request",
+					Message: "Error parsing scaling history id",
+				}))
+			})
+		})
+
+		Context("when the explanation does not exist", func() {
+			BeforeEach(func() {
+				scalingEngineDB.RetrieveScalingExplanationReturns(nil, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Not-Found",
+					Message: "Scaling explanation not found",
+				}))
+			})
+		})
+
+		Context("when query database fails", func() {
+			BeforeEach(func() {
+				scalingEngineDB.RetrieveScalingExplanationReturns(nil, errors.New("database error"))
+			})
+
+			It("returns 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Interal-Server-Error",
+					Message: "Error getting scaling explanation from database",
+				}))
+			})
+		})
+	})
+
 	Describe("StartActiveSchedule", func() {
 		JustBeforeEach(func() {
 			req, err = http.NewRequest(http.MethodPut, testUrlActiveSchedules, bytes.NewReader(body))
@@ -626,4 +726,123 @@ var _ = Describe("ScalingHandler", func() {
 			})
 		})
 	})
+	Describe("PauseApp", func() {
+		JustBeforeEach(func() {
+			req, err = http.NewRequest(http.MethodPut, testUrlPause, bytes.NewReader(body))
+			Expect(err).ToNot(HaveOccurred())
+
+			handler.PauseApp(resp, req, map[string]string{"appid": "an-app-id"})
+		})
+
+		Context("when the pause is valid", func() {
+			BeforeEach(func() {
+				body = []byte(`{"paused_by":"a-user-id", "reason":"database migration", "expire_at":222}`)
+			})
+
+			It("pauses the app and returns 200 with the pause", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+
+				appId, pause := scalingEngine.PauseAppArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(pause).To(Equal(&models.AppPause{PausedBy: "a-user-id", Reason: "database migration", ExpireAt: 222}))
+
+				actualPause := &models.AppPause{}
+				err = json.Unmarshal(resp.Body.Bytes(), actualPause)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actualPause).To(Equal(pause))
+			})
+		})
+
+		Context("when the pause is invalid", func() {
+			BeforeEach(func() {
+				body = []byte(`{"expire_at":"a"}`)
+			})
+
+			It("returns 400", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Bad-Request",
+					Message: "Incorrect pause in request body",
+				}))
+			})
+		})
+
+		Context("when pausing the app fails", func() {
+			BeforeEach(func() {
+				body = []byte(`{"paused_by":"a-user-id"}`)
+				scalingEngine.PauseAppReturns(errors.New("an error"))
+			})
+
+			It("returns 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Interal-Server-Error",
+					Message: "Error pausing app",
+				}))
+			})
+		})
+	})
+
+	Describe("ResumeApp", func() {
+		JustBeforeEach(func() {
+			req, err = http.NewRequest(http.MethodDelete, testUrlPause+"?resumed_by=a-user-id", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			handler.ResumeApp(resp, req, map[string]string{"appid": "an-app-id"})
+		})
+
+		Context("when resuming the app succeeds", func() {
+			It("returns 204", func() {
+				Expect(resp.Code).To(Equal(http.StatusNoContent))
+
+				appId, resumedBy := scalingEngine.ResumeAppArgsForCall(0)
+				Expect(appId).To(Equal("an-app-id"))
+				Expect(resumedBy).To(Equal("a-user-id"))
+			})
+		})
+
+		Context("when the app is not paused", func() {
+			BeforeEach(func() {
+				scalingEngine.ResumeAppReturns(&scalingengine.AppNotPausedError{})
+			})
+
+			It("returns 404", func() {
+				Expect(resp.Code).To(Equal(http.StatusNotFound))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Not-Found",
+					Message: "App is not paused",
+				}))
+			})
+		})
+
+		Context("when resuming the app fails", func() {
+			BeforeEach(func() {
+				scalingEngine.ResumeAppReturns(errors.New("an error"))
+			})
+
+			It("returns 500", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+
+				errJson := &models.ErrorResponse{}
+				err = json.Unmarshal(resp.Body.Bytes(), errJson)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errJson).To(Equal(&models.ErrorResponse{
+					Code:    "Interal-Server-Error",
+					Message: "Error resuming app",
+				}))
+			})
+		})
+	})
 })
@@ -33,11 +33,15 @@ func NewServer(logger lager.Logger, conf *config.Config, scalingEngineDB db.Scal
 	r.Use(httpStatusCollectMiddleware.Collect)
 	r.Get(routes.ScaleRouteName).Handler(VarsFunc(handler.Scale))
 	r.Get(routes.GetScalingHistoriesRouteName).Handler(VarsFunc(handler.GetScalingHistories))
+	r.Get(routes.GetScalingHistoryExplanationRouteName).Handler(VarsFunc(handler.GetScalingHistoryExplanation))
 
 	r.Get(routes.SetActiveScheduleRouteName).Handler(VarsFunc(handler.StartActiveSchedule))
 	r.Get(routes.DeleteActiveScheduleRouteName).Handler(VarsFunc(handler.RemoveActiveSchedule))
 	r.Get(routes.GetActiveSchedulesRouteName).Handler(VarsFunc(handler.GetActiveSchedule))
 
+	r.Get(routes.PauseAppRouteName).Handler(VarsFunc(handler.PauseApp))
+	r.Get(routes.ResumeAppRouteName).Handler(VarsFunc(handler.ResumeApp))
+
 	r.Get(routes.SyncActiveSchedulesRouteName).Handler(VarsFunc(syncHandler.Sync))
 
 	addr := fmt.Sprintf("0.0.0.0:%d", conf.Server.Port)
@@ -238,6 +238,38 @@ var _ = Describe("Server", func() {
 				})
 			})
 		})
+
+		Context("when pausing an app", func() {
+			BeforeEach(func() {
+				uPath, err := route.Get(routes.PauseAppRouteName).URLPath("appid", "test-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				urlPath = uPath.Path
+				bodyReader = bytes.NewReader([]byte(`{"paused_by":"a-user-id"}`))
+				method = http.MethodPut
+			})
+
+			It("should return 200", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+				rsp.Body.Close()
+			})
+		})
+
+		Context("when resuming an app", func() {
+			BeforeEach(func() {
+				uPath, err := route.Get(routes.ResumeAppRouteName).URLPath("appid", "test-app-id")
+				Expect(err).NotTo(HaveOccurred())
+				urlPath = uPath.Path
+				bodyReader = nil
+				method = http.MethodDelete
+			})
+
+			It("should return 204", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rsp.StatusCode).To(Equal(http.StatusNoContent))
+				rsp.Body.Close()
+			})
+		})
 	})
 
 	Context("when requesting sync shedule", func() {
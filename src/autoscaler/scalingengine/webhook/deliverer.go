@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"autoscaler/db"
+	"autoscaler/models"
+
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	SignatureHeader  = "X-Autoscaler-Signature"
+	DeliveryIdHeader = "X-Autoscaler-Delivery"
+
+	// the response body of a webhook is read up to maxResponseSize so that the connection can be reused
+	maxResponseSize = 64 * 1024
+)
+
+type DelivererConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// deliveries claimed by a scaling engine are not claimed again by others for the lease duration
+	Lease time.Duration
+}
+
+type Deliverer struct {
+	logger          lager.Logger
+	scalingEngineDB db.ScalingEngineDB
+	httpClient      *http.Client
+	clock           clock.Clock
+	conf            DelivererConfig
+}
+
+func NewDeliverer(logger lager.Logger, scalingEngineDB db.ScalingEngineDB, httpClient *http.Client, clock clock.Clock, conf DelivererConfig) *Deliverer {
+	return &Deliverer{
+		logger:          logger.Session("webhook-deliverer"),
+		scalingEngineDB: scalingEngineDB,
+		httpClient:      httpClient,
+		clock:           clock,
+		conf:            conf,
+	}
+}
+
+func (d *Deliverer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+	ticker := d.clock.NewTicker(d.conf.PollInterval)
+
+	d.logger.Info("started", lager.Data{"poll_interval": d.conf.PollInterval})
+
+	for {
+		d.Deliver()
+		select {
+		case <-signals:
+			ticker.Stop()
+			d.logger.Info("stopped")
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// Deliver sends the deliveries which are due, a failed delivery is retried with exponential backoff until
+// MaxAttempts is reached.
+func (d *Deliverer) Deliver() {
+	now := d.clock.Now()
+	deliveries, err := d.scalingEngineDB.ClaimWebhookDeliveries(now.UnixNano(), now.Add(d.conf.Lease).UnixNano(), d.conf.BatchSize)
+	if err != nil {
+		d.logger.Error("failed-to-claim-webhook-deliveries", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		logger := d.logger.WithData(lager.Data{"appId": delivery.AppId, "url": delivery.URL, "id": delivery.Id})
+
+		err = d.send(delivery)
+		if err == nil {
+			logger.Debug("delivered-webhook", lager.Data{"attempts": delivery.Attempts + 1})
+			err = d.scalingEngineDB.DeleteWebhookDelivery(delivery.Id)
+			if err != nil {
+				logger.Error("failed-to-delete-webhook-delivery", err)
+			}
+			continue
+		}
+
+		delivery.Attempts++
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= d.conf.MaxAttempts {
+			logger.Error("failed-to-deliver-webhook-giving-up", err, lager.Data{"attempts": delivery.Attempts, "payload": delivery.Payload})
+			err = d.scalingEngineDB.DeleteWebhookDelivery(delivery.Id)
+			if err != nil {
+				logger.Error("failed-to-delete-webhook-delivery", err)
+			}
+			continue
+		}
+
+		backoff := d.backoff(delivery.Attempts)
+		logger.Info("failed-to-deliver-webhook", lager.Data{"attempts": delivery.Attempts, "error": delivery.LastError, "retry_in": backoff})
+		delivery.NextAttemptAt = d.clock.Now().Add(backoff).UnixNano()
+		err = d.scalingEngineDB.UpdateWebhookDelivery(delivery)
+		if err != nil {
+			logger.Error("failed-to-update-webhook-delivery", err)
+		}
+	}
+}
+
+func (d *Deliverer) send(delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, delivery.Signature)
+	req.Header.Set(DeliveryIdHeader, strconv.FormatInt(delivery.Id, 10))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Deliverer) backoff(attempts int) time.Duration {
+	backoff := d.conf.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= d.conf.MaxBackoff {
+			return d.conf.MaxBackoff
+		}
+	}
+	return backoff
+}
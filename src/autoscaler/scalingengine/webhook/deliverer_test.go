@@ -0,0 +1,209 @@
+package webhook_test
+
+import (
+	"autoscaler/fakes"
+	"autoscaler/helpers"
+	"autoscaler/models"
+	. "autoscaler/scalingengine/webhook"
+
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/cfhttp"
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Deliverer", func() {
+	var (
+		deliverer       *Deliverer
+		scalingEngineDB *fakes.FakeScalingEngineDB
+		clock           *fakeclock.FakeClock
+		buffer          *gbytes.Buffer
+		server          *ghttp.Server
+		delivery        *models.WebhookDelivery
+		conf            DelivererConfig
+	)
+
+	BeforeEach(func() {
+		scalingEngineDB = &fakes.FakeScalingEngineDB{}
+		logger := lagertest.NewTestLogger("deliverer-test")
+		buffer = logger.Buffer()
+		clock = fakeclock.NewFakeClock(time.Now())
+		server = ghttp.NewServer()
+		conf = DelivererConfig{
+			PollInterval:   5 * time.Second,
+			BatchSize:      10,
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     15 * time.Second,
+			Lease:          time.Minute,
+		}
+		deliverer = NewDeliverer(logger, scalingEngineDB, cfhttp.NewClient(), clock, conf)
+
+		delivery = &models.WebhookDelivery{
+			Id:        7,
+			AppId:     "an-app-id",
+			URL:       server.URL() + "/hook",
+			Payload:   `{"app_id":"an-app-id"}`,
+			Signature: "sha256=a-signature",
+		}
+		scalingEngineDB.ClaimWebhookDeliveriesReturns([]*models.WebhookDelivery{delivery}, nil)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Deliver", func() {
+		JustBeforeEach(func() {
+			deliverer.Deliver()
+		})
+
+		Context("when the webhook accepts the delivery", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/hook"),
+					ghttp.VerifyContentType("application/json"),
+					ghttp.VerifyHeaderKV(SignatureHeader, "sha256=a-signature"),
+					ghttp.VerifyHeaderKV(DeliveryIdHeader, "7"),
+					ghttp.VerifyBody([]byte(`{"app_id":"an-app-id"}`)),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				))
+			})
+
+			It("claims the due deliveries for the lease", func() {
+				now, leaseUntil, limit := scalingEngineDB.ClaimWebhookDeliveriesArgsForCall(0)
+				Expect(now).To(Equal(clock.Now().UnixNano()))
+				Expect(leaseUntil).To(Equal(clock.Now().Add(time.Minute).UnixNano()))
+				Expect(limit).To(Equal(10))
+			})
+
+			It("deletes the delivery", func() {
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+				Expect(scalingEngineDB.DeleteWebhookDeliveryCallCount()).To(Equal(1))
+				Expect(scalingEngineDB.DeleteWebhookDeliveryArgsForCall(0)).To(Equal(int64(7)))
+				Expect(scalingEngineDB.UpdateWebhookDeliveryCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the webhook rejects the delivery", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+			})
+
+			It("schedules a retry after the initial backoff", func() {
+				Expect(scalingEngineDB.DeleteWebhookDeliveryCallCount()).To(Equal(0))
+				Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0)).To(Equal(&models.WebhookDelivery{
+					Id:            7,
+					AppId:         "an-app-id",
+					URL:           server.URL() + "/hook",
+					Payload:       `{"app_id":"an-app-id"}`,
+					Signature:     "sha256=a-signature",
+					Attempts:      1,
+					NextAttemptAt: clock.Now().Add(10 * time.Second).UnixNano(),
+					LastError:     "webhook responded with status code 500",
+				}))
+			})
+
+			Context("when it has been retried before", func() {
+				BeforeEach(func() {
+					delivery.Attempts = 1
+				})
+
+				It("doubles the backoff up to the max backoff", func() {
+					Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).Attempts).To(Equal(2))
+					Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).NextAttemptAt).To(Equal(clock.Now().Add(15 * time.Second).UnixNano()))
+				})
+			})
+
+			Context("when it reaches the max attempts", func() {
+				BeforeEach(func() {
+					delivery.Attempts = 2
+				})
+
+				It("gives up and deletes the delivery", func() {
+					Expect(scalingEngineDB.UpdateWebhookDeliveryCallCount()).To(Equal(0))
+					Expect(scalingEngineDB.DeleteWebhookDeliveryArgsForCall(0)).To(Equal(int64(7)))
+					Eventually(buffer).Should(gbytes.Say("failed-to-deliver-webhook-giving-up"))
+				})
+			})
+		})
+
+		Context("when the webhook is not reachable", func() {
+			BeforeEach(func() {
+				server.Close()
+			})
+
+			It("schedules a retry", func() {
+				Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).Attempts).To(Equal(1))
+				Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).LastError).NotTo(BeEmpty())
+			})
+		})
+
+		Context("when the webhook resolves to a loopback address", func() {
+			BeforeEach(func() {
+				guard, err := helpers.NewOutboundGuard(helpers.OutboundConfig{AllowedHosts: []string{"127.0.0.1"}})
+				Expect(err).NotTo(HaveOccurred())
+				deliverer = NewDeliverer(lagertest.NewTestLogger("deliverer-test"), scalingEngineDB, guard.NewHTTPClient(), clock, conf)
+			})
+
+			It("refuses to send the delivery", func() {
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+				Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).LastError).To(ContainSubstring("address 127.0.0.1 is not allowed"))
+			})
+		})
+
+		Context("when the host of the webhook is not allowed", func() {
+			BeforeEach(func() {
+				guard, err := helpers.NewOutboundGuard(helpers.OutboundConfig{AllowedHosts: []string{"hooks.example.com"}, AllowedNetworks: []string{"127.0.0.0/8"}})
+				Expect(err).NotTo(HaveOccurred())
+				deliverer = NewDeliverer(lagertest.NewTestLogger("deliverer-test"), scalingEngineDB, guard.NewHTTPClient(), clock, conf)
+			})
+
+			It("refuses to send the delivery", func() {
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+				Expect(scalingEngineDB.UpdateWebhookDeliveryArgsForCall(0).LastError).To(ContainSubstring("host 127.0.0.1 is not allowed"))
+			})
+		})
+
+		Context("when claiming the deliveries fails", func() {
+			BeforeEach(func() {
+				scalingEngineDB.ClaimWebhookDeliveriesReturns(nil, errors.New("test error"))
+			})
+
+			It("logs the error", func() {
+				Eventually(buffer).Should(gbytes.Say("failed-to-claim-webhook-deliveries"))
+				Expect(scalingEngineDB.DeleteWebhookDeliveryCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		var process ifrit.Process
+
+		BeforeEach(func() {
+			scalingEngineDB.ClaimWebhookDeliveriesReturns(nil, nil)
+			process = ifrit.Invoke(deliverer)
+		})
+
+		AfterEach(func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("delivers at every poll interval", func() {
+			Eventually(scalingEngineDB.ClaimWebhookDeliveriesCallCount).Should(Equal(1))
+			Eventually(clock.WatcherCount).Should(Equal(1))
+			clock.Increment(5 * time.Second)
+			Eventually(scalingEngineDB.ClaimWebhookDeliveriesCallCount).Should(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"autoscaler/db"
+	"autoscaler/models"
+
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+const SignaturePrefix = "sha256="
+
+type Notifier struct {
+	logger          lager.Logger
+	policyDB        db.PolicyDB
+	scalingEngineDB db.ScalingEngineDB
+	clock           clock.Clock
+}
+
+func NewNotifier(logger lager.Logger, policyDB db.PolicyDB, scalingEngineDB db.ScalingEngineDB, clock clock.Clock) *Notifier {
+	return &Notifier{
+		logger:          logger.Session("webhook-notifier"),
+		policyDB:        policyDB,
+		scalingEngineDB: scalingEngineDB,
+		clock:           clock,
+	}
+}
+
+// Deliveries returns a delivery of the scaling history for every webhook of the app policy subscribing to its events.
+// The scaling engine saves them in the outbox together with the history, and the Deliverer sends them, so that a slow
+// or unavailable endpoint never delays scaling. Pauses and resumes are not scaling outcomes and are not notified.
+func (n *Notifier) Deliveries(history *models.AppScalingHistory) []*models.WebhookDelivery {
+	if history.ScalingType == models.ScalingTypePause {
+		return nil
+	}
+	logger := n.logger.WithData(lager.Data{"appId": history.AppId})
+
+	policy, err := n.policyDB.GetAppPolicy(history.AppId)
+	if err != nil {
+		logger.Error("failed-to-get-app-policy", err)
+		return nil
+	}
+	if policy == nil || len(policy.Webhooks) == 0 {
+		return nil
+	}
+
+	events, err := n.getEvents(history, policy)
+	if err != nil {
+		logger.Error("failed-to-get-events", err)
+		return nil
+	}
+
+	payload, err := json.Marshal(&models.ScalingNotification{
+		AppId:          history.AppId,
+		Events:         events,
+		ScalingHistory: history,
+	})
+	if err != nil {
+		logger.Error("failed-to-marshal-notification", err)
+		return nil
+	}
+
+	now := n.clock.Now().UnixNano()
+	deliveries := []*models.WebhookDelivery{}
+	for _, webhook := range policy.Webhooks {
+		if !webhook.Subscribes(events) {
+			continue
+		}
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			AppId:         history.AppId,
+			URL:           webhook.URL,
+			Payload:       string(payload),
+			Signature:     Sign(payload, webhook.Secret),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return deliveries
+}
+
+func (n *Notifier) getEvents(history *models.AppScalingHistory, policy *models.ScalingPolicy) ([]string, error) {
+	var events []string
+	switch history.Status {
+	case models.ScalingStatusSucceeded:
+		events = []string{models.WebhookEventSucceeded}
+	case models.ScalingStatusFailed:
+		return []string{models.WebhookEventFailed}, nil
+	case models.ScalingStatusIgnored:
+		events = []string{models.WebhookEventIgnored}
+	case models.ScalingStatusSimulated:
+		events = []string{models.WebhookEventSimulated}
+	}
+
+	instanceMax := policy.InstanceMax
+	schedule, err := n.scalingEngineDB.GetActiveSchedule(history.AppId)
+	if err != nil {
+		return nil, err
+	}
+	if schedule != nil {
+		instanceMax = schedule.InstanceMax
+	}
+	if history.NewInstances >= instanceMax {
+		events = append(events, models.WebhookEventMaxInstancesReached)
+	}
+	return events, nil
+}
+
+// Sign returns the value of the signature header of a payload, which is the hex encoded HMAC-SHA256 of the payload
+// keyed with the webhook secret.
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,180 @@
+package webhook_test
+
+import (
+	"autoscaler/fakes"
+	"autoscaler/models"
+	. "autoscaler/scalingengine/webhook"
+
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		notifier        *Notifier
+		policyDB        *fakes.FakePolicyDB
+		scalingEngineDB *fakes.FakeScalingEngineDB
+		clock           *fakeclock.FakeClock
+		buffer          *gbytes.Buffer
+		policy          *models.ScalingPolicy
+		history         *models.AppScalingHistory
+		policyErr       error
+		deliveries      []*models.WebhookDelivery
+	)
+
+	BeforeEach(func() {
+		policyDB = &fakes.FakePolicyDB{}
+		scalingEngineDB = &fakes.FakeScalingEngineDB{}
+		logger := lagertest.NewTestLogger("notifier-test")
+		buffer = logger.Buffer()
+		clock = fakeclock.NewFakeClock(time.Now())
+		policyErr = nil
+		notifier = NewNotifier(logger, policyDB, scalingEngineDB, clock)
+
+		policy = &models.ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 5,
+			Webhooks: []*models.Webhook{
+				{URL: "https://all.example.com/hook", Secret: "all-secret"},
+				{URL: "https://oncall.example.com/hook", Secret: "oncall-secret", Events: []string{models.WebhookEventFailed, models.WebhookEventMaxInstancesReached}},
+			},
+		}
+		history = &models.AppScalingHistory{
+			AppId:        "an-app-id",
+			Timestamp:    clock.Now().UnixNano(),
+			ScalingType:  models.ScalingTypeDynamic,
+			Status:       models.ScalingStatusSucceeded,
+			OldInstances: 2,
+			NewInstances: 3,
+			Reason:       "+1 instance(s) because cpu > 80% for 120 seconds",
+		}
+	})
+
+	Describe("Deliveries", func() {
+		JustBeforeEach(func() {
+			policyDB.GetAppPolicyReturns(policy, policyErr)
+			deliveries = notifier.Deliveries(history)
+		})
+
+		Context("when the scaling succeeds", func() {
+			It("returns a signed delivery for the webhooks subscribing to succeeded", func() {
+				Expect(deliveries).To(HaveLen(1))
+				Expect(deliveries[0].AppId).To(Equal("an-app-id"))
+				Expect(deliveries[0].URL).To(Equal("https://all.example.com/hook"))
+				Expect(deliveries[0].NextAttemptAt).To(Equal(clock.Now().UnixNano()))
+				Expect(deliveries[0].CreatedAt).To(Equal(clock.Now().UnixNano()))
+
+				notification := &models.ScalingNotification{}
+				Expect(json.Unmarshal([]byte(deliveries[0].Payload), notification)).To(Succeed())
+				Expect(notification).To(Equal(&models.ScalingNotification{
+					AppId:          "an-app-id",
+					Events:         []string{models.WebhookEventSucceeded},
+					ScalingHistory: history,
+				}))
+
+				mac := hmac.New(sha256.New, []byte("all-secret"))
+				mac.Write([]byte(deliveries[0].Payload))
+				Expect(deliveries[0].Signature).To(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+			})
+		})
+
+		Context("when the scaling fails", func() {
+			BeforeEach(func() {
+				history.Status = models.ScalingStatusFailed
+				history.Error = "failed to set app instances: test error"
+			})
+
+			It("returns a delivery for every webhook subscribing to failed", func() {
+				Expect(deliveries).To(HaveLen(2))
+				Expect(deliveries[1].URL).To(Equal("https://oncall.example.com/hook"))
+				Expect(deliveries[1].Payload).To(ContainSubstring(`"events":["failed"]`))
+				Expect(deliveries[1].Signature).NotTo(Equal(deliveries[0].Signature))
+			})
+		})
+
+		Context("when the scaling reaches the max instances of the policy", func() {
+			BeforeEach(func() {
+				history.NewInstances = 5
+			})
+
+			It("returns a delivery with the max_instances_reached event", func() {
+				Expect(deliveries).To(HaveLen(2))
+				Expect(deliveries[1].Payload).To(ContainSubstring(`"events":["succeeded","max_instances_reached"]`))
+			})
+		})
+
+		Context("when there is an active schedule", func() {
+			BeforeEach(func() {
+				scalingEngineDB.GetActiveScheduleReturns(&models.ActiveSchedule{ScheduleId: "a-schedule-id", InstanceMin: 1, InstanceMax: 3}, nil)
+			})
+
+			It("checks the max instances of the active schedule", func() {
+				Expect(deliveries).To(HaveLen(2))
+				Expect(deliveries[0].Payload).To(ContainSubstring(`"events":["succeeded","max_instances_reached"]`))
+			})
+		})
+
+		Context("when no webhook subscribes to the events", func() {
+			BeforeEach(func() {
+				policy.Webhooks = policy.Webhooks[1:]
+			})
+
+			It("does not return any delivery", func() {
+				Expect(deliveries).To(BeEmpty())
+			})
+		})
+
+		Context("when the policy does not have webhooks", func() {
+			BeforeEach(func() {
+				policy.Webhooks = nil
+			})
+
+			It("does not return any delivery", func() {
+				Expect(scalingEngineDB.GetActiveScheduleCallCount()).To(Equal(0))
+				Expect(deliveries).To(BeEmpty())
+			})
+		})
+
+		Context("when the app does not have policy", func() {
+			BeforeEach(func() {
+				policy = nil
+			})
+
+			It("does not return any delivery", func() {
+				Expect(deliveries).To(BeEmpty())
+			})
+		})
+
+		Context("when getting the policy fails", func() {
+			BeforeEach(func() {
+				policyErr = errors.New("test error")
+			})
+
+			It("logs the error", func() {
+				Expect(deliveries).To(BeEmpty())
+				Eventually(buffer).Should(gbytes.Say("failed-to-get-app-policy"))
+			})
+		})
+
+		Context("when the history is a pause of autoscaling", func() {
+			BeforeEach(func() {
+				history.ScalingType = models.ScalingTypePause
+			})
+
+			It("does not notify it", func() {
+				Expect(policyDB.GetAppPolicyCallCount()).To(Equal(0))
+				Expect(deliveries).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -131,10 +131,11 @@ var _ = Describe("Integration_Api_EventGenerator", func() {
 		Context("Get aggregated metrics", func() {
 			BeforeEach(func() {
 				metric = &models.AppMetric{
-					AppId:      appId,
-					MetricType: models.MetricNameMemoryUsed,
-					Unit:       models.UnitMegaBytes,
-					Value:      "123456",
+					AppId:       appId,
+					MetricType:  models.MetricNameMemoryUsed,
+					Aggregation: models.AggregationAvg,
+					Unit:        models.UnitMegaBytes,
+					Value:       "123456",
 				}
 
 				metric.Timestamp = 666666
@@ -174,18 +175,20 @@ var _ = Describe("Integration_Api_EventGenerator", func() {
 					NextUrl:      getAppAggregatedMetricUrl(appId, metricType, parameters, 2),
 					Resources: []models.AppMetric{
 						models.AppMetric{
-							AppId:      appId,
-							MetricType: models.MetricNameMemoryUsed,
-							Unit:       models.UnitMegaBytes,
-							Value:      "123456",
-							Timestamp:  333333,
+							AppId:       appId,
+							MetricType:  models.MetricNameMemoryUsed,
+							Aggregation: models.AggregationAvg,
+							Unit:        models.UnitMegaBytes,
+							Value:       "123456",
+							Timestamp:   333333,
 						},
 						models.AppMetric{
-							AppId:      appId,
-							MetricType: models.MetricNameMemoryUsed,
-							Unit:       models.UnitMegaBytes,
-							Value:      "123456",
-							Timestamp:  444444,
+							AppId:       appId,
+							MetricType:  models.MetricNameMemoryUsed,
+							Aggregation: models.AggregationAvg,
+							Unit:        models.UnitMegaBytes,
+							Value:       "123456",
+							Timestamp:   444444,
 						},
 					},
 				}
@@ -202,18 +205,20 @@ var _ = Describe("Integration_Api_EventGenerator", func() {
 					NextUrl:      getAppAggregatedMetricUrl(appId, metricType, parameters, 3),
 					Resources: []models.AppMetric{
 						models.AppMetric{
-							AppId:      appId,
-							MetricType: models.MetricNameMemoryUsed,
-							Unit:       models.UnitMegaBytes,
-							Value:      "123456",
-							Timestamp:  555555,
+							AppId:       appId,
+							MetricType:  models.MetricNameMemoryUsed,
+							Aggregation: models.AggregationAvg,
+							Unit:        models.UnitMegaBytes,
+							Value:       "123456",
+							Timestamp:   555555,
 						},
 						models.AppMetric{
-							AppId:      appId,
-							MetricType: models.MetricNameMemoryUsed,
-							Unit:       models.UnitMegaBytes,
-							Value:      "123456",
-							Timestamp:  555555,
+							AppId:       appId,
+							MetricType:  models.MetricNameMemoryUsed,
+							Aggregation: models.AggregationAvg,
+							Unit:        models.UnitMegaBytes,
+							Value:       "123456",
+							Timestamp:   555555,
 						},
 					},
 				}
@@ -229,11 +234,12 @@ var _ = Describe("Integration_Api_EventGenerator", func() {
 					PrevUrl:      getAppAggregatedMetricUrl(appId, metricType, parameters, 2),
 					Resources: []models.AppMetric{
 						models.AppMetric{
-							AppId:      appId,
-							MetricType: models.MetricNameMemoryUsed,
-							Unit:       models.UnitMegaBytes,
-							Value:      "123456",
-							Timestamp:  666666,
+							AppId:       appId,
+							MetricType:  models.MetricNameMemoryUsed,
+							Aggregation: models.AggregationAvg,
+							Unit:        models.UnitMegaBytes,
+							Value:       "123456",
+							Timestamp:   666666,
 						},
 					},
 				}
@@ -201,11 +201,12 @@ var _ = Describe("Integration_Operator_Others", func() {
 			Expect(getAppInstanceMetricTotalCount(testAppId)).To(Equal(1))
 
 			appmetric := &models.AppMetric{
-				AppId:      testAppId,
-				MetricType: models.MetricNameMemoryUsed,
-				Unit:       models.UnitMegaBytes,
-				Value:      "123456",
-				Timestamp:  time.Now().Add(-24 * time.Hour).UnixNano(),
+				AppId:       testAppId,
+				MetricType:  models.MetricNameMemoryUsed,
+				Aggregation: models.AggregationAvg,
+				Unit:        models.UnitMegaBytes,
+				Value:       "123456",
+				Timestamp:   time.Now().Add(-24 * time.Hour).UnixNano(),
 			}
 			insertAppMetric(appmetric)
 			Expect(getAppMetricTotalCount(testAppId)).To(Equal(1))
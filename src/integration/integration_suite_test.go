@@ -650,9 +650,9 @@ func insertAppInstanceMetric(appInstanceMetric *models.AppInstanceMetric) {
 }
 func insertAppMetric(appMetrics *models.AppMetric) {
 	query := "INSERT INTO app_metric" +
-		"(app_id, metric_type, unit, value, timestamp) " +
-		"VALUES($1, $2, $3, $4, $5)"
-	_, err := dbHelper.Exec(query, appMetrics.AppId, appMetrics.MetricType, appMetrics.Unit, appMetrics.Value, appMetrics.Timestamp)
+		"(app_id, metric_type, aggregation, unit, value, timestamp) " +
+		"VALUES($1, $2, $3, $4, $5, $6)"
+	_, err := dbHelper.Exec(query, appMetrics.AppId, appMetrics.MetricType, appMetrics.Aggregation, appMetrics.Unit, appMetrics.Value, appMetrics.Timestamp)
 	Expect(err).NotTo(HaveOccurred())
 }
 